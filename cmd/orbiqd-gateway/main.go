@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/alecthomas/kong"
+	orbiqd_gateway "github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-gateway"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/cli"
+)
+
+func main() {
+	cli.BootstrapDaemon(orbiqd_gateway.Start,
+		cli.WithApplicationName("orbiqd-gateway"),
+		cli.WithKongOptions(
+			kong.Vars{cli.GatewayListenVar: "127.0.0.1:8080"},
+		),
+	)
+}
@@ -1,7 +1,11 @@
 package commands
 
-import "github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-ctl/commands/node"
+import (
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-ctl/commands/edid"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-ctl/commands/node"
+)
 
 type Commands struct {
 	Node node.Commands `cmd:"true" help:"Node-related commands."`
+	Edid edid.Commands `cmd:"true" help:"EDID decoding, encoding and programming commands."`
 }
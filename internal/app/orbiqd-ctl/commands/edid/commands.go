@@ -0,0 +1,10 @@
+package edid
+
+type Commands struct {
+	Decode   Decode   `cmd:"true" help:"Decode EDID file and print its specification."`
+	Encode   Encode   `cmd:"true" help:"Encode EDID specification file into raw EDID blocks."`
+	Diff     Diff     `cmd:"true" help:"Compare two EDID files field by field."`
+	Validate Validate `cmd:"true" help:"Validate EDID file and report missing recommended descriptors."`
+	Get      Get      `cmd:"true" help:"Fetch EDID presented by a display source of a node."`
+	Set      Set      `cmd:"true" help:"Program EDID on a display source of a node."`
+}
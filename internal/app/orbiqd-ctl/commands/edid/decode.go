@@ -0,0 +1,25 @@
+package edid
+
+import (
+	"log/slog"
+)
+
+type Decode struct {
+	File   string `help:"EDID file with raw blocks, or with YAML or JSON specification." arg:"true" type:"existingfile"`
+	Format string `help:"Output format." default:"text" enum:"text,json,yaml" short:"f" long:"format"`
+}
+
+func (command *Decode) Run(logger *slog.Logger) error {
+	specification, _, err := loadFile(command.File)
+	if err := warnCeaExtensionInvalid(err, logger); err != nil {
+		return err
+	}
+
+	if err := printSpecification(specification, command.Format); err != nil {
+		return err
+	}
+
+	logger.Debug("EDID decoded.", slog.String("file", command.File))
+
+	return nil
+}
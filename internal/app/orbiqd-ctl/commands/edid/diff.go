@@ -0,0 +1,150 @@
+package edid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/lensesio/tableprinter"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/edid"
+)
+
+type Diff struct {
+	FileA string `help:"First EDID file with raw blocks, or with YAML or JSON specification." arg:"true" type:"existingfile"`
+	FileB string `help:"Second EDID file with raw blocks, or with YAML or JSON specification." arg:"true" type:"existingfile"`
+}
+
+type differenceOutput struct {
+	Field string `json:"field" header:"Field"`
+	A     string `json:"a" header:"A"`
+	B     string `json:"b" header:"B"`
+}
+
+// Run prints fields which differ between decoded specifications and fails with ErrEdidDiffers when there are any.
+func (command *Diff) Run(logger *slog.Logger) error {
+	specificationA, rawA, err := loadFile(command.FileA)
+	if err := warnCeaExtensionInvalid(err, logger); err != nil {
+		return err
+	}
+
+	specificationB, rawB, err := loadFile(command.FileB)
+	if err := warnCeaExtensionInvalid(err, logger); err != nil {
+		return err
+	}
+
+	differences, err := diffSpecifications(specificationA, specificationB)
+	if err != nil {
+		return err
+	}
+
+	if len(differences) == 0 {
+		if count := countDifferentBytes(rawA, rawB); count > 0 {
+			fmt.Printf("Decoded EDIDs are identical, raw EDIDs differ in %d bytes which are not decoded.\n", count)
+		} else {
+			fmt.Println("EDIDs are identical.")
+		}
+
+		logger.Debug("EDIDs compared.")
+
+		return nil
+	}
+
+	tableprinter.Print(os.Stdout, differences)
+
+	return fmt.Errorf("%w: %d fields", ErrEdidDiffers, len(differences))
+}
+
+// diffSpecifications compares specifications by their JSON fields, field paths follow JSON names.
+func diffSpecifications(specificationA *edid.Specification, specificationB *edid.Specification) ([]differenceOutput, error) {
+	fieldsA, err := flattenSpecification(specificationA)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldsB, err := flattenSpecification(specificationB)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for path := range fieldsA {
+		paths = append(paths, path)
+	}
+	for path := range fieldsB {
+		if _, ok := fieldsA[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	differences := []differenceOutput{}
+
+	for _, path := range paths {
+		valueA, okA := fieldsA[path]
+		valueB, okB := fieldsB[path]
+
+		if okA && okB && valueA == valueB {
+			continue
+		}
+
+		if !okA {
+			valueA = "-"
+		}
+		if !okB {
+			valueB = "-"
+		}
+
+		differences = append(differences, differenceOutput{Field: path, A: valueA, B: valueB})
+	}
+
+	return differences, nil
+}
+
+func flattenSpecification(specification *edid.Specification) (map[string]string, error) {
+	data, err := json.Marshal(specification)
+	if err != nil {
+		return nil, fmt.Errorf("encode edid specification: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("decode edid specification: %w", err)
+	}
+
+	fields := map[string]string{}
+	flattenValue(fields, "", value)
+
+	return fields, nil
+}
+
+func flattenValue(fields map[string]string, path string, value any) {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		for key, nestedValue := range typedValue {
+			nestedPath := key
+			if path != "" {
+				nestedPath = path + "." + key
+			}
+
+			flattenValue(fields, nestedPath, nestedValue)
+		}
+	case []any:
+		for index, nestedValue := range typedValue {
+			flattenValue(fields, path+"["+strconv.Itoa(index)+"]", nestedValue)
+		}
+	case nil:
+	default:
+		fields[path] = fmt.Sprint(typedValue)
+	}
+}
+
+var ErrEdidDiffers = errors.New("edid differs")
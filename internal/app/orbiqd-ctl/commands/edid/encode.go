@@ -0,0 +1,27 @@
+package edid
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+type Encode struct {
+	File       string `help:"EDID specification file in YAML or JSON." arg:"true" type:"existingfile"`
+	OutputFile string `help:"Output file for raw EDID blocks." required:"true" short:"o" long:"output-file"`
+}
+
+func (command *Encode) Run(logger *slog.Logger) error {
+	_, raw, err := loadFile(command.File)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(command.OutputFile, raw, 0o644); err != nil {
+		return fmt.Errorf("write output file: %w", err)
+	}
+
+	logger.Info("EDID encoded.", slog.String("outputFile", command.OutputFile), slog.Int("size", len(raw)))
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package edid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/edid"
+)
+
+// loadFile reads EDID file with raw EDID blocks, or with YAML or JSON specification. It returns decoded specification
+// together with raw EDID blocks, also when the error wraps edid.ErrCeaExtensionInvalid.
+func loadFile(path string) (*edid.Specification, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read file: %w", err)
+	}
+
+	if bytes.HasPrefix(data, []byte(edid.HeaderBlockDefault)) {
+		specification, err := edid.CreateSpecificationFromBytes(data)
+		if errors.Is(err, edid.ErrCeaExtensionInvalid) {
+			return specification, data, fmt.Errorf("decode %s: %w", path, err)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode %s: %w", path, err)
+		}
+
+		return specification, data, nil
+	}
+
+	var specification edid.Specification
+	if err := yaml.UnmarshalStrict(data, &specification); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal %s: %w", path, err)
+	}
+
+	raw, err := edid.CreateBytesFromSpecification(specification)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode %s: %w", path, err)
+	}
+
+	// extension block count is derived from encoded blocks
+	decoded, err := edid.CreateSpecificationFromBytes(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+
+	return decoded, raw, nil
+}
+
+// warnCeaExtensionInvalid logs error of CEA-861 extension block which cannot be decoded, so the base block is still
+// shown. Other errors are returned.
+func warnCeaExtensionInvalid(err error, logger *slog.Logger) error {
+	if errors.Is(err, edid.ErrCeaExtensionInvalid) {
+		logger.Warn("CEA-861 extension block not decoded.", slog.String("error", err.Error()))
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,64 @@
+package edid
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/edid"
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type Get struct {
+	NodeId       string `help:"Identifier of the node to query." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the display source." required:"true" short:"p" long:"peripheral-id"`
+	OutputFile   string `help:"Output file for raw EDID blocks, decoded EDID is printed when not set." short:"o" long:"output-file"`
+	Format       string `help:"Output format of decoded EDID." default:"text" enum:"text,json,yaml" short:"f" long:"format"`
+}
+
+func (command *Get) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", peripheralId.String()),
+	)
+
+	displaySource, err := getDisplaySource(ctx, transport, nodeId, peripheralId)
+	if err != nil {
+		return err
+	}
+
+	edidData, err := displaySource.GetDisplayEdid(ctx)
+	if err != nil {
+		return fmt.Errorf("get display edid: %w", err)
+	}
+
+	if command.OutputFile != "" {
+		err = os.WriteFile(command.OutputFile, edidData, 0o644)
+		if err != nil {
+			return fmt.Errorf("write output file: %w", err)
+		}
+
+		logger.Info("Display EDID saved.", slog.String("outputFile", command.OutputFile), slog.Int("size", len(edidData)))
+
+		return nil
+	}
+
+	specification, err := edid.CreateSpecificationFromBytes(edidData)
+	if err := warnCeaExtensionInvalid(err, logger); err != nil {
+		return fmt.Errorf("decode edid: %w", err)
+	}
+
+	if err := printSpecification(specification, command.Format); err != nil {
+		return err
+	}
+
+	logger.Info("Display EDID fetched.")
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package edid
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/edid"
+)
+
+const (
+	outputFormatText = "text"
+	outputFormatJson = "json"
+	outputFormatYaml = "yaml"
+)
+
+// printSpecification prints the specification as human readable summary, JSON or YAML.
+func printSpecification(specification *edid.Specification, format string) error {
+	switch format {
+	case outputFormatJson:
+		output, err := json.MarshalIndent(specification, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode edid specification: %w", err)
+		}
+
+		fmt.Println(string(output))
+	case outputFormatYaml:
+		output, err := yaml.Marshal(specification)
+		if err != nil {
+			return fmt.Errorf("encode edid specification: %w", err)
+		}
+
+		fmt.Print(string(output))
+	default:
+		printSpecificationSummary(specification)
+	}
+
+	return nil
+}
+
+func printSpecificationSummary(specification *edid.Specification) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer func() {
+		_ = writer.Flush()
+	}()
+
+	vendor := specification.Vendor
+
+	fmt.Fprintf(writer, "Manufacturer:\t%s\n", vendor.Manufacturer)
+	fmt.Fprintf(writer, "Product code:\t%s\n", vendor.ProductCode)
+	fmt.Fprintf(writer, "Serial number:\t%s\n", vendor.SerialNumber)
+	fmt.Fprintf(writer, "Manufactured:\tweek %d, %d\n", vendor.WeekOfManufacture, vendor.YearOfManufacture)
+
+	for _, entry := range specification.Timings.Detailed.Entries {
+		switch {
+		case entry.MonitorName != nil:
+			fmt.Fprintf(writer, "Monitor name:\t%s\n", entry.MonitorName.Name)
+		case entry.MonitorSerial != nil:
+			fmt.Fprintf(writer, "Monitor serial:\t%s\n", entry.MonitorSerial.Name)
+		case entry.RangeLimits != nil:
+			rangeLimits := entry.RangeLimits
+			fmt.Fprintf(writer, "Range limits:\t%d-%d Hz vertical, %d-%d kHz horizontal, %d MHz pixel clock\n",
+				rangeLimits.MinVerticalHz, rangeLimits.MaxVerticalHz,
+				rangeLimits.MinKHz, rangeLimits.MaxKHz,
+				int(rangeLimits.MaxClockMHz)*10,
+			)
+		}
+	}
+
+	fmt.Fprintf(writer, "Input:\t%s\n", describeInput(specification.Display.Input))
+
+	size := specification.Display.Size
+	if size.Width != nil && size.Height != nil {
+		fmt.Fprintf(writer, "Screen size:\t%d x %d cm\n", *size.Width, *size.Height)
+	}
+
+	if specification.Display.Gamma != nil {
+		fmt.Fprintf(writer, "Gamma:\t%.2f\n", *specification.Display.Gamma)
+	}
+
+	displayModes := specification.DisplayModes()
+	if specification.Display.Features.HasPreferredTimingMode && len(displayModes) > 0 {
+		fmt.Fprintf(writer, "Preferred mode:\t%s\n", displayModes[0])
+	}
+
+	fmt.Fprintf(writer, "Display modes:\t%s\n", displayModes)
+	fmt.Fprintf(writer, "Extension blocks:\t%d\n", specification.ExtensionBlockCount)
+
+	if specification.CeaExtension != nil {
+		fmt.Fprintf(writer, "CEA-861:\t%s\n", describeCeaExtension(specification.CeaExtension))
+	}
+}
+
+func describeInput(input edid.DisplayInputSpecification) string {
+	if input.Analog != nil {
+		return "analog"
+	}
+
+	if input.Digital == nil {
+		return "unknown"
+	}
+
+	parts := []string{"digital"}
+
+	if input.Digital.ColorBitDepth != nil {
+		parts = append(parts, fmt.Sprintf("%d bit", *input.Digital.ColorBitDepth))
+	}
+
+	if input.Digital.Interface != nil && *input.Digital.Interface != edid.UndefinedDigitalInterface {
+		parts = append(parts, string(*input.Digital.Interface))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func describeCeaExtension(ceaExtension *edid.CeaExtensionSpecification) string {
+	parts := []string{
+		fmt.Sprintf("%d VICs", len(ceaExtension.VideoDescriptors)),
+		fmt.Sprintf("%d audio formats", len(ceaExtension.AudioDescriptors)),
+		fmt.Sprintf("%d detailed timings", len(ceaExtension.DetailedTimings)),
+	}
+
+	if ceaExtension.HdmiVendorSpecific != nil {
+		parts = append(parts, fmt.Sprintf("HDMI %s", ceaExtension.HdmiVendorSpecific.PhysicalAddress))
+	}
+
+	if ceaExtension.HdrStaticMetadata != nil {
+		parts = append(parts, "HDR")
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,27 @@
+package edid
+
+import (
+	"context"
+	"fmt"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func getDisplaySource(ctx context.Context, transport apiSDK.Transport, nodeId nodeSDK.NodeId, peripheralId peripheralSDK.Id) (*peripheralAPI.DisplaySourceClient, error) {
+	repositoryClient := peripheralAPI.NewRepositoryClient(nodeId, transport)
+
+	peripheral, err := repositoryClient.GetPeripheralById(ctx, peripheralId)
+	if err != nil {
+		return nil, fmt.Errorf("get peripheral: %w", err)
+	}
+
+	peripheralClient, isPeripheralClient := peripheral.(*peripheralAPI.PeripheralClient)
+	if !isPeripheralClient {
+		return nil, fmt.Errorf("peripheral %s is not a peripheral api client", peripheralId)
+	}
+
+	return peripheralAPI.AsDisplaySource(peripheralClient), nil
+}
@@ -0,0 +1,46 @@
+package edid
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type Set struct {
+	NodeId       string `help:"Identifier of the node containing the display source." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the display source." required:"true" short:"p" long:"peripheral-id"`
+	File         string `help:"EDID file with raw blocks, or with YAML or JSON specification." arg:"true" type:"existingfile"`
+}
+
+// Run programs EDID validated locally, the machine connected to the display source sees hot plug when EDID changes.
+func (command *Set) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", peripheralId.String()),
+	)
+
+	_, raw, err := loadFile(command.File)
+	if err != nil {
+		return err
+	}
+
+	displaySource, err := getDisplaySource(ctx, transport, nodeId, peripheralId)
+	if err != nil {
+		return err
+	}
+
+	if err := displaySource.SetDisplayEdid(ctx, raw); err != nil {
+		return fmt.Errorf("set display edid: %w", err)
+	}
+
+	logger.Info("Display EDID programmed.", slog.String("file", command.File), slog.Int("size", len(raw)))
+
+	return nil
+}
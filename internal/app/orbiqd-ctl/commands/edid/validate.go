@@ -0,0 +1,78 @@
+package edid
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/edid"
+)
+
+type Validate struct {
+	File string `help:"EDID file with raw blocks, or with YAML or JSON specification." arg:"true" type:"existingfile"`
+}
+
+// Run fails when EDID cannot be decoded or encoded. Missing descriptors which operating systems rely on and bits the
+// edid package does not keep are reported as warnings.
+func (command *Validate) Run(logger *slog.Logger) error {
+	specification, raw, err := loadFile(command.File)
+	if err := warnCeaExtensionInvalid(err, logger); err != nil {
+		return err
+	}
+
+	for _, warning := range validateSpecification(specification, raw) {
+		fmt.Printf("warning: %s\n", warning)
+	}
+
+	fmt.Printf("EDID is valid: %d blocks, display modes %s\n", len(raw)/len(edid.Block{}), specification.DisplayModes())
+
+	logger.Debug("EDID validated.", slog.String("file", command.File))
+
+	return nil
+}
+
+func validateSpecification(specification *edid.Specification, raw []byte) []string {
+	warnings := []string{}
+
+	var hasMonitorName, hasRangeLimits, hasDetailedTiming bool
+	for _, entry := range specification.Timings.Detailed.Entries {
+		hasMonitorName = hasMonitorName || entry.MonitorName != nil
+		hasRangeLimits = hasRangeLimits || entry.RangeLimits != nil
+		hasDetailedTiming = hasDetailedTiming || entry.Standard != nil
+	}
+
+	if !hasDetailedTiming {
+		warnings = append(warnings, "no detailed timing, machines cannot pick preferred display mode")
+	} else if specification.Timings.Detailed.Entries[0].Standard == nil {
+		warnings = append(warnings, "first descriptor is not a detailed timing, preferred display mode is undefined")
+	}
+
+	if !hasMonitorName {
+		warnings = append(warnings, "monitor name descriptor missing, required by EDID 1.3")
+	}
+
+	if !hasRangeLimits {
+		warnings = append(warnings, "range limits descriptor missing, required by EDID 1.3")
+	}
+
+	encoded, err := edid.CreateBytesFromSpecification(*specification)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("specification cannot be encoded again: %s", err))
+	} else if !bytes.Equal(encoded, raw) {
+		warnings = append(warnings, fmt.Sprintf("encoding differs from file in %d bytes, some fields or extension blocks are not decoded", countDifferentBytes(raw, encoded)))
+	}
+
+	return warnings
+}
+
+func countDifferentBytes(a []byte, b []byte) int {
+	count := max(len(a), len(b)) - min(len(a), len(b))
+
+	for index := range min(len(a), len(b)) {
+		if a[index] != b[index] {
+			count++
+		}
+	}
+
+	return count
+}
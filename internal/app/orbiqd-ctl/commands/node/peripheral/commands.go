@@ -1,12 +1,18 @@
 package peripheral
 
 import (
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-ctl/commands/node/peripheral/display_playback"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-ctl/commands/node/peripheral/display_sink"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-ctl/commands/node/peripheral/display_source"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-ctl/commands/node/peripheral/display_verifier"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-ctl/commands/node/peripheral/machine_power"
 )
 
 type Commands struct {
-	List          List                    `cmd:"true" help:"List peripherals registered on a specific node."`
-	DisplaySource display_source.Commands `cmd:"true" help:"Display source related commands."`
-	DisplaySink   display_sink.Commands   `cmd:"true" help:"Display sink related commands."`
+	List            List                      `cmd:"true" help:"List peripherals registered on a specific node."`
+	DisplaySource   display_source.Commands   `cmd:"true" help:"Display source related commands."`
+	DisplaySink     display_sink.Commands     `cmd:"true" help:"Display sink related commands."`
+	DisplayPlayback display_playback.Commands `cmd:"true" help:"Display playback related commands."`
+	DisplayVerifier display_verifier.Commands `cmd:"true" help:"Display verifier related commands."`
+	MachinePower    machine_power.Commands    `cmd:"true" help:"Machine power related commands."`
 }
@@ -0,0 +1,35 @@
+package display_playback
+
+import (
+	"context"
+	"fmt"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type Commands struct {
+	GetState  GetState  `cmd:"true" help:"Fetch playback state of a display source."`
+	Seek      Seek      `cmd:"true" help:"Move playback of a display source to a position."`
+	SetSpeed  SetSpeed  `cmd:"true" help:"Change playback speed of a display source."`
+	SetLoop   SetLoop   `cmd:"true" help:"Enable or disable looped playback of a display source."`
+	SetPaused SetPaused `cmd:"true" help:"Pause or resume playback of a display source."`
+}
+
+func getDisplayPlayback(ctx context.Context, transport apiSDK.Transport, nodeId nodeSDK.NodeId, peripheralId peripheralSDK.Id) (*peripheralAPI.DisplayPlaybackClient, error) {
+	repositoryClient := peripheralAPI.NewRepositoryClient(nodeId, transport)
+
+	peripheral, err := repositoryClient.GetPeripheralById(ctx, peripheralId)
+	if err != nil {
+		return nil, fmt.Errorf("get display source peripheral: %w", err)
+	}
+
+	peripheralClient, isPeripheralClient := peripheral.(*peripheralAPI.PeripheralClient)
+	if !isPeripheralClient {
+		return nil, fmt.Errorf("peripheral %s is not a peripheral api client", peripheralId)
+	}
+
+	return peripheralAPI.AsDisplayPlayback(peripheralClient), nil
+}
@@ -0,0 +1,71 @@
+package display_playback
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/lensesio/tableprinter"
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type GetState struct {
+	NodeId       string `help:"Identifier of the node to query." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the display source." required:"true" short:"p" long:"peripheral-id"`
+}
+
+func (command *GetState) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	displayPlayback, err := getDisplayPlayback(ctx, transport, nodeId, peripheralId)
+	if err != nil {
+		return err
+	}
+
+	state, err := displayPlayback.GetDisplayPlaybackState(ctx)
+	if err != nil {
+		return fmt.Errorf("get display playback state: %w", err)
+	}
+
+	output := []playbackStateOutput{
+		{
+			NodeId:       nodeId,
+			PeripheralId: peripheralId,
+		},
+	}
+
+	if state != nil {
+		output[0].Position = state.Position.String()
+		output[0].Duration = state.Duration.String()
+		output[0].Frame = fmt.Sprintf("%d/%d", state.FrameIndex+1, state.FrameCount)
+		output[0].Speed = state.Speed
+		output[0].Loop = state.Loop
+		output[0].Paused = state.Paused
+	}
+
+	tableprinter.Print(os.Stdout, output)
+
+	logger.Info("Display playback state fetched.")
+
+	return nil
+}
+
+type playbackStateOutput struct {
+	NodeId       nodeSDK.NodeId   `json:"nodeId" header:"Node ID"`
+	PeripheralId peripheralSDK.Id `json:"peripheralId" header:"Peripheral ID"`
+	Position     string           `json:"position" header:"Position"`
+	Duration     string           `json:"duration" header:"Duration"`
+	Frame        string           `json:"frame" header:"Frame"`
+	Speed        float64          `json:"speed" header:"Speed"`
+	Loop         bool             `json:"loop" header:"Loop"`
+	Paused       bool             `json:"paused" header:"Paused"`
+}
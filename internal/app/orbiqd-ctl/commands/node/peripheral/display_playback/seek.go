@@ -0,0 +1,42 @@
+package display_playback
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type Seek struct {
+	NodeId       string        `help:"Identifier of the node containing the display source." required:"true" short:"n" long:"node-id"`
+	PeripheralId string        `help:"Identifier of the display source peripheral." required:"true" short:"p" long:"peripheral-id"`
+	Position     time.Duration `help:"Playback position relative to the first frame, e.g. 1m30s." required:"true"`
+}
+
+func (command *Seek) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	displayPlayback, err := getDisplayPlayback(ctx, transport, nodeId, peripheralId)
+	if err != nil {
+		return err
+	}
+
+	err = displayPlayback.SeekDisplayPlayback(ctx, command.Position)
+	if err != nil {
+		return fmt.Errorf("seek display playback: %w", err)
+	}
+
+	logger.Info("Display playback position changed.", slog.Duration("position", command.Position))
+
+	return nil
+}
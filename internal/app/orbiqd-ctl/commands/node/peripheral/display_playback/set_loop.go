@@ -0,0 +1,41 @@
+package display_playback
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type SetLoop struct {
+	NodeId       string `help:"Identifier of the node containing the display source." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the display source peripheral." required:"true" short:"p" long:"peripheral-id"`
+	Loop         bool   `help:"Start playback over after the last frame." negatable:""`
+}
+
+func (command *SetLoop) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	displayPlayback, err := getDisplayPlayback(ctx, transport, nodeId, peripheralId)
+	if err != nil {
+		return err
+	}
+
+	err = displayPlayback.SetDisplayPlaybackLoop(ctx, command.Loop)
+	if err != nil {
+		return fmt.Errorf("set display playback loop: %w", err)
+	}
+
+	logger.Info("Display playback loop changed.", slog.Bool("loop", command.Loop))
+
+	return nil
+}
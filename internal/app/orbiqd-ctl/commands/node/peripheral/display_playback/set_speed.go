@@ -0,0 +1,41 @@
+package display_playback
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type SetSpeed struct {
+	NodeId       string  `help:"Identifier of the node containing the display source." required:"true" short:"n" long:"node-id"`
+	PeripheralId string  `help:"Identifier of the display source peripheral." required:"true" short:"p" long:"peripheral-id"`
+	Speed        float64 `help:"Playback rate, where 1 replays frames with original timing." required:"true"`
+}
+
+func (command *SetSpeed) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	displayPlayback, err := getDisplayPlayback(ctx, transport, nodeId, peripheralId)
+	if err != nil {
+		return err
+	}
+
+	err = displayPlayback.SetDisplayPlaybackSpeed(ctx, command.Speed)
+	if err != nil {
+		return fmt.Errorf("set display playback speed: %w", err)
+	}
+
+	logger.Info("Display playback speed changed.", slog.Float64("speed", command.Speed))
+
+	return nil
+}
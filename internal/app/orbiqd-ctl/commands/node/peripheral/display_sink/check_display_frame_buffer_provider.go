@@ -0,0 +1,72 @@
+package display_sink
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type CheckDisplayFrameBufferProvider struct {
+	NodeId               string `help:"Identifier of the node containing the display sink." required:"true" short:"n" long:"node-id"`
+	PeripheralId         string `help:"Identifier of the display sink peripheral." required:"true" short:"p" long:"peripheral-id"`
+	ProviderNodeId       string `help:"Identifier of the node containing the display source provider." required:"true" long:"provider-node-id"`
+	ProviderPeripheralId string `help:"Identifier of the display source provider peripheral." required:"true" long:"provider-peripheral-id"`
+}
+
+func (command *CheckDisplayFrameBufferProvider) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+	providerNodeId := nodeSDK.NodeId(command.ProviderNodeId)
+	providerPeripheralId := peripheralSDK.Id(command.ProviderPeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+		slog.String("providerNodeId", string(providerNodeId)),
+		slog.String("providerPeripheralId", string(providerPeripheralId)),
+	)
+
+	sinkRepositoryClient := peripheralAPI.NewRepositoryClient(nodeId, transport)
+
+	sinkPeripheral, err := sinkRepositoryClient.GetPeripheralById(ctx, peripheralId)
+	if err != nil {
+		return fmt.Errorf("get display sink peripheral: %w", err)
+	}
+
+	sinkPeripheralClient, isSinkPeripheralClient := sinkPeripheral.(*peripheralAPI.PeripheralClient)
+	if !isSinkPeripheralClient {
+		return fmt.Errorf("peripheral %s is not a peripheral api client", peripheralId)
+	}
+
+	displaySink := peripheralAPI.AsDisplaySink(sinkPeripheralClient)
+
+	providerRepositoryClient := peripheralAPI.NewRepositoryClient(providerNodeId, transport)
+
+	providerPeripheral, err := providerRepositoryClient.GetPeripheralById(ctx, providerPeripheralId)
+	if err != nil {
+		return fmt.Errorf("get display source provider peripheral: %w", err)
+	}
+
+	providerPeripheralClient, isProviderPeripheralClient := providerPeripheral.(*peripheralAPI.PeripheralClient)
+	if !isProviderPeripheralClient {
+		return fmt.Errorf("peripheral %s is not a peripheral api client", providerPeripheralId)
+	}
+
+	displaySourceProvider := peripheralAPI.AsDisplaySource(providerPeripheralClient)
+
+	report, err := displaySink.CheckDisplayFrameBufferProvider(ctx, displaySourceProvider)
+	if err != nil {
+		return fmt.Errorf("check display frame buffer provider: %w", err)
+	}
+
+	printCompatibilityReport(report)
+
+	logger.Info("Display frame buffer provider checked.", slog.Bool("compatible", report.Compatible))
+
+	return nil
+}
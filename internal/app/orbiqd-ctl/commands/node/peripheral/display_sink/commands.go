@@ -3,4 +3,11 @@ package display_sink
 type Commands struct {
 	SetDisplayFrameBufferProvider   SetDisplayFrameBufferProvider   `cmd:"true" help:"Set display frame buffer provider for a display sink."`
 	ClearDisplayFrameBufferProvider ClearDisplayFrameBufferProvider `cmd:"true" help:"Clear display frame buffer provider for a display sink."`
+
+	SetFailoverDisplayFrameBufferProvider SetFailoverDisplayFrameBufferProvider `cmd:"true" help:"Set ordered failover display frame buffer providers for a display sink."`
+
+	GetMetrics GetMetrics `cmd:"true" help:"Fetch frame throughput metrics of a display sink."`
+	GetInfo    GetInfo    `cmd:"true" help:"Fetch display sink info with supported display modes and pixel formats."`
+
+	CheckDisplayFrameBufferProvider CheckDisplayFrameBufferProvider `cmd:"true" help:"Check whether a display sink can accept a display frame buffer provider."`
 }
@@ -0,0 +1,57 @@
+package display_sink
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lensesio/tableprinter"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type compatibilityIssueOutput struct {
+	Code    peripheralSDK.DisplaySinkCompatibilityIssueCode `json:"code" header:"Issue"`
+	Message string                                          `json:"message" header:"Details"`
+}
+
+func printCompatibilityReport(report *peripheralSDK.DisplaySinkCompatibilityReport) {
+	displayMode := "unknown"
+	if report.DisplayMode != nil {
+		displayMode = report.DisplayMode.String()
+	}
+
+	fmt.Printf("Compatible: %t\nProvider display mode: %s\nProvider pixel format: %s\n", report.Compatible, displayMode, report.PixelFormat)
+
+	if !report.SinkInfoAvailable {
+		fmt.Println("Display sink does not describe accepted frames, only the provider was checked.")
+	}
+
+	if len(report.Issues) == 0 {
+		return
+	}
+
+	output := make([]compatibilityIssueOutput, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		output = append(output, compatibilityIssueOutput{
+			Code:    issue.Code,
+			Message: issue.Message,
+		})
+	}
+
+	fmt.Println()
+	tableprinter.Print(os.Stdout, output)
+}
+
+// compatibilityReportError joins issues of incompatible report into ErrProviderIncompatible.
+func compatibilityReportError(report *peripheralSDK.DisplaySinkCompatibilityReport) error {
+	messages := make([]string, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		messages = append(messages, issue.Message)
+	}
+
+	return fmt.Errorf("%w: %s", ErrProviderIncompatible, strings.Join(messages, "; "))
+}
+
+var ErrProviderIncompatible = errors.New("display sink cannot accept provider")
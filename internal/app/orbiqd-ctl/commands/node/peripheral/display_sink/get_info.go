@@ -0,0 +1,88 @@
+package display_sink
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/lensesio/tableprinter"
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type GetInfo struct {
+	NodeId       string `help:"Identifier of the node containing the display sink." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the display sink peripheral." required:"true" short:"p" long:"peripheral-id"`
+}
+
+func (command *GetInfo) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	repositoryClient := peripheralAPI.NewRepositoryClient(nodeId, transport)
+
+	peripheral, err := repositoryClient.GetPeripheralById(ctx, peripheralId)
+	if err != nil {
+		return fmt.Errorf("get display sink peripheral: %w", err)
+	}
+
+	peripheralClient, isPeripheralClient := peripheral.(*peripheralAPI.PeripheralClient)
+	if !isPeripheralClient {
+		return fmt.Errorf("peripheral %s is not a peripheral api client", peripheralId)
+	}
+
+	displaySink := peripheralAPI.AsDisplaySink(peripheralClient)
+
+	info, err := displaySink.GetDisplaySinkInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("get display sink info: %w", err)
+	}
+
+	output := infoOutput{
+		NodeId:         nodeId,
+		PeripheralId:   peripheralId,
+		Manufacturer:   info.Manufacturer,
+		Model:          info.Model,
+		SupportedModes: "any",
+		CurrentMode:    "-",
+	}
+
+	if len(info.SupportedModes) > 0 {
+		output.SupportedModes = info.SupportedModes.String()
+	}
+
+	if info.CurrentMode != nil {
+		output.CurrentMode = info.CurrentMode.String()
+	}
+
+	pixelFormats := make([]string, 0, len(info.PixelFormats))
+	for _, pixelFormat := range info.PixelFormats {
+		pixelFormats = append(pixelFormats, pixelFormat.String())
+	}
+	output.PixelFormats = strings.Join(pixelFormats, ", ")
+
+	tableprinter.Print(os.Stdout, []infoOutput{output})
+
+	logger.Info("Display sink info fetched.")
+
+	return nil
+}
+
+type infoOutput struct {
+	NodeId         nodeSDK.NodeId   `json:"nodeId" header:"Node ID"`
+	PeripheralId   peripheralSDK.Id `json:"peripheralId" header:"Peripheral ID"`
+	Manufacturer   string           `json:"manufacturer" header:"Manufacturer"`
+	Model          string           `json:"model" header:"Model"`
+	SupportedModes string           `json:"supportedModes" header:"Supported Modes"`
+	CurrentMode    string           `json:"currentMode" header:"Current Mode"`
+	PixelFormats   string           `json:"pixelFormats" header:"Pixel Formats"`
+}
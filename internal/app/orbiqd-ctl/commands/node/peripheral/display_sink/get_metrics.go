@@ -0,0 +1,113 @@
+package display_sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/lensesio/tableprinter"
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type GetMetrics struct {
+	NodeId       string `help:"Identifier of the node containing the display sink." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the display sink peripheral." required:"true" short:"p" long:"peripheral-id"`
+}
+
+func (command *GetMetrics) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	repositoryClient := peripheralAPI.NewRepositoryClient(nodeId, transport)
+
+	peripheral, err := repositoryClient.GetPeripheralById(ctx, peripheralId)
+	if err != nil {
+		return fmt.Errorf("get display sink peripheral: %w", err)
+	}
+
+	peripheralClient, isPeripheralClient := peripheral.(*peripheralAPI.PeripheralClient)
+	if !isPeripheralClient {
+		return fmt.Errorf("peripheral %s is not a peripheral api client", peripheralId)
+	}
+
+	displaySink := peripheralAPI.AsDisplaySink(peripheralClient)
+
+	metrics, err := displaySink.GetDisplaySinkMetrics(ctx)
+	if err != nil {
+		return fmt.Errorf("get display sink metrics: %w", err)
+	}
+
+	output := []metricsOutput{
+		{
+			NodeId:       nodeId,
+			PeripheralId: peripheralId,
+		},
+	}
+
+	if metrics != nil {
+		output[0].FramesReceived = metrics.FramesReceived
+		output[0].BytesReceived = metrics.BytesReceived
+		output[0].FramesPerSecond = metrics.FramesPerSecond
+		output[0].Errors = metrics.Errors
+	}
+
+	tableprinter.Print(os.Stdout, output)
+
+	if metrics != nil && len(metrics.AdditionalMetrics) > 0 {
+		additionalOutput := []additionalMetricOutput{}
+
+		for _, name := range slices.Sorted(maps.Keys(metrics.AdditionalMetrics)) {
+			additionalOutput = append(additionalOutput, additionalMetricOutput{
+				Metric: name,
+				Value:  formatAdditionalMetric(metrics.AdditionalMetrics[name]),
+			})
+		}
+
+		fmt.Println()
+		tableprinter.Print(os.Stdout, additionalOutput)
+	}
+
+	logger.Info("Display sink metrics fetched.")
+
+	return nil
+}
+
+type metricsOutput struct {
+	NodeId          nodeSDK.NodeId   `json:"nodeId" header:"Node ID"`
+	PeripheralId    peripheralSDK.Id `json:"peripheralId" header:"Peripheral ID"`
+	FramesReceived  uint64           `json:"framesReceived" header:"Frames"`
+	BytesReceived   uint64           `json:"bytesReceived" header:"Bytes"`
+	FramesPerSecond uint64           `json:"framesPerSecond" header:"FPS"`
+	Errors          uint64           `json:"errors" header:"Errors"`
+}
+
+type additionalMetricOutput struct {
+	Metric string `json:"metric" header:"Metric"`
+	Value  string `json:"value" header:"Value"`
+}
+
+// formatAdditionalMetric prints scalar values as they are and structured values, e.g. histograms, as JSON.
+func formatAdditionalMetric(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(encoded)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -12,10 +12,10 @@ import (
 )
 
 type SetDisplayFrameBufferProvider struct {
-	NodeId                string `help:"Identifier of the node containing the display sink." required:"true" short:"n" long:"node-id"`
-	PeripheralId          string `help:"Identifier of the display sink peripheral." required:"true" short:"p" long:"peripheral-id"`
-	ProviderNodeId        string `help:"Identifier of the node containing the display source provider." required:"true" long:"provider-node-id"`
-	ProviderPeripheralId  string `help:"Identifier of the display source provider peripheral." required:"true" long:"provider-peripheral-id"`
+	NodeId               string `help:"Identifier of the node containing the display sink." required:"true" short:"n" long:"node-id"`
+	PeripheralId         string `help:"Identifier of the display sink peripheral." required:"true" short:"p" long:"peripheral-id"`
+	ProviderNodeId       string `help:"Identifier of the node containing the display source provider." required:"true" long:"provider-node-id"`
+	ProviderPeripheralId string `help:"Identifier of the display source provider peripheral." required:"true" long:"provider-peripheral-id"`
 }
 
 func (command *SetDisplayFrameBufferProvider) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
@@ -61,6 +61,15 @@ func (command *SetDisplayFrameBufferProvider) Run(ctx context.Context, transport
 
 	displaySourceProvider := peripheralAPI.AsDisplaySource(providerPeripheralClient)
 
+	// Check the provider first, so the reason of failure is explained instead of a bare error
+	report, err := displaySink.CheckDisplayFrameBufferProvider(ctx, displaySourceProvider)
+	if err != nil {
+		logger.Debug("Failed to check display frame buffer provider.", slog.String("error", err.Error()))
+	} else if !report.Compatible {
+		printCompatibilityReport(report)
+		return compatibilityReportError(report)
+	}
+
 	// Set the frame buffer provider
 	err = displaySink.SetDisplayFrameBufferProvider(displaySourceProvider)
 	if err != nil {
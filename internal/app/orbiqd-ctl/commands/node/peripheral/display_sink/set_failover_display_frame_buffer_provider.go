@@ -0,0 +1,87 @@
+package display_sink
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type SetFailoverDisplayFrameBufferProvider struct {
+	NodeId         string        `help:"Identifier of the node containing the display sink." required:"true" short:"n" long:"node-id"`
+	PeripheralId   string        `help:"Identifier of the display sink peripheral." required:"true" short:"p" long:"peripheral-id"`
+	Providers      []string      `help:"Ordered display source providers in <node-id>/<peripheral-id> format, primary first." required:"true" long:"providers"`
+	FailoverAfter  time.Duration `help:"How long the active source may stay without signal before switching to the next one." long:"failover-after"`
+	FailbackAfter  time.Duration `help:"How long a higher priority source must stay healthy before switching back to it." long:"failback-after"`
+	ProbeInterval  time.Duration `help:"How often inactive sources are probed." long:"probe-interval"`
+	ErrorThreshold int           `help:"How many consecutive errors mark a source as offline." long:"error-threshold"`
+}
+
+func (command *SetFailoverDisplayFrameBufferProvider) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	// Get the display sink peripheral
+	sinkRepositoryClient := peripheralAPI.NewRepositoryClient(nodeId, transport)
+
+	sinkPeripheral, err := sinkRepositoryClient.GetPeripheralById(ctx, peripheralId)
+	if err != nil {
+		return fmt.Errorf("get display sink peripheral: %w", err)
+	}
+
+	sinkPeripheralClient, isSinkPeripheralClient := sinkPeripheral.(*peripheralAPI.PeripheralClient)
+	if !isSinkPeripheralClient {
+		return fmt.Errorf("peripheral %s is not a peripheral api client", peripheralId)
+	}
+
+	displaySink := peripheralAPI.AsDisplaySink(sinkPeripheralClient)
+
+	// Get the display source provider peripherals
+	displaySources := make([]*peripheralAPI.DisplaySourceClient, 0, len(command.Providers))
+	for _, provider := range command.Providers {
+		providerNodeId, providerPeripheralId, found := strings.Cut(provider, "/")
+		if !found || providerNodeId == "" || providerPeripheralId == "" {
+			return fmt.Errorf("invalid provider %q, expected <node-id>/<peripheral-id>", provider)
+		}
+
+		providerRepositoryClient := peripheralAPI.NewRepositoryClient(nodeSDK.NodeId(providerNodeId), transport)
+
+		providerPeripheral, err := providerRepositoryClient.GetPeripheralById(ctx, peripheralSDK.Id(providerPeripheralId))
+		if err != nil {
+			return fmt.Errorf("get display source provider peripheral %s: %w", provider, err)
+		}
+
+		providerPeripheralClient, isProviderPeripheralClient := providerPeripheral.(*peripheralAPI.PeripheralClient)
+		if !isProviderPeripheralClient {
+			return fmt.Errorf("peripheral %s is not a peripheral api client", providerPeripheralId)
+		}
+
+		displaySources = append(displaySources, peripheralAPI.AsDisplaySource(providerPeripheralClient))
+	}
+
+	// Set the failover frame buffer provider
+	err = displaySink.SetFailoverDisplayFrameBufferProvider(ctx, displaySources, peripheralAPI.DisplaySinkFailoverHysteresis{
+		FailoverAfter:  command.FailoverAfter,
+		FailbackAfter:  command.FailbackAfter,
+		ProbeInterval:  command.ProbeInterval,
+		ErrorThreshold: command.ErrorThreshold,
+	})
+	if err != nil {
+		return fmt.Errorf("set failover display frame buffer provider: %w", err)
+	}
+
+	logger.Info("Failover display frame buffer provider set successfully.", slog.Int("providerCount", len(displaySources)))
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package display_source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/edid"
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type GetDisplayEdid struct {
+	NodeId       string `help:"Identifier of the node to query." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the display source." required:"true" short:"p" long:"peripheral-id"`
+	OutputFile   string `help:"Output file for raw EDID blocks, decoded EDID is printed when not set." short:"o" long:"output-file"`
+}
+
+func (command *GetDisplayEdid) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", peripheralId.String()),
+	)
+
+	repositoryClient := peripheralAPI.NewRepositoryClient(nodeId, transport)
+
+	peripheral, err := repositoryClient.GetPeripheralById(ctx, peripheralId)
+	if err != nil {
+		return fmt.Errorf("get peripheral: %w", err)
+	}
+
+	peripheralClient, isPeripheralClient := peripheral.(*peripheralAPI.PeripheralClient)
+	if !isPeripheralClient {
+		return fmt.Errorf("peripheral %s is not a peripheral api client", peripheralId)
+	}
+
+	displaySource := peripheralAPI.AsDisplaySource(peripheralClient)
+
+	edidData, err := displaySource.GetDisplayEdid(ctx)
+	if err != nil {
+		return fmt.Errorf("get display edid: %w", err)
+	}
+
+	if command.OutputFile != "" {
+		err = os.WriteFile(command.OutputFile, edidData, 0o644)
+		if err != nil {
+			return fmt.Errorf("write output file: %w", err)
+		}
+
+		logger.Info("Display EDID saved.", slog.String("outputFile", command.OutputFile), slog.Int("size", len(edidData)))
+
+		return nil
+	}
+
+	specification, err := edid.CreateSpecificationFromBytes(edidData)
+	if errors.Is(err, edid.ErrCeaExtensionInvalid) {
+		logger.Warn("CEA-861 extension block not decoded.", slog.String("error", err.Error()))
+	} else if err != nil {
+		return fmt.Errorf("decode edid: %w", err)
+	}
+
+	output, err := yaml.Marshal(specification)
+	if err != nil {
+		return fmt.Errorf("encode edid specification: %w", err)
+	}
+
+	fmt.Print(string(output))
+
+	logger.Info("Display EDID fetched.")
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package display_verifier
+
+import (
+	"context"
+	"fmt"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type Commands struct {
+	GetReport GetReport `cmd:"true" help:"Fetch verification report of a display sink."`
+	Reset     Reset     `cmd:"true" help:"Clear verification report of a display sink."`
+}
+
+func getDisplayVerifier(ctx context.Context, transport apiSDK.Transport, nodeId nodeSDK.NodeId, peripheralId peripheralSDK.Id) (*peripheralAPI.DisplayVerifierClient, error) {
+	repositoryClient := peripheralAPI.NewRepositoryClient(nodeId, transport)
+
+	peripheral, err := repositoryClient.GetPeripheralById(ctx, peripheralId)
+	if err != nil {
+		return nil, fmt.Errorf("get display sink peripheral: %w", err)
+	}
+
+	peripheralClient, isPeripheralClient := peripheral.(*peripheralAPI.PeripheralClient)
+	if !isPeripheralClient {
+		return nil, fmt.Errorf("peripheral %s is not a peripheral api client", peripheralId)
+	}
+
+	return peripheralAPI.AsDisplayVerifier(peripheralClient), nil
+}
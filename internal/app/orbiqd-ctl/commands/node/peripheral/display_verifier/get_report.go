@@ -0,0 +1,79 @@
+package display_verifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/lensesio/tableprinter"
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type GetReport struct {
+	NodeId       string `help:"Identifier of the node to query." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the verifier display sink." required:"true" short:"p" long:"peripheral-id"`
+}
+
+func (command *GetReport) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	displayVerifier, err := getDisplayVerifier(ctx, transport, nodeId, peripheralId)
+	if err != nil {
+		return err
+	}
+
+	report, err := displayVerifier.GetDisplayVerificationReport(ctx)
+	if err != nil {
+		return fmt.Errorf("get display verification report: %w", err)
+	}
+
+	output := []reportOutput{
+		{
+			NodeId:       nodeId,
+			PeripheralId: peripheralId,
+		},
+	}
+
+	if report != nil {
+		output[0].Verified = report.FramesVerified
+		output[0].Frames = fmt.Sprintf("%d-%d", report.FirstFrameNumber, report.LastFrameNumber)
+		output[0].Dropped = report.DroppedFrames
+		output[0].Duplicate = report.DuplicateFrames
+		output[0].OutOfOrder = report.OutOfOrderFrames
+		output[0].MissingMarker = report.MissingMarkerFrames
+		output[0].ChecksumMismatch = report.ChecksumMismatchFrames
+		output[0].PixelMismatch = report.PixelMismatchFrames
+		output[0].ModeMismatch = report.DisplayModeMismatchFrames
+		output[0].Passed = report.Passed()
+	}
+
+	tableprinter.Print(os.Stdout, output)
+
+	logger.Info("Display verification report fetched.")
+
+	return nil
+}
+
+type reportOutput struct {
+	NodeId           nodeSDK.NodeId   `json:"nodeId" header:"Node ID"`
+	PeripheralId     peripheralSDK.Id `json:"peripheralId" header:"Peripheral ID"`
+	Verified         uint64           `json:"verified" header:"Verified"`
+	Frames           string           `json:"frames" header:"Frames"`
+	Dropped          uint64           `json:"dropped" header:"Dropped"`
+	Duplicate        uint64           `json:"duplicate" header:"Duplicate"`
+	OutOfOrder       uint64           `json:"outOfOrder" header:"Out Of Order"`
+	MissingMarker    uint64           `json:"missingMarker" header:"No Marker"`
+	ChecksumMismatch uint64           `json:"checksumMismatch" header:"Checksum"`
+	PixelMismatch    uint64           `json:"pixelMismatch" header:"Pixels"`
+	ModeMismatch     uint64           `json:"modeMismatch" header:"Mode"`
+	Passed           bool             `json:"passed" header:"Passed"`
+}
@@ -0,0 +1,39 @@
+package display_verifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type Reset struct {
+	NodeId       string `help:"Identifier of the node to query." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the verifier display sink." required:"true" short:"p" long:"peripheral-id"`
+}
+
+func (command *Reset) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	displayVerifier, err := getDisplayVerifier(ctx, transport, nodeId, peripheralId)
+	if err != nil {
+		return err
+	}
+
+	if err := displayVerifier.ResetDisplayVerification(ctx); err != nil {
+		return fmt.Errorf("reset display verification: %w", err)
+	}
+
+	logger.Info("Display verification reset.")
+
+	return nil
+}
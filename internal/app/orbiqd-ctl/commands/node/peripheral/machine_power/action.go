@@ -0,0 +1,41 @@
+package machine_power
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type Action struct {
+	NodeId       string `help:"Identifier of the node containing the machine." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the machine peripheral." required:"true" short:"p" long:"peripheral-id"`
+	Action       string `help:"Power action to apply." required:"true" enum:"power-on,shutdown,reset,pause,resume"`
+}
+
+func (command *Action) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	machinePower, err := getMachinePower(ctx, transport, nodeId, peripheralId)
+	if err != nil {
+		return err
+	}
+
+	err = machinePower.HandleMachinePowerAction(ctx, peripheralSDK.MachinePowerAction(command.Action))
+	if err != nil {
+		return fmt.Errorf("handle machine power action: %w", err)
+	}
+
+	logger.Info("Machine power action applied.", slog.String("action", command.Action))
+
+	return nil
+}
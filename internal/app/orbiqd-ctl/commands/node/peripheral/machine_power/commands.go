@@ -0,0 +1,32 @@
+package machine_power
+
+import (
+	"context"
+	"fmt"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type Commands struct {
+	GetState GetState `cmd:"true" help:"Fetch power state of a machine."`
+	Action   Action   `cmd:"true" help:"Apply power action to a machine."`
+}
+
+func getMachinePower(ctx context.Context, transport apiSDK.Transport, nodeId nodeSDK.NodeId, peripheralId peripheralSDK.Id) (*peripheralAPI.MachinePowerClient, error) {
+	repositoryClient := peripheralAPI.NewRepositoryClient(nodeId, transport)
+
+	peripheral, err := repositoryClient.GetPeripheralById(ctx, peripheralId)
+	if err != nil {
+		return nil, fmt.Errorf("get machine peripheral: %w", err)
+	}
+
+	peripheralClient, isPeripheralClient := peripheral.(*peripheralAPI.PeripheralClient)
+	if !isPeripheralClient {
+		return nil, fmt.Errorf("peripheral %s is not a peripheral api client", peripheralId)
+	}
+
+	return peripheralAPI.AsMachinePower(peripheralClient), nil
+}
@@ -0,0 +1,70 @@
+package machine_power
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/lensesio/tableprinter"
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type GetState struct {
+	NodeId       string `help:"Identifier of the node to query." required:"true" short:"n" long:"node-id"`
+	PeripheralId string `help:"Identifier of the machine peripheral." required:"true" short:"p" long:"peripheral-id"`
+}
+
+func (command *GetState) Run(ctx context.Context, transport apiSDK.Transport, logger *slog.Logger) error {
+	nodeId := nodeSDK.NodeId(command.NodeId)
+	peripheralId := peripheralSDK.Id(command.PeripheralId)
+
+	logger = logger.With(
+		slog.String("nodeId", string(nodeId)),
+		slog.String("peripheralId", string(peripheralId)),
+	)
+
+	machinePower, err := getMachinePower(ctx, transport, nodeId, peripheralId)
+	if err != nil {
+		return err
+	}
+
+	state, err := machinePower.GetMachinePowerState(ctx)
+	if err != nil {
+		return fmt.Errorf("get machine power state: %w", err)
+	}
+
+	output := []powerStateOutput{
+		{
+			NodeId:       nodeId,
+			PeripheralId: peripheralId,
+		},
+	}
+
+	if state != nil {
+		output[0].Status = string(state.Status)
+		output[0].Detail = state.Detail
+		output[0].LastEvent = state.LastEvent
+		if !state.LastEventAt.IsZero() {
+			output[0].LastEventAt = state.LastEventAt.Format(time.RFC3339)
+		}
+	}
+
+	tableprinter.Print(os.Stdout, output)
+
+	logger.Info("Machine power state fetched.")
+
+	return nil
+}
+
+type powerStateOutput struct {
+	NodeId       nodeSDK.NodeId   `json:"nodeId" header:"Node ID"`
+	PeripheralId peripheralSDK.Id `json:"peripheralId" header:"Peripheral ID"`
+	Status       string           `json:"status" header:"Status"`
+	Detail       string           `json:"detail" header:"Detail"`
+	LastEvent    string           `json:"lastEvent" header:"Last Event"`
+	LastEventAt  string           `json:"lastEventAt" header:"Last Event At"`
+}
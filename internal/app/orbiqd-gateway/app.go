@@ -0,0 +1,83 @@
+package orbiqd_gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/api/gateway"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/api/transport/loopback"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/api/transport/p2p"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/cli"
+	nodeInternal "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/node"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+)
+
+// Start runs HTTP API gateway as standalone node, which reaches peripherals of all nodes discovered by transport.
+func Start(ctx context.Context, wg *sync.WaitGroup, config Config) error {
+	logger := slog.Default()
+
+	if config.Gateway.Listen == "" {
+		return ErrGatewayListenMissing
+	}
+
+	if err := cli.SetupMemoryPool(); err != nil {
+		return fmt.Errorf("setup memory pool: %w", err)
+	}
+
+	var tokens []string
+	if config.Gateway.TokenFile != "" {
+		var err error
+		tokens, err = gateway.ReadTokenFile(config.Gateway.TokenFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := gateway.ValidateListenAddress(config.Gateway.Listen, tokens); err != nil {
+		return err
+	}
+
+	nodeRepository := nodeInternal.NewNodeRepository()
+	nodeRegistrar := nodeInternal.NewNodeRegistrar(nodeRepository)
+
+	transport, nodeService, err := cli.SetupTransport(ctx, wg, config.Transport, nodeSDK.Gateway,
+		p2p.WithTransportNodeRegistrar(nodeRegistrar),
+	)
+	if err != nil {
+		return fmt.Errorf("setup transport: %w", err)
+	}
+
+	gatewayTransport := loopback.NewTransport(transport,
+		loopback.WithTransportServices(nodeService),
+		loopback.WithTransportLogger(logger),
+	)
+
+	listener, err := net.Listen("tcp", config.Gateway.Listen)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	httpGateway := gateway.NewGateway(gatewayTransport, nodeRepository,
+		gateway.WithGatewayTokens(tokens...),
+		gateway.WithGatewayLogger(logger.With(slog.String("component", "gateway"))),
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		if err := httpGateway.Serve(ctx, listener); err != nil {
+			logger.Error("Gateway failed.", slog.String("error", err.Error()))
+		}
+
+		logger.Debug("Gateway terminated.")
+	}()
+
+	return nil
+}
+
+var ErrGatewayListenMissing = errors.New("gateway listen address missing")
@@ -0,0 +1,11 @@
+package orbiqd_gateway
+
+import (
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/cli"
+)
+
+type Config struct {
+	cli.LogConfigHelper
+	cli.TransportConfigHelper
+	cli.GatewayConfigHelper
+}
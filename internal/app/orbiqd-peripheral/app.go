@@ -2,18 +2,22 @@ package orbiqd_peripheral
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"sync"
-	"time"
 
+	"github.com/go-playground/validator/v10"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/api/gateway"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/api/transport/loopback"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/api/transport/p2p"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/cli"
-	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
 	nodeInternal "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/node"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/peripheral"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/rfb"
 	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
-	nodeAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node"
 	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
 	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
 	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
@@ -23,7 +27,7 @@ import (
 func Start(ctx context.Context, wg *sync.WaitGroup, config Config) error {
 	logger := slog.Default()
 
-	if err := setupMemoryPool(); err != nil {
+	if err := cli.SetupMemoryPool(); err != nil {
 		return fmt.Errorf("setup memory pool: %w", err)
 	}
 
@@ -44,12 +48,18 @@ func Start(ctx context.Context, wg *sync.WaitGroup, config Config) error {
 	nodeRepository := nodeInternal.NewNodeRepository()
 	nodeRegistrar := nodeInternal.NewNodeRegistrar(nodeRepository)
 
-	peripheralServices, err := setupPeripherals(ctx, wg, driverRepository, config.Peripheral)
+	peripheralServices, peripheralRepository, err := setupPeripherals(ctx, wg, driverRepository, config.Peripheral)
 	if err != nil {
 		return fmt.Errorf("setup peripherals: %w", err)
 	}
 
-	transport, err := setupTransport(ctx, wg, config.Transport,
+	for _, vncConfig := range config.VNC {
+		if err := setupVNC(ctx, wg, vncConfig, peripheralRepository); err != nil {
+			return fmt.Errorf("setup vnc %s: %w", vncConfig.Name, err)
+		}
+	}
+
+	transport, nodeService, err := cli.SetupTransport(ctx, wg, config.Transport, nodeSDK.Peripheral,
 		p2p.WithTransportServices(peripheralServices...),
 		p2p.WithTransportNodeRegistrar(nodeRegistrar),
 	)
@@ -57,85 +67,159 @@ func Start(ctx context.Context, wg *sync.WaitGroup, config Config) error {
 		return fmt.Errorf("setup transport: %w", err)
 	}
 
-	transport.GetLocalNodeId()
+	if config.Gateway.Listen != "" {
+		gatewayTransport := loopback.NewTransport(transport,
+			loopback.WithTransportServices(peripheralServices...),
+			loopback.WithTransportServices(nodeService),
+			loopback.WithTransportLogger(logger),
+		)
+
+		if err := setupGateway(ctx, wg, config.Gateway, gatewayTransport, nodeRepository); err != nil {
+			return fmt.Errorf("setup gateway: %w", err)
+		}
+	}
 
 	return nil
 }
 
-func setupTransport(ctx context.Context, wg *sync.WaitGroup, config cli.TransportConfig, opts ...p2p.TransportOpt) (apiSDK.Transport, error) {
-	logger := slog.Default()
+func setupGateway(ctx context.Context, wg *sync.WaitGroup, config cli.GatewayConfig, transport apiSDK.Transport, nodeRepository nodeSDK.NodeRepository) error {
+	logger := slog.Default().With(slog.String("component", "gateway"))
 
-	identity, err := p2p.NewIdentity(config.IdentityPath)
-	if err != nil {
-		return nil, fmt.Errorf("create identity: %w", err)
+	var tokens []string
+	if config.TokenFile != "" {
+		var err error
+		tokens, err = gateway.ReadTokenFile(config.TokenFile)
+		if err != nil {
+			return err
+		}
 	}
 
-	node := nodeInternal.NewNode(identity.GetId(), nodeInternal.WithNodeRole(nodeSDK.Peripheral))
-	nodeService := nodeAPI.NewNodeAdapter(node)
-
-	opts = append(opts,
-		p2p.WithTransportServices(nodeService),
-		p2p.WithTransportIdentity(identity),
-		p2p.WithTransportLogger(logger),
-		p2p.WithTransportBindAddress(config.BindAddress),
-	)
+	if err := gateway.ValidateListenAddress(config.Listen, tokens); err != nil {
+		return err
+	}
 
-	transport, err := p2p.NewTransport(opts...)
+	listener, err := net.Listen("tcp", config.Listen)
 	if err != nil {
-		return nil, fmt.Errorf("create transport: %w", err)
+		return fmt.Errorf("listen: %w", err)
 	}
 
-	discoverer, err := p2p.NewDiscoverer(transport,
-		p2p.WithMulticastDNSDiscovery(),
-		p2p.WithDiscovererLogger(logger),
+	httpGateway := gateway.NewGateway(transport, nodeRepository,
+		gateway.WithGatewayTokens(tokens...),
+		gateway.WithGatewayLogger(logger),
 	)
-	if err != nil {
-		return nil, fmt.Errorf("create discoverer: %w", err)
-	}
 
 	wg.Add(1)
 	go func() {
-		<-ctx.Done()
+		defer wg.Done()
 
-		if err := discoverer.Terminate(ctx); err != nil {
-			logger.Warn("Discoverer termination failed.", slog.String("error", err.Error()))
+		if err := httpGateway.Serve(ctx, listener); err != nil {
+			logger.Error("Gateway failed.", slog.String("error", err.Error()))
 		}
 
-		if err := transport.Terminate(ctx); err != nil {
-			logger.Warn("Transport termination failed.", slog.String("error", err.Error()))
+		logger.Debug("Gateway terminated.")
+	}()
+
+	return nil
+}
+
+func setupVNC(ctx context.Context, wg *sync.WaitGroup, config VNCConfig, peripheralRepository peripheralSDK.Repository) error {
+	logger := slog.Default().With(slog.String("component", "vnc"), slog.String("vncName", config.Name))
+
+	if err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
+
+	password := config.Password
+	if config.PasswordFile != "" {
+		var err error
+		password, err = rfb.ReadPasswordFile(config.PasswordFile)
+		if err != nil {
+			return err
 		}
+	}
 
-		wg.Done()
-		logger.Debug("Transport terminated.")
-	}()
+	if err := rfb.ValidateListenAddress(config.Listen, password); err != nil {
+		return err
+	}
 
-	logger.Debug("Waiting for discovery bootstrap finished.")
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-time.After(config.DiscoveryBootstrapWindow):
+	displaySource, err := getPeripheralByName[peripheralSDK.DisplaySource](ctx, peripheralRepository, config.DisplaySource)
+	if err != nil {
+		return fmt.Errorf("get display source: %w", err)
 	}
 
-	logger.Info("Transport ready.", slog.String("localNodeId", string(transport.GetLocalNodeId())))
+	desktopName := config.DesktopName
+	if desktopName == "" {
+		desktopName = config.Name
+	}
 
-	return transport, nil
-}
+	serverOpts := []rfb.ServerOpt{
+		rfb.WithServerPassword(password),
+		rfb.WithServerDesktopName(desktopName),
+		rfb.WithServerLogger(logger),
+	}
+
+	if config.MaxFrameRate != nil {
+		serverOpts = append(serverOpts, rfb.WithServerMaxFrameRate(*config.MaxFrameRate))
+	}
+
+	if config.KeyboardSink != "" {
+		keyboardSink, err := getPeripheralByName[peripheralSDK.KeyboardSink](ctx, peripheralRepository, config.KeyboardSink)
+		if err != nil {
+			return fmt.Errorf("get keyboard sink: %w", err)
+		}
+		serverOpts = append(serverOpts, rfb.WithServerKeyboardSink(keyboardSink))
+	}
+
+	if config.MouseSink != "" {
+		mouseSink, err := getPeripheralByName[peripheralSDK.MouseEventHandler](ctx, peripheralRepository, config.MouseSink)
+		if err != nil {
+			return fmt.Errorf("get mouse sink: %w", err)
+		}
+		serverOpts = append(serverOpts, rfb.WithServerMouseSink(mouseSink))
+	}
 
-func setupMemoryPool() error {
-	memoryPool, err := memory.NewHeapPool(1024*1024*16, 32)
+	server, err := rfb.NewServer(displaySource, serverOpts...)
 	if err != nil {
-		return fmt.Errorf("create heap pool: %w", err)
+		return fmt.Errorf("create server: %w", err)
 	}
 
-	err = memory.SetDefaultMemoryPool(memoryPool)
+	listener, err := net.Listen("tcp", config.Listen)
 	if err != nil {
-		return fmt.Errorf("set as default: %w", err)
+		return fmt.Errorf("listen: %w", err)
 	}
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		if err := server.Serve(ctx, listener); err != nil {
+			logger.Error("VNC server failed.", slog.String("error", err.Error()))
+		}
+
+		logger.Debug("VNC server terminated.")
+	}()
+
 	return nil
 }
 
-func setupPeripherals(ctx context.Context, wg *sync.WaitGroup, driverRepository driverSDK.DriverRepository, peripheralConfigList []PeripheralConfig) ([]nodeSDK.Service, error) {
+// getPeripheralByName returns peripheral of given name implementing T.
+func getPeripheralByName[T peripheralSDK.Peripheral](ctx context.Context, peripheralRepository peripheralSDK.Repository, name peripheralSDK.Name) (T, error) {
+	var empty T
+
+	peripheralInstance, err := peripheralRepository.GetPeripheralByName(ctx, name)
+	if err != nil {
+		return empty, fmt.Errorf("%s: %w", name, err)
+	}
+
+	typed, isTyped := peripheralInstance.(T)
+	if !isTyped {
+		return empty, fmt.Errorf("%w: %s", ErrPeripheralRoleMismatch, name)
+	}
+
+	return typed, nil
+}
+
+func setupPeripherals(ctx context.Context, wg *sync.WaitGroup, driverRepository driverSDK.DriverRepository, peripheralConfigList []PeripheralConfig) ([]nodeSDK.Service, peripheralSDK.Repository, error) {
 	var services []nodeSDK.Service
 	var repositoryOpts []peripheral.RepositoryOpt
 
@@ -147,12 +231,12 @@ func setupPeripherals(ctx context.Context, wg *sync.WaitGroup, driverRepository
 
 		driver, err := driverRepository.GetByKind(ctx, peripheralConfig.DriverKind)
 		if err != nil {
-			return nil, fmt.Errorf("get driver by kind: %s: %w", peripheralConfig.DriverKind, err)
+			return nil, nil, fmt.Errorf("get driver by kind: %s: %w", peripheralConfig.DriverKind, err)
 		}
 
 		peripheralInstance, err := driver.CreatePeripheral(ctx, peripheralConfig.Config, peripheralConfig.Name)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		services = append(services, peripheralAPI.NewPeripheralAdapter(peripheralInstance,
 			peripheralAPI.WithPeripheralAdapterLogger(logger),
@@ -170,6 +254,37 @@ func setupPeripherals(ctx context.Context, wg *sync.WaitGroup, driverRepository
 			))
 		}
 
+		if playbackController, isPlaybackController := peripheralInstance.(peripheralSDK.DisplayPlaybackController); isPlaybackController {
+			services = append(services, peripheralAPI.NewDisplayPlaybackAdapter(playbackController,
+				peripheralAPI.WithDisplayPlaybackAdapterLogger(logger),
+			))
+		}
+
+		if displayVerifier, isDisplayVerifier := peripheralInstance.(peripheralSDK.DisplayVerifier); isDisplayVerifier {
+			services = append(services, peripheralAPI.NewDisplayVerifierAdapter(displayVerifier,
+				peripheralAPI.WithDisplayVerifierAdapterLogger(logger),
+			))
+		}
+
+		if powerController, isPowerController := peripheralInstance.(peripheralSDK.MachinePowerController); isPowerController {
+			services = append(services, peripheralAPI.NewMachinePowerAdapter(powerController,
+				peripheralAPI.WithMachinePowerAdapterLogger(logger),
+			))
+		}
+
+		if keyboardSink, isKeyboardSink := peripheralInstance.(peripheralSDK.KeyboardSink); isKeyboardSink {
+			services = append(services, peripheralAPI.NewKeyboardSinkAdapter(keyboardSink,
+				peripheralAPI.WithKeyboardSinkAdapterLogger(logger),
+			))
+		}
+
+		// mouse sink has no methods of its own, so only sinks handling mouse events are served
+		if mouseSink, isMouseSink := peripheralInstance.(peripheralSDK.MouseEventHandler); isMouseSink {
+			services = append(services, peripheralAPI.NewMouseSinkAdapter(mouseSink,
+				peripheralAPI.WithMouseSinkAdapterLogger(logger),
+			))
+		}
+
 		repositoryOpts = append(repositoryOpts, peripheral.WithPeripheral(peripheralInstance))
 
 		wg.Add(1)
@@ -191,11 +306,13 @@ func setupPeripherals(ctx context.Context, wg *sync.WaitGroup, driverRepository
 
 	peripheralRepository, err := peripheral.NewRepository(repositoryOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("create peripheral repository: %w", err)
+		return nil, nil, fmt.Errorf("create peripheral repository: %w", err)
 	}
 	services = append(services, peripheralAPI.NewRepositoryAdapter(peripheralRepository,
 		peripheralAPI.WithRepositoryAdapterLogger(logger),
 	))
 
-	return services, nil
+	return services, peripheralRepository, nil
 }
+
+var ErrPeripheralRoleMismatch = errors.New("peripheral does not have required role")
@@ -28,13 +28,53 @@ func (peripheralConfig *PeripheralConfig) Decode(ctx *kong.DecodeContext) error
 		return fmt.Errorf("read peripheral config flag: %w", err)
 	}
 
+	var loadedConfig PeripheralConfig
+	if err := loadConfigFile(rawConfigLocation, &loadedConfig); err != nil {
+		return fmt.Errorf("load peripheral config: %w", err)
+	}
+
+	*peripheralConfig = loadedConfig
+	return nil
+}
+
+// VNCConfig describes RFB server channel exposing display source of this node, with optional keyboard and mouse
+// sinks receiving input of clients. Peripherals are referenced by name.
+type VNCConfig struct {
+	Name          string             `json:"name" validate:"required"`
+	Listen        string             `json:"listen" validate:"required"`
+	Password      string             `json:"password"`
+	PasswordFile  string             `json:"passwordFile"`
+	DesktopName   string             `json:"desktopName"`
+	DisplaySource peripheralSDK.Name `json:"displaySource" validate:"required"`
+	KeyboardSink  peripheralSDK.Name `json:"keyboardSink"`
+	MouseSink     peripheralSDK.Name `json:"mouseSink"`
+	MaxFrameRate  *int               `json:"maxFrameRate" validate:"omitempty,min=1,max=120"`
+}
+
+func (vncConfig *VNCConfig) Decode(ctx *kong.DecodeContext) error {
+	var rawConfigLocation string
+	if err := ctx.Scan.PopValueInto("string", &rawConfigLocation); err != nil {
+		return fmt.Errorf("read vnc config flag: %w", err)
+	}
+
+	var loadedConfig VNCConfig
+	if err := loadConfigFile(rawConfigLocation, &loadedConfig); err != nil {
+		return fmt.Errorf("load vnc config: %w", err)
+	}
+
+	*vncConfig = loadedConfig
+	return nil
+}
+
+// loadConfigFile reads YAML or JSON config referenced by file:// url into config.
+func loadConfigFile(rawConfigLocation string, config any) error {
 	parsedURL, err := url.Parse(rawConfigLocation)
 	if err != nil {
-		return fmt.Errorf("parse peripheral config url %q: %w", rawConfigLocation, err)
+		return fmt.Errorf("parse config url %q: %w", rawConfigLocation, err)
 	}
 
 	if parsedURL.Scheme != "file" {
-		return fmt.Errorf("peripheral config %q: unsupported scheme %q", rawConfigLocation, parsedURL.Scheme)
+		return fmt.Errorf("config %q: unsupported scheme %q", rawConfigLocation, parsedURL.Scheme)
 	}
 
 	var filePath string
@@ -47,12 +87,12 @@ func (peripheralConfig *PeripheralConfig) Decode(ctx *kong.DecodeContext) error
 		filePath = parsedURL.Opaque
 	}
 	if filePath == "" {
-		return fmt.Errorf("peripheral config %q: missing file path", rawConfigLocation)
+		return fmt.Errorf("config %q: missing file path", rawConfigLocation)
 	}
 
 	decodedPath, err := url.PathUnescape(filePath)
 	if err != nil {
-		return fmt.Errorf("decode peripheral config path %q: %w", filePath, err)
+		return fmt.Errorf("decode config path %q: %w", filePath, err)
 	}
 
 	expandedPath, err := homedir.Expand(decodedPath)
@@ -64,21 +104,21 @@ func (peripheralConfig *PeripheralConfig) Decode(ctx *kong.DecodeContext) error
 
 	configBytes, err := os.ReadFile(normalizedPath)
 	if err != nil {
-		return fmt.Errorf("read peripheral config %s: %w", normalizedPath, err)
+		return fmt.Errorf("read config %s: %w", normalizedPath, err)
 	}
 
-	var loadedConfig PeripheralConfig
-	if err := yaml.Unmarshal(configBytes, &loadedConfig); err != nil {
-		return fmt.Errorf("unmarshal peripheral config %s: %w", normalizedPath, err)
+	if err := yaml.Unmarshal(configBytes, config); err != nil {
+		return fmt.Errorf("unmarshal config %s: %w", normalizedPath, err)
 	}
 
-	*peripheralConfig = loadedConfig
 	return nil
 }
 
 type Config struct {
 	cli.LogConfigHelper
 	cli.TransportConfigHelper
+	cli.GatewayConfigHelper
 
 	Peripheral []PeripheralConfig `help:"Path to the peripheral config as url. Currently only file:// is supported."`
+	VNC        []VNCConfig        `name:"vnc" help:"Path to the VNC server channel config as url. Currently only file:// is supported."`
 }
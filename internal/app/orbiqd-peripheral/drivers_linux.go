@@ -2,7 +2,16 @@ package orbiqd_peripheral
 
 import (
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/ffmpeg"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/image"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/mjpeg"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/mpv"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/null"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/pattern"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/qemu"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/recording"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/rfb"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/v4l2"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/app/orbiqd-peripheral/peripheral/verifier"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
 
 	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
@@ -11,7 +20,19 @@ import (
 func createDriverRepository() (driverSDK.DriverRepository, error) {
 	return driver.NewLocalRepository(
 		driver.WithDriver(v4l2.DisplaySourceDriver),
+		driver.WithDriver(v4l2.CaptureDisplaySourceDriver),
 		driver.WithDriver(ffmpeg.DisplaySinkDriver),
 		driver.WithDriver(ffmpeg.DisplaySourceDriver),
+		driver.WithDriver(ffmpeg.EncoderDisplaySinkDriver),
+		driver.WithDriver(mpv.DisplaySinkDriver),
+		driver.WithDriver(recording.DisplaySinkDriver),
+		driver.WithDriver(recording.DisplaySourceDriver),
+		driver.WithDriver(image.DisplaySourceDriver),
+		driver.WithDriver(pattern.DisplaySourceDriver),
+		driver.WithDriver(verifier.DisplaySinkDriver),
+		driver.WithDriver(null.DisplaySinkDriver),
+		driver.WithDriver(mjpeg.DisplaySinkDriver),
+		driver.WithDriver(rfb.ClientDriver),
+		driver.WithDriver(qemu.MachineDriver),
 	)
 }
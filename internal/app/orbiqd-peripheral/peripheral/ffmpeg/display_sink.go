@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/peripheral"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/ffmpeg"
 	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
@@ -37,10 +39,16 @@ var DisplaySinkDriver = driver.NewLocalDriver(DisplaySinkDriverKind, func(ctx co
 })
 
 type DisplaySinkConfig struct {
-	Title                 *string                       `json:"title"`
-	SupportedDisplayModes peripheralSDK.DisplayModeList `json:"supportedDisplayModes"`
+	Title                 *string                          `json:"title"`
+	SupportedDisplayModes any                              `json:"supportedDisplayModes"`
+	Edid                  peripheral.DisplaySinkEdidConfig `json:"edid"`
+	StaleFrameTimeout     *string                          `json:"staleFrameTimeout"`
+	SourceErrorThreshold  *int                             `json:"sourceErrorThreshold"`
 }
 
+// routeProbeInterval defines how often provider is probed while the route is not live.
+const routeProbeInterval = 250 * time.Millisecond
+
 type DisplaySinkOptions struct {
 	logger *slog.Logger
 }
@@ -72,27 +80,43 @@ type DisplaySink struct {
 	frameBufferProviderLock sync.RWMutex
 
 	supportedDisplayModes  peripheralSDK.DisplayModeList
+	edid                   []byte
 	currentDisplayMode     peripheralSDK.DisplayMode
 	currentDisplayModeLock sync.RWMutex
 
 	controller *ffmpeg.FFplayController
 
+	routeMonitor *peripheral.DisplayRouteMonitor
+
 	logger *slog.Logger
 }
 
-var _ peripheralSDK.DisplaySink = (*DisplaySink)(nil)
+var (
+	_ peripheralSDK.DisplaySink             = (*DisplaySink)(nil)
+	_ peripheralSDK.DisplaySinkInfoProvider = (*DisplaySink)(nil)
+)
 
 func NewDisplaySink(ctx context.Context, config DisplaySinkConfig, name peripheralSDK.Name, opts ...DisplaySinkOpt) (*DisplaySink, error) {
-	if len(config.SupportedDisplayModes) == 0 {
+	supportedDisplayModes, err := peripheral.LoadDisplaySinkModes(config.SupportedDisplayModes, config.Edid)
+	if err != nil {
+		return nil, fmt.Errorf("load supported display modes: %w", err)
+	}
+
+	if len(supportedDisplayModes.DisplayModes) == 0 {
 		return nil, ErrMissingSupportedDisplayMode
 	}
 
-	for _, displayMode := range config.SupportedDisplayModes {
+	for _, displayMode := range supportedDisplayModes.DisplayModes {
 		if err := displayMode.Valid(); err != nil {
 			return nil, fmt.Errorf("invalid display mode: %w", err)
 		}
 	}
 
+	staleFrameTimeout, err := time.ParseDuration(utils.DefaultNil(config.StaleFrameTimeout, "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse stale frame timeout: %w", err)
+	}
+
 	options := defaultDisplaySinkOptions()
 	for _, opt := range opts {
 		opt(&options)
@@ -101,10 +125,26 @@ func NewDisplaySink(ctx context.Context, config DisplaySinkConfig, name peripher
 	id := peripheralSDK.CreatePeripheralRandomId("ffmpeg-display-sink")
 	title := utils.DefaultNil(config.Title, "ffplay-window")
 
-	defaultDisplayMode := config.SupportedDisplayModes[0]
+	defaultDisplayMode := supportedDisplayModes.DisplayModes[0]
 
 	logger := options.logger.With(slog.String("peripheralId", string(id)))
 
+	if supportedDisplayModes.Connector != "" {
+		logger.Info("Supported display modes derived from monitor EDID.",
+			slog.String("connector", supportedDisplayModes.Connector),
+			slog.String("displayModes", supportedDisplayModes.DisplayModes.String()),
+		)
+	}
+
+	routeMonitor, err := peripheral.NewDisplayRouteMonitor(
+		peripheral.WithDisplayRouteMonitorStaleFrameTimeout(staleFrameTimeout),
+		peripheral.WithDisplayRouteMonitorErrorThreshold(utils.DefaultNil(config.SourceErrorThreshold, 3)),
+		peripheral.WithDisplayRouteMonitorLogger(logger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create display route monitor: %w", err)
+	}
+
 	controller, err := ffmpeg.NewFFplayController(ffmpeg.NewInputStdin(), ffmpeg.RawConfiguration{
 		"-nodisp",
 	},
@@ -131,18 +171,21 @@ func NewDisplaySink(ctx context.Context, config DisplaySinkConfig, name peripher
 		framePumpTicker:         time.NewTicker(time.Second),
 		frameBufferProviderLock: sync.RWMutex{},
 
-		supportedDisplayModes:  config.SupportedDisplayModes,
+		supportedDisplayModes:  supportedDisplayModes.DisplayModes,
+		edid:                   supportedDisplayModes.Edid,
 		currentDisplayMode:     defaultDisplayMode,
 		currentDisplayModeLock: sync.RWMutex{},
 
 		controller: controller,
 
+		routeMonitor: routeMonitor,
+
 		logger: logger,
 	}
 
 	go displaySink.framePump(lifecycleCtx)
 
-	err = displaySink.setControllerMissingInput(ctx)
+	err = displaySink.setControllerMissingInput(ctx, "[NO INPUT]", "ffmpeg-display-sink")
 	if err != nil {
 		displaySink.lifecycleCancel()
 		displaySink.framePumpTicker.Stop()
@@ -169,16 +212,19 @@ func (sink *DisplaySink) GetId() peripheralSDK.Id {
 	return sink.id
 }
 
-func (sink *DisplaySink) GetDisplayInfo() (peripheralSDK.DisplayInfo, error) {
+func (sink *DisplaySink) GetDisplaySinkInfo(ctx context.Context) (*peripheralSDK.DisplaySinkInfo, error) {
 	sink.currentDisplayModeLock.RLock()
-	defer sink.currentDisplayModeLock.RUnlock()
+	currentDisplayMode := sink.currentDisplayMode
+	sink.currentDisplayModeLock.RUnlock()
 
-	return peripheralSDK.DisplayInfo{
+	return &peripheralSDK.DisplaySinkInfo{
 		Manufacturer:   "FFmpeg",
 		Model:          "FFplay Window",
 		SerialNumber:   sink.id.String(),
 		SupportedModes: sink.supportedDisplayModes,
-		CurrentMode:    sink.currentDisplayMode,
+		CurrentMode:    &currentDisplayMode,
+		PixelFormats:   []peripheralSDK.DisplayPixelFormat{peripheralSDK.DisplayPixelFormatRGB24},
+		Edid:           sink.edid,
 	}, nil
 }
 
@@ -219,9 +265,13 @@ func (sink *DisplaySink) SetDisplayFrameBufferProvider(provider peripheralSDK.Di
 		sink.frameBufferProviderLock.Lock()
 		sink.frameBufferProvider = nil
 		sink.frameBufferProviderLock.Unlock()
+
+		return err
 	}
 
-	return err
+	sink.routeMonitor.Attach()
+
+	return nil
 }
 
 func (sink *DisplaySink) ClearDisplayFrameBufferProvider() error {
@@ -229,7 +279,19 @@ func (sink *DisplaySink) ClearDisplayFrameBufferProvider() error {
 	sink.frameBufferProvider = nil
 	sink.frameBufferProviderLock.Unlock()
 
-	return sink.setControllerMissingInput(sink.lifecycleCtx)
+	sink.routeMonitor.Detach()
+
+	return sink.setControllerMissingInput(sink.lifecycleCtx, "[NO INPUT]", "ffmpeg-display-sink")
+}
+
+// GetDisplayRouteState returns health of the route between attached provider and this sink.
+func (sink *DisplaySink) GetDisplayRouteState() (peripheral.DisplayRouteState, string) {
+	return sink.routeMonitor.GetState()
+}
+
+// ListenDisplayRouteStateEvents returns channel with route state transitions.
+func (sink *DisplaySink) ListenDisplayRouteStateEvents(ctx context.Context) <-chan peripheral.DisplayRouteStateEvent {
+	return sink.routeMonitor.Listen(ctx)
 }
 
 func (sink *DisplaySink) Terminate(ctx context.Context) error {
@@ -274,6 +336,14 @@ func (sink *DisplaySink) writeFrameFromProvider() error {
 	sink.frameBufferProviderLock.RUnlock()
 
 	if err != nil {
+		if event, changed := sink.routeMonitor.ObserveError(err); changed {
+			return sink.handleRouteStateEvent(event)
+		}
+
+		if errors.Is(err, peripheralSDK.ErrDisplayFrameBufferNotReady) {
+			return nil
+		}
+
 		return fmt.Errorf("get frame buffer from provider: %w", err)
 	}
 
@@ -284,6 +354,20 @@ func (sink *DisplaySink) writeFrameFromProvider() error {
 		}
 	}()
 
+	if event, changed := sink.routeMonitor.ObserveFrame(frameBuffer); changed {
+		if err := sink.handleRouteStateEvent(event); err != nil {
+			return err
+		}
+	}
+
+	if state, _ := sink.routeMonitor.GetState(); state != peripheral.DisplayRouteStateLive {
+		return nil
+	}
+
+	if err := sink.reconcileDisplayMode(frameBuffer); err != nil {
+		return err
+	}
+
 	_, err = frameBuffer.WriteTo(sink.controller.GetStdin())
 	if err != nil {
 		return fmt.Errorf("write frame to stdin: %w", err)
@@ -292,15 +376,85 @@ func (sink *DisplaySink) writeFrameFromProvider() error {
 	return nil
 }
 
-func (sink *DisplaySink) setControllerMissingInput(ctx context.Context) error {
+// reconcileDisplayMode reconfigures ffplay when provider started to deliver frames in a different display mode,
+// which happens e.g. when failover provider switches to a source with another resolution.
+func (sink *DisplaySink) reconcileDisplayMode(frameBuffer *peripheralSDK.DisplayFrameBuffer) error {
+	sink.currentDisplayModeLock.RLock()
+	currentDisplayMode := sink.currentDisplayMode
+	sink.currentDisplayModeLock.RUnlock()
+
+	expectedSize := peripheral.DisplayModeFrameSize(currentDisplayMode)
+	if frameBuffer.GetSize() == expectedSize {
+		return nil
+	}
+
+	sink.frameBufferProviderLock.RLock()
+	provider := sink.frameBufferProvider
+	sink.frameBufferProviderLock.RUnlock()
+
+	if provider == nil {
+		return nil
+	}
+
+	providerDisplayMode, err := provider.GetDisplayMode(sink.lifecycleCtx)
+	if err != nil {
+		return fmt.Errorf("get display mode from provider: %w", err)
+	}
+
+	if !sink.supportedDisplayModes.Supports(*providerDisplayMode) {
+		return fmt.Errorf("%w: %s", ErrDisplayUnsupportedDisplayMode, providerDisplayMode.String())
+	}
+
+	if *providerDisplayMode == currentDisplayMode {
+		return fmt.Errorf("%w: got %d bytes, expected %d", peripheral.ErrDisplayFrameSizeMismatch, frameBuffer.GetSize(), expectedSize)
+	}
+
+	sink.logger.Info("Provider display mode changed.",
+		slog.String("previousDisplayMode", currentDisplayMode.String()),
+		slog.String("displayMode", providerDisplayMode.String()),
+	)
+
+	sink.currentDisplayModeLock.Lock()
+	sink.currentDisplayMode = *providerDisplayMode
+	sink.currentDisplayModeLock.Unlock()
+
+	if err := sink.setControllerValidInput(sink.lifecycleCtx); err != nil {
+		return fmt.Errorf("reconfigure display mode: %w", err)
+	}
+
+	return nil
+}
+
+func (sink *DisplaySink) handleRouteStateEvent(event peripheral.DisplayRouteStateEvent) error {
+	switch event.State {
+	case peripheral.DisplayRouteStateLive:
+		if err := sink.setControllerValidInput(sink.lifecycleCtx); err != nil {
+			return fmt.Errorf("restore live input: %w", err)
+		}
+	case peripheral.DisplayRouteStateNoSignal:
+		sink.framePumpTicker.Reset(routeProbeInterval)
+		if err := sink.setControllerMissingInput(sink.lifecycleCtx, "[NO SIGNAL]", event.Reason); err != nil {
+			return fmt.Errorf("set no signal input: %w", err)
+		}
+	case peripheral.DisplayRouteStateSourceOffline:
+		sink.framePumpTicker.Reset(routeProbeInterval)
+		if err := sink.setControllerMissingInput(sink.lifecycleCtx, "[SOURCE OFFLINE]", event.Reason); err != nil {
+			return fmt.Errorf("set source offline input: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (sink *DisplaySink) setControllerMissingInput(ctx context.Context, centerText string, bottomText string) error {
 	sink.currentDisplayModeLock.Lock()
 	displayMode := sink.currentDisplayMode
 	sink.currentDisplayModeLock.Unlock()
 
-	windowTitle := sink.getWindowTitle(displayMode, true)
+	windowTitle := sink.getWindowTitle(displayMode, centerText)
 
 	return sink.controller.SetInputWithConfiguration(ctx,
-		ffmpeg.NewInputMessageBoard("[NO INPUT]", "ffmpeg-display-sink", displayMode),
+		ffmpeg.NewInputMessageBoard(centerText, escapeMessageBoardText(bottomText), displayMode),
 		ffmpeg.RawConfiguration{
 			"-window_title",
 			windowTitle,
@@ -313,7 +467,7 @@ func (sink *DisplaySink) setControllerValidInput(ctx context.Context) error {
 	displayMode := sink.currentDisplayMode
 	sink.currentDisplayModeLock.Unlock()
 
-	windowTitle := sink.getWindowTitle(displayMode, false)
+	windowTitle := sink.getWindowTitle(displayMode, "")
 
 	sink.framePumpTicker.Reset(time.Second / time.Duration(displayMode.RefreshRate))
 
@@ -333,14 +487,19 @@ func (sink *DisplaySink) setControllerValidInput(ctx context.Context) error {
 		})
 }
 
-func (sink *DisplaySink) getWindowTitle(displayMode peripheralSDK.DisplayMode, noInput bool) string {
-	if noInput {
-		return fmt.Sprintf("%s [NO INPUT] [%s]", sink.title, displayMode.String())
+func (sink *DisplaySink) getWindowTitle(displayMode peripheralSDK.DisplayMode, status string) string {
+	if status != "" {
+		return fmt.Sprintf("%s %s [%s]", sink.title, status, displayMode.String())
 	} else {
 		return fmt.Sprintf("%s [%s]", sink.title, displayMode.String())
 	}
 }
 
+// escapeMessageBoardText strips characters which have special meaning in lavfi drawtext filter.
+func escapeMessageBoardText(text string) string {
+	return strings.NewReplacer("'", "", ":", " ", ",", " ", "\\", "", "%", "").Replace(text)
+}
+
 var (
 	ErrMissingSupportedDisplayMode   = errors.New("missing supported display modes")
 	ErrDisplayUnsupportedDisplayMode = errors.New("display mode is not supported")
@@ -0,0 +1,448 @@
+package ffmpeg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/go-homedir"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/peripheral"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/ffmpeg"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const EncoderDisplaySinkDriverKind = driverSDK.Kind("ffmpeg-encoder-display-sink")
+
+var EncoderDisplaySinkDriver = driver.NewLocalDriver(EncoderDisplaySinkDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := EncoderDisplaySinkConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", EncoderDisplaySinkDriverKind.String()))
+
+	displaySink, err := NewEncoderDisplaySink(ctx, driverConfig, name, WithEncoderDisplaySinkLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return displaySink, nil
+})
+
+type EncoderDisplaySinkConfig struct {
+	Executable struct {
+		Path *string `json:"path"`
+	} `json:"executable"`
+
+	// Output is a .mp4, .mkv or .ts file path, or rtp:// or udp:// URL of a local endpoint.
+	Output           string  `json:"output" validate:"required"`
+	Codec            *string `json:"codec"`
+	Preset           *string `json:"preset"`
+	Bitrate          *string `json:"bitrate"`
+	KeyframeInterval *string `json:"keyframeInterval"`
+	SdpFile          *string `json:"sdpFile"`
+}
+
+type EncoderDisplaySinkOptions struct {
+	logger *slog.Logger
+}
+
+type EncoderDisplaySinkOpt func(*EncoderDisplaySinkOptions)
+
+func defaultEncoderDisplaySinkOptions() EncoderDisplaySinkOptions {
+	return EncoderDisplaySinkOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithEncoderDisplaySinkLogger(logger *slog.Logger) EncoderDisplaySinkOpt {
+	return func(options *EncoderDisplaySinkOptions) {
+		options.logger = logger
+	}
+}
+
+// encoderSession is a single ffmpeg process encoding frames of one display mode.
+type encoderSession struct {
+	controller  *ffmpeg.FFmpegController
+	displayMode peripheralSDK.DisplayMode
+	target      string
+}
+
+// EncoderDisplaySink encodes frames received from provider with ffmpeg into a file or a local network stream. Only
+// frames with new sequence (or timestamp) are written, ffmpeg timestamps them on arrival. Encoder is started with
+// the first frame and restarted when display mode changes; every file session is written to a new file named
+// after its start time.
+type EncoderDisplaySink struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+
+	framePump *peripheral.DisplayFramePump
+
+	output         string
+	outputIsStream bool
+	encoding       ffmpeg.VideoEncoding
+	sdpFile        string
+	controllerOpts []ffmpeg.FFmpegControlerOpt
+
+	session      *encoderSession
+	sessionLock  sync.Mutex
+	sessionCount uint64
+
+	metrics     peripheralSDK.DisplaySinkMetrics
+	metricsLock sync.RWMutex
+	framesMeter *utils.RateMeter
+
+	logger *slog.Logger
+}
+
+var (
+	_ peripheralSDK.DisplaySink                = (*EncoderDisplaySink)(nil)
+	_ peripheralSDK.DisplaySinkMetricsProvider = (*EncoderDisplaySink)(nil)
+	_ peripheralSDK.DisplaySinkInfoProvider    = (*EncoderDisplaySink)(nil)
+)
+
+func NewEncoderDisplaySink(ctx context.Context, config EncoderDisplaySinkConfig, name peripheralSDK.Name, opts ...EncoderDisplaySinkOpt) (*EncoderDisplaySink, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	keyframeInterval, err := time.ParseDuration(utils.DefaultNil(config.KeyframeInterval, "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse keyframe interval: %w", err)
+	}
+
+	encoding := ffmpeg.VideoEncoding{
+		Codec:            utils.DefaultNil(config.Codec, "libx264"),
+		Preset:           utils.DefaultNil(config.Preset, "veryfast"),
+		Bitrate:          utils.DefaultNil(config.Bitrate, "4M"),
+		KeyframeInterval: keyframeInterval,
+	}
+
+	output := config.Output
+	outputIsStream := strings.Contains(output, "://")
+
+	sdpFile := ""
+	if config.SdpFile != nil {
+		sdpFile, err = homedir.Expand(*config.SdpFile)
+		if err != nil {
+			return nil, fmt.Errorf("expand sdp file: %w", err)
+		}
+	}
+
+	// output is validated up front, so configuration errors are reported on creation instead of first frame
+	if outputIsStream {
+		if _, err := ffmpeg.NewOutputVideoStream(output, encoding, sdpFile); err != nil {
+			return nil, fmt.Errorf("create output: %w", err)
+		}
+	} else {
+		output, err = homedir.Expand(output)
+		if err != nil {
+			return nil, fmt.Errorf("expand output: %w", err)
+		}
+
+		if _, err := ffmpeg.NewOutputVideoFile(output, encoding); err != nil {
+			return nil, fmt.Errorf("create output: %w", err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+			return nil, fmt.Errorf("create output directory: %w", err)
+		}
+	}
+
+	options := defaultEncoderDisplaySinkOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("ffmpeg-encoder-display-sink")
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)))
+
+	controllerOpts := []ffmpeg.FFmpegControlerOpt{
+		ffmpeg.WithFFmpegLogger(logger),
+	}
+
+	if config.Executable.Path != nil {
+		controllerOpts = append(controllerOpts, ffmpeg.WithFFmpegExecutablePath(*config.Executable.Path))
+	}
+
+	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
+
+	displaySink := &EncoderDisplaySink{
+		id:   id,
+		name: name,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+
+		output:         output,
+		outputIsStream: outputIsStream,
+		encoding:       encoding,
+		sdpFile:        sdpFile,
+		controllerOpts: controllerOpts,
+
+		sessionLock: sync.Mutex{},
+
+		metricsLock: sync.RWMutex{},
+		framesMeter: utils.NewRateMeter(),
+
+		logger: logger,
+	}
+
+	displaySink.framePump = peripheral.NewDisplayFramePump(lifecycleCtx, displaySink.encodeFrame,
+		peripheral.WithDisplayFramePumpErrorHandler(func(err error) {
+			displaySink.updateMetrics(func(metrics *peripheralSDK.DisplaySinkMetrics) {
+				metrics.Errors++
+			})
+
+			displaySink.logger.Warn("Failed to encode frame from provider.", slog.String("error", err.Error()))
+		}),
+		peripheral.WithDisplayFramePumpLogger(logger),
+	)
+
+	displaySink.logger.Debug("The ffmpeg encoder display sink created.", slog.String("output", output))
+
+	return displaySink, nil
+}
+
+func (sink *EncoderDisplaySink) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySinkCapability,
+	}
+}
+
+func (sink *EncoderDisplaySink) GetName() peripheralSDK.Name {
+	return sink.name
+}
+
+func (sink *EncoderDisplaySink) GetId() peripheralSDK.Id {
+	return sink.id
+}
+
+// GetDisplaySinkInfo returns info with current display mode of the attached provider. Any valid display mode is
+// accepted.
+func (sink *EncoderDisplaySink) GetDisplaySinkInfo(ctx context.Context) (*peripheralSDK.DisplaySinkInfo, error) {
+	return &peripheralSDK.DisplaySinkInfo{
+		Manufacturer:   "FFmpeg",
+		Model:          "Encoder",
+		SerialNumber:   sink.id.String(),
+		SupportedModes: peripheralSDK.DisplayModeList{},
+		PixelFormats:   []peripheralSDK.DisplayPixelFormat{peripheralSDK.DisplayPixelFormatRGB24},
+		CurrentMode:    sink.framePump.GetDisplayMode(),
+	}, nil
+}
+
+func (sink *EncoderDisplaySink) SetDisplayFrameBufferProvider(provider peripheralSDK.DisplayFrameBufferProvider) error {
+	_, err := sink.framePump.Attach(provider)
+
+	return err
+}
+
+func (sink *EncoderDisplaySink) ClearDisplayFrameBufferProvider() error {
+	sink.framePump.Detach()
+
+	ctx, cancel := context.WithTimeout(sink.lifecycleCtx, 10*time.Second)
+	defer cancel()
+
+	sink.sessionLock.Lock()
+	defer sink.sessionLock.Unlock()
+
+	return sink.stopSession(ctx)
+}
+
+func (sink *EncoderDisplaySink) GetDisplaySinkMetrics(ctx context.Context) (*peripheralSDK.DisplaySinkMetrics, error) {
+	sink.metricsLock.RLock()
+	metrics := sink.metrics
+	sink.metricsLock.RUnlock()
+
+	metrics.FramesPerSecond = sink.framesMeter.Rate()
+
+	sink.sessionLock.Lock()
+	defer sink.sessionLock.Unlock()
+
+	metrics.AdditionalMetrics = map[string]interface{}{
+		"sessions": sink.sessionCount,
+	}
+
+	if sink.session != nil {
+		status := sink.session.controller.GetStatus()
+
+		metrics.AdditionalMetrics["target"] = sink.session.target
+		metrics.AdditionalMetrics["displayMode"] = sink.session.displayMode.String()
+		metrics.AdditionalMetrics["encoderFrameRate"] = status.FrameRate
+		metrics.AdditionalMetrics["encoderSpeed"] = status.Speed
+		metrics.AdditionalMetrics["encoderDroppedFrames"] = status.DroppedFrames
+		metrics.AdditionalMetrics["encoderTotalSize"] = status.TotalSize
+	}
+
+	return &metrics, nil
+}
+
+func (sink *EncoderDisplaySink) Terminate(ctx context.Context) error {
+	sink.lifecycleCancel()
+
+	// frame being encoded would start a new encoder after the session is stopped
+	sink.framePump.Stop()
+
+	sink.sessionLock.Lock()
+	defer sink.sessionLock.Unlock()
+
+	return sink.stopSession(ctx)
+}
+
+func (sink *EncoderDisplaySink) encodeFrame(frame peripheral.DisplayFrame) error {
+	sink.sessionLock.Lock()
+	defer sink.sessionLock.Unlock()
+
+	displayMode := frame.DisplayMode
+
+	if sink.session != nil && sink.session.displayMode != displayMode {
+		sink.logger.Info("Provider display mode changed, restarting encoder.",
+			slog.String("previousDisplayMode", sink.session.displayMode.String()),
+			slog.String("displayMode", displayMode.String()),
+		)
+
+		if err := sink.stopSession(sink.lifecycleCtx); err != nil {
+			return err
+		}
+	}
+
+	if sink.session == nil {
+		if err := sink.startSession(displayMode); err != nil {
+			return err
+		}
+	}
+
+	if _, err := sink.session.controller.GetStdin().Write(frame.Data); err != nil {
+		// encoder is started again with the next frame, file outputs continue in a new file
+		if stopErr := sink.stopSession(sink.lifecycleCtx); stopErr != nil {
+			sink.logger.Warn("Failed to stop encoder.", slog.String("error", stopErr.Error()))
+		}
+
+		return fmt.Errorf("write frame to encoder: %w", err)
+	}
+
+	sink.framesMeter.Add(1)
+	sink.updateMetrics(func(metrics *peripheralSDK.DisplaySinkMetrics) {
+		metrics.FramesReceived++
+		metrics.BytesReceived += uint64(len(frame.Data))
+	})
+
+	return nil
+}
+
+// startSession starts encoder for frames of display mode. Caller must hold sessionLock.
+func (sink *EncoderDisplaySink) startSession(displayMode peripheralSDK.DisplayMode) error {
+	var output *ffmpeg.OutputVideo
+	var err error
+
+	if sink.outputIsStream {
+		output, err = ffmpeg.NewOutputVideoStream(sink.output, sink.encoding, sink.sdpFile)
+	} else {
+		output, err = ffmpeg.NewOutputVideoFile(sink.nextSessionPath(time.Now()), sink.encoding)
+	}
+	if err != nil {
+		return fmt.Errorf("create output: %w", err)
+	}
+
+	// frames are timestamped with wall clock on arrival, as they are written only when the source produced a new one
+	inputConfiguration := ffmpeg.RawConfiguration{
+		"-f",
+		"rawvideo",
+		"-pixel_format",
+		"rgb24",
+		"-video_size",
+		fmt.Sprintf("%dx%d", displayMode.Width, displayMode.Height),
+		"-framerate",
+		fmt.Sprintf("%d", displayMode.RefreshRate),
+		"-use_wallclock_as_timestamps",
+		"1",
+	}
+
+	controllerOpts := slices.Concat(sink.controllerOpts, []ffmpeg.FFmpegControlerOpt{
+		ffmpeg.WithFFmpegInputConfiguration(inputConfiguration),
+	})
+
+	controller, err := ffmpeg.NewFFmpegController(ffmpeg.NewInputStdin(), output, ffmpeg.RawConfiguration{}, controllerOpts...)
+	if err != nil {
+		return fmt.Errorf("create ffmpeg controller: %w", err)
+	}
+
+	if err := controller.Start(sink.lifecycleCtx); err != nil {
+		return fmt.Errorf("start ffmpeg controller: %w", err)
+	}
+
+	sink.session = &encoderSession{
+		controller:  controller,
+		displayMode: displayMode,
+		target:      output.GetTarget(),
+	}
+	sink.sessionCount++
+
+	sink.logger.Info("Encoder started.",
+		slog.String("target", output.GetTarget()),
+		slog.String("displayMode", displayMode.String()),
+	)
+
+	return nil
+}
+
+// stopSession stops running encoder, if any. Caller must hold sessionLock.
+func (sink *EncoderDisplaySink) stopSession(ctx context.Context) error {
+	if sink.session == nil {
+		return nil
+	}
+
+	session := sink.session
+	sink.session = nil
+
+	if err := session.controller.Stop(ctx); err != nil {
+		return fmt.Errorf("stop ffmpeg controller: %w", err)
+	}
+
+	sink.logger.Info("Encoder stopped.", slog.String("target", session.target))
+
+	return nil
+}
+
+// nextSessionPath returns path of a new file for the session started at startedAt, e.g. recording.mp4 becomes
+// recording-20250102-150405.mp4. Suffix is added when the file already exists.
+func (sink *EncoderDisplaySink) nextSessionPath(startedAt time.Time) string {
+	extension := filepath.Ext(sink.output)
+	base := fmt.Sprintf("%s-%s", strings.TrimSuffix(sink.output, extension), startedAt.Format("20060102-150405"))
+
+	path := base + extension
+	for suffix := 1; ; suffix++ {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return path
+		}
+
+		path = fmt.Sprintf("%s-%d%s", base, suffix, extension)
+	}
+}
+
+func (sink *EncoderDisplaySink) updateMetrics(updateFn func(metrics *peripheralSDK.DisplaySinkMetrics)) {
+	sink.metricsLock.Lock()
+	defer sink.metricsLock.Unlock()
+
+	updateFn(&sink.metrics)
+}
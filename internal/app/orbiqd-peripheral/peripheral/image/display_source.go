@@ -0,0 +1,377 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/go-homedir"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/imagefile"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/rgb"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	memorySDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const DisplaySourceDriverKind = driverSDK.Kind("image-display-source")
+
+var DisplaySourceDriver = driver.NewLocalDriver(DisplaySourceDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := DisplaySourceConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", DisplaySourceDriverKind.String()))
+
+	displaySource, err := NewDisplaySource(ctx, driverConfig, name, WithDisplaySourceLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return displaySource, nil
+})
+
+const (
+	DisplaySourceScalingFit     = "fit"
+	DisplaySourceScalingStretch = "stretch"
+)
+
+const defaultRefreshRate = 30
+
+type DisplaySourceConfig struct {
+	Path string `json:"path" validate:"required"`
+	// DisplayMode overrides served display mode. Zero width and height keep native image size, zero refresh rate
+	// defaults to 30.
+	DisplayMode *peripheralSDK.DisplayMode `json:"displayMode"`
+	// Scaling selects how image is resized to configured display mode: fit keeps aspect ratio with black bars,
+	// stretch fills whole frame.
+	Scaling       *string `json:"scaling" validate:"omitempty,oneof=fit stretch"`
+	Watch         *bool   `json:"watch"`
+	WatchInterval *string `json:"watchInterval"`
+}
+
+type DisplaySourceOptions struct {
+	logger *slog.Logger
+}
+
+type DisplaySourceOpt func(*DisplaySourceOptions)
+
+func defaultDisplaySourceOptions() DisplaySourceOptions {
+	return DisplaySourceOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithDisplaySourceLogger(logger *slog.Logger) DisplaySourceOpt {
+	return func(options *DisplaySourceOptions) {
+		options.logger = logger
+	}
+}
+
+// DisplaySource serves a still image loaded from PNG, JPEG or PPM file. The same frame is served with a new
+// sequence on every refresh interval, so sinks do not treat the image as a lost signal.
+type DisplaySource struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+
+	path           string
+	scaling        string
+	configuredMode peripheralSDK.DisplayMode
+	fileModifiedAt time.Time
+	fileSize       int64
+	decodeBuffer   []byte
+	scaleBuffer    []byte
+	startedAt      time.Time
+	reloadLock     sync.Mutex
+
+	memoryBuffer     memorySDK.Buffer
+	displayMode      peripheralSDK.DisplayMode
+	memoryBufferLock sync.RWMutex
+
+	pixelFormat peripheralSDK.DisplayPixelFormat
+
+	metrics     peripheralSDK.DisplaySourceMetrics
+	metricsLock sync.RWMutex
+
+	logger *slog.Logger
+}
+
+var _ peripheralSDK.DisplaySource = (*DisplaySource)(nil)
+
+func NewDisplaySource(ctx context.Context, config DisplaySourceConfig, name peripheralSDK.Name, opts ...DisplaySourceOpt) (*DisplaySource, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	path, err := homedir.Expand(config.Path)
+	if err != nil {
+		return nil, fmt.Errorf("expand path: %w", err)
+	}
+
+	watchInterval, err := time.ParseDuration(utils.DefaultNil(config.WatchInterval, "1s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse watch interval: %w", err)
+	}
+
+	configuredMode := utils.DefaultNil(config.DisplayMode, peripheralSDK.DisplayMode{})
+	if configuredMode.RefreshRate == 0 {
+		configuredMode.RefreshRate = defaultRefreshRate
+	}
+	if (configuredMode.Width == 0) != (configuredMode.Height == 0) {
+		return nil, fmt.Errorf("%w: width and height must be both set or both zero", ErrDisplayModeInvalid)
+	}
+
+	options := defaultDisplaySourceOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("image-display-source")
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)))
+
+	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
+
+	source := &DisplaySource{
+		id:   id,
+		name: name,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+
+		path:           path,
+		scaling:        utils.DefaultNil(config.Scaling, DisplaySourceScalingFit),
+		configuredMode: configuredMode,
+		startedAt:      time.Now(),
+		reloadLock:     sync.Mutex{},
+
+		memoryBufferLock: sync.RWMutex{},
+
+		pixelFormat: peripheralSDK.DisplayPixelFormatRGB24,
+
+		metricsLock: sync.RWMutex{},
+
+		logger: logger,
+	}
+
+	if _, err := source.reloadImage(true); err != nil {
+		lifecycleCancel()
+		return nil, err
+	}
+
+	if utils.DefaultNil(config.Watch, false) {
+		go source.watchLoop(lifecycleCtx, watchInterval)
+	}
+
+	source.logger.Debug("The image display source created.", slog.String("path", path))
+
+	return source, nil
+}
+
+func (source *DisplaySource) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySourceCapability,
+	}
+}
+
+func (source *DisplaySource) GetId() peripheralSDK.Id {
+	return source.id
+}
+
+func (source *DisplaySource) GetName() peripheralSDK.Name {
+	return source.name
+}
+
+func (source *DisplaySource) Terminate(ctx context.Context) error {
+	source.lifecycleCancel()
+
+	source.memoryBufferLock.Lock()
+	defer source.memoryBufferLock.Unlock()
+
+	if source.memoryBuffer == nil {
+		return nil
+	}
+
+	err := source.memoryBuffer.Release()
+	source.memoryBuffer = nil
+	if err != nil {
+		return fmt.Errorf("release memory buffer: %w", err)
+	}
+
+	return nil
+}
+
+func (source *DisplaySource) GetDisplayMode(ctx context.Context) (*peripheralSDK.DisplayMode, error) {
+	source.memoryBufferLock.RLock()
+	defer source.memoryBufferLock.RUnlock()
+
+	displayMode := source.displayMode
+
+	return &displayMode, nil
+}
+
+func (source *DisplaySource) GetDisplayPixelFormat(ctx context.Context) (*peripheralSDK.DisplayPixelFormat, error) {
+	return &source.pixelFormat, nil
+}
+
+func (source *DisplaySource) GetDisplayFrameBuffer(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+	source.memoryBufferLock.RLock()
+	defer source.memoryBufferLock.RUnlock()
+
+	if source.memoryBuffer == nil {
+		return nil, peripheralSDK.ErrDisplayFrameBufferNotReady
+	}
+
+	err := source.memoryBuffer.Retain()
+	if err != nil {
+		return nil, fmt.Errorf("retain memory buffer: %w", err)
+	}
+
+	now := time.Now()
+	frameInterval := time.Second / time.Duration(source.displayMode.RefreshRate)
+	sequence := uint64(now.Sub(source.startedAt)/frameInterval) + 1
+
+	return peripheralSDK.NewDisplayFrameBuffer(source.memoryBuffer,
+		peripheralSDK.WithDisplayFrameBufferSequence(sequence),
+		peripheralSDK.WithDisplayFrameBufferTimestamp(source.startedAt.Add(time.Duration(sequence-1)*frameInterval)),
+	), nil
+}
+
+func (source *DisplaySource) GetDisplaySourceMetrics() peripheralSDK.DisplaySourceMetrics {
+	source.metricsLock.RLock()
+	defer source.metricsLock.RUnlock()
+
+	return source.metrics
+}
+
+func (source *DisplaySource) updateMetrics(updateFn func(metrics *peripheralSDK.DisplaySourceMetrics)) {
+	source.metricsLock.Lock()
+	defer source.metricsLock.Unlock()
+
+	updateFn(&source.metrics)
+}
+
+func (source *DisplaySource) watchLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := source.reloadImage(false)
+			if err != nil {
+				source.logger.Warn("Failed to reload image, previous image is kept.", slog.String("error", err.Error()))
+				continue
+			}
+
+			if reloaded {
+				source.logger.Info("Image reloaded.", slog.String("path", source.path))
+			}
+		}
+	}
+}
+
+// reloadImage decodes image file and swaps served frame. Unless forced, file is decoded only when its modification
+// time or size changed.
+func (source *DisplaySource) reloadImage(force bool) (bool, error) {
+	source.reloadLock.Lock()
+	defer source.reloadLock.Unlock()
+
+	fileInfo, err := os.Stat(source.path)
+	if err != nil {
+		return false, fmt.Errorf("stat image file: %w", err)
+	}
+
+	if !force && fileInfo.ModTime().Equal(source.fileModifiedAt) && fileInfo.Size() == source.fileSize {
+		return false, nil
+	}
+
+	decodedImage, err := imagefile.DecodeFile(source.decodeBuffer, source.path)
+	if err != nil {
+		return false, fmt.Errorf("decode image file: %w", err)
+	}
+	source.decodeBuffer = decodedImage.Pixels
+
+	displayMode := source.configuredMode
+	pixels := decodedImage.Pixels
+
+	if displayMode.Width == 0 {
+		displayMode.Width = decodedImage.Width
+		displayMode.Height = decodedImage.Height
+	}
+
+	if displayMode.Width != decodedImage.Width || displayMode.Height != decodedImage.Height {
+		scaleFn := rgb.Fit
+		if source.scaling == DisplaySourceScalingStretch {
+			scaleFn = rgb.Scale
+		}
+
+		source.scaleBuffer = scaleFn(source.scaleBuffer, pixels,
+			int(decodedImage.Width), int(decodedImage.Height),
+			int(displayMode.Width), int(displayMode.Height),
+		)
+		pixels = source.scaleBuffer
+	}
+
+	memoryPool, err := memory.DefaultMemoryPoolProvider()
+	if err != nil {
+		return false, fmt.Errorf("get memory pool provider: %w", err)
+	}
+
+	memoryBuffer, err := memoryPool.Borrow(len(pixels))
+	if err != nil {
+		return false, fmt.Errorf("borrow memory buffer: %w", err)
+	}
+
+	if _, err := memoryBuffer.Write(pixels); err != nil {
+		_ = memoryBuffer.Release()
+		return false, fmt.Errorf("write memory buffer: %w", err)
+	}
+
+	source.memoryBufferLock.Lock()
+	previousMemoryBuffer := source.memoryBuffer
+	source.memoryBuffer = memoryBuffer
+	source.displayMode = displayMode
+	source.memoryBufferLock.Unlock()
+
+	if previousMemoryBuffer != nil {
+		if err := previousMemoryBuffer.Release(); err != nil {
+			source.logger.Warn("Failed to release memory buffer.", slog.String("error", err.Error()))
+		}
+	}
+
+	source.fileModifiedAt = fileInfo.ModTime()
+	source.fileSize = fileInfo.Size()
+
+	source.updateMetrics(func(metrics *peripheralSDK.DisplaySourceMetrics) {
+		metrics.FrameBufferSwaps++
+		metrics.FrameBufferWrittenBytes += uint64(len(pixels))
+	})
+
+	source.logger.Debug("Image loaded.",
+		slog.String("format", decodedImage.Format.String()),
+		slog.String("displayMode", displayMode.String()),
+	)
+
+	return true, nil
+}
+
+var ErrDisplayModeInvalid = errors.New("display mode invalid")
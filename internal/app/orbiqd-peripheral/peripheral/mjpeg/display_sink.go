@@ -0,0 +1,300 @@
+package mjpeg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/peripheral"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/mjpeg"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const DisplaySinkDriverKind = driverSDK.Kind("http-mjpeg-display-sink")
+
+var DisplaySinkDriver = driver.NewLocalDriver(DisplaySinkDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := DisplaySinkConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", DisplaySinkDriverKind.String()))
+
+	displaySink, err := NewDisplaySink(ctx, driverConfig, name, WithDisplaySinkLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return displaySink, nil
+})
+
+type DisplaySinkConfig struct {
+	// Listen is the address of HTTP listener. It defaults to loopback interface, so the stream is not exposed to the
+	// network unless configured explicitly.
+	Listen  *string `json:"listen"`
+	Quality *int    `json:"quality" validate:"omitempty,min=1,max=100"`
+	// MaxFrameRate limits number of encoded frames per second. Zero follows refresh rate of the source.
+	MaxFrameRate *uint32 `json:"maxFrameRate" validate:"omitempty,max=240"`
+}
+
+const (
+	streamPath   = "/stream.mjpg"
+	snapshotPath = "/snapshot.jpg"
+)
+
+type DisplaySinkOptions struct {
+	logger *slog.Logger
+}
+
+type DisplaySinkOpt func(*DisplaySinkOptions)
+
+func defaultDisplaySinkOptions() DisplaySinkOptions {
+	return DisplaySinkOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithDisplaySinkLogger(logger *slog.Logger) DisplaySinkOpt {
+	return func(options *DisplaySinkOptions) {
+		options.logger = logger
+	}
+}
+
+// DisplaySink serves routed frames to web browsers as MJPEG stream over HTTP. Frames are encoded only while at least
+// one client watches the stream, snapshot endpoint encodes the current frame on request.
+type DisplaySink struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+
+	framePump *peripheral.DisplayFramePump
+
+	stream  *mjpeg.Stream
+	encoder *mjpeg.Encoder
+
+	server   *http.Server
+	listener net.Listener
+
+	logger *slog.Logger
+}
+
+var (
+	_ peripheralSDK.DisplaySink             = (*DisplaySink)(nil)
+	_ peripheralSDK.DisplaySinkInfoProvider = (*DisplaySink)(nil)
+)
+
+func NewDisplaySink(ctx context.Context, config DisplaySinkConfig, name peripheralSDK.Name, opts ...DisplaySinkOpt) (*DisplaySink, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	options := defaultDisplaySinkOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	encoder, err := mjpeg.NewEncoder(utils.DefaultNil(config.Quality, 80))
+	if err != nil {
+		return nil, fmt.Errorf("create encoder: %w", err)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("http-mjpeg-display-sink")
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)))
+
+	listener, err := net.Listen("tcp", utils.DefaultNil(config.Listen, "127.0.0.1:8090"))
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
+
+	displaySink := &DisplaySink{
+		id:   id,
+		name: name,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+
+		stream:  mjpeg.NewStream(),
+		encoder: encoder,
+
+		listener: listener,
+
+		logger: logger,
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET "+streamPath, displaySink.stream)
+	mux.HandleFunc("GET "+snapshotPath, displaySink.handleSnapshot)
+	mux.HandleFunc("GET /{$}", displaySink.handleIndex)
+
+	displaySink.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return lifecycleCtx
+		},
+	}
+
+	displaySink.framePump = peripheral.NewDisplayFramePump(lifecycleCtx, displaySink.publishFrame,
+		peripheral.WithDisplayFramePumpMaxFrameRate(utils.DefaultNil(config.MaxFrameRate, 0)),
+		peripheral.WithDisplayFramePumpCondition(func() bool {
+			return displaySink.stream.GetWatchers() > 0
+		}),
+		peripheral.WithDisplayFramePumpErrorHandler(func(err error) {
+			displaySink.logger.Warn("Failed to publish frame from provider.", slog.String("error", err.Error()))
+		}),
+		peripheral.WithDisplayFramePumpLogger(logger),
+	)
+
+	go displaySink.serve()
+
+	displaySink.logger.Info("The MJPEG display sink is listening.", slog.String("address", listener.Addr().String()))
+
+	return displaySink, nil
+}
+
+func (sink *DisplaySink) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySinkCapability,
+	}
+}
+
+func (sink *DisplaySink) GetName() peripheralSDK.Name {
+	return sink.name
+}
+
+func (sink *DisplaySink) GetId() peripheralSDK.Id {
+	return sink.id
+}
+
+// GetAddress returns address of the HTTP listener.
+func (sink *DisplaySink) GetAddress() net.Addr {
+	return sink.listener.Addr()
+}
+
+// GetDisplaySinkInfo returns info with current display mode of the attached provider. Any valid display mode is
+// accepted.
+func (sink *DisplaySink) GetDisplaySinkInfo(ctx context.Context) (*peripheralSDK.DisplaySinkInfo, error) {
+	return &peripheralSDK.DisplaySinkInfo{
+		Manufacturer:   "OrbiqD",
+		Model:          "MJPEG Stream",
+		SerialNumber:   sink.id.String(),
+		SupportedModes: peripheralSDK.DisplayModeList{},
+		PixelFormats:   []peripheralSDK.DisplayPixelFormat{peripheralSDK.DisplayPixelFormatRGB24},
+		CurrentMode:    sink.framePump.GetDisplayMode(),
+	}, nil
+}
+
+func (sink *DisplaySink) SetDisplayFrameBufferProvider(provider peripheralSDK.DisplayFrameBufferProvider) error {
+	_, err := sink.framePump.Attach(provider)
+
+	return err
+}
+
+func (sink *DisplaySink) ClearDisplayFrameBufferProvider() error {
+	sink.framePump.Detach()
+
+	return nil
+}
+
+func (sink *DisplaySink) Terminate(ctx context.Context) error {
+	sink.lifecycleCancel()
+	sink.framePump.Stop()
+
+	sink.stream.Close()
+
+	err := sink.server.Shutdown(ctx)
+	if err != nil {
+		return fmt.Errorf("shutdown http server: %w", err)
+	}
+
+	return nil
+}
+
+func (sink *DisplaySink) serve() {
+	err := sink.server.Serve(sink.listener)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		sink.logger.Error("HTTP server failed.", slog.String("error", err.Error()))
+	}
+}
+
+func (sink *DisplaySink) publishFrame(frame peripheral.DisplayFrame) error {
+	image, err := sink.encodeFrame(frame)
+	if err != nil {
+		return err
+	}
+
+	sink.stream.Publish(image)
+
+	return nil
+}
+
+// encodeFrame returns JPEG image of the frame. Frames are passed by the pump one at a time, so the encoder is not
+// used concurrently.
+func (sink *DisplaySink) encodeFrame(frame peripheral.DisplayFrame) ([]byte, error) {
+	image, err := sink.encoder.Encode(frame.Data, int(frame.DisplayMode.Width), int(frame.DisplayMode.Height))
+	if err != nil {
+		return nil, fmt.Errorf("encode frame: %w", err)
+	}
+
+	return bytes.Clone(image), nil
+}
+
+func (sink *DisplaySink) handleSnapshot(writer http.ResponseWriter, request *http.Request) {
+	var image []byte
+
+	err := sink.framePump.ReadFrame(func(frame peripheral.DisplayFrame) error {
+		var err error
+		image, err = sink.encodeFrame(frame)
+
+		return err
+	})
+	if errors.Is(err, peripheral.ErrDisplayFrameBufferProviderMissing) || errors.Is(err, peripheralSDK.ErrDisplayFrameBufferNotReady) {
+		http.Error(writer, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		sink.logger.Warn("Failed to encode snapshot.", slog.String("error", err.Error()))
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "image/jpeg")
+	writer.Header().Set("Content-Length", strconv.Itoa(len(image)))
+	writer.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	_, _ = writer.Write(image)
+}
+
+func (sink *DisplaySink) handleIndex(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = fmt.Fprintf(writer, indexPage, html.EscapeString(sink.name.String()), streamPath)
+}
+
+const indexPage = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>%s</title></head>
+<body style="margin:0;background:#000;display:flex;align-items:center;justify-content:center;height:100vh">
+<img src="%s" style="max-width:100%%;max-height:100%%">
+</body>
+</html>
+`
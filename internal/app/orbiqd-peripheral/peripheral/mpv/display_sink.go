@@ -0,0 +1,436 @@
+package mpv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/peripheral"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/mpv"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const DisplaySinkDriverKind = driverSDK.Kind("mpv-display-sink")
+
+var DisplaySinkDriver = driver.NewLocalDriver(DisplaySinkDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := DisplaySinkConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", DisplaySinkDriverKind.String()))
+
+	displaySink, err := NewDisplaySink(ctx, driverConfig, name, WithDisplaySinkLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return displaySink, nil
+})
+
+type DisplaySinkConfig struct {
+	Executable struct {
+		Path *string `json:"path"`
+	} `json:"executable"`
+
+	Title                 *string                          `json:"title"`
+	SupportedDisplayModes any                              `json:"supportedDisplayModes"`
+	Edid                  peripheral.DisplaySinkEdidConfig `json:"edid"`
+	Fullscreen            *bool                            `json:"fullscreen"`
+	IpcSocketPath         *string                          `json:"ipcSocketPath"`
+	StaleFrameTimeout     *string                          `json:"staleFrameTimeout"`
+	SourceErrorThreshold  *int                             `json:"sourceErrorThreshold"`
+}
+
+// controlTimeout limits mpv IPC commands issued outside of a caller context.
+const controlTimeout = 5 * time.Second
+
+type DisplaySinkOptions struct {
+	logger *slog.Logger
+}
+
+type DisplaySinkOpt func(*DisplaySinkOptions)
+
+func defaultDisplaySinkOptions() DisplaySinkOptions {
+	return DisplaySinkOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithDisplaySinkLogger(logger *slog.Logger) DisplaySinkOpt {
+	return func(options *DisplaySinkOptions) {
+		options.logger = logger
+	}
+}
+
+// DisplaySink renders frames pumped from provider in mpv window. Unlike the ffplay sink, mpv is started once and
+// controlled through JSON IPC: route state is shown as OSD message and display mode changes reload the video
+// without closing the window.
+type DisplaySink struct {
+	id    peripheralSDK.Id
+	name  peripheralSDK.Name
+	title string
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+
+	framePump *peripheral.DisplayFramePump
+
+	supportedDisplayModes  peripheralSDK.DisplayModeList
+	edid                   []byte
+	currentDisplayMode     peripheralSDK.DisplayMode
+	currentDisplayModeLock sync.RWMutex
+
+	controller *mpv.Controller
+
+	routeMonitor     *peripheral.DisplayRouteMonitor
+	routeMonitorDone chan struct{}
+
+	logger *slog.Logger
+}
+
+var (
+	_ peripheralSDK.DisplaySink             = (*DisplaySink)(nil)
+	_ peripheralSDK.DisplaySinkInfoProvider = (*DisplaySink)(nil)
+)
+
+func NewDisplaySink(ctx context.Context, config DisplaySinkConfig, name peripheralSDK.Name, opts ...DisplaySinkOpt) (*DisplaySink, error) {
+	supportedDisplayModes, err := peripheral.LoadDisplaySinkModes(config.SupportedDisplayModes, config.Edid)
+	if err != nil {
+		return nil, fmt.Errorf("load supported display modes: %w", err)
+	}
+
+	if len(supportedDisplayModes.DisplayModes) == 0 {
+		return nil, ErrMissingSupportedDisplayMode
+	}
+
+	for _, displayMode := range supportedDisplayModes.DisplayModes {
+		if err := displayMode.Valid(); err != nil {
+			return nil, fmt.Errorf("invalid display mode: %w", err)
+		}
+	}
+
+	staleFrameTimeout, err := time.ParseDuration(utils.DefaultNil(config.StaleFrameTimeout, "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse stale frame timeout: %w", err)
+	}
+
+	options := defaultDisplaySinkOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("mpv-display-sink")
+	title := utils.DefaultNil(config.Title, "mpv-window")
+
+	ipcSocketPath, err := homedir.Expand(utils.DefaultNil(config.IpcSocketPath, filepath.Join(os.TempDir(), id.String()+".sock")))
+	if err != nil {
+		return nil, fmt.Errorf("expand ipc socket path: %w", err)
+	}
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)))
+
+	if supportedDisplayModes.Connector != "" {
+		logger.Info("Supported display modes derived from monitor EDID.",
+			slog.String("connector", supportedDisplayModes.Connector),
+			slog.String("displayModes", supportedDisplayModes.DisplayModes.String()),
+		)
+	}
+
+	routeMonitor, err := peripheral.NewDisplayRouteMonitor(
+		peripheral.WithDisplayRouteMonitorStaleFrameTimeout(staleFrameTimeout),
+		peripheral.WithDisplayRouteMonitorErrorThreshold(utils.DefaultNil(config.SourceErrorThreshold, 3)),
+		peripheral.WithDisplayRouteMonitorLogger(logger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create display route monitor: %w", err)
+	}
+
+	controllerOpts := []mpv.ControllerOpt{
+		mpv.WithControllerLogger(logger),
+		mpv.WithControllerArguments("--title=" + title),
+	}
+
+	if utils.DefaultNil(config.Fullscreen, false) {
+		controllerOpts = append(controllerOpts, mpv.WithControllerArguments("--fullscreen"))
+	}
+
+	if config.Executable.Path != nil {
+		controllerOpts = append(controllerOpts, mpv.WithControllerExecutablePath(*config.Executable.Path))
+	}
+
+	controller, err := mpv.NewController(ipcSocketPath, controllerOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("create mpv controller: %w", err)
+	}
+
+	if err := controller.Start(ctx); err != nil {
+		return nil, fmt.Errorf("start mpv controller: %w", err)
+	}
+
+	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
+
+	displaySink := &DisplaySink{
+		id:    id,
+		name:  name,
+		title: title,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+
+		supportedDisplayModes:  supportedDisplayModes.DisplayModes,
+		edid:                   supportedDisplayModes.Edid,
+		currentDisplayMode:     supportedDisplayModes.DisplayModes[0],
+		currentDisplayModeLock: sync.RWMutex{},
+
+		controller: controller,
+
+		routeMonitor:     routeMonitor,
+		routeMonitorDone: make(chan struct{}),
+
+		logger: logger,
+	}
+
+	displaySink.framePump = peripheral.NewDisplayFramePump(lifecycleCtx, displaySink.writeFrame,
+		peripheral.WithDisplayFramePumpRouteMonitor(routeMonitor),
+		peripheral.WithDisplayFramePumpLogger(logger),
+	)
+
+	go displaySink.handleRouteStateEvents(routeMonitor.Listen(lifecycleCtx))
+
+	err = displaySink.setControllerStatus(ctx, "[NO INPUT]")
+	if err != nil {
+		displaySink.lifecycleCancel()
+		displaySink.framePump.Stop()
+		_ = displaySink.controller.Stop(ctx)
+		return nil, fmt.Errorf("set controller status: %w", err)
+	}
+
+	displaySink.logger.Debug("The mpv display sink created.", slog.String("ipcSocketPath", ipcSocketPath))
+
+	return displaySink, nil
+}
+
+func (sink *DisplaySink) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySinkCapability,
+	}
+}
+
+func (sink *DisplaySink) GetName() peripheralSDK.Name {
+	return sink.name
+}
+
+func (sink *DisplaySink) GetId() peripheralSDK.Id {
+	return sink.id
+}
+
+func (sink *DisplaySink) GetDisplaySinkInfo(ctx context.Context) (*peripheralSDK.DisplaySinkInfo, error) {
+	sink.currentDisplayModeLock.RLock()
+	currentDisplayMode := sink.currentDisplayMode
+	sink.currentDisplayModeLock.RUnlock()
+
+	return &peripheralSDK.DisplaySinkInfo{
+		Manufacturer:   "mpv",
+		Model:          "mpv Window",
+		SerialNumber:   sink.id.String(),
+		SupportedModes: sink.supportedDisplayModes,
+		CurrentMode:    &currentDisplayMode,
+		PixelFormats:   []peripheralSDK.DisplayPixelFormat{peripheralSDK.DisplayPixelFormatRGB24},
+		Edid:           sink.edid,
+	}, nil
+}
+
+func (sink *DisplaySink) SetDisplayFrameBufferProvider(provider peripheralSDK.DisplayFrameBufferProvider) error {
+	providerDisplayMode, err := provider.GetDisplayMode(sink.lifecycleCtx)
+	if err != nil {
+		return fmt.Errorf("get display mode from provider: %w", err)
+	}
+
+	if !sink.supportedDisplayModes.Supports(*providerDisplayMode) {
+		return ErrDisplayUnsupportedDisplayMode
+	}
+
+	displayMode, err := sink.framePump.Attach(provider)
+	if err != nil {
+		return err
+	}
+
+	err = sink.loadDisplayMode(displayMode)
+	if err != nil {
+		sink.framePump.Detach()
+
+		return err
+	}
+
+	return nil
+}
+
+func (sink *DisplaySink) ClearDisplayFrameBufferProvider() error {
+	sink.framePump.Detach()
+
+	ctx, cancel := context.WithTimeout(sink.lifecycleCtx, controlTimeout)
+	defer cancel()
+
+	return sink.setControllerStatus(ctx, "[NO INPUT]")
+}
+
+// GetDisplayRouteState returns health of the route between attached provider and this sink.
+func (sink *DisplaySink) GetDisplayRouteState() (peripheral.DisplayRouteState, string) {
+	return sink.routeMonitor.GetState()
+}
+
+// ListenDisplayRouteStateEvents returns channel with route state transitions.
+func (sink *DisplaySink) ListenDisplayRouteStateEvents(ctx context.Context) <-chan peripheral.DisplayRouteStateEvent {
+	return sink.routeMonitor.Listen(ctx)
+}
+
+func (sink *DisplaySink) Terminate(ctx context.Context) error {
+	sink.lifecycleCancel()
+
+	// frame being written or route state being shown would use the stopped controller
+	sink.framePump.Stop()
+	<-sink.routeMonitorDone
+
+	err := sink.controller.Stop(ctx)
+	if err != nil {
+		return fmt.Errorf("stop mpv controller: %w", err)
+	}
+
+	return nil
+}
+
+func (sink *DisplaySink) writeFrame(frame peripheral.DisplayFrame) error {
+	sink.currentDisplayModeLock.RLock()
+	currentDisplayMode := sink.currentDisplayMode
+	sink.currentDisplayModeLock.RUnlock()
+
+	if frame.DisplayMode != currentDisplayMode {
+		if err := sink.reconcileDisplayMode(currentDisplayMode, frame.DisplayMode); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(sink.lifecycleCtx, controlTimeout)
+	defer cancel()
+
+	err := sink.controller.WriteFrame(ctx, bytes.NewReader(frame.Data))
+	if err != nil {
+		return fmt.Errorf("write frame to mpv: %w", err)
+	}
+
+	return nil
+}
+
+// reconcileDisplayMode reloads mpv video when provider started to deliver frames in a different display mode, e.g.
+// when failover provider switches to a source with another resolution. The window stays open.
+func (sink *DisplaySink) reconcileDisplayMode(previousDisplayMode peripheralSDK.DisplayMode, displayMode peripheralSDK.DisplayMode) error {
+	if !sink.supportedDisplayModes.Supports(displayMode) {
+		return fmt.Errorf("%w: %s", ErrDisplayUnsupportedDisplayMode, displayMode.String())
+	}
+
+	sink.logger.Info("Provider display mode changed.",
+		slog.String("previousDisplayMode", previousDisplayMode.String()),
+		slog.String("displayMode", displayMode.String()),
+	)
+
+	if err := sink.loadDisplayMode(displayMode); err != nil {
+		return fmt.Errorf("reconfigure display mode: %w", err)
+	}
+
+	return nil
+}
+
+// loadDisplayMode plays raw video of display mode from a new pipe and hides the route status.
+func (sink *DisplaySink) loadDisplayMode(displayMode peripheralSDK.DisplayMode) error {
+	sink.currentDisplayModeLock.Lock()
+	sink.currentDisplayMode = displayMode
+	sink.currentDisplayModeLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(sink.lifecycleCtx, controlTimeout)
+	defer cancel()
+
+	if err := sink.controller.LoadRawVideo(ctx, displayMode); err != nil {
+		return fmt.Errorf("load raw video: %w", err)
+	}
+
+	return sink.setControllerStatus(ctx, "")
+}
+
+func (sink *DisplaySink) handleRouteStateEvents(events <-chan peripheral.DisplayRouteStateEvent) {
+	defer close(sink.routeMonitorDone)
+
+	for event := range events {
+		err := sink.handleRouteStateEvent(event)
+		if err != nil {
+			sink.logger.Warn("Failed to show display route state.", slog.String("error", err.Error()))
+		}
+	}
+}
+
+func (sink *DisplaySink) handleRouteStateEvent(event peripheral.DisplayRouteStateEvent) error {
+	ctx, cancel := context.WithTimeout(sink.lifecycleCtx, controlTimeout)
+	defer cancel()
+
+	switch event.State {
+	case peripheral.DisplayRouteStateIdle:
+		if err := sink.setControllerStatus(ctx, "[NO INPUT]"); err != nil {
+			return fmt.Errorf("set no input status: %w", err)
+		}
+	case peripheral.DisplayRouteStateLive:
+		if err := sink.setControllerStatus(ctx, ""); err != nil {
+			return fmt.Errorf("restore live status: %w", err)
+		}
+	case peripheral.DisplayRouteStateNoSignal:
+		if err := sink.setControllerStatus(ctx, "[NO SIGNAL] "+event.Reason); err != nil {
+			return fmt.Errorf("set no signal status: %w", err)
+		}
+	case peripheral.DisplayRouteStateSourceOffline:
+		if err := sink.setControllerStatus(ctx, "[SOURCE OFFLINE] "+event.Reason); err != nil {
+			return fmt.Errorf("set source offline status: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// setControllerStatus shows status as OSD message and in the window title, empty status means live video.
+func (sink *DisplaySink) setControllerStatus(ctx context.Context, status string) error {
+	sink.currentDisplayModeLock.RLock()
+	displayMode := sink.currentDisplayMode
+	sink.currentDisplayModeLock.RUnlock()
+
+	windowTitle := fmt.Sprintf("%s [%s]", sink.title, displayMode.String())
+	if status != "" {
+		windowTitle = fmt.Sprintf("%s %s [%s]", sink.title, status, displayMode.String())
+	}
+
+	if err := sink.controller.SetTitle(ctx, windowTitle); err != nil {
+		return fmt.Errorf("set window title: %w", err)
+	}
+
+	if err := sink.controller.SetMessage(ctx, status); err != nil {
+		return fmt.Errorf("set message: %w", err)
+	}
+
+	return nil
+}
+
+var (
+	ErrMissingSupportedDisplayMode   = errors.New("missing supported display modes")
+	ErrDisplayUnsupportedDisplayMode = errors.New("display mode is not supported")
+)
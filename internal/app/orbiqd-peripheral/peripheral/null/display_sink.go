@@ -0,0 +1,405 @@
+package null
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const DisplaySinkDriverKind = driverSDK.Kind("null-display-sink")
+
+var DisplaySinkDriver = driver.NewLocalDriver(DisplaySinkDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := DisplaySinkConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", DisplaySinkDriverKind.String()))
+
+	displaySink, err := NewDisplaySink(ctx, driverConfig, name, WithDisplaySinkLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return displaySink, nil
+})
+
+type DisplaySinkConfig struct {
+	// PullRate is the number of frame fetches per second, 0 fetches as fast as possible while the provider delivers
+	// frames. Twice the provider refresh rate is used when not set.
+	PullRate *int `json:"pullRate" validate:"omitempty,min=0"`
+	// ReadFrames copies pixels of every new frame, so the cost of transferring frame data is measured as well.
+	ReadFrames *bool `json:"readFrames"`
+}
+
+// unlimitedPullBackoff is the delay between fetches with unlimited pull rate while the provider has no frame or fails,
+// so the pump does not spin when the source is offline or has no signal.
+const unlimitedPullBackoff = 10 * time.Millisecond
+
+type DisplaySinkOptions struct {
+	logger *slog.Logger
+}
+
+type DisplaySinkOpt func(*DisplaySinkOptions)
+
+func defaultDisplaySinkOptions() DisplaySinkOptions {
+	return DisplaySinkOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithDisplaySinkLogger(logger *slog.Logger) DisplaySinkOpt {
+	return func(options *DisplaySinkOptions) {
+		options.logger = logger
+	}
+}
+
+// DisplaySink fetches frames from provider and discards them. It measures capacity of sources and routes without
+// cost of rendering or encoding: fetch latency histogram, frames and bytes per second, duplicate frames and errors.
+// Metrics are reset when a provider is set, so every route is measured separately.
+type DisplaySink struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+
+	framePumpTicker         *time.Ticker
+	frameBufferProvider     peripheralSDK.DisplayFrameBufferProvider
+	frameBufferProviderLock sync.RWMutex
+
+	pullRate   *int
+	readFrames bool
+
+	lastSequence  uint64
+	lastTimestamp time.Time
+
+	metrics         peripheralSDK.DisplaySinkMetrics
+	fetches         uint64
+	duplicateFrames uint64
+	notReady        uint64
+	metricsLock     sync.RWMutex
+	framesMeter     *utils.RateMeter
+	bytesMeter      *utils.RateMeter
+	fetchLatency    *utils.LatencyHistogram
+
+	logger *slog.Logger
+}
+
+var (
+	_ peripheralSDK.DisplaySink                = (*DisplaySink)(nil)
+	_ peripheralSDK.DisplaySinkMetricsProvider = (*DisplaySink)(nil)
+	_ peripheralSDK.DisplaySinkInfoProvider    = (*DisplaySink)(nil)
+)
+
+func NewDisplaySink(ctx context.Context, config DisplaySinkConfig, name peripheralSDK.Name, opts ...DisplaySinkOpt) (*DisplaySink, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	options := defaultDisplaySinkOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("null-display-sink")
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)))
+
+	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
+
+	displaySink := &DisplaySink{
+		id:   id,
+		name: name,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+
+		framePumpTicker:         time.NewTicker(time.Second),
+		frameBufferProviderLock: sync.RWMutex{},
+
+		pullRate:   config.PullRate,
+		readFrames: utils.DefaultNil(config.ReadFrames, true),
+
+		metricsLock:  sync.RWMutex{},
+		framesMeter:  utils.NewRateMeter(),
+		bytesMeter:   utils.NewRateMeter(),
+		fetchLatency: utils.NewLatencyHistogram(),
+
+		logger: logger,
+	}
+
+	go displaySink.framePump(lifecycleCtx)
+
+	displaySink.logger.Debug("The null display sink created.")
+
+	return displaySink, nil
+}
+
+func (sink *DisplaySink) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySinkCapability,
+	}
+}
+
+func (sink *DisplaySink) GetName() peripheralSDK.Name {
+	return sink.name
+}
+
+func (sink *DisplaySink) GetId() peripheralSDK.Id {
+	return sink.id
+}
+
+func (sink *DisplaySink) SetDisplayFrameBufferProvider(provider peripheralSDK.DisplayFrameBufferProvider) error {
+	providerDisplayMode, err := provider.GetDisplayMode(sink.lifecycleCtx)
+	if err != nil {
+		return fmt.Errorf("get display mode from provider: %w", err)
+	}
+
+	err = providerDisplayMode.Valid()
+	if err != nil {
+		return fmt.Errorf("invalid display mode: %w", err)
+	}
+
+	sink.resetMetrics()
+
+	sink.frameBufferProviderLock.Lock()
+	sink.frameBufferProvider = provider
+	sink.frameBufferProviderLock.Unlock()
+
+	pullRate := int(providerDisplayMode.RefreshRate * 2)
+	if sink.pullRate != nil {
+		pullRate = *sink.pullRate
+	}
+
+	if pullRate > 0 {
+		sink.framePumpTicker.Reset(time.Second / time.Duration(pullRate))
+	}
+
+	sink.logger.Info("Benchmark of provider started.",
+		slog.String("displayMode", providerDisplayMode.String()),
+		slog.Int("pullRate", pullRate),
+	)
+
+	return nil
+}
+
+func (sink *DisplaySink) ClearDisplayFrameBufferProvider() error {
+	sink.frameBufferProviderLock.Lock()
+	sink.frameBufferProvider = nil
+	sink.frameBufferProviderLock.Unlock()
+
+	return nil
+}
+
+func (sink *DisplaySink) Terminate(ctx context.Context) error {
+	sink.lifecycleCancel()
+
+	sink.framePumpTicker.Stop()
+
+	return nil
+}
+
+// GetDisplaySinkInfo returns info of the sink, any valid display mode is accepted. Current display mode is asked from
+// the attached provider, as the sink does not track it.
+func (sink *DisplaySink) GetDisplaySinkInfo(ctx context.Context) (*peripheralSDK.DisplaySinkInfo, error) {
+	info := &peripheralSDK.DisplaySinkInfo{
+		Manufacturer:   "OrbiqD",
+		Model:          "Null Sink",
+		SerialNumber:   sink.id.String(),
+		SupportedModes: peripheralSDK.DisplayModeList{},
+		PixelFormats:   []peripheralSDK.DisplayPixelFormat{peripheralSDK.DisplayPixelFormatRGB24},
+	}
+
+	sink.frameBufferProviderLock.RLock()
+	provider := sink.frameBufferProvider
+	sink.frameBufferProviderLock.RUnlock()
+
+	if provider != nil {
+		displayMode, err := provider.GetDisplayMode(ctx)
+		if err == nil {
+			info.CurrentMode = displayMode
+		}
+	}
+
+	return info, nil
+}
+
+func (sink *DisplaySink) GetDisplaySinkMetrics(ctx context.Context) (*peripheralSDK.DisplaySinkMetrics, error) {
+	sink.metricsLock.RLock()
+	metrics := sink.metrics
+	fetches := sink.fetches
+	duplicateFrames := sink.duplicateFrames
+	notReady := sink.notReady
+	sink.metricsLock.RUnlock()
+
+	metrics.FramesPerSecond = sink.framesMeter.Rate()
+	metrics.AdditionalMetrics = map[string]interface{}{
+		"bytesPerSecond":  sink.bytesMeter.Rate(),
+		"fetches":         fetches,
+		"duplicateFrames": duplicateFrames,
+		"notReady":        notReady,
+		"fetchLatency":    sink.fetchLatency.Snapshot(),
+	}
+
+	return &metrics, nil
+}
+
+func (sink *DisplaySink) framePump(ctx context.Context) {
+	ticker := sink.framePumpTicker.C
+	done := ctx.Done()
+
+	fetched := true
+
+	for {
+		switch {
+		case sink.isUnlimited() && fetched:
+			select {
+			case <-done:
+				return
+			default:
+			}
+		case sink.isUnlimited():
+			select {
+			case <-done:
+				return
+			case <-time.After(unlimitedPullBackoff):
+			}
+		default:
+			select {
+			case <-done:
+				return
+			case <-ticker:
+			}
+		}
+
+		var err error
+
+		fetched, err = sink.pullFrameFromProvider()
+		if err != nil {
+			sink.updateMetrics(func(metrics *peripheralSDK.DisplaySinkMetrics) {
+				metrics.Errors++
+			})
+			sink.logger.Debug("Failed to pull frame from provider.", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// isUnlimited reports whether frames are pulled without waiting for the ticker. It is false without provider, so
+// the pump does not spin while idle.
+func (sink *DisplaySink) isUnlimited() bool {
+	if sink.pullRate == nil || *sink.pullRate != 0 {
+		return false
+	}
+
+	sink.frameBufferProviderLock.RLock()
+	defer sink.frameBufferProviderLock.RUnlock()
+
+	return sink.frameBufferProvider != nil
+}
+
+// pullFrameFromProvider fetches frame and reports whether the provider delivered one, duplicate frames included.
+func (sink *DisplaySink) pullFrameFromProvider() (bool, error) {
+	sink.frameBufferProviderLock.RLock()
+	provider := sink.frameBufferProvider
+	sink.frameBufferProviderLock.RUnlock()
+
+	if provider == nil {
+		return false, nil
+	}
+
+	startedAt := time.Now()
+
+	frameBuffer, err := provider.GetDisplayFrameBuffer(sink.lifecycleCtx)
+	if errors.Is(err, peripheralSDK.ErrDisplayFrameBufferNotReady) {
+		sink.updateMetrics(func(metrics *peripheralSDK.DisplaySinkMetrics) {
+			sink.fetches++
+			sink.notReady++
+		})
+		return false, nil
+	}
+	if err != nil {
+		sink.updateMetrics(func(metrics *peripheralSDK.DisplaySinkMetrics) {
+			sink.fetches++
+		})
+		return false, fmt.Errorf("get frame buffer from provider: %w", err)
+	}
+
+	defer func() {
+		err = frameBuffer.Release()
+		if err != nil {
+			sink.logger.Warn("Failed to release frame buffer.", slog.String("error", err.Error()))
+		}
+	}()
+
+	sequence := frameBuffer.GetSequence()
+	timestamp := frameBuffer.GetTimestamp()
+
+	duplicate := sequence == sink.lastSequence && timestamp.Equal(sink.lastTimestamp)
+
+	if !duplicate && sink.readFrames {
+		if _, err := frameBuffer.WriteTo(io.Discard); err != nil {
+			return true, fmt.Errorf("read frame buffer: %w", err)
+		}
+	}
+
+	sink.fetchLatency.Observe(time.Since(startedAt))
+
+	sink.updateMetrics(func(metrics *peripheralSDK.DisplaySinkMetrics) {
+		sink.fetches++
+		if duplicate {
+			sink.duplicateFrames++
+		}
+	})
+
+	if duplicate {
+		return true, nil
+	}
+
+	sink.lastSequence = sequence
+	sink.lastTimestamp = timestamp
+
+	frameSize := uint64(frameBuffer.GetSize())
+
+	sink.framesMeter.Add(1)
+	sink.bytesMeter.Add(frameSize)
+	sink.updateMetrics(func(metrics *peripheralSDK.DisplaySinkMetrics) {
+		metrics.FramesReceived++
+		metrics.BytesReceived += frameSize
+	})
+
+	return true, nil
+}
+
+func (sink *DisplaySink) resetMetrics() {
+	sink.updateMetrics(func(metrics *peripheralSDK.DisplaySinkMetrics) {
+		*metrics = peripheralSDK.DisplaySinkMetrics{}
+		sink.fetches = 0
+		sink.duplicateFrames = 0
+		sink.notReady = 0
+	})
+
+	sink.fetchLatency.Reset()
+}
+
+func (sink *DisplaySink) updateMetrics(updateFn func(metrics *peripheralSDK.DisplaySinkMetrics)) {
+	sink.metricsLock.Lock()
+	defer sink.metricsLock.Unlock()
+
+	updateFn(&sink.metrics)
+}
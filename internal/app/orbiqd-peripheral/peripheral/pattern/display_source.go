@@ -0,0 +1,255 @@
+package pattern
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/pattern"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const DisplaySourceDriverKind = driverSDK.Kind("pattern-display-source")
+
+var DisplaySourceDriver = driver.NewLocalDriver(DisplaySourceDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := DisplaySourceConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", DisplaySourceDriverKind.String()))
+
+	displaySource, err := NewDisplaySource(ctx, driverConfig, name, WithDisplaySourceLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return displaySource, nil
+})
+
+type DisplaySourceConfig struct {
+	DisplayMode peripheralSDK.DisplayMode `json:"displayMode"`
+	Pattern     *string                   `json:"pattern" validate:"omitempty,oneof=smpte-bars gradient moving-boxes"`
+	Seed        *uint64                   `json:"seed"`
+	BoxCount    *int                      `json:"boxCount" validate:"omitempty,min=0,max=64"`
+	// MarkerCellSize sets size in pixels of a single cell of the frame counter and checksum block. Zero disables it.
+	MarkerCellSize *int `json:"markerCellSize" validate:"omitempty,min=0,max=64"`
+}
+
+type DisplaySourceOptions struct {
+	logger *slog.Logger
+}
+
+type DisplaySourceOpt func(*DisplaySourceOptions)
+
+func defaultDisplaySourceOptions() DisplaySourceOptions {
+	return DisplaySourceOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithDisplaySourceLogger(logger *slog.Logger) DisplaySourceOpt {
+	return func(options *DisplaySourceOptions) {
+		options.logger = logger
+	}
+}
+
+// DisplaySource generates deterministic test pattern frames without external processes. Frame number is derived
+// from time elapsed since creation and refresh rate, frame is rendered when requested for the first time.
+type DisplaySource struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	generator *pattern.Generator
+	startedAt time.Time
+
+	frameBuffer      *peripheralSDK.DisplayFrameBuffer
+	frameNumber      uint64
+	frameBufferLock  sync.Mutex
+	frameRenderCache []byte
+
+	displayMode peripheralSDK.DisplayMode
+	pixelFormat peripheralSDK.DisplayPixelFormat
+
+	metrics     peripheralSDK.DisplaySourceMetrics
+	metricsLock sync.RWMutex
+
+	logger *slog.Logger
+}
+
+var _ peripheralSDK.DisplaySource = (*DisplaySource)(nil)
+
+func NewDisplaySource(ctx context.Context, config DisplaySourceConfig, name peripheralSDK.Name, opts ...DisplaySourceOpt) (*DisplaySource, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	options := defaultDisplaySourceOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	generator, err := pattern.NewGenerator(pattern.Kind(utils.DefaultNil(config.Pattern, pattern.KindSMPTEBars.String())), config.DisplayMode,
+		pattern.WithGeneratorSeed(utils.DefaultNil(config.Seed, 0)),
+		pattern.WithGeneratorBoxCount(utils.DefaultNil(config.BoxCount, 4)),
+		pattern.WithGeneratorMarkerCellSize(utils.DefaultNil(config.MarkerCellSize, 8)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create pattern generator: %w", err)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("pattern-display-source")
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)))
+
+	source := &DisplaySource{
+		id:   id,
+		name: name,
+
+		generator: generator,
+		startedAt: time.Now(),
+
+		frameBufferLock: sync.Mutex{},
+
+		displayMode: config.DisplayMode,
+		pixelFormat: peripheralSDK.DisplayPixelFormatRGB24,
+
+		metricsLock: sync.RWMutex{},
+
+		logger: logger,
+	}
+
+	source.logger.Debug("The pattern display source created.", slog.String("displayMode", config.DisplayMode.String()))
+
+	return source, nil
+}
+
+func (source *DisplaySource) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySourceCapability,
+	}
+}
+
+func (source *DisplaySource) GetId() peripheralSDK.Id {
+	return source.id
+}
+
+func (source *DisplaySource) GetName() peripheralSDK.Name {
+	return source.name
+}
+
+func (source *DisplaySource) Terminate(ctx context.Context) error {
+	source.frameBufferLock.Lock()
+	defer source.frameBufferLock.Unlock()
+
+	if source.frameBuffer == nil {
+		return nil
+	}
+
+	err := source.frameBuffer.Release()
+	source.frameBuffer = nil
+	if err != nil {
+		return fmt.Errorf("release frame buffer: %w", err)
+	}
+
+	return nil
+}
+
+func (source *DisplaySource) GetDisplayMode(ctx context.Context) (*peripheralSDK.DisplayMode, error) {
+	return &source.displayMode, nil
+}
+
+func (source *DisplaySource) GetDisplayPixelFormat(ctx context.Context) (*peripheralSDK.DisplayPixelFormat, error) {
+	return &source.pixelFormat, nil
+}
+
+func (source *DisplaySource) GetDisplayFrameBuffer(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+	source.frameBufferLock.Lock()
+	defer source.frameBufferLock.Unlock()
+
+	frameInterval := time.Second / time.Duration(source.displayMode.RefreshRate)
+	frameNumber := uint64(time.Since(source.startedAt) / frameInterval)
+
+	if source.frameBuffer == nil || frameNumber != source.frameNumber {
+		if err := source.renderFrameBuffer(frameNumber, source.startedAt.Add(time.Duration(frameNumber)*frameInterval)); err != nil {
+			return nil, err
+		}
+	}
+
+	err := source.frameBuffer.Retain()
+	if err != nil {
+		return nil, fmt.Errorf("retain frame buffer: %w", err)
+	}
+
+	return source.frameBuffer, nil
+}
+
+// renderFrameBuffer renders frame into new memory buffer. Sequence equals frame number increased by one, as zero
+// sequence means that source does not track sequence.
+func (source *DisplaySource) renderFrameBuffer(frameNumber uint64, timestamp time.Time) error {
+	pixels, err := source.generator.Render(source.frameRenderCache, frameNumber)
+	if err != nil {
+		return fmt.Errorf("render frame %d: %w", frameNumber, err)
+	}
+	source.frameRenderCache = pixels
+
+	memoryPool, err := memory.DefaultMemoryPoolProvider()
+	if err != nil {
+		return fmt.Errorf("get memory pool provider: %w", err)
+	}
+
+	memoryBuffer, err := memoryPool.Borrow(len(pixels))
+	if err != nil {
+		return fmt.Errorf("borrow memory buffer: %w", err)
+	}
+
+	if _, err := memoryBuffer.Write(pixels); err != nil {
+		_ = memoryBuffer.Release()
+		return fmt.Errorf("write memory buffer: %w", err)
+	}
+
+	if source.frameBuffer != nil {
+		if err := source.frameBuffer.Release(); err != nil {
+			source.logger.Warn("Failed to release frame buffer.", slog.String("error", err.Error()))
+		}
+	}
+
+	source.frameBuffer = peripheralSDK.NewDisplayFrameBuffer(memoryBuffer,
+		peripheralSDK.WithDisplayFrameBufferSequence(frameNumber+1),
+		peripheralSDK.WithDisplayFrameBufferTimestamp(timestamp),
+	)
+	source.frameNumber = frameNumber
+
+	source.updateMetrics(func(metrics *peripheralSDK.DisplaySourceMetrics) {
+		metrics.FrameBufferSwaps++
+		metrics.FrameBufferWrittenBytes += uint64(len(pixels))
+	})
+
+	return nil
+}
+
+func (source *DisplaySource) GetDisplaySourceMetrics() peripheralSDK.DisplaySourceMetrics {
+	source.metricsLock.RLock()
+	defer source.metricsLock.RUnlock()
+
+	return source.metrics
+}
+
+func (source *DisplaySource) updateMetrics(updateFn func(metrics *peripheralSDK.DisplaySourceMetrics)) {
+	source.metricsLock.Lock()
+	defer source.metricsLock.Unlock()
+
+	updateFn(&source.metrics)
+}
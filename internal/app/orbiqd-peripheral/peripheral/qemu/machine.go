@@ -0,0 +1,682 @@
+package qemu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/go-homedir"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/qmp"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/ppm"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/hid"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	memorySDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const MachineDriverKind = driverSDK.Kind("qemu-qmp")
+
+var MachineDriver = driver.NewLocalDriver(MachineDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := MachineConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", MachineDriverKind.String()))
+
+	machine, err := NewMachine(ctx, driverConfig, name, WithMachineLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return machine, nil
+})
+
+type MachineConfig struct {
+	SocketPath string `json:"socketPath" validate:"required"`
+	// ScreendumpPath is the file QEMU writes screen dumps to, it must be reachable under the same path by both
+	// processes. Defaults to a file in temporary directory.
+	ScreendumpPath *string `json:"screendumpPath"`
+	// MaxFrameRate limits how often screen is dumped, it is also reported as display mode refresh rate.
+	MaxFrameRate *int `json:"maxFrameRate" validate:"omitempty,min=1,max=60"`
+	// KeyboardDevice and MouseDevice select QEMU input devices receiving events, default devices are used when unset.
+	KeyboardDevice *string `json:"keyboardDevice"`
+	MouseDevice    *string `json:"mouseDevice"`
+	ReconnectDelay *string `json:"reconnectDelay"`
+}
+
+type MachineOptions struct {
+	logger *slog.Logger
+}
+
+type MachineOpt func(*MachineOptions)
+
+func defaultMachineOptions() MachineOptions {
+	return MachineOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithMachineLogger(logger *slog.Logger) MachineOpt {
+	return func(options *MachineOptions) {
+		options.logger = logger
+	}
+}
+
+// Machine controls QEMU virtual machine over QMP socket. Screen dumps are served as display source, keyboard and mouse
+// events are injected with input-send-event and power of the machine is controlled with run state commands.
+// Connection is established in background and restored after failures.
+type Machine struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+	lifecycleDone   chan struct{}
+
+	socketPath          string
+	screendumpPath      string
+	screendumpTemporary bool
+	maxFrameRate        int
+	keyboardDevice      string
+	mouseDevice         string
+	reconnectDelay      time.Duration
+
+	connection     *qmp.Client
+	connectionLock sync.RWMutex
+
+	decodeBuffer     []byte
+	memoryBuffer     memorySDK.Buffer
+	frameSequence    uint64
+	frameTimestamp   time.Time
+	displayMode      peripheralSDK.DisplayMode
+	memoryBufferLock sync.RWMutex
+
+	pixelFormat peripheralSDK.DisplayPixelFormat
+
+	lastPowerEvent   string
+	lastPowerEventAt time.Time
+	powerEventLock   sync.RWMutex
+
+	metrics     peripheralSDK.DisplaySourceMetrics
+	metricsLock sync.RWMutex
+
+	logger *slog.Logger
+}
+
+var (
+	_ peripheralSDK.DisplaySource          = (*Machine)(nil)
+	_ peripheralSDK.KeyboardSink           = (*Machine)(nil)
+	_ peripheralSDK.MouseSink              = (*Machine)(nil)
+	_ peripheralSDK.MouseEventHandler      = (*Machine)(nil)
+	_ peripheralSDK.MachinePowerController = (*Machine)(nil)
+)
+
+// powerEvents lists QMP events which change run state of the machine.
+var powerEvents = map[string]struct{}{
+	"SHUTDOWN": {}, "POWERDOWN": {}, "RESET": {}, "STOP": {}, "RESUME": {}, "SUSPEND": {}, "SUSPEND_DISK": {},
+	"WAKEUP": {}, "GUEST_PANICKED": {},
+}
+
+func NewMachine(ctx context.Context, config MachineConfig, name peripheralSDK.Name, opts ...MachineOpt) (*Machine, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	options := defaultMachineOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	socketPath, err := homedir.Expand(config.SocketPath)
+	if err != nil {
+		return nil, fmt.Errorf("expand socket path: %w", err)
+	}
+
+	reconnectDelay, err := time.ParseDuration(utils.DefaultNil(config.ReconnectDelay, "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse reconnect delay: %w", err)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("qemu-qmp")
+
+	screendumpPath := filepath.Join(os.TempDir(), fmt.Sprintf("%s.ppm", id))
+	if config.ScreendumpPath != nil {
+		screendumpPath, err = homedir.Expand(*config.ScreendumpPath)
+		if err != nil {
+			return nil, fmt.Errorf("expand screendump path: %w", err)
+		}
+	}
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)), slog.String("socketPath", socketPath))
+
+	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
+
+	machine := &Machine{
+		id:   id,
+		name: name,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+		lifecycleDone:   make(chan struct{}),
+
+		socketPath:          socketPath,
+		screendumpPath:      screendumpPath,
+		screendumpTemporary: config.ScreendumpPath == nil,
+		maxFrameRate:        utils.DefaultNil(config.MaxFrameRate, 10),
+		keyboardDevice:      utils.DefaultNil(config.KeyboardDevice, ""),
+		mouseDevice:         utils.DefaultNil(config.MouseDevice, ""),
+		reconnectDelay:      reconnectDelay,
+
+		connectionLock: sync.RWMutex{},
+
+		memoryBufferLock: sync.RWMutex{},
+
+		pixelFormat: peripheralSDK.DisplayPixelFormatRGB24,
+
+		powerEventLock: sync.RWMutex{},
+
+		metricsLock: sync.RWMutex{},
+
+		logger: logger,
+	}
+
+	go machine.connectLoop(lifecycleCtx)
+
+	machine.logger.Debug("The qemu machine created.")
+
+	return machine, nil
+}
+
+func (machine *Machine) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySourceCapability,
+		peripheralSDK.KeyboardSinkCapability,
+		peripheralSDK.MouseSinkCapability,
+	}
+}
+
+func (machine *Machine) GetId() peripheralSDK.Id {
+	return machine.id
+}
+
+func (machine *Machine) GetName() peripheralSDK.Name {
+	return machine.name
+}
+
+func (machine *Machine) Terminate(ctx context.Context) error {
+	machine.lifecycleCancel()
+
+	select {
+	case <-machine.lifecycleDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if machine.screendumpTemporary {
+		if err := os.Remove(machine.screendumpPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			machine.logger.Warn("Failed to remove screendump file.", slog.String("error", err.Error()))
+		}
+	}
+
+	machine.memoryBufferLock.Lock()
+	defer machine.memoryBufferLock.Unlock()
+
+	if machine.memoryBuffer == nil {
+		return nil
+	}
+
+	err := machine.memoryBuffer.Release()
+	machine.memoryBuffer = nil
+	if err != nil {
+		return fmt.Errorf("release memory buffer: %w", err)
+	}
+
+	return nil
+}
+
+// GetDisplayMode returns size of the last screen dump.
+func (machine *Machine) GetDisplayMode(ctx context.Context) (*peripheralSDK.DisplayMode, error) {
+	machine.memoryBufferLock.RLock()
+	defer machine.memoryBufferLock.RUnlock()
+
+	if machine.memoryBuffer == nil {
+		return nil, peripheralSDK.ErrDisplayFrameBufferNotReady
+	}
+
+	displayMode := machine.displayMode
+
+	return &displayMode, nil
+}
+
+func (machine *Machine) GetDisplayPixelFormat(ctx context.Context) (*peripheralSDK.DisplayPixelFormat, error) {
+	return &machine.pixelFormat, nil
+}
+
+func (machine *Machine) GetDisplayFrameBuffer(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+	machine.memoryBufferLock.RLock()
+	defer machine.memoryBufferLock.RUnlock()
+
+	if machine.memoryBuffer == nil {
+		return nil, peripheralSDK.ErrDisplayFrameBufferNotReady
+	}
+
+	err := machine.memoryBuffer.Retain()
+	if err != nil {
+		return nil, fmt.Errorf("retain memory buffer: %w", err)
+	}
+
+	return peripheralSDK.NewDisplayFrameBuffer(machine.memoryBuffer,
+		peripheralSDK.WithDisplayFrameBufferSequence(machine.frameSequence),
+		peripheralSDK.WithDisplayFrameBufferTimestamp(machine.frameTimestamp),
+	), nil
+}
+
+func (machine *Machine) GetDisplaySourceMetrics() peripheralSDK.DisplaySourceMetrics {
+	machine.metricsLock.RLock()
+	defer machine.metricsLock.RUnlock()
+
+	metrics := machine.metrics
+	metrics.AdditionalMetrics = map[string]interface{}{
+		"connected": machine.getConnection() != nil,
+	}
+	for key, value := range machine.metrics.AdditionalMetrics {
+		metrics.AdditionalMetrics[key] = value
+	}
+
+	return metrics
+}
+
+func (machine *Machine) updateMetrics(updateFn func(metrics *peripheralSDK.DisplaySourceMetrics)) {
+	machine.metricsLock.Lock()
+	defer machine.metricsLock.Unlock()
+
+	if machine.metrics.AdditionalMetrics == nil {
+		machine.metrics.AdditionalMetrics = map[string]interface{}{}
+	}
+
+	updateFn(&machine.metrics)
+}
+
+func (machine *Machine) incrementMetric(name string) {
+	machine.updateMetrics(func(metrics *peripheralSDK.DisplaySourceMetrics) {
+		value, _ := metrics.AdditionalMetrics[name].(uint64)
+		metrics.AdditionalMetrics[name] = value + 1
+	})
+}
+
+func (machine *Machine) getConnection() *qmp.Client {
+	machine.connectionLock.RLock()
+	defer machine.connectionLock.RUnlock()
+
+	return machine.connection
+}
+
+func (machine *Machine) setConnection(connection *qmp.Client) {
+	machine.connectionLock.Lock()
+	defer machine.connectionLock.Unlock()
+
+	machine.connection = connection
+}
+
+// connectLoop keeps connection to QMP socket, failed or lost connection is retried after reconnect delay.
+func (machine *Machine) connectLoop(ctx context.Context) {
+	defer close(machine.lifecycleDone)
+
+	for {
+		err := machine.serveConnection(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		machine.logger.Warn("QMP connection failed, reconnecting.",
+			slog.String("error", err.Error()),
+			slog.Duration("reconnectDelay", machine.reconnectDelay),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(machine.reconnectDelay):
+		}
+	}
+}
+
+func (machine *Machine) serveConnection(ctx context.Context) error {
+	connection, err := qmp.Dial(ctx, machine.socketPath,
+		qmp.WithClientEventHandler(machine.handleEvent),
+		qmp.WithClientLogger(machine.logger),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		machine.setConnection(nil)
+		_ = connection.Close()
+	}()
+
+	machine.setConnection(connection)
+	machine.incrementMetric("connections")
+
+	machine.logger.Info("QMP connection established.", slog.String("qemuVersion", connection.GetVersion().String()))
+
+	ticker := time.NewTicker(time.Second / time.Duration(machine.maxFrameRate))
+	defer ticker.Stop()
+
+	for {
+		err := machine.captureFrame(ctx, connection)
+		switch {
+		case errors.Is(err, qmp.ErrClosed):
+			return err
+		case err != nil && ctx.Err() == nil:
+			machine.incrementMetric("screendumpErrors")
+			machine.logger.Debug("Failed to capture screen.", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-connection.Done():
+			return fmt.Errorf("%w: %w", qmp.ErrClosed, connection.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// captureFrame dumps screen to file, decodes it and swaps served memory buffer.
+func (machine *Machine) captureFrame(ctx context.Context, connection *qmp.Client) error {
+	if err := connection.Screendump(ctx, machine.screendumpPath); err != nil {
+		return fmt.Errorf("screendump: %w", err)
+	}
+
+	file, err := os.Open(machine.screendumpPath)
+	if err != nil {
+		return fmt.Errorf("open screendump file: %w", err)
+	}
+
+	image, err := ppm.Decode(machine.decodeBuffer, file)
+	_ = file.Close()
+	if err != nil {
+		return fmt.Errorf("decode screendump file: %w", err)
+	}
+	machine.decodeBuffer = image.Pixels
+
+	memoryPool, err := memory.DefaultMemoryPoolProvider()
+	if err != nil {
+		return fmt.Errorf("get memory pool provider: %w", err)
+	}
+
+	memoryBuffer, err := memoryPool.Borrow(len(image.Pixels))
+	if err != nil {
+		return fmt.Errorf("borrow memory buffer: %w", err)
+	}
+
+	if _, err := memoryBuffer.Write(image.Pixels); err != nil {
+		_ = memoryBuffer.Release()
+		return fmt.Errorf("write memory buffer: %w", err)
+	}
+
+	machine.memoryBufferLock.Lock()
+	previousMemoryBuffer := machine.memoryBuffer
+	machine.memoryBuffer = memoryBuffer
+	machine.frameSequence++
+	machine.frameTimestamp = time.Now()
+	machine.displayMode = peripheralSDK.DisplayMode{
+		Width:       image.Width,
+		Height:      image.Height,
+		RefreshRate: uint32(machine.maxFrameRate),
+	}
+	machine.memoryBufferLock.Unlock()
+
+	if previousMemoryBuffer != nil {
+		if err := previousMemoryBuffer.Release(); err != nil {
+			machine.logger.Warn("Failed to release memory buffer.", slog.String("error", err.Error()))
+		}
+	}
+
+	machine.updateMetrics(func(metrics *peripheralSDK.DisplaySourceMetrics) {
+		metrics.FrameBufferSwaps++
+		metrics.FrameBufferWrittenBytes += uint64(len(image.Pixels))
+	})
+
+	return nil
+}
+
+func (machine *Machine) handleEvent(event qmp.Event) {
+	if _, isPowerEvent := powerEvents[event.Name]; !isPowerEvent {
+		return
+	}
+
+	machine.powerEventLock.Lock()
+	machine.lastPowerEvent = event.Name
+	machine.lastPowerEventAt = event.Timestamp
+	machine.powerEventLock.Unlock()
+
+	machine.logger.Info("Machine power event received.", slog.String("event", event.Name))
+}
+
+// GetMachinePowerState returns QEMU run state mapped to power status along with the last observed power event.
+func (machine *Machine) GetMachinePowerState(ctx context.Context) (*peripheralSDK.MachinePowerState, error) {
+	connection := machine.getConnection()
+	if connection == nil {
+		return nil, ErrNotConnected
+	}
+
+	status, err := connection.QueryStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query status: %w", err)
+	}
+
+	machine.powerEventLock.RLock()
+	defer machine.powerEventLock.RUnlock()
+
+	return &peripheralSDK.MachinePowerState{
+		Status:      powerStatus(status),
+		Detail:      status.Status,
+		LastEvent:   machine.lastPowerEvent,
+		LastEventAt: machine.lastPowerEventAt,
+	}, nil
+}
+
+func powerStatus(status *qmp.Status) peripheralSDK.MachinePowerStatus {
+	switch {
+	case status.Running:
+		return peripheralSDK.MachinePowerStatusRunning
+	case status.Status == "shutdown":
+		return peripheralSDK.MachinePowerStatusOff
+	case status.Status == "suspended":
+		return peripheralSDK.MachinePowerStatusSuspended
+	default:
+		return peripheralSDK.MachinePowerStatusPaused
+	}
+}
+
+// HandleMachinePowerAction maps power actions to QMP commands. Shutdown presses ACPI power button, so the machine
+// stays off only when QEMU runs with -no-shutdown, otherwise QEMU exits and power-on is not possible.
+func (machine *Machine) HandleMachinePowerAction(ctx context.Context, action peripheralSDK.MachinePowerAction) error {
+	connection := machine.getConnection()
+	if connection == nil {
+		return ErrNotConnected
+	}
+
+	var commands []string
+
+	switch action {
+	case peripheralSDK.MachinePowerActionPowerOn:
+		status, err := connection.QueryStatus(ctx)
+		if err != nil {
+			return fmt.Errorf("query status: %w", err)
+		}
+
+		switch {
+		case status.Running:
+		case status.Status == "shutdown":
+			commands = []string{"system_reset", "cont"}
+		case status.Status == "suspended":
+			commands = []string{"system_wakeup"}
+		default:
+			commands = []string{"cont"}
+		}
+	case peripheralSDK.MachinePowerActionShutdown:
+		commands = []string{"system_powerdown"}
+	case peripheralSDK.MachinePowerActionReset:
+		commands = []string{"system_reset"}
+	case peripheralSDK.MachinePowerActionPause:
+		commands = []string{"stop"}
+	case peripheralSDK.MachinePowerActionResume:
+		commands = []string{"cont"}
+	default:
+		return fmt.Errorf("%w: %s", peripheralSDK.ErrMachinePowerActionUnsupported, action)
+	}
+
+	for _, command := range commands {
+		if err := connection.Execute(ctx, command, nil, nil); err != nil {
+			return fmt.Errorf("execute %s: %w", command, err)
+		}
+	}
+
+	machine.logger.Info("Machine power action applied.", slog.String("action", string(action)))
+
+	return nil
+}
+
+// HandleKeyboardDataEvent sends key as QKeyCode of the physical key, so the guest applies its own keyboard layout.
+func (machine *Machine) HandleKeyboardDataEvent(event peripheralSDK.KeyboardEvent) error {
+	keyEvent, isKeyEvent := event.(peripheralSDK.KeyboardKeyEvent)
+	if !isKeyEvent {
+		return fmt.Errorf("%w: %T", ErrEventUnsupported, event)
+	}
+
+	qcode, found := hid.QCodeFromUsage(keyEvent.HIDUsage)
+	if !found {
+		return fmt.Errorf("%w: hid usage %#x", ErrEventUnsupported, keyEvent.HIDUsage)
+	}
+
+	connection := machine.getConnection()
+	if connection == nil {
+		return ErrNotConnected
+	}
+
+	down := keyEvent.State != peripheralSDK.KeyboardKeyStateRelease
+
+	return connection.SendInputEvents(machine.lifecycleCtx, machine.keyboardDevice, qmp.NewKeyEvent(qcode, down))
+}
+
+// KeyboardControlChannel returns channel closed when ctx is done, QMP does not report keyboard state.
+func (machine *Machine) KeyboardControlChannel(ctx context.Context) <-chan peripheralSDK.KeyboardControlEvent {
+	controlChannel := make(chan peripheralSDK.KeyboardControlEvent)
+
+	go func() {
+		<-ctx.Done()
+		close(controlChannel)
+	}()
+
+	return controlChannel
+}
+
+// HandleMouseDataEvent injects pointer events. Absolute moves need an absolute pointing device in the guest, e.g.
+// usb-tablet. Wheel steps are sent as press and release of wheel buttons.
+func (machine *Machine) HandleMouseDataEvent(event peripheralSDK.MouseEvent) error {
+	var events []qmp.InputEvent
+
+	switch typedEvent := event.(type) {
+	case peripheralSDK.MouseMoveEvent:
+		if typedEvent.Absolute {
+			events = append(events,
+				qmp.NewAbsoluteMoveEvent(qmp.AxisX, scaleAxis(typedEvent.X)),
+				qmp.NewAbsoluteMoveEvent(qmp.AxisY, scaleAxis(typedEvent.Y)),
+			)
+		} else {
+			if typedEvent.DeltaX != 0 {
+				events = append(events, qmp.NewRelativeMoveEvent(qmp.AxisX, int(typedEvent.DeltaX)))
+			}
+			if typedEvent.DeltaY != 0 {
+				events = append(events, qmp.NewRelativeMoveEvent(qmp.AxisY, int(typedEvent.DeltaY)))
+			}
+		}
+
+	case peripheralSDK.MouseButtonEvent:
+		button, found := mouseButtons[typedEvent.Button]
+		if !found {
+			return fmt.Errorf("%w: mouse button %d", ErrEventUnsupported, typedEvent.Button)
+		}
+
+		events = append(events, qmp.NewButtonEvent(button, typedEvent.State == peripheralSDK.MouseButtonStatePress))
+
+	case peripheralSDK.MouseWheelEvent:
+		steps := []struct {
+			delta              int32
+			negative, positive string
+		}{
+			{typedEvent.DeltaY, qmp.ButtonWheelUp, qmp.ButtonWheelDown},
+			{typedEvent.DeltaX, qmp.ButtonWheelLeft, qmp.ButtonWheelRight},
+		}
+
+		for _, step := range steps {
+			button := step.positive
+			if step.delta < 0 {
+				button = step.negative
+			}
+
+			for range min(abs(step.delta), 32) {
+				events = append(events, qmp.NewButtonEvent(button, true), qmp.NewButtonEvent(button, false))
+			}
+		}
+
+	default:
+		return fmt.Errorf("%w: %T", ErrEventUnsupported, event)
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	connection := machine.getConnection()
+	if connection == nil {
+		return ErrNotConnected
+	}
+
+	return connection.SendInputEvents(machine.lifecycleCtx, machine.mouseDevice, events...)
+}
+
+var mouseButtons = map[peripheralSDK.MouseButton]string{
+	peripheralSDK.MouseButtonLeft:    qmp.ButtonLeft,
+	peripheralSDK.MouseButtonMiddle:  qmp.ButtonMiddle,
+	peripheralSDK.MouseButtonRight:   qmp.ButtonRight,
+	peripheralSDK.MouseButtonBack:    qmp.ButtonSide,
+	peripheralSDK.MouseButtonForward: qmp.ButtonExtra,
+}
+
+// scaleAxis converts normalized position into absolute axis value.
+func scaleAxis(position float64) int {
+	return int(math.Round(min(max(position, 0), 1) * qmp.AbsoluteAxisMax))
+}
+
+func abs(value int32) int32 {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+var (
+	ErrNotConnected     = errors.New("qmp not connected")
+	ErrEventUnsupported = errors.New("input event unsupported")
+)
@@ -0,0 +1,260 @@
+package qemu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type testRequest struct {
+	Execute   string          `json:"execute"`
+	Arguments json.RawMessage `json:"arguments"`
+	Id        uint64          `json:"id"`
+}
+
+type testServer struct {
+	socketPath string
+	requests   chan testRequest
+	events     chan map[string]any
+	status     string
+}
+
+// startTestServer serves fake QMP on unix socket. Screendump writes 2x1 PPM image with red and blue pixel, other
+// commands except query-status are sent to requests channel.
+func startTestServer(t *testing.T) *testServer {
+	memory.SetupTestDefaultMemoryPool(t)
+
+	server := &testServer{
+		socketPath: filepath.Join(t.TempDir(), "qmp.sock"),
+		requests:   make(chan testRequest, 64),
+		events:     make(chan map[string]any, 8),
+		status:     "running",
+	}
+
+	listener, err := net.Listen("unix", server.socketPath)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		connection, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = connection.Close()
+		}()
+
+		var writeLock sync.Mutex
+		encoder := json.NewEncoder(connection)
+		encode := func(value any) {
+			writeLock.Lock()
+			defer writeLock.Unlock()
+			_ = encoder.Encode(value)
+		}
+
+		encode(map[string]any{"QMP": map[string]any{
+			"version":      map[string]any{"qemu": map[string]any{"major": 9, "minor": 1, "micro": 0}, "package": ""},
+			"capabilities": []string{},
+		}})
+
+		go func() {
+			for event := range server.events {
+				encode(event)
+			}
+		}()
+
+		decoder := json.NewDecoder(connection)
+		for {
+			var request testRequest
+			if err := decoder.Decode(&request); err != nil {
+				return
+			}
+
+			var result any = map[string]any{}
+
+			switch request.Execute {
+			case "screendump":
+				var arguments struct {
+					Filename string `json:"filename"`
+				}
+				_ = json.Unmarshal(request.Arguments, &arguments)
+				_ = os.WriteFile(arguments.Filename, append([]byte("P6\n2 1\n255\n"), 255, 0, 0, 0, 0, 255), 0o600)
+			case "query-status":
+				result = map[string]any{"running": server.status == "running", "status": server.status}
+			case "qmp_capabilities":
+			default:
+				server.requests <- request
+			}
+
+			encode(map[string]any{"return": result, "id": request.Id})
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+		close(server.events)
+		<-done
+	})
+
+	return server
+}
+
+func (server *testServer) nextRequest(t *testing.T) testRequest {
+	select {
+	case request := <-server.requests:
+		return request
+	case <-time.After(5 * time.Second):
+		t.Fatal("request not received")
+		return testRequest{}
+	}
+}
+
+func startTestMachine(t *testing.T, server *testServer) *Machine {
+	maxFrameRate := 60
+	screendumpPath := filepath.Join(t.TempDir(), "screen.ppm")
+
+	machine, err := NewMachine(t.Context(), MachineConfig{
+		SocketPath:     server.socketPath,
+		ScreendumpPath: &screendumpPath,
+		MaxFrameRate:   &maxFrameRate,
+	}, "test-vm")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		assert.NoError(t, machine.Terminate(context.Background()))
+	})
+
+	return machine
+}
+
+func TestMachineDisplaySource(t *testing.T) {
+	server := startTestServer(t)
+	machine := startTestMachine(t, server)
+
+	var frameBuffer *peripheralSDK.DisplayFrameBuffer
+	assert.Eventually(t, func() bool {
+		var err error
+		frameBuffer, err = machine.GetDisplayFrameBuffer(t.Context())
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	if !assert.NotNil(t, frameBuffer) {
+		t.FailNow()
+	}
+
+	pixels := bytes.Buffer{}
+	_, err := frameBuffer.WriteTo(&pixels)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{255, 0, 0, 0, 0, 255}, pixels.Bytes())
+	assert.NoError(t, frameBuffer.Release())
+
+	displayMode, err := machine.GetDisplayMode(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, peripheralSDK.DisplayMode{Width: 2, Height: 1, RefreshRate: 60}, *displayMode)
+}
+
+func TestMachineInput(t *testing.T) {
+	server := startTestServer(t)
+	machine := startTestMachine(t, server)
+
+	assert.Eventually(t, func() bool {
+		return machine.getConnection() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, machine.HandleKeyboardDataEvent(peripheralSDK.NewKeyboardKeyEvent(
+		0xe1, "", peripheralSDK.KeyboardLogicalKey{}, peripheralSDK.KeyboardModifierShift, peripheralSDK.KeyboardKeyStatePress, "", "test", time.Now(),
+	)))
+
+	request := server.nextRequest(t)
+	assert.Equal(t, "input-send-event", request.Execute)
+	assert.JSONEq(t, `{"events":[{"type":"key","data":{"down":true,"key":{"type":"qcode","data":"shift"}}}]}`, string(request.Arguments))
+
+	assert.NoError(t, machine.HandleMouseDataEvent(peripheralSDK.NewMouseAbsoluteMoveEvent(0.5, 1, "test", time.Now())))
+
+	request = server.nextRequest(t)
+	assert.JSONEq(t, `{"events":[{"type":"abs","data":{"axis":"x","value":16384}},{"type":"abs","data":{"axis":"y","value":32767}}]}`, string(request.Arguments))
+
+	assert.NoError(t, machine.HandleMouseDataEvent(peripheralSDK.NewMouseWheelEvent(0, -1, "test", time.Now())))
+
+	request = server.nextRequest(t)
+	assert.JSONEq(t, `{"events":[{"type":"btn","data":{"down":true,"button":"wheel-up"}},{"type":"btn","data":{"down":false,"button":"wheel-up"}}]}`, string(request.Arguments))
+
+	err := machine.HandleMouseDataEvent(peripheralSDK.NewMouseButtonEvent(peripheralSDK.MouseButtonUnknown, peripheralSDK.MouseButtonStatePress, "test", time.Now()))
+	assert.ErrorIs(t, err, ErrEventUnsupported)
+}
+
+func TestMachinePower(t *testing.T) {
+	server := startTestServer(t)
+	server.status = "shutdown"
+	machine := startTestMachine(t, server)
+
+	assert.Eventually(t, func() bool {
+		return machine.getConnection() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	server.events <- map[string]any{
+		"event":     "SHUTDOWN",
+		"data":      map[string]any{"guest": true, "reason": "guest-shutdown"},
+		"timestamp": map[string]any{"seconds": 1700000000, "microseconds": 0},
+	}
+
+	assert.Eventually(t, func() bool {
+		state, err := machine.GetMachinePowerState(t.Context())
+		return err == nil && state.LastEvent == "SHUTDOWN"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	state, err := machine.GetMachinePowerState(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, peripheralSDK.MachinePowerStatusOff, state.Status)
+	assert.Equal(t, "shutdown", state.Detail)
+	assert.Equal(t, time.Unix(1700000000, 0), state.LastEventAt)
+
+	assert.NoError(t, machine.HandleMachinePowerAction(t.Context(), peripheralSDK.MachinePowerActionPowerOn))
+	assert.Equal(t, "system_reset", server.nextRequest(t).Execute)
+	assert.Equal(t, "cont", server.nextRequest(t).Execute)
+
+	assert.NoError(t, machine.HandleMachinePowerAction(t.Context(), peripheralSDK.MachinePowerActionShutdown))
+	assert.Equal(t, "system_powerdown", server.nextRequest(t).Execute)
+
+	err = machine.HandleMachinePowerAction(t.Context(), "hibernate")
+	assert.ErrorIs(t, err, peripheralSDK.ErrMachinePowerActionUnsupported)
+}
+
+func TestMachineNotConnected(t *testing.T) {
+	reconnectDelay := "10ms"
+	machine, err := NewMachine(t.Context(), MachineConfig{
+		SocketPath:     filepath.Join(t.TempDir(), fmt.Sprintf("missing-%d.sock", time.Now().UnixNano())),
+		ReconnectDelay: &reconnectDelay,
+	}, "test-vm")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		assert.NoError(t, machine.Terminate(context.Background()))
+	}()
+
+	_, err = machine.GetDisplayFrameBuffer(t.Context())
+	assert.ErrorIs(t, err, peripheralSDK.ErrDisplayFrameBufferNotReady)
+
+	_, err = machine.GetMachinePowerState(t.Context())
+	assert.ErrorIs(t, err, ErrNotConnected)
+
+	err = machine.HandleMouseDataEvent(peripheralSDK.NewMouseRelativeMoveEvent(1, 0, "test", time.Now()))
+	assert.ErrorIs(t, err, ErrNotConnected)
+}
@@ -0,0 +1,232 @@
+package recording
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/go-homedir"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/peripheral"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/recording"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const DisplaySinkDriverKind = driverSDK.Kind("file-recorder-display-sink")
+
+var DisplaySinkDriver = driver.NewLocalDriver(DisplaySinkDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := DisplaySinkConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", DisplaySinkDriverKind.String()))
+
+	displaySink, err := NewDisplaySink(ctx, driverConfig, name, WithDisplaySinkLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return displaySink, nil
+})
+
+type DisplaySinkConfig struct {
+	Directory       string  `json:"directory" validate:"required"`
+	Codec           *string `json:"codec" validate:"omitempty,oneof=raw qoi"`
+	SegmentDuration *string `json:"segmentDuration"`
+	SegmentMaxSize  *int64  `json:"segmentMaxSize"`
+	MaxDuration     *string `json:"maxDuration"`
+	MaxSize         *int64  `json:"maxSize"`
+}
+
+type DisplaySinkOptions struct {
+	logger *slog.Logger
+}
+
+type DisplaySinkOpt func(*DisplaySinkOptions)
+
+func defaultDisplaySinkOptions() DisplaySinkOptions {
+	return DisplaySinkOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithDisplaySinkLogger(logger *slog.Logger) DisplaySinkOpt {
+	return func(options *DisplaySinkOptions) {
+		options.logger = logger
+	}
+}
+
+// DisplaySink records frames received from provider into segmented on-disk recording. Only frames with new
+// sequence (or timestamp) are stored, so the recording keeps original timing of the source.
+type DisplaySink struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+
+	framePump *peripheral.DisplayFramePump
+
+	recorder     *recording.Recorder
+	recorderLock sync.Mutex
+
+	logger *slog.Logger
+}
+
+var (
+	_ peripheralSDK.DisplaySink             = (*DisplaySink)(nil)
+	_ peripheralSDK.DisplaySinkInfoProvider = (*DisplaySink)(nil)
+)
+
+func NewDisplaySink(ctx context.Context, config DisplaySinkConfig, name peripheralSDK.Name, opts ...DisplaySinkOpt) (*DisplaySink, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	directory, err := homedir.Expand(config.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("expand directory: %w", err)
+	}
+
+	segmentDuration, err := time.ParseDuration(utils.DefaultNil(config.SegmentDuration, "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("parse segment duration: %w", err)
+	}
+
+	maxDuration, err := time.ParseDuration(utils.DefaultNil(config.MaxDuration, "0s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse max duration: %w", err)
+	}
+
+	options := defaultDisplaySinkOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("file-recorder-display-sink")
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)))
+
+	recorder, err := recording.NewRecorder(directory, recording.Codec(utils.DefaultNil(config.Codec, "qoi")),
+		recording.WithRecorderSegmentMaxDuration(segmentDuration),
+		recording.WithRecorderSegmentMaxSize(utils.DefaultNil(config.SegmentMaxSize, 512*1024*1024)),
+		recording.WithRecorderMaxDuration(maxDuration),
+		recording.WithRecorderMaxSize(utils.DefaultNil(config.MaxSize, 0)),
+		recording.WithRecorderLogger(logger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create recorder: %w", err)
+	}
+
+	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
+
+	displaySink := &DisplaySink{
+		id:   id,
+		name: name,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+
+		recorder:     recorder,
+		recorderLock: sync.Mutex{},
+
+		logger: logger,
+	}
+
+	displaySink.framePump = peripheral.NewDisplayFramePump(lifecycleCtx, displaySink.recordFrame,
+		peripheral.WithDisplayFramePumpErrorHandler(func(err error) {
+			displaySink.logger.Warn("Failed to record frame from provider.", slog.String("error", err.Error()))
+		}),
+		peripheral.WithDisplayFramePumpLogger(logger),
+	)
+
+	displaySink.logger.Debug("The file recorder display sink created.", slog.String("directory", directory))
+
+	return displaySink, nil
+}
+
+func (sink *DisplaySink) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySinkCapability,
+	}
+}
+
+func (sink *DisplaySink) GetName() peripheralSDK.Name {
+	return sink.name
+}
+
+func (sink *DisplaySink) GetId() peripheralSDK.Id {
+	return sink.id
+}
+
+// GetDisplaySinkInfo returns info with current display mode of the attached provider. Any valid display mode is
+// accepted.
+func (sink *DisplaySink) GetDisplaySinkInfo(ctx context.Context) (*peripheralSDK.DisplaySinkInfo, error) {
+	return &peripheralSDK.DisplaySinkInfo{
+		Manufacturer:   "OrbiqD",
+		Model:          "Frame Recorder",
+		SerialNumber:   sink.id.String(),
+		SupportedModes: peripheralSDK.DisplayModeList{},
+		PixelFormats:   []peripheralSDK.DisplayPixelFormat{peripheralSDK.DisplayPixelFormatRGB24},
+		CurrentMode:    sink.framePump.GetDisplayMode(),
+	}, nil
+}
+
+func (sink *DisplaySink) SetDisplayFrameBufferProvider(provider peripheralSDK.DisplayFrameBufferProvider) error {
+	_, err := sink.framePump.Attach(provider)
+
+	return err
+}
+
+func (sink *DisplaySink) ClearDisplayFrameBufferProvider() error {
+	sink.framePump.Detach()
+
+	sink.recorderLock.Lock()
+	defer sink.recorderLock.Unlock()
+
+	err := sink.recorder.FinishSegment()
+	if err != nil {
+		return fmt.Errorf("finish segment: %w", err)
+	}
+
+	return nil
+}
+
+func (sink *DisplaySink) Terminate(ctx context.Context) error {
+	sink.lifecycleCancel()
+
+	// frame being recorded would open a new segment after the recorder is closed
+	sink.framePump.Stop()
+
+	sink.recorderLock.Lock()
+	defer sink.recorderLock.Unlock()
+
+	err := sink.recorder.Close()
+	if err != nil {
+		return fmt.Errorf("close recorder: %w", err)
+	}
+
+	return nil
+}
+
+func (sink *DisplaySink) recordFrame(frame peripheral.DisplayFrame) error {
+	sink.recorderLock.Lock()
+	defer sink.recorderLock.Unlock()
+
+	if err := sink.recorder.WriteFrame(frame.DisplayMode, frame.Sequence, frame.Timestamp, frame.Data); err != nil {
+		return fmt.Errorf("write frame: %w", err)
+	}
+
+	return nil
+}
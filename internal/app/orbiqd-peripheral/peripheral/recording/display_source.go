@@ -0,0 +1,346 @@
+package recording
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/go-homedir"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/recording"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const DisplaySourceDriverKind = driverSDK.Kind("recording-playback-display-source")
+
+var DisplaySourceDriver = driver.NewLocalDriver(DisplaySourceDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := DisplaySourceConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", DisplaySourceDriverKind.String()))
+
+	displaySource, err := NewDisplaySource(ctx, driverConfig, name, WithDisplaySourceLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return displaySource, nil
+})
+
+const (
+	DisplaySourceFormatSegments = "segments"
+	DisplaySourceFormatImages   = "images"
+)
+
+type DisplaySourceConfig struct {
+	Directory string `json:"directory" validate:"required"`
+	// Format selects directory layout: recorder segments or PNG/JPEG/PPM images played in file name order.
+	Format    *string  `json:"format" validate:"omitempty,oneof=segments images"`
+	FrameRate *uint32  `json:"frameRate" validate:"omitempty,min=1,max=240"`
+	Speed     *float64 `json:"speed" validate:"omitempty,gt=0"`
+	Loop      *bool    `json:"loop"`
+	Paused    *bool    `json:"paused"`
+}
+
+type DisplaySourceOptions struct {
+	logger *slog.Logger
+}
+
+type DisplaySourceOpt func(*DisplaySourceOptions)
+
+func defaultDisplaySourceOptions() DisplaySourceOptions {
+	return DisplaySourceOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithDisplaySourceLogger(logger *slog.Logger) DisplaySourceOpt {
+	return func(options *DisplaySourceOptions) {
+		options.logger = logger
+	}
+}
+
+// DisplaySource replays frames stored by file recorder display sink, or a directory of images, with their original
+// timing. Frame for the current playback position is loaded when the frame buffer is requested.
+type DisplaySource struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	timeline *recording.Timeline
+	player   *recording.Player
+
+	frameBuffer         *peripheralSDK.DisplayFrameBuffer
+	frameBufferIndex    int
+	frameBufferSequence uint64
+	frameBufferLock     sync.Mutex
+	frameReadBuffer     []byte
+
+	pixelFormat peripheralSDK.DisplayPixelFormat
+
+	metrics     peripheralSDK.DisplaySourceMetrics
+	metricsLock sync.RWMutex
+
+	logger *slog.Logger
+}
+
+var (
+	_ peripheralSDK.DisplaySource             = (*DisplaySource)(nil)
+	_ peripheralSDK.DisplayPlaybackController = (*DisplaySource)(nil)
+)
+
+func NewDisplaySource(ctx context.Context, config DisplaySourceConfig, name peripheralSDK.Name, opts ...DisplaySourceOpt) (*DisplaySource, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	directory, err := homedir.Expand(config.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("expand directory: %w", err)
+	}
+
+	options := defaultDisplaySourceOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("recording-playback-display-source")
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)))
+
+	var timeline *recording.Timeline
+
+	switch utils.DefaultNil(config.Format, DisplaySourceFormatSegments) {
+	case DisplaySourceFormatImages:
+		frameRate := utils.DefaultNil(config.FrameRate, 30)
+		timeline, err = recording.OpenImageTimeline(directory, time.Second/time.Duration(frameRate))
+	default:
+		timeline, err = recording.OpenSegmentTimeline(directory)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open timeline: %w", err)
+	}
+
+	player, err := recording.NewPlayer(timeline,
+		recording.WithPlayerSpeed(utils.DefaultNil(config.Speed, 1)),
+		recording.WithPlayerLoop(utils.DefaultNil(config.Loop, false)),
+		recording.WithPlayerPaused(utils.DefaultNil(config.Paused, false)),
+	)
+	if err != nil {
+		_ = timeline.Close()
+		return nil, fmt.Errorf("create player: %w", err)
+	}
+
+	displaySource := &DisplaySource{
+		id:   id,
+		name: name,
+
+		timeline: timeline,
+		player:   player,
+
+		frameBufferIndex: -1,
+		frameBufferLock:  sync.Mutex{},
+
+		pixelFormat: peripheralSDK.DisplayPixelFormatRGB24,
+
+		metricsLock: sync.RWMutex{},
+
+		logger: logger,
+	}
+
+	displaySource.logger.Debug("The recording playback display source created.",
+		slog.String("directory", directory),
+		slog.Int("frameCount", timeline.GetFrameCount()),
+		slog.Duration("duration", timeline.GetDuration()),
+	)
+
+	return displaySource, nil
+}
+
+func (source *DisplaySource) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySourceCapability,
+	}
+}
+
+func (source *DisplaySource) GetId() peripheralSDK.Id {
+	return source.id
+}
+
+func (source *DisplaySource) GetName() peripheralSDK.Name {
+	return source.name
+}
+
+func (source *DisplaySource) Terminate(ctx context.Context) error {
+	source.frameBufferLock.Lock()
+	defer source.frameBufferLock.Unlock()
+
+	if source.frameBuffer != nil {
+		if err := source.frameBuffer.Release(); err != nil {
+			source.logger.Warn("Failed to release frame buffer.", slog.String("error", err.Error()))
+		}
+		source.frameBuffer = nil
+	}
+
+	err := source.timeline.Close()
+	if err != nil {
+		return fmt.Errorf("close timeline: %w", err)
+	}
+
+	return nil
+}
+
+// GetDisplayMode returns display mode of the frame at the current playback position.
+func (source *DisplaySource) GetDisplayMode(ctx context.Context) (*peripheralSDK.DisplayMode, error) {
+	frame, err := source.timeline.GetFrame(source.player.GetFrameIndex())
+	if err != nil {
+		return nil, fmt.Errorf("get timeline frame: %w", err)
+	}
+
+	return &frame.DisplayMode, nil
+}
+
+func (source *DisplaySource) GetDisplayPixelFormat(ctx context.Context) (*peripheralSDK.DisplayPixelFormat, error) {
+	return &source.pixelFormat, nil
+}
+
+func (source *DisplaySource) GetDisplayFrameBuffer(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+	source.frameBufferLock.Lock()
+	defer source.frameBufferLock.Unlock()
+
+	frameIndex := source.player.GetFrameIndex()
+	if frameIndex != source.frameBufferIndex || source.frameBuffer == nil {
+		if err := source.loadFrameBuffer(frameIndex); err != nil {
+			return nil, err
+		}
+	}
+
+	err := source.frameBuffer.Retain()
+	if err != nil {
+		return nil, fmt.Errorf("retain frame buffer: %w", err)
+	}
+
+	return source.frameBuffer, nil
+}
+
+// loadFrameBuffer replaces current frame buffer with frame read from the timeline. Each loaded frame gets new
+// sequence number, so looped playback is not treated as stale by sinks.
+func (source *DisplaySource) loadFrameBuffer(frameIndex int) error {
+	frame, err := source.timeline.GetFrame(frameIndex)
+	if err != nil {
+		return fmt.Errorf("get timeline frame: %w", err)
+	}
+
+	source.frameReadBuffer, err = source.timeline.ReadFrame(source.frameReadBuffer, frameIndex)
+	if err != nil {
+		return fmt.Errorf("read frame %d: %w", frameIndex, err)
+	}
+
+	memoryPool, err := memory.DefaultMemoryPoolProvider()
+	if err != nil {
+		return fmt.Errorf("get memory pool provider: %w", err)
+	}
+
+	memoryBuffer, err := memoryPool.Borrow(len(source.frameReadBuffer))
+	if err != nil {
+		return fmt.Errorf("borrow memory buffer: %w", err)
+	}
+
+	if _, err := memoryBuffer.Write(source.frameReadBuffer); err != nil {
+		_ = memoryBuffer.Release()
+		return fmt.Errorf("write memory buffer: %w", err)
+	}
+
+	source.frameBufferSequence++
+
+	frameBuffer := peripheralSDK.NewDisplayFrameBuffer(memoryBuffer,
+		peripheralSDK.WithDisplayFrameBufferSequence(source.frameBufferSequence),
+		peripheralSDK.WithDisplayFrameBufferTimestamp(frame.Timestamp),
+	)
+
+	if source.frameBuffer != nil {
+		if err := source.frameBuffer.Release(); err != nil {
+			source.logger.Warn("Failed to release frame buffer.", slog.String("error", err.Error()))
+		}
+	}
+
+	source.frameBuffer = frameBuffer
+	source.frameBufferIndex = frameIndex
+
+	source.updateMetrics(func(metrics *peripheralSDK.DisplaySourceMetrics) {
+		metrics.FrameBufferSwaps++
+		metrics.FrameBufferWrittenBytes += uint64(len(source.frameReadBuffer))
+	})
+
+	return nil
+}
+
+func (source *DisplaySource) GetDisplaySourceMetrics() peripheralSDK.DisplaySourceMetrics {
+	source.metricsLock.RLock()
+	defer source.metricsLock.RUnlock()
+
+	return source.metrics
+}
+
+func (source *DisplaySource) updateMetrics(updateFn func(metrics *peripheralSDK.DisplaySourceMetrics)) {
+	source.metricsLock.Lock()
+	defer source.metricsLock.Unlock()
+
+	updateFn(&source.metrics)
+}
+
+func (source *DisplaySource) GetDisplayPlaybackState(ctx context.Context) (*peripheralSDK.DisplayPlaybackState, error) {
+	state := source.player.GetState()
+
+	return &state, nil
+}
+
+func (source *DisplaySource) SeekDisplayPlayback(ctx context.Context, position time.Duration) error {
+	err := source.player.Seek(position)
+	if err != nil {
+		return err
+	}
+
+	source.logger.Info("Display playback position changed.", slog.Duration("position", position))
+
+	return nil
+}
+
+func (source *DisplaySource) SetDisplayPlaybackSpeed(ctx context.Context, speed float64) error {
+	err := source.player.SetSpeed(speed)
+	if err != nil {
+		return err
+	}
+
+	source.logger.Info("Display playback speed changed.", slog.Float64("speed", speed))
+
+	return nil
+}
+
+func (source *DisplaySource) SetDisplayPlaybackLoop(ctx context.Context, loop bool) error {
+	source.player.SetLoop(loop)
+
+	source.logger.Info("Display playback loop changed.", slog.Bool("loop", loop))
+
+	return nil
+}
+
+func (source *DisplaySource) SetDisplayPlaybackPaused(ctx context.Context, paused bool) error {
+	source.player.SetPaused(paused)
+
+	source.logger.Info("Display playback pause changed.", slog.Bool("paused", paused))
+
+	return nil
+}
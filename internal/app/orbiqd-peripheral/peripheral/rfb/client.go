@@ -0,0 +1,586 @@
+package rfb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/rfb"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/hid"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const ClientDriverKind = driverSDK.Kind("rfb-client")
+
+var ClientDriver = driver.NewLocalDriver(ClientDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := ClientConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", ClientDriverKind.String()))
+
+	client, err := NewClient(ctx, driverConfig, name, WithClientLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return client, nil
+})
+
+type ClientConfig struct {
+	Address      string  `json:"address" validate:"required,hostname_port"`
+	Password     *string `json:"password"`
+	PasswordFile *string `json:"passwordFile"`
+	// MaxFrameRate limits how often framebuffer updates are requested, it is also reported as display mode refresh rate.
+	MaxFrameRate   *int    `json:"maxFrameRate" validate:"omitempty,min=1,max=120"`
+	ReconnectDelay *string `json:"reconnectDelay"`
+}
+
+type ClientOptions struct {
+	logger *slog.Logger
+}
+
+type ClientOpt func(*ClientOptions)
+
+func defaultClientOptions() ClientOptions {
+	return ClientOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithClientLogger(logger *slog.Logger) ClientOpt {
+	return func(options *ClientOptions) {
+		options.logger = logger
+	}
+}
+
+// Client connects to remote VNC server, like virtual machine or BMC console, and exposes it as display source with
+// keyboard and mouse sinks. Connection is established in background and restored after failures, frames are served
+// from the last applied framebuffer update.
+type Client struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+	lifecycleDone   chan struct{}
+
+	address        string
+	password       string
+	maxFrameRate   int
+	reconnectDelay time.Duration
+
+	connection     *rfb.Client
+	connectionLock sync.RWMutex
+
+	frameBuffer     *peripheralSDK.DisplayFrameBuffer
+	frameSequence   uint64
+	displayMode     peripheralSDK.DisplayMode
+	frameBufferLock sync.RWMutex
+
+	pixelFormat peripheralSDK.DisplayPixelFormat
+
+	buttonMask  uint8
+	pointerX    uint16
+	pointerY    uint16
+	pressedKeys map[peripheralSDK.KeyboardHIDUsage]hid.Keysym
+	inputLock   sync.Mutex
+
+	metrics     peripheralSDK.DisplaySourceMetrics
+	metricsLock sync.RWMutex
+
+	logger *slog.Logger
+}
+
+var (
+	_ peripheralSDK.DisplaySource     = (*Client)(nil)
+	_ peripheralSDK.KeyboardSink      = (*Client)(nil)
+	_ peripheralSDK.MouseSink         = (*Client)(nil)
+	_ peripheralSDK.MouseEventHandler = (*Client)(nil)
+)
+
+func NewClient(ctx context.Context, config ClientConfig, name peripheralSDK.Name, opts ...ClientOpt) (*Client, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	options := defaultClientOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	password := utils.DefaultNil(config.Password, "")
+	if config.PasswordFile != nil {
+		password, err = rfb.ReadPasswordFile(*config.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reconnectDelay, err := time.ParseDuration(utils.DefaultNil(config.ReconnectDelay, "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse reconnect delay: %w", err)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("rfb-client")
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)), slog.String("address", config.Address))
+
+	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
+
+	client := &Client{
+		id:   id,
+		name: name,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+		lifecycleDone:   make(chan struct{}),
+
+		address:        config.Address,
+		password:       password,
+		maxFrameRate:   utils.DefaultNil(config.MaxFrameRate, 30),
+		reconnectDelay: reconnectDelay,
+
+		connectionLock: sync.RWMutex{},
+
+		frameBufferLock: sync.RWMutex{},
+
+		pixelFormat: peripheralSDK.DisplayPixelFormatRGB24,
+
+		pressedKeys: make(map[peripheralSDK.KeyboardHIDUsage]hid.Keysym),
+		inputLock:   sync.Mutex{},
+
+		metricsLock: sync.RWMutex{},
+
+		logger: logger,
+	}
+
+	go client.connectLoop(lifecycleCtx)
+
+	client.logger.Debug("The rfb client created.")
+
+	return client, nil
+}
+
+func (client *Client) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySourceCapability,
+		peripheralSDK.KeyboardSinkCapability,
+		peripheralSDK.MouseSinkCapability,
+	}
+}
+
+func (client *Client) GetId() peripheralSDK.Id {
+	return client.id
+}
+
+func (client *Client) GetName() peripheralSDK.Name {
+	return client.name
+}
+
+func (client *Client) Terminate(ctx context.Context) error {
+	client.lifecycleCancel()
+
+	select {
+	case <-client.lifecycleDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	client.frameBufferLock.Lock()
+	defer client.frameBufferLock.Unlock()
+
+	if client.frameBuffer == nil {
+		return nil
+	}
+
+	err := client.frameBuffer.Release()
+	client.frameBuffer = nil
+	if err != nil {
+		return fmt.Errorf("release frame buffer: %w", err)
+	}
+
+	return nil
+}
+
+// GetDisplayMode returns size of remote desktop, it is known after the first connection.
+func (client *Client) GetDisplayMode(ctx context.Context) (*peripheralSDK.DisplayMode, error) {
+	client.frameBufferLock.RLock()
+	defer client.frameBufferLock.RUnlock()
+
+	if client.frameBuffer == nil {
+		return nil, peripheralSDK.ErrDisplayFrameBufferNotReady
+	}
+
+	displayMode := client.displayMode
+
+	return &displayMode, nil
+}
+
+func (client *Client) GetDisplayPixelFormat(ctx context.Context) (*peripheralSDK.DisplayPixelFormat, error) {
+	return &client.pixelFormat, nil
+}
+
+func (client *Client) GetDisplayFrameBuffer(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+	client.frameBufferLock.RLock()
+	defer client.frameBufferLock.RUnlock()
+
+	if client.frameBuffer == nil {
+		return nil, peripheralSDK.ErrDisplayFrameBufferNotReady
+	}
+
+	err := client.frameBuffer.Retain()
+	if err != nil {
+		return nil, fmt.Errorf("retain frame buffer: %w", err)
+	}
+
+	return client.frameBuffer, nil
+}
+
+func (client *Client) GetDisplaySourceMetrics() peripheralSDK.DisplaySourceMetrics {
+	client.metricsLock.RLock()
+	defer client.metricsLock.RUnlock()
+
+	metrics := client.metrics
+	metrics.AdditionalMetrics = map[string]interface{}{
+		"connected": client.getConnection() != nil,
+	}
+	for key, value := range client.metrics.AdditionalMetrics {
+		metrics.AdditionalMetrics[key] = value
+	}
+
+	return metrics
+}
+
+func (client *Client) updateMetrics(updateFn func(metrics *peripheralSDK.DisplaySourceMetrics)) {
+	client.metricsLock.Lock()
+	defer client.metricsLock.Unlock()
+
+	if client.metrics.AdditionalMetrics == nil {
+		client.metrics.AdditionalMetrics = map[string]interface{}{}
+	}
+
+	updateFn(&client.metrics)
+}
+
+func (client *Client) getConnection() *rfb.Client {
+	client.connectionLock.RLock()
+	defer client.connectionLock.RUnlock()
+
+	return client.connection
+}
+
+func (client *Client) setConnection(connection *rfb.Client) {
+	client.connectionLock.Lock()
+	defer client.connectionLock.Unlock()
+
+	client.connection = connection
+}
+
+// connectLoop keeps connection to the server, failed or lost connection is retried after reconnect delay.
+func (client *Client) connectLoop(ctx context.Context) {
+	defer close(client.lifecycleDone)
+
+	for {
+		err := client.serveConnection(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		client.logger.Warn("RFB connection failed, reconnecting.",
+			slog.String("error", err.Error()),
+			slog.Duration("reconnectDelay", client.reconnectDelay),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(client.reconnectDelay):
+		}
+	}
+}
+
+func (client *Client) serveConnection(ctx context.Context) error {
+	connection, err := rfb.Dial(ctx, client.address,
+		rfb.WithClientPassword(client.password),
+		rfb.WithClientLogger(client.logger),
+	)
+	if err != nil {
+		return err
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = connection.Close()
+	})
+	defer stop()
+
+	defer func() {
+		client.setConnection(nil)
+		_ = connection.Close()
+	}()
+
+	client.resetInput()
+	client.setConnection(connection)
+
+	client.updateMetrics(func(metrics *peripheralSDK.DisplaySourceMetrics) {
+		connections, _ := metrics.AdditionalMetrics["connections"].(uint64)
+		metrics.AdditionalMetrics["connections"] = connections + 1
+	})
+
+	client.logger.Info("RFB connection established.", slog.String("desktopName", connection.GetDesktopName()))
+
+	updateInterval := time.Second / time.Duration(client.maxFrameRate)
+	requestedAt := time.Now()
+
+	if err := connection.RequestUpdate(false); err != nil {
+		return err
+	}
+
+	for {
+		rectangles, err := connection.ReadUpdate()
+		if err != nil {
+			return fmt.Errorf("read update: %w", err)
+		}
+
+		if len(rectangles) > 0 {
+			if err := client.publishFrame(connection.GetFrame()); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Until(requestedAt.Add(updateInterval))):
+		}
+
+		requestedAt = time.Now()
+		if err := connection.RequestUpdate(true); err != nil {
+			return err
+		}
+	}
+}
+
+// publishFrame copies framebuffer into new memory buffer and swaps served frame buffer.
+func (client *Client) publishFrame(frame *rfb.Frame) error {
+	memoryPool, err := memory.DefaultMemoryPoolProvider()
+	if err != nil {
+		return fmt.Errorf("get memory pool provider: %w", err)
+	}
+
+	memoryBuffer, err := memoryPool.Borrow(len(frame.Pixels))
+	if err != nil {
+		return fmt.Errorf("borrow memory buffer: %w", err)
+	}
+
+	if _, err := memoryBuffer.Write(frame.Pixels); err != nil {
+		_ = memoryBuffer.Release()
+		return fmt.Errorf("write memory buffer: %w", err)
+	}
+
+	client.frameBufferLock.Lock()
+	if client.frameBuffer != nil {
+		if err := client.frameBuffer.Release(); err != nil {
+			client.logger.Warn("Failed to release frame buffer.", slog.String("error", err.Error()))
+		}
+	}
+
+	client.frameSequence++
+	client.frameBuffer = peripheralSDK.NewDisplayFrameBuffer(memoryBuffer,
+		peripheralSDK.WithDisplayFrameBufferSequence(client.frameSequence),
+		peripheralSDK.WithDisplayFrameBufferTimestamp(time.Now()),
+	)
+	client.displayMode = peripheralSDK.DisplayMode{
+		Width:       uint32(frame.Width),
+		Height:      uint32(frame.Height),
+		RefreshRate: uint32(client.maxFrameRate),
+	}
+	client.frameBufferLock.Unlock()
+
+	client.updateMetrics(func(metrics *peripheralSDK.DisplaySourceMetrics) {
+		metrics.FrameBufferSwaps++
+		metrics.FrameBufferWrittenBytes += uint64(len(frame.Pixels))
+	})
+
+	return nil
+}
+
+func (client *Client) resetInput() {
+	client.inputLock.Lock()
+	defer client.inputLock.Unlock()
+
+	client.buttonMask = 0
+	clear(client.pressedKeys)
+}
+
+// HandleKeyboardDataEvent sends key as keysym of US layout, Shift modifier selects shifted keysym. Release uses keysym
+// sent on press, so the server releases the same key when Shift was released first.
+func (client *Client) HandleKeyboardDataEvent(event peripheralSDK.KeyboardEvent) error {
+	keyEvent, isKeyEvent := event.(peripheralSDK.KeyboardKeyEvent)
+	if !isKeyEvent {
+		return fmt.Errorf("%w: %T", ErrEventUnsupported, event)
+	}
+
+	connection := client.getConnection()
+	if connection == nil {
+		return ErrNotConnected
+	}
+
+	client.inputLock.Lock()
+	defer client.inputLock.Unlock()
+
+	keysym, pressed := client.pressedKeys[keyEvent.HIDUsage]
+	if !pressed || keyEvent.State == peripheralSDK.KeyboardKeyStatePress {
+		var found bool
+		keysym, found = hid.KeysymFromUsage(keyEvent.HIDUsage, keyEvent.Modifiers&peripheralSDK.KeyboardModifierShift != 0)
+		if !found {
+			return fmt.Errorf("%w: hid usage %#x", ErrEventUnsupported, keyEvent.HIDUsage)
+		}
+	}
+
+	down := keyEvent.State != peripheralSDK.KeyboardKeyStateRelease
+	if down {
+		client.pressedKeys[keyEvent.HIDUsage] = keysym
+	} else {
+		delete(client.pressedKeys, keyEvent.HIDUsage)
+	}
+
+	return connection.SendKeyEvent(down, uint32(keysym))
+}
+
+// KeyboardControlChannel returns channel closed when ctx is done, the server does not report keyboard state.
+func (client *Client) KeyboardControlChannel(ctx context.Context) <-chan peripheralSDK.KeyboardControlEvent {
+	controlChannel := make(chan peripheralSDK.KeyboardControlEvent)
+
+	go func() {
+		<-ctx.Done()
+		close(controlChannel)
+	}()
+
+	return controlChannel
+}
+
+// HandleMouseDataEvent sends pointer state to the server. Relative moves are applied to the last position, wheel
+// steps are sent as press and release of wheel buttons.
+func (client *Client) HandleMouseDataEvent(event peripheralSDK.MouseEvent) error {
+	connection := client.getConnection()
+	if connection == nil {
+		return ErrNotConnected
+	}
+
+	client.inputLock.Lock()
+	defer client.inputLock.Unlock()
+
+	client.frameBufferLock.RLock()
+	width, height := uint16(client.displayMode.Width), uint16(client.displayMode.Height)
+	client.frameBufferLock.RUnlock()
+
+	switch typedEvent := event.(type) {
+	case peripheralSDK.MouseMoveEvent:
+		if typedEvent.Absolute {
+			client.pointerX = scalePointer(typedEvent.X, width)
+			client.pointerY = scalePointer(typedEvent.Y, height)
+		} else {
+			client.pointerX = movePointer(client.pointerX, typedEvent.DeltaX, width)
+			client.pointerY = movePointer(client.pointerY, typedEvent.DeltaY, height)
+		}
+
+		return connection.SendPointerEvent(client.buttonMask, client.pointerX, client.pointerY)
+
+	case peripheralSDK.MouseButtonEvent:
+		var bit uint8
+		switch typedEvent.Button {
+		case peripheralSDK.MouseButtonLeft:
+			bit = rfb.ButtonLeft
+		case peripheralSDK.MouseButtonMiddle:
+			bit = rfb.ButtonMiddle
+		case peripheralSDK.MouseButtonRight:
+			bit = rfb.ButtonRight
+		default:
+			return fmt.Errorf("%w: mouse button %d", ErrEventUnsupported, typedEvent.Button)
+		}
+
+		if typedEvent.State == peripheralSDK.MouseButtonStatePress {
+			client.buttonMask |= bit
+		} else {
+			client.buttonMask &^= bit
+		}
+
+		return connection.SendPointerEvent(client.buttonMask, client.pointerX, client.pointerY)
+
+	case peripheralSDK.MouseWheelEvent:
+		steps := []struct {
+			delta              int32
+			negative, positive uint8
+		}{
+			{typedEvent.DeltaY, rfb.ButtonWheelUp, rfb.ButtonWheelDown},
+			{typedEvent.DeltaX, rfb.ButtonWheelLeft, rfb.ButtonWheelRight},
+		}
+
+		for _, step := range steps {
+			bit := step.positive
+			if step.delta < 0 {
+				bit = step.negative
+			}
+
+			for range min(abs(step.delta), 32) {
+				if err := connection.SendPointerEvent(client.buttonMask|bit, client.pointerX, client.pointerY); err != nil {
+					return err
+				}
+				if err := connection.SendPointerEvent(client.buttonMask, client.pointerX, client.pointerY); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("%w: %T", ErrEventUnsupported, event)
+	}
+}
+
+// scalePointer converts normalized position into framebuffer coordinate.
+func scalePointer(position float64, size uint16) uint16 {
+	if size == 0 {
+		return 0
+	}
+
+	return uint16(math.Round(min(max(position, 0), 1) * float64(size-1)))
+}
+
+func movePointer(position uint16, delta int32, size uint16) uint16 {
+	if size == 0 {
+		return 0
+	}
+
+	return uint16(min(max(int32(position)+delta, 0), int32(size)-1))
+}
+
+func abs(value int32) int32 {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+var (
+	ErrNotConnected     = errors.New("rfb client not connected")
+	ErrEventUnsupported = errors.New("input event unsupported")
+)
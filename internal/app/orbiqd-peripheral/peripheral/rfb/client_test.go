@@ -0,0 +1,165 @@
+package rfb
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/rfb"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// startTestServer serves 4x2 frame, pixel x of the first row has red value x, over RFB on loopback address.
+func startTestServer(t *testing.T, opts ...rfb.ServerOpt) string {
+	memory.SetupTestDefaultMemoryPool(t)
+
+	pixels := make([]byte, 4*2*3)
+	for x := range 4 {
+		pixels[x*3] = byte(x)
+	}
+
+	displayMode := peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 30}
+	pixelFormat := peripheralSDK.DisplayPixelFormatRGB24
+
+	displaySource := peripheralSDK.NewDisplaySourceMock(t)
+	displaySource.EXPECT().GetDisplayMode(mock.Anything).Return(&displayMode, nil).Maybe()
+	displaySource.EXPECT().GetDisplayPixelFormat(mock.Anything).Return(&pixelFormat, nil).Maybe()
+	displaySource.EXPECT().GetDisplayFrameBuffer(mock.Anything).RunAndReturn(func(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+		memoryPool, err := memory.DefaultMemoryPoolProvider()
+		if err != nil {
+			return nil, err
+		}
+
+		buffer, err := memoryPool.Borrow(len(pixels))
+		if err != nil {
+			return nil, err
+		}
+		_, _ = buffer.Write(pixels)
+
+		return peripheralSDK.NewDisplayFrameBuffer(buffer, peripheralSDK.WithDisplayFrameBufferSequence(1)), nil
+	}).Maybe()
+
+	server, err := rfb.NewServer(displaySource, opts...)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = server.Serve(ctx, listener)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return listener.Addr().String()
+}
+
+func TestClient(t *testing.T) {
+	keyboardEvents := make(chan peripheralSDK.KeyboardEvent, 8)
+	keyboardSink := peripheralSDK.NewKeyboardSinkMock(t)
+	keyboardSink.EXPECT().HandleKeyboardDataEvent(mock.Anything).RunAndReturn(func(event peripheralSDK.KeyboardEvent) error {
+		keyboardEvents <- event
+		return nil
+	})
+
+	mouseEvents := make(chan peripheralSDK.MouseEvent, 8)
+	mouseSink := peripheralSDK.NewMouseEventHandlerMock(t)
+	mouseSink.EXPECT().HandleMouseDataEvent(mock.Anything).RunAndReturn(func(event peripheralSDK.MouseEvent) error {
+		mouseEvents <- event
+		return nil
+	})
+
+	address := startTestServer(t,
+		rfb.WithServerPassword("secret"),
+		rfb.WithServerKeyboardSink(keyboardSink),
+		rfb.WithServerMouseSink(mouseSink),
+	)
+
+	password := "secret"
+	client, err := NewClient(t.Context(), ClientConfig{Address: address, Password: &password}, "vm-console")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		assert.NoError(t, client.Terminate(context.Background()))
+	}()
+
+	var frameBuffer *peripheralSDK.DisplayFrameBuffer
+	assert.Eventually(t, func() bool {
+		frameBuffer, err = client.GetDisplayFrameBuffer(t.Context())
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	if !assert.NotNil(t, frameBuffer) {
+		t.FailNow()
+	}
+
+	pixels := bytes.Buffer{}
+	_, err = frameBuffer.WriteTo(&pixels)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 1, 0, 0, 2, 0, 0, 3, 0, 0}, pixels.Bytes()[:12])
+	assert.NoError(t, frameBuffer.Release())
+
+	displayMode, err := client.GetDisplayMode(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(4), displayMode.Width)
+	assert.Equal(t, uint32(2), displayMode.Height)
+
+	assert.NoError(t, client.HandleKeyboardDataEvent(peripheralSDK.NewKeyboardKeyEvent(
+		0x04, "", peripheralSDK.KeyboardLogicalKey{}, peripheralSDK.KeyboardModifierNone, peripheralSDK.KeyboardKeyStatePress, "", "test", time.Now(),
+	)))
+
+	keyEvent := (<-keyboardEvents).(peripheralSDK.KeyboardKeyEvent)
+	assert.Equal(t, peripheralSDK.KeyboardHIDUsage(0x04), keyEvent.HIDUsage)
+	assert.Equal(t, "a", keyEvent.Text)
+
+	assert.NoError(t, client.HandleMouseDataEvent(peripheralSDK.NewMouseAbsoluteMoveEvent(1, 1, "test", time.Now())))
+	assert.NoError(t, client.HandleMouseDataEvent(peripheralSDK.NewMouseButtonEvent(peripheralSDK.MouseButtonRight, peripheralSDK.MouseButtonStatePress, "test", time.Now())))
+
+	moveEvent := (<-mouseEvents).(peripheralSDK.MouseMoveEvent)
+	assert.InDelta(t, 1, moveEvent.X, 0.001)
+	assert.InDelta(t, 1, moveEvent.Y, 0.001)
+
+	buttonEvent := (<-mouseEvents).(peripheralSDK.MouseButtonEvent)
+	assert.Equal(t, peripheralSDK.MouseButtonRight, buttonEvent.Button)
+	assert.Equal(t, peripheralSDK.MouseButtonStatePress, buttonEvent.State)
+}
+
+func TestClientNotConnected(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	reconnectDelay := "10ms"
+	client, err := NewClient(t.Context(), ClientConfig{Address: address, ReconnectDelay: &reconnectDelay}, "vm-console")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		assert.NoError(t, client.Terminate(context.Background()))
+	}()
+
+	_, err = client.GetDisplayFrameBuffer(t.Context())
+	assert.ErrorIs(t, err, peripheralSDK.ErrDisplayFrameBufferNotReady)
+
+	err = client.HandleMouseDataEvent(peripheralSDK.NewMouseWheelEvent(0, 1, "test", time.Now()))
+	assert.ErrorIs(t, err, ErrNotConnected)
+}
@@ -0,0 +1,236 @@
+//go:build linux
+
+package v4l2
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/v4l2/capture"
+	v4l2io "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/v4l2/io"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	memorySDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const CaptureDisplaySourceDriverKind = driverSDK.Kind("v4l2-capture-display-source")
+
+var CaptureDisplaySourceDriver = driver.NewLocalDriver(CaptureDisplaySourceDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := CaptureDisplaySourceConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", CaptureDisplaySourceDriverKind.String()))
+
+	displaySource, err := NewCaptureDisplaySource(ctx, driverConfig, name, WithDisplaySourceLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return displaySource, nil
+})
+
+// capturePixelFormats maps configured pixel format names to formats the device captures.
+var capturePixelFormats = map[string]v4l2io.PixelFormatCode{
+	"rgb24": v4l2io.PixelFormatCodeRGB24,
+	"yuyv":  v4l2io.PixelFormatCodeYUYV,
+	"mjpeg": v4l2io.PixelFormatCodeMJPEG,
+}
+
+type CaptureDisplaySourceConfig struct {
+	DevicePath string `json:"devicePath" validate:"required"`
+	// DisplayMode the captured format is selected to match best, the largest frame size with the highest frame rate
+	// is captured when not set.
+	DisplayMode *peripheralSDK.DisplayMode `json:"displayMode"`
+	// PixelFormats captured from the device in order of preference, all supported formats when not set.
+	PixelFormats []string `json:"pixelFormats" validate:"dive,oneof=rgb24 yuyv mjpeg"`
+	// FrameTimeout after which capture is restarted when the device delivers no frames, 5s when not set.
+	FrameTimeout *string `json:"frameTimeout"`
+}
+
+var (
+	_ peripheralSDK.DisplaySource = (*CaptureDisplaySource)(nil)
+)
+
+// CaptureDisplaySource captures frames of a generic V4L2 capture device, like UVC webcam or USB HDMI grabber.
+type CaptureDisplaySource struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+
+	frameBuffer         *peripheralSDK.DisplayFrameBuffer
+	frameBufferSequence uint64
+	frameBufferLock     *sync.RWMutex
+
+	videoDevice *capture.Device
+
+	metrics     peripheralSDK.DisplaySourceMetrics
+	metricsLock sync.RWMutex
+
+	logger *slog.Logger
+}
+
+func NewCaptureDisplaySource(ctx context.Context, config CaptureDisplaySourceConfig, name peripheralSDK.Name, opts ...DisplaySourceOpt) (*CaptureDisplaySource, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	id, err := createDisplaySourceId(config.DevicePath, "capture")
+	if err != nil {
+		return nil, fmt.Errorf("create display source id: %w", err)
+	}
+
+	options := defaultDisplaySourceOptions()
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	frameTimeout, err := time.ParseDuration(utils.DefaultNil(config.FrameTimeout, "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse frame timeout: %w", err)
+	}
+
+	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
+
+	logger := options.logger.With(slog.String("peripheralId", id.String()))
+
+	source := &CaptureDisplaySource{
+		id:   id,
+		name: name,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+
+		frameBuffer:     nil,
+		frameBufferLock: &sync.RWMutex{},
+
+		metricsLock: sync.RWMutex{},
+
+		logger: logger,
+	}
+
+	deviceOpts := []capture.DeviceOpt{
+		capture.WithMemoryPoolProvider(options.memoryPoolProvider),
+		capture.WithFrameTimeout(frameTimeout),
+		capture.WithLogger(logger),
+		capture.WithFrameHandler(source.frameHandler),
+	}
+
+	if config.DisplayMode != nil {
+		deviceOpts = append(deviceOpts, capture.WithDisplayMode(*config.DisplayMode))
+	}
+
+	if len(config.PixelFormats) > 0 {
+		pixelFormats := make([]v4l2io.PixelFormatCode, 0, len(config.PixelFormats))
+		for _, pixelFormat := range config.PixelFormats {
+			pixelFormats = append(pixelFormats, capturePixelFormats[pixelFormat])
+		}
+
+		deviceOpts = append(deviceOpts, capture.WithPixelFormats(pixelFormats...))
+	}
+
+	videoDevice, err := capture.Open(lifecycleCtx, config.DevicePath, deviceOpts...)
+	if err != nil {
+		lifecycleCancel()
+		return nil, fmt.Errorf("open device: %w", err)
+	}
+	source.videoDevice = videoDevice
+
+	return source, nil
+}
+
+func (source *CaptureDisplaySource) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySourceCapability,
+	}
+}
+
+func (source *CaptureDisplaySource) GetId() peripheralSDK.Id {
+	return source.id
+}
+
+func (source *CaptureDisplaySource) GetName() peripheralSDK.Name {
+	return source.name
+}
+
+func (source *CaptureDisplaySource) Terminate(ctx context.Context) error {
+	source.lifecycleCancel()
+	return nil
+}
+
+func (source *CaptureDisplaySource) GetDisplayFrameBuffer(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+	source.frameBufferLock.RLock()
+	defer source.frameBufferLock.RUnlock()
+
+	if source.frameBuffer == nil {
+		return nil, peripheralSDK.ErrDisplayFrameBufferNotReady
+	}
+
+	err := source.frameBuffer.Retain()
+	if err != nil {
+		return nil, fmt.Errorf("retain frame buffer: %w", err)
+	}
+
+	return source.frameBuffer, nil
+}
+
+func (source *CaptureDisplaySource) GetDisplayMode(ctx context.Context) (*peripheralSDK.DisplayMode, error) {
+	return source.videoDevice.GetDisplayMode()
+}
+
+func (source *CaptureDisplaySource) GetDisplayPixelFormat(ctx context.Context) (*peripheralSDK.DisplayPixelFormat, error) {
+	return source.videoDevice.GetPixelFormat()
+}
+
+func (source *CaptureDisplaySource) GetDisplaySourceMetrics() peripheralSDK.DisplaySourceMetrics {
+	source.metricsLock.RLock()
+	defer source.metricsLock.RUnlock()
+
+	return source.metrics
+}
+
+func (source *CaptureDisplaySource) updateMetrics(updateFn func(metrics *peripheralSDK.DisplaySourceMetrics)) {
+	source.metricsLock.Lock()
+	defer source.metricsLock.Unlock()
+
+	updateFn(&source.metrics)
+}
+
+func (source *CaptureDisplaySource) frameHandler(memoryBuffer memorySDK.Buffer) error {
+	frameSize := memoryBuffer.GetSize()
+
+	source.frameBufferLock.Lock()
+	defer source.frameBufferLock.Unlock()
+
+	if source.frameBuffer != nil {
+		err := source.frameBuffer.Release()
+		if err != nil {
+			return fmt.Errorf("releasing previous frame buffer: %w", err)
+		}
+	}
+
+	source.frameBufferSequence++
+	source.frameBuffer = peripheralSDK.NewDisplayFrameBuffer(memoryBuffer, peripheralSDK.WithDisplayFrameBufferSequence(source.frameBufferSequence))
+
+	source.updateMetrics(func(metrics *peripheralSDK.DisplaySourceMetrics) {
+		metrics.FrameBufferSwaps++
+		metrics.FrameBufferWrittenBytes += uint64(frameSize)
+	})
+
+	return nil
+}
@@ -4,10 +4,14 @@ package v4l2
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/iancoleman/strcase"
@@ -15,6 +19,9 @@ import (
 
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/edid"
+	v4l2io "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/v4l2/io"
 	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/v4l2/tc358743"
 	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
 	memorySDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/memory"
@@ -67,8 +74,131 @@ func defaultDisplaySourceOptions() *DisplaySourceOptions {
 
 type DisplaySourceConfig struct {
 	DevicePath string `json:"devicePath" validate:"required"`
+	// Edid is programmed on the device, so the connected machine offers display modes it declares. EDID present on
+	// the device is kept when not set.
+	Edid *DisplaySourceEdidConfig `json:"edid"`
+	// Simulation replaces the device with a simulated HDMI bridge, so the source runs without capture hardware. The
+	// device path only names the source then.
+	Simulation *DisplaySourceSimulationConfig `json:"simulation"`
 }
 
+type DisplaySourceSimulationConfig struct {
+	// DisplayModes of the signal presented one after another, the last one stays presented.
+	DisplayModes peripheralSDK.DisplayModeList `json:"displayModes" validate:"required,min=1"`
+	// SwitchInterval is how long every display mode is presented before the next one, 10s when not set.
+	SwitchInterval *string `json:"switchInterval"`
+}
+
+// createBackend returns simulated bridge playing configured display modes until the context is done.
+func (config *DisplaySourceSimulationConfig) createBackend(ctx context.Context) (*v4l2io.SimulatedBackend, error) {
+	switchInterval, err := time.ParseDuration(utils.DefaultNil(config.SwitchInterval, "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("parse switch interval: %w", err)
+	}
+
+	steps := make([]v4l2io.SimulatedTimingsStep, 0, len(config.DisplayModes))
+	for _, displayMode := range config.DisplayModes {
+		timings := v4l2io.NewSimulatedTimings(displayMode.Width, displayMode.Height, displayMode.RefreshRate)
+
+		steps = append(steps, v4l2io.SimulatedTimingsStep{
+			Timings:  &timings,
+			Duration: switchInterval,
+		})
+	}
+
+	backend := v4l2io.NewSimulatedBackend(v4l2io.WithSimulatedTimings(*steps[0].Timings))
+	backend.PlayTimingsScript(ctx, steps...)
+
+	return backend, nil
+}
+
+// DisplaySourceEdidPolicy decides how EDID follows the display sink frames of the source are routed to.
+type DisplaySourceEdidPolicy string
+
+const (
+	// DisplaySourceEdidPolicyFixed presents configured EDID regardless of the sink.
+	DisplaySourceEdidPolicyFixed DisplaySourceEdidPolicy = "fixed"
+	// DisplaySourceEdidPolicyFollowSink presents EDID of the monitor behind the sink, or configured EDID declaring only
+	// display modes the sink supports. Configured EDID is presented when the sink accepts any display mode.
+	DisplaySourceEdidPolicyFollowSink DisplaySourceEdidPolicy = "follow-sink"
+	// DisplaySourceEdidPolicyUnion presents configured EDID declaring display modes of the sink first and its own
+	// display modes after them.
+	DisplaySourceEdidPolicyUnion DisplaySourceEdidPolicy = "union"
+)
+
+type DisplaySourceEdidConfig struct {
+	// Path of a file with raw EDID blocks.
+	Path *string `json:"path" validate:"required_without_all=Specification DisplayModes,excluded_with=Specification DisplayModes"`
+	// Specification of EDID base block and optional CEA-861 extension, it is validated when encoded while the source is
+	// created.
+	Specification *edid.Specification `json:"specification" validate:"-"`
+	// DisplayModes to generate EDID for, the first one is preferred. Every display mode has to be declared.
+	DisplayModes peripheralSDK.DisplayModeList `json:"displayModes" validate:"excluded_with=Specification"`
+	// CvtFormula computing timings of generated EDID for display modes not defined by VESA DMT or CTA-861, cvt-rb when
+	// not set.
+	CvtFormula edid.CvtFormula `json:"cvtFormula" validate:"omitempty,oneof=cvt cvt-rb cvt-rb2"`
+	// Policy of adapting EDID to the routed display sink, fixed when not set.
+	Policy DisplaySourceEdidPolicy `json:"policy" validate:"omitempty,oneof=fixed follow-sink union"`
+}
+
+// load returns raw EDID blocks from the file, encoded from the specification or generated for the display modes,
+// together with decoded base block.
+func (config *DisplaySourceEdidConfig) load() ([]byte, *edid.Specification, error) {
+	var data []byte
+
+	switch {
+	case config.Path != nil:
+		fileData, err := os.ReadFile(*config.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read file: %w", err)
+		}
+
+		data = fileData
+	case config.DisplayModes != nil:
+		generatorOpts := []edid.GeneratorOpt{}
+		if config.CvtFormula != "" {
+			generatorOpts = append(generatorOpts, edid.WithGeneratorCvtFormula(config.CvtFormula))
+		}
+
+		specification, skippedModes, err := edid.CreateSpecificationFromDisplayModes(config.DisplayModes, generatorOpts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generate specification: %w", err)
+		}
+
+		if len(skippedModes) > 0 {
+			return nil, nil, fmt.Errorf("display modes cannot be declared: %s", skippedModes)
+		}
+
+		specificationData, err := edid.CreateBytesFromSpecification(*specification)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encode specification: %w", err)
+		}
+
+		data = specificationData
+	default:
+		specificationData, err := edid.CreateBytesFromSpecification(*config.Specification)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encode specification: %w", err)
+		}
+
+		data = specificationData
+	}
+
+	specification, err := edid.CreateSpecificationFromBytes(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode: %w", err)
+	}
+
+	return data, specification, nil
+}
+
+var (
+	_ peripheralSDK.DisplaySource                = (*DisplaySource)(nil)
+	_ peripheralSDK.DisplaySourceEdidProvider    = (*DisplaySource)(nil)
+	_ peripheralSDK.DisplaySourceEdidPassthrough = (*DisplaySource)(nil)
+	_ peripheralSDK.DisplaySourceEdidProgrammer  = (*DisplaySource)(nil)
+)
+
 type DisplaySource struct {
 	id   peripheralSDK.Id
 	name peripheralSDK.Name
@@ -76,11 +206,17 @@ type DisplaySource struct {
 	lifecycleCtx    context.Context
 	lifecycleCancel context.CancelFunc
 
-	frameBuffer     *peripheralSDK.DisplayFrameBuffer
-	frameBufferLock *sync.RWMutex
+	frameBuffer         *peripheralSDK.DisplayFrameBuffer
+	frameBufferSequence uint64
+	frameBufferLock     *sync.RWMutex
 
 	videoDevice *tc358743.Device
 
+	edid              []byte
+	edidSpecification *edid.Specification
+	edidPolicy        DisplaySourceEdidPolicy
+	edidLock          sync.Mutex
+
 	memoryPool memorySDK.Pool
 	logger     *slog.Logger
 }
@@ -91,6 +227,14 @@ func WithDisplaySourceLogger(logger *slog.Logger) DisplaySourceOpt {
 	}
 }
 
+// WithDisplaySourceMemoryPoolProvider sets provider of memory pool frames are captured into, default memory pool is used
+// when not set.
+func WithDisplaySourceMemoryPoolProvider(memoryPoolProvider memorySDK.PoolProvider) DisplaySourceOpt {
+	return func(options *DisplaySourceOptions) {
+		options.memoryPoolProvider = memoryPoolProvider
+	}
+}
+
 func NewDisplaySource(ctx context.Context, config DisplaySourceConfig, name peripheralSDK.Name, opts ...DisplaySourceOpt) (*DisplaySource, error) {
 	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
 	if err != nil {
@@ -113,8 +257,37 @@ func NewDisplaySource(ctx context.Context, config DisplaySourceConfig, name peri
 		return nil, fmt.Errorf("memory pool: %w", err)
 	}
 
+	deviceOpts := []tc358743.DeviceOpt{}
+
+	var edidData []byte
+	var edidSpecification *edid.Specification
+	edidPolicy := DisplaySourceEdidPolicyFixed
+
+	if config.Edid != nil {
+		edidData, edidSpecification, err = config.Edid.load()
+		if err != nil {
+			return nil, fmt.Errorf("load edid: %w", err)
+		}
+
+		if config.Edid.Policy != "" {
+			edidPolicy = config.Edid.Policy
+		}
+
+		deviceOpts = append(deviceOpts, tc358743.WithEdid(edidData))
+	}
+
 	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
 
+	if config.Simulation != nil {
+		backend, err := config.Simulation.createBackend(lifecycleCtx)
+		if err != nil {
+			lifecycleCancel()
+			return nil, fmt.Errorf("create simulation: %w", err)
+		}
+
+		deviceOpts = append(deviceOpts, tc358743.WithBackend(backend))
+	}
+
 	logger := options.logger.With(slog.String("peripheralId", id.String()))
 
 	source := &DisplaySource{
@@ -127,14 +300,21 @@ func NewDisplaySource(ctx context.Context, config DisplaySourceConfig, name peri
 		frameBuffer:     nil,
 		frameBufferLock: &sync.RWMutex{},
 
+		edid:              edidData,
+		edidSpecification: edidSpecification,
+		edidPolicy:        edidPolicy,
+
 		memoryPool: memoryPool,
 		logger:     logger,
 	}
 
-	videoDevice, err := tc358743.Open(lifecycleCtx, config.DevicePath,
+	deviceOpts = append(deviceOpts,
 		tc358743.WithLogger(logger),
 		tc358743.WithFrameHandler(source.frameHandler),
+		tc358743.WithMemoryPoolProvider(options.memoryPoolProvider),
 	)
+
+	videoDevice, err := tc358743.Open(lifecycleCtx, config.DevicePath, deviceOpts...)
 	if err != nil {
 		lifecycleCancel()
 		return nil, fmt.Errorf("open device: %w", err)
@@ -188,6 +368,165 @@ func (source *DisplaySource) GetDisplayPixelFormat(ctx context.Context) (*periph
 	return &pixelFormat, nil
 }
 
+func (source *DisplaySource) GetDisplayEdid(ctx context.Context) ([]byte, error) {
+	return source.videoDevice.GetEdid()
+}
+
+// SetDisplayEdid replaces configured EDID and programs it, so passthrough policy adapts the new EDID to routed sinks.
+// The connected machine sees hot plug when presented EDID changes.
+func (source *DisplaySource) SetDisplayEdid(ctx context.Context, edidData []byte) error {
+	specification, err := edid.CreateSpecificationFromBytes(edidData)
+	if err != nil {
+		return fmt.Errorf("decode edid: %w", err)
+	}
+
+	source.edidLock.Lock()
+	defer source.edidLock.Unlock()
+
+	err = source.videoDevice.SetEdid(edidData)
+	if err != nil {
+		return fmt.Errorf("set edid: %w", err)
+	}
+
+	source.edid = edidData
+	source.edidSpecification = specification
+
+	source.logger.Info("EDID programmed.", slog.Int("size", len(edidData)))
+
+	return nil
+}
+
+// PassthroughDisplaySinkInfo programs EDID adapted to the sink according to the configured policy, nil sink info
+// programs configured EDID again. The connected machine sees hot plug when presented EDID changes.
+func (source *DisplaySource) PassthroughDisplaySinkInfo(ctx context.Context, sinkInfo *peripheralSDK.DisplaySinkInfo) error {
+	source.edidLock.Lock()
+	defer source.edidLock.Unlock()
+
+	if sinkInfo == nil {
+		return source.restoreEdid()
+	}
+
+	edidData, err := source.createPassthroughEdid(sinkInfo)
+	if err != nil {
+		return fmt.Errorf("create edid: %w", err)
+	}
+
+	if edidData == nil {
+		source.logger.Debug("EDID is fixed, display sink info ignored.", slog.String("edidPolicy", string(source.edidPolicy)))
+		return nil
+	}
+
+	err = source.videoDevice.SetEdid(edidData)
+	if err != nil {
+		return fmt.Errorf("set edid: %w", err)
+	}
+
+	source.logger.Info("EDID adapted to display sink.",
+		slog.String("edidPolicy", string(source.edidPolicy)),
+		slog.String("sinkManufacturer", sinkInfo.Manufacturer),
+		slog.String("sinkModel", sinkInfo.Model),
+	)
+
+	return nil
+}
+
+// restoreEdid programs configured EDID when presented EDID follows sinks, the route to the last sink is gone then.
+func (source *DisplaySource) restoreEdid() error {
+	if source.edidSpecification == nil || source.edidPolicy == DisplaySourceEdidPolicyFixed {
+		return nil
+	}
+
+	err := source.videoDevice.SetEdid(source.edid)
+	if err != nil {
+		return fmt.Errorf("set edid: %w", err)
+	}
+
+	source.logger.Info("Configured EDID restored.", slog.String("edidPolicy", string(source.edidPolicy)))
+
+	return nil
+}
+
+// createPassthroughEdid returns EDID to present for the sink, nil when presented EDID does not follow the sink.
+func (source *DisplaySource) createPassthroughEdid(sinkInfo *peripheralSDK.DisplaySinkInfo) ([]byte, error) {
+	if source.edidSpecification == nil {
+		return nil, nil
+	}
+
+	switch source.edidPolicy {
+	case DisplaySourceEdidPolicyFollowSink:
+		if len(sinkInfo.Edid) > 0 {
+			_, err := source.decodeSinkEdid(sinkInfo.Edid)
+			if err != nil {
+				return nil, err
+			}
+
+			return sinkInfo.Edid, nil
+		}
+
+		if len(sinkInfo.SupportedModes) == 0 {
+			return source.edid, nil
+		}
+
+		return source.declareDisplayModes(sinkInfo.SupportedModes)
+	case DisplaySourceEdidPolicyUnion:
+		sinkModes := sinkInfo.SupportedModes
+
+		if len(sinkInfo.Edid) > 0 {
+			sinkSpecification, err := source.decodeSinkEdid(sinkInfo.Edid)
+			if err != nil {
+				return nil, err
+			}
+
+			sinkModes = sinkSpecification.DisplayModes()
+		}
+
+		if len(sinkModes) == 0 {
+			return source.edid, nil
+		}
+
+		displayModes := append(slices.Clone(sinkModes), source.edidSpecification.DisplayModes()...)
+
+		return source.declareDisplayModes(displayModes)
+	default:
+		return nil, nil
+	}
+}
+
+// decodeSinkEdid decodes EDID of the monitor behind the sink. Monitors with CEA-861 extension block which cannot be
+// decoded are still followed by their base block.
+func (source *DisplaySource) decodeSinkEdid(edidData []byte) (*edid.Specification, error) {
+	specification, err := edid.CreateSpecificationFromBytes(edidData)
+	if errors.Is(err, edid.ErrCeaExtensionInvalid) {
+		source.logger.Warn("CEA-861 extension block of sink EDID not decoded.", slog.String("error", err.Error()))
+		return specification, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode sink edid: %w", err)
+	}
+
+	return specification, nil
+}
+
+// declareDisplayModes returns configured EDID declaring the display modes instead of its own. Data blocks of its CEA-861
+// extension, e.g. HDMI vendor specific and audio ones, are kept.
+func (source *DisplaySource) declareDisplayModes(displayModes peripheralSDK.DisplayModeList) ([]byte, error) {
+	specification, skippedModes, err := source.edidSpecification.WithDisplayModes(displayModes)
+	if err != nil {
+		return nil, fmt.Errorf("declare display modes: %w", err)
+	}
+
+	if len(skippedModes) > 0 {
+		source.logger.Debug("Display modes cannot be declared in EDID.", slog.String("displayModes", skippedModes.String()))
+	}
+
+	edidData, err := edid.CreateBytesFromSpecification(*specification)
+	if err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+
+	return edidData, nil
+}
+
 func (source *DisplaySource) GetDisplaySourceMetrics() peripheralSDK.DisplaySourceMetrics {
 	//TODO implement me
 	panic("implement me")
@@ -204,7 +543,8 @@ func (source *DisplaySource) frameHandler(memoryBuffer memorySDK.Buffer) error {
 		}
 	}
 
-	source.frameBuffer = peripheralSDK.NewDisplayFrameBuffer(memoryBuffer)
+	source.frameBufferSequence++
+	source.frameBuffer = peripheralSDK.NewDisplayFrameBuffer(memoryBuffer, peripheralSDK.WithDisplayFrameBufferSequence(source.frameBufferSequence))
 
 	return nil
 }
@@ -0,0 +1,237 @@
+//go:build linux
+
+package v4l2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/edid"
+	memorySDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+var configuredDisplayModes = peripheralSDK.DisplayModeList{
+	{Width: 1920, Height: 1080, RefreshRate: 60},
+	{Width: 1280, Height: 720, RefreshRate: 60},
+}
+
+// createConfiguredSpecification returns EDID of an HDMI monitor with audio, declaring configured display modes.
+func createConfiguredSpecification(t *testing.T) *edid.Specification {
+	t.Helper()
+
+	specification, _, err := edid.CreateSpecificationFromDisplayModes(configuredDisplayModes)
+	require.NoError(t, err)
+
+	specification.CeaExtension = &edid.CeaExtensionSpecification{
+		BasicAudio: true,
+		VideoDescriptors: []edid.CeaShortVideoDescriptorSpecification{
+			{Vic: 16, Native: true},
+			{Vic: 4},
+		},
+		AudioDescriptors: []edid.CeaShortAudioDescriptorSpecification{{
+			Format:      edid.CeaAudioFormatLpcm,
+			MaxChannels: 2,
+			SampleRates: []uint32{48000},
+			BitDepths:   []uint8{16},
+		}},
+		HdmiVendorSpecific: &edid.CeaHdmiVendorSpecificSpecification{PhysicalAddress: "1.0.0.0"},
+	}
+
+	return specification
+}
+
+func createSinkEdid(t *testing.T, displayModes peripheralSDK.DisplayModeList) []byte {
+	t.Helper()
+
+	specification, _, err := edid.CreateSpecificationFromDisplayModes(displayModes, edid.WithGeneratorMonitorName("Sink"))
+	require.NoError(t, err)
+
+	edidData, err := edid.CreateBytesFromSpecification(*specification)
+	require.NoError(t, err)
+
+	return edidData
+}
+
+// openSimulatedDisplaySource opens the source on simulated HDMI bridge, configured EDID is presented with the policy
+// unless the policy is empty.
+func openSimulatedDisplaySource(t *testing.T, policy DisplaySourceEdidPolicy) *DisplaySource {
+	t.Helper()
+
+	config := DisplaySourceConfig{
+		DevicePath: "/dev/video0",
+		Simulation: &DisplaySourceSimulationConfig{
+			DisplayModes: peripheralSDK.DisplayModeList{{Width: 64, Height: 48, RefreshRate: 30}},
+		},
+	}
+
+	if policy != "" {
+		config.Edid = &DisplaySourceEdidConfig{
+			Specification: createConfiguredSpecification(t),
+			Policy:        policy,
+		}
+	}
+
+	memoryPool, err := memory.NewHeapPool(64*48*3, 16)
+	require.NoError(t, err)
+
+	source, err := NewDisplaySource(t.Context(), config, "simulated",
+		WithDisplaySourceMemoryPoolProvider(func() (memorySDK.Pool, error) { return memoryPool, nil }),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = source.Terminate(t.Context())
+	})
+
+	return source
+}
+
+func getPresentedSpecification(t *testing.T, source *DisplaySource) *edid.Specification {
+	t.Helper()
+
+	edidData, err := source.GetDisplayEdid(t.Context())
+	require.NoError(t, err)
+
+	specification, err := edid.CreateSpecificationFromBytes(edidData)
+	require.NoError(t, err)
+
+	return specification
+}
+
+func TestDisplaySourceEdidPolicyFixed(t *testing.T) {
+	source := openSimulatedDisplaySource(t, DisplaySourceEdidPolicyFixed)
+
+	configuredEdid, err := source.GetDisplayEdid(t.Context())
+	require.NoError(t, err)
+
+	err = source.PassthroughDisplaySinkInfo(t.Context(), &peripheralSDK.DisplaySinkInfo{
+		SupportedModes: peripheralSDK.DisplayModeList{{Width: 2560, Height: 1440, RefreshRate: 60}},
+		Edid:           createSinkEdid(t, peripheralSDK.DisplayModeList{{Width: 2560, Height: 1440, RefreshRate: 60}}),
+	})
+	require.NoError(t, err)
+
+	presentedEdid, err := source.GetDisplayEdid(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, configuredEdid, presentedEdid)
+}
+
+func TestDisplaySourceEdidPolicyFollowSinkEdid(t *testing.T) {
+	source := openSimulatedDisplaySource(t, DisplaySourceEdidPolicyFollowSink)
+
+	sinkEdid := createSinkEdid(t, peripheralSDK.DisplayModeList{{Width: 2560, Height: 1440, RefreshRate: 60}})
+
+	// sink EDID wins over the mode list
+	err := source.PassthroughDisplaySinkInfo(t.Context(), &peripheralSDK.DisplaySinkInfo{
+		SupportedModes: peripheralSDK.DisplayModeList{{Width: 1024, Height: 768, RefreshRate: 60}},
+		Edid:           sinkEdid,
+	})
+	require.NoError(t, err)
+
+	presentedEdid, err := source.GetDisplayEdid(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, sinkEdid, presentedEdid)
+
+	err = source.PassthroughDisplaySinkInfo(t.Context(), &peripheralSDK.DisplaySinkInfo{Edid: sinkEdid[:100]})
+	assert.ErrorIs(t, err, edid.ErrInvalidLength)
+}
+
+func TestDisplaySourceEdidPolicyFollowSinkModes(t *testing.T) {
+	source := openSimulatedDisplaySource(t, DisplaySourceEdidPolicyFollowSink)
+
+	sinkModes := peripheralSDK.DisplayModeList{
+		{Width: 2560, Height: 1440, RefreshRate: 60},
+		{Width: 1024, Height: 768, RefreshRate: 60},
+	}
+
+	err := source.PassthroughDisplaySinkInfo(t.Context(), &peripheralSDK.DisplaySinkInfo{SupportedModes: sinkModes})
+	require.NoError(t, err)
+
+	specification := getPresentedSpecification(t, source)
+	assert.Equal(t, sinkModes, specification.DisplayModes())
+
+	// capabilities of the configured monitor are kept, its own video data blocks are not
+	configuredSpecification := createConfiguredSpecification(t)
+	if assert.NotNil(t, specification.CeaExtension) {
+		assert.Equal(t, configuredSpecification.CeaExtension.HdmiVendorSpecific, specification.CeaExtension.HdmiVendorSpecific)
+		assert.Equal(t, configuredSpecification.CeaExtension.AudioDescriptors, specification.CeaExtension.AudioDescriptors)
+		assert.Empty(t, specification.CeaExtension.VideoDescriptors)
+	}
+
+	// sink accepting any display mode gets configured EDID
+	err = source.PassthroughDisplaySinkInfo(t.Context(), &peripheralSDK.DisplaySinkInfo{})
+	require.NoError(t, err)
+
+	assert.Equal(t, configuredDisplayModes, getPresentedSpecification(t, source).DisplayModes())
+}
+
+func TestDisplaySourceEdidPolicyUnion(t *testing.T) {
+	source := openSimulatedDisplaySource(t, DisplaySourceEdidPolicyUnion)
+
+	err := source.PassthroughDisplaySinkInfo(t.Context(), &peripheralSDK.DisplaySinkInfo{
+		SupportedModes: peripheralSDK.DisplayModeList{{Width: 1024, Height: 768, RefreshRate: 60}},
+	})
+	require.NoError(t, err)
+
+	specification := getPresentedSpecification(t, source)
+	assert.Equal(t, append(peripheralSDK.DisplayModeList{{Width: 1024, Height: 768, RefreshRate: 60}}, configuredDisplayModes...), specification.DisplayModes())
+
+	if assert.NotNil(t, specification.CeaExtension) {
+		assert.NotNil(t, specification.CeaExtension.HdmiVendorSpecific)
+		assert.True(t, specification.CeaExtension.BasicAudio)
+	}
+
+	// display modes of sink EDID win over the mode list
+	err = source.PassthroughDisplaySinkInfo(t.Context(), &peripheralSDK.DisplaySinkInfo{
+		SupportedModes: peripheralSDK.DisplayModeList{{Width: 1024, Height: 768, RefreshRate: 60}},
+		Edid:           createSinkEdid(t, peripheralSDK.DisplayModeList{{Width: 2560, Height: 1440, RefreshRate: 60}}),
+	})
+	require.NoError(t, err)
+
+	specification = getPresentedSpecification(t, source)
+	assert.Equal(t, append(peripheralSDK.DisplayModeList{{Width: 2560, Height: 1440, RefreshRate: 60}}, configuredDisplayModes...), specification.DisplayModes())
+}
+
+func TestDisplaySourceEdidRestoredWhenSinkIsGone(t *testing.T) {
+	source := openSimulatedDisplaySource(t, DisplaySourceEdidPolicyFollowSink)
+
+	configuredEdid, err := source.GetDisplayEdid(t.Context())
+	require.NoError(t, err)
+
+	err = source.PassthroughDisplaySinkInfo(t.Context(), &peripheralSDK.DisplaySinkInfo{
+		Edid: createSinkEdid(t, peripheralSDK.DisplayModeList{{Width: 2560, Height: 1440, RefreshRate: 60}}),
+	})
+	require.NoError(t, err)
+
+	presentedEdid, err := source.GetDisplayEdid(t.Context())
+	require.NoError(t, err)
+	assert.NotEqual(t, configuredEdid, presentedEdid)
+
+	err = source.PassthroughDisplaySinkInfo(t.Context(), nil)
+	require.NoError(t, err)
+
+	presentedEdid, err = source.GetDisplayEdid(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, configuredEdid, presentedEdid)
+}
+
+func TestDisplaySourceEdidPassthroughWithoutConfiguredEdid(t *testing.T) {
+	source := openSimulatedDisplaySource(t, "")
+
+	presentedEdid, presentedErr := source.GetDisplayEdid(t.Context())
+
+	err := source.PassthroughDisplaySinkInfo(t.Context(), &peripheralSDK.DisplaySinkInfo{
+		Edid: createSinkEdid(t, peripheralSDK.DisplayModeList{{Width: 2560, Height: 1440, RefreshRate: 60}}),
+	})
+	require.NoError(t, err)
+
+	err = source.PassthroughDisplaySinkInfo(t.Context(), nil)
+	require.NoError(t, err)
+
+	edidData, err := source.GetDisplayEdid(t.Context())
+	assert.Equal(t, presentedEdid, edidData)
+	assert.Equal(t, presentedErr, err)
+}
@@ -0,0 +1,269 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/driver"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/peripheral"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/pattern"
+	driverSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/driver"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const DisplaySinkDriverKind = driverSDK.Kind("verifier-display-sink")
+
+var DisplaySinkDriver = driver.NewLocalDriver(DisplaySinkDriverKind, func(ctx context.Context, config any, name peripheralSDK.Name) (peripheralSDK.Peripheral, error) {
+	driverConfig := DisplaySinkConfig{}
+
+	err := mapstructure.Decode(config, &driverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	logger := slog.Default().With(slog.String("driverKind", DisplaySinkDriverKind.String()))
+
+	displaySink, err := NewDisplaySink(ctx, driverConfig, name, WithDisplaySinkLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("create peripheral: %w", err)
+	}
+
+	return displaySink, nil
+})
+
+// DisplaySinkExpectedConfig describes pattern rendered by the source. It must match pattern display source config.
+type DisplaySinkExpectedConfig struct {
+	DisplayMode *peripheralSDK.DisplayMode `json:"displayMode"`
+	Pattern     *string                    `json:"pattern" validate:"omitempty,oneof=smpte-bars gradient moving-boxes"`
+	Seed        *uint64                    `json:"seed"`
+	BoxCount    *int                       `json:"boxCount" validate:"omitempty,min=0,max=64"`
+}
+
+type DisplaySinkConfig struct {
+	MarkerCellSize *int `json:"markerCellSize" validate:"omitempty,min=1,max=64"`
+	// Expected enables pixel comparison against the pattern. Without it only markers are verified.
+	Expected *DisplaySinkExpectedConfig `json:"expected"`
+	// Tolerance is the maximum difference of a single color channel not counted as pixel mismatch.
+	Tolerance *uint8 `json:"tolerance"`
+}
+
+type DisplaySinkOptions struct {
+	logger *slog.Logger
+}
+
+type DisplaySinkOpt func(*DisplaySinkOptions)
+
+func defaultDisplaySinkOptions() DisplaySinkOptions {
+	return DisplaySinkOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+func WithDisplaySinkLogger(logger *slog.Logger) DisplaySinkOpt {
+	return func(options *DisplaySinkOptions) {
+		options.logger = logger
+	}
+}
+
+// DisplaySink verifies frames generated by pattern display source after they passed through the route. Provider is
+// polled twice per frame interval, so frames are not skipped due to timer jitter, and frames with the already seen
+// sequence and timestamp are ignored.
+type DisplaySink struct {
+	id   peripheralSDK.Id
+	name peripheralSDK.Name
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+
+	framePump *peripheral.DisplayFramePump
+
+	verifier     *pattern.Verifier
+	verifierLock sync.Mutex
+
+	metrics     peripheralSDK.DisplaySinkMetrics
+	metricsLock sync.RWMutex
+	framesMeter *utils.RateMeter
+
+	logger *slog.Logger
+}
+
+var (
+	_ peripheralSDK.DisplaySink                = (*DisplaySink)(nil)
+	_ peripheralSDK.DisplaySinkMetricsProvider = (*DisplaySink)(nil)
+	_ peripheralSDK.DisplaySinkInfoProvider    = (*DisplaySink)(nil)
+	_ peripheralSDK.DisplayVerifier            = (*DisplaySink)(nil)
+)
+
+func NewDisplaySink(ctx context.Context, config DisplaySinkConfig, name peripheralSDK.Name, opts ...DisplaySinkOpt) (*DisplaySink, error) {
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(&config)
+	if err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	options := defaultDisplaySinkOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	verifierOpts := []pattern.VerifierOpt{
+		pattern.WithVerifierMarkerCellSize(utils.DefaultNil(config.MarkerCellSize, 8)),
+		pattern.WithVerifierTolerance(utils.DefaultNil(config.Tolerance, 0)),
+	}
+
+	if config.Expected != nil {
+		if config.Expected.DisplayMode != nil {
+			verifierOpts = append(verifierOpts, pattern.WithVerifierExpectedDisplayMode(*config.Expected.DisplayMode))
+		}
+
+		if config.Expected.Pattern != nil {
+			generatorOpts := []pattern.GeneratorOpt{
+				pattern.WithGeneratorSeed(utils.DefaultNil(config.Expected.Seed, 0)),
+			}
+			if config.Expected.BoxCount != nil {
+				generatorOpts = append(generatorOpts, pattern.WithGeneratorBoxCount(*config.Expected.BoxCount))
+			}
+
+			verifierOpts = append(verifierOpts, pattern.WithVerifierExpectedPattern(pattern.Kind(*config.Expected.Pattern), generatorOpts...))
+		}
+	}
+
+	verifier, err := pattern.NewVerifier(verifierOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("create verifier: %w", err)
+	}
+
+	id := peripheralSDK.CreatePeripheralRandomId("verifier-display-sink")
+
+	logger := options.logger.With(slog.String("peripheralId", string(id)))
+
+	lifecycleCtx, lifecycleCancel := context.WithCancel(ctx)
+
+	displaySink := &DisplaySink{
+		id:   id,
+		name: name,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+
+		verifier:     verifier,
+		verifierLock: sync.Mutex{},
+
+		metricsLock: sync.RWMutex{},
+		framesMeter: utils.NewRateMeter(),
+
+		logger: logger,
+	}
+
+	displaySink.framePump = peripheral.NewDisplayFramePump(lifecycleCtx, displaySink.verifyFrame,
+		peripheral.WithDisplayFramePumpOversampling(2),
+		peripheral.WithDisplayFramePumpErrorHandler(func(err error) {
+			displaySink.updateMetrics(func(metrics *peripheralSDK.DisplaySinkMetrics) {
+				metrics.Errors++
+			})
+			displaySink.logger.Warn("Failed to verify frame from provider.", slog.String("error", err.Error()))
+		}),
+		peripheral.WithDisplayFramePumpLogger(logger),
+	)
+
+	displaySink.logger.Debug("The verifier display sink created.")
+
+	return displaySink, nil
+}
+
+func (sink *DisplaySink) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return []peripheralSDK.PeripheralCapability{
+		peripheralSDK.DisplaySinkCapability,
+	}
+}
+
+func (sink *DisplaySink) GetName() peripheralSDK.Name {
+	return sink.name
+}
+
+func (sink *DisplaySink) GetId() peripheralSDK.Id {
+	return sink.id
+}
+
+// GetDisplaySinkInfo returns info with current display mode of the attached provider. Any valid display mode is
+// accepted.
+func (sink *DisplaySink) GetDisplaySinkInfo(ctx context.Context) (*peripheralSDK.DisplaySinkInfo, error) {
+	return &peripheralSDK.DisplaySinkInfo{
+		Manufacturer:   "OrbiqD",
+		Model:          "Display Verifier",
+		SerialNumber:   sink.id.String(),
+		SupportedModes: peripheralSDK.DisplayModeList{},
+		PixelFormats:   []peripheralSDK.DisplayPixelFormat{peripheralSDK.DisplayPixelFormatRGB24},
+		CurrentMode:    sink.framePump.GetDisplayMode(),
+	}, nil
+}
+
+func (sink *DisplaySink) SetDisplayFrameBufferProvider(provider peripheralSDK.DisplayFrameBufferProvider) error {
+	_, err := sink.framePump.Attach(provider)
+
+	return err
+}
+
+func (sink *DisplaySink) ClearDisplayFrameBufferProvider() error {
+	sink.framePump.Detach()
+
+	return nil
+}
+
+func (sink *DisplaySink) Terminate(ctx context.Context) error {
+	sink.lifecycleCancel()
+	sink.framePump.Stop()
+
+	return nil
+}
+
+func (sink *DisplaySink) GetDisplayVerificationReport(ctx context.Context) (*peripheralSDK.DisplayVerificationReport, error) {
+	report := sink.verifier.GetReport()
+
+	return &report, nil
+}
+
+func (sink *DisplaySink) ResetDisplayVerification(ctx context.Context) error {
+	sink.verifier.Reset()
+
+	sink.logger.Info("Display verification reset.")
+
+	return nil
+}
+
+func (sink *DisplaySink) GetDisplaySinkMetrics(ctx context.Context) (*peripheralSDK.DisplaySinkMetrics, error) {
+	sink.metricsLock.RLock()
+	metrics := sink.metrics
+	sink.metricsLock.RUnlock()
+
+	metrics.FramesPerSecond = sink.framesMeter.Rate()
+
+	return &metrics, nil
+}
+
+func (sink *DisplaySink) verifyFrame(frame peripheral.DisplayFrame) error {
+	sink.verifierLock.Lock()
+	defer sink.verifierLock.Unlock()
+
+	sink.framesMeter.Add(1)
+	sink.updateMetrics(func(metrics *peripheralSDK.DisplaySinkMetrics) {
+		metrics.FramesReceived++
+		metrics.BytesReceived += uint64(len(frame.Data))
+	})
+
+	sink.verifier.Observe(frame.Data, frame.DisplayMode)
+
+	return nil
+}
+
+func (sink *DisplaySink) updateMetrics(updateFn func(metrics *peripheralSDK.DisplaySinkMetrics)) {
+	sink.metricsLock.Lock()
+	defer sink.metricsLock.Unlock()
+
+	updateFn(&sink.metrics)
+}
@@ -0,0 +1,167 @@
+package gateway
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+//go:embed console
+var consoleFiles embed.FS
+
+// consoleStreamMode selects how frames are sent to the browser.
+type consoleStreamMode string
+
+const (
+	// consoleStreamModeTiles sends only tiles changed since the previous frame.
+	consoleStreamModeTiles consoleStreamMode = "tiles"
+	// consoleStreamModeJPEG sends every changed frame as a single JPEG image.
+	consoleStreamModeJPEG consoleStreamMode = "jpeg"
+)
+
+// consoleSessionConfig describes peripherals attached to console session and stream parameters.
+type consoleSessionConfig struct {
+	displaySource *peripheralAPI.DisplaySourceClient
+	keyboardSink  peripheralSDK.KeyboardSink
+	mouseSink     peripheralSDK.MouseEventHandler
+
+	mode         consoleStreamMode
+	quality      int
+	maxFrameRate int
+}
+
+func (gateway *Gateway) registerConsoleRoutes() {
+	staticFiles, err := fs.Sub(consoleFiles, "console")
+	if err != nil {
+		panic(fmt.Errorf("console files: %w", err))
+	}
+
+	gateway.mux.Handle("GET /console/", http.StripPrefix("/console/", http.FileServerFS(staticFiles)))
+	gateway.mux.HandleFunc("GET /console/ws", gateway.handleConsoleWebSocket)
+}
+
+// handleConsoleWebSocket upgrades connection to WebSocket and runs console session. Peripherals are selected with
+// query parameters displaySource, keyboardSink and mouseSink in "<nodeId>/<peripheral>" form, only display source is
+// required.
+func (gateway *Gateway) handleConsoleWebSocket(writer http.ResponseWriter, request *http.Request) {
+	config, err := gateway.getConsoleSessionConfig(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		HandshakeTimeout: 5 * time.Second,
+	}
+
+	connection, err := upgrader.Upgrade(writer, request, nil)
+	if err != nil {
+		gateway.logger.Warn("Console WebSocket upgrade failed.", slog.String("error", err.Error()))
+		return
+	}
+
+	session := newConsoleSession(connection, config, gateway.logger.With(
+		slog.String("remoteAddress", request.RemoteAddr),
+		slog.String("displaySourceId", config.displaySource.GetId().String()),
+	))
+
+	session.Run(request.Context())
+}
+
+func (gateway *Gateway) getConsoleSessionConfig(request *http.Request) (*consoleSessionConfig, error) {
+	query := request.URL.Query()
+
+	config := &consoleSessionConfig{
+		mode:         consoleStreamMode(query.Get("mode")),
+		quality:      75,
+		maxFrameRate: 30,
+	}
+
+	switch config.mode {
+	case "":
+		config.mode = consoleStreamModeTiles
+	case consoleStreamModeTiles, consoleStreamModeJPEG:
+	default:
+		return nil, fmt.Errorf("%w: unsupported mode %q", ErrInvalidRequest, config.mode)
+	}
+
+	if value := query.Get("quality"); value != "" {
+		quality, err := strconv.Atoi(value)
+		if err != nil || quality < 1 || quality > 100 {
+			return nil, fmt.Errorf("%w: quality must be between 1 and 100", ErrInvalidRequest)
+		}
+		config.quality = quality
+	}
+
+	if value := query.Get("maxFrameRate"); value != "" {
+		maxFrameRate, err := strconv.Atoi(value)
+		if err != nil || maxFrameRate < 1 || maxFrameRate > 60 {
+			return nil, fmt.Errorf("%w: max frame rate must be between 1 and 60", ErrInvalidRequest)
+		}
+		config.maxFrameRate = maxFrameRate
+	}
+
+	displaySourceClient, err := gateway.resolveConsolePeripheral(request, "displaySource", peripheralSDK.DisplaySourceCapability)
+	if err != nil {
+		return nil, err
+	}
+	if displaySourceClient == nil {
+		return nil, fmt.Errorf("%w: displaySource is required", ErrInvalidRequest)
+	}
+	config.displaySource = peripheralAPI.AsDisplaySource(displaySourceClient)
+
+	keyboardSinkClient, err := gateway.resolveConsolePeripheral(request, "keyboardSink", peripheralSDK.KeyboardSinkCapability)
+	if err != nil {
+		return nil, err
+	}
+	if keyboardSinkClient != nil {
+		config.keyboardSink = peripheralAPI.AsKeyboardSink(keyboardSinkClient)
+	}
+
+	mouseSinkClient, err := gateway.resolveConsolePeripheral(request, "mouseSink", peripheralSDK.MouseSinkCapability)
+	if err != nil {
+		return nil, err
+	}
+	if mouseSinkClient != nil {
+		config.mouseSink = peripheralAPI.AsMouseSink(mouseSinkClient)
+	}
+
+	return config, nil
+}
+
+// resolveConsolePeripheral returns peripheral selected by query parameter, nil when parameter is empty.
+func (gateway *Gateway) resolveConsolePeripheral(request *http.Request, parameter string, capability peripheralSDK.PeripheralCapability) (*peripheralAPI.PeripheralClient, error) {
+	value := request.URL.Query().Get(parameter)
+	if value == "" {
+		return nil, nil
+	}
+
+	nodeValue, peripheralValue, found := strings.Cut(value, "/")
+	if !found {
+		return nil, fmt.Errorf("%w: %s must have <nodeId>/<peripheral> form", ErrInvalidRequest, parameter)
+	}
+
+	_, peripheralClient, err := gateway.resolvePeripheral(request.Context(), nodeValue, peripheralValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasCapability(peripheralClient, capability) {
+		return nil, fmt.Errorf("%w: peripheral %s is not a %s", ErrInvalidRequest, peripheralClient.GetId(), capability.String())
+	}
+
+	return peripheralClient, nil
+}
+
+var ErrConsoleInputUnsupported = errors.New("console input unsupported")
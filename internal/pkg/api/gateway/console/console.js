@@ -0,0 +1,201 @@
+"use strict";
+
+// Console talks to the gateway it was served from. Token given in page URL is passed to API calls and WebSocket.
+const pageParams = new URLSearchParams(location.search);
+const token = pageParams.get("token") || "";
+
+const form = document.getElementById("session");
+const statusLabel = document.getElementById("status");
+const canvas = document.getElementById("screen");
+const context = canvas.getContext("2d");
+
+let socket = null;
+let drawQueue = Promise.resolve();
+
+async function api(path) {
+  const headers = token ? {Authorization: "Bearer " + token} : {};
+  const response = await fetch(path, {headers});
+  if (!response.ok) {
+    throw new Error(path + ": " + response.status);
+  }
+  return response.json();
+}
+
+async function loadPeripherals() {
+  const options = {"display-source": [], "keyboard-sink": [], "mouse-sink": []};
+
+  for (const node of await api("../node")) {
+    let peripherals = [];
+    try {
+      peripherals = await api("../node/" + encodeURIComponent(node.id) + "/peripheral");
+    } catch (error) {
+      continue;
+    }
+
+    for (const peripheral of peripherals) {
+      for (const capability of peripheral.capabilities) {
+        if (options[capability]) {
+          options[capability].push({
+            value: node.id + "/" + peripheral.id,
+            label: peripheral.name + " @ " + (node.hostName || node.id),
+          });
+        }
+      }
+    }
+  }
+
+  fillSelect(form.displaySource, options["display-source"], false);
+  fillSelect(form.keyboardSink, options["keyboard-sink"], true);
+  fillSelect(form.mouseSink, options["mouse-sink"], true);
+}
+
+function fillSelect(select, options, optional) {
+  select.replaceChildren();
+  if (optional) {
+    select.append(new Option("none", ""));
+  }
+  for (const option of options) {
+    select.append(new Option(option.label, option.value));
+  }
+  const preselected = pageParams.get(select.name);
+  if (preselected) {
+    select.value = preselected;
+  }
+}
+
+function connect() {
+  if (socket) {
+    socket.close();
+  }
+
+  const params = new URLSearchParams();
+  for (const name of ["displaySource", "keyboardSink", "mouseSink", "mode", "quality"]) {
+    if (form[name].value) {
+      params.set(name, form[name].value);
+    }
+  }
+  if (token) {
+    params.set("token", token);
+  }
+
+  const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+  socket = new WebSocket(scheme + "//" + location.host + location.pathname.replace(/[^/]*$/, "") + "ws?" + params);
+  socket.binaryType = "arraybuffer";
+
+  socket.onopen = () => setStatus("connected");
+  socket.onclose = () => setStatus("disconnected");
+  socket.onmessage = (event) => {
+    if (typeof event.data === "string") {
+      handleTextMessage(JSON.parse(event.data));
+    } else {
+      const data = event.data;
+      drawQueue = drawQueue.then(() => drawTiles(data)).catch((error) => console.warn(error));
+    }
+  };
+}
+
+function setStatus(text) {
+  statusLabel.textContent = text;
+}
+
+function handleTextMessage(message) {
+  switch (message.type) {
+    case "displayMode":
+      canvas.width = message.displayMode.width;
+      canvas.height = message.displayMode.height;
+      setStatus("connected " + message.displayMode.width + "x" + message.displayMode.height);
+      break;
+    case "error":
+      setStatus("error: " + message.error);
+      break;
+  }
+}
+
+// drawTiles decodes binary tiles message, layout is described in console_session.go.
+async function drawTiles(buffer) {
+  const view = new DataView(buffer);
+  if (view.getUint8(0) !== 1) {
+    return;
+  }
+
+  const tileCount = view.getUint16(5);
+  let offset = 7;
+  const tiles = [];
+
+  for (let i = 0; i < tileCount; i++) {
+    const x = view.getUint16(offset);
+    const y = view.getUint16(offset + 2);
+    const length = view.getUint32(offset + 8);
+    const image = new Blob([new Uint8Array(buffer, offset + 12, length)], {type: "image/jpeg"});
+    tiles.push(createImageBitmap(image).then((bitmap) => ({x, y, bitmap})));
+    offset += 12 + length;
+  }
+
+  for (const tile of await Promise.all(tiles)) {
+    context.drawImage(tile.bitmap, tile.x, tile.y);
+    tile.bitmap.close();
+  }
+}
+
+function send(message) {
+  if (socket && socket.readyState === WebSocket.OPEN) {
+    socket.send(JSON.stringify(message));
+  }
+}
+
+function pointerPosition(event) {
+  const rect = canvas.getBoundingClientRect();
+  return {
+    x: Math.min(Math.max((event.clientX - rect.left) / rect.width, 0), 1),
+    y: Math.min(Math.max((event.clientY - rect.top) / rect.height, 0), 1),
+  };
+}
+
+canvas.addEventListener("keydown", (event) => {
+  event.preventDefault();
+  send({type: "key", code: event.code, key: event.key, state: event.repeat ? "repeat" : "press"});
+});
+
+canvas.addEventListener("keyup", (event) => {
+  event.preventDefault();
+  send({type: "key", code: event.code, key: event.key, state: "release"});
+});
+
+let pendingMove = null;
+canvas.addEventListener("mousemove", (event) => {
+  const firstMove = pendingMove === null;
+  pendingMove = pointerPosition(event);
+  if (firstMove) {
+    requestAnimationFrame(() => {
+      send({type: "mouseMove", ...pendingMove});
+      pendingMove = null;
+    });
+  }
+});
+
+canvas.addEventListener("mousedown", (event) => {
+  event.preventDefault();
+  canvas.focus();
+  send({type: "mouseMove", ...pointerPosition(event)});
+  send({type: "mouseButton", button: event.button, state: "press"});
+});
+
+canvas.addEventListener("mouseup", (event) => {
+  event.preventDefault();
+  send({type: "mouseButton", button: event.button, state: "release"});
+});
+
+canvas.addEventListener("wheel", (event) => {
+  event.preventDefault();
+  send({type: "mouseWheel", deltaX: Math.sign(event.deltaX), deltaY: Math.sign(event.deltaY)});
+}, {passive: false});
+
+canvas.addEventListener("contextmenu", (event) => event.preventDefault());
+
+form.addEventListener("submit", (event) => {
+  event.preventDefault();
+  connect();
+  canvas.focus();
+});
+
+loadPeripherals().catch((error) => setStatus("error: " + error.message));
@@ -0,0 +1,404 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/hid"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/tile"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// consoleTilesMessage is the kind byte of binary message with frame tiles. Message layout, big endian:
+//
+//	u8 kind, u16 frame width, u16 frame height, u16 tile count,
+//	tile count * (u16 x, u16 y, u16 width, u16 height, u32 image length, JPEG image)
+const consoleTilesMessage = 1
+
+// consoleOutputMessage is text message sent to the browser.
+type consoleOutputMessage struct {
+	Type        string                     `json:"type"`
+	DisplayMode *peripheralSDK.DisplayMode `json:"displayMode,omitempty"`
+	Keyboard    bool                       `json:"keyboard,omitempty"`
+	Mouse       bool                       `json:"mouse,omitempty"`
+	Error       string                     `json:"error,omitempty"`
+}
+
+// consoleInputMessage is text message received from the browser. Key events carry KeyboardEvent.code and key values,
+// mouse positions are normalized to the [0, 1] range of the displayed frame.
+type consoleInputMessage struct {
+	Type   string  `json:"type"`
+	Code   string  `json:"code"`
+	Key    string  `json:"key"`
+	State  string  `json:"state"`
+	Button int     `json:"button"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	DeltaX int32   `json:"deltaX"`
+	DeltaY int32   `json:"deltaY"`
+}
+
+// consoleSession streams frames of display source to the browser and forwards browser input to keyboard and mouse
+// sinks. Keys and buttons still pressed when session ends are released.
+type consoleSession struct {
+	connection *websocket.Conn
+	config     *consoleSessionConfig
+	writeLock  sync.Mutex
+
+	pressedKeys    map[peripheralSDK.KeyboardHIDUsage]struct{}
+	pressedButtons map[peripheralSDK.MouseButton]struct{}
+
+	logger *slog.Logger
+}
+
+func newConsoleSession(connection *websocket.Conn, config *consoleSessionConfig, logger *slog.Logger) *consoleSession {
+	return &consoleSession{
+		connection:     connection,
+		config:         config,
+		pressedKeys:    make(map[peripheralSDK.KeyboardHIDUsage]struct{}),
+		pressedButtons: make(map[peripheralSDK.MouseButton]struct{}),
+		logger:         logger,
+	}
+}
+
+func (session *consoleSession) Run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	defer func() {
+		_ = session.connection.Close()
+	}()
+
+	session.logger.Info("Console session started.")
+
+	err := session.writeJSON(consoleOutputMessage{
+		Type:     "hello",
+		Keyboard: session.config.keyboardSink != nil,
+		Mouse:    session.config.mouseSink != nil,
+	})
+	if err != nil {
+		session.logger.Debug("Failed to send console hello.", slog.String("error", err.Error()))
+		return
+	}
+
+	streamDone := make(chan struct{})
+	go func() {
+		defer close(streamDone)
+		defer cancel()
+
+		if err := session.streamFrames(ctx); err != nil {
+			session.logger.Warn("Console frame stream failed.", slog.String("error", err.Error()))
+			_ = session.writeJSON(consoleOutputMessage{Type: "error", Error: err.Error()})
+		}
+	}()
+
+	// closing connection interrupts blocked read when frame stream ends first
+	go func() {
+		<-ctx.Done()
+		_ = session.connection.Close()
+	}()
+
+	session.readInput(ctx)
+	cancel()
+
+	<-streamDone
+	session.releaseInput()
+
+	session.logger.Info("Console session finished.")
+}
+
+func (session *consoleSession) streamFrames(ctx context.Context) error {
+	encoderOpts := []tile.EncoderOpt{tile.WithEncoderQuality(session.config.quality)}
+	if session.config.mode == consoleStreamModeJPEG {
+		encoderOpts = append(encoderOpts, tile.WithEncoderFullFrameThreshold(0))
+	}
+
+	encoder, err := tile.NewEncoder(encoderOpts...)
+	if err != nil {
+		return fmt.Errorf("create tile encoder: %w", err)
+	}
+
+	displaySource := session.config.displaySource
+
+	pixelFormat, err := displaySource.GetDisplayPixelFormat(ctx)
+	if err != nil {
+		return fmt.Errorf("get display pixel format: %w", err)
+	}
+	if *pixelFormat != peripheralSDK.DisplayPixelFormatRGB24 {
+		return fmt.Errorf("%w: %s", ErrFrameFormatUnsupported, *pixelFormat)
+	}
+
+	var (
+		displayMode   *peripheralSDK.DisplayMode
+		lastSequence  uint64
+		lastTimestamp time.Time
+		pixels        bytes.Buffer
+		message       []byte
+	)
+
+	ticker := time.NewTicker(time.Second / time.Duration(session.config.maxFrameRate))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		frameBuffer, err := displaySource.GetDisplayFrameBuffer(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			session.logger.Debug("Failed to get display frame buffer.", slog.String("error", err.Error()))
+			continue
+		}
+
+		sequence := frameBuffer.GetSequence()
+		timestamp := frameBuffer.GetTimestamp()
+		unchanged := sequence == lastSequence && timestamp.Equal(lastTimestamp)
+
+		pixels.Reset()
+		if !unchanged {
+			_, err = frameBuffer.WriteTo(&pixels)
+		}
+		if releaseErr := frameBuffer.Release(); releaseErr != nil {
+			session.logger.Warn("Failed to release frame buffer.", slog.String("error", releaseErr.Error()))
+		}
+		if err != nil {
+			return fmt.Errorf("read frame buffer: %w", err)
+		}
+		if unchanged {
+			continue
+		}
+		lastSequence = sequence
+		lastTimestamp = timestamp
+
+		if displayMode == nil || int(displayMode.Width*displayMode.Height)*3 != pixels.Len() {
+			displayMode, err = displaySource.GetDisplayMode(ctx)
+			if err != nil {
+				return fmt.Errorf("get display mode: %w", err)
+			}
+
+			if err := session.writeJSON(consoleOutputMessage{Type: "displayMode", DisplayMode: displayMode}); err != nil {
+				return nil
+			}
+			encoder.Reset()
+		}
+
+		tiles, err := encoder.Encode(pixels.Bytes(), int(displayMode.Width), int(displayMode.Height))
+		if err != nil {
+			session.logger.Debug("Failed to encode frame.", slog.String("error", err.Error()))
+			displayMode = nil
+			continue
+		}
+		if len(tiles) == 0 {
+			continue
+		}
+
+		message = appendTilesMessage(message[:0], *displayMode, tiles)
+		if err := session.writeMessage(websocket.BinaryMessage, message); err != nil {
+			return nil
+		}
+	}
+}
+
+func appendTilesMessage(message []byte, displayMode peripheralSDK.DisplayMode, tiles []tile.Tile) []byte {
+	message = append(message, consoleTilesMessage)
+	message = binary.BigEndian.AppendUint16(message, uint16(displayMode.Width))
+	message = binary.BigEndian.AppendUint16(message, uint16(displayMode.Height))
+	message = binary.BigEndian.AppendUint16(message, uint16(len(tiles)))
+
+	for _, frameTile := range tiles {
+		message = binary.BigEndian.AppendUint16(message, uint16(frameTile.X))
+		message = binary.BigEndian.AppendUint16(message, uint16(frameTile.Y))
+		message = binary.BigEndian.AppendUint16(message, uint16(frameTile.Width))
+		message = binary.BigEndian.AppendUint16(message, uint16(frameTile.Height))
+		message = binary.BigEndian.AppendUint32(message, uint32(len(frameTile.Image)))
+		message = append(message, frameTile.Image...)
+	}
+
+	return message
+}
+
+func (session *consoleSession) readInput(ctx context.Context) {
+	session.connection.SetReadLimit(64 * 1024)
+
+	for ctx.Err() == nil {
+		messageType, payload, err := session.connection.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		var message consoleInputMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			session.logger.Debug("Failed to decode console input.", slog.String("error", err.Error()))
+			continue
+		}
+
+		if err := session.handleInput(message); err != nil {
+			session.logger.Debug("Failed to handle console input.", slog.String("type", message.Type), slog.String("error", err.Error()))
+		}
+	}
+}
+
+func (session *consoleSession) handleInput(message consoleInputMessage) error {
+	switch message.Type {
+	case "key":
+		return session.handleKey(message)
+	case "mouseMove":
+		if session.config.mouseSink == nil {
+			return nil
+		}
+		x := min(max(message.X, 0), 1)
+		y := min(max(message.Y, 0), 1)
+		return session.config.mouseSink.HandleMouseDataEvent(peripheralSDK.NewMouseAbsoluteMoveEvent(x, y, "console", time.Now()))
+	case "mouseButton":
+		return session.handleMouseButton(message)
+	case "mouseWheel":
+		if session.config.mouseSink == nil {
+			return nil
+		}
+		return session.config.mouseSink.HandleMouseDataEvent(peripheralSDK.NewMouseWheelEvent(message.DeltaX, message.DeltaY, "console", time.Now()))
+	default:
+		return fmt.Errorf("%w: %q", ErrConsoleInputUnsupported, message.Type)
+	}
+}
+
+func (session *consoleSession) handleKey(message consoleInputMessage) error {
+	if session.config.keyboardSink == nil {
+		return nil
+	}
+
+	usage, found := hid.UsageFromDOMCode(message.Code)
+	if !found {
+		return fmt.Errorf("%w: key code %q", ErrConsoleInputUnsupported, message.Code)
+	}
+
+	var state peripheralSDK.KeyboardKeyState
+	switch message.State {
+	case "press":
+		state = peripheralSDK.KeyboardKeyStatePress
+		session.pressedKeys[usage] = struct{}{}
+	case "repeat":
+		state = peripheralSDK.KeyboardKeyStateRepeat
+	case "release":
+		state = peripheralSDK.KeyboardKeyStateRelease
+		delete(session.pressedKeys, usage)
+	default:
+		return fmt.Errorf("%w: key state %q", ErrConsoleInputUnsupported, message.State)
+	}
+
+	var text string
+	if state != peripheralSDK.KeyboardKeyStateRelease && len([]rune(message.Key)) == 1 {
+		text = message.Key
+	}
+
+	return session.config.keyboardSink.HandleKeyboardDataEvent(peripheralSDK.NewKeyboardKeyEvent(
+		usage,
+		message.Code,
+		peripheralSDK.KeyboardLogicalKey{Code: message.Key},
+		session.modifiers(),
+		state,
+		text,
+		"console",
+		time.Now(),
+	))
+}
+
+func (session *consoleSession) handleMouseButton(message consoleInputMessage) error {
+	if session.config.mouseSink == nil {
+		return nil
+	}
+
+	// values of MouseEvent.button
+	buttons := map[int]peripheralSDK.MouseButton{
+		0: peripheralSDK.MouseButtonLeft,
+		1: peripheralSDK.MouseButtonMiddle,
+		2: peripheralSDK.MouseButtonRight,
+		3: peripheralSDK.MouseButtonBack,
+		4: peripheralSDK.MouseButtonForward,
+	}
+
+	button, found := buttons[message.Button]
+	if !found {
+		return fmt.Errorf("%w: mouse button %d", ErrConsoleInputUnsupported, message.Button)
+	}
+
+	var state peripheralSDK.MouseButtonState
+	switch message.State {
+	case "press":
+		state = peripheralSDK.MouseButtonStatePress
+		session.pressedButtons[button] = struct{}{}
+	case "release":
+		state = peripheralSDK.MouseButtonStateRelease
+		delete(session.pressedButtons, button)
+	default:
+		return fmt.Errorf("%w: mouse button state %q", ErrConsoleInputUnsupported, message.State)
+	}
+
+	return session.config.mouseSink.HandleMouseDataEvent(peripheralSDK.NewMouseButtonEvent(button, state, "console", time.Now()))
+}
+
+func (session *consoleSession) modifiers() peripheralSDK.KeyboardModifiers {
+	modifiers := peripheralSDK.KeyboardModifierNone
+	for usage := range session.pressedKeys {
+		modifiers |= hid.ModifierFromUsage(usage)
+	}
+
+	return modifiers
+}
+
+// releaseInput releases keys and buttons left pressed, for example when browser tab was closed with key held.
+func (session *consoleSession) releaseInput() {
+	for usage := range session.pressedKeys {
+		delete(session.pressedKeys, usage)
+
+		err := session.config.keyboardSink.HandleKeyboardDataEvent(peripheralSDK.NewKeyboardKeyEvent(
+			usage, "", peripheralSDK.KeyboardLogicalKey{}, session.modifiers(), peripheralSDK.KeyboardKeyStateRelease, "", "console", time.Now(),
+		))
+		if err != nil {
+			session.logger.Warn("Failed to release key.", slog.Int("hidUsage", int(usage)), slog.String("error", err.Error()))
+		}
+	}
+
+	for button := range session.pressedButtons {
+		delete(session.pressedButtons, button)
+
+		err := session.config.mouseSink.HandleMouseDataEvent(peripheralSDK.NewMouseButtonEvent(button, peripheralSDK.MouseButtonStateRelease, "console", time.Now()))
+		if err != nil {
+			session.logger.Warn("Failed to release mouse button.", slog.Int("button", int(button)), slog.String("error", err.Error()))
+		}
+	}
+}
+
+func (session *consoleSession) writeJSON(message consoleOutputMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	return session.writeMessage(websocket.TextMessage, payload)
+}
+
+func (session *consoleSession) writeMessage(messageType int, payload []byte) error {
+	session.writeLock.Lock()
+	defer session.writeLock.Unlock()
+
+	_ = session.connection.SetWriteDeadline(time.Now().Add(10 * time.Second))
+
+	return session.connection.WriteMessage(messageType, payload)
+}
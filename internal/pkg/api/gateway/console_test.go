@@ -0,0 +1,200 @@
+package gateway
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func newTestKeyboardSink(t *testing.T, events chan<- peripheralSDK.KeyboardEvent) *peripheralSDK.KeyboardSinkMock {
+	keyboardSink := peripheralSDK.NewKeyboardSinkMock(t)
+	keyboardSink.EXPECT().GetId().Return("keyboard-1").Maybe()
+	keyboardSink.EXPECT().GetName().Return("keyboard").Maybe()
+	keyboardSink.EXPECT().GetCapabilities().Return([]peripheralSDK.PeripheralCapability{peripheralSDK.KeyboardSinkCapability}).Maybe()
+	keyboardSink.EXPECT().HandleKeyboardDataEvent(mock.Anything).RunAndReturn(func(event peripheralSDK.KeyboardEvent) error {
+		events <- event
+		return nil
+	}).Maybe()
+
+	return keyboardSink
+}
+
+func newTestMouseSink(t *testing.T, events chan<- peripheralSDK.MouseEvent) *peripheralSDK.MouseEventHandlerMock {
+	mouseSink := peripheralSDK.NewMouseEventHandlerMock(t)
+	mouseSink.EXPECT().GetId().Return("mouse-1").Maybe()
+	mouseSink.EXPECT().GetName().Return("mouse").Maybe()
+	mouseSink.EXPECT().GetCapabilities().Return([]peripheralSDK.PeripheralCapability{peripheralSDK.MouseSinkCapability}).Maybe()
+	mouseSink.EXPECT().HandleMouseDataEvent(mock.Anything).RunAndReturn(func(event peripheralSDK.MouseEvent) error {
+		events <- event
+		return nil
+	}).Maybe()
+
+	return mouseSink
+}
+
+func dialTestConsole(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/console/ws?" + query
+
+	connection, response, err := websocket.DefaultDialer.Dial(url, nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_ = response.Body.Close()
+	t.Cleanup(func() {
+		_ = connection.Close()
+	})
+
+	_ = connection.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	return connection
+}
+
+func readTestConsoleMessage(t *testing.T, connection *websocket.Conn) consoleOutputMessage {
+	messageType, payload, err := connection.ReadMessage()
+	if !assert.NoError(t, err) || !assert.Equal(t, websocket.TextMessage, messageType) {
+		t.FailNow()
+	}
+
+	var message consoleOutputMessage
+	assert.NoError(t, json.Unmarshal(payload, &message))
+
+	return message
+}
+
+func TestConsoleServesStaticPage(t *testing.T) {
+	gateway := newTestGateway(t)
+
+	response := serve(gateway, http.MethodGet, "/console/", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), "console.js")
+
+	response = serve(gateway, http.MethodGet, "/console/console.js", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestConsoleRejectsMissingDisplaySource(t *testing.T) {
+	gateway := newTestGateway(t)
+
+	response := serve(gateway, http.MethodGet, "/console/ws", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = serve(gateway, http.MethodGet, "/console/ws?displaySource=local", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestConsoleAcceptsQueryToken(t *testing.T) {
+	gateway := newTestGateway(t, WithGatewayTokens("secret"))
+
+	response := serve(gateway, http.MethodGet, "/console/?token=wrong", nil)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	response = serve(gateway, http.MethodGet, "/console/?token=secret", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestConsoleStreamsFramesAndForwardsInput(t *testing.T) {
+	keyboardEvents := make(chan peripheralSDK.KeyboardEvent, 8)
+	mouseEvents := make(chan peripheralSDK.MouseEvent, 8)
+
+	gateway := newTestGatewayWithPeripherals(t, []peripheralSDK.Peripheral{
+		newTestDisplaySource(t),
+		newTestKeyboardSink(t, keyboardEvents),
+		newTestMouseSink(t, mouseEvents),
+	})
+
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	connection := dialTestConsole(t, server, "displaySource=local/source-1&keyboardSink=local/keyboard-1&mouseSink=local/name:mouse")
+
+	hello := readTestConsoleMessage(t, connection)
+	assert.Equal(t, "hello", hello.Type)
+	assert.True(t, hello.Keyboard)
+	assert.True(t, hello.Mouse)
+
+	displayMode := readTestConsoleMessage(t, connection)
+	if assert.Equal(t, "displayMode", displayMode.Type) && assert.NotNil(t, displayMode.DisplayMode) {
+		assert.Equal(t, uint32(2), displayMode.DisplayMode.Width)
+	}
+
+	messageType, payload, err := connection.ReadMessage()
+	if assert.NoError(t, err) && assert.Equal(t, websocket.BinaryMessage, messageType) {
+		assert.Equal(t, byte(consoleTilesMessage), payload[0])
+		assert.Equal(t, uint16(2), binary.BigEndian.Uint16(payload[1:3]))
+		assert.Equal(t, uint16(1), binary.BigEndian.Uint16(payload[5:7]))
+	}
+
+	assert.NoError(t, connection.WriteJSON(map[string]any{"type": "key", "code": "ShiftLeft", "key": "Shift", "state": "press"}))
+	assert.NoError(t, connection.WriteJSON(map[string]any{"type": "key", "code": "KeyA", "key": "A", "state": "press"}))
+	assert.NoError(t, connection.WriteJSON(map[string]any{"type": "mouseMove", "x": 0.25, "y": 1.5}))
+	assert.NoError(t, connection.WriteJSON(map[string]any{"type": "mouseButton", "button": 2, "state": "press"}))
+
+	select {
+	case <-keyboardEvents:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shift key event not received")
+	}
+
+	select {
+	case event := <-keyboardEvents:
+		keyEvent, isKeyEvent := event.(peripheralSDK.KeyboardKeyEvent)
+		if assert.True(t, isKeyEvent) {
+			assert.Equal(t, peripheralSDK.KeyboardHIDUsage(0x04), keyEvent.HIDUsage)
+			assert.Equal(t, peripheralSDK.KeyboardKeyStatePress, keyEvent.State)
+			assert.Equal(t, peripheralSDK.KeyboardModifierShift, keyEvent.Modifiers)
+			assert.Equal(t, "A", keyEvent.Text)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("key event not received")
+	}
+
+	select {
+	case event := <-mouseEvents:
+		moveEvent, isMoveEvent := event.(peripheralSDK.MouseMoveEvent)
+		if assert.True(t, isMoveEvent) {
+			assert.True(t, moveEvent.Absolute)
+			assert.Equal(t, 0.25, moveEvent.X)
+			assert.Equal(t, 1.0, moveEvent.Y)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("mouse move event not received")
+	}
+
+	select {
+	case event := <-mouseEvents:
+		assert.Equal(t, peripheralSDK.NewMouseButtonEvent(peripheralSDK.MouseButtonRight, peripheralSDK.MouseButtonStatePress, "console", event.Timestamp()), event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("mouse button event not received")
+	}
+
+	_ = connection.Close()
+
+	released := map[peripheralSDK.KeyboardHIDUsage]bool{}
+	for len(released) < 2 {
+		select {
+		case event := <-keyboardEvents:
+			keyEvent := event.(peripheralSDK.KeyboardKeyEvent)
+			assert.Equal(t, peripheralSDK.KeyboardKeyStateRelease, keyEvent.State)
+			released[keyEvent.HIDUsage] = true
+		case <-time.After(5 * time.Second):
+			t.Fatal("pressed keys not released after disconnect")
+		}
+	}
+
+	select {
+	case event := <-mouseEvents:
+		assert.Equal(t, peripheralSDK.MouseButtonStateRelease, event.(peripheralSDK.MouseButtonEvent).State)
+	case <-time.After(5 * time.Second):
+		t.Fatal("pressed button not released after disconnect")
+	}
+}
@@ -0,0 +1,447 @@
+package gateway
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/rgb"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// maxEdidLength is the size of EDID with all 255 extension blocks.
+const maxEdidLength = 256 * 128
+
+// frameBufferProviderInput addresses display source routed to the display sink. Machine and peripheral identifiers
+// of the documented API are accepted instead of node id and peripheral.
+type frameBufferProviderInput struct {
+	NodeId     string `json:"nodeId"`
+	Peripheral string `json:"peripheral"`
+
+	MachineIdentifier    *identifierInput `json:"machineIdentifier"`
+	PeripheralIdentifier *identifierInput `json:"peripheralIdentifier"`
+}
+
+// identifierInput addresses a machine or peripheral by id or by name.
+type identifierInput struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (identifier *identifierInput) String() string {
+	if identifier == nil {
+		return ""
+	}
+
+	if identifier.Name != "" {
+		return "name:" + identifier.Name
+	}
+
+	return identifier.Id
+}
+
+// address returns node and peripheral as they are given in paths.
+func (input frameBufferProviderInput) address() (string, string) {
+	nodeValue, peripheralValue := input.NodeId, input.Peripheral
+
+	if nodeValue == "" {
+		nodeValue = input.MachineIdentifier.String()
+	}
+
+	if peripheralValue == "" {
+		peripheralValue = input.PeripheralIdentifier.String()
+	}
+
+	return nodeValue, peripheralValue
+}
+
+func (gateway *Gateway) handleGetDisplayMode(writer http.ResponseWriter, request *http.Request) {
+	displaySource, err := gateway.getDisplaySource(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	displayMode, err := displaySource.GetDisplayMode(request.Context())
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, displayMode)
+}
+
+func (gateway *Gateway) handleGetDisplayPixelFormat(writer http.ResponseWriter, request *http.Request) {
+	displaySource, err := gateway.getDisplaySource(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	pixelFormat, err := displaySource.GetDisplayPixelFormat(request.Context())
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, map[string]peripheralSDK.DisplayPixelFormat{"pixelFormat": *pixelFormat})
+}
+
+func (gateway *Gateway) handleGetDisplaySourceMetrics(writer http.ResponseWriter, request *http.Request) {
+	displaySource, err := gateway.getDisplaySource(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, displaySource.GetDisplaySourceMetrics())
+}
+
+// handleGetDisplayEdid responds with raw EDID blocks presented by the display source.
+func (gateway *Gateway) handleGetDisplayEdid(writer http.ResponseWriter, request *http.Request) {
+	displaySource, err := gateway.getDisplaySource(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	edid, err := displaySource.GetDisplayEdid(request.Context())
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/octet-stream")
+	writer.Header().Set("Content-Length", strconv.Itoa(len(edid)))
+	writer.WriteHeader(http.StatusOK)
+
+	_, _ = writer.Write(edid)
+}
+
+// handleSetDisplayEdid programs raw EDID blocks sent as request body on the display source.
+func (gateway *Gateway) handleSetDisplayEdid(writer http.ResponseWriter, request *http.Request) {
+	edid, err := io.ReadAll(http.MaxBytesReader(writer, request.Body, maxEdidLength))
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, errors.Join(ErrInvalidRequest, err))
+		return
+	}
+
+	if len(edid) == 0 {
+		writeError(writer, http.StatusBadRequest, fmt.Errorf("%w: empty edid", ErrInvalidRequest))
+		return
+	}
+
+	displaySource, err := gateway.getDisplaySource(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	if err := displaySource.SetDisplayEdid(request.Context(), edid); err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetDisplayFrameBuffer responds with the current frame encoded as image. Format is selected with "format"
+// query parameter: png (default), jpeg, ppm or raw pixels. Frame metadata is returned in response headers.
+func (gateway *Gateway) handleGetDisplayFrameBuffer(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = "png"
+	}
+
+	quality := 80
+	if value := query.Get("quality"); value != "" {
+		parsedQuality, err := strconv.Atoi(value)
+		if err != nil || parsedQuality < 1 || parsedQuality > 100 {
+			writeError(writer, http.StatusBadRequest, fmt.Errorf("%w: quality must be between 1 and 100", ErrInvalidRequest))
+			return
+		}
+		quality = parsedQuality
+	}
+
+	switch format {
+	case "png", "jpeg", "ppm", "raw":
+	default:
+		writeError(writer, http.StatusBadRequest, fmt.Errorf("%w: unsupported format %q", ErrInvalidRequest, format))
+		return
+	}
+
+	displaySource, err := gateway.getDisplaySource(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	displayMode, err := displaySource.GetDisplayMode(request.Context())
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	pixelFormat, err := displaySource.GetDisplayPixelFormat(request.Context())
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	frameBuffer, err := displaySource.GetDisplayFrameBuffer(request.Context())
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	pixels := bytes.NewBuffer(make([]byte, 0, frameBuffer.GetSize()))
+	_, err = frameBuffer.WriteTo(pixels)
+	sequence := frameBuffer.GetSequence()
+	timestamp := frameBuffer.GetTimestamp()
+	if releaseErr := frameBuffer.Release(); releaseErr != nil {
+		gateway.logger.Warn("Failed to release frame buffer.", slog.String("error", releaseErr.Error()))
+	}
+	if err != nil {
+		writeError(writer, http.StatusInternalServerError, fmt.Errorf("read frame buffer: %w", err))
+		return
+	}
+
+	contentType, body, err := encodeFrame(format, quality, *pixelFormat, *displayMode, pixels.Bytes())
+	if err != nil {
+		writeError(writer, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	writer.Header().Set("Content-Type", contentType)
+	writer.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.Header().Set("X-Display-Mode", displayMode.String())
+	writer.Header().Set("X-Display-Pixel-Format", string(*pixelFormat))
+	writer.Header().Set("X-Display-Frame-Sequence", strconv.FormatUint(sequence, 10))
+	writer.Header().Set("X-Display-Frame-Timestamp", timestamp.UTC().Format(time.RFC3339Nano))
+	writer.WriteHeader(http.StatusOK)
+
+	_, _ = writer.Write(body)
+}
+
+// encodeFrame encodes frame pixels in the requested format. Image formats are available only for RGB24 frames.
+func encodeFrame(format string, quality int, pixelFormat peripheralSDK.DisplayPixelFormat, displayMode peripheralSDK.DisplayMode, pixels []byte) (string, []byte, error) {
+	if format == "raw" {
+		return "application/octet-stream", pixels, nil
+	}
+
+	if pixelFormat != peripheralSDK.DisplayPixelFormatRGB24 {
+		return "", nil, fmt.Errorf("%w: %s", ErrFrameFormatUnsupported, pixelFormat)
+	}
+
+	width := int(displayMode.Width)
+	height := int(displayMode.Height)
+	if len(pixels) != width*height*3 {
+		return "", nil, fmt.Errorf("%w: expected %d bytes for %s, got %d", ErrFrameSizeMismatch, width*height*3, displayMode.String(), len(pixels))
+	}
+
+	var output bytes.Buffer
+
+	switch format {
+	case "ppm":
+		output.Grow(len(pixels) + 32)
+		_, _ = fmt.Fprintf(&output, "P6\n%d %d\n255\n", width, height)
+		output.Write(pixels)
+
+		return "image/x-portable-pixmap", output.Bytes(), nil
+	case "jpeg":
+		if err := jpeg.Encode(&output, rgb.ToRGBA(nil, pixels, width, height), &jpeg.Options{Quality: quality}); err != nil {
+			return "", nil, fmt.Errorf("encode jpeg: %w", err)
+		}
+
+		return "image/jpeg", output.Bytes(), nil
+	default:
+		encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+		if err := encoder.Encode(&output, rgb.ToRGBA(nil, pixels, width, height)); err != nil {
+			return "", nil, fmt.Errorf("encode png: %w", err)
+		}
+
+		return "image/png", output.Bytes(), nil
+	}
+}
+
+func (gateway *Gateway) handleSetDisplayFrameBufferProvider(writer http.ResponseWriter, request *http.Request) {
+	var input frameBufferProviderInput
+	if err := decodeJSON(request, &input); err != nil {
+		writeError(writer, http.StatusBadRequest, err)
+		return
+	}
+
+	displaySink, err := gateway.getDisplaySink(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	if err := gateway.connectDisplaySource(request, displaySink, input); err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (gateway *Gateway) handleClearDisplayFrameBufferProvider(writer http.ResponseWriter, request *http.Request) {
+	displaySink, err := gateway.getDisplaySink(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	if err := displaySink.ClearDisplayFrameBufferProvider(); err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (gateway *Gateway) handleGetDisplaySinkMetrics(writer http.ResponseWriter, request *http.Request) {
+	displaySink, err := gateway.getDisplaySink(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	metrics, err := displaySink.GetDisplaySinkMetrics(request.Context())
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, metrics)
+}
+
+func (gateway *Gateway) handleGetDisplaySinkInfo(writer http.ResponseWriter, request *http.Request) {
+	displaySink, err := gateway.getDisplaySink(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	info, err := displaySink.GetDisplaySinkInfo(request.Context())
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, info)
+}
+
+// handleCheckDisplayFrameBufferProvider reports whether the display sink can accept display source addressed by
+// input, without routing it.
+func (gateway *Gateway) handleCheckDisplayFrameBufferProvider(writer http.ResponseWriter, request *http.Request) {
+	var input frameBufferProviderInput
+	if err := decodeJSON(request, &input); err != nil {
+		writeError(writer, http.StatusBadRequest, err)
+		return
+	}
+
+	displaySink, err := gateway.getDisplaySink(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	displaySource, err := gateway.resolveDisplaySource(request, input)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	report, err := displaySink.CheckDisplayFrameBufferProvider(request.Context(), displaySource)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, report)
+}
+
+// connectDisplaySource routes display source addressed by input to the display sink.
+func (gateway *Gateway) connectDisplaySource(request *http.Request, displaySink *peripheralAPI.DisplaySinkClient, input frameBufferProviderInput) error {
+	displaySource, err := gateway.resolveDisplaySource(request, input)
+	if err != nil {
+		return err
+	}
+
+	return displaySink.SetDisplayFrameBufferProvider(displaySource)
+}
+
+func (gateway *Gateway) resolveDisplaySource(request *http.Request, input frameBufferProviderInput) (*peripheralAPI.DisplaySourceClient, error) {
+	nodeValue, peripheralValue := input.address()
+	if nodeValue == "" || peripheralValue == "" {
+		return nil, fmt.Errorf("%w: display source node id and peripheral are required", ErrInvalidRequest)
+	}
+
+	_, peripheralClient, err := gateway.resolvePeripheral(request.Context(), nodeValue, peripheralValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasCapability(peripheralClient, peripheralSDK.DisplaySourceCapability) {
+		return nil, fmt.Errorf("%w: peripheral %s is not a display source", ErrInvalidRequest, peripheralClient.GetId())
+	}
+
+	return peripheralAPI.AsDisplaySource(peripheralClient), nil
+}
+
+func (gateway *Gateway) getDisplaySource(request *http.Request) (*peripheralAPI.DisplaySourceClient, error) {
+	_, peripheralClient, err := gateway.getPeripheral(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasCapability(peripheralClient, peripheralSDK.DisplaySourceCapability) {
+		return nil, fmt.Errorf("%w: peripheral %s is not a display source", ErrInvalidRequest, peripheralClient.GetId())
+	}
+
+	return peripheralAPI.AsDisplaySource(peripheralClient), nil
+}
+
+func (gateway *Gateway) getDisplaySink(request *http.Request) (*peripheralAPI.DisplaySinkClient, error) {
+	_, peripheralClient, err := gateway.getPeripheral(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return gateway.asDisplaySink(peripheralClient)
+}
+
+func (gateway *Gateway) asDisplaySink(peripheralClient *peripheralAPI.PeripheralClient) (*peripheralAPI.DisplaySinkClient, error) {
+	if !hasCapability(peripheralClient, peripheralSDK.DisplaySinkCapability) {
+		return nil, fmt.Errorf("%w: peripheral %s is not a display sink", ErrInvalidRequest, peripheralClient.GetId())
+	}
+
+	return peripheralAPI.AsDisplaySink(peripheralClient), nil
+}
+
+func hasCapability(peripheral peripheralSDK.Peripheral, capability peripheralSDK.PeripheralCapability) bool {
+	for _, peripheralCapability := range peripheral.GetCapabilities() {
+		if peripheralCapability.Kind == capability.Kind && peripheralCapability.Role == capability.Role {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	ErrFrameFormatUnsupported = errors.New("pixel format can not be encoded as image")
+	ErrFrameSizeMismatch      = errors.New("frame size does not match display mode")
+)
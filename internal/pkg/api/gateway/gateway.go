@@ -0,0 +1,193 @@
+package gateway
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+)
+
+type GatewayOptions struct {
+	tokens []string
+	logger *slog.Logger
+}
+
+type GatewayOpt func(*GatewayOptions)
+
+func defaultGatewayOptions() GatewayOptions {
+	return GatewayOptions{
+		logger: slog.New(slog.DiscardHandler),
+	}
+}
+
+// WithGatewayTokens sets bearer tokens accepted in Authorization header or token query parameter. Gateway without
+// tokens accepts all requests.
+func WithGatewayTokens(tokens ...string) GatewayOpt {
+	return func(options *GatewayOptions) {
+		options.tokens = append(options.tokens, tokens...)
+	}
+}
+
+func WithGatewayLogger(logger *slog.Logger) GatewayOpt {
+	return func(options *GatewayOptions) {
+		options.logger = logger
+	}
+}
+
+// Gateway exposes node and peripheral services as HTTP/JSON API. Every request is translated into calls of service
+// clients over the transport, so the gateway reaches all nodes known to the node repository.
+type Gateway struct {
+	transport      apiSDK.Transport
+	nodeRepository nodeSDK.NodeRepository
+
+	tokens [][]byte
+	mux    *http.ServeMux
+
+	logger *slog.Logger
+}
+
+var _ http.Handler = (*Gateway)(nil)
+
+func NewGateway(transport apiSDK.Transport, nodeRepository nodeSDK.NodeRepository, opts ...GatewayOpt) *Gateway {
+	options := defaultGatewayOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	gateway := &Gateway{
+		transport:      transport,
+		nodeRepository: nodeRepository,
+
+		mux: http.NewServeMux(),
+
+		logger: options.logger,
+	}
+
+	for _, token := range options.tokens {
+		gateway.tokens = append(gateway.tokens, []byte(token))
+	}
+
+	gateway.registerRoutes()
+
+	return gateway
+}
+
+func (gateway *Gateway) registerRoutes() {
+	gateway.mux.HandleFunc("GET /node", gateway.handleListNodes)
+	gateway.mux.HandleFunc("GET /node/{nodeId}", gateway.handleGetNode)
+
+	gateway.mux.HandleFunc("GET /node/{nodeId}/peripheral", gateway.handleListPeripherals)
+	gateway.mux.HandleFunc("GET /node/{nodeId}/peripheral/{peripheral}", gateway.handleGetPeripheral)
+
+	gateway.mux.HandleFunc("GET /node/{nodeId}/peripheral/{peripheral}/display-source/display-mode", gateway.handleGetDisplayMode)
+	gateway.mux.HandleFunc("GET /node/{nodeId}/peripheral/{peripheral}/display-source/pixel-format", gateway.handleGetDisplayPixelFormat)
+	gateway.mux.HandleFunc("GET /node/{nodeId}/peripheral/{peripheral}/display-source/frame-buffer", gateway.handleGetDisplayFrameBuffer)
+	gateway.mux.HandleFunc("GET /node/{nodeId}/peripheral/{peripheral}/display-source/metrics", gateway.handleGetDisplaySourceMetrics)
+	gateway.mux.HandleFunc("GET /node/{nodeId}/peripheral/{peripheral}/display-source/edid", gateway.handleGetDisplayEdid)
+	gateway.mux.HandleFunc("PUT /node/{nodeId}/peripheral/{peripheral}/display-source/edid", gateway.handleSetDisplayEdid)
+
+	gateway.mux.HandleFunc("PUT /node/{nodeId}/peripheral/{peripheral}/display-sink/frame-buffer-provider", gateway.handleSetDisplayFrameBufferProvider)
+	gateway.mux.HandleFunc("DELETE /node/{nodeId}/peripheral/{peripheral}/display-sink/frame-buffer-provider", gateway.handleClearDisplayFrameBufferProvider)
+	gateway.mux.HandleFunc("POST /node/{nodeId}/peripheral/{peripheral}/display-sink/frame-buffer-provider/check", gateway.handleCheckDisplayFrameBufferProvider)
+	gateway.mux.HandleFunc("GET /node/{nodeId}/peripheral/{peripheral}/display-sink/metrics", gateway.handleGetDisplaySinkMetrics)
+	gateway.mux.HandleFunc("GET /node/{nodeId}/peripheral/{peripheral}/display-sink/info", gateway.handleGetDisplaySinkInfo)
+
+	// machine routes of the documented API address nodes the same way
+	gateway.mux.HandleFunc("GET /machine", gateway.handleListNodes)
+	gateway.mux.HandleFunc("GET /machine/{nodeId}", gateway.handleGetNode)
+	gateway.mux.HandleFunc("GET /machine/{nodeId}/peripheral", gateway.handleListPeripherals)
+	gateway.mux.HandleFunc("GET /machine/{nodeId}/peripheral/{peripheral}", gateway.handleGetPeripheral)
+	gateway.mux.HandleFunc("GET /machine/{nodeId}/peripheral/{peripheral}/display-source/display-mode", gateway.handleGetDisplayMode)
+	gateway.mux.HandleFunc("GET /machine/{nodeId}/peripheral/{peripheral}/display-source/framebuffer", gateway.handleGetDisplayFrameBuffer)
+
+	gateway.mux.HandleFunc("POST /router/display/connect", gateway.handleDisplayRouterConnect)
+	gateway.mux.HandleFunc("POST /router/display/disconnect", gateway.handleDisplayRouterDisconnect)
+
+	gateway.registerConsoleRoutes()
+}
+
+func (gateway *Gateway) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if !gateway.authorize(request) {
+		writer.Header().Set("WWW-Authenticate", `Bearer realm="orbiqd"`)
+		writeError(writer, http.StatusUnauthorized, ErrUnauthorized)
+		return
+	}
+
+	gateway.logger.Debug("Gateway request received.",
+		slog.String("method", request.Method),
+		slog.String("path", request.URL.Path),
+	)
+
+	gateway.mux.ServeHTTP(writer, request)
+}
+
+func (gateway *Gateway) authorize(request *http.Request) bool {
+	if len(gateway.tokens) == 0 {
+		return true
+	}
+
+	// browsers can not set headers of WebSocket handshake, so console passes token as query parameter
+	token, found := strings.CutPrefix(request.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		token = request.URL.Query().Get("token")
+	}
+	if token == "" {
+		return false
+	}
+
+	for _, acceptedToken := range gateway.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), acceptedToken) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Serve handles HTTP requests accepted on listener until ctx is done.
+func (gateway *Gateway) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{
+		Handler:           gateway,
+		ReadHeaderTimeout: 5 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			gateway.logger.Warn("Gateway shutdown failed.", slog.String("error", err.Error()))
+		}
+	}()
+
+	gateway.logger.Info("Gateway listening.",
+		slog.String("address", listener.Addr().String()),
+		slog.Bool("authentication", len(gateway.tokens) > 0),
+	)
+
+	err := server.Serve(listener)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	return nil
+}
+
+var (
+	ErrUnauthorized   = errors.New("unauthorized")
+	ErrTokenRequired  = errors.New("gateway listening on non-loopback address requires token")
+	ErrInvalidRequest = errors.New("invalid request")
+)
@@ -0,0 +1,258 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/api/transport/loopback"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	nodeInternal "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/node"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/peripheral"
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node"
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const testNodeId = nodeSDK.NodeId("test-node")
+
+func newTestDisplaySource(t *testing.T) *peripheralSDK.DisplaySourceMock {
+	displayMode := peripheralSDK.DisplayMode{Width: 2, Height: 2, RefreshRate: 30}
+	pixelFormat := peripheralSDK.DisplayPixelFormatRGB24
+
+	displaySource := peripheralSDK.NewDisplaySourceMock(t)
+	displaySource.EXPECT().GetId().Return("source-1").Maybe()
+	displaySource.EXPECT().GetName().Return("source").Maybe()
+	displaySource.EXPECT().GetCapabilities().Return([]peripheralSDK.PeripheralCapability{peripheralSDK.DisplaySourceCapability}).Maybe()
+	displaySource.EXPECT().GetDisplayMode(mock.Anything).Return(&displayMode, nil).Maybe()
+	displaySource.EXPECT().GetDisplayPixelFormat(mock.Anything).Return(&pixelFormat, nil).Maybe()
+	displaySource.EXPECT().GetDisplayFrameBuffer(mock.Anything).RunAndReturn(func(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+		memoryPool, err := memory.DefaultMemoryPoolProvider()
+		if err != nil {
+			return nil, err
+		}
+
+		buffer, err := memoryPool.Borrow(12)
+		if err != nil {
+			return nil, err
+		}
+		_, _ = buffer.Write([]byte{255, 0, 0, 0, 255, 0, 0, 0, 255, 255, 255, 255})
+
+		return peripheralSDK.NewDisplayFrameBuffer(buffer,
+			peripheralSDK.WithDisplayFrameBufferSequence(7),
+			peripheralSDK.WithDisplayFrameBufferTimestamp(time.Unix(1000, 0)),
+		), nil
+	}).Maybe()
+
+	return displaySource
+}
+
+func newTestGateway(t *testing.T, opts ...GatewayOpt) *Gateway {
+	return newTestGatewayWithPeripherals(t, []peripheralSDK.Peripheral{newTestDisplaySource(t)}, opts...)
+}
+
+func newTestGatewayWithPeripherals(t *testing.T, peripherals []peripheralSDK.Peripheral, opts ...GatewayOpt) *Gateway {
+	memory.SetupTestDefaultMemoryPool(t)
+
+	services := []nodeSDK.Service{
+		nodeAPI.NewNodeAdapter(nodeInternal.NewNode(testNodeId, nodeInternal.WithNodeRole(nodeSDK.Peripheral))),
+	}
+
+	var repositoryOpts []peripheral.RepositoryOpt
+	for _, peripheralInstance := range peripherals {
+		repositoryOpts = append(repositoryOpts, peripheral.WithPeripheral(peripheralInstance))
+		services = append(services, peripheralAPI.NewPeripheralAdapter(peripheralInstance))
+
+		if displaySource, isDisplaySource := peripheralInstance.(peripheralSDK.DisplaySource); isDisplaySource {
+			services = append(services, peripheralAPI.NewDisplaySourceAdapter(displaySource))
+		}
+		if keyboardSink, isKeyboardSink := peripheralInstance.(peripheralSDK.KeyboardSink); isKeyboardSink {
+			services = append(services, peripheralAPI.NewKeyboardSinkAdapter(keyboardSink))
+		}
+		if mouseSink, isMouseSink := peripheralInstance.(peripheralSDK.MouseEventHandler); isMouseSink {
+			services = append(services, peripheralAPI.NewMouseSinkAdapter(mouseSink))
+		}
+	}
+
+	peripheralRepository, err := peripheral.NewRepository(repositoryOpts...)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	services = append(services, peripheralAPI.NewRepositoryAdapter(peripheralRepository))
+
+	innerTransport := apiSDK.NewTransportMock(t)
+	innerTransport.EXPECT().GetLocalNodeId().Return(testNodeId).Maybe()
+
+	transport := loopback.NewTransport(innerTransport, loopback.WithTransportServices(services...))
+
+	return NewGateway(transport, nodeInternal.NewNodeRepository(), opts...)
+}
+
+func serve(gateway *Gateway, method string, path string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	gateway.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestGatewayRejectsRequestWithoutToken(t *testing.T) {
+	gateway := newTestGateway(t, WithGatewayTokens("secret"))
+
+	response := serve(gateway, http.MethodGet, "/node", nil)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.NotEmpty(t, response.Header().Get("WWW-Authenticate"))
+
+	response = serve(gateway, http.MethodGet, "/node", map[string]string{"Authorization": "Bearer wrong"})
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	response = serve(gateway, http.MethodGet, "/node", map[string]string{"Authorization": "Bearer secret"})
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestGatewayListsNodesAndPeripherals(t *testing.T) {
+	gateway := newTestGateway(t)
+
+	response := serve(gateway, http.MethodGet, "/node", nil)
+	if !assert.Equal(t, http.StatusOK, response.Code) {
+		t.FailNow()
+	}
+
+	var nodes []nodeOutput
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &nodes))
+	if assert.Len(t, nodes, 1) {
+		assert.Equal(t, testNodeId, nodes[0].Id)
+		assert.True(t, nodes[0].Local)
+		assert.Equal(t, []nodeSDK.NodeRole{nodeSDK.Peripheral}, nodes[0].Roles)
+		assert.Empty(t, nodes[0].Error)
+	}
+
+	response = serve(gateway, http.MethodGet, "/node/local/peripheral", nil)
+	if !assert.Equal(t, http.StatusOK, response.Code) {
+		t.FailNow()
+	}
+
+	var peripherals []peripheralOutput
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &peripherals))
+	if assert.Len(t, peripherals, 1) {
+		assert.Equal(t, peripheralSDK.Id("source-1"), peripherals[0].Id)
+		assert.Equal(t, []string{peripheralSDK.DisplaySourceCapability.String()}, peripherals[0].Capabilities)
+	}
+
+	response = serve(gateway, http.MethodGet, "/node/"+string(testNodeId)+"/peripheral/name:source", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	hostName, err := os.Hostname()
+	if assert.NoError(t, err) {
+		response = serve(gateway, http.MethodGet, "/node/name:"+hostName, nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+	}
+
+	response = serve(gateway, http.MethodGet, "/node/unknown-node/peripheral", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	response = serve(gateway, http.MethodGet, "/node/local/peripheral/missing", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestGatewayServesMachineRoutes(t *testing.T) {
+	gateway := newTestGateway(t)
+
+	for _, path := range []string{
+		"/machine",
+		"/machine/name:local",
+		"/machine/name:local/peripheral",
+		"/machine/name:local/peripheral/name:source",
+		"/machine/name:local/peripheral/name:source/display-source/display-mode",
+		"/machine/name:local/peripheral/name:source/display-source/framebuffer",
+	} {
+		response := serve(gateway, http.MethodGet, path, nil)
+		assert.Equal(t, http.StatusOK, response.Code, path)
+	}
+}
+
+func TestFrameBufferProviderInputAcceptsMachineIdentifiers(t *testing.T) {
+	var input displayRouterConnectInput
+	err := json.Unmarshal([]byte(`{
+		"displaySource": {"machineIdentifier": {"name": "remote"}, "peripheralIdentifier": {"name": "display-source"}},
+		"displaySink": {"machineIdentifier": {"id": "node-1"}, "peripheralIdentifier": {"id": "sink-1"}}
+	}`), &input)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	nodeValue, peripheralValue := input.DisplaySource.address()
+	assert.Equal(t, "name:remote", nodeValue)
+	assert.Equal(t, "name:display-source", peripheralValue)
+
+	nodeValue, peripheralValue = input.DisplaySink.address()
+	assert.Equal(t, "node-1", nodeValue)
+	assert.Equal(t, "sink-1", peripheralValue)
+}
+
+func TestGatewayReturnsDisplayFrameBufferAsImage(t *testing.T) {
+	gateway := newTestGateway(t)
+
+	response := serve(gateway, http.MethodGet, "/node/local/peripheral/source-1/display-source/frame-buffer", nil)
+	if !assert.Equal(t, http.StatusOK, response.Code, response.Body.String()) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "image/png", response.Header().Get("Content-Type"))
+	assert.Equal(t, "7", response.Header().Get("X-Display-Frame-Sequence"))
+	assert.Equal(t, "2x2@30", response.Header().Get("X-Display-Mode"))
+
+	decoded, err := png.Decode(bytes.NewReader(response.Body.Bytes()))
+	if assert.NoError(t, err) {
+		red, green, blue, _ := decoded.At(1, 0).RGBA()
+		assert.Equal(t, []uint32{0, 0xffff, 0}, []uint32{red, green, blue})
+	}
+
+	response = serve(gateway, http.MethodGet, "/node/local/peripheral/source-1/display-source/frame-buffer?format=ppm", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, append([]byte("P6\n2 2\n255\n"), 255, 0, 0, 0, 255, 0, 0, 0, 255, 255, 255, 255), response.Body.Bytes())
+
+	response = serve(gateway, http.MethodGet, "/node/local/peripheral/source-1/display-source/frame-buffer?format=gif", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestGatewayRejectsSinkOperationOnDisplaySource(t *testing.T) {
+	gateway := newTestGateway(t)
+
+	response := serve(gateway, http.MethodDelete, "/node/local/peripheral/source-1/display-sink/frame-buffer-provider", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestReadTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+	assert.NoError(t, os.WriteFile(path, []byte("# comment\n\nfirst\n  second  \n"), 0o600))
+
+	tokens, err := ReadTokenFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, tokens)
+}
+
+func TestValidateListenAddress(t *testing.T) {
+	assert.NoError(t, ValidateListenAddress("127.0.0.1:8080", nil))
+	assert.NoError(t, ValidateListenAddress("[::1]:8080", nil))
+	assert.NoError(t, ValidateListenAddress("localhost:8080", nil))
+	assert.NoError(t, ValidateListenAddress("0.0.0.0:8080", []string{"secret"}))
+	assert.ErrorIs(t, ValidateListenAddress("0.0.0.0:8080", nil), ErrTokenRequired)
+	assert.ErrorIs(t, ValidateListenAddress(":8080", nil), ErrTokenRequired)
+}
@@ -0,0 +1,153 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	nodeAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+)
+
+type nodeOutput struct {
+	Id       nodeSDK.NodeId        `json:"id"`
+	Local    bool                  `json:"local"`
+	HostName string                `json:"hostName,omitempty"`
+	Roles    []nodeSDK.NodeRole    `json:"roles,omitempty"`
+	Platform *nodeSDK.NodePlatform `json:"platform,omitempty"`
+	Error    string                `json:"error,omitempty"`
+}
+
+func (gateway *Gateway) handleListNodes(writer http.ResponseWriter, request *http.Request) {
+	nodeIds, err := gateway.getNodeIds(request.Context())
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	output := make([]nodeOutput, 0, len(nodeIds))
+	for _, nodeId := range nodeIds {
+		output = append(output, gateway.describeNode(request.Context(), nodeId))
+	}
+
+	writeJSON(writer, http.StatusOK, output)
+}
+
+func (gateway *Gateway) handleGetNode(writer http.ResponseWriter, request *http.Request) {
+	nodeId, err := gateway.getNodeId(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	output := gateway.describeNode(request.Context(), nodeId)
+	if output.Error != "" {
+		writeJSON(writer, http.StatusBadGateway, output)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, output)
+}
+
+// getNodeIds returns local node followed by remote nodes known to the repository, sorted by id.
+func (gateway *Gateway) getNodeIds(ctx context.Context) ([]nodeSDK.NodeId, error) {
+	remoteNodeIds, err := gateway.nodeRepository.GetAllNodeIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(remoteNodeIds)
+
+	localNodeId := gateway.transport.GetLocalNodeId()
+
+	nodeIds := []nodeSDK.NodeId{localNodeId}
+	for _, nodeId := range remoteNodeIds {
+		if nodeId != localNodeId {
+			nodeIds = append(nodeIds, nodeId)
+		}
+	}
+
+	return nodeIds, nil
+}
+
+// getNodeId returns node id from the request path. Word "local" addresses the node the gateway runs in, value
+// prefixed with "name:" addresses node by its host name.
+func (gateway *Gateway) getNodeId(request *http.Request) (nodeSDK.NodeId, error) {
+	return gateway.resolveNodeId(request.Context(), request.PathValue("nodeId"))
+}
+
+func (gateway *Gateway) resolveNodeId(ctx context.Context, value string) (nodeSDK.NodeId, error) {
+	localNodeId := gateway.transport.GetLocalNodeId()
+
+	nodeId := nodeSDK.NodeId(value)
+	// machine named local of the documented API is the node the gateway runs in
+	if value == "local" || value == "name:local" || nodeId == localNodeId {
+		return localNodeId, nil
+	}
+
+	if hostName, isHostName := strings.CutPrefix(value, "name:"); isHostName {
+		return gateway.findNodeByHostName(ctx, hostName)
+	}
+
+	if _, err := gateway.nodeRepository.GetNodeById(ctx, nodeId); err != nil {
+		return "", err
+	}
+
+	return nodeId, nil
+}
+
+func (gateway *Gateway) findNodeByHostName(ctx context.Context, hostName string) (nodeSDK.NodeId, error) {
+	nodeIds, err := gateway.getNodeIds(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, nodeId := range nodeIds {
+		nodeHostName, err := nodeAPI.NewNodeClient(nodeId, gateway.transport).GetHostName(ctx)
+		if err != nil {
+			gateway.logger.Debug("Failed to get node host name.", slog.String("nodeId", string(nodeId)), slog.String("error", err.Error()))
+			continue
+		}
+
+		if *nodeHostName == hostName {
+			return nodeId, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: host name %s", nodeSDK.ErrNodeIdNotFound, hostName)
+}
+
+func (gateway *Gateway) describeNode(ctx context.Context, nodeId nodeSDK.NodeId) nodeOutput {
+	output := nodeOutput{
+		Id:    nodeId,
+		Local: nodeId == gateway.transport.GetLocalNodeId(),
+	}
+
+	nodeClient := nodeAPI.NewNodeClient(nodeId, gateway.transport)
+
+	hostName, err := nodeClient.GetHostName(ctx)
+	if err != nil {
+		output.Error = err.Error()
+		return output
+	}
+	output.HostName = *hostName
+
+	roles, err := nodeClient.GetRoles(ctx)
+	if err != nil {
+		output.Error = err.Error()
+		return output
+	}
+	output.Roles = roles
+
+	platform, err := nodeClient.GetPlatform(ctx)
+	if err != nil {
+		output.Error = err.Error()
+		return output
+	}
+	output.Platform = platform
+
+	return output
+}
@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	peripheralAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node/peripheral"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type peripheralOutput struct {
+	NodeId       nodeSDK.NodeId     `json:"nodeId"`
+	Id           peripheralSDK.Id   `json:"id"`
+	Name         peripheralSDK.Name `json:"name"`
+	Capabilities []string           `json:"capabilities"`
+}
+
+func newPeripheralOutput(nodeId nodeSDK.NodeId, peripheral peripheralSDK.Peripheral) peripheralOutput {
+	output := peripheralOutput{
+		NodeId:       nodeId,
+		Id:           peripheral.GetId(),
+		Name:         peripheral.GetName(),
+		Capabilities: make([]string, 0),
+	}
+
+	for _, capability := range peripheral.GetCapabilities() {
+		output.Capabilities = append(output.Capabilities, capability.String())
+	}
+
+	return output
+}
+
+func (gateway *Gateway) handleListPeripherals(writer http.ResponseWriter, request *http.Request) {
+	nodeId, err := gateway.getNodeId(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	peripherals, err := peripheralAPI.NewRepositoryClient(nodeId, gateway.transport).GetAllPeripherals(request.Context())
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	output := make([]peripheralOutput, 0, len(peripherals))
+	for _, peripheral := range peripherals {
+		output = append(output, newPeripheralOutput(nodeId, peripheral))
+	}
+
+	writeJSON(writer, http.StatusOK, output)
+}
+
+func (gateway *Gateway) handleGetPeripheral(writer http.ResponseWriter, request *http.Request) {
+	nodeId, peripheralClient, err := gateway.getPeripheral(request)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, newPeripheralOutput(nodeId, peripheralClient))
+}
+
+// getPeripheral returns client of the peripheral addressed by request path.
+func (gateway *Gateway) getPeripheral(request *http.Request) (nodeSDK.NodeId, *peripheralAPI.PeripheralClient, error) {
+	return gateway.resolvePeripheral(request.Context(), request.PathValue("nodeId"), request.PathValue("peripheral"))
+}
+
+// resolvePeripheral finds peripheral on the node. Peripheral is identified by its id, or by its name when prefixed
+// with "name:".
+func (gateway *Gateway) resolvePeripheral(ctx context.Context, nodeValue string, peripheralValue string) (nodeSDK.NodeId, *peripheralAPI.PeripheralClient, error) {
+	if nodeValue == "" || peripheralValue == "" {
+		return "", nil, fmt.Errorf("%w: node id and peripheral are required", ErrInvalidRequest)
+	}
+
+	nodeId, err := gateway.resolveNodeId(ctx, nodeValue)
+	if err != nil {
+		return "", nil, err
+	}
+
+	repositoryClient := peripheralAPI.NewRepositoryClient(nodeId, gateway.transport)
+
+	var peripheral peripheralSDK.Peripheral
+	if name, isName := strings.CutPrefix(peripheralValue, "name:"); isName {
+		peripheral, err = repositoryClient.GetPeripheralByName(ctx, peripheralSDK.Name(name))
+	} else {
+		peripheral, err = repositoryClient.GetPeripheralById(ctx, peripheralSDK.Id(peripheralValue))
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	peripheralClient, isPeripheralClient := peripheral.(*peripheralAPI.PeripheralClient)
+	if !isPeripheralClient {
+		return "", nil, fmt.Errorf("peripheral %s is not a peripheral api client", peripheral.GetId())
+	}
+
+	return nodeId, peripheralClient, nil
+}
@@ -0,0 +1,54 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	_ = json.NewEncoder(writer).Encode(value)
+}
+
+func writeError(writer http.ResponseWriter, status int, err error) {
+	writeJSON(writer, status, errorResponse{Error: err.Error()})
+}
+
+// writeServiceError writes error returned by service client. Errors of remote services are transferred as text, so
+// not found errors are recognized by their message.
+func writeServiceError(writer http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidRequest):
+		writeError(writer, http.StatusBadRequest, err)
+	case errors.Is(err, nodeSDK.ErrNodeIdNotFound),
+		strings.Contains(err.Error(), peripheralSDK.ErrPeripheralNotFound.Error()),
+		strings.Contains(err.Error(), nodeSDK.ErrNodeIdNotFound.Error()):
+		writeError(writer, http.StatusNotFound, err)
+	case strings.Contains(err.Error(), peripheralSDK.ErrDisplayFrameBufferNotReady.Error()):
+		writeError(writer, http.StatusServiceUnavailable, err)
+	default:
+		writeError(writer, http.StatusBadGateway, err)
+	}
+}
+
+func decodeJSON(request *http.Request, value any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, request.Body, 1024*1024))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(value); err != nil {
+		return errors.Join(ErrInvalidRequest, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"net/http"
+)
+
+type displayRouterConnectInput struct {
+	DisplaySource frameBufferProviderInput `json:"displaySource"`
+	DisplaySink   frameBufferProviderInput `json:"displaySink"`
+}
+
+type displayRouterDisconnectInput struct {
+	DisplaySink frameBufferProviderInput `json:"displaySink"`
+}
+
+// handleDisplayRouterConnect routes display source to display sink, both may live on any known node.
+func (gateway *Gateway) handleDisplayRouterConnect(writer http.ResponseWriter, request *http.Request) {
+	var input displayRouterConnectInput
+	if err := decodeJSON(request, &input); err != nil {
+		writeError(writer, http.StatusBadRequest, err)
+		return
+	}
+
+	sinkNodeValue, sinkPeripheralValue := input.DisplaySink.address()
+
+	_, sinkClient, err := gateway.resolvePeripheral(request.Context(), sinkNodeValue, sinkPeripheralValue)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	displaySink, err := gateway.asDisplaySink(sinkClient)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	if err := gateway.connectDisplaySource(request, displaySink, input.DisplaySource); err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (gateway *Gateway) handleDisplayRouterDisconnect(writer http.ResponseWriter, request *http.Request) {
+	var input displayRouterDisconnectInput
+	if err := decodeJSON(request, &input); err != nil {
+		writeError(writer, http.StatusBadRequest, err)
+		return
+	}
+
+	sinkNodeValue, sinkPeripheralValue := input.DisplaySink.address()
+
+	_, sinkClient, err := gateway.resolvePeripheral(request.Context(), sinkNodeValue, sinkPeripheralValue)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	displaySink, err := gateway.asDisplaySink(sinkClient)
+	if err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	if err := displaySink.ClearDisplayFrameBufferProvider(); err != nil {
+		writeServiceError(writer, err)
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
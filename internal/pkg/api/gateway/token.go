@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+)
+
+// ReadTokenFile reads bearer tokens from file, one token per line. Empty lines and lines starting with '#' are
+// skipped.
+func ReadTokenFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open token file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var tokens []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		tokens = append(tokens, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read token file: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// ValidateListenAddress checks that gateway exposed beyond loopback interface is protected by tokens.
+func ValidateListenAddress(address string, tokens []string) error {
+	if len(tokens) > 0 {
+		return nil
+	}
+
+	loopback, err := utils.IsLoopbackAddress(address)
+	if err != nil {
+		return fmt.Errorf("parse listen address: %w", err)
+	}
+
+	if loopback {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrTokenRequired, address)
+}
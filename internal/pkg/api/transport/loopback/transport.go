@@ -0,0 +1,90 @@
+package loopback
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"time"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+)
+
+type TransportOpt func(*Transport)
+
+func WithTransportServices(services ...nodeSDK.Service) TransportOpt {
+	return func(transport *Transport) {
+		for _, service := range services {
+			transport.services[service.GetServiceId()] = service
+		}
+	}
+}
+
+func WithTransportLogger(logger *slog.Logger) TransportOpt {
+	return func(transport *Transport) {
+		transport.logger = logger
+	}
+}
+
+// Transport handles streams opened to the local node by in-process services and passes streams to other nodes to
+// the wrapped transport. It lets components which address services by node id, like HTTP gateway, reach services of
+// the node they run in, which p2p transport cannot dial.
+type Transport struct {
+	transport apiSDK.Transport
+	services  map[nodeSDK.ServiceId]nodeSDK.Service
+	logger    *slog.Logger
+}
+
+var _ apiSDK.Transport = (*Transport)(nil)
+
+func NewTransport(transport apiSDK.Transport, opts ...TransportOpt) *Transport {
+	loopbackTransport := &Transport{
+		transport: transport,
+		services:  make(map[nodeSDK.ServiceId]nodeSDK.Service),
+		logger:    slog.New(slog.DiscardHandler),
+	}
+
+	for _, opt := range opts {
+		opt(loopbackTransport)
+	}
+
+	return loopbackTransport
+}
+
+func (transport *Transport) GetLocalNodeId() nodeSDK.NodeId {
+	return transport.transport.GetLocalNodeId()
+}
+
+func (transport *Transport) OpenServiceStream(ctx context.Context, serviceId nodeSDK.ServiceId, nodeId nodeSDK.NodeId) (io.ReadWriteCloser, error) {
+	if nodeId != transport.GetLocalNodeId() {
+		return transport.transport.OpenServiceStream(ctx, serviceId, nodeId)
+	}
+
+	service, found := transport.services[serviceId]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", nodeSDK.ErrServiceNotFound, serviceId)
+	}
+
+	clientStream, serviceStream := net.Pipe()
+
+	go func() {
+		serviceCtx := context.WithValue(context.Background(), "transport", transport)
+		serviceCtx, serviceCtxCancel := context.WithTimeout(serviceCtx, time.Second*10)
+		defer serviceCtxCancel()
+		defer func() {
+			_ = serviceStream.Close()
+		}()
+
+		service.Handle(serviceCtx, serviceStream)
+	}()
+
+	transport.logger.Debug("Local service stream opened.", slog.String("serviceId", string(serviceId)))
+
+	return clientStream, nil
+}
+
+func (transport *Transport) Terminate(ctx context.Context) error {
+	return transport.transport.Terminate(ctx)
+}
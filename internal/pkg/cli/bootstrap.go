@@ -46,6 +46,7 @@ func BootstrapDaemon[CONFIG Config](startHandler DaemonStartHandler[CONFIG], opt
 
 	kongOptions := append([]kong.Option{
 		kong.UsageOnError(),
+		kong.Vars{GatewayListenVar: ""},
 	}, options.kongOptions...)
 
 	var config CONFIG
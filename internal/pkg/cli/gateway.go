@@ -0,0 +1,22 @@
+package cli
+
+// GatewayListenVar names kong variable with default address of the HTTP API gateway, applications serving the
+// gateway on their own set it with kong.Vars.
+const GatewayListenVar = "gatewayListen"
+
+type GatewayConfig struct {
+	Listen    string `help:"Address of the HTTP API gateway, empty disables the gateway." placeholder:"HOST:PORT" default:"${gatewayListen}"`
+	TokenFile string `help:"Path to the file with accepted bearer tokens, one per line. Required when listening on non-loopback address." placeholder:"FILE" type:"path"`
+}
+
+type SupportGatewayConfig interface {
+	GetGatewayConfig() GatewayConfig
+}
+
+type GatewayConfigHelper struct {
+	Gateway GatewayConfig `kong:"embed,prefix='gateway-',group='Gateway configuration'"`
+}
+
+func (helper GatewayConfigHelper) GetGatewayConfig() GatewayConfig {
+	return helper.Gateway
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/api/transport/p2p"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	nodeInternal "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/node"
+	nodeAPI "github.com/szymonpodeszwa/go-kvm-agent/pkg/api/service/node"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+)
+
+// SetupTransport creates transport of the local node with given role and waits until discovery bootstrap window
+// passes. Transport is terminated when the context is done.
+func SetupTransport(ctx context.Context, wg *sync.WaitGroup, config TransportConfig, role nodeSDK.NodeRole, opts ...p2p.TransportOpt) (*p2p.Transport, nodeSDK.Service, error) {
+	logger := slog.Default()
+
+	identity, err := p2p.NewIdentity(config.IdentityPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create identity: %w", err)
+	}
+
+	node := nodeInternal.NewNode(identity.GetId(), nodeInternal.WithNodeRole(role))
+	nodeService := nodeAPI.NewNodeAdapter(node)
+
+	opts = append(opts,
+		p2p.WithTransportServices(nodeService),
+		p2p.WithTransportIdentity(identity),
+		p2p.WithTransportLogger(logger),
+		p2p.WithTransportBindAddress(config.BindAddress),
+	)
+
+	transport, err := p2p.NewTransport(opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create transport: %w", err)
+	}
+
+	discoverer, err := p2p.NewDiscoverer(transport,
+		p2p.WithMulticastDNSDiscovery(),
+		p2p.WithDiscovererLogger(logger),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create discoverer: %w", err)
+	}
+
+	wg.Add(1)
+	go func() {
+		<-ctx.Done()
+
+		if err := discoverer.Terminate(ctx); err != nil {
+			logger.Warn("Discoverer termination failed.", slog.String("error", err.Error()))
+		}
+
+		if err := transport.Terminate(ctx); err != nil {
+			logger.Warn("Transport termination failed.", slog.String("error", err.Error()))
+		}
+
+		wg.Done()
+		logger.Debug("Transport terminated.")
+	}()
+
+	logger.Debug("Waiting for discovery bootstrap finished.")
+	select {
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-time.After(config.DiscoveryBootstrapWindow):
+	}
+
+	logger.Info("Transport ready.", slog.String("localNodeId", string(transport.GetLocalNodeId())))
+
+	return transport, nodeService, nil
+}
+
+// SetupMemoryPool sets heap pool as default memory pool of the process.
+func SetupMemoryPool() error {
+	memoryPool, err := memory.NewHeapPool(1024*1024*16, 32)
+	if err != nil {
+		return fmt.Errorf("create heap pool: %w", err)
+	}
+
+	err = memory.SetDefaultMemoryPool(memoryPool)
+	if err != nil {
+		return fmt.Errorf("set as default: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"sync"
+	"testing"
+)
+
+var testDefaultMemoryPoolOnce sync.Once
+
+// SetupTestDefaultMemoryPool sets heap pool as default memory pool once per test binary, so tests of packages
+// borrowing buffers from the default pool can run in any order.
+func SetupTestDefaultMemoryPool(t testing.TB) {
+	t.Helper()
+
+	var err error
+
+	testDefaultMemoryPoolOnce.Do(func() {
+		var pool *HeapPool
+
+		pool, err = NewHeapPool(1024*1024, 16)
+		if err != nil {
+			return
+		}
+
+		err = SetDefaultMemoryPool(pool)
+	})
+
+	if err != nil {
+		t.Fatalf("setup default memory pool: %v", err)
+	}
+}
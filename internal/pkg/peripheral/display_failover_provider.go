@@ -0,0 +1,319 @@
+package peripheral
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// DisplayFailoverEvent is emitted when failover provider switches the active provider.
+type DisplayFailoverEvent struct {
+	ActiveIndex   int       `json:"activeIndex"`
+	PreviousIndex int       `json:"previousIndex"`
+	Reason        string    `json:"reason"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+type DisplayFailoverProviderOptions struct {
+	failoverAfter  time.Duration
+	failbackAfter  time.Duration
+	probeInterval  time.Duration
+	probeTimeout   time.Duration
+	errorThreshold int
+	clock          func() time.Time
+	logger         *slog.Logger
+}
+
+type DisplayFailoverProviderOpt func(*DisplayFailoverProviderOptions)
+
+func defaultDisplayFailoverProviderOptions() DisplayFailoverProviderOptions {
+	return DisplayFailoverProviderOptions{
+		failoverAfter:  2 * time.Second,
+		failbackAfter:  10 * time.Second,
+		probeInterval:  time.Second,
+		probeTimeout:   time.Second,
+		errorThreshold: 3,
+		clock:          time.Now,
+		logger:         slog.New(slog.DiscardHandler),
+	}
+}
+
+// WithDisplayFailoverProviderFailoverAfter sets how long active provider may deliver stale frames before
+// failover happens.
+func WithDisplayFailoverProviderFailoverAfter(duration time.Duration) DisplayFailoverProviderOpt {
+	return func(options *DisplayFailoverProviderOptions) {
+		options.failoverAfter = duration
+	}
+}
+
+// WithDisplayFailoverProviderFailbackAfter sets how long higher priority provider must stay live before
+// it becomes active again.
+func WithDisplayFailoverProviderFailbackAfter(duration time.Duration) DisplayFailoverProviderOpt {
+	return func(options *DisplayFailoverProviderOptions) {
+		options.failbackAfter = duration
+	}
+}
+
+// WithDisplayFailoverProviderProbeInterval sets how often inactive providers are probed.
+func WithDisplayFailoverProviderProbeInterval(interval time.Duration) DisplayFailoverProviderOpt {
+	return func(options *DisplayFailoverProviderOptions) {
+		options.probeInterval = interval
+	}
+}
+
+// WithDisplayFailoverProviderErrorThreshold sets how many consecutive errors mark provider as offline.
+func WithDisplayFailoverProviderErrorThreshold(threshold int) DisplayFailoverProviderOpt {
+	return func(options *DisplayFailoverProviderOptions) {
+		options.errorThreshold = threshold
+	}
+}
+
+// WithDisplayFailoverProviderClock replaces time source used by the provider.
+func WithDisplayFailoverProviderClock(clock func() time.Time) DisplayFailoverProviderOpt {
+	return func(options *DisplayFailoverProviderOptions) {
+		options.clock = clock
+	}
+}
+
+func WithDisplayFailoverProviderLogger(logger *slog.Logger) DisplayFailoverProviderOpt {
+	return func(options *DisplayFailoverProviderOptions) {
+		options.logger = logger
+	}
+}
+
+type displayFailoverCandidate struct {
+	provider  peripheralSDK.DisplayFrameBufferProvider
+	monitor   *DisplayRouteMonitor
+	liveSince time.Time
+}
+
+// DisplayFailoverProvider wraps ordered list of providers and serves frames from the first healthy one.
+// Inactive providers are probed in background, so the provider fails back to a higher priority provider once it
+// stays live for the configured hysteresis.
+type DisplayFailoverProvider struct {
+	options DisplayFailoverProviderOptions
+
+	candidates  []*displayFailoverCandidate
+	activeIndex int
+	lastProbeAt time.Time
+	lock        sync.Mutex
+	probing     atomic.Bool
+
+	eventsEmitter *utils.EventEmitter[DisplayFailoverEvent]
+}
+
+var _ peripheralSDK.DisplayFrameBufferProvider = (*DisplayFailoverProvider)(nil)
+
+func NewDisplayFailoverProvider(providers []peripheralSDK.DisplayFrameBufferProvider, opts ...DisplayFailoverProviderOpt) (*DisplayFailoverProvider, error) {
+	if len(providers) == 0 {
+		return nil, ErrMissingDisplayFailoverProviders
+	}
+
+	options := defaultDisplayFailoverProviderOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.failbackAfter < 0 {
+		return nil, fmt.Errorf("%w: failback duration must not be negative", ErrInvalidDisplayFailoverProviderConfiguration)
+	}
+
+	if options.probeInterval <= 0 {
+		return nil, fmt.Errorf("%w: probe interval must be greater than zero", ErrInvalidDisplayFailoverProviderConfiguration)
+	}
+
+	now := options.clock()
+
+	candidates := make([]*displayFailoverCandidate, 0, len(providers))
+	for index, provider := range providers {
+		monitor, err := NewDisplayRouteMonitor(
+			WithDisplayRouteMonitorStaleFrameTimeout(options.failoverAfter),
+			WithDisplayRouteMonitorErrorThreshold(options.errorThreshold),
+			WithDisplayRouteMonitorClock(options.clock),
+			WithDisplayRouteMonitorLogger(options.logger.With(slog.Int("providerIndex", index))),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create display route monitor: %w", err)
+		}
+
+		monitor.Attach()
+
+		candidates = append(candidates, &displayFailoverCandidate{
+			provider:  provider,
+			monitor:   monitor,
+			liveSince: now,
+		})
+	}
+
+	return &DisplayFailoverProvider{
+		options:       options,
+		candidates:    candidates,
+		activeIndex:   0,
+		lastProbeAt:   now,
+		lock:          sync.Mutex{},
+		eventsEmitter: utils.NewEventEmitter[DisplayFailoverEvent](utils.WithEventEmitterQueueSize[DisplayFailoverEvent](16)),
+	}, nil
+}
+
+// Listen returns channel with failover events. Channel is closed when context is done.
+func (provider *DisplayFailoverProvider) Listen(ctx context.Context) <-chan DisplayFailoverEvent {
+	return provider.eventsEmitter.Listen(ctx)
+}
+
+// GetActiveIndex returns index of provider which currently serves frames.
+func (provider *DisplayFailoverProvider) GetActiveIndex() int {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+
+	return provider.activeIndex
+}
+
+func (provider *DisplayFailoverProvider) GetDisplayFrameBuffer(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+	provider.lock.Lock()
+	active := provider.candidates[provider.activeIndex]
+	provider.lock.Unlock()
+
+	frameBuffer, err := active.provider.GetDisplayFrameBuffer(ctx)
+
+	provider.lock.Lock()
+	now := provider.options.clock()
+	provider.observe(active, frameBuffer, err, now)
+	provider.evaluate(now)
+
+	shouldProbe := len(provider.candidates) > 1 && now.Sub(provider.lastProbeAt) >= provider.options.probeInterval
+	if shouldProbe {
+		provider.lastProbeAt = now
+	}
+	provider.lock.Unlock()
+
+	if shouldProbe && provider.probing.CompareAndSwap(false, true) {
+		go provider.probe(context.WithoutCancel(ctx))
+	}
+
+	return frameBuffer, err
+}
+
+func (provider *DisplayFailoverProvider) GetDisplayMode(ctx context.Context) (*peripheralSDK.DisplayMode, error) {
+	return provider.getActiveProvider().GetDisplayMode(ctx)
+}
+
+func (provider *DisplayFailoverProvider) GetDisplayPixelFormat(ctx context.Context) (*peripheralSDK.DisplayPixelFormat, error) {
+	return provider.getActiveProvider().GetDisplayPixelFormat(ctx)
+}
+
+func (provider *DisplayFailoverProvider) getActiveProvider() peripheralSDK.DisplayFrameBufferProvider {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+
+	return provider.candidates[provider.activeIndex].provider
+}
+
+func (provider *DisplayFailoverProvider) probe(ctx context.Context) {
+	defer provider.probing.Store(false)
+
+	provider.lock.Lock()
+	activeIndex := provider.activeIndex
+	provider.lock.Unlock()
+
+	for index, candidate := range provider.candidates {
+		if index == activeIndex {
+			continue
+		}
+
+		probeCtx, probeCancel := context.WithTimeout(ctx, provider.options.probeTimeout)
+		frameBuffer, err := candidate.provider.GetDisplayFrameBuffer(probeCtx)
+		probeCancel()
+
+		provider.lock.Lock()
+		provider.observe(candidate, frameBuffer, err, provider.options.clock())
+		provider.lock.Unlock()
+
+		if err == nil {
+			if releaseErr := frameBuffer.Release(); releaseErr != nil {
+				provider.options.logger.Warn("Failed to release probed frame buffer.", slog.String("error", releaseErr.Error()))
+			}
+		}
+	}
+
+	provider.lock.Lock()
+	provider.evaluate(provider.options.clock())
+	provider.lock.Unlock()
+}
+
+func (provider *DisplayFailoverProvider) observe(candidate *displayFailoverCandidate, frameBuffer *peripheralSDK.DisplayFrameBuffer, err error, now time.Time) {
+	if err != nil {
+		candidate.monitor.ObserveError(err)
+	} else {
+		candidate.monitor.ObserveFrame(frameBuffer)
+	}
+
+	state, _ := candidate.monitor.GetState()
+	if state != DisplayRouteStateLive {
+		candidate.liveSince = time.Time{}
+		return
+	}
+
+	if candidate.liveSince.IsZero() {
+		candidate.liveSince = now
+	}
+}
+
+// evaluate selects the best candidate. It must be called with lock held.
+func (provider *DisplayFailoverProvider) evaluate(now time.Time) {
+	activeState, activeReason := provider.candidates[provider.activeIndex].monitor.GetState()
+	activeLive := activeState == DisplayRouteStateLive
+
+	for index, candidate := range provider.candidates {
+		if state, _ := candidate.monitor.GetState(); state != DisplayRouteStateLive {
+			continue
+		}
+
+		if index == provider.activeIndex {
+			return
+		}
+
+		if activeLive && now.Sub(candidate.liveSince) < provider.options.failbackAfter {
+			continue
+		}
+
+		reason := fmt.Sprintf("provider %d is %s: %s", provider.activeIndex, activeState, activeReason)
+		if activeLive {
+			reason = fmt.Sprintf("provider %d recovered", index)
+		}
+
+		provider.switchTo(index, reason, now)
+
+		return
+	}
+}
+
+func (provider *DisplayFailoverProvider) switchTo(index int, reason string, now time.Time) {
+	event := DisplayFailoverEvent{
+		ActiveIndex:   index,
+		PreviousIndex: provider.activeIndex,
+		Reason:        reason,
+		Timestamp:     now,
+	}
+
+	provider.activeIndex = index
+
+	provider.options.logger.Info("Switched active display frame buffer provider.",
+		slog.Int("activeIndex", event.ActiveIndex),
+		slog.Int("previousIndex", event.PreviousIndex),
+		slog.String("reason", reason),
+	)
+
+	provider.eventsEmitter.Emit(event)
+}
+
+var (
+	ErrMissingDisplayFailoverProviders             = errors.New("missing display failover providers")
+	ErrInvalidDisplayFailoverProviderConfiguration = errors.New("invalid display failover provider configuration")
+)
@@ -0,0 +1,184 @@
+package peripheral
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// testFrameBufferProvider delivers frames with increasing sequence until err is set.
+type testFrameBufferProvider struct {
+	*peripheralSDK.DisplayFrameBufferProviderMock
+
+	sequence    uint64
+	err         error
+	displayMode peripheralSDK.DisplayMode
+}
+
+func newTestFrameBufferProvider(t *testing.T, displayMode peripheralSDK.DisplayMode, err error) *testFrameBufferProvider {
+	provider := &testFrameBufferProvider{
+		DisplayFrameBufferProviderMock: peripheralSDK.NewDisplayFrameBufferProviderMock(t),
+		err:                            err,
+		displayMode:                    displayMode,
+	}
+
+	provider.EXPECT().GetDisplayFrameBuffer(mock.Anything).RunAndReturn(func(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+		if provider.err != nil {
+			return nil, provider.err
+		}
+
+		provider.sequence++
+
+		return newTestFrameBuffer(t, provider.sequence), nil
+	}).Maybe()
+	provider.EXPECT().GetDisplayMode(mock.Anything).RunAndReturn(func(ctx context.Context) (*peripheralSDK.DisplayMode, error) {
+		return &provider.displayMode, nil
+	}).Maybe()
+
+	return provider
+}
+
+func newTestDisplayFailoverProvider(t *testing.T, clock *fakeClock, providers ...peripheralSDK.DisplayFrameBufferProvider) *DisplayFailoverProvider {
+	failoverProvider, err := NewDisplayFailoverProvider(providers,
+		WithDisplayFailoverProviderClock(clock.Now),
+		WithDisplayFailoverProviderFailoverAfter(time.Second),
+		WithDisplayFailoverProviderFailbackAfter(5*time.Second),
+		WithDisplayFailoverProviderProbeInterval(time.Hour),
+		WithDisplayFailoverProviderErrorThreshold(2),
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return failoverProvider
+}
+
+func TestNewDisplayFailoverProviderRequiresProviders(t *testing.T) {
+	_, err := NewDisplayFailoverProvider(nil)
+	assert.ErrorIs(t, err, ErrMissingDisplayFailoverProviders)
+}
+
+func TestNewDisplayFailoverProviderRejectsInvalidConfiguration(t *testing.T) {
+	providers := []peripheralSDK.DisplayFrameBufferProvider{peripheralSDK.NewDisplayFrameBufferProviderMock(t)}
+
+	_, err := NewDisplayFailoverProvider(providers, WithDisplayFailoverProviderProbeInterval(0))
+	assert.ErrorIs(t, err, ErrInvalidDisplayFailoverProviderConfiguration)
+
+	_, err = NewDisplayFailoverProvider(providers, WithDisplayFailoverProviderFailbackAfter(-time.Second))
+	assert.ErrorIs(t, err, ErrInvalidDisplayFailoverProviderConfiguration)
+}
+
+func TestDisplayFailoverProviderFailsOverOnErrors(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	primary := newTestFrameBufferProvider(t, peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 60}, nil)
+	secondary := newTestFrameBufferProvider(t, peripheralSDK.DisplayMode{Width: 1280, Height: 720, RefreshRate: 30}, nil)
+
+	failoverProvider := newTestDisplayFailoverProvider(t, clock, primary, secondary)
+
+	events := failoverProvider.Listen(ctx)
+
+	frameBuffer, err := failoverProvider.GetDisplayFrameBuffer(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), frameBuffer.GetSequence())
+	assert.Equal(t, 0, failoverProvider.GetActiveIndex())
+
+	primary.err = errors.New("node detached")
+
+	_, err = failoverProvider.GetDisplayFrameBuffer(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 0, failoverProvider.GetActiveIndex())
+
+	_, err = failoverProvider.GetDisplayFrameBuffer(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 1, failoverProvider.GetActiveIndex())
+
+	select {
+	case event := <-events:
+		assert.Equal(t, 1, event.ActiveIndex)
+		assert.Equal(t, 0, event.PreviousIndex)
+		assert.Contains(t, event.Reason, "node detached")
+	case <-time.After(time.Second):
+		t.Fatal("expected failover event")
+	}
+
+	displayMode, err := failoverProvider.GetDisplayMode(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, secondary.displayMode, *displayMode)
+
+	frameBuffer, err = failoverProvider.GetDisplayFrameBuffer(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), frameBuffer.GetSequence())
+}
+
+func TestDisplayFailoverProviderFailsBackWithHysteresis(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	primary := newTestFrameBufferProvider(t, peripheralSDK.DisplayMode{}, errors.New("node detached"))
+	secondary := newTestFrameBufferProvider(t, peripheralSDK.DisplayMode{}, nil)
+
+	failoverProvider := newTestDisplayFailoverProvider(t, clock, primary, secondary)
+
+	_, _ = failoverProvider.GetDisplayFrameBuffer(ctx)
+	_, _ = failoverProvider.GetDisplayFrameBuffer(ctx)
+	assert.Equal(t, 1, failoverProvider.GetActiveIndex())
+
+	primary.err = nil
+
+	clock.Advance(time.Second)
+	failoverProvider.probe(ctx)
+	assert.Equal(t, 1, failoverProvider.GetActiveIndex())
+
+	clock.Advance(3 * time.Second)
+	failoverProvider.probe(ctx)
+	_, err := failoverProvider.GetDisplayFrameBuffer(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, failoverProvider.GetActiveIndex())
+
+	clock.Advance(3 * time.Second)
+	failoverProvider.probe(ctx)
+	assert.Equal(t, 0, failoverProvider.GetActiveIndex())
+}
+
+func TestDisplayFailoverProviderFailsOverOnStaleFrames(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	primary := newTestFrameBufferProvider(t, peripheralSDK.DisplayMode{}, peripheralSDK.ErrDisplayFrameBufferNotReady)
+	secondary := newTestFrameBufferProvider(t, peripheralSDK.DisplayMode{}, nil)
+
+	failoverProvider := newTestDisplayFailoverProvider(t, clock, primary, secondary)
+
+	_, err := failoverProvider.GetDisplayFrameBuffer(ctx)
+	assert.ErrorIs(t, err, peripheralSDK.ErrDisplayFrameBufferNotReady)
+	assert.Equal(t, 0, failoverProvider.GetActiveIndex())
+
+	clock.Advance(2 * time.Second)
+	_, _ = failoverProvider.GetDisplayFrameBuffer(ctx)
+	assert.Equal(t, 1, failoverProvider.GetActiveIndex())
+}
+
+func TestDisplayFailoverProviderStaysOnActiveWhenAllDown(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	primary := newTestFrameBufferProvider(t, peripheralSDK.DisplayMode{}, errors.New("primary down"))
+	secondary := newTestFrameBufferProvider(t, peripheralSDK.DisplayMode{}, errors.New("secondary down"))
+
+	failoverProvider := newTestDisplayFailoverProvider(t, clock, primary, secondary)
+
+	failoverProvider.probe(ctx)
+	failoverProvider.probe(ctx)
+
+	_, _ = failoverProvider.GetDisplayFrameBuffer(ctx)
+	_, err := failoverProvider.GetDisplayFrameBuffer(ctx)
+	assert.ErrorContains(t, err, "primary down")
+	assert.Equal(t, 0, failoverProvider.GetActiveIndex())
+}
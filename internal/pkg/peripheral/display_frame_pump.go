@@ -0,0 +1,368 @@
+package peripheral
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// DisplayFrame is a frame read from display frame buffer provider together with display mode matching its size. Data
+// is valid only until the handler returns.
+type DisplayFrame struct {
+	Data        []byte
+	DisplayMode peripheralSDK.DisplayMode
+	Sequence    uint64
+	Timestamp   time.Time
+}
+
+// DisplayFrameHandler consumes frame read by DisplayFramePump. Frame is considered seen only when nil is returned.
+type DisplayFrameHandler func(frame DisplayFrame) error
+
+type DisplayFramePumpOptions struct {
+	maxFrameRate uint32
+	oversampling uint32
+	condition    func() bool
+	errorHandler func(err error)
+	routeMonitor *DisplayRouteMonitor
+	logger       *slog.Logger
+}
+
+type DisplayFramePumpOpt func(*DisplayFramePumpOptions)
+
+func defaultDisplayFramePumpOptions() DisplayFramePumpOptions {
+	return DisplayFramePumpOptions{
+		maxFrameRate: 0,
+		oversampling: 1,
+		condition:    nil,
+		errorHandler: nil,
+		routeMonitor: nil,
+		logger:       slog.New(slog.DiscardHandler),
+	}
+}
+
+// WithDisplayFramePumpMaxFrameRate limits how many times per second frames are read. Zero follows refresh rate of the
+// provider.
+func WithDisplayFramePumpMaxFrameRate(frameRate uint32) DisplayFramePumpOpt {
+	return func(options *DisplayFramePumpOptions) {
+		options.maxFrameRate = frameRate
+	}
+}
+
+// WithDisplayFramePumpOversampling sets how many times per frame of the provider refresh rate the provider is asked
+// for a new frame, so frames are not missed when the source is not in phase with the pump.
+func WithDisplayFramePumpOversampling(factor uint32) DisplayFramePumpOpt {
+	return func(options *DisplayFramePumpOptions) {
+		options.oversampling = factor
+	}
+}
+
+// WithDisplayFramePumpCondition sets condition checked before every read, frames are not read while it is false.
+func WithDisplayFramePumpCondition(condition func() bool) DisplayFramePumpOpt {
+	return func(options *DisplayFramePumpOptions) {
+		options.condition = condition
+	}
+}
+
+// WithDisplayFramePumpErrorHandler sets function called with errors of pumped frames, they are logged when not set.
+func WithDisplayFramePumpErrorHandler(errorHandler func(err error)) DisplayFramePumpOpt {
+	return func(options *DisplayFramePumpOptions) {
+		options.errorHandler = errorHandler
+	}
+}
+
+// WithDisplayFramePumpRouteMonitor sets monitor observing every frame and error of the attached provider, also frames
+// which were already seen, so the route state follows the pumped provider.
+func WithDisplayFramePumpRouteMonitor(routeMonitor *DisplayRouteMonitor) DisplayFramePumpOpt {
+	return func(options *DisplayFramePumpOptions) {
+		options.routeMonitor = routeMonitor
+	}
+}
+
+func WithDisplayFramePumpLogger(logger *slog.Logger) DisplayFramePumpOpt {
+	return func(options *DisplayFramePumpOptions) {
+		options.logger = logger
+	}
+}
+
+// DisplayFramePump reads frames of the provider attached to a display sink at the refresh rate of the provider and
+// passes frames with new sequence (or timestamp) to the handler. Display mode of a frame is resolved by its size, the
+// provider is asked again when the size does not match the last known display mode, so sinks follow display mode
+// changes of the source, e.g. when failover provider switches sources.
+type DisplayFramePump struct {
+	options DisplayFramePumpOptions
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	handler DisplayFrameHandler
+
+	ticker *time.Ticker
+
+	provider     peripheralSDK.DisplayFrameBufferProvider
+	displayMode  peripheralSDK.DisplayMode
+	providerLock sync.RWMutex
+
+	lastSequence  uint64
+	lastTimestamp time.Time
+	readBuffer    bytes.Buffer
+	readLock      sync.Mutex
+}
+
+// NewDisplayFramePump creates pump running until the context is done or it is stopped.
+func NewDisplayFramePump(ctx context.Context, handler DisplayFrameHandler, opts ...DisplayFramePumpOpt) *DisplayFramePump {
+	options := defaultDisplayFramePumpOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	pumpCtx, pumpCancel := context.WithCancel(ctx)
+
+	pump := &DisplayFramePump{
+		options: options,
+		ctx:     pumpCtx,
+		cancel:  pumpCancel,
+		done:    make(chan struct{}),
+		handler: handler,
+
+		ticker: time.NewTicker(time.Second),
+
+		providerLock: sync.RWMutex{},
+		readLock:     sync.Mutex{},
+	}
+
+	go pump.run()
+
+	return pump
+}
+
+// Attach starts pumping frames of the provider and returns its display mode. Provider must deliver RGB24 frames.
+func (pump *DisplayFramePump) Attach(provider peripheralSDK.DisplayFrameBufferProvider) (peripheralSDK.DisplayMode, error) {
+	providerDisplayMode, err := provider.GetDisplayMode(pump.ctx)
+	if err != nil {
+		return peripheralSDK.DisplayMode{}, fmt.Errorf("get display mode from provider: %w", err)
+	}
+
+	err = providerDisplayMode.Valid()
+	if err != nil {
+		return peripheralSDK.DisplayMode{}, fmt.Errorf("invalid display mode: %w", err)
+	}
+
+	pixelFormat, err := provider.GetDisplayPixelFormat(pump.ctx)
+	if err != nil {
+		return peripheralSDK.DisplayMode{}, fmt.Errorf("get display pixel format: %w", err)
+	}
+
+	if *pixelFormat != peripheralSDK.DisplayPixelFormatRGB24 {
+		return peripheralSDK.DisplayMode{}, ErrDisplayPixelFormatUnsupported
+	}
+
+	pump.providerLock.Lock()
+	pump.provider = provider
+	pump.displayMode = *providerDisplayMode
+	pump.providerLock.Unlock()
+
+	frameRate := providerDisplayMode.RefreshRate
+	if pump.options.maxFrameRate > 0 && pump.options.maxFrameRate < frameRate {
+		frameRate = pump.options.maxFrameRate
+	}
+
+	pump.ticker.Reset(time.Second / time.Duration(frameRate*max(pump.options.oversampling, 1)))
+
+	if pump.options.routeMonitor != nil {
+		pump.options.routeMonitor.Attach()
+	}
+
+	return *providerDisplayMode, nil
+}
+
+// Detach stops pumping frames of the attached provider.
+func (pump *DisplayFramePump) Detach() {
+	pump.providerLock.Lock()
+	pump.provider = nil
+	pump.providerLock.Unlock()
+
+	if pump.options.routeMonitor != nil {
+		pump.options.routeMonitor.Detach()
+	}
+}
+
+// GetDisplayMode returns display mode of the last frame of the attached provider, nil when no provider is attached.
+func (pump *DisplayFramePump) GetDisplayMode() *peripheralSDK.DisplayMode {
+	pump.providerLock.RLock()
+	defer pump.providerLock.RUnlock()
+
+	if pump.provider == nil {
+		return nil
+	}
+
+	displayMode := pump.displayMode
+
+	return &displayMode
+}
+
+// ReadFrame passes the current frame of the attached provider to the handler, even when it was already seen. It fails
+// with ErrDisplayFrameBufferProviderMissing when no provider is attached. Handlers are never called concurrently, also
+// with the pumped frames.
+func (pump *DisplayFramePump) ReadFrame(handler DisplayFrameHandler) error {
+	return pump.readFrame(false, handler)
+}
+
+// Stop stops pumping frames and waits until the handler returns, so resources used by the handler can be released
+// afterwards. Frames are not passed to handlers once it returns, ReadFrame fails with ErrDisplayFramePumpStopped.
+func (pump *DisplayFramePump) Stop() {
+	pump.cancel()
+	<-pump.done
+
+	// frame read by ReadFrame may be still handled
+	pump.readLock.Lock()
+	pump.readLock.Unlock()
+}
+
+func (pump *DisplayFramePump) run() {
+	defer close(pump.done)
+	defer pump.ticker.Stop()
+
+	done := pump.ctx.Done()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-pump.ticker.C:
+			if pump.options.condition != nil && !pump.options.condition() {
+				continue
+			}
+
+			err := pump.readFrame(true, pump.handler)
+			if errors.Is(err, ErrDisplayFrameBufferProviderMissing) || errors.Is(err, peripheralSDK.ErrDisplayFrameBufferNotReady) {
+				continue
+			}
+			if err == nil {
+				continue
+			}
+
+			if pump.options.errorHandler != nil {
+				pump.options.errorHandler(err)
+			} else {
+				pump.options.logger.Warn("Failed to pump frame from provider.", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+func (pump *DisplayFramePump) readFrame(skipSeen bool, handler DisplayFrameHandler) error {
+	pump.providerLock.RLock()
+	provider := pump.provider
+	pump.providerLock.RUnlock()
+
+	if provider == nil {
+		return ErrDisplayFrameBufferProviderMissing
+	}
+
+	frameBuffer, err := provider.GetDisplayFrameBuffer(pump.ctx)
+	if err != nil {
+		if pump.options.routeMonitor != nil {
+			pump.options.routeMonitor.ObserveError(err)
+		}
+
+		return fmt.Errorf("get frame buffer from provider: %w", err)
+	}
+
+	if pump.options.routeMonitor != nil {
+		pump.options.routeMonitor.ObserveFrame(frameBuffer)
+	}
+
+	defer func() {
+		err := frameBuffer.Release()
+		if err != nil {
+			pump.options.logger.Warn("Failed to release frame buffer.", slog.String("error", err.Error()))
+		}
+	}()
+
+	pump.readLock.Lock()
+	defer pump.readLock.Unlock()
+
+	if pump.ctx.Err() != nil {
+		return ErrDisplayFramePumpStopped
+	}
+
+	sequence := frameBuffer.GetSequence()
+	timestamp := frameBuffer.GetTimestamp()
+
+	if skipSeen && sequence == pump.lastSequence && timestamp.Equal(pump.lastTimestamp) {
+		return nil
+	}
+
+	pump.readBuffer.Reset()
+	if _, err := frameBuffer.WriteTo(&pump.readBuffer); err != nil {
+		return fmt.Errorf("read frame buffer: %w", err)
+	}
+
+	displayMode, err := pump.getFrameDisplayMode(provider, pump.readBuffer.Len())
+	if err != nil {
+		return err
+	}
+
+	err = handler(DisplayFrame{
+		Data:        pump.readBuffer.Bytes(),
+		DisplayMode: displayMode,
+		Sequence:    sequence,
+		Timestamp:   timestamp,
+	})
+	if err != nil {
+		return err
+	}
+
+	if skipSeen {
+		pump.lastSequence = sequence
+		pump.lastTimestamp = timestamp
+	}
+
+	return nil
+}
+
+// getFrameDisplayMode returns display mode matching the frame. Provider is asked again when the frame size does
+// not match the last known display mode.
+func (pump *DisplayFramePump) getFrameDisplayMode(provider peripheralSDK.DisplayFrameBufferProvider, frameSize int) (peripheralSDK.DisplayMode, error) {
+	pump.providerLock.RLock()
+	displayMode := pump.displayMode
+	pump.providerLock.RUnlock()
+
+	if DisplayModeFrameSize(displayMode) == frameSize {
+		return displayMode, nil
+	}
+
+	providerDisplayMode, err := provider.GetDisplayMode(pump.ctx)
+	if err != nil {
+		return peripheralSDK.DisplayMode{}, fmt.Errorf("get display mode from provider: %w", err)
+	}
+
+	if DisplayModeFrameSize(*providerDisplayMode) != frameSize {
+		return peripheralSDK.DisplayMode{}, fmt.Errorf("%w: %d bytes for %s", ErrDisplayFrameSizeMismatch, frameSize, providerDisplayMode.String())
+	}
+
+	pump.providerLock.Lock()
+	if pump.provider == provider {
+		pump.displayMode = *providerDisplayMode
+	}
+	pump.providerLock.Unlock()
+
+	return *providerDisplayMode, nil
+}
+
+// DisplayModeFrameSize returns size of RGB24 frame of the display mode.
+func DisplayModeFrameSize(displayMode peripheralSDK.DisplayMode) int {
+	return int(displayMode.Width*displayMode.Height) * peripheralSDK.DisplayPixelFormatRGB24.BytesPerPixel()
+}
+
+var (
+	ErrDisplayPixelFormatUnsupported     = errors.New("display pixel format unsupported")
+	ErrDisplayFrameSizeMismatch          = errors.New("display frame size does not match display mode")
+	ErrDisplayFrameBufferProviderMissing = errors.New("display frame buffer provider missing")
+	ErrDisplayFramePumpStopped           = errors.New("display frame pump stopped")
+)
@@ -0,0 +1,231 @@
+package peripheral
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// testFrameProvider delivers frames sized for its frame display mode, which may differ from the reported one.
+type testFrameProvider struct {
+	*peripheralSDK.DisplayFrameBufferProviderMock
+
+	lock             sync.Mutex
+	sequence         uint64
+	displayMode      peripheralSDK.DisplayMode
+	frameDisplayMode peripheralSDK.DisplayMode
+}
+
+func newTestFrameProvider(t *testing.T, displayMode peripheralSDK.DisplayMode) *testFrameProvider {
+	provider := &testFrameProvider{
+		DisplayFrameBufferProviderMock: peripheralSDK.NewDisplayFrameBufferProviderMock(t),
+		sequence:                       1,
+		displayMode:                    displayMode,
+		frameDisplayMode:               displayMode,
+	}
+
+	pixelFormat := peripheralSDK.DisplayPixelFormatRGB24
+
+	provider.EXPECT().GetDisplayFrameBuffer(mock.Anything).RunAndReturn(func(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+		provider.lock.Lock()
+		defer provider.lock.Unlock()
+
+		size := DisplayModeFrameSize(provider.frameDisplayMode)
+
+		pool, err := memory.NewHeapPool(size, 1)
+		require.NoError(t, err)
+
+		buffer, err := pool.Borrow(size)
+		require.NoError(t, err)
+
+		_, err = buffer.ReadFrom(bytes.NewReader(make([]byte, size)))
+		require.NoError(t, err)
+
+		return peripheralSDK.NewDisplayFrameBuffer(buffer,
+			peripheralSDK.WithDisplayFrameBufferSequence(provider.sequence),
+			peripheralSDK.WithDisplayFrameBufferTimestamp(time.Unix(int64(provider.sequence), 0)),
+		), nil
+	}).Maybe()
+	provider.EXPECT().GetDisplayMode(mock.Anything).RunAndReturn(func(ctx context.Context) (*peripheralSDK.DisplayMode, error) {
+		provider.lock.Lock()
+		defer provider.lock.Unlock()
+
+		displayMode := provider.displayMode
+
+		return &displayMode, nil
+	}).Maybe()
+	provider.EXPECT().GetDisplayPixelFormat(mock.Anything).Return(&pixelFormat, nil).Maybe()
+
+	return provider
+}
+
+func (provider *testFrameProvider) update(updateFn func(provider *testFrameProvider)) {
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+
+	updateFn(provider)
+}
+
+func TestDisplayFramePumpAttachRejectsUnsupportedPixelFormat(t *testing.T) {
+	pump := NewDisplayFramePump(t.Context(), func(frame DisplayFrame) error { return nil })
+
+	displayMode := peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 30}
+	pixelFormat := peripheralSDK.DisplayPixelFormat("yuyv")
+
+	provider := peripheralSDK.NewDisplayFrameBufferProviderMock(t)
+	provider.EXPECT().GetDisplayMode(mock.Anything).Return(&displayMode, nil)
+	provider.EXPECT().GetDisplayPixelFormat(mock.Anything).Return(&pixelFormat, nil)
+
+	_, err := pump.Attach(provider)
+	assert.ErrorIs(t, err, ErrDisplayPixelFormatUnsupported)
+	assert.Nil(t, pump.GetDisplayMode())
+
+	err = pump.ReadFrame(func(frame DisplayFrame) error { return nil })
+	assert.ErrorIs(t, err, ErrDisplayFrameBufferProviderMissing)
+}
+
+func TestDisplayFramePumpPassesOnlyNewFrames(t *testing.T) {
+	frames := atomic.Int64{}
+
+	pump := NewDisplayFramePump(t.Context(), func(frame DisplayFrame) error {
+		frames.Add(1)
+		return nil
+	})
+
+	provider := newTestFrameProvider(t, peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 200})
+
+	displayMode, err := pump.Attach(provider)
+	require.NoError(t, err)
+	assert.Equal(t, peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 200}, displayMode)
+
+	assert.Eventually(t, func() bool { return frames.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int64(1), frames.Load())
+
+	provider.update(func(provider *testFrameProvider) {
+		provider.sequence++
+	})
+
+	assert.Eventually(t, func() bool { return frames.Load() == 2 }, time.Second, time.Millisecond)
+
+	pump.Detach()
+	assert.Nil(t, pump.GetDisplayMode())
+}
+
+func TestDisplayFramePumpFollowsDisplayModeOfFrames(t *testing.T) {
+	pump := NewDisplayFramePump(t.Context(), func(frame DisplayFrame) error { return nil })
+
+	provider := newTestFrameProvider(t, peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 1})
+
+	_, err := pump.Attach(provider)
+	require.NoError(t, err)
+
+	provider.update(func(provider *testFrameProvider) {
+		provider.displayMode = peripheralSDK.DisplayMode{Width: 8, Height: 4, RefreshRate: 1}
+		provider.frameDisplayMode = provider.displayMode
+	})
+
+	var frame DisplayFrame
+	err = pump.ReadFrame(func(readFrame DisplayFrame) error {
+		frame = readFrame
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, peripheralSDK.DisplayMode{Width: 8, Height: 4, RefreshRate: 1}, frame.DisplayMode)
+	assert.Len(t, frame.Data, 8*4*3)
+	assert.Equal(t, &peripheralSDK.DisplayMode{Width: 8, Height: 4, RefreshRate: 1}, pump.GetDisplayMode())
+
+	provider.update(func(provider *testFrameProvider) {
+		provider.frameDisplayMode = peripheralSDK.DisplayMode{Width: 2, Height: 2, RefreshRate: 1}
+	})
+
+	err = pump.ReadFrame(func(readFrame DisplayFrame) error { return nil })
+	assert.ErrorIs(t, err, ErrDisplayFrameSizeMismatch)
+}
+
+func TestDisplayFramePumpStopWaitsForHandler(t *testing.T) {
+	handled := make(chan struct{}, 1)
+	handling := atomic.Bool{}
+	frames := atomic.Int64{}
+
+	pump := NewDisplayFramePump(t.Context(), func(frame DisplayFrame) error {
+		handling.Store(true)
+		defer handling.Store(false)
+
+		select {
+		case handled <- struct{}{}:
+		default:
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		frames.Add(1)
+
+		return nil
+	})
+
+	provider := newTestFrameProvider(t, peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 200})
+
+	_, err := pump.Attach(provider)
+	require.NoError(t, err)
+
+	<-handled
+	pump.Stop()
+	assert.False(t, handling.Load())
+
+	handledFrames := frames.Load()
+
+	provider.update(func(provider *testFrameProvider) {
+		provider.sequence++
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, handledFrames, frames.Load())
+
+	err = pump.ReadFrame(func(frame DisplayFrame) error { return nil })
+	assert.ErrorIs(t, err, ErrDisplayFramePumpStopped)
+}
+
+func TestDisplayFramePumpFeedsRouteMonitor(t *testing.T) {
+	routeMonitor, err := NewDisplayRouteMonitor(WithDisplayRouteMonitorStaleFrameTimeout(20 * time.Millisecond))
+	require.NoError(t, err)
+
+	pump := NewDisplayFramePump(t.Context(), func(frame DisplayFrame) error { return nil },
+		WithDisplayFramePumpRouteMonitor(routeMonitor),
+	)
+
+	provider := newTestFrameProvider(t, peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 200})
+
+	_, err = pump.Attach(provider)
+	require.NoError(t, err)
+
+	state, _ := routeMonitor.GetState()
+	assert.Equal(t, DisplayRouteStateLive, state)
+
+	assert.Eventually(t, func() bool {
+		state, _ := routeMonitor.GetState()
+		return state == DisplayRouteStateNoSignal
+	}, time.Second, time.Millisecond)
+
+	provider.update(func(provider *testFrameProvider) {
+		provider.sequence++
+	})
+
+	assert.Eventually(t, func() bool {
+		state, _ := routeMonitor.GetState()
+		return state == DisplayRouteStateLive
+	}, time.Second, time.Millisecond)
+
+	pump.Detach()
+
+	state, _ = routeMonitor.GetState()
+	assert.Equal(t, DisplayRouteStateIdle, state)
+}
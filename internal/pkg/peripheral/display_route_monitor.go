@@ -0,0 +1,258 @@
+package peripheral
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// DisplayRouteState describes health of the route between display frame buffer provider and display sink.
+type DisplayRouteState string
+
+const (
+	// DisplayRouteStateUnknown represents an uninitialized or invalid route state.
+	DisplayRouteStateUnknown DisplayRouteState = ""
+	// DisplayRouteStateIdle indicates that no provider is attached to the sink.
+	DisplayRouteStateIdle DisplayRouteState = "idle"
+	// DisplayRouteStateLive indicates that provider delivers fresh frames.
+	DisplayRouteStateLive DisplayRouteState = "live"
+	// DisplayRouteStateNoSignal indicates that provider is reachable but does not deliver new frames.
+	DisplayRouteStateNoSignal DisplayRouteState = "no-signal"
+	// DisplayRouteStateSourceOffline indicates that provider can not be reached.
+	DisplayRouteStateSourceOffline DisplayRouteState = "source-offline"
+)
+
+func (state DisplayRouteState) String() string {
+	return string(state)
+}
+
+// DisplayRouteStateEvent is emitted every time the route changes its state.
+type DisplayRouteStateEvent struct {
+	State         DisplayRouteState `json:"state"`
+	PreviousState DisplayRouteState `json:"previousState"`
+	Reason        string            `json:"reason"`
+	Timestamp     time.Time         `json:"timestamp"`
+}
+
+type DisplayRouteMonitorOptions struct {
+	staleFrameTimeout time.Duration
+	errorThreshold    int
+	clock             func() time.Time
+	logger            *slog.Logger
+}
+
+type DisplayRouteMonitorOpt func(*DisplayRouteMonitorOptions)
+
+func defaultDisplayRouteMonitorOptions() DisplayRouteMonitorOptions {
+	return DisplayRouteMonitorOptions{
+		staleFrameTimeout: 2 * time.Second,
+		errorThreshold:    3,
+		clock:             time.Now,
+		logger:            slog.New(slog.DiscardHandler),
+	}
+}
+
+// WithDisplayRouteMonitorStaleFrameTimeout sets how long provider may deliver the same frame before route is
+// considered to have no signal.
+func WithDisplayRouteMonitorStaleFrameTimeout(timeout time.Duration) DisplayRouteMonitorOpt {
+	return func(options *DisplayRouteMonitorOptions) {
+		options.staleFrameTimeout = timeout
+	}
+}
+
+// WithDisplayRouteMonitorErrorThreshold sets how many consecutive provider errors mark the source as offline.
+func WithDisplayRouteMonitorErrorThreshold(threshold int) DisplayRouteMonitorOpt {
+	return func(options *DisplayRouteMonitorOptions) {
+		options.errorThreshold = threshold
+	}
+}
+
+// WithDisplayRouteMonitorClock replaces time source used by the monitor.
+func WithDisplayRouteMonitorClock(clock func() time.Time) DisplayRouteMonitorOpt {
+	return func(options *DisplayRouteMonitorOptions) {
+		options.clock = clock
+	}
+}
+
+func WithDisplayRouteMonitorLogger(logger *slog.Logger) DisplayRouteMonitorOpt {
+	return func(options *DisplayRouteMonitorOptions) {
+		options.logger = logger
+	}
+}
+
+// DisplayRouteMonitor tracks frames fetched from a display frame buffer provider and decides whether the route
+// is live, stale or offline. Frames are considered fresh when their sequence (or timestamp, for sources that do not
+// track sequence) differs from the previously observed frame.
+type DisplayRouteMonitor struct {
+	options DisplayRouteMonitorOptions
+
+	state              DisplayRouteState
+	reason             string
+	lastSequence       uint64
+	lastTimestamp      time.Time
+	lastProgressAt     time.Time
+	consecutiveErrors  int
+	stateLock          sync.Mutex
+	stateEventsEmitter *utils.EventEmitter[DisplayRouteStateEvent]
+}
+
+func NewDisplayRouteMonitor(opts ...DisplayRouteMonitorOpt) (*DisplayRouteMonitor, error) {
+	options := defaultDisplayRouteMonitorOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.staleFrameTimeout <= 0 {
+		return nil, fmt.Errorf("%w: stale frame timeout must be greater than zero", ErrInvalidDisplayRouteMonitorConfiguration)
+	}
+
+	if options.errorThreshold <= 0 {
+		return nil, fmt.Errorf("%w: error threshold must be greater than zero", ErrInvalidDisplayRouteMonitorConfiguration)
+	}
+
+	return &DisplayRouteMonitor{
+		options:            options,
+		state:              DisplayRouteStateIdle,
+		stateLock:          sync.Mutex{},
+		stateEventsEmitter: utils.NewEventEmitter[DisplayRouteStateEvent](utils.WithEventEmitterQueueSize[DisplayRouteStateEvent](16)),
+	}, nil
+}
+
+// Listen returns channel with route state events. Channel is closed when context is done.
+func (monitor *DisplayRouteMonitor) Listen(ctx context.Context) <-chan DisplayRouteStateEvent {
+	return monitor.stateEventsEmitter.Listen(ctx)
+}
+
+// GetState returns current route state together with reason of the last transition.
+func (monitor *DisplayRouteMonitor) GetState() (DisplayRouteState, string) {
+	monitor.stateLock.Lock()
+	defer monitor.stateLock.Unlock()
+
+	return monitor.state, monitor.reason
+}
+
+// Attach resets the monitor for a newly attached provider. Route is assumed live until proven otherwise.
+func (monitor *DisplayRouteMonitor) Attach() DisplayRouteStateEvent {
+	monitor.stateLock.Lock()
+	defer monitor.stateLock.Unlock()
+
+	monitor.lastSequence = 0
+	monitor.lastTimestamp = time.Time{}
+	monitor.lastProgressAt = monitor.options.clock()
+	monitor.consecutiveErrors = 0
+
+	event, _ := monitor.transition(DisplayRouteStateLive, "provider attached")
+
+	return event
+}
+
+// Detach marks the route as idle.
+func (monitor *DisplayRouteMonitor) Detach() DisplayRouteStateEvent {
+	monitor.stateLock.Lock()
+	defer monitor.stateLock.Unlock()
+
+	event, _ := monitor.transition(DisplayRouteStateIdle, "provider detached")
+
+	return event
+}
+
+// ObserveFrame records successfully fetched frame. It returns the transition event and true when state changed.
+func (monitor *DisplayRouteMonitor) ObserveFrame(frameBuffer *peripheralSDK.DisplayFrameBuffer) (DisplayRouteStateEvent, bool) {
+	monitor.stateLock.Lock()
+	defer monitor.stateLock.Unlock()
+
+	if monitor.state == DisplayRouteStateIdle {
+		return DisplayRouteStateEvent{}, false
+	}
+
+	now := monitor.options.clock()
+	monitor.consecutiveErrors = 0
+
+	sequence := frameBuffer.GetSequence()
+	timestamp := frameBuffer.GetTimestamp()
+
+	var fresh bool
+	if sequence != 0 {
+		fresh = sequence != monitor.lastSequence
+	} else {
+		fresh = !timestamp.Equal(monitor.lastTimestamp)
+	}
+
+	monitor.lastSequence = sequence
+	monitor.lastTimestamp = timestamp
+
+	if fresh {
+		monitor.lastProgressAt = now
+		return monitor.transition(DisplayRouteStateLive, "frames resumed")
+	}
+
+	return monitor.checkStale(now, "frames are not updated")
+}
+
+// ObserveError records provider error. ErrDisplayFrameBufferNotReady is treated as missing signal, any other
+// error as transport failure.
+func (monitor *DisplayRouteMonitor) ObserveError(err error) (DisplayRouteStateEvent, bool) {
+	monitor.stateLock.Lock()
+	defer monitor.stateLock.Unlock()
+
+	if monitor.state == DisplayRouteStateIdle {
+		return DisplayRouteStateEvent{}, false
+	}
+
+	now := monitor.options.clock()
+
+	if errors.Is(err, peripheralSDK.ErrDisplayFrameBufferNotReady) {
+		monitor.consecutiveErrors = 0
+		return monitor.checkStale(now, "source has no frame")
+	}
+
+	monitor.consecutiveErrors++
+	if monitor.consecutiveErrors < monitor.options.errorThreshold {
+		return DisplayRouteStateEvent{}, false
+	}
+
+	return monitor.transition(DisplayRouteStateSourceOffline, err.Error())
+}
+
+func (monitor *DisplayRouteMonitor) checkStale(now time.Time, reason string) (DisplayRouteStateEvent, bool) {
+	staleFor := now.Sub(monitor.lastProgressAt)
+	if staleFor < monitor.options.staleFrameTimeout {
+		return DisplayRouteStateEvent{}, false
+	}
+
+	return monitor.transition(DisplayRouteStateNoSignal, fmt.Sprintf("%s for %s", reason, staleFor.Truncate(time.Millisecond)))
+}
+
+func (monitor *DisplayRouteMonitor) transition(state DisplayRouteState, reason string) (DisplayRouteStateEvent, bool) {
+	if monitor.state == state {
+		return DisplayRouteStateEvent{}, false
+	}
+
+	event := DisplayRouteStateEvent{
+		State:         state,
+		PreviousState: monitor.state,
+		Reason:        reason,
+		Timestamp:     monitor.options.clock(),
+	}
+
+	monitor.state = state
+	monitor.reason = reason
+
+	monitor.options.logger.Info("Display route state changed.",
+		slog.String("state", state.String()),
+		slog.String("previousState", event.PreviousState.String()),
+		slog.String("reason", reason),
+	)
+
+	monitor.stateEventsEmitter.Emit(event)
+
+	return event, true
+}
+
+var ErrInvalidDisplayRouteMonitorConfiguration = errors.New("invalid display route monitor configuration")
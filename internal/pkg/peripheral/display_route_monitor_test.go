@@ -0,0 +1,181 @@
+package peripheral
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (clock *fakeClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *fakeClock) Advance(duration time.Duration) {
+	clock.now = clock.now.Add(duration)
+}
+
+func newTestFrameBuffer(t *testing.T, sequence uint64) *peripheralSDK.DisplayFrameBuffer {
+	pool, err := memory.NewHeapPool(16, 4)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	buffer, err := pool.Borrow(4)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return peripheralSDK.NewDisplayFrameBuffer(buffer, peripheralSDK.WithDisplayFrameBufferSequence(sequence))
+}
+
+func newTestDisplayRouteMonitor(t *testing.T, clock *fakeClock) *DisplayRouteMonitor {
+	monitor, err := NewDisplayRouteMonitor(
+		WithDisplayRouteMonitorClock(clock.Now),
+		WithDisplayRouteMonitorStaleFrameTimeout(time.Second),
+		WithDisplayRouteMonitorErrorThreshold(2),
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return monitor
+}
+
+func TestNewDisplayRouteMonitorRejectsInvalidConfiguration(t *testing.T) {
+	_, err := NewDisplayRouteMonitor(WithDisplayRouteMonitorStaleFrameTimeout(0))
+	assert.ErrorIs(t, err, ErrInvalidDisplayRouteMonitorConfiguration)
+
+	_, err = NewDisplayRouteMonitor(WithDisplayRouteMonitorErrorThreshold(0))
+	assert.ErrorIs(t, err, ErrInvalidDisplayRouteMonitorConfiguration)
+}
+
+func TestDisplayRouteMonitorStartsIdleAndIgnoresObservations(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	monitor := newTestDisplayRouteMonitor(t, clock)
+
+	state, _ := monitor.GetState()
+	assert.Equal(t, DisplayRouteStateIdle, state)
+
+	_, changed := monitor.ObserveError(errors.New("boom"))
+	assert.False(t, changed)
+
+	_, changed = monitor.ObserveFrame(newTestFrameBuffer(t, 1))
+	assert.False(t, changed)
+}
+
+func TestDisplayRouteMonitorDetectsStaleFrames(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	monitor := newTestDisplayRouteMonitor(t, clock)
+
+	event := monitor.Attach()
+	assert.Equal(t, DisplayRouteStateLive, event.State)
+	assert.Equal(t, DisplayRouteStateIdle, event.PreviousState)
+
+	_, changed := monitor.ObserveFrame(newTestFrameBuffer(t, 1))
+	assert.False(t, changed)
+
+	clock.Advance(500 * time.Millisecond)
+	_, changed = monitor.ObserveFrame(newTestFrameBuffer(t, 1))
+	assert.False(t, changed)
+
+	clock.Advance(600 * time.Millisecond)
+	event, changed = monitor.ObserveFrame(newTestFrameBuffer(t, 1))
+	assert.True(t, changed)
+	assert.Equal(t, DisplayRouteStateNoSignal, event.State)
+	assert.Contains(t, event.Reason, "frames are not updated")
+
+	event, changed = monitor.ObserveFrame(newTestFrameBuffer(t, 2))
+	assert.True(t, changed)
+	assert.Equal(t, DisplayRouteStateLive, event.State)
+	assert.Equal(t, DisplayRouteStateNoSignal, event.PreviousState)
+}
+
+func TestDisplayRouteMonitorTreatsNotReadyAsNoSignal(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	monitor := newTestDisplayRouteMonitor(t, clock)
+	monitor.Attach()
+
+	_, changed := monitor.ObserveError(peripheralSDK.ErrDisplayFrameBufferNotReady)
+	assert.False(t, changed)
+
+	clock.Advance(2 * time.Second)
+	event, changed := monitor.ObserveError(peripheralSDK.ErrDisplayFrameBufferNotReady)
+	assert.True(t, changed)
+	assert.Equal(t, DisplayRouteStateNoSignal, event.State)
+	assert.Contains(t, event.Reason, "source has no frame")
+}
+
+func TestDisplayRouteMonitorDetectsSourceOffline(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	monitor := newTestDisplayRouteMonitor(t, clock)
+	monitor.Attach()
+
+	_, changed := monitor.ObserveError(errors.New("connection reset"))
+	assert.False(t, changed)
+
+	event, changed := monitor.ObserveError(errors.New("connection reset"))
+	assert.True(t, changed)
+	assert.Equal(t, DisplayRouteStateSourceOffline, event.State)
+	assert.Equal(t, "connection reset", event.Reason)
+
+	state, reason := monitor.GetState()
+	assert.Equal(t, DisplayRouteStateSourceOffline, state)
+	assert.Equal(t, "connection reset", reason)
+
+	event, changed = monitor.ObserveFrame(newTestFrameBuffer(t, 7))
+	assert.True(t, changed)
+	assert.Equal(t, DisplayRouteStateLive, event.State)
+}
+
+func TestDisplayRouteMonitorUsesTimestampWithoutSequence(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	monitor := newTestDisplayRouteMonitor(t, clock)
+	monitor.Attach()
+
+	frameBuffer := newTestFrameBuffer(t, 0)
+
+	_, changed := monitor.ObserveFrame(frameBuffer)
+	assert.False(t, changed)
+
+	clock.Advance(2 * time.Second)
+	event, changed := monitor.ObserveFrame(frameBuffer)
+	assert.True(t, changed)
+	assert.Equal(t, DisplayRouteStateNoSignal, event.State)
+}
+
+func TestDisplayRouteMonitorEmitsEvents(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	monitor := newTestDisplayRouteMonitor(t, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := monitor.Listen(ctx)
+
+	monitor.Attach()
+	monitor.Detach()
+
+	select {
+	case event := <-events:
+		assert.Equal(t, DisplayRouteStateLive, event.State)
+	case <-time.After(time.Second):
+		t.Fatal("expected live event")
+	}
+
+	select {
+	case event := <-events:
+		assert.Equal(t, DisplayRouteStateIdle, event.State)
+		assert.Equal(t, "provider detached", event.Reason)
+	case <-time.After(time.Second):
+		t.Fatal("expected idle event")
+	}
+}
@@ -0,0 +1,87 @@
+package peripheral
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/edid"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/drm/sysfs"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// DisplaySinkModesFromEdid is the value of supported display modes config which derives them from EDID of the monitor
+// connected to the machine running the sink.
+const DisplaySinkModesFromEdid = "fromEdid"
+
+// DisplaySinkEdidConfig selects DRM connector of the monitor whose EDID declares display modes of the sink.
+type DisplaySinkEdidConfig struct {
+	// SysfsPath is the directory with DRM connectors, /sys/class/drm by default.
+	SysfsPath *string `json:"sysfsPath"`
+	// Connector is the name of connector directory, e.g. card0-HDMI-A-1. The first connected connector presenting EDID
+	// is used when not set.
+	Connector *string `json:"connector"`
+}
+
+// DisplaySinkModes are display modes accepted by the sink together with EDID they were derived from.
+type DisplaySinkModes struct {
+	DisplayModes peripheralSDK.DisplayModeList
+
+	// Edid holds raw EDID blocks of the monitor and Connector its DRM connector, both are empty when display modes
+	// were listed in config.
+	Edid      []byte
+	Connector string
+}
+
+// LoadDisplaySinkModes resolves supported display modes config of a display sink, which is either a list of display
+// modes or DisplaySinkModesFromEdid. Derived display modes are the ones declared by EDID of the monitor, the preferred
+// one first.
+func LoadDisplaySinkModes(supportedDisplayModes any, edidConfig DisplaySinkEdidConfig) (*DisplaySinkModes, error) {
+	if value, isString := supportedDisplayModes.(string); isString {
+		if value != DisplaySinkModesFromEdid {
+			return nil, fmt.Errorf("%w: %q", ErrDisplaySinkModesUnknown, value)
+		}
+
+		return loadDisplaySinkModesFromEdid(edidConfig)
+	}
+
+	displayModes := peripheralSDK.DisplayModeList{}
+
+	err := mapstructure.Decode(supportedDisplayModes, &displayModes)
+	if err != nil {
+		return nil, fmt.Errorf("decode display modes: %w", err)
+	}
+
+	return &DisplaySinkModes{DisplayModes: displayModes}, nil
+}
+
+func loadDisplaySinkModesFromEdid(edidConfig DisplaySinkEdidConfig) (*DisplaySinkModes, error) {
+	connector, err := sysfs.ReadConnectorEdid(utils.DefaultNil(edidConfig.SysfsPath, sysfs.DefaultPath), utils.DefaultNil(edidConfig.Connector, ""))
+	if err != nil {
+		return nil, fmt.Errorf("read edid: %w", err)
+	}
+
+	// display modes of the base block are still declared when CEA-861 extension block cannot be decoded
+	specification, err := edid.CreateSpecificationFromBytes(connector.Edid)
+	if err != nil && !errors.Is(err, edid.ErrCeaExtensionInvalid) {
+		return nil, fmt.Errorf("decode edid of %s: %w", connector.Name, err)
+	}
+
+	displayModes := specification.DisplayModes()
+	if len(displayModes) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrDisplaySinkModesNotDeclared, connector.Name)
+	}
+
+	return &DisplaySinkModes{
+		DisplayModes: displayModes,
+		Edid:         connector.Edid,
+		Connector:    connector.Name,
+	}, nil
+}
+
+var (
+	ErrDisplaySinkModesUnknown     = errors.New("unknown supported display modes")
+	ErrDisplaySinkModesNotDeclared = errors.New("edid declares no display mode")
+)
@@ -0,0 +1,103 @@
+package peripheral
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/edid"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+var monitorDisplayModes = peripheralSDK.DisplayModeList{
+	{Width: 2560, Height: 1440, RefreshRate: 60},
+	{Width: 1920, Height: 1080, RefreshRate: 60},
+	{Width: 1280, Height: 720, RefreshRate: 60},
+}
+
+// createFakeDrmSysfs creates sysfs tree with a disconnected connector followed by a connector of the monitor.
+func createFakeDrmSysfs(t *testing.T) (string, []byte) {
+	t.Helper()
+
+	specification, skipped, err := edid.CreateSpecificationFromDisplayModes(monitorDisplayModes)
+	require.NoError(t, err)
+	require.Empty(t, skipped)
+
+	edidData, err := edid.CreateBytesFromSpecification(*specification)
+	require.NoError(t, err)
+
+	path := t.TempDir()
+
+	connectors := []struct {
+		name   string
+		status string
+		edid   []byte
+	}{
+		{name: "card0-DP-1", status: "disconnected"},
+		{name: "card0-HDMI-A-1", status: "connected", edid: edidData},
+	}
+
+	for _, connector := range connectors {
+		connectorPath := filepath.Join(path, connector.name)
+		require.NoError(t, os.MkdirAll(connectorPath, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(connectorPath, "status"), []byte(connector.status+"\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(connectorPath, "edid"), connector.edid, 0o644))
+	}
+
+	return path, edidData
+}
+
+func TestLoadDisplaySinkModesFromList(t *testing.T) {
+	config := []any{
+		map[string]any{"width": 1920, "height": 1080, "refreshRate": 60},
+		map[string]any{"width": 1280, "height": 720, "refreshRate": 30},
+	}
+
+	modes, err := LoadDisplaySinkModes(config, DisplaySinkEdidConfig{})
+	require.NoError(t, err)
+
+	assert.Equal(t, &DisplaySinkModes{
+		DisplayModes: peripheralSDK.DisplayModeList{
+			{Width: 1920, Height: 1080, RefreshRate: 60},
+			{Width: 1280, Height: 720, RefreshRate: 30},
+		},
+	}, modes)
+}
+
+func TestLoadDisplaySinkModesFromEdid(t *testing.T) {
+	path, edidData := createFakeDrmSysfs(t)
+
+	modes, err := LoadDisplaySinkModes(DisplaySinkModesFromEdid, DisplaySinkEdidConfig{SysfsPath: &path})
+	require.NoError(t, err)
+
+	assert.Equal(t, "card0-HDMI-A-1", modes.Connector)
+	assert.Equal(t, edidData, modes.Edid)
+	if assert.NotEmpty(t, modes.DisplayModes) {
+		assert.Equal(t, monitorDisplayModes[0], modes.DisplayModes[0])
+	}
+	for _, displayMode := range monitorDisplayModes {
+		assert.True(t, modes.DisplayModes.Supports(displayMode), displayMode.String())
+	}
+}
+
+func TestLoadDisplaySinkModesFromEdidErrors(t *testing.T) {
+	path, _ := createFakeDrmSysfs(t)
+
+	connector := "card0-DP-1"
+	_, err := LoadDisplaySinkModes(DisplaySinkModesFromEdid, DisplaySinkEdidConfig{SysfsPath: &path, Connector: &connector})
+	assert.ErrorContains(t, err, "edid not available")
+
+	corruptedPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(corruptedPath, "card0-HDMI-A-1"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(corruptedPath, "card0-HDMI-A-1", "status"), []byte("connected\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(corruptedPath, "card0-HDMI-A-1", "edid"), make([]byte, 128), 0o644))
+
+	_, err = LoadDisplaySinkModes(DisplaySinkModesFromEdid, DisplaySinkEdidConfig{SysfsPath: &corruptedPath})
+	assert.ErrorContains(t, err, "decode edid of card0-HDMI-A-1")
+
+	_, err = LoadDisplaySinkModes("fromMonitor", DisplaySinkEdidConfig{})
+	assert.ErrorIs(t, err, ErrDisplaySinkModesUnknown)
+}
@@ -0,0 +1,303 @@
+package qmp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+const clientHandshakeTimeout = 10 * time.Second
+
+// Event is asynchronous notification sent by QEMU, e.g. SHUTDOWN or RESET.
+type Event struct {
+	Name      string
+	Data      json.RawMessage
+	Timestamp time.Time
+}
+
+type EventHandler func(event Event)
+
+type ClientOptions struct {
+	eventHandler EventHandler
+	logger       *slog.Logger
+}
+
+type ClientOpt func(*ClientOptions)
+
+func defaultClientOptions() ClientOptions {
+	return ClientOptions{
+		eventHandler: func(event Event) {},
+		logger:       slog.New(slog.DiscardHandler),
+	}
+}
+
+// WithClientEventHandler sets handler called for every event, it is called from the read loop, so it must not block.
+func WithClientEventHandler(handler EventHandler) ClientOpt {
+	return func(options *ClientOptions) {
+		options.eventHandler = handler
+	}
+}
+
+func WithClientLogger(logger *slog.Logger) ClientOpt {
+	return func(options *ClientOptions) {
+		options.logger = logger
+	}
+}
+
+// Client is QMP client. Commands may be executed concurrently, responses are matched by request id.
+type Client struct {
+	connection net.Conn
+	encoder    *json.Encoder
+	writeLock  sync.Mutex
+
+	version Version
+
+	nextId      uint64
+	pending     map[uint64]chan message
+	pendingLock sync.Mutex
+
+	done    chan struct{}
+	doneErr error
+
+	eventHandler EventHandler
+	logger       *slog.Logger
+}
+
+// Version is QEMU version reported in the greeting.
+type Version struct {
+	Major   int    `json:"major"`
+	Minor   int    `json:"minor"`
+	Micro   int    `json:"micro"`
+	Package string `json:"package"`
+}
+
+func (version Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Micro)
+}
+
+type message struct {
+	Greeting *struct {
+		Version struct {
+			Qemu    Version `json:"qemu"`
+			Package string  `json:"package"`
+		} `json:"version"`
+	} `json:"QMP"`
+
+	Id     *uint64         `json:"id"`
+	Return json.RawMessage `json:"return"`
+	Error  *Error          `json:"error"`
+
+	Event     string          `json:"event"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp struct {
+		Seconds      int64 `json:"seconds"`
+		Microseconds int64 `json:"microseconds"`
+	} `json:"timestamp"`
+}
+
+type request struct {
+	Execute   string `json:"execute"`
+	Arguments any    `json:"arguments,omitempty"`
+	Id        uint64 `json:"id"`
+}
+
+// Dial connects to QMP unix socket at path and negotiates capabilities.
+func Dial(ctx context.Context, path string, opts ...ClientOpt) (*Client, error) {
+	var dialer net.Dialer
+
+	connection, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+
+	client, err := NewClient(ctx, connection, opts...)
+	if err != nil {
+		_ = connection.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// NewClient reads greeting from connection, leaves capabilities negotiation mode and starts reading responses and
+// events in background.
+func NewClient(ctx context.Context, connection net.Conn, opts ...ClientOpt) (*Client, error) {
+	options := defaultClientOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	client := &Client{
+		connection: connection,
+		encoder:    json.NewEncoder(connection),
+		pending:    make(map[uint64]chan message),
+		done:       make(chan struct{}),
+
+		eventHandler: options.eventHandler,
+		logger:       options.logger,
+	}
+
+	decoder := json.NewDecoder(connection)
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = connection.SetDeadline(time.Now())
+	})
+
+	_ = connection.SetDeadline(time.Now().Add(clientHandshakeTimeout))
+
+	var greeting message
+	err := decoder.Decode(&greeting)
+	if err == nil && greeting.Greeting == nil {
+		err = fmt.Errorf("%w: greeting expected", ErrProtocolViolation)
+	}
+
+	stop()
+	_ = connection.SetDeadline(time.Time{})
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("read greeting: %w", err)
+	}
+
+	client.version = greeting.Greeting.Version.Qemu
+
+	go client.readLoop(decoder)
+
+	if err := client.Execute(ctx, "qmp_capabilities", nil, nil); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("negotiate capabilities: %w", err)
+	}
+
+	return client, nil
+}
+
+func (client *Client) GetVersion() Version {
+	return client.version
+}
+
+// Execute runs command with arguments and decodes its return value into result. Both arguments and result may be
+// nil. Command errors reported by QEMU are returned as *Error.
+func (client *Client) Execute(ctx context.Context, command string, arguments any, result any) error {
+	if err := client.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrClosed, err)
+	}
+
+	responseChannel := make(chan message, 1)
+
+	client.pendingLock.Lock()
+	client.nextId++
+	id := client.nextId
+	client.pending[id] = responseChannel
+	client.pendingLock.Unlock()
+
+	defer func() {
+		client.pendingLock.Lock()
+		delete(client.pending, id)
+		client.pendingLock.Unlock()
+	}()
+
+	client.writeLock.Lock()
+	err := client.encoder.Encode(request{Execute: command, Arguments: arguments, Id: id})
+	client.writeLock.Unlock()
+	if err != nil {
+		return fmt.Errorf("write command: %w", err)
+	}
+
+	var response message
+	select {
+	case response = <-responseChannel:
+	case <-client.done:
+		return fmt.Errorf("%w: %w", ErrClosed, client.doneErr)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(response.Return, result); err != nil {
+		return fmt.Errorf("decode %s result: %w", command, err)
+	}
+
+	return nil
+}
+
+// Done returns channel closed when connection is lost.
+func (client *Client) Done() <-chan struct{} {
+	return client.done
+}
+
+// Err returns reason of connection loss, nil until Done is closed.
+func (client *Client) Err() error {
+	select {
+	case <-client.done:
+		return client.doneErr
+	default:
+		return nil
+	}
+}
+
+func (client *Client) Close() error {
+	return client.connection.Close()
+}
+
+func (client *Client) readLoop(decoder *json.Decoder) {
+	defer close(client.done)
+
+	for {
+		var received message
+		if err := decoder.Decode(&received); err != nil {
+			client.doneErr = err
+			return
+		}
+
+		if received.Event != "" {
+			client.eventHandler(Event{
+				Name:      received.Event,
+				Data:      received.Data,
+				Timestamp: time.Unix(received.Timestamp.Seconds, received.Timestamp.Microseconds*int64(time.Microsecond)),
+			})
+			continue
+		}
+
+		if received.Id == nil {
+			client.logger.Warn("Received QMP response without id.")
+			continue
+		}
+
+		client.pendingLock.Lock()
+		responseChannel, found := client.pending[*received.Id]
+		client.pendingLock.Unlock()
+
+		if found {
+			responseChannel <- received
+		}
+	}
+}
+
+// Error is error reported by QEMU in response to command.
+type Error struct {
+	Class       string `json:"class"`
+	Description string `json:"desc"`
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("qmp %s: %s", err.Class, err.Description)
+}
+
+var (
+	ErrClosed            = errors.New("qmp connection closed")
+	ErrProtocolViolation = errors.New("qmp protocol violation")
+)
@@ -0,0 +1,178 @@
+package qmp
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequest struct {
+	Execute   string          `json:"execute"`
+	Arguments json.RawMessage `json:"arguments"`
+	Id        uint64          `json:"id"`
+}
+
+// startTestServer serves QMP on pipe, handler returns result or *Error of every command except qmp_capabilities.
+// Requests are sent to the returned channel.
+func startTestServer(t *testing.T, handler func(request testRequest) (any, *Error)) (net.Conn, chan testRequest, *json.Encoder) {
+	clientConn, serverConn := net.Pipe()
+	requests := make(chan testRequest, 16)
+	encoder := json.NewEncoder(serverConn)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		_ = encoder.Encode(map[string]any{
+			"QMP": map[string]any{
+				"version":      map[string]any{"qemu": map[string]any{"major": 9, "minor": 1, "micro": 2}, "package": ""},
+				"capabilities": []string{},
+			},
+		})
+
+		decoder := json.NewDecoder(serverConn)
+		for {
+			var request testRequest
+			if err := decoder.Decode(&request); err != nil {
+				return
+			}
+
+			if request.Execute == "qmp_capabilities" {
+				_ = encoder.Encode(map[string]any{"return": map[string]any{}, "id": request.Id})
+				continue
+			}
+
+			requests <- request
+
+			result, qmpErr := handler(request)
+			if qmpErr != nil {
+				_ = encoder.Encode(map[string]any{"error": qmpErr, "id": request.Id})
+			} else {
+				_ = encoder.Encode(map[string]any{"return": result, "id": request.Id})
+			}
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = serverConn.Close()
+		<-done
+	})
+
+	return clientConn, requests, encoder
+}
+
+func TestClientExecute(t *testing.T) {
+	connection, requests, _ := startTestServer(t, func(request testRequest) (any, *Error) {
+		if request.Execute == "query-status" {
+			return Status{Running: true, Status: "running"}, nil
+		}
+		return nil, &Error{Class: "CommandNotFound", Description: "The command " + request.Execute + " has not been found"}
+	})
+
+	client, err := NewClient(t.Context(), connection)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	assert.Equal(t, "9.1.2", client.GetVersion().String())
+
+	status, err := client.QueryStatus(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, &Status{Running: true, Status: "running"}, status)
+	assert.Equal(t, "query-status", (<-requests).Execute)
+
+	err = client.Execute(t.Context(), "unknown", nil, nil)
+	var qmpErr *Error
+	assert.ErrorAs(t, err, &qmpErr)
+	assert.Equal(t, "CommandNotFound", qmpErr.Class)
+}
+
+func TestClientSendInputEvents(t *testing.T) {
+	connection, requests, _ := startTestServer(t, func(request testRequest) (any, *Error) {
+		return map[string]any{}, nil
+	})
+
+	client, err := NewClient(t.Context(), connection)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	err = client.SendInputEvents(t.Context(), "", NewKeyEvent("a", true), NewAbsoluteMoveEvent(AxisX, AbsoluteAxisMax), NewButtonEvent(ButtonLeft, false))
+	assert.NoError(t, err)
+
+	request := <-requests
+	assert.Equal(t, "input-send-event", request.Execute)
+	assert.JSONEq(t, `{"events":[
+		{"type":"key","data":{"down":true,"key":{"type":"qcode","data":"a"}}},
+		{"type":"abs","data":{"axis":"x","value":32767}},
+		{"type":"btn","data":{"down":false,"button":"left"}}
+	]}`, string(request.Arguments))
+}
+
+func TestClientEvents(t *testing.T) {
+	connection, _, encoder := startTestServer(t, func(request testRequest) (any, *Error) {
+		return map[string]any{}, nil
+	})
+
+	events := make(chan Event, 1)
+	client, err := NewClient(t.Context(), connection, WithClientEventHandler(func(event Event) {
+		events <- event
+	}))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	_ = encoder.Encode(map[string]any{
+		"event":     "SHUTDOWN",
+		"data":      map[string]any{"guest": true, "reason": "guest-shutdown"},
+		"timestamp": map[string]any{"seconds": 1700000000, "microseconds": 500},
+	})
+
+	event := <-events
+	assert.Equal(t, "SHUTDOWN", event.Name)
+	assert.JSONEq(t, `{"guest":true,"reason":"guest-shutdown"}`, string(event.Data))
+	assert.Equal(t, time.Unix(1700000000, 500000), event.Timestamp)
+}
+
+func TestClientConnectionLost(t *testing.T) {
+	connection, _, _ := startTestServer(t, func(request testRequest) (any, *Error) {
+		return map[string]any{}, nil
+	})
+
+	client, err := NewClient(t.Context(), connection)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_ = client.Close()
+	<-client.Done()
+
+	assert.Error(t, client.Err())
+	assert.ErrorIs(t, client.Execute(t.Context(), "query-status", nil, nil), ErrClosed)
+}
+
+func TestClientGreetingCanceled(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer func() {
+		_ = serverConn.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := NewClient(ctx, clientConn)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
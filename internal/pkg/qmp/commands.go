@@ -0,0 +1,104 @@
+package qmp
+
+import (
+	"context"
+)
+
+// AbsoluteAxisMax is the largest value of absolute pointer axis, it maps to the right or bottom edge of the display.
+const AbsoluteAxisMax = 0x7fff
+
+const (
+	ButtonLeft       = "left"
+	ButtonMiddle     = "middle"
+	ButtonRight      = "right"
+	ButtonWheelUp    = "wheel-up"
+	ButtonWheelDown  = "wheel-down"
+	ButtonSide       = "side"
+	ButtonExtra      = "extra"
+	ButtonWheelLeft  = "wheel-left"
+	ButtonWheelRight = "wheel-right"
+)
+
+const (
+	AxisX = "x"
+	AxisY = "y"
+)
+
+// InputEvent is single event of input-send-event command.
+type InputEvent struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+type keyValue struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+type keyEventData struct {
+	Down bool     `json:"down"`
+	Key  keyValue `json:"key"`
+}
+
+type buttonEventData struct {
+	Down   bool   `json:"down"`
+	Button string `json:"button"`
+}
+
+type moveEventData struct {
+	Axis  string `json:"axis"`
+	Value int    `json:"value"`
+}
+
+// NewKeyEvent returns press or release of key identified by QKeyCode, e.g. "a", "shift" or "kp_enter".
+func NewKeyEvent(qcode string, down bool) InputEvent {
+	return InputEvent{Type: "key", Data: keyEventData{Down: down, Key: keyValue{Type: "qcode", Data: qcode}}}
+}
+
+func NewButtonEvent(button string, down bool) InputEvent {
+	return InputEvent{Type: "btn", Data: buttonEventData{Down: down, Button: button}}
+}
+
+// NewAbsoluteMoveEvent returns absolute pointer position on axis in the [0, AbsoluteAxisMax] range.
+func NewAbsoluteMoveEvent(axis string, value int) InputEvent {
+	return InputEvent{Type: "abs", Data: moveEventData{Axis: axis, Value: value}}
+}
+
+func NewRelativeMoveEvent(axis string, value int) InputEvent {
+	return InputEvent{Type: "rel", Data: moveEventData{Axis: axis, Value: value}}
+}
+
+type sendInputEventArguments struct {
+	Device string       `json:"device,omitempty"`
+	Events []InputEvent `json:"events"`
+}
+
+// SendInputEvents injects events with input-send-event. Empty device sends events to the default input device of
+// each event type.
+func (client *Client) SendInputEvents(ctx context.Context, device string, events ...InputEvent) error {
+	return client.Execute(ctx, "input-send-event", sendInputEventArguments{Device: device, Events: events}, nil)
+}
+
+type screendumpArguments struct {
+	Filename string `json:"filename"`
+}
+
+// Screendump writes current display as PPM image to filename, path is resolved by QEMU process.
+func (client *Client) Screendump(ctx context.Context, filename string) error {
+	return client.Execute(ctx, "screendump", screendumpArguments{Filename: filename}, nil)
+}
+
+// Status is virtual machine run state reported by query-status.
+type Status struct {
+	Running bool   `json:"running"`
+	Status  string `json:"status"`
+}
+
+func (client *Client) QueryStatus(ctx context.Context) (*Status, error) {
+	var status Status
+	if err := client.Execute(ctx, "query-status", nil, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
@@ -0,0 +1,42 @@
+package rfb
+
+import (
+	"crypto/des"
+	"crypto/subtle"
+	"fmt"
+	"math/bits"
+)
+
+// vncAuthResponse encrypts challenge with password as VNC authentication does: DES in ECB mode, key made of the
+// first eight password bytes with bit order of every byte reversed.
+func vncAuthResponse(password string, challenge []byte) ([]byte, error) {
+	if len(challenge) != 16 {
+		return nil, fmt.Errorf("%w: challenge must have 16 bytes", ErrProtocolViolation)
+	}
+
+	var key [8]byte
+	copy(key[:], password)
+	for i := range key {
+		key[i] = bits.Reverse8(key[i])
+	}
+
+	cipher, err := des.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	response := make([]byte, 16)
+	cipher.Encrypt(response[:8], challenge[:8])
+	cipher.Encrypt(response[8:], challenge[8:])
+
+	return response, nil
+}
+
+func verifyVNCAuthResponse(password string, challenge []byte, response []byte) bool {
+	expected, err := vncAuthResponse(password, challenge)
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(expected, response) == 1
+}
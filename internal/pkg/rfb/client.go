@@ -0,0 +1,425 @@
+package rfb
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	clientHandshakeTimeout = 30 * time.Second
+	clientMaxDesktopName   = 4096
+)
+
+type ClientOptions struct {
+	password  string
+	encodings []Encoding
+	logger    *slog.Logger
+}
+
+type ClientOpt func(*ClientOptions)
+
+func defaultClientOptions() ClientOptions {
+	return ClientOptions{
+		encodings: []Encoding{EncodingZRLE, EncodingCopyRect, EncodingRaw, EncodingDesktopSize},
+		logger:    slog.New(slog.DiscardHandler),
+	}
+}
+
+// WithClientPassword sets password used when server requires VNC authentication.
+func WithClientPassword(password string) ClientOpt {
+	return func(options *ClientOptions) {
+		options.password = password
+	}
+}
+
+// WithClientEncodings sets encodings announced to server in order of preference. Only Raw, CopyRect, ZRLE and
+// DesktopSize are decoded.
+func WithClientEncodings(encodings ...Encoding) ClientOpt {
+	return func(options *ClientOptions) {
+		options.encodings = encodings
+	}
+}
+
+func WithClientLogger(logger *slog.Logger) ClientOpt {
+	return func(options *ClientOptions) {
+		options.logger = logger
+	}
+}
+
+// Client is RFB client keeping copy of the remote framebuffer. Updates must be read from single goroutine, input
+// events may be sent concurrently.
+type Client struct {
+	connection net.Conn
+	reader     *bufio.Reader
+	writeLock  sync.Mutex
+
+	desktopName string
+	pixelFormat PixelFormat
+	frame       *Frame
+	zrleDecoder *zrleDecoder
+
+	logger *slog.Logger
+}
+
+// Dial connects to server at address and performs handshake.
+func Dial(ctx context.Context, address string, opts ...ClientOpt) (*Client, error) {
+	var dialer net.Dialer
+
+	connection, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+
+	client, err := NewClient(ctx, connection, opts...)
+	if err != nil {
+		_ = connection.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// NewClient performs handshake on connection, announces pixel format and encodings. Handshake is interrupted when
+// context is done.
+func NewClient(ctx context.Context, connection net.Conn, opts ...ClientOpt) (*Client, error) {
+	options := defaultClientOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	for _, encoding := range options.encodings {
+		if !slices.Contains([]Encoding{EncodingRaw, EncodingCopyRect, EncodingZRLE, EncodingDesktopSize}, encoding) {
+			return nil, fmt.Errorf("%w: %s", ErrEncodingUnsupported, encoding)
+		}
+	}
+
+	client := &Client{
+		connection:  connection,
+		reader:      bufio.NewReader(connection),
+		pixelFormat: DefaultPixelFormat,
+		zrleDecoder: newZRLEDecoder(),
+		logger:      options.logger,
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = connection.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	_ = connection.SetDeadline(time.Now().Add(clientHandshakeTimeout))
+	if err := client.handshake(options); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("handshake: %w", err)
+	}
+	_ = connection.SetDeadline(time.Time{})
+
+	return client, nil
+}
+
+func (client *Client) handshake(options ClientOptions) error {
+	serverVersion := make([]byte, len(ProtocolVersion38))
+	if _, err := io.ReadFull(client.reader, serverVersion); err != nil {
+		return fmt.Errorf("read protocol version: %w", err)
+	}
+
+	version, err := parseProtocolVersion(serverVersion)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.connection.Write([]byte(version)); err != nil {
+		return fmt.Errorf("write protocol version: %w", err)
+	}
+
+	if err := client.authenticate(version, options.password); err != nil {
+		return err
+	}
+
+	// shared flag of ClientInit, other clients stay connected
+	if _, err := client.connection.Write([]byte{1}); err != nil {
+		return fmt.Errorf("write client init: %w", err)
+	}
+
+	serverInit := make([]byte, 20)
+	if _, err := io.ReadFull(client.reader, serverInit); err != nil {
+		return fmt.Errorf("read server init: %w", err)
+	}
+
+	desktopName, err := readString(client.reader, clientMaxDesktopName)
+	if err != nil {
+		return fmt.Errorf("read desktop name: %w", err)
+	}
+
+	client.desktopName = desktopName
+	client.frame = NewFrame(int(binary.BigEndian.Uint16(serverInit[0:])), int(binary.BigEndian.Uint16(serverInit[2:])))
+
+	pixelFormatData, _ := client.pixelFormat.MarshalBinary()
+	message := append([]byte{messageSetPixelFormat, 0, 0, 0}, pixelFormatData...)
+
+	message = append(message, messageSetEncodings, 0)
+	message = binary.BigEndian.AppendUint16(message, uint16(len(options.encodings)))
+	for _, encoding := range options.encodings {
+		message = binary.BigEndian.AppendUint32(message, uint32(encoding))
+	}
+
+	if _, err := client.connection.Write(message); err != nil {
+		return fmt.Errorf("write pixel format and encodings: %w", err)
+	}
+
+	return nil
+}
+
+// authenticate selects no authentication when server offers it, VNC authentication otherwise.
+func (client *Client) authenticate(version ProtocolVersion, password string) error {
+	var securityType SecurityType
+
+	if version == ProtocolVersion33 {
+		var value uint32
+		if err := binary.Read(client.reader, binary.BigEndian, &value); err != nil {
+			return fmt.Errorf("read security type: %w", err)
+		}
+		securityType = SecurityType(value)
+
+		if securityType == SecurityTypeInvalid {
+			return client.readFailure(ErrConnectionRejected)
+		}
+	} else {
+		count, err := client.reader.ReadByte()
+		if err != nil {
+			return fmt.Errorf("read security types: %w", err)
+		}
+		if count == 0 {
+			return client.readFailure(ErrConnectionRejected)
+		}
+
+		securityTypes := make([]byte, count)
+		if _, err := io.ReadFull(client.reader, securityTypes); err != nil {
+			return fmt.Errorf("read security types: %w", err)
+		}
+
+		switch {
+		case slices.Contains(securityTypes, byte(SecurityTypeNone)):
+			securityType = SecurityTypeNone
+		case slices.Contains(securityTypes, byte(SecurityTypeVNCAuth)):
+			securityType = SecurityTypeVNCAuth
+		default:
+			return fmt.Errorf("%w: %v", ErrSecurityTypeUnsupported, securityTypes)
+		}
+
+		if _, err := client.connection.Write([]byte{byte(securityType)}); err != nil {
+			return fmt.Errorf("write security type: %w", err)
+		}
+	}
+
+	switch securityType {
+	case SecurityTypeNone:
+		if version != ProtocolVersion38 {
+			return nil
+		}
+	case SecurityTypeVNCAuth:
+		if password == "" {
+			return fmt.Errorf("%w: password required", ErrAuthenticationFailed)
+		}
+
+		challenge := make([]byte, 16)
+		if _, err := io.ReadFull(client.reader, challenge); err != nil {
+			return fmt.Errorf("read challenge: %w", err)
+		}
+
+		response, err := vncAuthResponse(password, challenge)
+		if err != nil {
+			return err
+		}
+
+		if _, err := client.connection.Write(response); err != nil {
+			return fmt.Errorf("write challenge response: %w", err)
+		}
+	default:
+		return fmt.Errorf("%w: %d", ErrSecurityTypeUnsupported, securityType)
+	}
+
+	var result uint32
+	if err := binary.Read(client.reader, binary.BigEndian, &result); err != nil {
+		return fmt.Errorf("read security result: %w", err)
+	}
+
+	if result == 0 {
+		return nil
+	}
+
+	if version == ProtocolVersion38 {
+		return client.readFailure(ErrAuthenticationFailed)
+	}
+
+	return ErrAuthenticationFailed
+}
+
+// readFailure reads failure reason sent by server and returns it wrapped in cause.
+func (client *Client) readFailure(cause error) error {
+	reason, err := readString(client.reader, clientMaxDesktopName)
+	if err != nil {
+		return cause
+	}
+
+	return fmt.Errorf("%w: %s", cause, reason)
+}
+
+func (client *Client) GetDesktopName() string {
+	return client.desktopName
+}
+
+// GetFrame returns copy of the remote framebuffer. It is modified by ReadUpdate, so it must be used from the same
+// goroutine.
+func (client *Client) GetFrame() *Frame {
+	return client.frame
+}
+
+// RequestUpdate asks server for FramebufferUpdate of the whole framebuffer.
+func (client *Client) RequestUpdate(incremental bool) error {
+	bounds := client.frame.Bounds()
+
+	message := []byte{messageFramebufferUpdateRequest, boolByte(incremental)}
+	message = binary.BigEndian.AppendUint16(message, bounds.X)
+	message = binary.BigEndian.AppendUint16(message, bounds.Y)
+	message = binary.BigEndian.AppendUint16(message, bounds.Width)
+	message = binary.BigEndian.AppendUint16(message, bounds.Height)
+
+	return client.write(message)
+}
+
+// ReadUpdate reads server messages until FramebufferUpdate is applied to the frame and returns updated areas. Bell
+// and cut text messages are skipped. Frame is replaced when server changes desktop size.
+func (client *Client) ReadUpdate() ([]Rectangle, error) {
+	for {
+		messageType, err := client.reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch messageType {
+		case messageFramebufferUpdate:
+			return client.readFramebufferUpdate()
+
+		case messageBell:
+
+		case messageServerCutText:
+			var message [7]byte
+			if _, err := io.ReadFull(client.reader, message[:]); err != nil {
+				return nil, err
+			}
+
+			length := binary.BigEndian.Uint32(message[3:])
+			if length > serverMaxCutText {
+				return nil, fmt.Errorf("%w: cut text of %d bytes", ErrProtocolViolation, length)
+			}
+
+			if _, err := io.CopyN(io.Discard, client.reader, int64(length)); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, fmt.Errorf("%w: message type %d", ErrProtocolViolation, messageType)
+		}
+	}
+}
+
+func (client *Client) readFramebufferUpdate() ([]Rectangle, error) {
+	var header [3]byte
+	if _, err := io.ReadFull(client.reader, header[:]); err != nil {
+		return nil, err
+	}
+
+	count := int(binary.BigEndian.Uint16(header[1:]))
+	rectangles := make([]Rectangle, 0, count)
+
+	for range count {
+		var rectangleHeader [12]byte
+		if _, err := io.ReadFull(client.reader, rectangleHeader[:]); err != nil {
+			return nil, err
+		}
+
+		rectangle := Rectangle{
+			X:      binary.BigEndian.Uint16(rectangleHeader[0:]),
+			Y:      binary.BigEndian.Uint16(rectangleHeader[2:]),
+			Width:  binary.BigEndian.Uint16(rectangleHeader[4:]),
+			Height: binary.BigEndian.Uint16(rectangleHeader[6:]),
+		}
+		encoding := Encoding(int32(binary.BigEndian.Uint32(rectangleHeader[8:])))
+
+		if encoding == EncodingDesktopSize {
+			client.frame = NewFrame(int(rectangle.Width), int(rectangle.Height))
+			rectangles = append(rectangles, client.frame.Bounds())
+			continue
+		}
+
+		if rectangle.Intersect(client.frame.Bounds()) != rectangle {
+			return nil, fmt.Errorf("%w: rectangle %+v outside of framebuffer", ErrProtocolViolation, rectangle)
+		}
+
+		var err error
+		switch encoding {
+		case EncodingRaw:
+			err = decodeRaw(client.reader, client.frame, rectangle, client.pixelFormat)
+		case EncodingCopyRect:
+			err = decodeCopyRect(client.reader, client.frame, rectangle)
+		case EncodingZRLE:
+			err = client.zrleDecoder.Decode(client.reader, client.frame, rectangle, client.pixelFormat)
+		default:
+			err = fmt.Errorf("%w: %s", ErrEncodingUnsupported, encoding)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode %s rectangle: %w", encoding, err)
+		}
+
+		rectangles = append(rectangles, rectangle)
+	}
+
+	return rectangles, nil
+}
+
+// SendKeyEvent sends key press or release of keysym.
+func (client *Client) SendKeyEvent(down bool, keysym uint32) error {
+	message := []byte{messageKeyEvent, boolByte(down), 0, 0}
+
+	return client.write(binary.BigEndian.AppendUint32(message, keysym))
+}
+
+// SendPointerEvent sends pointer position in framebuffer coordinates with mask of pressed buttons.
+func (client *Client) SendPointerEvent(buttonMask uint8, x uint16, y uint16) error {
+	message := []byte{messagePointerEvent, buttonMask}
+	message = binary.BigEndian.AppendUint16(message, x)
+
+	return client.write(binary.BigEndian.AppendUint16(message, y))
+}
+
+func (client *Client) write(message []byte) error {
+	client.writeLock.Lock()
+	defer client.writeLock.Unlock()
+
+	if _, err := client.connection.Write(message); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+
+	return nil
+}
+
+func (client *Client) Close() error {
+	return client.connection.Close()
+}
+
+var (
+	ErrConnectionRejected      = errors.New("rfb connection rejected")
+	ErrSecurityTypeUnsupported = errors.New("rfb security type unsupported")
+	ErrEncodingUnsupported     = errors.New("rfb encoding unsupported")
+)
@@ -0,0 +1,106 @@
+package rfb
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func startTestServer(t *testing.T, frame *Frame, opts ...ServerOpt) (net.Conn, *testDisplaySource) {
+	displaySource, source := newTestDisplaySource(t, frame)
+
+	server, err := NewServer(displaySource, append([]ServerOpt{WithServerMaxFrameRate(100)}, opts...)...)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	clientConn, serverConn := net.Pipe()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.ServeConnection(t.Context(), serverConn)
+	}()
+
+	t.Cleanup(func() {
+		_ = clientConn.Close()
+		<-done
+	})
+
+	return clientConn, source
+}
+
+func TestClientDecodesServerUpdates(t *testing.T) {
+	encodingSets := map[string][]Encoding{
+		"zrle": {EncodingZRLE, EncodingCopyRect, EncodingDesktopSize},
+		"raw":  {EncodingRaw, EncodingCopyRect, EncodingDesktopSize},
+	}
+
+	for name, encodings := range encodingSets {
+		t.Run(name, func(t *testing.T) {
+			frame := newTestMixedFrame(200, 150)
+			connection, source := startTestServer(t, frame, WithServerPassword("secret"), WithServerDesktopName("test desktop"))
+
+			client, err := NewClient(t.Context(), connection, WithClientPassword("secret"), WithClientEncodings(encodings...))
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, "test desktop", client.GetDesktopName())
+
+			assert.NoError(t, client.RequestUpdate(false))
+			_, err = client.ReadUpdate()
+			assert.NoError(t, err)
+			assert.Equal(t, frame.Pixels, client.GetFrame().Pixels)
+
+			// scroll by 40 rows and change a single pixel
+			scrolled := newTestMixedFrame(200, 150)
+			scrolled.MoveRows(40, 0, 110)
+			setTestPixel(scrolled, 150, 140)
+			source.setFrame(scrolled)
+
+			assert.NoError(t, client.RequestUpdate(true))
+			rectangles, err := client.ReadUpdate()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, rectangles)
+			assert.Equal(t, scrolled.Pixels, client.GetFrame().Pixels)
+
+			resized := newTestMixedFrame(120, 90)
+			source.setFrame(resized)
+
+			assert.NoError(t, client.RequestUpdate(true))
+			_, err = client.ReadUpdate()
+			assert.NoError(t, err)
+			assert.Equal(t, resized.Bounds(), client.GetFrame().Bounds())
+			assert.Equal(t, resized.Pixels, client.GetFrame().Pixels)
+		})
+	}
+}
+
+func TestClientAuthenticationFailure(t *testing.T) {
+	connection, _ := startTestServer(t, newTestFrame(8, 8), WithServerPassword("secret"))
+
+	_, err := NewClient(t.Context(), connection, WithClientPassword("wrong"))
+	assert.ErrorIs(t, err, ErrAuthenticationFailed)
+}
+
+func TestClientPasswordRequired(t *testing.T) {
+	connection, _ := startTestServer(t, newTestFrame(8, 8), WithServerPassword("secret"))
+
+	_, err := NewClient(t.Context(), connection)
+	assert.ErrorIs(t, err, ErrAuthenticationFailed)
+}
+
+func TestClientHandshakeCanceled(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer func() {
+		_ = serverConn.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := NewClient(ctx, clientConn)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,296 @@
+package rfb
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// setPixel stores pixel value of given pixel format as RGB24.
+func (frame *Frame) setPixel(pixelFormat PixelFormat, x int, y int, value uint32) {
+	offset := (y*frame.Width + x) * 3
+	frame.Pixels[offset], frame.Pixels[offset+1], frame.Pixels[offset+2] = pixelFormat.Unpack(value)
+}
+
+// fill sets all pixels of rectangle to pixel value.
+func (frame *Frame) fill(pixelFormat PixelFormat, rectangle Rectangle, value uint32) {
+	red, green, blue := pixelFormat.Unpack(value)
+
+	for y := int(rectangle.Y); y < int(rectangle.Y)+int(rectangle.Height); y++ {
+		row := frame.row(y, int(rectangle.X), int(rectangle.Width))
+		for offset := 0; offset < len(row); offset += 3 {
+			row[offset], row[offset+1], row[offset+2] = red, green, blue
+		}
+	}
+}
+
+func decodeRaw(reader io.Reader, frame *Frame, rectangle Rectangle, pixelFormat PixelFormat) error {
+	bytesPerPixel := pixelFormat.BytesPerPixel()
+	row := make([]byte, int(rectangle.Width)*bytesPerPixel)
+
+	for y := int(rectangle.Y); y < int(rectangle.Y)+int(rectangle.Height); y++ {
+		if _, err := io.ReadFull(reader, row); err != nil {
+			return err
+		}
+
+		for x := 0; x < int(rectangle.Width); x++ {
+			frame.setPixel(pixelFormat, int(rectangle.X)+x, y, pixelFormat.ReadPixel(row[x*bytesPerPixel:]))
+		}
+	}
+
+	return nil
+}
+
+func decodeCopyRect(reader io.Reader, frame *Frame, rectangle Rectangle) error {
+	var position [4]byte
+	if _, err := io.ReadFull(reader, position[:]); err != nil {
+		return err
+	}
+
+	source := Rectangle{
+		X:      binary.BigEndian.Uint16(position[0:]),
+		Y:      binary.BigEndian.Uint16(position[2:]),
+		Width:  rectangle.Width,
+		Height: rectangle.Height,
+	}
+	if source.Intersect(frame.Bounds()) != source {
+		return fmt.Errorf("%w: copy source %+v outside of framebuffer", ErrProtocolViolation, source)
+	}
+
+	// rows are copied through temporary buffer, source and destination may overlap
+	rows := make([]byte, 0, rectangle.Area()*3)
+	for y := int(source.Y); y < int(source.Y)+int(source.Height); y++ {
+		rows = append(rows, frame.row(y, int(source.X), int(source.Width))...)
+	}
+
+	rowLength := int(rectangle.Width) * 3
+	for y := 0; y < int(rectangle.Height); y++ {
+		copy(frame.row(int(rectangle.Y)+y, int(rectangle.X), int(rectangle.Width)), rows[y*rowLength:(y+1)*rowLength])
+	}
+
+	return nil
+}
+
+// zrleDecoder decodes ZRLE rectangles. Compressed data of all rectangles forms single zlib stream, decompressed data
+// is read exactly as tiles need it, so the decompressor never waits for data of the next rectangle.
+type zrleDecoder struct {
+	compressed   bytes.Buffer
+	decompressor io.ReadCloser
+
+	scratch []byte
+	palette [zrleMaxPaletteSize]uint32
+}
+
+func newZRLEDecoder() *zrleDecoder {
+	return &zrleDecoder{}
+}
+
+func (decoder *zrleDecoder) Decode(reader io.Reader, frame *Frame, rectangle Rectangle, pixelFormat PixelFormat) error {
+	var length [4]byte
+	if _, err := io.ReadFull(reader, length[:]); err != nil {
+		return err
+	}
+
+	if _, err := io.CopyN(&decoder.compressed, reader, int64(binary.BigEndian.Uint32(length[:]))); err != nil {
+		return err
+	}
+
+	if decoder.decompressor == nil {
+		decompressor, err := zlib.NewReader(&decoder.compressed)
+		if err != nil {
+			return fmt.Errorf("create zlib reader: %w", err)
+		}
+		decoder.decompressor = decompressor
+	}
+
+	for tileY := int(rectangle.Y); tileY < int(rectangle.Y)+int(rectangle.Height); tileY += zrleTileSize {
+		for tileX := int(rectangle.X); tileX < int(rectangle.X)+int(rectangle.Width); tileX += zrleTileSize {
+			tile := Rectangle{
+				X:      uint16(tileX),
+				Y:      uint16(tileY),
+				Width:  uint16(min(zrleTileSize, int(rectangle.X)+int(rectangle.Width)-tileX)),
+				Height: uint16(min(zrleTileSize, int(rectangle.Y)+int(rectangle.Height)-tileY)),
+			}
+
+			if err := decoder.decodeTile(frame, tile, pixelFormat); err != nil {
+				return fmt.Errorf("decode tile: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (decoder *zrleDecoder) decodeTile(frame *Frame, tile Rectangle, pixelFormat PixelFormat) error {
+	subencoding, err := decoder.readByte()
+	if err != nil {
+		return err
+	}
+
+	pixelSize, _ := pixelFormat.compactPixelSize()
+	pixelCount := tile.Area()
+
+	switch {
+	case subencoding == zrleRaw:
+		data, err := decoder.read(pixelCount * pixelSize)
+		if err != nil {
+			return err
+		}
+
+		for index := range pixelCount {
+			x := int(tile.X) + index%int(tile.Width)
+			y := int(tile.Y) + index/int(tile.Width)
+			frame.setPixel(pixelFormat, x, y, pixelFormat.readCompactPixel(data[index*pixelSize:]))
+		}
+
+	case subencoding == zrleSolid:
+		data, err := decoder.read(pixelSize)
+		if err != nil {
+			return err
+		}
+		frame.fill(pixelFormat, tile, pixelFormat.readCompactPixel(data))
+
+	case subencoding >= 2 && subencoding <= 16:
+		paletteSize := int(subencoding)
+		if err := decoder.readPalette(paletteSize, pixelSize, pixelFormat); err != nil {
+			return err
+		}
+
+		bits := zrlePackedBits(paletteSize)
+		rowBytes := (int(tile.Width)*bits + 7) / 8
+		data, err := decoder.read(rowBytes * int(tile.Height))
+		if err != nil {
+			return err
+		}
+
+		mask := byte(1<<bits - 1)
+		for y := 0; y < int(tile.Height); y++ {
+			row := data[y*rowBytes:]
+			for x := 0; x < int(tile.Width); x++ {
+				bitOffset := x * bits
+				index := row[bitOffset/8] >> (8 - bits - bitOffset%8) & mask
+				if int(index) >= paletteSize {
+					return fmt.Errorf("%w: palette index %d", ErrProtocolViolation, index)
+				}
+				frame.setPixel(pixelFormat, int(tile.X)+x, int(tile.Y)+y, decoder.palette[index])
+			}
+		}
+
+	case subencoding == zrlePlainRLE:
+		for index := 0; index < pixelCount; {
+			data, err := decoder.read(pixelSize)
+			if err != nil {
+				return err
+			}
+			value := pixelFormat.readCompactPixel(data)
+
+			runLength, err := decoder.readRunLength()
+			if err != nil {
+				return err
+			}
+
+			if index, err = decoder.setRun(frame, tile, pixelFormat, index, runLength, value); err != nil {
+				return err
+			}
+		}
+
+	case subencoding >= zrlePaletteRLE+2:
+		paletteSize := int(subencoding - zrlePaletteRLE)
+		if err := decoder.readPalette(paletteSize, pixelSize, pixelFormat); err != nil {
+			return err
+		}
+
+		for index := 0; index < pixelCount; {
+			paletteIndex, err := decoder.readByte()
+			if err != nil {
+				return err
+			}
+
+			runLength := 1
+			if paletteIndex&0x80 != 0 {
+				paletteIndex &= 0x7f
+				if runLength, err = decoder.readRunLength(); err != nil {
+					return err
+				}
+			}
+
+			if int(paletteIndex) >= paletteSize {
+				return fmt.Errorf("%w: palette index %d", ErrProtocolViolation, paletteIndex)
+			}
+
+			if index, err = decoder.setRun(frame, tile, pixelFormat, index, runLength, decoder.palette[paletteIndex]); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("%w: zrle subencoding %d", ErrProtocolViolation, subencoding)
+	}
+
+	return nil
+}
+
+func (decoder *zrleDecoder) setRun(frame *Frame, tile Rectangle, pixelFormat PixelFormat, index int, runLength int, value uint32) (int, error) {
+	if index+runLength > tile.Area() {
+		return 0, fmt.Errorf("%w: zrle run exceeds tile", ErrProtocolViolation)
+	}
+
+	for end := index + runLength; index < end; index++ {
+		frame.setPixel(pixelFormat, int(tile.X)+index%int(tile.Width), int(tile.Y)+index/int(tile.Width), value)
+	}
+
+	return index, nil
+}
+
+func (decoder *zrleDecoder) readPalette(paletteSize int, pixelSize int, pixelFormat PixelFormat) error {
+	data, err := decoder.read(paletteSize * pixelSize)
+	if err != nil {
+		return err
+	}
+
+	for index := range paletteSize {
+		decoder.palette[index] = pixelFormat.readCompactPixel(data[index*pixelSize:])
+	}
+
+	return nil
+}
+
+func (decoder *zrleDecoder) readRunLength() (int, error) {
+	runLength := 1
+	for {
+		value, err := decoder.readByte()
+		if err != nil {
+			return 0, err
+		}
+
+		runLength += int(value)
+		if value != 255 {
+			return runLength, nil
+		}
+	}
+}
+
+func (decoder *zrleDecoder) readByte() (byte, error) {
+	data, err := decoder.read(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return data[0], nil
+}
+
+// read returns next length bytes of decompressed data, valid until the next call.
+func (decoder *zrleDecoder) read(length int) ([]byte, error) {
+	if cap(decoder.scratch) < length {
+		decoder.scratch = make([]byte, length)
+	}
+	data := decoder.scratch[:length]
+
+	if _, err := io.ReadFull(decoder.decompressor, data); err != nil {
+		return nil, fmt.Errorf("read zlib stream: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,53 @@
+package rfb
+
+import (
+	"encoding/binary"
+)
+
+// rectangleEncoder appends encoded rectangle data, without rectangle header, to dst.
+type rectangleEncoder interface {
+	Encoding() Encoding
+	Encode(dst []byte, frame *Frame, rectangle Rectangle, pixelFormat PixelFormat) ([]byte, error)
+}
+
+// splitRectangle returns rectangles encoded by encoder, Tight limits size of a single rectangle.
+func splitRectangle(encoder rectangleEncoder, rectangle Rectangle) []Rectangle {
+	if encoder.Encoding() != EncodingTight {
+		return []Rectangle{rectangle}
+	}
+
+	return splitTightRectangle(rectangle)
+}
+
+// appendRectangleHeader appends header of FramebufferUpdate rectangle.
+func appendRectangleHeader(dst []byte, rectangle Rectangle, encoding Encoding) []byte {
+	dst = binary.BigEndian.AppendUint16(dst, rectangle.X)
+	dst = binary.BigEndian.AppendUint16(dst, rectangle.Y)
+	dst = binary.BigEndian.AppendUint16(dst, rectangle.Width)
+	dst = binary.BigEndian.AppendUint16(dst, rectangle.Height)
+
+	return binary.BigEndian.AppendUint32(dst, uint32(encoding))
+}
+
+type rawEncoder struct{}
+
+func (encoder rawEncoder) Encoding() Encoding {
+	return EncodingRaw
+}
+
+func (encoder rawEncoder) Encode(dst []byte, frame *Frame, rectangle Rectangle, pixelFormat PixelFormat) ([]byte, error) {
+	for y := int(rectangle.Y); y < int(rectangle.Y)+int(rectangle.Height); y++ {
+		for x := int(rectangle.X); x < int(rectangle.X)+int(rectangle.Width); x++ {
+			dst = pixelFormat.AppendPixel(dst, frame.pixel(pixelFormat, x, y))
+		}
+	}
+
+	return dst, nil
+}
+
+// appendCopyRect appends data of CopyRect rectangle, the position of source area.
+func appendCopyRect(dst []byte, sourceX uint16, sourceY uint16) []byte {
+	dst = binary.BigEndian.AppendUint16(dst, sourceX)
+
+	return binary.BigEndian.AppendUint16(dst, sourceY)
+}
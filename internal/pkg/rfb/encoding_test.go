@@ -0,0 +1,198 @@
+package rfb
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testPixelFormats = map[string]PixelFormat{
+	"default": DefaultPixelFormat,
+	"bgr32-big-endian": {
+		BitsPerPixel: 32, Depth: 24, BigEndian: true, TrueColor: true,
+		RedMax: 255, GreenMax: 255, BlueMax: 255, RedShift: 0, GreenShift: 8, BlueShift: 16,
+	},
+	"rgb32-high-bytes": {
+		BitsPerPixel: 32, Depth: 24, TrueColor: true,
+		RedMax: 255, GreenMax: 255, BlueMax: 255, RedShift: 24, GreenShift: 16, BlueShift: 8,
+	},
+	"rgb565": {
+		BitsPerPixel: 16, Depth: 16, TrueColor: true,
+		RedMax: 31, GreenMax: 63, BlueMax: 31, RedShift: 11, GreenShift: 5,
+	},
+	"bgr233": {
+		BitsPerPixel: 8, Depth: 8, TrueColor: true,
+		RedMax: 7, GreenMax: 7, BlueMax: 3, RedShift: 0, GreenShift: 3, BlueShift: 6,
+	},
+}
+
+// newTestMixedFrame returns frame with areas hitting all ZRLE subencodings: solid, few colors, long runs and noise.
+func newTestMixedFrame(width int, height int) *Frame {
+	frame := NewFrame(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			offset := (y*width + x) * 3
+			switch {
+			case x < 64 && y < 64:
+				frame.Pixels[offset] = 200
+			case x < 128 && y < 64:
+				frame.Pixels[offset+1] = byte((x / 3 % 3) * 100)
+			case y < 64:
+				frame.Pixels[offset+2] = byte(x / 20 * 40)
+			case x < 64:
+				copy(frame.Pixels[offset:], []byte{byte(x ^ y), byte(x * y), byte(x + y)})
+			default:
+				frame.Pixels[offset] = byte((x + y) / 7 % 40 * 5)
+			}
+		}
+	}
+
+	return frame
+}
+
+// quantize returns frame as seen by client using pixel format.
+func quantize(frame *Frame, pixelFormat PixelFormat) *Frame {
+	quantized := NewFrame(frame.Width, frame.Height)
+	for y := 0; y < frame.Height; y++ {
+		for x := 0; x < frame.Width; x++ {
+			quantized.setPixel(pixelFormat, x, y, frame.pixel(pixelFormat, x, y))
+		}
+	}
+
+	return quantized
+}
+
+func TestZRLERoundTrip(t *testing.T) {
+	frame := newTestMixedFrame(200, 150)
+
+	for name, pixelFormat := range testPixelFormats {
+		encoder := newZRLEEncoder()
+		decoder := newZRLEDecoder()
+		decoded := NewFrame(frame.Width, frame.Height)
+
+		// rectangles share zlib stream
+		for _, rectangle := range []Rectangle{{Width: 200, Height: 100}, {Y: 100, Width: 200, Height: 50}, {X: 10, Y: 10, Width: 1, Height: 1}} {
+			data, err := encoder.Encode(nil, frame, rectangle, pixelFormat)
+			assert.NoError(t, err, name)
+
+			err = decoder.Decode(bytes.NewReader(data), decoded, rectangle, pixelFormat)
+			assert.NoError(t, err, name)
+		}
+
+		assert.Equal(t, quantize(frame, pixelFormat).Pixels, decoded.Pixels, name)
+	}
+}
+
+func TestZRLESubencodings(t *testing.T) {
+	frame := newTestMixedFrame(256, 128)
+	encoder := newZRLEEncoder()
+
+	subencoding := func(x int, y int) uint8 {
+		tile := encoder.encodeTile(nil, frame, Rectangle{X: uint16(x), Y: uint16(y), Width: 64, Height: 64}, DefaultPixelFormat)
+		return tile[0]
+	}
+
+	assert.Equal(t, zrleSolid, subencoding(0, 0))
+	assert.Equal(t, uint8(3), subencoding(64, 0))
+	assert.Equal(t, zrleRaw, subencoding(0, 64))
+	assert.Greater(t, subencoding(128, 64), zrlePaletteRLE)
+}
+
+// decodeTestTight decodes lossless Tight rectangle using fill or basic compression of stream 0.
+func decodeTestTight(t *testing.T, data []byte, decompressor *io.ReadCloser, compressed *bytes.Buffer, frame *Frame, rectangle Rectangle, pixelFormat PixelFormat) {
+	pixelSize := pixelFormat.BytesPerPixel()
+	if tightPixelRGB(pixelFormat) {
+		pixelSize = 3
+	}
+
+	readPixel := func(data []byte) uint32 {
+		if tightPixelRGB(pixelFormat) {
+			return pixelFormat.Pack(data[0], data[1], data[2])
+		}
+		return pixelFormat.ReadPixel(data)
+	}
+
+	control, data := data[0], data[1:]
+	if control == tightFill {
+		frame.fill(pixelFormat, rectangle, readPixel(data))
+		return
+	}
+	assert.Equal(t, tightBasic, control)
+
+	pixels := data
+	if length := rectangle.Area() * pixelSize; length >= tightMinCompression {
+		compactLength, offset := 0, 0
+		for shift := 0; ; shift += 7 {
+			compactLength |= int(data[offset]&0x7f) << shift
+			offset++
+			if data[offset-1]&0x80 == 0 || offset == 3 {
+				break
+			}
+		}
+		compressed.Write(data[offset : offset+compactLength])
+
+		if *decompressor == nil {
+			var err error
+			*decompressor, err = zlib.NewReader(compressed)
+			assert.NoError(t, err)
+		}
+
+		pixels = make([]byte, length)
+		_, err := io.ReadFull(*decompressor, pixels)
+		assert.NoError(t, err)
+	}
+
+	for index := range rectangle.Area() {
+		x := int(rectangle.X) + index%int(rectangle.Width)
+		y := int(rectangle.Y) + index/int(rectangle.Width)
+		frame.setPixel(pixelFormat, x, y, readPixel(pixels[index*pixelSize:]))
+	}
+}
+
+func TestTightRoundTrip(t *testing.T) {
+	frame := newTestMixedFrame(300, 260)
+
+	for name, pixelFormat := range testPixelFormats {
+		encoder := newTightEncoder()
+		decoded := NewFrame(frame.Width, frame.Height)
+
+		var (
+			decompressor io.ReadCloser
+			compressed   bytes.Buffer
+		)
+
+		rectangles := []Rectangle{{Width: 64, Height: 64}, {X: 1, Y: 1, Width: 2, Height: 1}}
+		rectangles = append(rectangles, splitTightRectangle(frame.Bounds())...)
+
+		for _, rectangle := range rectangles {
+			data, err := encoder.Encode(nil, frame, rectangle, pixelFormat)
+			assert.NoError(t, err, name)
+
+			decodeTestTight(t, data, &decompressor, &compressed, decoded, rectangle, pixelFormat)
+		}
+
+		assert.Equal(t, quantize(frame, pixelFormat).Pixels, decoded.Pixels, name)
+	}
+}
+
+func TestSplitTightRectangle(t *testing.T) {
+	parts := splitTightRectangle(Rectangle{X: 10, Width: 3000, Height: 100})
+
+	area := 0
+	for _, part := range parts {
+		assert.LessOrEqual(t, int(part.Width), tightMaxWidth)
+		assert.LessOrEqual(t, part.Area(), tightMaxArea)
+		area += part.Area()
+	}
+	assert.Equal(t, 300000, area)
+	assert.Equal(t, Rectangle{X: 10, Width: 2048, Height: 32}, parts[0])
+}
+
+func TestAppendCompactLength(t *testing.T) {
+	assert.Equal(t, []byte{0x7f}, appendCompactLength(nil, 127))
+	assert.Equal(t, []byte{0x80, 0x01}, appendCompactLength(nil, 128))
+	assert.Equal(t, []byte{0xff, 0xff, 0x01}, appendCompactLength(nil, 0x7fff))
+}
@@ -0,0 +1,217 @@
+package rfb
+
+import (
+	"bytes"
+	"hash/maphash"
+)
+
+// damageTileSize is the edge of square tiles compared when looking for changed areas.
+const damageTileSize = 16
+
+// Frame is RGB24 image, three bytes per pixel in red, green, blue order.
+type Frame struct {
+	Pixels []byte
+	Width  int
+	Height int
+}
+
+func NewFrame(width int, height int) *Frame {
+	return &Frame{
+		Pixels: make([]byte, width*height*3),
+		Width:  width,
+		Height: height,
+	}
+}
+
+func (frame *Frame) Bounds() Rectangle {
+	return Rectangle{Width: uint16(frame.Width), Height: uint16(frame.Height)}
+}
+
+func (frame *Frame) row(y int, x int, width int) []byte {
+	offset := (y*frame.Width + x) * 3
+
+	return frame.Pixels[offset : offset+width*3]
+}
+
+// pixel returns value of pixel in given pixel format.
+func (frame *Frame) pixel(pixelFormat PixelFormat, x int, y int) uint32 {
+	offset := (y*frame.Width + x) * 3
+
+	return pixelFormat.Pack(frame.Pixels[offset], frame.Pixels[offset+1], frame.Pixels[offset+2])
+}
+
+// CopyRectangle copies area of source frame into the same area of frame, frames must have equal size.
+func (frame *Frame) CopyRectangle(source *Frame, rectangle Rectangle) {
+	for y := int(rectangle.Y); y < int(rectangle.Y)+int(rectangle.Height); y++ {
+		copy(frame.row(y, int(rectangle.X), int(rectangle.Width)), source.row(y, int(rectangle.X), int(rectangle.Width)))
+	}
+}
+
+// MoveRows copies height rows starting at sourceY to destinationY within the frame, overlapping ranges are allowed.
+func (frame *Frame) MoveRows(sourceY int, destinationY int, height int) {
+	copy(frame.row(destinationY, 0, frame.Width*height), frame.row(sourceY, 0, frame.Width*height))
+}
+
+// Damage returns areas of current frame inside of region that differ from reference frame. Changed tiles are merged
+// horizontally into runs, runs with the same span in consecutive tile rows are merged vertically.
+func Damage(reference *Frame, current *Frame, region Rectangle) []Rectangle {
+	region = region.Intersect(current.Bounds())
+	if region.Empty() {
+		return nil
+	}
+
+	var (
+		rectangles []Rectangle
+		open       []int // indexes of rectangles that may grow down
+	)
+
+	for tileY := int(region.Y); tileY < int(region.Y)+int(region.Height); tileY += damageTileSize {
+		tileHeight := min(damageTileSize, int(region.Y)+int(region.Height)-tileY)
+
+		var (
+			runs     []Rectangle
+			runStart = -1
+		)
+
+		regionEnd := int(region.X) + int(region.Width)
+		for tileX := int(region.X); tileX < regionEnd+damageTileSize; tileX += damageTileSize {
+			changed := false
+			if tileX < regionEnd {
+				tileWidth := min(damageTileSize, regionEnd-tileX)
+				changed = tileChanged(reference, current, tileX, tileY, tileWidth, tileHeight)
+			}
+
+			switch {
+			case changed && runStart < 0:
+				runStart = tileX
+			case !changed && runStart >= 0:
+				runs = append(runs, Rectangle{
+					X:      uint16(runStart),
+					Y:      uint16(tileY),
+					Width:  uint16(min(tileX, regionEnd) - runStart),
+					Height: uint16(tileHeight),
+				})
+				runStart = -1
+			}
+		}
+
+		var nextOpen []int
+		for _, run := range runs {
+			merged := false
+			for _, index := range open {
+				candidate := &rectangles[index]
+				if candidate.X == run.X && candidate.Width == run.Width {
+					candidate.Height += run.Height
+					nextOpen = append(nextOpen, index)
+					merged = true
+					break
+				}
+			}
+
+			if !merged {
+				rectangles = append(rectangles, run)
+				nextOpen = append(nextOpen, len(rectangles)-1)
+			}
+		}
+		open = nextOpen
+	}
+
+	return rectangles
+}
+
+func tileChanged(reference *Frame, current *Frame, x int, y int, width int, height int) bool {
+	for row := y; row < y+height; row++ {
+		if !bytes.Equal(reference.row(row, x, width), current.row(row, x, width)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Scroll describes full width vertical movement of rows, rows from SourceY are found at DestinationY.
+type Scroll struct {
+	SourceY      int
+	DestinationY int
+	Height       int
+}
+
+// DetectScroll looks for the longest run of full width rows of current frame equal to rows of reference frame shifted
+// vertically by a constant offset. Offsets are voted by changed rows whose content is unique in reference frame, so
+// uniform backgrounds do not produce false matches. Runs shorter than minHeight are ignored.
+func DetectScroll(reference *Frame, current *Frame, minHeight int) (Scroll, bool) {
+	if reference.Width != current.Width || reference.Height != current.Height || current.Height < minHeight {
+		return Scroll{}, false
+	}
+
+	seed := maphash.MakeSeed()
+	referenceHashes := rowHashes(seed, reference)
+	currentHashes := rowHashes(seed, current)
+
+	referenceRows := make(map[uint64]int, len(referenceHashes))
+	for y, hash := range referenceHashes {
+		if _, found := referenceRows[hash]; found {
+			referenceRows[hash] = -1
+			continue
+		}
+		referenceRows[hash] = y
+	}
+
+	votes := make(map[int]int)
+	for y, hash := range currentHashes {
+		if hash == referenceHashes[y] {
+			continue
+		}
+
+		if referenceY, found := referenceRows[hash]; found && referenceY >= 0 {
+			votes[y-referenceY]++
+		}
+	}
+
+	bestOffset, bestVotes := 0, 0
+	for offset, count := range votes {
+		if count > bestVotes || (count == bestVotes && offset < bestOffset) {
+			bestOffset, bestVotes = offset, count
+		}
+	}
+	if bestVotes == 0 {
+		return Scroll{}, false
+	}
+
+	var best Scroll
+	runStart := -1
+	for y := 0; y <= current.Height; y++ {
+		matches := false
+		if y < current.Height {
+			referenceY := y - bestOffset
+			matches = referenceY >= 0 && referenceY < reference.Height &&
+				currentHashes[y] == referenceHashes[referenceY] &&
+				bytes.Equal(current.row(y, 0, current.Width), reference.row(referenceY, 0, reference.Width))
+		}
+
+		switch {
+		case matches && runStart < 0:
+			runStart = y
+		case !matches && runStart >= 0:
+			if y-runStart > best.Height {
+				best = Scroll{SourceY: runStart - bestOffset, DestinationY: runStart, Height: y - runStart}
+			}
+			runStart = -1
+		}
+	}
+
+	if best.Height < minHeight {
+		return Scroll{}, false
+	}
+
+	return best, true
+}
+
+func rowHashes(seed maphash.Seed, frame *Frame) []uint64 {
+	hashes := make([]uint64, frame.Height)
+	for y := range hashes {
+		hashes[y] = maphash.Bytes(seed, frame.row(y, 0, frame.Width))
+	}
+
+	return hashes
+}
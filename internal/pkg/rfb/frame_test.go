@@ -0,0 +1,87 @@
+package rfb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFrame(width int, height int) *Frame {
+	frame := NewFrame(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			offset := (y*width + x) * 3
+			frame.Pixels[offset] = byte(x)
+			frame.Pixels[offset+1] = byte(y)
+			frame.Pixels[offset+2] = byte(x ^ y)
+		}
+	}
+
+	return frame
+}
+
+func setTestPixel(frame *Frame, x int, y int) {
+	offset := (y*frame.Width + x) * 3
+	frame.Pixels[offset] ^= 0xff
+}
+
+func TestDamage(t *testing.T) {
+	reference := newTestFrame(100, 50)
+	current := newTestFrame(100, 50)
+
+	assert.Empty(t, Damage(reference, current, current.Bounds()))
+
+	setTestPixel(current, 1, 1)
+	setTestPixel(current, 20, 1)
+	setTestPixel(current, 20, 20)
+	setTestPixel(current, 99, 49)
+
+	assert.Equal(t, []Rectangle{
+		{X: 0, Y: 0, Width: 32, Height: 16},
+		{X: 16, Y: 16, Width: 16, Height: 16},
+		{X: 96, Y: 48, Width: 4, Height: 2},
+	}, Damage(reference, current, current.Bounds()))
+
+	// tiles are aligned to the region
+	assert.Equal(t, []Rectangle{
+		{X: 90, Y: 40, Width: 10, Height: 10},
+	}, Damage(reference, current, Rectangle{X: 90, Y: 40, Width: 100, Height: 100}))
+}
+
+func TestDamageMergesRows(t *testing.T) {
+	reference := newTestFrame(64, 64)
+	current := newTestFrame(64, 64)
+
+	for y := 0; y < 40; y++ {
+		setTestPixel(current, 17, y)
+	}
+
+	assert.Equal(t, []Rectangle{{X: 16, Y: 0, Width: 16, Height: 48}}, Damage(reference, current, current.Bounds()))
+}
+
+func TestDetectScroll(t *testing.T) {
+	reference := newTestFrame(64, 200)
+	current := newTestFrame(64, 200)
+
+	// content moves up by 10 rows, the bottom rows are new
+	current.MoveRows(10, 0, 190)
+	for y := 190; y < 200; y++ {
+		setTestPixel(current, 0, y)
+	}
+
+	scroll, found := DetectScroll(reference, current, 32)
+	assert.True(t, found)
+	assert.Equal(t, Scroll{SourceY: 10, DestinationY: 0, Height: 190}, scroll)
+
+	reference.MoveRows(scroll.SourceY, scroll.DestinationY, scroll.Height)
+	assert.Equal(t, []Rectangle{{X: 0, Y: 176, Width: 16, Height: 24}}, Damage(reference, current, current.Bounds()))
+}
+
+func TestDetectScrollIgnoresUniformRows(t *testing.T) {
+	reference := NewFrame(64, 100)
+	current := NewFrame(64, 100)
+	setTestPixel(current, 0, 0)
+
+	_, found := DetectScroll(reference, current, 32)
+	assert.False(t, found)
+}
@@ -0,0 +1,54 @@
+package rfb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+)
+
+// ReadPasswordFile returns the first line of the file, surrounding whitespace is trimmed.
+func ReadPasswordFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read password file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() {
+		return "", fmt.Errorf("%w: %s", ErrPasswordEmpty, path)
+	}
+
+	password := string(bytes.TrimSpace(scanner.Bytes()))
+	if password == "" {
+		return "", fmt.Errorf("%w: %s", ErrPasswordEmpty, path)
+	}
+
+	return password, nil
+}
+
+// ValidateListenAddress checks that server exposed beyond loopback interface is protected by password.
+func ValidateListenAddress(address string, password string) error {
+	if password != "" {
+		return nil
+	}
+
+	loopback, err := utils.IsLoopbackAddress(address)
+	if err != nil {
+		return fmt.Errorf("parse listen address: %w", err)
+	}
+
+	if loopback {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrPasswordRequired, address)
+}
+
+var (
+	ErrPasswordRequired = errors.New("password required for non-loopback listen address")
+	ErrPasswordEmpty    = errors.New("password file is empty")
+)
@@ -0,0 +1,397 @@
+package rfb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ProtocolVersion is RFB protocol version exchanged in handshake.
+type ProtocolVersion string
+
+const (
+	ProtocolVersion33 ProtocolVersion = "RFB 003.003\n"
+	ProtocolVersion37 ProtocolVersion = "RFB 003.007\n"
+	ProtocolVersion38 ProtocolVersion = "RFB 003.008\n"
+)
+
+// SecurityType identifies authentication scheme.
+type SecurityType uint8
+
+const (
+	SecurityTypeInvalid SecurityType = 0
+	SecurityTypeNone    SecurityType = 1
+	SecurityTypeVNCAuth SecurityType = 2
+)
+
+// Encoding identifies rectangle encoding, negative values are pseudo-encodings.
+type Encoding int32
+
+const (
+	EncodingRaw         Encoding = 0
+	EncodingCopyRect    Encoding = 1
+	EncodingTight       Encoding = 7
+	EncodingZRLE        Encoding = 16
+	EncodingDesktopSize Encoding = -223
+)
+
+func (encoding Encoding) String() string {
+	switch encoding {
+	case EncodingRaw:
+		return "raw"
+	case EncodingCopyRect:
+		return "copy-rect"
+	case EncodingTight:
+		return "tight"
+	case EncodingZRLE:
+		return "zrle"
+	case EncodingDesktopSize:
+		return "desktop-size"
+	default:
+		return fmt.Sprintf("encoding(%d)", int32(encoding))
+	}
+}
+
+// parseProtocolVersion returns version supported by both sides for version sent by the peer. Versions between 3.3 and
+// 3.7 are treated as 3.3 and versions above 3.8 as 3.8, as the specification recommends.
+func parseProtocolVersion(value []byte) (ProtocolVersion, error) {
+	var major, minor int
+	if _, err := fmt.Sscanf(string(value), "RFB %03d.%03d\n", &major, &minor); err != nil || major != 3 {
+		return "", fmt.Errorf("%w: protocol version %q", ErrProtocolViolation, value)
+	}
+
+	switch {
+	case minor >= 8:
+		return ProtocolVersion38, nil
+	case minor == 7:
+		return ProtocolVersion37, nil
+	default:
+		return ProtocolVersion33, nil
+	}
+}
+
+// Client to server message types.
+const (
+	messageSetPixelFormat           uint8 = 0
+	messageSetEncodings             uint8 = 2
+	messageFramebufferUpdateRequest uint8 = 3
+	messageKeyEvent                 uint8 = 4
+	messagePointerEvent             uint8 = 5
+	messageClientCutText            uint8 = 6
+)
+
+// Server to client message types.
+const (
+	messageFramebufferUpdate uint8 = 0
+	messageBell              uint8 = 2
+	messageServerCutText     uint8 = 3
+)
+
+// Button mask bits of PointerEvent message.
+const (
+	ButtonLeft       uint8 = 1 << 0
+	ButtonMiddle     uint8 = 1 << 1
+	ButtonRight      uint8 = 1 << 2
+	ButtonWheelUp    uint8 = 1 << 3
+	ButtonWheelDown  uint8 = 1 << 4
+	ButtonWheelLeft  uint8 = 1 << 5
+	ButtonWheelRight uint8 = 1 << 6
+)
+
+// Rectangle is an area of the framebuffer.
+type Rectangle struct {
+	X, Y          uint16
+	Width, Height uint16
+}
+
+func (rectangle Rectangle) Area() int {
+	return int(rectangle.Width) * int(rectangle.Height)
+}
+
+func (rectangle Rectangle) Empty() bool {
+	return rectangle.Width == 0 || rectangle.Height == 0
+}
+
+// Union returns the smallest rectangle containing both rectangles.
+func (rectangle Rectangle) Union(other Rectangle) Rectangle {
+	if rectangle.Empty() {
+		return other
+	}
+	if other.Empty() {
+		return rectangle
+	}
+
+	x0 := min(rectangle.X, other.X)
+	y0 := min(rectangle.Y, other.Y)
+	x1 := max(int(rectangle.X)+int(rectangle.Width), int(other.X)+int(other.Width))
+	y1 := max(int(rectangle.Y)+int(rectangle.Height), int(other.Y)+int(other.Height))
+
+	return Rectangle{X: x0, Y: y0, Width: uint16(x1 - int(x0)), Height: uint16(y1 - int(y0))}
+}
+
+// Intersect returns part of rectangle inside of other.
+func (rectangle Rectangle) Intersect(other Rectangle) Rectangle {
+	x0 := max(int(rectangle.X), int(other.X))
+	y0 := max(int(rectangle.Y), int(other.Y))
+	x1 := min(int(rectangle.X)+int(rectangle.Width), int(other.X)+int(other.Width))
+	y1 := min(int(rectangle.Y)+int(rectangle.Height), int(other.Y)+int(other.Height))
+
+	if x1 <= x0 || y1 <= y0 {
+		return Rectangle{}
+	}
+
+	return Rectangle{X: uint16(x0), Y: uint16(y0), Width: uint16(x1 - x0), Height: uint16(y1 - y0)}
+}
+
+// PixelFormat describes how pixel values are transferred on the wire.
+type PixelFormat struct {
+	BitsPerPixel uint8
+	Depth        uint8
+	BigEndian    bool
+	TrueColor    bool
+	RedMax       uint16
+	GreenMax     uint16
+	BlueMax      uint16
+	RedShift     uint8
+	GreenShift   uint8
+	BlueShift    uint8
+}
+
+// DefaultPixelFormat is 32 bits per pixel little endian true color format announced in ServerInit.
+var DefaultPixelFormat = PixelFormat{
+	BitsPerPixel: 32,
+	Depth:        24,
+	TrueColor:    true,
+	RedMax:       255,
+	GreenMax:     255,
+	BlueMax:      255,
+	RedShift:     16,
+	GreenShift:   8,
+	BlueShift:    0,
+}
+
+func (pixelFormat PixelFormat) Valid() error {
+	switch pixelFormat.BitsPerPixel {
+	case 8, 16, 32:
+	default:
+		return fmt.Errorf("%w: %d bits per pixel", ErrPixelFormatUnsupported, pixelFormat.BitsPerPixel)
+	}
+
+	if !pixelFormat.TrueColor {
+		return fmt.Errorf("%w: color map", ErrPixelFormatUnsupported)
+	}
+
+	if pixelFormat.RedMax == 0 || pixelFormat.GreenMax == 0 || pixelFormat.BlueMax == 0 {
+		return fmt.Errorf("%w: zero color max", ErrPixelFormatUnsupported)
+	}
+
+	return nil
+}
+
+func (pixelFormat PixelFormat) BytesPerPixel() int {
+	return int(pixelFormat.BitsPerPixel) / 8
+}
+
+// Pack converts 8 bit color components into pixel value.
+func (pixelFormat PixelFormat) Pack(red, green, blue uint8) uint32 {
+	return scale(red, pixelFormat.RedMax)<<pixelFormat.RedShift |
+		scale(green, pixelFormat.GreenMax)<<pixelFormat.GreenShift |
+		scale(blue, pixelFormat.BlueMax)<<pixelFormat.BlueShift
+}
+
+// Unpack converts pixel value into 8 bit color components.
+func (pixelFormat PixelFormat) Unpack(value uint32) (uint8, uint8, uint8) {
+	return unscale(value>>pixelFormat.RedShift, pixelFormat.RedMax),
+		unscale(value>>pixelFormat.GreenShift, pixelFormat.GreenMax),
+		unscale(value>>pixelFormat.BlueShift, pixelFormat.BlueMax)
+}
+
+func scale(component uint8, componentMax uint16) uint32 {
+	if componentMax == 255 {
+		return uint32(component)
+	}
+
+	return (uint32(component)*uint32(componentMax) + 127) / 255
+}
+
+func unscale(value uint32, componentMax uint16) uint8 {
+	value &= uint32(componentMax)
+	if componentMax == 255 {
+		return uint8(value)
+	}
+
+	return uint8((value*255 + uint32(componentMax)/2) / uint32(componentMax))
+}
+
+// AppendPixel appends pixel value in wire representation.
+func (pixelFormat PixelFormat) AppendPixel(dst []byte, value uint32) []byte {
+	switch pixelFormat.BitsPerPixel {
+	case 8:
+		return append(dst, uint8(value))
+	case 16:
+		if pixelFormat.BigEndian {
+			return binary.BigEndian.AppendUint16(dst, uint16(value))
+		}
+		return binary.LittleEndian.AppendUint16(dst, uint16(value))
+	default:
+		if pixelFormat.BigEndian {
+			return binary.BigEndian.AppendUint32(dst, value)
+		}
+		return binary.LittleEndian.AppendUint32(dst, value)
+	}
+}
+
+// ReadPixel reads pixel value in wire representation.
+func (pixelFormat PixelFormat) ReadPixel(src []byte) uint32 {
+	switch pixelFormat.BitsPerPixel {
+	case 8:
+		return uint32(src[0])
+	case 16:
+		if pixelFormat.BigEndian {
+			return uint32(binary.BigEndian.Uint16(src))
+		}
+		return uint32(binary.LittleEndian.Uint16(src))
+	default:
+		if pixelFormat.BigEndian {
+			return binary.BigEndian.Uint32(src)
+		}
+		return binary.LittleEndian.Uint32(src)
+	}
+}
+
+// compactPixelSize returns size of CPIXEL used by ZRLE and TPIXEL used by Tight, and whether three byte pixel holds
+// the least significant bytes of the value.
+func (pixelFormat PixelFormat) compactPixelSize() (int, bool) {
+	if !pixelFormat.TrueColor || pixelFormat.BitsPerPixel != 32 || pixelFormat.Depth > 24 {
+		return pixelFormat.BytesPerPixel(), false
+	}
+
+	colorMask := uint32(pixelFormat.RedMax)<<pixelFormat.RedShift |
+		uint32(pixelFormat.GreenMax)<<pixelFormat.GreenShift |
+		uint32(pixelFormat.BlueMax)<<pixelFormat.BlueShift
+
+	switch {
+	case colorMask&0xff000000 == 0:
+		return 3, true
+	case colorMask&0x000000ff == 0:
+		return 3, false
+	default:
+		return 4, false
+	}
+}
+
+// appendCompactPixel appends CPIXEL representation of pixel value.
+func (pixelFormat PixelFormat) appendCompactPixel(dst []byte, value uint32) []byte {
+	size, leastSignificant := pixelFormat.compactPixelSize()
+	if size != 3 {
+		return pixelFormat.AppendPixel(dst, value)
+	}
+
+	if !leastSignificant {
+		value >>= 8
+	}
+
+	if pixelFormat.BigEndian {
+		return append(dst, byte(value>>16), byte(value>>8), byte(value))
+	}
+
+	return append(dst, byte(value), byte(value>>8), byte(value>>16))
+}
+
+// readCompactPixel reads CPIXEL representation of pixel value.
+func (pixelFormat PixelFormat) readCompactPixel(src []byte) uint32 {
+	size, leastSignificant := pixelFormat.compactPixelSize()
+	if size != 3 {
+		return pixelFormat.ReadPixel(src)
+	}
+
+	var value uint32
+	if pixelFormat.BigEndian {
+		value = uint32(src[0])<<16 | uint32(src[1])<<8 | uint32(src[2])
+	} else {
+		value = uint32(src[2])<<16 | uint32(src[1])<<8 | uint32(src[0])
+	}
+
+	if !leastSignificant {
+		value <<= 8
+	}
+
+	return value
+}
+
+func (pixelFormat PixelFormat) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 16)
+	data[0] = pixelFormat.BitsPerPixel
+	data[1] = pixelFormat.Depth
+	data[2] = boolByte(pixelFormat.BigEndian)
+	data[3] = boolByte(pixelFormat.TrueColor)
+	binary.BigEndian.PutUint16(data[4:], pixelFormat.RedMax)
+	binary.BigEndian.PutUint16(data[6:], pixelFormat.GreenMax)
+	binary.BigEndian.PutUint16(data[8:], pixelFormat.BlueMax)
+	data[10] = pixelFormat.RedShift
+	data[11] = pixelFormat.GreenShift
+	data[12] = pixelFormat.BlueShift
+
+	return data, nil
+}
+
+func (pixelFormat *PixelFormat) UnmarshalBinary(data []byte) error {
+	if len(data) != 16 {
+		return fmt.Errorf("%w: pixel format must have 16 bytes", ErrProtocolViolation)
+	}
+
+	*pixelFormat = PixelFormat{
+		BitsPerPixel: data[0],
+		Depth:        data[1],
+		BigEndian:    data[2] != 0,
+		TrueColor:    data[3] != 0,
+		RedMax:       binary.BigEndian.Uint16(data[4:]),
+		GreenMax:     binary.BigEndian.Uint16(data[6:]),
+		BlueMax:      binary.BigEndian.Uint16(data[8:]),
+		RedShift:     data[10],
+		GreenShift:   data[11],
+		BlueShift:    data[12],
+	}
+
+	return nil
+}
+
+func boolByte(value bool) byte {
+	if value {
+		return 1
+	}
+
+	return 0
+}
+
+// readString reads string prefixed by 32 bit length, used by failure reasons and desktop names.
+func readString(reader io.Reader, maxLength uint32) (string, error) {
+	var length uint32
+	if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+
+	if length > maxLength {
+		return "", fmt.Errorf("%w: string of %d bytes", ErrProtocolViolation, length)
+	}
+
+	value := make([]byte, length)
+	if _, err := io.ReadFull(reader, value); err != nil {
+		return "", err
+	}
+
+	return string(value), nil
+}
+
+func appendString(dst []byte, value string) []byte {
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(value)))
+
+	return append(dst, value...)
+}
+
+var (
+	ErrProtocolViolation      = errors.New("rfb protocol violation")
+	ErrPixelFormatUnsupported = errors.New("rfb pixel format unsupported")
+	ErrAuthenticationFailed   = errors.New("rfb authentication failed")
+)
@@ -0,0 +1,122 @@
+package rfb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProtocolVersion(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected ProtocolVersion
+	}{
+		{"RFB 003.003\n", ProtocolVersion33},
+		{"RFB 003.005\n", ProtocolVersion33},
+		{"RFB 003.007\n", ProtocolVersion37},
+		{"RFB 003.008\n", ProtocolVersion38},
+		{"RFB 003.889\n", ProtocolVersion38},
+	}
+
+	for _, test := range tests {
+		version, err := parseProtocolVersion([]byte(test.value))
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, test.expected, version, test.value)
+	}
+
+	_, err := parseProtocolVersion([]byte("RFB 004.000\n"))
+	assert.ErrorIs(t, err, ErrProtocolViolation)
+
+	_, err = parseProtocolVersion([]byte("HTTP/1.1 200"))
+	assert.ErrorIs(t, err, ErrProtocolViolation)
+}
+
+func TestPixelFormatMarshalRoundTrip(t *testing.T) {
+	pixelFormat := PixelFormat{
+		BitsPerPixel: 16,
+		Depth:        16,
+		BigEndian:    true,
+		TrueColor:    true,
+		RedMax:       31,
+		GreenMax:     63,
+		BlueMax:      31,
+		RedShift:     11,
+		GreenShift:   5,
+		BlueShift:    0,
+	}
+
+	data, err := pixelFormat.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Len(t, data, 16)
+
+	var decoded PixelFormat
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, pixelFormat, decoded)
+	assert.NoError(t, decoded.Valid())
+}
+
+func TestPixelFormatPack(t *testing.T) {
+	assert.Equal(t, uint32(0x00102030), DefaultPixelFormat.Pack(0x10, 0x20, 0x30))
+	assert.Equal(t, []byte{0x30, 0x20, 0x10, 0x00}, DefaultPixelFormat.AppendPixel(nil, 0x00102030))
+
+	rgb565 := PixelFormat{BitsPerPixel: 16, Depth: 16, TrueColor: true, RedMax: 31, GreenMax: 63, BlueMax: 31, RedShift: 11, GreenShift: 5}
+	value := rgb565.Pack(255, 0, 255)
+	assert.Equal(t, uint32(0xf81f), value)
+	assert.Equal(t, []byte{0x1f, 0xf8}, rgb565.AppendPixel(nil, value))
+
+	red, green, blue := rgb565.Unpack(value)
+	assert.Equal(t, []uint8{255, 0, 255}, []uint8{red, green, blue})
+}
+
+func TestPixelFormatCompactPixel(t *testing.T) {
+	size, _ := DefaultPixelFormat.compactPixelSize()
+	assert.Equal(t, 3, size)
+	assert.Equal(t, []byte{0x30, 0x20, 0x10}, DefaultPixelFormat.appendCompactPixel(nil, 0x00102030))
+	assert.Equal(t, uint32(0x00102030), DefaultPixelFormat.readCompactPixel([]byte{0x30, 0x20, 0x10}))
+
+	shifted := DefaultPixelFormat
+	shifted.RedShift, shifted.GreenShift, shifted.BlueShift = 24, 16, 8
+	assert.Equal(t, []byte{0x30, 0x20, 0x10}, shifted.appendCompactPixel(nil, 0x10203000))
+	assert.Equal(t, uint32(0x10203000), shifted.readCompactPixel([]byte{0x30, 0x20, 0x10}))
+}
+
+func TestPixelFormatValid(t *testing.T) {
+	assert.NoError(t, DefaultPixelFormat.Valid())
+
+	colorMap := DefaultPixelFormat
+	colorMap.TrueColor = false
+	assert.ErrorIs(t, colorMap.Valid(), ErrPixelFormatUnsupported)
+
+	bits24 := DefaultPixelFormat
+	bits24.BitsPerPixel = 24
+	assert.ErrorIs(t, bits24.Valid(), ErrPixelFormatUnsupported)
+}
+
+func TestVNCAuthResponse(t *testing.T) {
+	challenge := []byte("0123456789abcdef")
+
+	response, err := vncAuthResponse("secret", challenge)
+	assert.NoError(t, err)
+	assert.Len(t, response, 16)
+	assert.NotEqual(t, challenge, response)
+
+	assert.True(t, verifyVNCAuthResponse("secret", challenge, response))
+	assert.False(t, verifyVNCAuthResponse("Secret", challenge, response))
+
+	// only the first eight bytes of password are used
+	long, err := vncAuthResponse("password-with-suffix", challenge)
+	assert.NoError(t, err)
+	assert.True(t, verifyVNCAuthResponse("password", challenge, long))
+
+	_, err = vncAuthResponse("secret", challenge[:8])
+	assert.ErrorIs(t, err, ErrProtocolViolation)
+}
+
+func TestValidateListenAddress(t *testing.T) {
+	assert.NoError(t, ValidateListenAddress("127.0.0.1:5900", ""))
+	assert.NoError(t, ValidateListenAddress("localhost:5900", ""))
+	assert.NoError(t, ValidateListenAddress("[::1]:5900", ""))
+	assert.NoError(t, ValidateListenAddress("0.0.0.0:5900", "secret"))
+	assert.ErrorIs(t, ValidateListenAddress("0.0.0.0:5900", ""), ErrPasswordRequired)
+	assert.ErrorIs(t, ValidateListenAddress(":5900", ""), ErrPasswordRequired)
+}
@@ -0,0 +1,145 @@
+package rfb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type ServerOptions struct {
+	password     string
+	desktopName  string
+	keyboardSink peripheralSDK.KeyboardSink
+	mouseSink    peripheralSDK.MouseEventHandler
+	maxFrameRate int
+	logger       *slog.Logger
+}
+
+type ServerOpt func(*ServerOptions)
+
+func defaultServerOptions() ServerOptions {
+	return ServerOptions{
+		desktopName:  "orbiqd",
+		maxFrameRate: 30,
+		logger:       slog.New(slog.DiscardHandler),
+	}
+}
+
+// WithServerPassword enables VNC authentication, only the first eight bytes of password are significant.
+func WithServerPassword(password string) ServerOpt {
+	return func(options *ServerOptions) {
+		options.password = password
+	}
+}
+
+func WithServerDesktopName(desktopName string) ServerOpt {
+	return func(options *ServerOptions) {
+		options.desktopName = desktopName
+	}
+}
+
+// WithServerKeyboardSink sets sink receiving key events of clients, key events are ignored without it.
+func WithServerKeyboardSink(keyboardSink peripheralSDK.KeyboardSink) ServerOpt {
+	return func(options *ServerOptions) {
+		options.keyboardSink = keyboardSink
+	}
+}
+
+// WithServerMouseSink sets sink receiving pointer events of clients, pointer events are ignored without it.
+func WithServerMouseSink(mouseSink peripheralSDK.MouseEventHandler) ServerOpt {
+	return func(options *ServerOptions) {
+		options.mouseSink = mouseSink
+	}
+}
+
+// WithServerMaxFrameRate limits how often display source is polled for new frames.
+func WithServerMaxFrameRate(maxFrameRate int) ServerOpt {
+	return func(options *ServerOptions) {
+		options.maxFrameRate = maxFrameRate
+	}
+}
+
+func WithServerLogger(logger *slog.Logger) ServerOpt {
+	return func(options *ServerOptions) {
+		options.logger = logger
+	}
+}
+
+// Server exposes display source as RFB desktop and forwards input of clients to keyboard and mouse sinks.
+type Server struct {
+	displaySource peripheralSDK.DisplaySource
+	options       ServerOptions
+
+	logger *slog.Logger
+}
+
+func NewServer(displaySource peripheralSDK.DisplaySource, opts ...ServerOpt) (*Server, error) {
+	options := defaultServerOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.maxFrameRate < 1 || options.maxFrameRate > 120 {
+		return nil, fmt.Errorf("%w: max frame rate %d", ErrInvalidServerConfiguration, options.maxFrameRate)
+	}
+
+	return &Server{
+		displaySource: displaySource,
+		options:       options,
+		logger:        options.logger,
+	}, nil
+}
+
+// Serve accepts connections until context is done or listener fails. Every connection is served by its own session.
+func (server *Server) Serve(ctx context.Context, listener net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	server.logger.Info("RFB server listening.",
+		slog.String("address", listener.Addr().String()),
+		slog.Bool("authentication", server.options.password != ""),
+	)
+
+	for {
+		connection, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("accept: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.ServeConnection(ctx, connection)
+		}()
+	}
+}
+
+// ServeConnection runs session on already accepted connection and closes it when session ends.
+func (server *Server) ServeConnection(ctx context.Context, connection net.Conn) {
+	logger := server.logger.With(slog.String("remoteAddress", connection.RemoteAddr().String()))
+
+	session := newServerSession(connection, server.displaySource, &server.options, logger)
+	if err := session.Run(ctx); err != nil {
+		logger.Warn("RFB session failed.", slog.String("error", err.Error()))
+	}
+}
+
+var (
+	ErrInvalidServerConfiguration = errors.New("invalid rfb server configuration")
+	ErrDesktopResizeUnsupported   = errors.New("rfb client does not support desktop resize")
+	ErrFrameSizeMismatch          = errors.New("frame size does not match display mode")
+	ErrInputUnsupported           = errors.New("rfb input unsupported")
+)
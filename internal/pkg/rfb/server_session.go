@@ -0,0 +1,774 @@
+package rfb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/hid"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const (
+	serverHandshakeTimeout = 30 * time.Second
+	serverWriteTimeout     = 10 * time.Second
+	serverMaxCutText       = 1024 * 1024
+	serverInputSourceID    = "rfb"
+)
+
+// updateRequest is pending FramebufferUpdateRequest. Requests received before the update is taken for sending are
+// merged.
+type updateRequest struct {
+	incremental bool
+	region      Rectangle
+}
+
+// serverSession serves single client. Reader goroutine handles client messages and forwards input to sinks, update
+// goroutine polls display source and is the only writer after handshake.
+type serverSession struct {
+	connection    net.Conn
+	reader        *bufio.Reader
+	displaySource peripheralSDK.DisplaySource
+	options       *ServerOptions
+
+	lock          sync.Mutex
+	pixelFormat   PixelFormat
+	encodings     []Encoding
+	request       *updateRequest
+	width, height int
+	requestNotify chan struct{}
+
+	current       *Frame
+	reference     *Frame // framebuffer as known by the client
+	frameChanged  bool
+	lastSequence  uint64
+	lastTimestamp time.Time
+	frameData     bytes.Buffer
+	message       []byte
+	zrleEncoder   *zrleEncoder
+	tightEncoder  *tightEncoder
+
+	buttonMask     uint8
+	pointerKnown   bool
+	pointerX       uint16
+	pointerY       uint16
+	pressedKeys    map[peripheralSDK.KeyboardHIDUsage]struct{}
+	pressedButtons map[peripheralSDK.MouseButton]struct{}
+
+	logger *slog.Logger
+}
+
+func newServerSession(connection net.Conn, displaySource peripheralSDK.DisplaySource, options *ServerOptions, logger *slog.Logger) *serverSession {
+	return &serverSession{
+		connection:     connection,
+		reader:         bufio.NewReader(connection),
+		displaySource:  displaySource,
+		options:        options,
+		pixelFormat:    DefaultPixelFormat,
+		encodings:      []Encoding{EncodingRaw},
+		requestNotify:  make(chan struct{}, 1),
+		pressedKeys:    make(map[peripheralSDK.KeyboardHIDUsage]struct{}),
+		pressedButtons: make(map[peripheralSDK.MouseButton]struct{}),
+		logger:         logger,
+	}
+}
+
+func (session *serverSession) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	defer func() {
+		_ = session.connection.Close()
+	}()
+
+	// closing connection interrupts blocked read when updates end first or server is terminated
+	go func() {
+		<-ctx.Done()
+		_ = session.connection.Close()
+	}()
+
+	_ = session.connection.SetDeadline(time.Now().Add(serverHandshakeTimeout))
+	if err := session.handshake(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("handshake: %w", err)
+	}
+	_ = session.connection.SetDeadline(time.Time{})
+
+	session.logger.Info("RFB session started.", slog.Int("width", session.width), slog.Int("height", session.height))
+
+	updateDone := make(chan error, 1)
+	go func() {
+		defer cancel()
+		updateDone <- session.sendUpdates(ctx)
+	}()
+
+	readErr := session.readMessages()
+	cancel()
+
+	updateErr := <-updateDone
+	session.releaseInput()
+
+	session.logger.Info("RFB session finished.")
+
+	if updateErr != nil {
+		return updateErr
+	}
+	if readErr != nil && !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrUnexpectedEOF) && !errors.Is(readErr, net.ErrClosed) {
+		return readErr
+	}
+
+	return nil
+}
+
+func (session *serverSession) handshake(ctx context.Context) error {
+	if _, err := session.connection.Write([]byte(ProtocolVersion38)); err != nil {
+		return fmt.Errorf("write protocol version: %w", err)
+	}
+
+	clientVersion := make([]byte, len(ProtocolVersion38))
+	if _, err := io.ReadFull(session.reader, clientVersion); err != nil {
+		return fmt.Errorf("read protocol version: %w", err)
+	}
+
+	version, err := parseProtocolVersion(clientVersion)
+	if err != nil {
+		return err
+	}
+
+	if err := session.authenticate(version); err != nil {
+		return err
+	}
+
+	// shared flag of ClientInit, sessions are always shared
+	if _, err := session.reader.ReadByte(); err != nil {
+		return fmt.Errorf("read client init: %w", err)
+	}
+
+	pixelFormat, err := session.displaySource.GetDisplayPixelFormat(ctx)
+	if err != nil {
+		return fmt.Errorf("get display pixel format: %w", err)
+	}
+	if *pixelFormat != peripheralSDK.DisplayPixelFormatRGB24 {
+		return fmt.Errorf("%w: display %s", ErrPixelFormatUnsupported, *pixelFormat)
+	}
+
+	displayMode, err := session.displaySource.GetDisplayMode(ctx)
+	if err != nil {
+		return fmt.Errorf("get display mode: %w", err)
+	}
+
+	session.width = int(displayMode.Width)
+	session.height = int(displayMode.Height)
+
+	serverInit := binary.BigEndian.AppendUint16(nil, uint16(session.width))
+	serverInit = binary.BigEndian.AppendUint16(serverInit, uint16(session.height))
+	pixelFormatData, _ := DefaultPixelFormat.MarshalBinary()
+	serverInit = append(serverInit, pixelFormatData...)
+	serverInit = appendString(serverInit, session.options.desktopName)
+
+	if _, err := session.connection.Write(serverInit); err != nil {
+		return fmt.Errorf("write server init: %w", err)
+	}
+
+	return nil
+}
+
+// authenticate offers VNC authentication when password is set, no authentication otherwise.
+func (session *serverSession) authenticate(version ProtocolVersion) error {
+	securityType := SecurityTypeNone
+	if session.options.password != "" {
+		securityType = SecurityTypeVNCAuth
+	}
+
+	if version == ProtocolVersion33 {
+		if _, err := session.connection.Write(binary.BigEndian.AppendUint32(nil, uint32(securityType))); err != nil {
+			return fmt.Errorf("write security type: %w", err)
+		}
+	} else {
+		if _, err := session.connection.Write([]byte{1, byte(securityType)}); err != nil {
+			return fmt.Errorf("write security types: %w", err)
+		}
+
+		selectedType, err := session.reader.ReadByte()
+		if err != nil {
+			return fmt.Errorf("read security type: %w", err)
+		}
+
+		if SecurityType(selectedType) != securityType {
+			err := fmt.Errorf("%w: security type %d not offered", ErrProtocolViolation, selectedType)
+			return errors.Join(err, session.writeSecurityResult(version, err))
+		}
+	}
+
+	if securityType == SecurityTypeNone {
+		if version == ProtocolVersion38 {
+			return session.writeSecurityResult(version, nil)
+		}
+		return nil
+	}
+
+	challenge := make([]byte, 16)
+	if _, err := rand.Read(challenge); err != nil {
+		return fmt.Errorf("generate challenge: %w", err)
+	}
+
+	if _, err := session.connection.Write(challenge); err != nil {
+		return fmt.Errorf("write challenge: %w", err)
+	}
+
+	response := make([]byte, 16)
+	if _, err := io.ReadFull(session.reader, response); err != nil {
+		return fmt.Errorf("read challenge response: %w", err)
+	}
+
+	if !verifyVNCAuthResponse(session.options.password, challenge, response) {
+		return errors.Join(ErrAuthenticationFailed, session.writeSecurityResult(version, ErrAuthenticationFailed))
+	}
+
+	return session.writeSecurityResult(version, nil)
+}
+
+// writeSecurityResult sends SecurityResult message, failure reason is sent only to version 3.8 clients.
+func (session *serverSession) writeSecurityResult(version ProtocolVersion, failure error) error {
+	if failure == nil {
+		_, err := session.connection.Write(binary.BigEndian.AppendUint32(nil, 0))
+		return err
+	}
+
+	message := binary.BigEndian.AppendUint32(nil, 1)
+	if version == ProtocolVersion38 {
+		message = appendString(message, failure.Error())
+	}
+
+	_, err := session.connection.Write(message)
+
+	return err
+}
+
+func (session *serverSession) readMessages() error {
+	for {
+		messageType, err := session.reader.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		switch messageType {
+		case messageSetPixelFormat:
+			var message [19]byte
+			if _, err := io.ReadFull(session.reader, message[:]); err != nil {
+				return err
+			}
+
+			var pixelFormat PixelFormat
+			if err := pixelFormat.UnmarshalBinary(message[3:]); err != nil {
+				return err
+			}
+			if err := pixelFormat.Valid(); err != nil {
+				return err
+			}
+
+			session.lock.Lock()
+			session.pixelFormat = pixelFormat
+			session.lock.Unlock()
+
+		case messageSetEncodings:
+			var message [3]byte
+			if _, err := io.ReadFull(session.reader, message[:]); err != nil {
+				return err
+			}
+
+			data := make([]byte, int(binary.BigEndian.Uint16(message[1:]))*4)
+			if _, err := io.ReadFull(session.reader, data); err != nil {
+				return err
+			}
+
+			encodings := make([]Encoding, 0, len(data)/4)
+			for offset := 0; offset < len(data); offset += 4 {
+				encodings = append(encodings, Encoding(int32(binary.BigEndian.Uint32(data[offset:]))))
+			}
+
+			session.lock.Lock()
+			session.encodings = encodings
+			session.lock.Unlock()
+
+			session.logger.Debug("RFB client encodings set.", slog.Any("encodings", encodings))
+
+		case messageFramebufferUpdateRequest:
+			var message [9]byte
+			if _, err := io.ReadFull(session.reader, message[:]); err != nil {
+				return err
+			}
+
+			session.requestUpdate(updateRequest{
+				incremental: message[0] != 0,
+				region: Rectangle{
+					X:      binary.BigEndian.Uint16(message[1:]),
+					Y:      binary.BigEndian.Uint16(message[3:]),
+					Width:  binary.BigEndian.Uint16(message[5:]),
+					Height: binary.BigEndian.Uint16(message[7:]),
+				},
+			})
+
+		case messageKeyEvent:
+			var message [7]byte
+			if _, err := io.ReadFull(session.reader, message[:]); err != nil {
+				return err
+			}
+
+			keysym := hid.Keysym(binary.BigEndian.Uint32(message[3:]))
+			if err := session.handleKey(message[0] != 0, keysym); err != nil {
+				session.logger.Debug("Failed to handle RFB key event.", slog.Uint64("keysym", uint64(keysym)), slog.String("error", err.Error()))
+			}
+
+		case messagePointerEvent:
+			var message [5]byte
+			if _, err := io.ReadFull(session.reader, message[:]); err != nil {
+				return err
+			}
+
+			err := session.handlePointer(message[0], binary.BigEndian.Uint16(message[1:]), binary.BigEndian.Uint16(message[3:]))
+			if err != nil {
+				session.logger.Debug("Failed to handle RFB pointer event.", slog.String("error", err.Error()))
+			}
+
+		case messageClientCutText:
+			var message [7]byte
+			if _, err := io.ReadFull(session.reader, message[:]); err != nil {
+				return err
+			}
+
+			length := binary.BigEndian.Uint32(message[3:])
+			if length > serverMaxCutText {
+				return fmt.Errorf("%w: cut text of %d bytes", ErrProtocolViolation, length)
+			}
+
+			if _, err := io.CopyN(io.Discard, session.reader, int64(length)); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("%w: message type %d", ErrProtocolViolation, messageType)
+		}
+	}
+}
+
+func (session *serverSession) requestUpdate(request updateRequest) {
+	session.restoreRequest(request)
+
+	select {
+	case session.requestNotify <- struct{}{}:
+	default:
+	}
+}
+
+// sendUpdates answers update requests. Incremental requests stay pending until the frame changes.
+func (session *serverSession) sendUpdates(ctx context.Context) error {
+	ticker := time.NewTicker(time.Second / time.Duration(session.options.maxFrameRate))
+	defer ticker.Stop()
+
+	session.frameChanged = true
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-session.requestNotify:
+		}
+
+		session.lock.Lock()
+		request := session.request
+		session.request = nil
+		pixelFormat := session.pixelFormat
+		encodings := session.encodings
+		session.lock.Unlock()
+
+		if request == nil {
+			continue
+		}
+
+		if err := session.refreshFrame(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			session.logger.Debug("Failed to refresh RFB frame.", slog.String("error", err.Error()))
+			session.restoreRequest(*request)
+			continue
+		}
+
+		sent, err := session.sendUpdate(*request, pixelFormat, encodings)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		if !sent {
+			session.restoreRequest(*request)
+		}
+	}
+}
+
+// restoreRequest puts back request that was not answered yet, merging it with request received meanwhile.
+func (session *serverSession) restoreRequest(request updateRequest) {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+
+	if session.request != nil {
+		request.incremental = request.incremental && session.request.incremental
+		request.region = request.region.Union(session.request.region)
+	}
+	session.request = &request
+}
+
+// refreshFrame copies frame buffer of display source into current frame when it has changed.
+func (session *serverSession) refreshFrame(ctx context.Context) error {
+	frameBuffer, err := session.displaySource.GetDisplayFrameBuffer(ctx)
+	if err != nil {
+		return fmt.Errorf("get display frame buffer: %w", err)
+	}
+
+	sequence := frameBuffer.GetSequence()
+	timestamp := frameBuffer.GetTimestamp()
+	unchanged := session.current != nil && sequence == session.lastSequence && timestamp.Equal(session.lastTimestamp)
+
+	session.frameData.Reset()
+	if !unchanged {
+		_, err = frameBuffer.WriteTo(&session.frameData)
+	}
+	if releaseErr := frameBuffer.Release(); releaseErr != nil {
+		session.logger.Warn("Failed to release frame buffer.", slog.String("error", releaseErr.Error()))
+	}
+	if err != nil {
+		return fmt.Errorf("read frame buffer: %w", err)
+	}
+	if unchanged {
+		return nil
+	}
+
+	if session.current == nil || len(session.current.Pixels) != session.frameData.Len() {
+		displayMode, err := session.displaySource.GetDisplayMode(ctx)
+		if err != nil {
+			return fmt.Errorf("get display mode: %w", err)
+		}
+
+		width, height := int(displayMode.Width), int(displayMode.Height)
+		if width*height*3 != session.frameData.Len() {
+			return fmt.Errorf("%w: %d bytes for %dx%d", ErrFrameSizeMismatch, session.frameData.Len(), width, height)
+		}
+
+		session.current = NewFrame(width, height)
+	}
+
+	copy(session.current.Pixels, session.frameData.Bytes())
+	session.lastSequence = sequence
+	session.lastTimestamp = timestamp
+	session.frameChanged = true
+
+	return nil
+}
+
+// sendUpdate sends FramebufferUpdate answering request and reports whether it was sent. Changed size of the desktop is
+// announced with DesktopSize pseudo-rectangle followed by the whole frame.
+func (session *serverSession) sendUpdate(request updateRequest, pixelFormat PixelFormat, encodings []Encoding) (bool, error) {
+	current := session.current
+	if current == nil {
+		return false, nil
+	}
+
+	resized := current.Width != session.width || current.Height != session.height
+	if resized {
+		if !supportsEncoding(encodings, EncodingDesktopSize) {
+			return false, fmt.Errorf("%w: %dx%d", ErrDesktopResizeUnsupported, current.Width, current.Height)
+		}
+		session.reference = nil
+	}
+
+	var (
+		paint []Rectangle
+		moves []Scroll
+	)
+
+	switch {
+	case session.reference == nil:
+		paint = []Rectangle{current.Bounds()}
+	case !request.incremental:
+		if region := request.region.Intersect(current.Bounds()); !region.Empty() {
+			paint = []Rectangle{region}
+		}
+	default:
+		if !session.frameChanged {
+			return false, nil
+		}
+
+		fullRegion := request.region.Intersect(current.Bounds()) == current.Bounds()
+
+		if fullRegion && supportsEncoding(encodings, EncodingCopyRect) {
+			if scroll, found := DetectScroll(session.reference, current, max(32, current.Height/4)); found {
+				moves = append(moves, scroll)
+				session.reference.MoveRows(scroll.SourceY, scroll.DestinationY, scroll.Height)
+			}
+		}
+
+		paint = Damage(session.reference, current, request.region)
+		if fullRegion {
+			session.frameChanged = false
+		}
+
+		if len(paint) == 0 && len(moves) == 0 {
+			return false, nil
+		}
+	}
+
+	encoder := session.encoder(encodings)
+
+	message := append(session.message[:0], messageFramebufferUpdate, 0, 0, 0)
+	count := 0
+
+	if resized {
+		message = appendRectangleHeader(message, current.Bounds(), EncodingDesktopSize)
+		count++
+	}
+
+	for _, move := range moves {
+		destination := Rectangle{Y: uint16(move.DestinationY), Width: uint16(current.Width), Height: uint16(move.Height)}
+		message = appendRectangleHeader(message, destination, EncodingCopyRect)
+		message = appendCopyRect(message, 0, uint16(move.SourceY))
+		count++
+	}
+
+	for _, rectangle := range paint {
+		for _, part := range splitRectangle(encoder, rectangle) {
+			message = appendRectangleHeader(message, part, encoder.Encoding())
+
+			var err error
+			message, err = encoder.Encode(message, current, part, pixelFormat)
+			if err != nil {
+				return false, fmt.Errorf("encode rectangle: %w", err)
+			}
+			count++
+		}
+	}
+
+	binary.BigEndian.PutUint16(message[2:], uint16(count))
+	session.message = message
+
+	_ = session.connection.SetWriteDeadline(time.Now().Add(serverWriteTimeout))
+	if _, err := session.connection.Write(message); err != nil {
+		return false, fmt.Errorf("write framebuffer update: %w", err)
+	}
+
+	if session.reference == nil {
+		session.reference = NewFrame(current.Width, current.Height)
+	}
+	for _, rectangle := range paint {
+		session.reference.CopyRectangle(current, rectangle)
+	}
+
+	if resized {
+		session.lock.Lock()
+		session.width, session.height = current.Width, current.Height
+		session.lock.Unlock()
+
+		session.logger.Info("RFB desktop resized.", slog.Int("width", current.Width), slog.Int("height", current.Height))
+	}
+
+	return true, nil
+}
+
+// encoder returns encoder of the first encoding preferred by client that server supports. Compressing encoders keep
+// zlib state for the whole connection, so they are created once.
+func (session *serverSession) encoder(encodings []Encoding) rectangleEncoder {
+	for _, encoding := range encodings {
+		switch encoding {
+		case EncodingZRLE:
+			if session.zrleEncoder == nil {
+				session.zrleEncoder = newZRLEEncoder()
+			}
+			return session.zrleEncoder
+		case EncodingTight:
+			if session.tightEncoder == nil {
+				session.tightEncoder = newTightEncoder()
+			}
+			return session.tightEncoder
+		case EncodingRaw:
+			return rawEncoder{}
+		}
+	}
+
+	return rawEncoder{}
+}
+
+func supportsEncoding(encodings []Encoding, encoding Encoding) bool {
+	for _, candidate := range encodings {
+		if candidate == encoding {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (session *serverSession) handleKey(down bool, keysym hid.Keysym) error {
+	keyboardSink := session.options.keyboardSink
+	if keyboardSink == nil {
+		return nil
+	}
+
+	usage, _, found := hid.UsageFromKeysym(keysym)
+	if !found {
+		return fmt.Errorf("%w: keysym %#x", ErrInputUnsupported, uint32(keysym))
+	}
+
+	_, pressed := session.pressedKeys[usage]
+
+	var state peripheralSDK.KeyboardKeyState
+	switch {
+	case down && pressed:
+		state = peripheralSDK.KeyboardKeyStateRepeat
+	case down:
+		state = peripheralSDK.KeyboardKeyStatePress
+		session.pressedKeys[usage] = struct{}{}
+	default:
+		state = peripheralSDK.KeyboardKeyStateRelease
+		delete(session.pressedKeys, usage)
+	}
+
+	// Latin-1 keysyms are equal to their code points
+	var text string
+	if down && (keysym >= 0x20 && keysym < 0x7f || keysym >= 0xa0 && keysym <= 0xff) {
+		text = string(rune(keysym))
+	}
+
+	return keyboardSink.HandleKeyboardDataEvent(peripheralSDK.NewKeyboardKeyEvent(
+		usage,
+		"",
+		peripheralSDK.KeyboardLogicalKey{Code: text},
+		session.modifiers(),
+		state,
+		text,
+		serverInputSourceID,
+		time.Now(),
+	))
+}
+
+// pointerButtons maps bits of PointerEvent button mask to mouse buttons.
+var pointerButtons = []struct {
+	bit    uint8
+	button peripheralSDK.MouseButton
+}{
+	{ButtonLeft, peripheralSDK.MouseButtonLeft},
+	{ButtonMiddle, peripheralSDK.MouseButtonMiddle},
+	{ButtonRight, peripheralSDK.MouseButtonRight},
+}
+
+// pointerWheel maps bits of PointerEvent button mask to wheel steps, a step is sent when the bit is set.
+var pointerWheel = []struct {
+	bit            uint8
+	deltaX, deltaY int32
+}{
+	{ButtonWheelUp, 0, -1},
+	{ButtonWheelDown, 0, 1},
+	{ButtonWheelLeft, -1, 0},
+	{ButtonWheelRight, 1, 0},
+}
+
+func (session *serverSession) handlePointer(buttonMask uint8, x uint16, y uint16) error {
+	mouseSink := session.options.mouseSink
+	if mouseSink == nil {
+		return nil
+	}
+
+	var events []peripheralSDK.MouseEvent
+	now := time.Now()
+
+	if !session.pointerKnown || x != session.pointerX || y != session.pointerY {
+		session.lock.Lock()
+		width, height := session.width, session.height
+		session.lock.Unlock()
+
+		normalizedX := min(float64(x)/float64(max(width-1, 1)), 1)
+		normalizedY := min(float64(y)/float64(max(height-1, 1)), 1)
+		events = append(events, peripheralSDK.NewMouseAbsoluteMoveEvent(normalizedX, normalizedY, serverInputSourceID, now))
+
+		session.pointerKnown = true
+		session.pointerX, session.pointerY = x, y
+	}
+
+	for _, pointerButton := range pointerButtons {
+		if (buttonMask^session.buttonMask)&pointerButton.bit == 0 {
+			continue
+		}
+
+		state := peripheralSDK.MouseButtonStateRelease
+		if buttonMask&pointerButton.bit != 0 {
+			state = peripheralSDK.MouseButtonStatePress
+			session.pressedButtons[pointerButton.button] = struct{}{}
+		} else {
+			delete(session.pressedButtons, pointerButton.button)
+		}
+
+		events = append(events, peripheralSDK.NewMouseButtonEvent(pointerButton.button, state, serverInputSourceID, now))
+	}
+
+	for _, wheel := range pointerWheel {
+		if buttonMask&wheel.bit != 0 && session.buttonMask&wheel.bit == 0 {
+			events = append(events, peripheralSDK.NewMouseWheelEvent(wheel.deltaX, wheel.deltaY, serverInputSourceID, now))
+		}
+	}
+
+	session.buttonMask = buttonMask
+
+	var errs []error
+	for _, event := range events {
+		if err := mouseSink.HandleMouseDataEvent(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (session *serverSession) modifiers() peripheralSDK.KeyboardModifiers {
+	modifiers := peripheralSDK.KeyboardModifierNone
+	for usage := range session.pressedKeys {
+		modifiers |= hid.ModifierFromUsage(usage)
+	}
+
+	return modifiers
+}
+
+// releaseInput releases keys and buttons left pressed when client disconnects.
+func (session *serverSession) releaseInput() {
+	for usage := range session.pressedKeys {
+		delete(session.pressedKeys, usage)
+
+		err := session.options.keyboardSink.HandleKeyboardDataEvent(peripheralSDK.NewKeyboardKeyEvent(
+			usage, "", peripheralSDK.KeyboardLogicalKey{}, session.modifiers(), peripheralSDK.KeyboardKeyStateRelease, "", serverInputSourceID, time.Now(),
+		))
+		if err != nil {
+			session.logger.Warn("Failed to release key.", slog.Int("hidUsage", int(usage)), slog.String("error", err.Error()))
+		}
+	}
+
+	for button := range session.pressedButtons {
+		delete(session.pressedButtons, button)
+
+		err := session.options.mouseSink.HandleMouseDataEvent(peripheralSDK.NewMouseButtonEvent(button, peripheralSDK.MouseButtonStateRelease, serverInputSourceID, time.Now()))
+		if err != nil {
+			session.logger.Warn("Failed to release mouse button.", slog.Int("button", int(button)), slog.String("error", err.Error()))
+		}
+	}
+}
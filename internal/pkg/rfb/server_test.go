@@ -0,0 +1,327 @@
+package rfb
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// testDisplaySource serves frame that can be replaced during the test, every replacement gets a new sequence.
+type testDisplaySource struct {
+	lock     sync.Mutex
+	frame    *Frame
+	sequence uint64
+}
+
+func (source *testDisplaySource) setFrame(frame *Frame) {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+
+	source.frame = frame
+	source.sequence++
+}
+
+func newTestDisplaySource(t *testing.T, frame *Frame) (*peripheralSDK.DisplaySourceMock, *testDisplaySource) {
+	memory.SetupTestDefaultMemoryPool(t)
+
+	source := &testDisplaySource{}
+	source.setFrame(frame)
+	pixelFormat := peripheralSDK.DisplayPixelFormatRGB24
+
+	displaySource := peripheralSDK.NewDisplaySourceMock(t)
+	displaySource.EXPECT().GetDisplayPixelFormat(mock.Anything).Return(&pixelFormat, nil).Maybe()
+	displaySource.EXPECT().GetDisplayMode(mock.Anything).RunAndReturn(func(ctx context.Context) (*peripheralSDK.DisplayMode, error) {
+		source.lock.Lock()
+		defer source.lock.Unlock()
+
+		return &peripheralSDK.DisplayMode{Width: uint32(source.frame.Width), Height: uint32(source.frame.Height), RefreshRate: 30}, nil
+	}).Maybe()
+	displaySource.EXPECT().GetDisplayFrameBuffer(mock.Anything).RunAndReturn(func(ctx context.Context) (*peripheralSDK.DisplayFrameBuffer, error) {
+		source.lock.Lock()
+		defer source.lock.Unlock()
+
+		memoryPool, err := memory.DefaultMemoryPoolProvider()
+		if err != nil {
+			return nil, err
+		}
+
+		buffer, err := memoryPool.Borrow(len(source.frame.Pixels))
+		if err != nil {
+			return nil, err
+		}
+		_, _ = buffer.Write(source.frame.Pixels)
+
+		return peripheralSDK.NewDisplayFrameBuffer(buffer,
+			peripheralSDK.WithDisplayFrameBufferSequence(source.sequence),
+			peripheralSDK.WithDisplayFrameBufferTimestamp(time.Unix(1000, 0)),
+		), nil
+	}).Maybe()
+
+	return displaySource, source
+}
+
+// testClient drives server session from the client side of a pipe.
+type testClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func startTestSession(t *testing.T, displaySource peripheralSDK.DisplaySource, opts ...ServerOpt) (*testClient, <-chan struct{}) {
+	server, err := NewServer(displaySource, append([]ServerOpt{WithServerMaxFrameRate(100)}, opts...)...)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() {
+		_ = clientConn.Close()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.ServeConnection(t.Context(), serverConn)
+	}()
+
+	_ = clientConn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	return &testClient{t: t, conn: clientConn, reader: bufio.NewReader(clientConn)}, done
+}
+
+func (client *testClient) read(length int) []byte {
+	data := make([]byte, length)
+	if _, err := io.ReadFull(client.reader, data); !assert.NoError(client.t, err) {
+		client.t.FailNow()
+	}
+
+	return data
+}
+
+func (client *testClient) write(data ...byte) {
+	if _, err := client.conn.Write(data); !assert.NoError(client.t, err) {
+		client.t.FailNow()
+	}
+}
+
+func (client *testClient) setEncodings(encodings ...Encoding) {
+	message := []byte{messageSetEncodings, 0}
+	message = binary.BigEndian.AppendUint16(message, uint16(len(encodings)))
+	for _, encoding := range encodings {
+		message = binary.BigEndian.AppendUint32(message, uint32(encoding))
+	}
+	client.write(message...)
+}
+
+func (client *testClient) requestUpdate(incremental bool, rectangle Rectangle) {
+	message := []byte{messageFramebufferUpdateRequest, boolByte(incremental)}
+	message = binary.BigEndian.AppendUint16(message, rectangle.X)
+	message = binary.BigEndian.AppendUint16(message, rectangle.Y)
+	message = binary.BigEndian.AppendUint16(message, rectangle.Width)
+	message = binary.BigEndian.AppendUint16(message, rectangle.Height)
+	client.write(message...)
+}
+
+type testRectangle struct {
+	Rectangle
+	encoding Encoding
+	data     []byte
+}
+
+// readRawUpdate reads FramebufferUpdate with raw, CopyRect and DesktopSize rectangles of 32 bit pixels.
+func (client *testClient) readRawUpdate() []testRectangle {
+	header := client.read(4)
+	assert.Equal(client.t, messageFramebufferUpdate, header[0])
+
+	rectangles := make([]testRectangle, binary.BigEndian.Uint16(header[2:]))
+	for i := range rectangles {
+		data := client.read(12)
+		rectangle := testRectangle{
+			Rectangle: Rectangle{
+				X:      binary.BigEndian.Uint16(data[0:]),
+				Y:      binary.BigEndian.Uint16(data[2:]),
+				Width:  binary.BigEndian.Uint16(data[4:]),
+				Height: binary.BigEndian.Uint16(data[6:]),
+			},
+			encoding: Encoding(int32(binary.BigEndian.Uint32(data[8:]))),
+		}
+
+		switch rectangle.encoding {
+		case EncodingRaw:
+			rectangle.data = client.read(rectangle.Area() * 4)
+		case EncodingCopyRect:
+			rectangle.data = client.read(4)
+		}
+
+		rectangles[i] = rectangle
+	}
+
+	return rectangles
+}
+
+func (client *testClient) handshake(password string) (uint16, uint16) {
+	assert.Equal(client.t, []byte(ProtocolVersion38), client.read(12))
+	client.write([]byte(ProtocolVersion38)...)
+
+	if password == "" {
+		assert.Equal(client.t, []byte{1, byte(SecurityTypeNone)}, client.read(2))
+		client.write(byte(SecurityTypeNone))
+	} else {
+		assert.Equal(client.t, []byte{1, byte(SecurityTypeVNCAuth)}, client.read(2))
+		client.write(byte(SecurityTypeVNCAuth))
+
+		response, err := vncAuthResponse(password, client.read(16))
+		assert.NoError(client.t, err)
+		client.write(response...)
+	}
+	assert.Equal(client.t, []byte{0, 0, 0, 0}, client.read(4))
+
+	client.write(1)
+
+	serverInit := client.read(24)
+	var pixelFormat PixelFormat
+	assert.NoError(client.t, pixelFormat.UnmarshalBinary(serverInit[4:20]))
+	assert.Equal(client.t, DefaultPixelFormat, pixelFormat)
+	client.read(int(binary.BigEndian.Uint32(serverInit[20:])))
+
+	return binary.BigEndian.Uint16(serverInit[0:]), binary.BigEndian.Uint16(serverInit[2:])
+}
+
+func TestServerSessionUpdates(t *testing.T) {
+	frame := newTestFrame(32, 32)
+	displaySource, source := newTestDisplaySource(t, frame)
+
+	client, done := startTestSession(t, displaySource, WithServerPassword("secret"))
+
+	width, height := client.handshake("secret")
+	assert.Equal(t, []uint16{32, 32}, []uint16{width, height})
+
+	client.setEncodings(EncodingRaw, EncodingCopyRect, EncodingDesktopSize)
+	client.requestUpdate(false, Rectangle{Width: 32, Height: 32})
+
+	rectangles := client.readRawUpdate()
+	if assert.Len(t, rectangles, 1) {
+		assert.Equal(t, Rectangle{Width: 32, Height: 32}, rectangles[0].Rectangle)
+		assert.Equal(t, EncodingRaw, rectangles[0].encoding)
+
+		// pixel (5, 3) in little endian 0x00RRGGBB
+		offset := (3*32 + 5) * 4
+		assert.Equal(t, []byte{5 ^ 3, 3, 5, 0}, rectangles[0].data[offset:offset+4])
+	}
+
+	changed := newTestFrame(32, 32)
+	setTestPixel(changed, 20, 20)
+	source.setFrame(changed)
+
+	client.requestUpdate(true, Rectangle{Width: 32, Height: 32})
+	rectangles = client.readRawUpdate()
+	if assert.Len(t, rectangles, 1) {
+		assert.Equal(t, Rectangle{X: 16, Y: 16, Width: 16, Height: 16}, rectangles[0].Rectangle)
+	}
+
+	source.setFrame(newTestFrame(48, 16))
+
+	client.requestUpdate(true, Rectangle{Width: 32, Height: 32})
+	rectangles = client.readRawUpdate()
+	if assert.Len(t, rectangles, 2) {
+		assert.Equal(t, testRectangle{Rectangle: Rectangle{Width: 48, Height: 16}, encoding: EncodingDesktopSize}, rectangles[0])
+		assert.Equal(t, Rectangle{Width: 48, Height: 16}, rectangles[1].Rectangle)
+	}
+
+	_ = client.conn.Close()
+	<-done
+}
+
+func TestServerSessionAuthenticationFailure(t *testing.T) {
+	displaySource, _ := newTestDisplaySource(t, newTestFrame(8, 8))
+
+	client, done := startTestSession(t, displaySource, WithServerPassword("secret"))
+
+	client.read(12)
+	client.write([]byte(ProtocolVersion38)...)
+	client.read(2)
+	client.write(byte(SecurityTypeVNCAuth))
+
+	response, err := vncAuthResponse("wrong", client.read(16))
+	assert.NoError(t, err)
+	client.write(response...)
+
+	assert.Equal(t, []byte{0, 0, 0, 1}, client.read(4))
+	reason := client.read(int(binary.BigEndian.Uint32(client.read(4))))
+	assert.Equal(t, ErrAuthenticationFailed.Error(), string(reason))
+
+	<-done
+}
+
+func TestServerSessionInput(t *testing.T) {
+	displaySource, _ := newTestDisplaySource(t, newTestFrame(101, 51))
+
+	keyboardEvents := make(chan peripheralSDK.KeyboardEvent, 8)
+	keyboardSink := peripheralSDK.NewKeyboardSinkMock(t)
+	keyboardSink.EXPECT().HandleKeyboardDataEvent(mock.Anything).RunAndReturn(func(event peripheralSDK.KeyboardEvent) error {
+		keyboardEvents <- event
+		return nil
+	})
+
+	mouseEvents := make(chan peripheralSDK.MouseEvent, 8)
+	mouseSink := peripheralSDK.NewMouseEventHandlerMock(t)
+	mouseSink.EXPECT().HandleMouseDataEvent(mock.Anything).RunAndReturn(func(event peripheralSDK.MouseEvent) error {
+		mouseEvents <- event
+		return nil
+	})
+
+	client, done := startTestSession(t, displaySource, WithServerKeyboardSink(keyboardSink), WithServerMouseSink(mouseSink))
+	client.handshake("")
+
+	// Shift_L down, 'A' down
+	client.write(messageKeyEvent, 1, 0, 0, 0x00, 0x00, 0xff, 0xe1)
+	client.write(messageKeyEvent, 1, 0, 0, 0x00, 0x00, 0x00, 'A')
+
+	// left button pressed at the center, then wheel down
+	client.write(messagePointerEvent, ButtonLeft, 0, 50, 0, 25)
+	client.write(messagePointerEvent, ButtonLeft|ButtonWheelDown, 0, 50, 0, 25)
+
+	_ = client.conn.Close()
+	<-done
+
+	shift := (<-keyboardEvents).(peripheralSDK.KeyboardKeyEvent)
+	assert.Equal(t, peripheralSDK.KeyboardHIDUsage(0xe1), shift.HIDUsage)
+	assert.Equal(t, peripheralSDK.KeyboardKeyStatePress, shift.State)
+
+	letter := (<-keyboardEvents).(peripheralSDK.KeyboardKeyEvent)
+	assert.Equal(t, peripheralSDK.KeyboardHIDUsage(0x04), letter.HIDUsage)
+	assert.Equal(t, peripheralSDK.KeyboardModifierShift, letter.Modifiers)
+	assert.Equal(t, "A", letter.Text)
+
+	move := (<-mouseEvents).(peripheralSDK.MouseMoveEvent)
+	assert.True(t, move.Absolute)
+	assert.InDelta(t, 0.5, move.X, 0.001)
+	assert.InDelta(t, 0.5, move.Y, 0.001)
+
+	press := (<-mouseEvents).(peripheralSDK.MouseButtonEvent)
+	assert.Equal(t, peripheralSDK.MouseButtonLeft, press.Button)
+	assert.Equal(t, peripheralSDK.MouseButtonStatePress, press.State)
+
+	wheel := (<-mouseEvents).(peripheralSDK.MouseWheelEvent)
+	assert.Equal(t, int32(1), wheel.DeltaY)
+
+	// keys and buttons held on disconnect are released
+	for range 2 {
+		release := (<-keyboardEvents).(peripheralSDK.KeyboardKeyEvent)
+		assert.Equal(t, peripheralSDK.KeyboardKeyStateRelease, release.State)
+	}
+
+	release := (<-mouseEvents).(peripheralSDK.MouseButtonEvent)
+	assert.Equal(t, peripheralSDK.MouseButtonStateRelease, release.State)
+}
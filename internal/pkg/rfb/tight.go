@@ -0,0 +1,125 @@
+package rfb
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+)
+
+const (
+	tightMaxWidth       = 2048
+	tightMaxArea        = 65536
+	tightMinCompression = 12
+)
+
+// Tight compression control values.
+const (
+	tightBasic uint8 = 0x00
+	tightFill  uint8 = 0x80
+)
+
+// tightEncoder encodes rectangles with lossless subset of Tight: fill for uniform rectangles and basic compression
+// without filter otherwise. Only zlib stream 0 is used and it is never reset, so encoder is bound to one client.
+type tightEncoder struct {
+	compressed bytes.Buffer
+	writer     *zlib.Writer
+
+	pixels []byte
+}
+
+func newTightEncoder() *tightEncoder {
+	encoder := &tightEncoder{}
+	encoder.writer = zlib.NewWriter(&encoder.compressed)
+
+	return encoder
+}
+
+func (encoder *tightEncoder) Encoding() Encoding {
+	return EncodingTight
+}
+
+func (encoder *tightEncoder) Encode(dst []byte, frame *Frame, rectangle Rectangle, pixelFormat PixelFormat) ([]byte, error) {
+	encoder.pixels = encoder.pixels[:0]
+
+	uniform := true
+	first := frame.pixel(pixelFormat, int(rectangle.X), int(rectangle.Y))
+	for y := int(rectangle.Y); y < int(rectangle.Y)+int(rectangle.Height); y++ {
+		for x := int(rectangle.X); x < int(rectangle.X)+int(rectangle.Width); x++ {
+			value := frame.pixel(pixelFormat, x, y)
+			uniform = uniform && value == first
+			encoder.pixels = appendTightPixel(encoder.pixels, pixelFormat, value)
+		}
+	}
+
+	if uniform {
+		dst = append(dst, tightFill)
+		return appendTightPixel(dst, pixelFormat, first), nil
+	}
+
+	dst = append(dst, tightBasic)
+
+	if len(encoder.pixels) < tightMinCompression {
+		return append(dst, encoder.pixels...), nil
+	}
+
+	encoder.compressed.Reset()
+	if _, err := encoder.writer.Write(encoder.pixels); err != nil {
+		return nil, fmt.Errorf("compress rectangle: %w", err)
+	}
+	if err := encoder.writer.Flush(); err != nil {
+		return nil, fmt.Errorf("flush zlib stream: %w", err)
+	}
+
+	dst = appendCompactLength(dst, encoder.compressed.Len())
+
+	return append(dst, encoder.compressed.Bytes()...), nil
+}
+
+// tightPixelRGB reports whether TPIXEL is sent as three bytes in red, green, blue order.
+func tightPixelRGB(pixelFormat PixelFormat) bool {
+	return pixelFormat.TrueColor && pixelFormat.BitsPerPixel == 32 && pixelFormat.Depth == 24 &&
+		pixelFormat.RedMax == 255 && pixelFormat.GreenMax == 255 && pixelFormat.BlueMax == 255
+}
+
+func appendTightPixel(dst []byte, pixelFormat PixelFormat, value uint32) []byte {
+	if !tightPixelRGB(pixelFormat) {
+		return pixelFormat.AppendPixel(dst, value)
+	}
+
+	red, green, blue := pixelFormat.Unpack(value)
+
+	return append(dst, red, green, blue)
+}
+
+// appendCompactLength appends length as one to three bytes, seven bits per byte with continuation in the high bit.
+func appendCompactLength(dst []byte, length int) []byte {
+	if length < 0x80 {
+		return append(dst, byte(length))
+	}
+	if length < 0x4000 {
+		return append(dst, byte(length)|0x80, byte(length>>7))
+	}
+
+	return append(dst, byte(length)|0x80, byte(length>>7)|0x80, byte(length>>14))
+}
+
+// splitTightRectangle splits rectangle into parts not wider than 2048 pixels and not larger than 65536 pixels.
+func splitTightRectangle(rectangle Rectangle) []Rectangle {
+	var parts []Rectangle
+
+	for x := int(rectangle.X); x < int(rectangle.X)+int(rectangle.Width); x += tightMaxWidth {
+		width := min(tightMaxWidth, int(rectangle.X)+int(rectangle.Width)-x)
+		rows := max(1, tightMaxArea/width)
+
+		for y := int(rectangle.Y); y < int(rectangle.Y)+int(rectangle.Height); y += rows {
+			parts = append(parts, Rectangle{
+				X:      uint16(x),
+				Y:      uint16(y),
+				Width:  uint16(width),
+				Height: uint16(min(rows, int(rectangle.Y)+int(rectangle.Height)-y)),
+			})
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,249 @@
+package rfb
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	zrleTileSize       = 64
+	zrleMaxPaletteSize = 127
+)
+
+// ZRLE tile subencodings.
+const (
+	zrleRaw        uint8 = 0
+	zrleSolid      uint8 = 1
+	zrlePlainRLE   uint8 = 128
+	zrlePaletteRLE uint8 = 128 // combined with palette size
+)
+
+// zrleEncoder encodes rectangles with ZRLE. All rectangles of a connection share single zlib stream, so encoder is
+// bound to one client.
+type zrleEncoder struct {
+	compressed bytes.Buffer
+	writer     *zlib.Writer
+
+	tile    []byte
+	pixels  []uint32
+	palette map[uint32]uint8
+	colors  []uint32
+}
+
+func newZRLEEncoder() *zrleEncoder {
+	encoder := &zrleEncoder{
+		pixels:  make([]uint32, 0, zrleTileSize*zrleTileSize),
+		palette: make(map[uint32]uint8, zrleMaxPaletteSize),
+	}
+	encoder.writer = zlib.NewWriter(&encoder.compressed)
+
+	return encoder
+}
+
+func (encoder *zrleEncoder) Encoding() Encoding {
+	return EncodingZRLE
+}
+
+func (encoder *zrleEncoder) Encode(dst []byte, frame *Frame, rectangle Rectangle, pixelFormat PixelFormat) ([]byte, error) {
+	encoder.compressed.Reset()
+
+	for tileY := int(rectangle.Y); tileY < int(rectangle.Y)+int(rectangle.Height); tileY += zrleTileSize {
+		for tileX := int(rectangle.X); tileX < int(rectangle.X)+int(rectangle.Width); tileX += zrleTileSize {
+			tile := Rectangle{
+				X:      uint16(tileX),
+				Y:      uint16(tileY),
+				Width:  uint16(min(zrleTileSize, int(rectangle.X)+int(rectangle.Width)-tileX)),
+				Height: uint16(min(zrleTileSize, int(rectangle.Y)+int(rectangle.Height)-tileY)),
+			}
+
+			encoder.tile = encoder.encodeTile(encoder.tile[:0], frame, tile, pixelFormat)
+			if _, err := encoder.writer.Write(encoder.tile); err != nil {
+				return nil, fmt.Errorf("compress tile: %w", err)
+			}
+		}
+	}
+
+	if err := encoder.writer.Flush(); err != nil {
+		return nil, fmt.Errorf("flush zlib stream: %w", err)
+	}
+
+	dst = binary.BigEndian.AppendUint32(dst, uint32(encoder.compressed.Len()))
+
+	return append(dst, encoder.compressed.Bytes()...), nil
+}
+
+// encodeTile picks the smallest of raw, solid, packed palette, plain RLE and palette RLE subencodings.
+func (encoder *zrleEncoder) encodeTile(dst []byte, frame *Frame, tile Rectangle, pixelFormat PixelFormat) []byte {
+	encoder.pixels = encoder.pixels[:0]
+	clear(encoder.palette)
+	encoder.colors = encoder.colors[:0]
+
+	runs := 0
+	runBytes := 0        // length bytes of plain RLE runs
+	paletteRunBytes := 0 // index and length bytes of palette RLE runs
+	runLength := 0
+
+	endRun := func() {
+		lengthBytes := (runLength-1)/255 + 1
+		runBytes += lengthBytes
+		if runLength == 1 {
+			paletteRunBytes++
+		} else {
+			paletteRunBytes += 1 + lengthBytes
+		}
+		runs++
+	}
+
+	for y := int(tile.Y); y < int(tile.Y)+int(tile.Height); y++ {
+		for x := int(tile.X); x < int(tile.X)+int(tile.Width); x++ {
+			value := frame.pixel(pixelFormat, x, y)
+
+			if len(encoder.pixels) > 0 && encoder.pixels[len(encoder.pixels)-1] == value {
+				runLength++
+			} else {
+				if runLength > 0 {
+					endRun()
+				}
+				runLength = 1
+			}
+			encoder.pixels = append(encoder.pixels, value)
+
+			if _, found := encoder.palette[value]; !found && len(encoder.colors) <= zrleMaxPaletteSize {
+				encoder.palette[value] = uint8(len(encoder.colors))
+				encoder.colors = append(encoder.colors, value)
+			}
+		}
+	}
+	endRun()
+
+	pixelSize, _ := pixelFormat.compactPixelSize()
+
+	if len(encoder.colors) == 1 {
+		dst = append(dst, zrleSolid)
+		return pixelFormat.appendCompactPixel(dst, encoder.colors[0])
+	}
+
+	paletteSize := len(encoder.colors)
+	usePalette := paletteSize <= zrleMaxPaletteSize
+
+	bestSize := len(encoder.pixels) * pixelSize
+	best := zrleRaw
+
+	if size := runs*pixelSize + runBytes; size < bestSize {
+		bestSize, best = size, zrlePlainRLE
+	}
+
+	if usePalette {
+		if size := paletteSize*pixelSize + paletteRunBytes; size < bestSize {
+			bestSize, best = size, zrlePaletteRLE+uint8(paletteSize)
+		}
+
+		if bits := zrlePackedBits(paletteSize); bits > 0 {
+			rowBytes := (int(tile.Width)*bits + 7) / 8
+			if size := paletteSize*pixelSize + rowBytes*int(tile.Height); size < bestSize {
+				best = uint8(paletteSize)
+			}
+		}
+	}
+
+	dst = append(dst, best)
+
+	switch {
+	case best == zrleRaw:
+		for _, value := range encoder.pixels {
+			dst = pixelFormat.appendCompactPixel(dst, value)
+		}
+	case best == zrlePlainRLE:
+		dst = encoder.appendRuns(dst, pixelFormat, false)
+	case best > zrlePaletteRLE:
+		for _, value := range encoder.colors {
+			dst = pixelFormat.appendCompactPixel(dst, value)
+		}
+		dst = encoder.appendRuns(dst, pixelFormat, true)
+	default:
+		for _, value := range encoder.colors {
+			dst = pixelFormat.appendCompactPixel(dst, value)
+		}
+		dst = encoder.appendPacked(dst, int(tile.Width), zrlePackedBits(paletteSize))
+	}
+
+	return dst
+}
+
+func (encoder *zrleEncoder) appendRuns(dst []byte, pixelFormat PixelFormat, usePalette bool) []byte {
+	for start := 0; start < len(encoder.pixels); {
+		value := encoder.pixels[start]
+		end := start + 1
+		for end < len(encoder.pixels) && encoder.pixels[end] == value {
+			end++
+		}
+		runLength := end - start
+		start = end
+
+		if usePalette {
+			index := encoder.palette[value]
+			if runLength == 1 {
+				dst = append(dst, index)
+				continue
+			}
+			dst = append(dst, index|0x80)
+		} else {
+			dst = pixelFormat.appendCompactPixel(dst, value)
+		}
+
+		dst = appendRunLength(dst, runLength)
+	}
+
+	return dst
+}
+
+func (encoder *zrleEncoder) appendPacked(dst []byte, width int, bits int) []byte {
+	for rowStart := 0; rowStart < len(encoder.pixels); rowStart += width {
+		var (
+			current byte
+			used    int
+		)
+
+		for _, value := range encoder.pixels[rowStart : rowStart+width] {
+			current |= encoder.palette[value] << (8 - bits - used)
+			used += bits
+			if used == 8 {
+				dst = append(dst, current)
+				current, used = 0, 0
+			}
+		}
+
+		if used > 0 {
+			dst = append(dst, current)
+		}
+	}
+
+	return dst
+}
+
+// appendRunLength appends run length as sequence of 255 bytes terminated by a byte lower than 255, encoding length-1.
+func appendRunLength(dst []byte, runLength int) []byte {
+	runLength--
+	for runLength >= 255 {
+		dst = append(dst, 255)
+		runLength -= 255
+	}
+
+	return append(dst, byte(runLength))
+}
+
+// zrlePackedBits returns bits per palette index of packed palette subencoding, or zero when palette is too large.
+func zrlePackedBits(paletteSize int) int {
+	switch {
+	case paletteSize <= 2:
+		return 1
+	case paletteSize <= 4:
+		return 2
+	case paletteSize <= 16:
+		return 4
+	default:
+		return 0
+	}
+}
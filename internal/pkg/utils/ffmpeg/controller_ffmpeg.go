@@ -22,6 +22,7 @@ type SupervisorProvider func(specification process.Specification, restartPolicy
 
 type ffmpegControllerOptions struct {
 	executablePath     string
+	inputConfiguration Configuration
 	logger             *slog.Logger
 	supervisorProvider SupervisorProvider
 }
@@ -29,6 +30,7 @@ type ffmpegControllerOptions struct {
 func defaultFFmpegControllerOptions() ffmpegControllerOptions {
 	return ffmpegControllerOptions{
 		executablePath:     "/usr/local/bin/ffmpeg",
+		inputConfiguration: RawConfiguration{},
 		logger:             slog.New(slog.DiscardHandler),
 		supervisorProvider: nil,
 	}
@@ -75,6 +77,14 @@ func WithFFmpegExecutablePath(executablePath string) FFmpegControlerOpt {
 	}
 }
 
+// WithFFmpegInputConfiguration sets parameters placed before the input, e.g. format of raw video read from stdin.
+func WithFFmpegInputConfiguration(configuration Configuration) FFmpegControlerOpt {
+	return func(options *ffmpegControllerOptions) error {
+		options.inputConfiguration = configuration
+		return nil
+	}
+}
+
 func WithSupervisorProvider(provider SupervisorProvider) FFmpegControlerOpt {
 	return func(options *ffmpegControllerOptions) error {
 		options.supervisorProvider = provider
@@ -91,6 +101,7 @@ func NewFFmpegController(input Input, output Output, configuration Configuration
 	}
 
 	arguments := slices.Concat(
+		options.inputConfiguration.Parameters(),
 		input.Parameters(),
 		[]string{
 			"-progress",
@@ -153,11 +164,15 @@ func (controller *FFmpegController) Stop(ctx context.Context) error {
 
 	controller.logger.Debug("FFmpeg stopped.")
 
+	if err == nil {
+		return nil
+	}
+
 	if errors.Is(err, process.KilledError{}) {
 		return nil
-	} else {
-		return fmt.Errorf("error stopping ffmpeg: %w", err)
 	}
+
+	return fmt.Errorf("error stopping ffmpeg: %w", err)
 }
 
 func (controller *FFmpegController) GetStatus() FFmpegStatus {
@@ -189,6 +189,28 @@ func TestWithFFmpegExecutablePath(t *testing.T) {
 	assert.Equal(t, customPath, capturedSpecification.ExecutablePath)
 }
 
+// TestWithFFmpegInputConfiguration tests that input configuration is placed before the input.
+func TestWithFFmpegInputConfiguration(t *testing.T) {
+	var capturedSpecification process.Specification
+
+	customProvider := func(specification process.Specification, restartPolicy process.RestartPolicy) process.Supervisor {
+		capturedSpecification = specification
+		return &mockSupervisor{}
+	}
+
+	controller, err := NewFFmpegController(
+		NewInputStdin(),
+		NewOutputStdout(),
+		RawConfiguration{"-f", "image2pipe"},
+		WithFFmpegInputConfiguration(RawConfiguration{"-f", "rawvideo"}),
+		WithSupervisorProvider(customProvider),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, controller)
+
+	assert.Equal(t, []string{"-f", "rawvideo", "-i", "pipe:0", "-progress", "pipe:2", "-f", "image2pipe"}, capturedSpecification.Arguments[:8])
+}
+
 // TestParseStatusLine_DropFrames tests parsing of drop_frames status line.
 func TestParseStatusLine_DropFrames(t *testing.T) {
 	controller := &FFmpegController{
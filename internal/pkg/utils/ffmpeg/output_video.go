@@ -0,0 +1,122 @@
+package ffmpeg
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils"
+)
+
+// VideoEncoding describes video encoder settings. Empty preset and bitrate keep encoder defaults.
+type VideoEncoding struct {
+	Codec            string
+	Preset           string
+	Bitrate          string
+	KeyframeInterval time.Duration
+}
+
+// OutputVideo encodes video to a file or a network stream. Container format is selected by file extension or URL
+// scheme.
+type OutputVideo struct {
+	encoding VideoEncoding
+	format   []string
+	target   string
+}
+
+// NewOutputVideoFile writes video to path with .mp4, .mkv or .ts extension. MP4 is fragmented, so the file stays
+// playable when ffmpeg is killed. Existing file is never overwritten.
+func NewOutputVideoFile(path string, encoding VideoEncoding) (*OutputVideo, error) {
+	var format []string
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mp4":
+		format = []string{"-movflags", "+frag_keyframe+empty_moov+default_base_moof", "-f", "mp4", "-n"}
+	case ".mkv":
+		format = []string{"-f", "matroska", "-n"}
+	case ".ts":
+		format = []string{"-f", "mpegts", "-n"}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrOutputFormatUnsupported, path)
+	}
+
+	return &OutputVideo{
+		encoding: encoding,
+		format:   format,
+		target:   path,
+	}, nil
+}
+
+// NewOutputVideoStream sends video to rtp:// or udp:// (MPEG-TS) URL. Host must be a loopback, private, link-local
+// or multicast address, streams are not sent outside the local network. Session description of RTP stream is
+// written to sdpFile when set.
+func NewOutputVideoStream(address string, encoding VideoEncoding, sdpFile string) (*OutputVideo, error) {
+	parsedURL, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("parse output url: %w", err)
+	}
+
+	var format []string
+
+	switch parsedURL.Scheme {
+	case "rtp":
+		format = []string{"-f", "rtp"}
+		if sdpFile != "" {
+			format = append(format, "-sdp_file", sdpFile)
+		}
+	case "udp":
+		format = []string{"-f", "mpegts"}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrOutputFormatUnsupported, address)
+	}
+
+	if !utils.IsLocalNetworkHost(parsedURL.Hostname()) {
+		return nil, fmt.Errorf("%w: %s", ErrOutputAddressNotLocal, parsedURL.Hostname())
+	}
+
+	return &OutputVideo{
+		encoding: encoding,
+		format:   format,
+		target:   address,
+	}, nil
+}
+
+func (output *OutputVideo) Parameters() []string {
+	parameters := []string{
+		"-an",
+		"-c:v",
+		output.encoding.Codec,
+	}
+
+	if output.encoding.Preset != "" {
+		parameters = append(parameters, "-preset", output.encoding.Preset)
+	}
+
+	if output.encoding.Bitrate != "" {
+		parameters = append(parameters, "-b:v", output.encoding.Bitrate)
+	}
+
+	// frames are timestamped on arrival, so keyframes are forced by time instead of frame count
+	if output.encoding.KeyframeInterval > 0 {
+		seconds := strconv.FormatFloat(output.encoding.KeyframeInterval.Seconds(), 'f', -1, 64)
+		parameters = append(parameters, "-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%s)", seconds))
+	}
+
+	parameters = append(parameters, "-pix_fmt", "yuv420p", "-fps_mode", "passthrough")
+	parameters = append(parameters, output.format...)
+
+	return append(parameters, output.target)
+}
+
+func (output *OutputVideo) GetTarget() string {
+	return output.target
+}
+
+var (
+	ErrOutputFormatUnsupported = errors.New("output format unsupported")
+	ErrOutputAddressNotLocal   = errors.New("output address is not local")
+)
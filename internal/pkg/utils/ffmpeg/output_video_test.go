@@ -0,0 +1,64 @@
+package ffmpeg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputVideoFileParameters(t *testing.T) {
+	t.Parallel()
+
+	output, err := NewOutputVideoFile("/recordings/vm.mp4", VideoEncoding{
+		Codec:            "libx264",
+		Preset:           "veryfast",
+		Bitrate:          "4M",
+		KeyframeInterval: 2 * time.Second,
+	})
+	assert.NoError(t, err)
+
+	expected := []string{
+		"-an", "-c:v", "libx264", "-preset", "veryfast", "-b:v", "4M",
+		"-force_key_frames", "expr:gte(t,n_forced*2)", "-pix_fmt", "yuv420p", "-fps_mode", "passthrough",
+		"-movflags", "+frag_keyframe+empty_moov+default_base_moof", "-f", "mp4", "-n", "/recordings/vm.mp4",
+	}
+	assert.Equal(t, expected, output.Parameters())
+	assert.Equal(t, "/recordings/vm.mp4", output.GetTarget())
+}
+
+func TestOutputVideoFileUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewOutputVideoFile("/recordings/vm.avi", VideoEncoding{Codec: "libx264"})
+	assert.ErrorIs(t, err, ErrOutputFormatUnsupported)
+}
+
+func TestOutputVideoStreamParameters(t *testing.T) {
+	t.Parallel()
+
+	output, err := NewOutputVideoStream("rtp://127.0.0.1:5004", VideoEncoding{Codec: "libx264"}, "/tmp/vm.sdp")
+	assert.NoError(t, err)
+
+	expected := []string{
+		"-an", "-c:v", "libx264", "-pix_fmt", "yuv420p", "-fps_mode", "passthrough",
+		"-f", "rtp", "-sdp_file", "/tmp/vm.sdp", "rtp://127.0.0.1:5004",
+	}
+	assert.Equal(t, expected, output.Parameters())
+
+	output, err = NewOutputVideoStream("udp://239.0.0.1:1234?pkt_size=1316", VideoEncoding{Codec: "libx264"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-f", "mpegts", "udp://239.0.0.1:1234?pkt_size=1316"}, output.Parameters()[len(output.Parameters())-3:])
+}
+
+func TestOutputVideoStreamRejectsRemoteAddress(t *testing.T) {
+	t.Parallel()
+
+	for _, address := range []string{"rtp://8.8.8.8:5004", "udp://example.com:1234"} {
+		_, err := NewOutputVideoStream(address, VideoEncoding{Codec: "libx264"}, "")
+		assert.ErrorIs(t, err, ErrOutputAddressNotLocal, address)
+	}
+
+	_, err := NewOutputVideoStream("srt://127.0.0.1:9000", VideoEncoding{Codec: "libx264"}, "")
+	assert.ErrorIs(t, err, ErrOutputFormatUnsupported)
+}
@@ -0,0 +1,461 @@
+package edid
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// CeaExtensionBlock is CEA-861 extension block carrying data blocks and additional detailed timings.
+type CeaExtensionBlock [128]byte
+
+const (
+	ceaExtensionTag      = 0x02
+	ceaExtensionRevision = 0x03
+
+	// revisions before 3 have no data block collection, revision 1 has no flags either
+	ceaExtensionRevisionHeaderOnly = 0x01
+	ceaExtensionRevisionFlags      = 0x02
+
+	ceaHeaderLength  = 4
+	ceaChecksumIndex = 127
+
+	ceaFlagUnderscan            = 0b10000000
+	ceaFlagBasicAudio           = 0b01000000
+	ceaFlagYCbCr444             = 0b00100000
+	ceaFlagYCbCr422             = 0b00010000
+	ceaNativeDetailedTimingMask = 0b00001111
+
+	ceaDataBlockMaxPayloadLength = 31
+
+	ceaDataBlockTagAudio             = 1
+	ceaDataBlockTagVideo             = 2
+	ceaDataBlockTagVendorSpecific    = 3
+	ceaDataBlockTagSpeakerAllocation = 4
+	ceaDataBlockTagExtended          = 7
+
+	ceaExtendedTagColorimetry       = 5
+	ceaExtendedTagHdrStaticMetadata = 6
+)
+
+type CeaExtensionSpecification struct {
+	Underscan                 bool  `json:"underscan,omitempty"`
+	BasicAudio                bool  `json:"basicAudio,omitempty"`
+	YCbCr444                  bool  `json:"yCbCr444,omitempty"`
+	YCbCr422                  bool  `json:"yCbCr422,omitempty"`
+	NativeDetailedTimingCount uint8 `json:"nativeDetailedTimingCount,omitempty" validate:"max=15"`
+
+	VideoDescriptors   []CeaShortVideoDescriptorSpecification `json:"videoDescriptors,omitempty"`
+	AudioDescriptors   []CeaShortAudioDescriptorSpecification `json:"audioDescriptors,omitempty"`
+	SpeakerAllocation  *CeaSpeakerAllocationSpecification     `json:"speakerAllocation,omitempty"`
+	HdmiVendorSpecific *CeaHdmiVendorSpecificSpecification    `json:"hdmiVendorSpecific,omitempty"`
+	Colorimetry        *CeaColorimetrySpecification           `json:"colorimetry,omitempty"`
+	HdrStaticMetadata  *CeaHdrStaticMetadataSpecification     `json:"hdrStaticMetadata,omitempty"`
+
+	// OtherDataBlocks keeps data blocks which are not decoded, so they survive decoding and encoding.
+	OtherDataBlocks []CeaDataBlockSpecification `json:"otherDataBlocks,omitempty"`
+
+	DetailedTimings []DetailedTimingsEntrySpecification `json:"detailedTimings,omitempty"`
+}
+
+type CeaDataBlockSpecification struct {
+	Tag uint8 `json:"tag" validate:"min=1,max=7"`
+	// ExtendedTag is set for data blocks using extended tag, it is the first byte of their payload.
+	ExtendedTag *uint8 `json:"extendedTag,omitempty"`
+	Payload     []byte `json:"payload,omitempty" validate:"max=31"`
+}
+
+func CreateCeaExtensionSpecificationFromBlock(block CeaExtensionBlock) (*CeaExtensionSpecification, error) {
+	if err := validateEdidChecksum(Block(block)); err != nil {
+		return nil, err
+	}
+
+	if block[0] != ceaExtensionTag {
+		return nil, fmt.Errorf("not a cea extension block: tag 0x%02x", block[0])
+	}
+
+	revision := block[1]
+	if revision < ceaExtensionRevisionHeaderOnly || revision > ceaExtensionRevision {
+		return nil, fmt.Errorf("unsupported cea extension revision: %d", revision)
+	}
+
+	specification := &CeaExtensionSpecification{}
+
+	if revision >= ceaExtensionRevisionFlags {
+		specification.Underscan = block[3]&ceaFlagUnderscan != 0
+		specification.BasicAudio = block[3]&ceaFlagBasicAudio != 0
+		specification.YCbCr444 = block[3]&ceaFlagYCbCr444 != 0
+		specification.YCbCr422 = block[3]&ceaFlagYCbCr422 != 0
+		specification.NativeDetailedTimingCount = block[3] & ceaNativeDetailedTimingMask
+	}
+
+	// zero offset means the block carries neither data blocks nor detailed timings
+	detailedTimingsStart := int(block[2])
+	if detailedTimingsStart == 0 {
+		return specification, nil
+	}
+
+	if detailedTimingsStart < ceaHeaderLength || detailedTimingsStart > ceaChecksumIndex {
+		return nil, fmt.Errorf("invalid detailed timings offset: %d", detailedTimingsStart)
+	}
+
+	if revision == ceaExtensionRevision {
+		if err := decodeCeaDataBlockCollection(block[ceaHeaderLength:detailedTimingsStart], specification); err != nil {
+			return nil, err
+		}
+	}
+
+	for offset := detailedTimingsStart; offset+detailedTimingDescriptorLength <= ceaChecksumIndex; offset += detailedTimingDescriptorLength {
+		descriptor := block[offset : offset+detailedTimingDescriptorLength]
+
+		// padding follows the last descriptor
+		if isZeroDescriptor(descriptor) {
+			break
+		}
+
+		entry, err := decodeDetailedTimingDescriptor(descriptor)
+		if err != nil {
+			return nil, fmt.Errorf("detailed timing at offset %d: %w", offset, err)
+		}
+
+		if entry.isEmpty() {
+			continue
+		}
+
+		specification.DetailedTimings = append(specification.DetailedTimings, entry)
+	}
+
+	return specification, nil
+}
+
+func CreateCeaExtensionBlockFromSpecification(specification CeaExtensionSpecification) (*CeaExtensionBlock, error) {
+	if err := specification.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+
+	collection, err := encodeCeaDataBlockCollection(specification)
+	if err != nil {
+		return nil, err
+	}
+
+	detailedTimingsStart := ceaHeaderLength + len(collection)
+	detailedTimingsEnd := detailedTimingsStart + len(specification.DetailedTimings)*detailedTimingDescriptorLength
+
+	if detailedTimingsEnd > ceaChecksumIndex {
+		return nil, fmt.Errorf("data blocks and detailed timings exceed block length: %d bytes", detailedTimingsEnd)
+	}
+
+	block := &CeaExtensionBlock{}
+	block[0] = ceaExtensionTag
+	block[1] = ceaExtensionRevision
+
+	if len(collection) > 0 || len(specification.DetailedTimings) > 0 {
+		block[2] = byte(detailedTimingsStart)
+	}
+
+	block[3] = specification.NativeDetailedTimingCount & ceaNativeDetailedTimingMask
+	if specification.Underscan {
+		block[3] |= ceaFlagUnderscan
+	}
+	if specification.BasicAudio {
+		block[3] |= ceaFlagBasicAudio
+	}
+	if specification.YCbCr444 {
+		block[3] |= ceaFlagYCbCr444
+	}
+	if specification.YCbCr422 {
+		block[3] |= ceaFlagYCbCr422
+	}
+
+	copy(block[ceaHeaderLength:], collection)
+
+	for index, entry := range specification.DetailedTimings {
+		descriptor, err := encodeDetailedTimingDescriptor(entry)
+		if err != nil {
+			return nil, fmt.Errorf("detailed timing %d: %w", index, err)
+		}
+
+		copy(block[detailedTimingsStart+index*detailedTimingDescriptorLength:], descriptor[:])
+	}
+
+	block[ceaChecksumIndex] = calculateChecksumByte(block[:ceaChecksumIndex])
+
+	return block, nil
+}
+
+func (specification *CeaExtensionSpecification) Validate() error {
+	if specification == nil {
+		return fmt.Errorf("nil specification")
+	}
+
+	specificationValidator := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := specificationValidator.Struct(specification); err != nil {
+		return err
+	}
+
+	for index := range specification.VideoDescriptors {
+		if err := specification.VideoDescriptors[index].Validate(); err != nil {
+			return fmt.Errorf("video descriptor %d invalid: %w", index, err)
+		}
+	}
+
+	for index := range specification.AudioDescriptors {
+		if err := specification.AudioDescriptors[index].Validate(); err != nil {
+			return fmt.Errorf("audio descriptor %d invalid: %w", index, err)
+		}
+	}
+
+	if specification.HdmiVendorSpecific != nil {
+		if err := specification.HdmiVendorSpecific.Validate(); err != nil {
+			return fmt.Errorf("hdmi vendor specific data block invalid: %w", err)
+		}
+	}
+
+	if specification.HdrStaticMetadata != nil {
+		if err := specification.HdrStaticMetadata.Validate(); err != nil {
+			return fmt.Errorf("hdr static metadata data block invalid: %w", err)
+		}
+	}
+
+	for index := range specification.OtherDataBlocks {
+		if err := specification.OtherDataBlocks[index].Validate(); err != nil {
+			return fmt.Errorf("data block %d invalid: %w", index, err)
+		}
+	}
+
+	for index := range specification.DetailedTimings {
+		if err := specification.DetailedTimings[index].Validate(); err != nil {
+			return fmt.Errorf("detailed timing %d invalid: %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+func (specification *CeaDataBlockSpecification) Validate() error {
+	if specification == nil {
+		return fmt.Errorf("nil data block")
+	}
+
+	specificationValidator := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := specificationValidator.Struct(specification); err != nil {
+		return err
+	}
+
+	if specification.Tag == ceaDataBlockTagExtended {
+		if specification.ExtendedTag == nil {
+			return fmt.Errorf("extended tag missing")
+		}
+
+		if len(specification.Payload) > ceaDataBlockMaxPayloadLength-1 {
+			return fmt.Errorf("payload too long: %d", len(specification.Payload))
+		}
+	} else if specification.ExtendedTag != nil {
+		return fmt.Errorf("extended tag set for tag %d", specification.Tag)
+	}
+
+	return nil
+}
+
+func decodeCeaDataBlockCollection(collection []byte, specification *CeaExtensionSpecification) error {
+	for offset := 0; offset < len(collection); {
+		tag := collection[offset] >> 5
+		length := int(collection[offset] & 0x1F)
+
+		if offset+1+length > len(collection) {
+			return fmt.Errorf("data block at offset %d exceeds data block collection", offset)
+		}
+
+		payload := collection[offset+1 : offset+1+length]
+
+		if err := decodeCeaDataBlock(tag, payload, specification); err != nil {
+			return fmt.Errorf("data block at offset %d: %w", offset, err)
+		}
+
+		offset += 1 + length
+	}
+
+	return nil
+}
+
+func decodeCeaDataBlock(tag byte, payload []byte, specification *CeaExtensionSpecification) error {
+	switch tag {
+	case ceaDataBlockTagVideo:
+		descriptors, err := decodeCeaVideoDataBlock(payload)
+		if err != nil {
+			return fmt.Errorf("video: %w", err)
+		}
+
+		specification.VideoDescriptors = append(specification.VideoDescriptors, descriptors...)
+
+		return nil
+	case ceaDataBlockTagAudio:
+		descriptors, err := decodeCeaAudioDataBlock(payload)
+		if err != nil {
+			return fmt.Errorf("audio: %w", err)
+		}
+
+		specification.AudioDescriptors = append(specification.AudioDescriptors, descriptors...)
+
+		return nil
+	case ceaDataBlockTagSpeakerAllocation:
+		if specification.SpeakerAllocation != nil {
+			return fmt.Errorf("duplicate speaker allocation data block")
+		}
+
+		speakerAllocation, err := decodeCeaSpeakerAllocationDataBlock(payload)
+		if err != nil {
+			return fmt.Errorf("speaker allocation: %w", err)
+		}
+
+		specification.SpeakerAllocation = speakerAllocation
+
+		return nil
+	case ceaDataBlockTagVendorSpecific:
+		if !isCeaHdmiVendorSpecificDataBlock(payload) || specification.HdmiVendorSpecific != nil {
+			break
+		}
+
+		hdmiVendorSpecific, err := decodeCeaHdmiVendorSpecificDataBlock(payload)
+		if err != nil {
+			return fmt.Errorf("hdmi vendor specific: %w", err)
+		}
+
+		specification.HdmiVendorSpecific = hdmiVendorSpecific
+
+		return nil
+	case ceaDataBlockTagExtended:
+		if len(payload) == 0 {
+			return fmt.Errorf("extended tag missing")
+		}
+
+		extendedTag := payload[0]
+
+		switch {
+		case extendedTag == ceaExtendedTagColorimetry && specification.Colorimetry == nil:
+			colorimetry, err := decodeCeaColorimetryDataBlock(payload[1:])
+			if err != nil {
+				return fmt.Errorf("colorimetry: %w", err)
+			}
+
+			specification.Colorimetry = colorimetry
+
+			return nil
+		case extendedTag == ceaExtendedTagHdrStaticMetadata && specification.HdrStaticMetadata == nil:
+			hdrStaticMetadata, err := decodeCeaHdrStaticMetadataDataBlock(payload[1:])
+			if err != nil {
+				return fmt.Errorf("hdr static metadata: %w", err)
+			}
+
+			specification.HdrStaticMetadata = hdrStaticMetadata
+
+			return nil
+		}
+
+		specification.OtherDataBlocks = append(specification.OtherDataBlocks, CeaDataBlockSpecification{
+			Tag:         tag,
+			ExtendedTag: &extendedTag,
+			Payload:     append([]byte{}, payload[1:]...),
+		})
+
+		return nil
+	}
+
+	specification.OtherDataBlocks = append(specification.OtherDataBlocks, CeaDataBlockSpecification{
+		Tag:     tag,
+		Payload: append([]byte{}, payload...),
+	})
+
+	return nil
+}
+
+// encodeCeaDataBlockCollection encodes decoded data blocks in the order most monitors use, followed by other data
+// blocks in their original order.
+// withoutDisplayModes returns copy of the extension without video data blocks and detailed timings, so it carries
+// capabilities of the monitor but declares no display modes.
+func (specification CeaExtensionSpecification) withoutDisplayModes() *CeaExtensionSpecification {
+	specification.VideoDescriptors = nil
+	specification.DetailedTimings = nil
+	specification.NativeDetailedTimingCount = 0
+
+	return &specification
+}
+
+// freeDetailedTimingSlots returns the number of detailed timings fitting the extension after its data blocks.
+func (specification CeaExtensionSpecification) freeDetailedTimingSlots() int {
+	collection, err := encodeCeaDataBlockCollection(specification)
+	if err != nil {
+		return 0
+	}
+
+	return max(ceaChecksumIndex-ceaHeaderLength-len(collection), 0) / detailedTimingDescriptorLength
+}
+
+func encodeCeaDataBlockCollection(specification CeaExtensionSpecification) ([]byte, error) {
+	collection := []byte{}
+
+	for start := 0; start < len(specification.VideoDescriptors); start += ceaVideoDataBlockMaxDescriptors {
+		end := min(start+ceaVideoDataBlockMaxDescriptors, len(specification.VideoDescriptors))
+
+		payload, err := encodeCeaVideoDataBlock(specification.VideoDescriptors[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("video: %w", err)
+		}
+
+		collection = appendCeaDataBlock(collection, ceaDataBlockTagVideo, payload)
+	}
+
+	for start := 0; start < len(specification.AudioDescriptors); start += ceaAudioDataBlockMaxDescriptors {
+		end := min(start+ceaAudioDataBlockMaxDescriptors, len(specification.AudioDescriptors))
+
+		payload, err := encodeCeaAudioDataBlock(specification.AudioDescriptors[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("audio: %w", err)
+		}
+
+		collection = appendCeaDataBlock(collection, ceaDataBlockTagAudio, payload)
+	}
+
+	if specification.SpeakerAllocation != nil {
+		collection = appendCeaDataBlock(collection, ceaDataBlockTagSpeakerAllocation, encodeCeaSpeakerAllocationDataBlock(*specification.SpeakerAllocation))
+	}
+
+	if specification.HdmiVendorSpecific != nil {
+		payload, err := encodeCeaHdmiVendorSpecificDataBlock(*specification.HdmiVendorSpecific)
+		if err != nil {
+			return nil, fmt.Errorf("hdmi vendor specific: %w", err)
+		}
+
+		collection = appendCeaDataBlock(collection, ceaDataBlockTagVendorSpecific, payload)
+	}
+
+	if specification.Colorimetry != nil {
+		payload := append([]byte{ceaExtendedTagColorimetry}, encodeCeaColorimetryDataBlock(*specification.Colorimetry)...)
+		collection = appendCeaDataBlock(collection, ceaDataBlockTagExtended, payload)
+	}
+
+	if specification.HdrStaticMetadata != nil {
+		payload := append([]byte{ceaExtendedTagHdrStaticMetadata}, encodeCeaHdrStaticMetadataDataBlock(*specification.HdrStaticMetadata)...)
+		collection = appendCeaDataBlock(collection, ceaDataBlockTagExtended, payload)
+	}
+
+	for _, dataBlock := range specification.OtherDataBlocks {
+		payload := dataBlock.Payload
+		if dataBlock.ExtendedTag != nil {
+			payload = append([]byte{*dataBlock.ExtendedTag}, payload...)
+		}
+
+		collection = appendCeaDataBlock(collection, dataBlock.Tag, payload)
+	}
+
+	return collection, nil
+}
+
+// appendCeaDataBlock appends data block header and payload, payload length is limited by validation of the
+// specification.
+func appendCeaDataBlock(collection []byte, tag byte, payload []byte) []byte {
+	collection = append(collection, tag<<5|byte(len(payload)))
+	return append(collection, payload...)
+}
@@ -0,0 +1,296 @@
+package edid
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	ceaShortAudioDescriptorLength   = 3
+	ceaAudioDataBlockMaxDescriptors = ceaDataBlockMaxPayloadLength / ceaShortAudioDescriptorLength
+
+	ceaSpeakerAllocationDataBlockLength = 3
+)
+
+type CeaAudioFormat string
+
+const (
+	CeaAudioFormatLpcm        CeaAudioFormat = "lpcm"
+	CeaAudioFormatAc3         CeaAudioFormat = "ac3"
+	CeaAudioFormatMpeg1       CeaAudioFormat = "mpeg1"
+	CeaAudioFormatMp3         CeaAudioFormat = "mp3"
+	CeaAudioFormatMpeg2       CeaAudioFormat = "mpeg2"
+	CeaAudioFormatAacLc       CeaAudioFormat = "aac-lc"
+	CeaAudioFormatDts         CeaAudioFormat = "dts"
+	CeaAudioFormatAtrac       CeaAudioFormat = "atrac"
+	CeaAudioFormatOneBitAudio CeaAudioFormat = "one-bit-audio"
+	CeaAudioFormatEnhancedAc3 CeaAudioFormat = "e-ac3"
+	CeaAudioFormatDtsHd       CeaAudioFormat = "dts-hd"
+	CeaAudioFormatMat         CeaAudioFormat = "mat"
+	CeaAudioFormatDst         CeaAudioFormat = "dst"
+	CeaAudioFormatWmaPro      CeaAudioFormat = "wma-pro"
+	CeaAudioFormatExtended    CeaAudioFormat = "extended"
+)
+
+// CeaShortAudioDescriptorSpecification declares audio format accepted by the monitor.
+type CeaShortAudioDescriptorSpecification struct {
+	Format      CeaAudioFormat `json:"format" validate:"required"`
+	MaxChannels uint8          `json:"maxChannels" validate:"min=1,max=8"`
+	// SampleRates in Hz.
+	SampleRates []uint32 `json:"sampleRates,omitempty" validate:"omitempty,dive,oneof=32000 44100 48000 88200 96000 176400 192000"`
+	// BitDepths of lpcm format.
+	BitDepths []uint8 `json:"bitDepths,omitempty" validate:"omitempty,dive,oneof=16 20 24"`
+	// MaxBitRate in kbit/s of formats from ac3 to atrac, it is a multiple of 8.
+	MaxBitRate uint32 `json:"maxBitRate,omitempty" validate:"max=2040"`
+	// FormatSpecific is the last descriptor byte of remaining formats.
+	FormatSpecific uint8 `json:"formatSpecific,omitempty"`
+}
+
+// CeaSpeakerAllocationSpecification declares speakers present on the monitor.
+type CeaSpeakerAllocationSpecification struct {
+	FrontLeftRight       bool `json:"frontLeftRight,omitempty"`
+	LowFrequencyEffect   bool `json:"lowFrequencyEffect,omitempty"`
+	FrontCenter          bool `json:"frontCenter,omitempty"`
+	RearLeftRight        bool `json:"rearLeftRight,omitempty"`
+	RearCenter           bool `json:"rearCenter,omitempty"`
+	FrontLeftRightCenter bool `json:"frontLeftRightCenter,omitempty"`
+	RearLeftRightCenter  bool `json:"rearLeftRightCenter,omitempty"`
+	FrontLeftRightWide   bool `json:"frontLeftRightWide,omitempty"`
+	FrontLeftRightHigh   bool `json:"frontLeftRightHigh,omitempty"`
+	TopCenter            bool `json:"topCenter,omitempty"`
+	FrontCenterHigh      bool `json:"frontCenterHigh,omitempty"`
+}
+
+var (
+	ceaAudioFormatByCode = map[byte]CeaAudioFormat{
+		1:  CeaAudioFormatLpcm,
+		2:  CeaAudioFormatAc3,
+		3:  CeaAudioFormatMpeg1,
+		4:  CeaAudioFormatMp3,
+		5:  CeaAudioFormatMpeg2,
+		6:  CeaAudioFormatAacLc,
+		7:  CeaAudioFormatDts,
+		8:  CeaAudioFormatAtrac,
+		9:  CeaAudioFormatOneBitAudio,
+		10: CeaAudioFormatEnhancedAc3,
+		11: CeaAudioFormatDtsHd,
+		12: CeaAudioFormatMat,
+		13: CeaAudioFormatDst,
+		14: CeaAudioFormatWmaPro,
+		15: CeaAudioFormatExtended,
+	}
+
+	ceaAudioFormatToCode = map[CeaAudioFormat]byte{
+		CeaAudioFormatLpcm:        1,
+		CeaAudioFormatAc3:         2,
+		CeaAudioFormatMpeg1:       3,
+		CeaAudioFormatMp3:         4,
+		CeaAudioFormatMpeg2:       5,
+		CeaAudioFormatAacLc:       6,
+		CeaAudioFormatDts:         7,
+		CeaAudioFormatAtrac:       8,
+		CeaAudioFormatOneBitAudio: 9,
+		CeaAudioFormatEnhancedAc3: 10,
+		CeaAudioFormatDtsHd:       11,
+		CeaAudioFormatMat:         12,
+		CeaAudioFormatDst:         13,
+		CeaAudioFormatWmaPro:      14,
+		CeaAudioFormatExtended:    15,
+	}
+
+	// ceaAudioSampleRates and ceaAudioBitDepths are ordered by their bit in the descriptor.
+	ceaAudioSampleRates = []uint32{32000, 44100, 48000, 88200, 96000, 176400, 192000}
+	ceaAudioBitDepths   = []uint8{16, 20, 24}
+)
+
+func (specification *CeaShortAudioDescriptorSpecification) Validate() error {
+	if specification == nil {
+		return fmt.Errorf("nil audio descriptor")
+	}
+
+	specificationValidator := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := specificationValidator.Struct(specification); err != nil {
+		return err
+	}
+
+	code, supported := ceaAudioFormatToCode[specification.Format]
+	if !supported {
+		return fmt.Errorf("unsupported audio format: %s", specification.Format)
+	}
+
+	if code != 1 && len(specification.BitDepths) > 0 {
+		return fmt.Errorf("bit depths set for %s format", specification.Format)
+	}
+
+	if !ceaAudioFormatHasMaxBitRate(code) && specification.MaxBitRate != 0 {
+		return fmt.Errorf("max bit rate set for %s format", specification.Format)
+	}
+
+	if specification.MaxBitRate%8 != 0 {
+		return fmt.Errorf("max bit rate must be a multiple of 8: %d", specification.MaxBitRate)
+	}
+
+	if (code == 1 || ceaAudioFormatHasMaxBitRate(code)) && specification.FormatSpecific != 0 {
+		return fmt.Errorf("format specific value set for %s format", specification.Format)
+	}
+
+	return nil
+}
+
+func decodeCeaAudioDataBlock(payload []byte) ([]CeaShortAudioDescriptorSpecification, error) {
+	if len(payload)%ceaShortAudioDescriptorLength != 0 {
+		return nil, fmt.Errorf("payload length is not a multiple of descriptor length: %d", len(payload))
+	}
+
+	descriptors := make([]CeaShortAudioDescriptorSpecification, 0, len(payload)/ceaShortAudioDescriptorLength)
+
+	for offset := 0; offset < len(payload); offset += ceaShortAudioDescriptorLength {
+		descriptor, err := decodeCeaShortAudioDescriptor(payload[offset : offset+ceaShortAudioDescriptorLength])
+		if err != nil {
+			return nil, fmt.Errorf("descriptor %d: %w", offset/ceaShortAudioDescriptorLength, err)
+		}
+
+		descriptors = append(descriptors, descriptor)
+	}
+
+	return descriptors, nil
+}
+
+func decodeCeaShortAudioDescriptor(descriptor []byte) (CeaShortAudioDescriptorSpecification, error) {
+	if descriptor[0]&0b10000000 != 0 || descriptor[1]&0b10000000 != 0 {
+		return CeaShortAudioDescriptorSpecification{}, fmt.Errorf("reserved bits set")
+	}
+
+	code := (descriptor[0] >> 3) & 0x0F
+
+	format, supported := ceaAudioFormatByCode[code]
+	if !supported {
+		return CeaShortAudioDescriptorSpecification{}, fmt.Errorf("unsupported audio format code: %d", code)
+	}
+
+	specification := CeaShortAudioDescriptorSpecification{
+		Format:      format,
+		MaxChannels: descriptor[0]&0b111 + 1,
+	}
+
+	for bit, sampleRate := range ceaAudioSampleRates {
+		if descriptor[1]&(1<<bit) != 0 {
+			specification.SampleRates = append(specification.SampleRates, sampleRate)
+		}
+	}
+
+	switch {
+	case code == 1:
+		if descriptor[2]&0b11111000 != 0 {
+			return CeaShortAudioDescriptorSpecification{}, fmt.Errorf("reserved bit depth bits set")
+		}
+
+		for bit, bitDepth := range ceaAudioBitDepths {
+			if descriptor[2]&(1<<bit) != 0 {
+				specification.BitDepths = append(specification.BitDepths, bitDepth)
+			}
+		}
+	case ceaAudioFormatHasMaxBitRate(code):
+		specification.MaxBitRate = uint32(descriptor[2]) * 8
+	default:
+		specification.FormatSpecific = descriptor[2]
+	}
+
+	return specification, nil
+}
+
+func encodeCeaAudioDataBlock(descriptors []CeaShortAudioDescriptorSpecification) ([]byte, error) {
+	payload := make([]byte, 0, len(descriptors)*ceaShortAudioDescriptorLength)
+
+	for index, descriptor := range descriptors {
+		if err := descriptor.Validate(); err != nil {
+			return nil, fmt.Errorf("descriptor %d: %w", index, err)
+		}
+
+		code := ceaAudioFormatToCode[descriptor.Format]
+
+		var encoded [ceaShortAudioDescriptorLength]byte
+		encoded[0] = code<<3 | (descriptor.MaxChannels - 1)
+
+		for _, sampleRate := range descriptor.SampleRates {
+			encoded[1] |= 1 << slices.Index(ceaAudioSampleRates, sampleRate)
+		}
+
+		switch {
+		case code == 1:
+			for _, bitDepth := range descriptor.BitDepths {
+				encoded[2] |= 1 << slices.Index(ceaAudioBitDepths, bitDepth)
+			}
+		case ceaAudioFormatHasMaxBitRate(code):
+			encoded[2] = byte(descriptor.MaxBitRate / 8)
+		default:
+			encoded[2] = descriptor.FormatSpecific
+		}
+
+		payload = append(payload, encoded[:]...)
+	}
+
+	return payload, nil
+}
+
+func decodeCeaSpeakerAllocationDataBlock(payload []byte) (*CeaSpeakerAllocationSpecification, error) {
+	if len(payload) != ceaSpeakerAllocationDataBlockLength {
+		return nil, fmt.Errorf("unsupported payload length: %d", len(payload))
+	}
+
+	if payload[1]&0b11111000 != 0 || payload[2] != 0 {
+		return nil, fmt.Errorf("reserved bits set")
+	}
+
+	return &CeaSpeakerAllocationSpecification{
+		FrontLeftRight:       payload[0]&0b00000001 != 0,
+		LowFrequencyEffect:   payload[0]&0b00000010 != 0,
+		FrontCenter:          payload[0]&0b00000100 != 0,
+		RearLeftRight:        payload[0]&0b00001000 != 0,
+		RearCenter:           payload[0]&0b00010000 != 0,
+		FrontLeftRightCenter: payload[0]&0b00100000 != 0,
+		RearLeftRightCenter:  payload[0]&0b01000000 != 0,
+		FrontLeftRightWide:   payload[0]&0b10000000 != 0,
+		FrontLeftRightHigh:   payload[1]&0b00000001 != 0,
+		TopCenter:            payload[1]&0b00000010 != 0,
+		FrontCenterHigh:      payload[1]&0b00000100 != 0,
+	}, nil
+}
+
+func encodeCeaSpeakerAllocationDataBlock(specification CeaSpeakerAllocationSpecification) []byte {
+	payload := make([]byte, ceaSpeakerAllocationDataBlockLength)
+
+	flags := []struct {
+		set   bool
+		index int
+		mask  byte
+	}{
+		{specification.FrontLeftRight, 0, 0b00000001},
+		{specification.LowFrequencyEffect, 0, 0b00000010},
+		{specification.FrontCenter, 0, 0b00000100},
+		{specification.RearLeftRight, 0, 0b00001000},
+		{specification.RearCenter, 0, 0b00010000},
+		{specification.FrontLeftRightCenter, 0, 0b00100000},
+		{specification.RearLeftRightCenter, 0, 0b01000000},
+		{specification.FrontLeftRightWide, 0, 0b10000000},
+		{specification.FrontLeftRightHigh, 1, 0b00000001},
+		{specification.TopCenter, 1, 0b00000010},
+		{specification.FrontCenterHigh, 1, 0b00000100},
+	}
+
+	for _, flag := range flags {
+		if flag.set {
+			payload[flag.index] |= flag.mask
+		}
+	}
+
+	return payload
+}
+
+// ceaAudioFormatHasMaxBitRate reports whether third descriptor byte of the format code is maximum bit rate.
+func ceaAudioFormatHasMaxBitRate(code byte) bool {
+	return code >= 2 && code <= 8
+}
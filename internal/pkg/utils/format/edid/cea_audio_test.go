@@ -0,0 +1,116 @@
+package edid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCeaAudioDataBlockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	descriptors := []CeaShortAudioDescriptorSpecification{
+		{Format: CeaAudioFormatLpcm, MaxChannels: 8, SampleRates: []uint32{44100, 48000, 96000, 192000}, BitDepths: []uint8{16, 24}},
+		{Format: CeaAudioFormatAc3, MaxChannels: 6, SampleRates: []uint32{32000, 44100, 48000}, MaxBitRate: 640},
+		{Format: CeaAudioFormatEnhancedAc3, MaxChannels: 8, SampleRates: []uint32{48000}, FormatSpecific: 0x01},
+	}
+
+	payload, err := encodeCeaAudioDataBlock(descriptors)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x0F, 0x56, 0x05, 0x15, 0x07, 0x50, 0x57, 0x04, 0x01}, payload)
+
+	decoded, err := decodeCeaAudioDataBlock(payload)
+	require.NoError(t, err)
+	assert.Equal(t, descriptors, decoded)
+}
+
+func TestDecodeCeaAudioDataBlockErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := decodeCeaAudioDataBlock([]byte{0x09, 0x07})
+	assert.ErrorContains(t, err, "not a multiple of descriptor length")
+
+	_, err = decodeCeaAudioDataBlock([]byte{0x01, 0x07, 0x07})
+	assert.ErrorContains(t, err, "unsupported audio format code: 0")
+
+	_, err = decodeCeaAudioDataBlock([]byte{0x09, 0x87, 0x07})
+	assert.ErrorContains(t, err, "reserved bits set")
+
+	_, err = decodeCeaAudioDataBlock([]byte{0x09, 0x07, 0x0F})
+	assert.ErrorContains(t, err, "reserved bit depth bits set")
+}
+
+func TestCeaShortAudioDescriptorSpecificationValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		descriptor CeaShortAudioDescriptorSpecification
+		errMsg     string
+	}{
+		{
+			name:       "unsupported format",
+			descriptor: CeaShortAudioDescriptorSpecification{Format: "opus", MaxChannels: 2},
+			errMsg:     "unsupported audio format: opus",
+		},
+		{
+			name:       "bit depths of compressed format",
+			descriptor: CeaShortAudioDescriptorSpecification{Format: CeaAudioFormatAc3, MaxChannels: 2, BitDepths: []uint8{16}},
+			errMsg:     "bit depths set for ac3 format",
+		},
+		{
+			name:       "max bit rate of lpcm",
+			descriptor: CeaShortAudioDescriptorSpecification{Format: CeaAudioFormatLpcm, MaxChannels: 2, MaxBitRate: 64},
+			errMsg:     "max bit rate set for lpcm format",
+		},
+		{
+			name:       "max bit rate step",
+			descriptor: CeaShortAudioDescriptorSpecification{Format: CeaAudioFormatDts, MaxChannels: 2, MaxBitRate: 100},
+			errMsg:     "max bit rate must be a multiple of 8: 100",
+		},
+		{
+			name:       "sample rate",
+			descriptor: CeaShortAudioDescriptorSpecification{Format: CeaAudioFormatLpcm, MaxChannels: 2, SampleRates: []uint32{22050}},
+			errMsg:     "SampleRates[0]",
+		},
+		{
+			name:       "channels",
+			descriptor: CeaShortAudioDescriptorSpecification{Format: CeaAudioFormatLpcm, MaxChannels: 9},
+			errMsg:     "MaxChannels",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.ErrorContains(t, testCase.descriptor.Validate(), testCase.errMsg)
+		})
+	}
+}
+
+func TestCeaSpeakerAllocationDataBlockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	speakerAllocation := CeaSpeakerAllocationSpecification{
+		FrontLeftRight:     true,
+		LowFrequencyEffect: true,
+		FrontCenter:        true,
+		RearLeftRight:      true,
+		TopCenter:          true,
+	}
+
+	payload := encodeCeaSpeakerAllocationDataBlock(speakerAllocation)
+	assert.Equal(t, []byte{0x0F, 0x02, 0x00}, payload)
+
+	decoded, err := decodeCeaSpeakerAllocationDataBlock(payload)
+	require.NoError(t, err)
+	assert.Equal(t, &speakerAllocation, decoded)
+
+	_, err = decodeCeaSpeakerAllocationDataBlock([]byte{0x01, 0x00})
+	assert.ErrorContains(t, err, "unsupported payload length")
+
+	_, err = decodeCeaSpeakerAllocationDataBlock([]byte{0x01, 0x08, 0x00})
+	assert.ErrorContains(t, err, "reserved bits set")
+}
@@ -0,0 +1,191 @@
+package edid
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	ceaColorimetryDataBlockLength          = 2
+	ceaHdrStaticMetadataDataBlockMinLength = 2
+	ceaHdrStaticMetadataDataBlockMaxLength = 5
+
+	ceaColorimetryMetadataProfileMask = 0b00001111
+	ceaColorimetryFlagDciP3           = 0b10000000
+
+	ceaHdrEotfReservedMask           = 0b11110000
+	ceaHdrStaticMetadataType1        = 0b00000001
+	ceaHdrStaticMetadataReservedMask = 0b11111110
+)
+
+// CeaColorimetrySpecification declares extended colorimetry accepted by the monitor.
+type CeaColorimetrySpecification struct {
+	XvYcc601   bool `json:"xvYcc601,omitempty"`
+	XvYcc709   bool `json:"xvYcc709,omitempty"`
+	SYcc601    bool `json:"sYcc601,omitempty"`
+	OpYcc601   bool `json:"opYcc601,omitempty"`
+	OpRgb      bool `json:"opRgb,omitempty"`
+	Bt2020cYcc bool `json:"bt2020cYcc,omitempty"`
+	Bt2020Ycc  bool `json:"bt2020Ycc,omitempty"`
+	Bt2020Rgb  bool `json:"bt2020Rgb,omitempty"`
+	DciP3      bool `json:"dciP3,omitempty"`
+
+	// MetadataProfiles is bit mask of supported gamut metadata profiles MD0 to MD3.
+	MetadataProfiles uint8 `json:"metadataProfiles,omitempty" validate:"max=15"`
+}
+
+// CeaHdrStaticMetadataSpecification declares HDR transfer functions and luminance of the monitor.
+type CeaHdrStaticMetadataSpecification struct {
+	TraditionalGammaSdr bool `json:"traditionalGammaSdr,omitempty"`
+	TraditionalGammaHdr bool `json:"traditionalGammaHdr,omitempty"`
+	Smpte2084           bool `json:"smpte2084,omitempty"`
+	Hlg                 bool `json:"hlg,omitempty"`
+
+	StaticMetadataType1 bool `json:"staticMetadataType1,omitempty"`
+
+	// MaxLuminance, MaxFrameAverageLuminance and MinLuminance are coded values defined by CTA-861, nil when not
+	// declared. Each value requires the previous ones.
+	MaxLuminance             *uint8 `json:"maxLuminance,omitempty"`
+	MaxFrameAverageLuminance *uint8 `json:"maxFrameAverageLuminance,omitempty"`
+	MinLuminance             *uint8 `json:"minLuminance,omitempty"`
+}
+
+func (specification *CeaHdrStaticMetadataSpecification) Validate() error {
+	if specification == nil {
+		return fmt.Errorf("nil hdr static metadata data block")
+	}
+
+	specificationValidator := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := specificationValidator.Struct(specification); err != nil {
+		return err
+	}
+
+	if specification.MaxLuminance == nil && specification.MaxFrameAverageLuminance != nil {
+		return fmt.Errorf("max frame average luminance requires max luminance")
+	}
+
+	if specification.MaxFrameAverageLuminance == nil && specification.MinLuminance != nil {
+		return fmt.Errorf("min luminance requires max frame average luminance")
+	}
+
+	return nil
+}
+
+func decodeCeaColorimetryDataBlock(payload []byte) (*CeaColorimetrySpecification, error) {
+	if len(payload) != ceaColorimetryDataBlockLength {
+		return nil, fmt.Errorf("unsupported payload length: %d", len(payload))
+	}
+
+	if payload[1]&^(ceaColorimetryMetadataProfileMask|ceaColorimetryFlagDciP3) != 0 {
+		return nil, fmt.Errorf("reserved bits set")
+	}
+
+	return &CeaColorimetrySpecification{
+		XvYcc601:         payload[0]&0b00000001 != 0,
+		XvYcc709:         payload[0]&0b00000010 != 0,
+		SYcc601:          payload[0]&0b00000100 != 0,
+		OpYcc601:         payload[0]&0b00001000 != 0,
+		OpRgb:            payload[0]&0b00010000 != 0,
+		Bt2020cYcc:       payload[0]&0b00100000 != 0,
+		Bt2020Ycc:        payload[0]&0b01000000 != 0,
+		Bt2020Rgb:        payload[0]&0b10000000 != 0,
+		DciP3:            payload[1]&ceaColorimetryFlagDciP3 != 0,
+		MetadataProfiles: payload[1] & ceaColorimetryMetadataProfileMask,
+	}, nil
+}
+
+func encodeCeaColorimetryDataBlock(specification CeaColorimetrySpecification) []byte {
+	payload := make([]byte, ceaColorimetryDataBlockLength)
+
+	colorimetries := []bool{
+		specification.XvYcc601,
+		specification.XvYcc709,
+		specification.SYcc601,
+		specification.OpYcc601,
+		specification.OpRgb,
+		specification.Bt2020cYcc,
+		specification.Bt2020Ycc,
+		specification.Bt2020Rgb,
+	}
+
+	for bit, supported := range colorimetries {
+		if supported {
+			payload[0] |= 1 << bit
+		}
+	}
+
+	payload[1] = specification.MetadataProfiles & ceaColorimetryMetadataProfileMask
+	if specification.DciP3 {
+		payload[1] |= ceaColorimetryFlagDciP3
+	}
+
+	return payload
+}
+
+func decodeCeaHdrStaticMetadataDataBlock(payload []byte) (*CeaHdrStaticMetadataSpecification, error) {
+	if len(payload) < ceaHdrStaticMetadataDataBlockMinLength || len(payload) > ceaHdrStaticMetadataDataBlockMaxLength {
+		return nil, fmt.Errorf("unsupported payload length: %d", len(payload))
+	}
+
+	if payload[0]&ceaHdrEotfReservedMask != 0 || payload[1]&ceaHdrStaticMetadataReservedMask != 0 {
+		return nil, fmt.Errorf("reserved bits set")
+	}
+
+	specification := &CeaHdrStaticMetadataSpecification{
+		TraditionalGammaSdr: payload[0]&0b00000001 != 0,
+		TraditionalGammaHdr: payload[0]&0b00000010 != 0,
+		Smpte2084:           payload[0]&0b00000100 != 0,
+		Hlg:                 payload[0]&0b00001000 != 0,
+		StaticMetadataType1: payload[1]&ceaHdrStaticMetadataType1 != 0,
+	}
+
+	luminances := []**uint8{
+		&specification.MaxLuminance,
+		&specification.MaxFrameAverageLuminance,
+		&specification.MinLuminance,
+	}
+
+	for index, value := range payload[2:] {
+		*luminances[index] = &value
+	}
+
+	return specification, nil
+}
+
+func encodeCeaHdrStaticMetadataDataBlock(specification CeaHdrStaticMetadataSpecification) []byte {
+	payload := make([]byte, ceaHdrStaticMetadataDataBlockMinLength, ceaHdrStaticMetadataDataBlockMaxLength)
+
+	transferFunctions := []bool{
+		specification.TraditionalGammaSdr,
+		specification.TraditionalGammaHdr,
+		specification.Smpte2084,
+		specification.Hlg,
+	}
+
+	for bit, supported := range transferFunctions {
+		if supported {
+			payload[0] |= 1 << bit
+		}
+	}
+
+	if specification.StaticMetadataType1 {
+		payload[1] |= ceaHdrStaticMetadataType1
+	}
+
+	// luminance values are declared in order, validation guarantees no gaps
+	for _, luminance := range []*uint8{
+		specification.MaxLuminance,
+		specification.MaxFrameAverageLuminance,
+		specification.MinLuminance,
+	} {
+		if luminance == nil {
+			break
+		}
+
+		payload = append(payload, *luminance)
+	}
+
+	return payload
+}
@@ -0,0 +1,69 @@
+package edid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCeaColorimetryDataBlockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	colorimetry := CeaColorimetrySpecification{
+		XvYcc601:         true,
+		XvYcc709:         true,
+		Bt2020Rgb:        true,
+		DciP3:            true,
+		MetadataProfiles: 0b0001,
+	}
+
+	payload := encodeCeaColorimetryDataBlock(colorimetry)
+	assert.Equal(t, []byte{0x83, 0x81}, payload)
+
+	decoded, err := decodeCeaColorimetryDataBlock(payload)
+	require.NoError(t, err)
+	assert.Equal(t, &colorimetry, decoded)
+
+	_, err = decodeCeaColorimetryDataBlock([]byte{0x83})
+	assert.ErrorContains(t, err, "unsupported payload length")
+
+	_, err = decodeCeaColorimetryDataBlock([]byte{0x83, 0x10})
+	assert.ErrorContains(t, err, "reserved bits set")
+}
+
+func TestCeaHdrStaticMetadataDataBlockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, payload := range [][]byte{
+		{0x0D, 0x01},
+		{0x0D, 0x01, 0x60},
+		{0x0D, 0x01, 0x60, 0x48, 0x10},
+	} {
+		decoded, err := decodeCeaHdrStaticMetadataDataBlock(payload)
+		require.NoError(t, err)
+		require.NoError(t, decoded.Validate())
+
+		assert.True(t, decoded.TraditionalGammaSdr)
+		assert.True(t, decoded.Smpte2084)
+		assert.True(t, decoded.Hlg)
+		assert.False(t, decoded.TraditionalGammaHdr)
+
+		assert.Equal(t, payload, encodeCeaHdrStaticMetadataDataBlock(*decoded))
+	}
+
+	_, err := decodeCeaHdrStaticMetadataDataBlock([]byte{0x0D})
+	assert.ErrorContains(t, err, "unsupported payload length")
+
+	_, err = decodeCeaHdrStaticMetadataDataBlock([]byte{0x1D, 0x01})
+	assert.ErrorContains(t, err, "reserved bits set")
+}
+
+func TestCeaHdrStaticMetadataSpecificationValidate(t *testing.T) {
+	t.Parallel()
+
+	luminance := uint8(0x40)
+
+	assert.EqualError(t, (&CeaHdrStaticMetadataSpecification{MaxFrameAverageLuminance: &luminance}).Validate(), "max frame average luminance requires max luminance")
+	assert.EqualError(t, (&CeaHdrStaticMetadataSpecification{MaxLuminance: &luminance, MinLuminance: &luminance}).Validate(), "min luminance requires max frame average luminance")
+}
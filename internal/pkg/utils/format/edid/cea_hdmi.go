@@ -0,0 +1,171 @@
+package edid
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var ceaHdmiOui = []byte{0x03, 0x0C, 0x00}
+
+const (
+	ceaHdmiVendorSpecificMinLength = 5
+
+	ceaHdmiFlagSupportsAi        = 0b10000000
+	ceaHdmiFlagDeepColor48       = 0b01000000
+	ceaHdmiFlagDeepColor36       = 0b00100000
+	ceaHdmiFlagDeepColor30       = 0b00010000
+	ceaHdmiFlagDeepColorYCbCr444 = 0b00001000
+	ceaHdmiFlagReserved          = 0b00000110
+	ceaHdmiFlagDualDvi           = 0b00000001
+
+	ceaHdmiTmdsClockStep = 5
+)
+
+// CeaHdmiVendorSpecificSpecification is HDMI 1.4 vendor specific data block, its presence marks HDMI monitor.
+type CeaHdmiVendorSpecificSpecification struct {
+	// PhysicalAddress is CEC physical address, for example 1.0.0.0.
+	PhysicalAddress string `json:"physicalAddress" validate:"required"`
+
+	SupportsAi        bool `json:"supportsAi,omitempty"`
+	DeepColor48       bool `json:"deepColor48,omitempty"`
+	DeepColor36       bool `json:"deepColor36,omitempty"`
+	DeepColor30       bool `json:"deepColor30,omitempty"`
+	DeepColorYCbCr444 bool `json:"deepColorYCbCr444,omitempty"`
+	DualDvi           bool `json:"dualDvi,omitempty"`
+
+	// MaxTmdsClock in MHz, zero when not declared.
+	MaxTmdsClock uint16 `json:"maxTmdsClock,omitempty" validate:"max=1275"`
+
+	// AdditionalData holds latency and HDMI video fields which follow max TMDS clock, they are kept verbatim.
+	AdditionalData []byte `json:"additionalData,omitempty" validate:"max=24"`
+}
+
+func (specification *CeaHdmiVendorSpecificSpecification) Validate() error {
+	if specification == nil {
+		return fmt.Errorf("nil hdmi vendor specific data block")
+	}
+
+	specificationValidator := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := specificationValidator.Struct(specification); err != nil {
+		return err
+	}
+
+	if specification.MaxTmdsClock%ceaHdmiTmdsClockStep != 0 {
+		return fmt.Errorf("max tmds clock must be a multiple of %d: %d", ceaHdmiTmdsClockStep, specification.MaxTmdsClock)
+	}
+
+	if _, err := encodeCeaPhysicalAddress(specification.PhysicalAddress); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func isCeaHdmiVendorSpecificDataBlock(payload []byte) bool {
+	return len(payload) >= len(ceaHdmiOui) && bytes.Equal(payload[:len(ceaHdmiOui)], ceaHdmiOui)
+}
+
+func decodeCeaHdmiVendorSpecificDataBlock(payload []byte) (*CeaHdmiVendorSpecificSpecification, error) {
+	if len(payload) < ceaHdmiVendorSpecificMinLength {
+		return nil, fmt.Errorf("payload too short: %d", len(payload))
+	}
+
+	specification := &CeaHdmiVendorSpecificSpecification{
+		PhysicalAddress: decodeCeaPhysicalAddress(payload[3], payload[4]),
+	}
+
+	if len(payload) > 5 {
+		flags := payload[5]
+
+		if flags&ceaHdmiFlagReserved != 0 {
+			return nil, fmt.Errorf("reserved flag bits set")
+		}
+
+		specification.SupportsAi = flags&ceaHdmiFlagSupportsAi != 0
+		specification.DeepColor48 = flags&ceaHdmiFlagDeepColor48 != 0
+		specification.DeepColor36 = flags&ceaHdmiFlagDeepColor36 != 0
+		specification.DeepColor30 = flags&ceaHdmiFlagDeepColor30 != 0
+		specification.DeepColorYCbCr444 = flags&ceaHdmiFlagDeepColorYCbCr444 != 0
+		specification.DualDvi = flags&ceaHdmiFlagDualDvi != 0
+	}
+
+	if len(payload) > 6 {
+		specification.MaxTmdsClock = uint16(payload[6]) * ceaHdmiTmdsClockStep
+	}
+
+	if len(payload) > 7 {
+		specification.AdditionalData = append([]byte{}, payload[7:]...)
+	}
+
+	return specification, nil
+}
+
+func encodeCeaHdmiVendorSpecificDataBlock(specification CeaHdmiVendorSpecificSpecification) ([]byte, error) {
+	physicalAddress, err := encodeCeaPhysicalAddress(specification.PhysicalAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := append(append([]byte{}, ceaHdmiOui...), physicalAddress[:]...)
+
+	var flags byte
+	if specification.SupportsAi {
+		flags |= ceaHdmiFlagSupportsAi
+	}
+	if specification.DeepColor48 {
+		flags |= ceaHdmiFlagDeepColor48
+	}
+	if specification.DeepColor36 {
+		flags |= ceaHdmiFlagDeepColor36
+	}
+	if specification.DeepColor30 {
+		flags |= ceaHdmiFlagDeepColor30
+	}
+	if specification.DeepColorYCbCr444 {
+		flags |= ceaHdmiFlagDeepColorYCbCr444
+	}
+	if specification.DualDvi {
+		flags |= ceaHdmiFlagDualDvi
+	}
+
+	// optional fields are present up to the last declared one
+	hasMaxTmdsClock := specification.MaxTmdsClock != 0 || len(specification.AdditionalData) > 0
+
+	if flags != 0 || hasMaxTmdsClock {
+		payload = append(payload, flags)
+	}
+
+	if hasMaxTmdsClock {
+		payload = append(payload, byte(specification.MaxTmdsClock/ceaHdmiTmdsClockStep))
+	}
+
+	return append(payload, specification.AdditionalData...), nil
+}
+
+func decodeCeaPhysicalAddress(high byte, low byte) string {
+	return fmt.Sprintf("%x.%x.%x.%x", high>>4, high&0x0F, low>>4, low&0x0F)
+}
+
+func encodeCeaPhysicalAddress(value string) ([2]byte, error) {
+	parts := strings.Split(value, ".")
+	if len(parts) != 4 {
+		return [2]byte{}, fmt.Errorf("invalid physical address: %q", value)
+	}
+
+	var nibbles [4]byte
+	for index, part := range parts {
+		nibble, err := strconv.ParseUint(part, 16, 4)
+		if err != nil || len(part) != 1 {
+			return [2]byte{}, fmt.Errorf("invalid physical address: %q", value)
+		}
+
+		nibbles[index] = byte(nibble)
+	}
+
+	return [2]byte{nibbles[0]<<4 | nibbles[1], nibbles[2]<<4 | nibbles[3]}, nil
+}
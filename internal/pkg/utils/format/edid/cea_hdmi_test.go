@@ -0,0 +1,81 @@
+package edid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCeaHdmiVendorSpecificDataBlockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		payload       []byte
+		specification CeaHdmiVendorSpecificSpecification
+	}{
+		{
+			name:          "physical address only",
+			payload:       []byte{0x03, 0x0C, 0x00, 0x21, 0x00},
+			specification: CeaHdmiVendorSpecificSpecification{PhysicalAddress: "2.1.0.0"},
+		},
+		{
+			name:    "flags",
+			payload: []byte{0x03, 0x0C, 0x00, 0x10, 0x00, 0xC1},
+			specification: CeaHdmiVendorSpecificSpecification{
+				PhysicalAddress: "1.0.0.0",
+				SupportsAi:      true,
+				DeepColor48:     true,
+				DualDvi:         true,
+			},
+		},
+		{
+			name:    "latency",
+			payload: []byte{0x03, 0x0C, 0x00, 0x10, 0x00, 0x00, 0x3C, 0x20, 0x00, 0x80},
+			specification: CeaHdmiVendorSpecificSpecification{
+				PhysicalAddress: "1.0.0.0",
+				MaxTmdsClock:    300,
+				AdditionalData:  []byte{0x20, 0x00, 0x80},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.True(t, isCeaHdmiVendorSpecificDataBlock(testCase.payload))
+
+			decoded, err := decodeCeaHdmiVendorSpecificDataBlock(testCase.payload)
+			require.NoError(t, err)
+			assert.Equal(t, &testCase.specification, decoded)
+
+			payload, err := encodeCeaHdmiVendorSpecificDataBlock(testCase.specification)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.payload, payload)
+		})
+	}
+}
+
+func TestDecodeCeaHdmiVendorSpecificDataBlockErrors(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, isCeaHdmiVendorSpecificDataBlock([]byte{0xD8, 0x5D, 0xC4, 0x01}))
+
+	_, err := decodeCeaHdmiVendorSpecificDataBlock([]byte{0x03, 0x0C, 0x00, 0x10})
+	assert.ErrorContains(t, err, "payload too short")
+
+	_, err = decodeCeaHdmiVendorSpecificDataBlock([]byte{0x03, 0x0C, 0x00, 0x10, 0x00, 0x02})
+	assert.ErrorContains(t, err, "reserved flag bits set")
+}
+
+func TestCeaHdmiVendorSpecificSpecificationValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&CeaHdmiVendorSpecificSpecification{PhysicalAddress: "a.b.c.d", MaxTmdsClock: 340}).Validate())
+	assert.ErrorContains(t, (&CeaHdmiVendorSpecificSpecification{PhysicalAddress: "1.0.0"}).Validate(), "invalid physical address")
+	assert.ErrorContains(t, (&CeaHdmiVendorSpecificSpecification{PhysicalAddress: "1.0.0.10"}).Validate(), "invalid physical address")
+	assert.ErrorContains(t, (&CeaHdmiVendorSpecificSpecification{PhysicalAddress: "1.0.0.0", MaxTmdsClock: 301}).Validate(), "multiple of 5")
+	assert.ErrorContains(t, (&CeaHdmiVendorSpecificSpecification{PhysicalAddress: "1.0.0.0", MaxTmdsClock: 1280}).Validate(), "MaxTmdsClock")
+}
@@ -0,0 +1,242 @@
+package edid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hdmiCeaExtensionBlock returns extension block laid out like the one of an HDMI monitor with HDR support.
+func hdmiCeaExtensionBlock(t *testing.T) CeaExtensionBlock {
+	t.Helper()
+
+	dataBlocks := []byte{
+		0x44, 0x90, 0x04, 0x1F, 0x5F, // video: native VIC 16, VICs 4, 31 and 95
+		0x23, 0x09, 0x07, 0x07, // audio: 2 channel LPCM, 32-48 kHz, 16-24 bit
+		0x83, 0x01, 0x00, 0x00, // speaker allocation: front left and right
+		0x67, 0x03, 0x0C, 0x00, 0x10, 0x00, 0x38, 0x3C, // hdmi: 1.0.0.0, deep color, 300 MHz
+		0xE3, 0x05, 0xC0, 0x00, // colorimetry: BT.2020 YCC and RGB
+		0xE6, 0x06, 0x05, 0x01, 0x78, 0x50, 0x00, // hdr static metadata: SDR and ST 2084
+		0xE2, 0x00, 0x0F, // video capability, kept as other data block
+	}
+
+	descriptor := knownTimingDescriptor(t, knownTimings[4].displayMode)
+	detailedTiming, err := encodeStandardTimingDescriptor(descriptor)
+	require.NoError(t, err)
+
+	block := CeaExtensionBlock{ceaExtensionTag, ceaExtensionRevision, byte(ceaHeaderLength + len(dataBlocks)), 0xF1}
+	copy(block[ceaHeaderLength:], dataBlocks)
+	copy(block[ceaHeaderLength+len(dataBlocks):], detailedTiming[:])
+	block[ceaChecksumIndex] = calculateChecksumByte(block[:ceaChecksumIndex])
+
+	return block
+}
+
+func TestCreateCeaExtensionSpecificationFromBlock(t *testing.T) {
+	t.Parallel()
+
+	block := hdmiCeaExtensionBlock(t)
+
+	specification, err := CreateCeaExtensionSpecificationFromBlock(block)
+	require.NoError(t, err)
+
+	assert.True(t, specification.Underscan)
+	assert.True(t, specification.BasicAudio)
+	assert.True(t, specification.YCbCr444)
+	assert.True(t, specification.YCbCr422)
+	assert.Equal(t, uint8(1), specification.NativeDetailedTimingCount)
+
+	assert.Equal(t, []CeaShortVideoDescriptorSpecification{
+		{Vic: 16, Native: true},
+		{Vic: 4},
+		{Vic: 31},
+		{Vic: 95},
+	}, specification.VideoDescriptors)
+
+	assert.Equal(t, []CeaShortAudioDescriptorSpecification{{
+		Format:      CeaAudioFormatLpcm,
+		MaxChannels: 2,
+		SampleRates: []uint32{32000, 44100, 48000},
+		BitDepths:   []uint8{16, 20, 24},
+	}}, specification.AudioDescriptors)
+
+	assert.Equal(t, &CeaSpeakerAllocationSpecification{FrontLeftRight: true}, specification.SpeakerAllocation)
+
+	assert.Equal(t, &CeaHdmiVendorSpecificSpecification{
+		PhysicalAddress:   "1.0.0.0",
+		DeepColor36:       true,
+		DeepColor30:       true,
+		DeepColorYCbCr444: true,
+		MaxTmdsClock:      300,
+	}, specification.HdmiVendorSpecific)
+
+	assert.Equal(t, &CeaColorimetrySpecification{Bt2020Ycc: true, Bt2020Rgb: true}, specification.Colorimetry)
+
+	if assert.NotNil(t, specification.HdrStaticMetadata) {
+		assert.True(t, specification.HdrStaticMetadata.TraditionalGammaSdr)
+		assert.True(t, specification.HdrStaticMetadata.Smpte2084)
+		assert.True(t, specification.HdrStaticMetadata.StaticMetadataType1)
+		assert.Equal(t, uint8(0x78), *specification.HdrStaticMetadata.MaxLuminance)
+		assert.Equal(t, uint8(0x50), *specification.HdrStaticMetadata.MaxFrameAverageLuminance)
+		assert.Equal(t, uint8(0x00), *specification.HdrStaticMetadata.MinLuminance)
+	}
+
+	extendedTag := uint8(0)
+	assert.Equal(t, []CeaDataBlockSpecification{{Tag: 7, ExtendedTag: &extendedTag, Payload: []byte{0x0F}}}, specification.OtherDataBlocks)
+
+	if assert.Len(t, specification.DetailedTimings, 1) {
+		assert.Equal(t, uint16(1280), specification.DetailedTimings[0].Standard.HorizontalActive)
+	}
+
+	encoded, err := CreateCeaExtensionBlockFromSpecification(*specification)
+	require.NoError(t, err)
+	assert.Equal(t, block, *encoded)
+}
+
+func TestCreateCeaExtensionSpecificationFromBlockEmpty(t *testing.T) {
+	t.Parallel()
+
+	block := CeaExtensionBlock{ceaExtensionTag, ceaExtensionRevision, 0, 0}
+	block[ceaChecksumIndex] = calculateChecksumByte(block[:ceaChecksumIndex])
+
+	specification, err := CreateCeaExtensionSpecificationFromBlock(block)
+	require.NoError(t, err)
+	assert.Equal(t, &CeaExtensionSpecification{}, specification)
+
+	encoded, err := CreateCeaExtensionBlockFromSpecification(*specification)
+	require.NoError(t, err)
+	assert.Equal(t, block, *encoded)
+}
+
+func TestCreateCeaExtensionSpecificationFromBlockEarlyRevisions(t *testing.T) {
+	t.Parallel()
+
+	descriptor := knownTimingDescriptor(t, knownTimings[4].displayMode)
+	detailedTiming, err := encodeStandardTimingDescriptor(descriptor)
+	require.NoError(t, err)
+
+	for _, revision := range []byte{1, 2} {
+		// bytes before detailed timings are reserved in early revisions, they must not be read as data blocks
+		block := CeaExtensionBlock{ceaExtensionTag, revision, 8, 0xF1, 0x44, 0x90, 0x04, 0x1F}
+		copy(block[8:], detailedTiming[:])
+		block[ceaChecksumIndex] = calculateChecksumByte(block[:ceaChecksumIndex])
+
+		specification, err := CreateCeaExtensionSpecificationFromBlock(block)
+		require.NoError(t, err)
+
+		assert.Empty(t, specification.VideoDescriptors)
+		assert.Equal(t, revision == 2, specification.Underscan)
+		assert.Equal(t, revision == 2, specification.YCbCr422)
+
+		if assert.Len(t, specification.DetailedTimings, 1) {
+			assert.Equal(t, uint16(1280), specification.DetailedTimings[0].Standard.HorizontalActive)
+		}
+	}
+}
+
+func TestCreateCeaExtensionSpecificationFromBlockErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		modify func(block *CeaExtensionBlock)
+		errMsg string
+	}{
+		{
+			name:   "checksum",
+			modify: func(block *CeaExtensionBlock) { block[ceaChecksumIndex]++ },
+			errMsg: "checksum",
+		},
+		{
+			name:   "tag",
+			modify: func(block *CeaExtensionBlock) { block[0] = 0xF0 },
+			errMsg: "not a cea extension block",
+		},
+		{
+			name:   "revision",
+			modify: func(block *CeaExtensionBlock) { block[1] = 4 },
+			errMsg: "unsupported cea extension revision",
+		},
+		{
+			name:   "detailed timings offset",
+			modify: func(block *CeaExtensionBlock) { block[2] = 2 },
+			errMsg: "invalid detailed timings offset",
+		},
+		{
+			name:   "data block length",
+			modify: func(block *CeaExtensionBlock) { block[ceaHeaderLength+32] = 0xE5 },
+			errMsg: "exceeds data block collection",
+		},
+		{
+			name:   "reserved vic",
+			modify: func(block *CeaExtensionBlock) { block[ceaHeaderLength+1] = 0 },
+			errMsg: "reserved value",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			block := hdmiCeaExtensionBlock(t)
+			testCase.modify(&block)
+
+			if testCase.name != "checksum" {
+				block[ceaChecksumIndex] = calculateChecksumByte(block[:ceaChecksumIndex])
+			}
+
+			_, err := CreateCeaExtensionSpecificationFromBlock(block)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), testCase.errMsg)
+			}
+		})
+	}
+}
+
+func TestCreateCeaExtensionBlockFromSpecificationSplitsDataBlocks(t *testing.T) {
+	t.Parallel()
+
+	specification := CeaExtensionSpecification{}
+	for vic := uint8(1); vic <= 40; vic++ {
+		specification.VideoDescriptors = append(specification.VideoDescriptors, CeaShortVideoDescriptorSpecification{Vic: vic})
+	}
+
+	block, err := CreateCeaExtensionBlockFromSpecification(specification)
+	require.NoError(t, err)
+
+	assert.Equal(t, byte(ceaDataBlockTagVideo<<5|31), block[ceaHeaderLength])
+	assert.Equal(t, byte(ceaDataBlockTagVideo<<5|9), block[ceaHeaderLength+32])
+
+	decoded, err := CreateCeaExtensionSpecificationFromBlock(*block)
+	require.NoError(t, err)
+	assert.Equal(t, specification.VideoDescriptors, decoded.VideoDescriptors)
+}
+
+func TestCreateCeaExtensionBlockFromSpecificationTooLong(t *testing.T) {
+	t.Parallel()
+
+	descriptor := knownTimingDescriptor(t, knownTimings[0].displayMode)
+
+	specification := CeaExtensionSpecification{}
+	for range 7 {
+		specification.DetailedTimings = append(specification.DetailedTimings, DetailedTimingsEntrySpecification{Standard: descriptor})
+	}
+
+	_, err := CreateCeaExtensionBlockFromSpecification(specification)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "exceed block length")
+	}
+}
+
+func TestCeaDataBlockSpecificationValidate(t *testing.T) {
+	t.Parallel()
+
+	extendedTag := uint8(13)
+
+	assert.NoError(t, (&CeaDataBlockSpecification{Tag: 7, ExtendedTag: &extendedTag}).Validate())
+	assert.EqualError(t, (&CeaDataBlockSpecification{Tag: 7}).Validate(), "extended tag missing")
+	assert.EqualError(t, (&CeaDataBlockSpecification{Tag: 3, ExtendedTag: &extendedTag}).Validate(), "extended tag set for tag 3")
+	assert.EqualError(t, (&CeaDataBlockSpecification{Tag: 7, ExtendedTag: &extendedTag, Payload: make([]byte, 31)}).Validate(), "payload too long: 31")
+	assert.Error(t, (&CeaDataBlockSpecification{Tag: 0}).Validate())
+}
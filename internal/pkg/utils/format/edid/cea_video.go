@@ -0,0 +1,74 @@
+package edid
+
+import (
+	"fmt"
+)
+
+const (
+	ceaVideoDataBlockMaxDescriptors = ceaDataBlockMaxPayloadLength
+
+	ceaShortVideoDescriptorNativeFlag   = 0b10000000
+	ceaShortVideoDescriptorMaxNativeVic = 64
+)
+
+// CeaShortVideoDescriptorSpecification declares display mode by CTA-861 video identification code (VIC).
+type CeaShortVideoDescriptorSpecification struct {
+	Vic uint8 `json:"vic"`
+	// Native marks native display mode, only VICs 1 to 64 can be marked.
+	Native bool `json:"native,omitempty"`
+}
+
+func (specification *CeaShortVideoDescriptorSpecification) Validate() error {
+	if specification == nil {
+		return fmt.Errorf("nil video descriptor")
+	}
+
+	if specification.Vic == 0 || specification.Vic == 128 || specification.Vic > 253 {
+		return fmt.Errorf("reserved vic: %d", specification.Vic)
+	}
+
+	if specification.Native && specification.Vic > ceaShortVideoDescriptorMaxNativeVic {
+		return fmt.Errorf("vic %d cannot be marked native", specification.Vic)
+	}
+
+	return nil
+}
+
+func decodeCeaVideoDataBlock(payload []byte) ([]CeaShortVideoDescriptorSpecification, error) {
+	descriptors := make([]CeaShortVideoDescriptorSpecification, 0, len(payload))
+
+	for index, value := range payload {
+		switch {
+		case value == 0 || value == 128 || value > 253:
+			return nil, fmt.Errorf("descriptor %d: reserved value: %d", index, value)
+		case value > 128 && value-ceaShortVideoDescriptorNativeFlag <= ceaShortVideoDescriptorMaxNativeVic:
+			descriptors = append(descriptors, CeaShortVideoDescriptorSpecification{
+				Vic:    value &^ ceaShortVideoDescriptorNativeFlag,
+				Native: true,
+			})
+		default:
+			descriptors = append(descriptors, CeaShortVideoDescriptorSpecification{Vic: value})
+		}
+	}
+
+	return descriptors, nil
+}
+
+func encodeCeaVideoDataBlock(descriptors []CeaShortVideoDescriptorSpecification) ([]byte, error) {
+	payload := make([]byte, 0, len(descriptors))
+
+	for index, descriptor := range descriptors {
+		if err := descriptor.Validate(); err != nil {
+			return nil, fmt.Errorf("descriptor %d: %w", index, err)
+		}
+
+		value := descriptor.Vic
+		if descriptor.Native {
+			value |= ceaShortVideoDescriptorNativeFlag
+		}
+
+		payload = append(payload, value)
+	}
+
+	return payload, nil
+}
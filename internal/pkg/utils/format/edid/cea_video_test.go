@@ -0,0 +1,50 @@
+package edid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCeaVideoDataBlockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte{0x90, 0x04, 0x40, 0xC0, 0x41, 0x61, 0xC1, 0xFD}
+
+	descriptors, err := decodeCeaVideoDataBlock(payload)
+	require.NoError(t, err)
+
+	assert.Equal(t, []CeaShortVideoDescriptorSpecification{
+		{Vic: 16, Native: true},
+		{Vic: 4},
+		{Vic: 64},
+		{Vic: 64, Native: true},
+		{Vic: 65},
+		{Vic: 97},
+		{Vic: 193},
+		{Vic: 253},
+	}, descriptors)
+
+	encoded, err := encodeCeaVideoDataBlock(descriptors)
+	require.NoError(t, err)
+	assert.Equal(t, payload, encoded)
+}
+
+func TestDecodeCeaVideoDataBlockReservedValues(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []byte{0x00, 0x80, 0xFE, 0xFF} {
+		_, err := decodeCeaVideoDataBlock([]byte{0x10, value})
+		assert.ErrorContains(t, err, "descriptor 1: reserved value")
+	}
+}
+
+func TestCeaShortVideoDescriptorSpecificationValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&CeaShortVideoDescriptorSpecification{Vic: 64, Native: true}).Validate())
+	assert.EqualError(t, (&CeaShortVideoDescriptorSpecification{Vic: 65, Native: true}).Validate(), "vic 65 cannot be marked native")
+	assert.EqualError(t, (&CeaShortVideoDescriptorSpecification{Vic: 128}).Validate(), "reserved vic: 128")
+	assert.EqualError(t, (&CeaShortVideoDescriptorSpecification{}).Validate(), "reserved vic: 0")
+}
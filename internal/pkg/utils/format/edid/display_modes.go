@@ -0,0 +1,304 @@
+package edid
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const maxStandardTimingEntries = 8
+
+type establishedTiming struct {
+	displayMode peripheralSDK.DisplayMode
+	flag        func(specification *EstablishedTimingsSpecification) *bool
+}
+
+// establishedTimings lists progressive established timings, interlaced 1024x768 cannot be expressed as display mode.
+var establishedTimings = []establishedTiming{
+	{peripheralSDK.DisplayMode{Width: 720, Height: 400, RefreshRate: 70}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports720x400x70 }},
+	{peripheralSDK.DisplayMode{Width: 720, Height: 400, RefreshRate: 88}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports720x400x88 }},
+	{peripheralSDK.DisplayMode{Width: 640, Height: 480, RefreshRate: 60}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports640x480x60 }},
+	{peripheralSDK.DisplayMode{Width: 640, Height: 480, RefreshRate: 67}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports640x480x67 }},
+	{peripheralSDK.DisplayMode{Width: 640, Height: 480, RefreshRate: 72}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports640x480x72 }},
+	{peripheralSDK.DisplayMode{Width: 640, Height: 480, RefreshRate: 75}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports640x480x75 }},
+	{peripheralSDK.DisplayMode{Width: 800, Height: 600, RefreshRate: 56}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports800x600x56 }},
+	{peripheralSDK.DisplayMode{Width: 800, Height: 600, RefreshRate: 60}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports800x600x60 }},
+	{peripheralSDK.DisplayMode{Width: 800, Height: 600, RefreshRate: 72}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports800x600x72 }},
+	{peripheralSDK.DisplayMode{Width: 800, Height: 600, RefreshRate: 75}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports800x600x75 }},
+	{peripheralSDK.DisplayMode{Width: 832, Height: 624, RefreshRate: 75}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports832x624x75 }},
+	{peripheralSDK.DisplayMode{Width: 1024, Height: 768, RefreshRate: 60}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports1024x768x60 }},
+	{peripheralSDK.DisplayMode{Width: 1024, Height: 768, RefreshRate: 70}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports1024x768x70 }},
+	{peripheralSDK.DisplayMode{Width: 1024, Height: 768, RefreshRate: 75}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports1024x768x75 }},
+	{peripheralSDK.DisplayMode{Width: 1280, Height: 1024, RefreshRate: 75}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports1280x1024x75 }},
+	{peripheralSDK.DisplayMode{Width: 1152, Height: 870, RefreshRate: 75}, func(specification *EstablishedTimingsSpecification) *bool { return &specification.Supports1152x870x75 }},
+}
+
+var standardTimingAspectRatios = []StandardTimingEntryAspectRatio{
+	StandardTimingEntryAspectRatio16x9,
+	StandardTimingEntryAspectRatio16x10,
+	StandardTimingEntryAspectRatio4x3,
+	StandardTimingEntryAspectRatio5x4,
+}
+
+// DisplayModes returns display modes declared by detailed, standard and established timings, including detailed
+// timings of CEA-861 extension. Preferred display mode is the first one when specification declares it in detailed
+// timings.
+func (specification *Specification) DisplayModes() peripheralSDK.DisplayModeList {
+	displayModes := peripheralSDK.DisplayModeList{}
+
+	appendDisplayMode := func(displayMode peripheralSDK.DisplayMode) {
+		if displayMode.Valid() != nil || displayModes.Supports(displayMode) {
+			return
+		}
+
+		displayModes = append(displayModes, displayMode)
+	}
+
+	detailedTimings := specification.Timings.Detailed.Entries
+	if specification.CeaExtension != nil {
+		detailedTimings = append(slices.Clone(detailedTimings), specification.CeaExtension.DetailedTimings...)
+	}
+
+	for _, entry := range detailedTimings {
+		if entry.Standard == nil || entry.Standard.Interlaced {
+			continue
+		}
+
+		appendDisplayMode(detailedTimingDisplayMode(*entry.Standard))
+	}
+
+	for _, entry := range specification.Timings.Standard.Entries {
+		appendDisplayMode(peripheralSDK.DisplayMode{
+			Width:       uint32(entry.Width),
+			Height:      uint32(entry.Height),
+			RefreshRate: uint32(entry.RefreshRate),
+		})
+	}
+
+	for _, timing := range establishedTimings {
+		if *timing.flag(&specification.Timings.Established) {
+			appendDisplayMode(timing.displayMode)
+		}
+	}
+
+	return displayModes
+}
+
+// WithDisplayModes returns copy of the specification declaring the display modes instead of its own timings. The first
+// display mode becomes the preferred one, display modes fill free detailed timing descriptors with DMT or CTA-861
+// timings, or with CVT reduced blanking timings when they are not defined there. Remaining modes are declared by
+// established and standard timings. Monitor name and serial descriptors are kept, range limits are dropped as they may
+// contradict declared modes. CEA-861 extension keeps its data blocks, e.g. HDMI vendor specific and audio ones, while
+// its video data blocks and detailed timings are replaced by display modes which do not fit the base block. Other
+// extension blocks are dropped. Display modes which cannot be declared are returned as skipped.
+func (specification Specification) WithDisplayModes(displayModes peripheralSDK.DisplayModeList) (*Specification, peripheralSDK.DisplayModeList, error) {
+	monitorDescriptors := []DetailedTimingsEntrySpecification{}
+	for _, entry := range specification.Timings.Detailed.Entries {
+		if entry.MonitorName != nil || entry.MonitorSerial != nil {
+			monitorDescriptors = append(monitorDescriptors, entry)
+		}
+	}
+
+	// at least one descriptor is reserved for preferred timing
+	if len(monitorDescriptors) > detailedTimingDescriptorCount-1 {
+		monitorDescriptors = monitorDescriptors[:detailedTimingDescriptorCount-1]
+	}
+
+	var ceaExtension *CeaExtensionSpecification
+	extensionDetailedTimingSlots := 0
+	if specification.CeaExtension != nil {
+		ceaExtension = specification.CeaExtension.withoutDisplayModes()
+		extensionDetailedTimingSlots = ceaExtension.freeDetailedTimingSlots()
+	}
+
+	declaration := specification.declareDisplayModes(displayModes, detailedTimingDescriptorCount-len(monitorDescriptors), extensionDetailedTimingSlots, CvtFormulaReducedBlanking)
+
+	if len(declaration.timings.Detailed.Entries) == 0 {
+		return nil, declaration.skippedModes, fmt.Errorf("%w: %s", ErrPreferredTimingUnknown, displayModes)
+	}
+
+	declaration.timings.Detailed.Entries = append(declaration.timings.Detailed.Entries, monitorDescriptors...)
+
+	specification.Timings = declaration.timings
+	specification.ExtensionBlockCount = 0
+	specification.CeaExtension = nil
+
+	if ceaExtension != nil {
+		ceaExtension.DetailedTimings = declaration.extensionDetailedTimings
+		specification.ExtensionBlockCount = 1
+		specification.CeaExtension = ceaExtension
+	}
+
+	if err := specification.Validate(); err != nil {
+		return nil, declaration.skippedModes, err
+	}
+
+	return &specification, declaration.skippedModes, nil
+}
+
+// displayModesDeclaration holds timings declaring display modes, detailed timings which do not fit into the base block
+// are declared by extension.
+type displayModesDeclaration struct {
+	timings                  TimingsSpecification
+	extensionDetailedTimings []DetailedTimingsEntrySpecification
+
+	declaredModes   peripheralSDK.DisplayModeList
+	declaredTimings []detailedTiming
+	skippedModes    peripheralSDK.DisplayModeList
+}
+
+// declareDisplayModes declares each display mode by detailed timing while free descriptors are left in the base
+// block, then by established or standard timing, finally by detailed timing of extension. Detailed timings are known
+// DMT or CTA-861 timings, or they are computed by the CVT formula.
+func (specification *Specification) declareDisplayModes(displayModes peripheralSDK.DisplayModeList, detailedTimingSlots int, extensionDetailedTimingSlots int, formula CvtFormula) displayModesDeclaration {
+	declaration := displayModesDeclaration{
+		declaredModes: peripheralSDK.DisplayModeList{},
+		skippedModes:  peripheralSDK.DisplayModeList{},
+	}
+
+	timings := &declaration.timings
+
+	for _, displayMode := range displayModes {
+		if displayMode.Valid() != nil {
+			declaration.skippedModes = append(declaration.skippedModes, displayMode)
+			continue
+		}
+
+		if declaration.declaredModes.Supports(displayMode) {
+			continue
+		}
+
+		timing, descriptor, hasDescriptor := specification.createDisplayModeDescriptor(displayMode, formula)
+
+		declared := false
+
+		if hasDescriptor && len(timings.Detailed.Entries) < detailedTimingSlots {
+			timings.Detailed.Entries = append(timings.Detailed.Entries, DetailedTimingsEntrySpecification{Standard: descriptor})
+			declared = true
+		}
+
+		if flag := findEstablishedTimingFlag(&timings.Established, displayMode); flag != nil {
+			*flag = true
+			declared = true
+		}
+
+		if !declared && len(timings.Standard.Entries) < maxStandardTimingEntries {
+			if entry, ok := createStandardTimingEntry(displayMode); ok {
+				timings.Standard.Entries = append(timings.Standard.Entries, entry)
+				declared = true
+			}
+		}
+
+		if !declared && hasDescriptor && len(declaration.extensionDetailedTimings) < extensionDetailedTimingSlots {
+			declaration.extensionDetailedTimings = append(declaration.extensionDetailedTimings, DetailedTimingsEntrySpecification{Standard: descriptor})
+			declared = true
+		}
+
+		if !declared {
+			declaration.skippedModes = append(declaration.skippedModes, displayMode)
+			continue
+		}
+
+		declaration.declaredModes = append(declaration.declaredModes, displayMode)
+		if hasDescriptor {
+			declaration.declaredTimings = append(declaration.declaredTimings, timing)
+		}
+	}
+
+	return declaration
+}
+
+// createDisplayModeDescriptor returns detailed timing of the display mode, known one or computed by the CVT formula,
+// and its descriptor. False is returned when the timing cannot be expressed by detailed timing descriptor.
+func (specification *Specification) createDisplayModeDescriptor(displayMode peripheralSDK.DisplayMode, formula CvtFormula) (detailedTiming, *DetailedTimingsStandardDescriptorEntry, bool) {
+	timing, ok := findKnownTiming(displayMode)
+	if !ok {
+		cvtTiming, err := computeCvtTiming(displayMode, formula)
+		if err != nil {
+			return detailedTiming{}, nil, false
+		}
+
+		timing = cvtTiming
+	}
+
+	horizontalImageSize, verticalImageSize := specification.imageSize(displayMode)
+
+	descriptor := timing.toDescriptor(horizontalImageSize, verticalImageSize)
+	if descriptor.Validate() != nil {
+		return detailedTiming{}, nil, false
+	}
+
+	return timing, descriptor, true
+}
+
+// imageSize returns image size in millimeters, derived from display size or from 96 DPI when display size is unknown.
+func (specification *Specification) imageSize(displayMode peripheralSDK.DisplayMode) (uint16, uint16) {
+	size := specification.Display.Size
+	if size.Width != nil && size.Height != nil {
+		return uint16(*size.Width * 10), uint16(*size.Height * 10)
+	}
+
+	return uint16(displayMode.Width * 254 / 960), uint16(displayMode.Height * 254 / 960)
+}
+
+func detailedTimingDisplayMode(entry DetailedTimingsStandardDescriptorEntry) peripheralSDK.DisplayMode {
+	horizontalTotal := uint64(entry.HorizontalActive) + uint64(entry.HorizontalBlank)
+	verticalTotal := uint64(entry.VerticalActive) + uint64(entry.VerticalBlank)
+
+	var refreshRate uint64
+	if horizontalTotal > 0 && verticalTotal > 0 {
+		pixelClock := uint64(entry.PixelClock) * 1000
+		frameLength := horizontalTotal * verticalTotal
+
+		refreshRate = (pixelClock + frameLength/2) / frameLength
+	}
+
+	return peripheralSDK.DisplayMode{
+		Width:       uint32(entry.HorizontalActive),
+		Height:      uint32(entry.VerticalActive),
+		RefreshRate: uint32(refreshRate),
+	}
+}
+
+func findEstablishedTimingFlag(specification *EstablishedTimingsSpecification, displayMode peripheralSDK.DisplayMode) *bool {
+	for _, timing := range establishedTimings {
+		if timing.displayMode == displayMode {
+			return timing.flag(specification)
+		}
+	}
+
+	return nil
+}
+
+func createStandardTimingEntry(displayMode peripheralSDK.DisplayMode) (StandardTimingEntrySpecification, bool) {
+	// standard timing encodes width in 8 pixel steps
+	if displayMode.Width%8 != 0 {
+		return StandardTimingEntrySpecification{}, false
+	}
+
+	for _, aspectRatio := range standardTimingAspectRatios {
+		height, err := deriveHeight(int(displayMode.Width), aspectRatio)
+		if err != nil || height != int(displayMode.Height) {
+			continue
+		}
+
+		entry := StandardTimingEntrySpecification{
+			Width:       int(displayMode.Width),
+			Height:      height,
+			RefreshRate: int(displayMode.RefreshRate),
+			AspectRatio: aspectRatio,
+		}
+
+		if entry.Validate() != nil {
+			return StandardTimingEntrySpecification{}, false
+		}
+
+		return entry, true
+	}
+
+	return StandardTimingEntrySpecification{}, false
+}
+
+var ErrPreferredTimingUnknown = errors.New("no display mode can be declared by detailed timing")
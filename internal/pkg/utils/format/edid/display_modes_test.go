@@ -0,0 +1,181 @@
+package edid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func TestSpecificationDisplayModes(t *testing.T) {
+	t.Parallel()
+
+	specification := validSpecification()
+	specification.Timings = TimingsSpecification{
+		Established: EstablishedTimingsSpecification{
+			Supports640x480x60:   true,
+			Supports1024x768x87i: true,
+		},
+		Standard: StandardTimingsSpecification{
+			Entries: []StandardTimingEntrySpecification{
+				{Width: 1280, Height: 1024, RefreshRate: 60, AspectRatio: StandardTimingEntryAspectRatio5x4},
+				{Width: 1920, Height: 1080, RefreshRate: 60, AspectRatio: StandardTimingEntryAspectRatio16x9},
+			},
+		},
+		Detailed: DetailedTimingsSpecification{
+			Entries: []DetailedTimingsEntrySpecification{
+				{Standard: knownTimingDescriptor(t, peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 60})},
+				{MonitorName: &DetailedTimingsMonitorNameDescriptorEntry{Name: "Modes"}},
+			},
+		},
+	}
+
+	assert.Equal(t, peripheralSDK.DisplayModeList{
+		{Width: 1920, Height: 1080, RefreshRate: 60},
+		{Width: 1280, Height: 1024, RefreshRate: 60},
+		{Width: 640, Height: 480, RefreshRate: 60},
+	}, specification.DisplayModes())
+}
+
+func TestSpecificationWithDisplayModes(t *testing.T) {
+	t.Parallel()
+
+	specification := validSpecification()
+	specification.ExtensionBlockCount = 1
+	specification.Timings.Detailed.Entries = []DetailedTimingsEntrySpecification{
+		{RangeLimits: &DetailedTimingsRangeLimitsDescriptorEntry{MinVerticalHz: 50, MaxVerticalHz: 75, MinKHz: 30, MaxKHz: 80, MaxClockMHz: 170}},
+		{MonitorName: &DetailedTimingsMonitorNameDescriptorEntry{Name: "Passthrough"}},
+	}
+
+	displayModes := peripheralSDK.DisplayModeList{
+		{Width: 2560, Height: 1440, RefreshRate: 60},
+		{Width: 1920, Height: 1080, RefreshRate: 60},
+		{Width: 1920, Height: 1080, RefreshRate: 60},
+		{Width: 1280, Height: 720, RefreshRate: 60},
+		{Width: 1024, Height: 768, RefreshRate: 75},
+		{Width: 1600, Height: 1200, RefreshRate: 85},
+		{Width: 1366, Height: 768, RefreshRate: 75},
+	}
+
+	declared, skipped, err := specification.WithDisplayModes(displayModes)
+	require.NoError(t, err)
+
+	assert.Equal(t, peripheralSDK.DisplayModeList{{Width: 1366, Height: 768, RefreshRate: 75}}, skipped)
+	assert.Equal(t, uint8(0), declared.ExtensionBlockCount)
+	assert.Equal(t, "Passthrough", declared.Timings.Detailed.Entries[3].MonitorName.Name)
+	assert.Equal(t, uint16(400), declared.Timings.Detailed.Entries[0].Standard.HorizontalImageSize)
+	assert.True(t, declared.Timings.Established.Supports1024x768x75)
+
+	assert.Equal(t, peripheralSDK.DisplayModeList{
+		{Width: 2560, Height: 1440, RefreshRate: 60},
+		{Width: 1920, Height: 1080, RefreshRate: 60},
+		{Width: 1280, Height: 720, RefreshRate: 60},
+		{Width: 1600, Height: 1200, RefreshRate: 85},
+		{Width: 1024, Height: 768, RefreshRate: 75},
+	}, declared.DisplayModes())
+
+	block, err := CreateBlockFromSpecification(*declared)
+	require.NoError(t, err)
+
+	decoded, err := CreateSpecificationFromBlock(*block)
+	require.NoError(t, err)
+	assert.Equal(t, declared.DisplayModes(), decoded.DisplayModes())
+}
+
+func TestSpecificationWithDisplayModesKeepsCeaExtension(t *testing.T) {
+	t.Parallel()
+
+	block := hdmiCeaExtensionBlock(t)
+
+	ceaExtension, err := CreateCeaExtensionSpecificationFromBlock(block)
+	require.NoError(t, err)
+
+	specification := validSpecification()
+	specification.ExtensionBlockCount = 1
+	specification.CeaExtension = ceaExtension
+
+	displayModes := peripheralSDK.DisplayModeList{
+		{Width: 2560, Height: 1440, RefreshRate: 60},
+		{Width: 1920, Height: 1080, RefreshRate: 60},
+		{Width: 1280, Height: 720, RefreshRate: 60},
+		{Width: 1600, Height: 1200, RefreshRate: 85},
+		{Width: 1366, Height: 768, RefreshRate: 75},
+	}
+
+	declared, skipped, err := specification.WithDisplayModes(displayModes)
+	require.NoError(t, err)
+
+	assert.Empty(t, skipped)
+	assert.Equal(t, uint8(1), declared.ExtensionBlockCount)
+
+	if assert.NotNil(t, declared.CeaExtension) {
+		assert.Equal(t, ceaExtension.HdmiVendorSpecific, declared.CeaExtension.HdmiVendorSpecific)
+		assert.Equal(t, ceaExtension.AudioDescriptors, declared.CeaExtension.AudioDescriptors)
+		assert.True(t, declared.CeaExtension.BasicAudio)
+		assert.Empty(t, declared.CeaExtension.VideoDescriptors)
+		assert.Len(t, declared.CeaExtension.DetailedTimings, 1)
+	}
+
+	// configured extension is not modified
+	assert.Len(t, ceaExtension.VideoDescriptors, 4)
+
+	data, err := CreateBytesFromSpecification(*declared)
+	require.NoError(t, err)
+
+	decoded, err := CreateSpecificationFromBytes(data)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, displayModes, decoded.DisplayModes())
+	assert.Equal(t, declared.CeaExtension.HdmiVendorSpecific, decoded.CeaExtension.HdmiVendorSpecific)
+}
+
+func TestSpecificationWithDisplayModesCvtTiming(t *testing.T) {
+	t.Parallel()
+
+	declared, skipped, err := validSpecification().WithDisplayModes(peripheralSDK.DisplayModeList{
+		{Width: 1600, Height: 1200, RefreshRate: 85},
+		{Width: 1366, Height: 768, RefreshRate: 75},
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, skipped)
+	assert.Equal(t, uint32(186750), declared.Timings.Detailed.Entries[0].Standard.PixelClock)
+	assert.Equal(t, uint16(160), declared.Timings.Detailed.Entries[1].Standard.HorizontalBlank)
+
+	assert.Equal(t, peripheralSDK.DisplayModeList{
+		{Width: 1600, Height: 1200, RefreshRate: 85},
+		{Width: 1366, Height: 768, RefreshRate: 75},
+	}, declared.DisplayModes())
+}
+
+func TestSpecificationWithDisplayModesUnknownTiming(t *testing.T) {
+	t.Parallel()
+
+	_, skipped, err := validSpecification().WithDisplayModes(peripheralSDK.DisplayModeList{
+		{Width: 5120, Height: 2880, RefreshRate: 60},
+	})
+
+	assert.ErrorIs(t, err, ErrPreferredTimingUnknown)
+	assert.Equal(t, peripheralSDK.DisplayModeList{{Width: 5120, Height: 2880, RefreshRate: 60}}, skipped)
+}
+
+func TestKnownTimingsRefreshRate(t *testing.T) {
+	t.Parallel()
+
+	for _, timing := range knownTimings {
+		descriptor := timing.toDescriptor(100, 100)
+
+		assert.NoError(t, descriptor.Validate(), timing.displayMode.String())
+		assert.Equal(t, timing.displayMode, detailedTimingDisplayMode(*descriptor), timing.displayMode.String())
+	}
+}
+
+func knownTimingDescriptor(t *testing.T, displayMode peripheralSDK.DisplayMode) *DetailedTimingsStandardDescriptorEntry {
+	t.Helper()
+
+	timing, ok := findKnownTiming(displayMode)
+	require.True(t, ok)
+
+	return timing.toDescriptor(400, 300)
+}
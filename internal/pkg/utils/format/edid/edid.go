@@ -1,6 +1,9 @@
 package edid
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 type Block [128]byte
 
@@ -31,6 +34,8 @@ type Specification struct {
 	Chromacity          ChromacitySpecification `json:"chromacity" validate:"required"`
 	Timings             TimingsSpecification    `json:"timings" validate:"required"`
 	ExtensionBlockCount uint8                   `json:"extensionBlockCount" validate:"max=127"`
+	// CeaExtension is the first CEA-861 extension block, nil when EDID has none or only base block was decoded.
+	CeaExtension *CeaExtensionSpecification `json:"ceaExtension,omitempty"`
 }
 
 func CreateSpecificationFromBlock(block Block) (*Specification, error) {
@@ -89,6 +94,70 @@ func CreateSpecificationFromBlock(block Block) (*Specification, error) {
 	return specification, nil
 }
 
+// CreateSpecificationFromBytes decodes raw EDID, for example read from a device or a file. Data must contain the base
+// block followed by the number of extension blocks it declares. The first CEA-861 extension block is decoded, other
+// extension blocks are only counted. When only the CEA-861 extension block cannot be decoded, the specification of the
+// base block is returned together with error wrapping ErrCeaExtensionInvalid and CeaExtension is left nil.
+func CreateSpecificationFromBytes(data []byte) (*Specification, error) {
+	if len(data) < edidBlockLength || len(data)%edidBlockLength != 0 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidLength, len(data))
+	}
+
+	specification, err := CreateSpecificationFromBlock(Block(data[:edidBlockLength]))
+	if err != nil {
+		return nil, err
+	}
+
+	extensionBlockCount := len(data)/edidBlockLength - 1
+	if int(specification.ExtensionBlockCount) != extensionBlockCount {
+		return nil, fmt.Errorf("%w: declared %d, found %d", ErrExtensionBlockCountMismatch, specification.ExtensionBlockCount, extensionBlockCount)
+	}
+
+	for index := 1; index <= extensionBlockCount; index++ {
+		extensionBlock := data[index*edidBlockLength : (index+1)*edidBlockLength]
+		if extensionBlock[0] != ceaExtensionTag {
+			continue
+		}
+
+		ceaExtension, err := CreateCeaExtensionSpecificationFromBlock(CeaExtensionBlock(extensionBlock))
+		if err != nil {
+			return specification, fmt.Errorf("%w: extension block %d: %w", ErrCeaExtensionInvalid, index, err)
+		}
+
+		specification.CeaExtension = ceaExtension
+		break
+	}
+
+	return specification, nil
+}
+
+// CreateBytesFromSpecification encodes raw EDID, the base block followed by CEA-861 extension block when the
+// specification has one. Extension block count of the base block is set to the number of encoded extension blocks.
+func CreateBytesFromSpecification(specification Specification) ([]byte, error) {
+	specification.ExtensionBlockCount = 0
+	if specification.CeaExtension != nil {
+		specification.ExtensionBlockCount = 1
+	}
+
+	block, err := CreateBlockFromSpecification(specification)
+	if err != nil {
+		return nil, err
+	}
+
+	data := block[:]
+
+	if specification.CeaExtension != nil {
+		ceaBlock, err := CreateCeaExtensionBlockFromSpecification(*specification.CeaExtension)
+		if err != nil {
+			return nil, fmt.Errorf("cea extension: %w", err)
+		}
+
+		data = append(data, ceaBlock[:]...)
+	}
+
+	return data, nil
+}
+
 func CreateBlockFromSpecification(specification Specification) (*Block, error) {
 	if err := specification.Validate(); err != nil {
 		return nil, fmt.Errorf("validate: %w", err)
@@ -156,6 +225,12 @@ func (specification *Specification) Validate() error {
 		return fmt.Errorf("timings: %w", err)
 	}
 
+	if specification.CeaExtension != nil {
+		if err := specification.CeaExtension.Validate(); err != nil {
+			return fmt.Errorf("cea extension: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -208,3 +283,9 @@ func calculateChecksumByte(prefix []byte) byte {
 
 	return byte((256 - (sum % 256)) % 256)
 }
+
+var (
+	ErrInvalidLength               = errors.New("edid length must be a multiple of 128 bytes")
+	ErrExtensionBlockCountMismatch = errors.New("extension block count mismatch")
+	ErrCeaExtensionInvalid         = errors.New("cea extension block invalid")
+)
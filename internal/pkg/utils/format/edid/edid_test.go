@@ -156,6 +156,91 @@ func TestCreateSpecificationFromBlock_InvalidVersion(t *testing.T) {
 	assert.Contains(t, decodeErr.Error(), "version")
 }
 
+func TestCreateSpecificationFromBytes(t *testing.T) {
+	t.Parallel()
+
+	block, err := CreateBlockFromSpecification(validSpecification())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	specification, err := CreateSpecificationFromBytes(block[:])
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ACM", specification.Vendor.Manufacturer)
+	}
+
+	_, err = CreateSpecificationFromBytes(block[:100])
+	assert.ErrorIs(t, err, ErrInvalidLength)
+
+	_, err = CreateSpecificationFromBytes(append(block[:], make([]byte, edidBlockLength)...))
+	assert.ErrorIs(t, err, ErrExtensionBlockCountMismatch)
+}
+
+func TestCreateSpecificationFromBytesInvalidCeaExtension(t *testing.T) {
+	t.Parallel()
+
+	specification := validSpecification()
+	specification.CeaExtension = &CeaExtensionSpecification{BasicAudio: true}
+
+	data, err := CreateBytesFromSpecification(specification)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// revision 1 block is decoded in its header only form
+	data[edidBlockLength+1] = 1
+	data[len(data)-1] = calculateChecksumByte(data[edidBlockLength : len(data)-1])
+
+	decoded, err := CreateSpecificationFromBytes(data)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &CeaExtensionSpecification{}, decoded.CeaExtension)
+	}
+
+	data[len(data)-1]++
+
+	decoded, err = CreateSpecificationFromBytes(data)
+	assert.ErrorIs(t, err, ErrCeaExtensionInvalid)
+	if assert.NotNil(t, decoded) {
+		assert.Equal(t, "ACM", decoded.Vendor.Manufacturer)
+		assert.Equal(t, uint8(1), decoded.ExtensionBlockCount)
+		assert.Nil(t, decoded.CeaExtension)
+	}
+}
+
+func TestCreateBytesFromSpecification(t *testing.T) {
+	t.Parallel()
+
+	specification := validSpecification()
+	specification.CeaExtension = &CeaExtensionSpecification{
+		BasicAudio:         true,
+		VideoDescriptors:   []CeaShortVideoDescriptorSpecification{{Vic: 16, Native: true}},
+		HdmiVendorSpecific: &CeaHdmiVendorSpecificSpecification{PhysicalAddress: "1.0.0.0"},
+	}
+
+	data, err := CreateBytesFromSpecification(specification)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Len(t, data, 2*edidBlockLength)
+	assert.Equal(t, byte(1), data[edidExtensionIndex])
+
+	decoded, err := CreateSpecificationFromBytes(data)
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint8(1), decoded.ExtensionBlockCount)
+		assert.Equal(t, specification.CeaExtension, decoded.CeaExtension)
+	}
+
+	specification.CeaExtension = nil
+	specification.ExtensionBlockCount = 1
+
+	data, err = CreateBytesFromSpecification(specification)
+	if assert.NoError(t, err) {
+		assert.Len(t, data, edidBlockLength)
+		assert.Equal(t, byte(0), data[edidExtensionIndex])
+	}
+}
+
 func TestCreateBlockFromSpecification_InvalidVendor(t *testing.T) {
 	t.Parallel()
 
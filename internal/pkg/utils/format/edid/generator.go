@@ -0,0 +1,189 @@
+package edid
+
+import (
+	"fmt"
+	"slices"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const (
+	// generatorDetailedTimingSlots leaves descriptors of the base block for monitor name and range limits.
+	generatorDetailedTimingSlots = detailedTimingDescriptorCount - 2
+	// generatorExtensionDetailedTimingSlots is the number of detailed timings fitting extension without data blocks.
+	generatorExtensionDetailedTimingSlots = (ceaChecksumIndex - ceaHeaderLength) / detailedTimingDescriptorLength
+
+	generatorDefaultDpi = 96
+)
+
+type GeneratorOptions struct {
+	vendor      VendorSpecification
+	monitorName string
+	displaySize *DisplaySizeSpecification
+	cvtFormula  CvtFormula
+}
+
+type GeneratorOpt func(*GeneratorOptions)
+
+func defaultGeneratorOptions() GeneratorOptions {
+	return GeneratorOptions{
+		vendor: VendorSpecification{
+			Manufacturer:      "OQD",
+			ProductCode:       "0001",
+			SerialNumber:      "00000001",
+			YearOfManufacture: 2025,
+		},
+		monitorName: "OrbiQD",
+		cvtFormula:  CvtFormulaReducedBlanking,
+	}
+}
+
+func WithGeneratorVendor(vendor VendorSpecification) GeneratorOpt {
+	return func(options *GeneratorOptions) {
+		options.vendor = vendor
+	}
+}
+
+func WithGeneratorMonitorName(name string) GeneratorOpt {
+	return func(options *GeneratorOptions) {
+		options.monitorName = name
+	}
+}
+
+// WithGeneratorDisplaySize sets display size in centimeters, it is derived from the preferred display mode at 96 DPI
+// when not set.
+func WithGeneratorDisplaySize(width int, height int) GeneratorOpt {
+	return func(options *GeneratorOptions) {
+		options.displaySize = &DisplaySizeSpecification{Width: &width, Height: &height}
+	}
+}
+
+// WithGeneratorCvtFormula sets CVT formula computing detailed timings of display modes which are not defined by VESA
+// DMT or CTA-861, reduced blanking is used when not set.
+func WithGeneratorCvtFormula(formula CvtFormula) GeneratorOpt {
+	return func(options *GeneratorOptions) {
+		options.cvtFormula = formula
+	}
+}
+
+// CreateSpecificationFromDisplayModes generates specification of a digital sRGB monitor declaring the display modes,
+// the first one is the preferred mode. Display modes are declared by detailed timings while free descriptors are left,
+// then by established and standard timings, and finally by detailed timings of CEA-861 extension, which is added only
+// when needed. Base block carries monitor name and range limits covering declared modes. Display modes which cannot be
+// declared are returned as skipped. Use CreateBytesFromSpecification to encode checksummed EDID.
+func CreateSpecificationFromDisplayModes(displayModes peripheralSDK.DisplayModeList, opts ...GeneratorOpt) (*Specification, peripheralSDK.DisplayModeList, error) {
+	options := defaultGeneratorOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if err := options.cvtFormula.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	gamma := 2.2
+
+	specification := Specification{
+		Vendor: options.vendor,
+		Display: DisplaySpecification{
+			Input: DisplayInputSpecification{
+				Digital: &DisplayDigitalInputSpecification{},
+			},
+			Gamma: &gamma,
+			Features: DisplayFeaturesSpecification{
+				IsRgbColor:                 true,
+				UsesStandardSrgbColorSpace: true,
+				HasPreferredTimingMode:     true,
+			},
+		},
+		Chromacity: ChromacitySpecification{
+			RedX:   0.640,
+			RedY:   0.330,
+			GreenX: 0.300,
+			GreenY: 0.600,
+			BlueX:  0.150,
+			BlueY:  0.060,
+			WhiteX: 0.3127,
+			WhiteY: 0.3290,
+		},
+	}
+
+	if options.displaySize != nil {
+		specification.Display.Size = *options.displaySize
+	} else if len(displayModes) > 0 {
+		specification.Display.Size = deriveDisplaySize(displayModes[0])
+	}
+
+	declaration := specification.declareDisplayModes(displayModes, generatorDetailedTimingSlots, generatorExtensionDetailedTimingSlots, options.cvtFormula)
+
+	if len(declaration.timings.Detailed.Entries) == 0 {
+		return nil, declaration.skippedModes, fmt.Errorf("%w: %s", ErrPreferredTimingUnknown, displayModes)
+	}
+
+	specification.Timings = declaration.timings
+	specification.Timings.Detailed.Entries = append(specification.Timings.Detailed.Entries,
+		DetailedTimingsEntrySpecification{RangeLimits: createRangeLimits(declaration.declaredTimings)},
+		DetailedTimingsEntrySpecification{MonitorName: &DetailedTimingsMonitorNameDescriptorEntry{Name: options.monitorName}},
+	)
+
+	if len(declaration.extensionDetailedTimings) > 0 {
+		specification.ExtensionBlockCount = 1
+		specification.CeaExtension = &CeaExtensionSpecification{
+			DetailedTimings: declaration.extensionDetailedTimings,
+		}
+	}
+
+	if err := specification.Validate(); err != nil {
+		return nil, declaration.skippedModes, err
+	}
+
+	return &specification, declaration.skippedModes, nil
+}
+
+// deriveDisplaySize returns display size in centimeters of the display mode at 96 DPI.
+func deriveDisplaySize(displayMode peripheralSDK.DisplayMode) DisplaySizeSpecification {
+	toCentimeters := func(pixels uint32) *int {
+		size := int((uint64(pixels)*254 + generatorDefaultDpi*50) / (generatorDefaultDpi * 100))
+		size = min(max(size, 1), 255)
+
+		return &size
+	}
+
+	return DisplaySizeSpecification{
+		Width:  toCentimeters(displayMode.Width),
+		Height: toCentimeters(displayMode.Height),
+	}
+}
+
+// createRangeLimits returns range limits covering the timings. Minimal and maximal rates differ by at least one, as
+// range limits descriptor requires.
+func createRangeLimits(timings []detailedTiming) *DetailedTimingsRangeLimitsDescriptorEntry {
+	verticalRates := []uint32{}
+	horizontalRates := []uint32{}
+	maxPixelClock := uint32(0)
+
+	for _, timing := range timings {
+		horizontalTotal := timing.displayMode.Width + uint32(timing.horizontalBlank)
+
+		verticalRates = append(verticalRates, timing.displayMode.RefreshRate)
+		horizontalRates = append(horizontalRates, timing.pixelClock/horizontalTotal, (timing.pixelClock+horizontalTotal-1)/horizontalTotal)
+		maxPixelClock = max(maxPixelClock, timing.pixelClock)
+	}
+
+	clampRange := func(minValue uint32, maxValue uint32, minLimit uint32, maxLimit uint32) (uint8, uint8) {
+		minValue = min(max(minValue, minLimit), maxLimit-1)
+		maxValue = min(max(maxValue, minValue+1), maxLimit)
+
+		return uint8(minValue), uint8(maxValue)
+	}
+
+	rangeLimits := &DetailedTimingsRangeLimitsDescriptorEntry{}
+
+	rangeLimits.MinVerticalHz, rangeLimits.MaxVerticalHz = clampRange(slices.Min(verticalRates), slices.Max(verticalRates), 1, 240)
+	rangeLimits.MinKHz, rangeLimits.MaxKHz = clampRange(slices.Min(horizontalRates), slices.Max(horizontalRates), 1, 255)
+
+	// range limits declare pixel clock in 10 MHz steps
+	rangeLimits.MaxClockMHz = uint8(min(max((maxPixelClock+9999)/10000, 10), 255))
+
+	return rangeLimits
+}
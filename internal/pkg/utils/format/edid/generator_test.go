@@ -0,0 +1,116 @@
+package edid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func TestCreateSpecificationFromDisplayModes(t *testing.T) {
+	t.Parallel()
+
+	displayModes := peripheralSDK.DisplayModeList{
+		{Width: 1920, Height: 1080, RefreshRate: 60},
+		{Width: 2560, Height: 1080, RefreshRate: 75},
+		{Width: 1024, Height: 768, RefreshRate: 75},
+		{Width: 1600, Height: 900, RefreshRate: 75},
+		{Width: 3440, Height: 1440, RefreshRate: 60},
+		{Width: 1366, Height: 768, RefreshRate: 60},
+		{Width: 8192, Height: 4320, RefreshRate: 60},
+	}
+
+	specification, skipped, err := CreateSpecificationFromDisplayModes(displayModes)
+	require.NoError(t, err)
+
+	assert.Equal(t, peripheralSDK.DisplayModeList{{Width: 8192, Height: 4320, RefreshRate: 60}}, skipped)
+
+	assert.Equal(t, "OQD", specification.Vendor.Manufacturer)
+	assert.Equal(t, 51, *specification.Display.Size.Width)
+	assert.Equal(t, 29, *specification.Display.Size.Height)
+
+	detailedEntries := specification.Timings.Detailed.Entries
+	require.Len(t, detailedEntries, detailedTimingDescriptorCount)
+	assert.Equal(t, knownTimings[15].toDescriptor(510, 290), detailedEntries[0].Standard)
+	assert.Equal(t, uint16(cvtRbHorizontalBlank), detailedEntries[1].Standard.HorizontalBlank)
+	assert.Equal(t, &DetailedTimingsRangeLimitsDescriptorEntry{MinVerticalHz: 60, MaxVerticalHz: 75, MinKHz: 47, MaxKHz: 89, MaxClockMHz: 32}, detailedEntries[2].RangeLimits)
+	assert.Equal(t, "OrbiQD", detailedEntries[3].MonitorName.Name)
+
+	assert.True(t, specification.Timings.Established.Supports1024x768x75)
+	assert.Equal(t, []StandardTimingEntrySpecification{
+		{Width: 1600, Height: 900, RefreshRate: 75, AspectRatio: StandardTimingEntryAspectRatio16x9},
+	}, specification.Timings.Standard.Entries)
+
+	require.NotNil(t, specification.CeaExtension)
+	assert.Len(t, specification.CeaExtension.DetailedTimings, 2)
+
+	expectedDisplayModes := peripheralSDK.DisplayModeList{
+		{Width: 1920, Height: 1080, RefreshRate: 60},
+		{Width: 2560, Height: 1080, RefreshRate: 75},
+		{Width: 3440, Height: 1440, RefreshRate: 60},
+		{Width: 1366, Height: 768, RefreshRate: 60},
+		{Width: 1600, Height: 900, RefreshRate: 75},
+		{Width: 1024, Height: 768, RefreshRate: 75},
+	}
+	assert.Equal(t, expectedDisplayModes, specification.DisplayModes())
+
+	data, err := CreateBytesFromSpecification(*specification)
+	require.NoError(t, err)
+	assert.Len(t, data, 2*edidBlockLength)
+
+	decoded, err := CreateSpecificationFromBytes(data)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(1), decoded.ExtensionBlockCount)
+	assert.Equal(t, expectedDisplayModes, decoded.DisplayModes())
+}
+
+func TestCreateSpecificationFromDisplayModesOptions(t *testing.T) {
+	t.Parallel()
+
+	vendor := VendorSpecification{Manufacturer: "ACM", ProductCode: "1A2B", SerialNumber: "00ABCDEF", YearOfManufacture: 2020}
+
+	specification, skipped, err := CreateSpecificationFromDisplayModes(
+		peripheralSDK.DisplayModeList{{Width: 2560, Height: 1600, RefreshRate: 60}},
+		WithGeneratorVendor(vendor),
+		WithGeneratorMonitorName("Capture"),
+		WithGeneratorDisplaySize(60, 34),
+		WithGeneratorCvtFormula(CvtFormulaReducedBlankingV2),
+	)
+	require.NoError(t, err)
+
+	assert.Empty(t, skipped)
+	assert.Equal(t, vendor, specification.Vendor)
+	assert.Nil(t, specification.CeaExtension)
+
+	detailedEntries := specification.Timings.Detailed.Entries
+	require.Len(t, detailedEntries, 3)
+	assert.Equal(t, uint16(cvtRb2HorizontalBlank), detailedEntries[0].Standard.HorizontalBlank)
+	assert.Equal(t, uint16(600), detailedEntries[0].Standard.HorizontalImageSize)
+	assert.Equal(t, uint8(60), detailedEntries[1].RangeLimits.MinVerticalHz)
+	assert.Equal(t, uint8(61), detailedEntries[1].RangeLimits.MaxVerticalHz)
+	assert.Equal(t, "Capture", detailedEntries[2].MonitorName.Name)
+
+	_, err = CreateBytesFromSpecification(*specification)
+	assert.NoError(t, err)
+}
+
+func TestCreateSpecificationFromDisplayModesErrors(t *testing.T) {
+	t.Parallel()
+
+	displayModes := peripheralSDK.DisplayModeList{{Width: 1920, Height: 1080, RefreshRate: 60}}
+
+	_, _, err := CreateSpecificationFromDisplayModes(displayModes, WithGeneratorCvtFormula("gtf"))
+	assert.ErrorContains(t, err, "unknown cvt formula")
+
+	_, _, err = CreateSpecificationFromDisplayModes(displayModes, WithGeneratorMonitorName("Monitor name too long"))
+	assert.ErrorContains(t, err, "Name")
+
+	_, skipped, err := CreateSpecificationFromDisplayModes(peripheralSDK.DisplayModeList{{Width: 5120, Height: 2880, RefreshRate: 60}})
+	assert.ErrorIs(t, err, ErrPreferredTimingUnknown)
+	assert.Len(t, skipped, 1)
+
+	_, _, err = CreateSpecificationFromDisplayModes(nil)
+	assert.ErrorIs(t, err, ErrPreferredTimingUnknown)
+}
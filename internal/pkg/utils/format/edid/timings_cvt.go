@@ -0,0 +1,222 @@
+package edid
+
+import (
+	"fmt"
+	"math"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// CvtFormula selects variant of VESA Coordinated Video Timings used to compute detailed timing of a display mode.
+type CvtFormula string
+
+const (
+	// CvtFormulaStandard is CVT 1.2 with standard blanking, suitable for analog displays and old receivers.
+	CvtFormulaStandard CvtFormula = "cvt"
+	// CvtFormulaReducedBlanking is CVT 1.2 reduced blanking, which lowers pixel clock of digital displays.
+	CvtFormulaReducedBlanking CvtFormula = "cvt-rb"
+	// CvtFormulaReducedBlankingV2 is CVT 1.2 reduced blanking version 2 with even shorter horizontal blanking and pixel
+	// clock in 1 kHz steps, rounded down to 10 kHz steps of detailed timing descriptor.
+	CvtFormulaReducedBlankingV2 CvtFormula = "cvt-rb2"
+)
+
+const (
+	cvtCellGranularity             = 8
+	cvtMinVerticalFrontPorch       = 3
+	cvtMinVerticalBackPorch        = 6
+	cvtMinVerticalSyncBackPorchUs  = 550.0
+	cvtHorizontalSyncPercent       = 8.0
+	cvtBlankingFormulaOffset       = 30.0
+	cvtBlankingFormulaGradient     = 300.0
+	cvtMinHorizontalBlankDutyCycle = 20.0
+	cvtClockStepKHz                = 250
+
+	cvtRbMinVerticalBlankUs    = 460.0
+	cvtRbHorizontalBlank       = 160
+	cvtRbHorizontalSync        = 32
+	cvtRbHorizontalFrontPorch  = 48
+	cvtRbMinVerticalFrontPorch = 3
+
+	cvtRb2HorizontalBlank       = 80
+	cvtRb2HorizontalSync        = 32
+	cvtRb2HorizontalFrontPorch  = 8
+	cvtRb2VerticalSync          = 8
+	cvtRb2VerticalBackPorch     = 6
+	cvtRb2MinVerticalFrontPorch = 1
+	cvtRb2ClockStepKHz          = 10
+
+	// cvtMaxActive is the largest active size expressible by detailed timing descriptor.
+	cvtMaxActive = 4095
+)
+
+// CreateCvtDetailedTiming computes detailed timing descriptor of the progressive display mode using the CVT formula,
+// image size is in millimeters. Active width is kept even when it is not a multiple of the character cell.
+func CreateCvtDetailedTiming(displayMode peripheralSDK.DisplayMode, formula CvtFormula, horizontalImageSize uint16, verticalImageSize uint16) (*DetailedTimingsStandardDescriptorEntry, error) {
+	timing, err := computeCvtTiming(displayMode, formula)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor := timing.toDescriptor(horizontalImageSize, verticalImageSize)
+
+	if err := descriptor.Validate(); err != nil {
+		return nil, fmt.Errorf("validate %s timing of %s: %w", formula, displayMode, err)
+	}
+
+	return descriptor, nil
+}
+
+func (formula CvtFormula) Validate() error {
+	switch formula {
+	case CvtFormulaStandard, CvtFormulaReducedBlanking, CvtFormulaReducedBlankingV2:
+		return nil
+	default:
+		return fmt.Errorf("unknown cvt formula: %q", formula)
+	}
+}
+
+func computeCvtTiming(displayMode peripheralSDK.DisplayMode, formula CvtFormula) (detailedTiming, error) {
+	if err := displayMode.Valid(); err != nil {
+		return detailedTiming{}, err
+	}
+
+	if err := formula.Validate(); err != nil {
+		return detailedTiming{}, err
+	}
+
+	if displayMode.Width > cvtMaxActive || displayMode.Height > cvtMaxActive {
+		return detailedTiming{}, fmt.Errorf("display mode exceeds detailed timing range: %s", displayMode)
+	}
+
+	// frame has to be longer than the minimal vertical blanking of every formula
+	if uint64(displayMode.RefreshRate)*cvtMinVerticalSyncBackPorchUs >= 1e6 {
+		return detailedTiming{}, fmt.Errorf("refresh rate too high: %s", displayMode)
+	}
+
+	switch formula {
+	case CvtFormulaStandard:
+		return computeCvtStandardTiming(displayMode), nil
+	case CvtFormulaReducedBlankingV2:
+		return computeCvtReducedBlankingV2Timing(displayMode), nil
+	default:
+		return computeCvtReducedBlankingTiming(displayMode), nil
+	}
+}
+
+func computeCvtStandardTiming(displayMode peripheralSDK.DisplayMode) detailedTiming {
+	width := float64(displayMode.Width)
+	height := float64(displayMode.Height)
+	refreshRate := float64(displayMode.RefreshRate)
+
+	verticalSync := cvtVerticalSyncWidth(displayMode)
+
+	horizontalPeriodEstimate := (1e6/refreshRate - cvtMinVerticalSyncBackPorchUs) / (height + cvtMinVerticalFrontPorch)
+
+	// blanking lines are clamped, descriptor validation rejects timings exceeding its range
+	verticalSyncBackPorch := uint16(min(math.Floor(cvtMinVerticalSyncBackPorchUs/horizontalPeriodEstimate)+1, math.MaxUint16/2))
+	verticalSyncBackPorch = max(verticalSyncBackPorch, verticalSync+cvtMinVerticalBackPorch)
+
+	dutyCycle := cvtBlankingFormulaOffset - cvtBlankingFormulaGradient*horizontalPeriodEstimate/1000
+	dutyCycle = max(dutyCycle, cvtMinHorizontalBlankDutyCycle)
+
+	horizontalBlank := uint16(math.Floor(width*dutyCycle/(100-dutyCycle)/(2*cvtCellGranularity))) * 2 * cvtCellGranularity
+	horizontalTotal := float64(displayMode.Width) + float64(horizontalBlank)
+
+	pixelClockKHz := horizontalTotal / horizontalPeriodEstimate * 1000
+	pixelClock := uint32(math.Floor(pixelClockKHz/cvtClockStepKHz)) * cvtClockStepKHz
+
+	horizontalSync := uint16(math.Floor(cvtHorizontalSyncPercent/100*horizontalTotal/cvtCellGranularity)) * cvtCellGranularity
+	horizontalBackPorch := horizontalBlank / 2
+
+	return detailedTiming{
+		displayMode:          displayMode,
+		pixelClock:           pixelClock,
+		horizontalBlank:      horizontalBlank,
+		horizontalSyncOffset: horizontalBlank - horizontalBackPorch - horizontalSync,
+		horizontalSyncWidth:  horizontalSync,
+		verticalBlank:        verticalSyncBackPorch + cvtMinVerticalFrontPorch,
+		verticalSyncOffset:   cvtMinVerticalFrontPorch,
+		verticalSyncWidth:    verticalSync,
+
+		horizontalSyncPositive: false,
+		verticalSyncPositive:   true,
+	}
+}
+
+func computeCvtReducedBlankingTiming(displayMode peripheralSDK.DisplayMode) detailedTiming {
+	verticalSync := cvtVerticalSyncWidth(displayMode)
+
+	verticalBlank := cvtReducedBlankingVerticalBlank(displayMode, cvtRbMinVerticalFrontPorch+verticalSync+cvtMinVerticalBackPorch)
+	pixelClock := cvtReducedBlankingPixelClock(displayMode, cvtRbHorizontalBlank, verticalBlank, cvtClockStepKHz)
+
+	return detailedTiming{
+		displayMode:          displayMode,
+		pixelClock:           pixelClock,
+		horizontalBlank:      cvtRbHorizontalBlank,
+		horizontalSyncOffset: cvtRbHorizontalFrontPorch,
+		horizontalSyncWidth:  cvtRbHorizontalSync,
+		verticalBlank:        verticalBlank,
+		verticalSyncOffset:   cvtRbMinVerticalFrontPorch,
+		verticalSyncWidth:    verticalSync,
+
+		horizontalSyncPositive: true,
+		verticalSyncPositive:   false,
+	}
+}
+
+func computeCvtReducedBlankingV2Timing(displayMode peripheralSDK.DisplayMode) detailedTiming {
+	verticalBlank := cvtReducedBlankingVerticalBlank(displayMode, cvtRb2MinVerticalFrontPorch+cvtRb2VerticalSync+cvtRb2VerticalBackPorch)
+	pixelClock := cvtReducedBlankingPixelClock(displayMode, cvtRb2HorizontalBlank, verticalBlank, cvtRb2ClockStepKHz)
+
+	return detailedTiming{
+		displayMode:          displayMode,
+		pixelClock:           pixelClock,
+		horizontalBlank:      cvtRb2HorizontalBlank,
+		horizontalSyncOffset: cvtRb2HorizontalFrontPorch,
+		horizontalSyncWidth:  cvtRb2HorizontalSync,
+		verticalBlank:        verticalBlank,
+		verticalSyncOffset:   verticalBlank - cvtRb2VerticalSync - cvtRb2VerticalBackPorch,
+		verticalSyncWidth:    cvtRb2VerticalSync,
+
+		horizontalSyncPositive: true,
+		verticalSyncPositive:   false,
+	}
+}
+
+// cvtReducedBlankingVerticalBlank returns number of lines needed to cover minimal vertical blanking time.
+func cvtReducedBlankingVerticalBlank(displayMode peripheralSDK.DisplayMode, minVerticalBlank uint16) uint16 {
+	horizontalPeriodEstimate := (1e6/float64(displayMode.RefreshRate) - cvtRbMinVerticalBlankUs) / float64(displayMode.Height)
+
+	verticalBlank := uint16(min(math.Floor(cvtRbMinVerticalBlankUs/horizontalPeriodEstimate)+1, math.MaxUint16/2))
+
+	return max(verticalBlank, minVerticalBlank)
+}
+
+// cvtReducedBlankingPixelClock returns pixel clock in kHz rounded down to the clock step.
+func cvtReducedBlankingPixelClock(displayMode peripheralSDK.DisplayMode, horizontalBlank uint16, verticalBlank uint16, clockStep uint64) uint32 {
+	horizontalTotal := uint64(displayMode.Width) + uint64(horizontalBlank)
+	verticalTotal := uint64(displayMode.Height) + uint64(verticalBlank)
+
+	pixelClockHz := uint64(displayMode.RefreshRate) * horizontalTotal * verticalTotal
+
+	return uint32(pixelClockHz / (clockStep * 1000) * clockStep)
+}
+
+// cvtVerticalSyncWidth returns vertical sync width, which encodes aspect ratio of the display mode in CVT.
+func cvtVerticalSyncWidth(displayMode peripheralSDK.DisplayMode) uint16 {
+	width := displayMode.Width
+	height := displayMode.Height
+
+	switch {
+	case width*3 == height*4:
+		return 4
+	case width*9 == height*16:
+		return 5
+	case width*10 == height*16:
+		return 6
+	case width*4 == height*5, width*9 == height*15:
+		return 7
+	default:
+		return 10
+	}
+}
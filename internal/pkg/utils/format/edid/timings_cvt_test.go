@@ -0,0 +1,137 @@
+package edid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func TestCreateCvtDetailedTiming(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		displayMode peripheralSDK.DisplayMode
+		formula     CvtFormula
+		expected    detailedTiming
+	}{
+		{
+			name:        "cvt 1024x768",
+			displayMode: peripheralSDK.DisplayMode{Width: 1024, Height: 768, RefreshRate: 60},
+			formula:     CvtFormulaStandard,
+			expected:    detailedTiming{pixelClock: 63500, horizontalBlank: 304, horizontalSyncOffset: 48, horizontalSyncWidth: 104, verticalBlank: 30, verticalSyncOffset: 3, verticalSyncWidth: 4, verticalSyncPositive: true},
+		},
+		{
+			name:        "cvt 1280x720",
+			displayMode: peripheralSDK.DisplayMode{Width: 1280, Height: 720, RefreshRate: 60},
+			formula:     CvtFormulaStandard,
+			expected:    detailedTiming{pixelClock: 74500, horizontalBlank: 384, horizontalSyncOffset: 64, horizontalSyncWidth: 128, verticalBlank: 28, verticalSyncOffset: 3, verticalSyncWidth: 5, verticalSyncPositive: true},
+		},
+		{
+			name:        "cvt 1920x1080",
+			displayMode: peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 60},
+			formula:     CvtFormulaStandard,
+			expected:    detailedTiming{pixelClock: 173000, horizontalBlank: 656, horizontalSyncOffset: 128, horizontalSyncWidth: 200, verticalBlank: 40, verticalSyncOffset: 3, verticalSyncWidth: 5, verticalSyncPositive: true},
+		},
+		{
+			name:        "cvt-rb 1920x1080",
+			displayMode: peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 60},
+			formula:     CvtFormulaReducedBlanking,
+			expected:    detailedTiming{pixelClock: 138500, horizontalBlank: 160, horizontalSyncOffset: 48, horizontalSyncWidth: 32, verticalBlank: 31, verticalSyncOffset: 3, verticalSyncWidth: 5, horizontalSyncPositive: true},
+		},
+		{
+			name:        "cvt-rb 2560x1440",
+			displayMode: peripheralSDK.DisplayMode{Width: 2560, Height: 1440, RefreshRate: 60},
+			formula:     CvtFormulaReducedBlanking,
+			expected:    detailedTiming{pixelClock: 241500, horizontalBlank: 160, horizontalSyncOffset: 48, horizontalSyncWidth: 32, verticalBlank: 41, verticalSyncOffset: 3, verticalSyncWidth: 5, horizontalSyncPositive: true},
+		},
+		{
+			name:        "cvt-rb2 1920x1080",
+			displayMode: peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 60},
+			formula:     CvtFormulaReducedBlankingV2,
+			expected:    detailedTiming{pixelClock: 133320, horizontalBlank: 80, horizontalSyncOffset: 8, horizontalSyncWidth: 32, verticalBlank: 31, verticalSyncOffset: 17, verticalSyncWidth: 8, horizontalSyncPositive: true},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			descriptor, err := CreateCvtDetailedTiming(testCase.displayMode, testCase.formula, 400, 300)
+			require.NoError(t, err)
+
+			expected := testCase.expected
+			expected.displayMode = testCase.displayMode
+
+			assert.Equal(t, expected.toDescriptor(400, 300), descriptor)
+			assert.Equal(t, testCase.displayMode, detailedTimingDisplayMode(*descriptor))
+		})
+	}
+}
+
+func TestCreateCvtDetailedTimingErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		displayMode peripheralSDK.DisplayMode
+		formula     CvtFormula
+		errMsg      string
+	}{
+		{
+			name:        "unknown formula",
+			displayMode: peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 60},
+			formula:     "gtf",
+			errMsg:      "unknown cvt formula",
+		},
+		{
+			name:        "invalid display mode",
+			displayMode: peripheralSDK.DisplayMode{Width: 1920, Height: 1080},
+			formula:     CvtFormulaReducedBlanking,
+			errMsg:      "invalid display mode refresh rate",
+		},
+		{
+			name:        "width",
+			displayMode: peripheralSDK.DisplayMode{Width: 5120, Height: 2160, RefreshRate: 30},
+			formula:     CvtFormulaReducedBlanking,
+			errMsg:      "exceeds detailed timing range",
+		},
+		{
+			name:        "refresh rate",
+			displayMode: peripheralSDK.DisplayMode{Width: 640, Height: 480, RefreshRate: 2000},
+			formula:     CvtFormulaReducedBlanking,
+			errMsg:      "refresh rate too high",
+		},
+		{
+			name:        "pixel clock",
+			displayMode: peripheralSDK.DisplayMode{Width: 3840, Height: 2160, RefreshRate: 120},
+			formula:     CvtFormulaStandard,
+			errMsg:      "PixelClock",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateCvtDetailedTiming(testCase.displayMode, testCase.formula, 400, 300)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), testCase.errMsg)
+			}
+		})
+	}
+}
+
+func TestCvtVerticalSyncWidth(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, uint16(4), cvtVerticalSyncWidth(peripheralSDK.DisplayMode{Width: 1600, Height: 1200}))
+	assert.Equal(t, uint16(5), cvtVerticalSyncWidth(peripheralSDK.DisplayMode{Width: 3840, Height: 2160}))
+	assert.Equal(t, uint16(6), cvtVerticalSyncWidth(peripheralSDK.DisplayMode{Width: 1920, Height: 1200}))
+	assert.Equal(t, uint16(7), cvtVerticalSyncWidth(peripheralSDK.DisplayMode{Width: 1280, Height: 1024}))
+	assert.Equal(t, uint16(7), cvtVerticalSyncWidth(peripheralSDK.DisplayMode{Width: 1280, Height: 768}))
+	assert.Equal(t, uint16(10), cvtVerticalSyncWidth(peripheralSDK.DisplayMode{Width: 1366, Height: 768}))
+}
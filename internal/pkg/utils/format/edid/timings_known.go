@@ -0,0 +1,82 @@
+package edid
+
+import (
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// detailedTiming is a detailed timing of a display mode. Pixel clock is in kHz.
+type detailedTiming struct {
+	displayMode peripheralSDK.DisplayMode
+
+	pixelClock uint32
+
+	horizontalBlank      uint16
+	horizontalSyncOffset uint16
+	horizontalSyncWidth  uint16
+
+	verticalBlank      uint16
+	verticalSyncOffset uint16
+	verticalSyncWidth  uint16
+
+	horizontalSyncPositive bool
+	verticalSyncPositive   bool
+}
+
+// knownTimings are detailed timings defined by VESA DMT or CTA-861. Timings with sync offset exceeding the detailed
+// timing descriptor range are left out.
+var knownTimings = []detailedTiming{
+	{peripheralSDK.DisplayMode{Width: 640, Height: 480, RefreshRate: 60}, 25170, 160, 16, 96, 45, 10, 2, false, false},
+	{peripheralSDK.DisplayMode{Width: 800, Height: 600, RefreshRate: 60}, 40000, 256, 40, 128, 28, 1, 4, true, true},
+	{peripheralSDK.DisplayMode{Width: 1024, Height: 768, RefreshRate: 60}, 65000, 320, 24, 136, 38, 3, 6, false, false},
+	{peripheralSDK.DisplayMode{Width: 1280, Height: 720, RefreshRate: 50}, 74250, 700, 440, 40, 30, 5, 5, true, true},
+	{peripheralSDK.DisplayMode{Width: 1280, Height: 720, RefreshRate: 60}, 74250, 370, 110, 40, 30, 5, 5, true, true},
+	{peripheralSDK.DisplayMode{Width: 1280, Height: 800, RefreshRate: 60}, 71000, 160, 48, 32, 23, 3, 6, true, false},
+	{peripheralSDK.DisplayMode{Width: 1280, Height: 1024, RefreshRate: 60}, 108000, 408, 48, 112, 42, 1, 3, true, true},
+	{peripheralSDK.DisplayMode{Width: 1366, Height: 768, RefreshRate: 60}, 85500, 426, 70, 143, 30, 3, 3, true, true},
+	{peripheralSDK.DisplayMode{Width: 1440, Height: 900, RefreshRate: 60}, 88750, 160, 48, 32, 26, 3, 6, true, false},
+	{peripheralSDK.DisplayMode{Width: 1600, Height: 900, RefreshRate: 60}, 108000, 200, 24, 80, 100, 1, 3, true, true},
+	{peripheralSDK.DisplayMode{Width: 1680, Height: 1050, RefreshRate: 60}, 119000, 160, 48, 32, 30, 3, 6, true, false},
+	{peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 24}, 74250, 830, 638, 44, 45, 4, 5, true, true},
+	{peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 25}, 74250, 720, 528, 44, 45, 4, 5, true, true},
+	{peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 30}, 74250, 280, 88, 44, 45, 4, 5, true, true},
+	{peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 50}, 148500, 720, 528, 44, 45, 4, 5, true, true},
+	{peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 60}, 148500, 280, 88, 44, 45, 4, 5, true, true},
+	{peripheralSDK.DisplayMode{Width: 1920, Height: 1200, RefreshRate: 60}, 154000, 160, 48, 32, 35, 3, 6, true, false},
+	{peripheralSDK.DisplayMode{Width: 2560, Height: 1440, RefreshRate: 60}, 241500, 160, 48, 32, 41, 3, 5, true, false},
+	{peripheralSDK.DisplayMode{Width: 3840, Height: 2160, RefreshRate: 30}, 297000, 560, 176, 88, 90, 8, 10, true, true},
+	{peripheralSDK.DisplayMode{Width: 3840, Height: 2160, RefreshRate: 60}, 594000, 560, 176, 88, 90, 8, 10, true, true},
+}
+
+// findKnownTiming returns detailed timing of the display mode when it is defined by VESA DMT or CTA-861.
+func findKnownTiming(displayMode peripheralSDK.DisplayMode) (detailedTiming, bool) {
+	for _, timing := range knownTimings {
+		if timing.displayMode == displayMode {
+			return timing, true
+		}
+	}
+
+	return detailedTiming{}, false
+}
+
+// toDescriptor returns detailed timing descriptor with image size in millimeters.
+func (timing detailedTiming) toDescriptor(horizontalImageSize uint16, verticalImageSize uint16) *DetailedTimingsStandardDescriptorEntry {
+	return &DetailedTimingsStandardDescriptorEntry{
+		PixelClock:           timing.pixelClock,
+		HorizontalActive:     uint16(timing.displayMode.Width),
+		HorizontalBlank:      timing.horizontalBlank,
+		VerticalActive:       uint16(timing.displayMode.Height),
+		VerticalBlank:        timing.verticalBlank,
+		HorizontalSyncOffset: timing.horizontalSyncOffset,
+		HorizontalSyncWidth:  timing.horizontalSyncWidth,
+		VerticalSyncOffset:   timing.verticalSyncOffset,
+		VerticalSyncWidth:    timing.verticalSyncWidth,
+		HorizontalImageSize:  horizontalImageSize,
+		VerticalImageSize:    verticalImageSize,
+
+		StereoMode: DetailedTimingsStereoModeNone,
+		SyncType:   DetailedTimingsSyncTypeDigitalSeparate,
+
+		HorizontalSyncPositive: timing.horizontalSyncPositive,
+		VerticalSyncPositive:   timing.verticalSyncPositive,
+	}
+}
@@ -0,0 +1,179 @@
+package imagefile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/ppm"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/rgb"
+)
+
+// Format identifies image file encoding.
+type Format string
+
+const (
+	FormatUnknown Format = ""
+	FormatPNG     Format = "png"
+	FormatJPEG    Format = "jpeg"
+	FormatPPM     Format = "ppm"
+)
+
+func (format Format) String() string {
+	return string(format)
+}
+
+var (
+	pngMagic  = []byte("\x89PNG")
+	jpegMagic = []byte{0xff, 0xd8}
+	ppmMagic  = []byte("P6")
+)
+
+// Image holds RGB24 pixels of a decoded image file.
+type Image struct {
+	Format Format
+	Width  uint32
+	Height uint32
+	Pixels []byte
+}
+
+// IsSupportedExtension reports whether file name has extension of a supported image format.
+func IsSupportedExtension(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png", ".jpg", ".jpeg", ".ppm":
+		return true
+	default:
+		return false
+	}
+}
+
+// DetectFormat recognizes image format from the leading bytes of the file.
+func DetectFormat(header []byte) Format {
+	switch {
+	case bytes.HasPrefix(header, pngMagic):
+		return FormatPNG
+	case bytes.HasPrefix(header, jpegMagic):
+		return FormatJPEG
+	case bytes.HasPrefix(header, ppmMagic):
+		return FormatPPM
+	default:
+		return FormatUnknown
+	}
+}
+
+// DecodeConfig returns format and dimensions of an image without decoding pixel data.
+func DecodeConfig(reader io.Reader) (Format, uint32, uint32, error) {
+	bufferedReader := bufio.NewReader(reader)
+
+	format, err := detectReaderFormat(bufferedReader)
+	if err != nil {
+		return FormatUnknown, 0, 0, err
+	}
+
+	var config image.Config
+
+	switch format {
+	case FormatPPM:
+		width, height, err := ppm.DecodeConfig(bufferedReader)
+		if err != nil {
+			return FormatUnknown, 0, 0, err
+		}
+		return format, width, height, nil
+	case FormatPNG:
+		config, err = png.DecodeConfig(bufferedReader)
+	case FormatJPEG:
+		config, err = jpeg.DecodeConfig(bufferedReader)
+	}
+	if err != nil {
+		return FormatUnknown, 0, 0, fmt.Errorf("decode %s config: %w", format, err)
+	}
+
+	return format, uint32(config.Width), uint32(config.Height), nil
+}
+
+// Decode decodes image into RGB24 pixels. Pixels are appended to dst, which may be nil.
+func Decode(dst []byte, reader io.Reader) (*Image, error) {
+	bufferedReader := bufio.NewReader(reader)
+
+	format, err := detectReaderFormat(bufferedReader)
+	if err != nil {
+		return nil, err
+	}
+
+	var decodedImage image.Image
+
+	switch format {
+	case FormatPPM:
+		ppmImage, err := ppm.Decode(dst, bufferedReader)
+		if err != nil {
+			return nil, err
+		}
+		return &Image{Format: format, Width: ppmImage.Width, Height: ppmImage.Height, Pixels: ppmImage.Pixels}, nil
+	case FormatPNG:
+		decodedImage, err = png.Decode(bufferedReader)
+	case FormatJPEG:
+		decodedImage, err = jpeg.Decode(bufferedReader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", format, err)
+	}
+
+	bounds := decodedImage.Bounds()
+
+	return &Image{
+		Format: format,
+		Width:  uint32(bounds.Dx()),
+		Height: uint32(bounds.Dy()),
+		Pixels: rgb.FromImage(dst, decodedImage),
+	}, nil
+}
+
+// DecodeFileConfig opens file at path and returns its format and dimensions.
+func DecodeFileConfig(path string) (Format, uint32, uint32, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return FormatUnknown, 0, 0, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return DecodeConfig(file)
+}
+
+// DecodeFile opens file at path and decodes it into RGB24 pixels appended to dst.
+func DecodeFile(dst []byte, path string) (*Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return Decode(dst, file)
+}
+
+func detectReaderFormat(reader *bufio.Reader) (Format, error) {
+	header, err := reader.Peek(len(pngMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return FormatUnknown, fmt.Errorf("read header: %w", err)
+	}
+
+	format := DetectFormat(header)
+	if format == FormatUnknown {
+		return FormatUnknown, ErrUnsupportedFormat
+	}
+
+	return format, nil
+}
+
+var ErrUnsupportedFormat = errors.New("unsupported image format")
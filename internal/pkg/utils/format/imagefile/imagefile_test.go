@@ -0,0 +1,110 @@
+package imagefile
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	return img
+}
+
+func TestDecodeFormats(t *testing.T) {
+	t.Parallel()
+
+	var pngData bytes.Buffer
+	assert.NoError(t, png.Encode(&pngData, testImage()))
+
+	var jpegData bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&jpegData, testImage(), &jpeg.Options{Quality: 100}))
+
+	ppmData := append([]byte("P6\n2 2\n255\n"), bytes.Repeat([]byte{200, 100, 50}, 4)...)
+
+	testCases := []struct {
+		format Format
+		data   []byte
+	}{
+		{format: FormatPNG, data: pngData.Bytes()},
+		{format: FormatJPEG, data: jpegData.Bytes()},
+		{format: FormatPPM, data: ppmData},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.format.String(), func(t *testing.T) {
+			format, width, height, err := DecodeConfig(bytes.NewReader(testCase.data))
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.format, format)
+			assert.Equal(t, uint32(2), width)
+			assert.Equal(t, uint32(2), height)
+
+			decodedImage, err := Decode(nil, bytes.NewReader(testCase.data))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, testCase.format, decodedImage.Format)
+			assert.Len(t, decodedImage.Pixels, 12)
+			assert.InDelta(t, 200, int(decodedImage.Pixels[0]), 4)
+			assert.InDelta(t, 100, int(decodedImage.Pixels[1]), 4)
+			assert.InDelta(t, 50, int(decodedImage.Pixels[2]), 4)
+		})
+	}
+}
+
+func TestDecodeFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "slate.png")
+
+	file, err := os.Create(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, png.Encode(file, testImage()))
+	assert.NoError(t, file.Close())
+
+	format, width, height, err := DecodeFileConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, FormatPNG, format)
+	assert.Equal(t, uint32(2), width)
+	assert.Equal(t, uint32(2), height)
+
+	decodedImage, err := DecodeFile(nil, path)
+	assert.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte{200, 100, 50}, 4), decodedImage.Pixels)
+}
+
+func TestDecodeRejectsUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := Decode(nil, bytes.NewReader([]byte("GIF89a")))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, _, _, err = DecodeConfig(bytes.NewReader(nil))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestIsSupportedExtension(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsSupportedExtension("a.PNG"))
+	assert.True(t, IsSupportedExtension("a.jpeg"))
+	assert.True(t, IsSupportedExtension("a.jpg"))
+	assert.True(t, IsSupportedExtension("a.ppm"))
+	assert.False(t, IsSupportedExtension("a.gif"))
+}
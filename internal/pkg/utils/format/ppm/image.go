@@ -0,0 +1,63 @@
+package ppm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// maxImageDimension limits size of a single decoded image.
+const maxImageDimension = 16384
+
+// Image holds RGB24 pixels of a single decoded PPM image.
+type Image struct {
+	Width  uint32
+	Height uint32
+	Pixels []byte
+}
+
+// DecodeConfig reads PPM header and returns image dimensions without reading pixel data.
+func DecodeConfig(reader io.Reader) (uint32, uint32, error) {
+	parser := newImageParser(reader)
+
+	header, err := parser.readHeader()
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+	}
+
+	return header.width, header.height, nil
+}
+
+// Decode reads single binary PPM (P6) image. Pixels are appended to dst, which may be nil.
+func Decode(dst []byte, reader io.Reader) (*Image, error) {
+	parser := newImageParser(reader)
+
+	header, err := parser.readHeader()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+	}
+
+	pixels := dst[:0]
+	if cap(pixels) < header.payloadBytes {
+		pixels = make([]byte, header.payloadBytes)
+	}
+	pixels = pixels[:header.payloadBytes]
+
+	if _, err := io.ReadFull(parser.reader, pixels); err != nil {
+		return nil, fmt.Errorf("read payload: %w: %w", ErrIncompleteFrame, err)
+	}
+
+	return &Image{
+		Width:  header.width,
+		Height: header.height,
+		Pixels: pixels,
+	}, nil
+}
+
+func newImageParser(reader io.Reader) *streamParser {
+	return &streamParser{
+		reader:    bufio.NewReader(reader),
+		maxWidth:  maxImageDimension,
+		maxHeight: maxImageDimension,
+	}
+}
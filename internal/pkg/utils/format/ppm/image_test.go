@@ -0,0 +1,54 @@
+package ppm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeImage(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	data := buildPPM(2, 2, payload)
+
+	width, height, err := DecodeConfig(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), width)
+	assert.Equal(t, uint32(2), height)
+
+	image, err := Decode(nil, bytes.NewReader(data))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, uint32(2), image.Width)
+	assert.Equal(t, uint32(2), image.Height)
+	assert.Equal(t, payload, image.Pixels)
+}
+
+func TestDecodeImageReusesDestination(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte{1, 2, 3}
+	dst := make([]byte, 0, 16)
+
+	image, err := Decode(dst, bytes.NewReader(buildPPM(1, 1, payload)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, payload, image.Pixels)
+	assert.Equal(t, &dst[:1][0], &image.Pixels[0])
+}
+
+func TestDecodeImageRejectsInvalidData(t *testing.T) {
+	t.Parallel()
+
+	_, err := Decode(nil, bytes.NewReader([]byte("P3\n1 1\n255\n")))
+	assert.ErrorIs(t, err, ErrInvalidMagic)
+
+	_, err = Decode(nil, bytes.NewReader(buildPPM(2, 2, []byte{1, 2, 3})))
+	assert.ErrorIs(t, err, ErrIncompleteFrame)
+}
@@ -40,6 +40,8 @@ type streamParser struct {
 
 	maxWidth  int
 	maxHeight int
+
+	frameSequence uint64
 }
 
 func WithStreamParserMemoryBufferPool(pool memorySDK.Pool) StreamParserOpt {
@@ -196,7 +198,9 @@ func (parser *streamParser) readPayload(header *ppmHeader) error {
 		return fmt.Errorf("read payload: %w", ErrIncompleteFrame)
 	}
 
-	frameBuffer := peripheralSDK.NewDisplayFrameBuffer(buffer)
+	parser.frameSequence++
+
+	frameBuffer := peripheralSDK.NewDisplayFrameBuffer(buffer, peripheralSDK.WithDisplayFrameBufferSequence(parser.frameSequence))
 	if handlerErr := parser.handler(frameBuffer); handlerErr != nil {
 		_ = buffer.Release()
 		return fmt.Errorf("frame handler: %w", handlerErr)
@@ -0,0 +1,231 @@
+package qoi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Implementation of "Quite OK Image" format (https://qoiformat.org/qoi-specification.pdf) limited to RGB24 pixels,
+// which is the only pixel format used by display frame buffers.
+
+const (
+	headerLength   = 14
+	channelsRGB    = 3
+	colorspaceSRGB = 0
+
+	opIndex = 0x00
+	opDiff  = 0x40
+	opLuma  = 0x80
+	opRun   = 0xc0
+	opRGB   = 0xfe
+	opRGBA  = 0xff
+
+	opMask2 = 0xc0
+
+	maxRunLength = 62
+)
+
+var magic = [4]byte{'q', 'o', 'i', 'f'}
+
+var endMarker = [8]byte{0, 0, 0, 0, 0, 0, 0, 1}
+
+type pixel struct {
+	r, g, b, a byte
+}
+
+func (value pixel) hash() int {
+	return (int(value.r)*3 + int(value.g)*5 + int(value.b)*7 + int(value.a)*11) % 64
+}
+
+// Header describes encoded image.
+type Header struct {
+	Width  uint32
+	Height uint32
+}
+
+// Encode encodes RGB24 pixels into QOI image and appends it to dst.
+func Encode(dst []byte, pixels []byte, width uint32, height uint32) ([]byte, error) {
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("%w: width and height must be greater than zero", ErrInvalidDimensions)
+	}
+
+	pixelCount := int(width) * int(height)
+	if len(pixels) != pixelCount*channelsRGB {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidPixelData, pixelCount*channelsRGB, len(pixels))
+	}
+
+	dst = append(dst, magic[:]...)
+	dst = binary.BigEndian.AppendUint32(dst, width)
+	dst = binary.BigEndian.AppendUint32(dst, height)
+	dst = append(dst, channelsRGB, colorspaceSRGB)
+
+	var index [64]pixel
+	previous := pixel{a: 255}
+	run := 0
+
+	for offset := 0; offset < len(pixels); offset += channelsRGB {
+		current := pixel{r: pixels[offset], g: pixels[offset+1], b: pixels[offset+2], a: 255}
+
+		if current == previous {
+			run++
+			if run == maxRunLength || offset+channelsRGB == len(pixels) {
+				dst = append(dst, opRun|byte(run-1))
+				run = 0
+			}
+			continue
+		}
+
+		if run > 0 {
+			dst = append(dst, opRun|byte(run-1))
+			run = 0
+		}
+
+		hash := current.hash()
+		if index[hash] == current {
+			dst = append(dst, opIndex|byte(hash))
+			previous = current
+			continue
+		}
+		index[hash] = current
+
+		diffRed := int8(current.r - previous.r)
+		diffGreen := int8(current.g - previous.g)
+		diffBlue := int8(current.b - previous.b)
+
+		diffRedGreen := diffRed - diffGreen
+		diffBlueGreen := diffBlue - diffGreen
+
+		switch {
+		case diffRed >= -2 && diffRed <= 1 && diffGreen >= -2 && diffGreen <= 1 && diffBlue >= -2 && diffBlue <= 1:
+			dst = append(dst, opDiff|byte(diffRed+2)<<4|byte(diffGreen+2)<<2|byte(diffBlue+2))
+		case diffGreen >= -32 && diffGreen <= 31 && diffRedGreen >= -8 && diffRedGreen <= 7 && diffBlueGreen >= -8 && diffBlueGreen <= 7:
+			dst = append(dst, opLuma|byte(diffGreen+32), byte(diffRedGreen+8)<<4|byte(diffBlueGreen+8))
+		default:
+			dst = append(dst, opRGB, current.r, current.g, current.b)
+		}
+
+		previous = current
+	}
+
+	dst = append(dst, endMarker[:]...)
+
+	return dst, nil
+}
+
+// DecodeHeader parses header of QOI image.
+func DecodeHeader(data []byte) (*Header, error) {
+	if len(data) < headerLength {
+		return nil, fmt.Errorf("%w: header too short", ErrInvalidHeader)
+	}
+
+	if [4]byte(data[0:4]) != magic {
+		return nil, fmt.Errorf("%w: invalid magic", ErrInvalidHeader)
+	}
+
+	header := &Header{
+		Width:  binary.BigEndian.Uint32(data[4:8]),
+		Height: binary.BigEndian.Uint32(data[8:12]),
+	}
+
+	if header.Width == 0 || header.Height == 0 {
+		return nil, fmt.Errorf("%w: width and height must be greater than zero", ErrInvalidDimensions)
+	}
+
+	channels := data[12]
+	if channels != 3 && channels != 4 {
+		return nil, fmt.Errorf("%w: unsupported channel count %d", ErrInvalidHeader, channels)
+	}
+
+	return header, nil
+}
+
+// Decode decodes QOI image into RGB24 pixels appended to dst. Alpha channel, when present, is discarded.
+func Decode(dst []byte, data []byte) ([]byte, *Header, error) {
+	header, err := DecodeHeader(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pixelCount := int(header.Width) * int(header.Height)
+	dst = growBytes(dst, pixelCount*channelsRGB)
+
+	var index [64]pixel
+	previous := pixel{a: 255}
+	run := 0
+	position := headerLength
+	chunksEnd := len(data) - len(endMarker)
+
+	for pixelIndex := 0; pixelIndex < pixelCount; pixelIndex++ {
+		if run > 0 {
+			run--
+		} else {
+			if position >= chunksEnd {
+				return nil, nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidPixelData)
+			}
+
+			tag := data[position]
+			position++
+
+			switch {
+			case tag == opRGB:
+				if position+3 > chunksEnd {
+					return nil, nil, fmt.Errorf("%w: truncated rgb chunk", ErrInvalidPixelData)
+				}
+				previous.r, previous.g, previous.b = data[position], data[position+1], data[position+2]
+				position += 3
+			case tag == opRGBA:
+				if position+4 > chunksEnd {
+					return nil, nil, fmt.Errorf("%w: truncated rgba chunk", ErrInvalidPixelData)
+				}
+				previous = pixel{r: data[position], g: data[position+1], b: data[position+2], a: data[position+3]}
+				position += 4
+			case tag&opMask2 == opIndex:
+				previous = index[tag]
+			case tag&opMask2 == opDiff:
+				previous.r += (tag>>4)&0x03 - 2
+				previous.g += (tag>>2)&0x03 - 2
+				previous.b += tag&0x03 - 2
+			case tag&opMask2 == opLuma:
+				if position >= chunksEnd {
+					return nil, nil, fmt.Errorf("%w: truncated luma chunk", ErrInvalidPixelData)
+				}
+				next := data[position]
+				position++
+
+				diffGreen := (tag & 0x3f) - 32
+				previous.r += diffGreen - 8 + (next>>4)&0x0f
+				previous.g += diffGreen
+				previous.b += diffGreen - 8 + next&0x0f
+			case tag&opMask2 == opRun:
+				run = int(tag & 0x3f)
+			}
+
+			index[previous.hash()] = previous
+		}
+
+		offset := len(dst) - (pixelCount-pixelIndex)*channelsRGB
+		dst[offset] = previous.r
+		dst[offset+1] = previous.g
+		dst[offset+2] = previous.b
+	}
+
+	return dst, header, nil
+}
+
+func growBytes(dst []byte, size int) []byte {
+	length := len(dst)
+	if cap(dst)-length < size {
+		grown := make([]byte, length, length+size)
+		copy(grown, dst)
+		dst = grown
+	}
+
+	return dst[:length+size]
+}
+
+var (
+	ErrInvalidHeader     = errors.New("invalid qoi header")
+	ErrInvalidDimensions = errors.New("invalid qoi dimensions")
+	ErrInvalidPixelData  = errors.New("invalid qoi pixel data")
+)
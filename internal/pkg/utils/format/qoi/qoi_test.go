@@ -0,0 +1,151 @@
+package qoi
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	random := rand.New(rand.NewSource(42))
+
+	testCases := []struct {
+		name   string
+		width  uint32
+		height uint32
+		fill   func(pixels []byte)
+	}{
+		{
+			name:   "solid color",
+			width:  64,
+			height: 32,
+			fill: func(pixels []byte) {
+				for i := 0; i < len(pixels); i += 3 {
+					pixels[i], pixels[i+1], pixels[i+2] = 10, 20, 30
+				}
+			},
+		},
+		{
+			name:   "gradient",
+			width:  256,
+			height: 4,
+			fill: func(pixels []byte) {
+				for i := 0; i < len(pixels); i += 3 {
+					value := byte(i / 3)
+					pixels[i], pixels[i+1], pixels[i+2] = value, value+1, value+3
+				}
+			},
+		},
+		{
+			name:   "random noise",
+			width:  31,
+			height: 17,
+			fill: func(pixels []byte) {
+				random.Read(pixels)
+			},
+		},
+		{
+			name:   "repeating palette",
+			width:  100,
+			height: 3,
+			fill: func(pixels []byte) {
+				palette := [][3]byte{{255, 0, 0}, {0, 255, 0}, {0, 0, 255}, {255, 255, 255}}
+				for i := 0; i < len(pixels); i += 3 {
+					color := palette[(i/3)%len(palette)]
+					pixels[i], pixels[i+1], pixels[i+2] = color[0], color[1], color[2]
+				}
+			},
+		},
+		{
+			name:   "single pixel",
+			width:  1,
+			height: 1,
+			fill: func(pixels []byte) {
+				pixels[0], pixels[1], pixels[2] = 0, 0, 0
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			pixels := make([]byte, testCase.width*testCase.height*3)
+			testCase.fill(pixels)
+
+			encoded, err := Encode(nil, pixels, testCase.width, testCase.height)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			decoded, header, err := Decode(nil, encoded)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, testCase.width, header.Width)
+			assert.Equal(t, testCase.height, header.Height)
+			assert.Equal(t, pixels, decoded)
+		})
+	}
+}
+
+func TestEncodeCompressesSolidColor(t *testing.T) {
+	t.Parallel()
+
+	pixels := make([]byte, 1920*1080*3)
+
+	encoded, err := Encode(nil, pixels, 1920, 1080)
+	assert.NoError(t, err)
+	assert.Less(t, len(encoded), len(pixels)/50)
+}
+
+func TestEncodeWritesHeader(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := Encode([]byte{0xaa}, []byte{1, 2, 3}, 1, 1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, byte(0xaa), encoded[0])
+	assert.Equal(t, []byte("qoif"), encoded[1:5])
+	assert.Equal(t, []byte{0, 0, 0, 1, 0, 0, 0, 1, 3, 0}, encoded[5:15])
+	assert.Equal(t, endMarker[:], encoded[len(encoded)-8:])
+}
+
+func TestEncodeRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	_, err := Encode(nil, []byte{1, 2, 3}, 0, 1)
+	assert.ErrorIs(t, err, ErrInvalidDimensions)
+
+	_, err = Encode(nil, []byte{1, 2}, 1, 1)
+	assert.ErrorIs(t, err, ErrInvalidPixelData)
+}
+
+func TestDecodeRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := Decode(nil, []byte("qoif"))
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+
+	_, _, err = Decode(nil, []byte("abcd\x00\x00\x00\x01\x00\x00\x00\x01\x03\x00"))
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+
+	encoded, err := Encode(nil, []byte{1, 2, 3, 200, 100, 50}, 2, 1)
+	assert.NoError(t, err)
+
+	_, _, err = Decode(nil, encoded[:headerLength+2])
+	assert.ErrorIs(t, err, ErrInvalidPixelData)
+}
+
+func TestDecodeAppendsToDestination(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := Encode(nil, []byte{1, 2, 3}, 1, 1)
+	assert.NoError(t, err)
+
+	decoded, _, err := Decode([]byte{9}, encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{9, 1, 2, 3}, decoded)
+}
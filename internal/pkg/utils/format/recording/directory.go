@@ -0,0 +1,77 @@
+package recording
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SegmentInfo describes segment stored in recording directory.
+type SegmentInfo struct {
+	Name       string    `json:"name"`
+	FramesPath string    `json:"framesPath"`
+	IndexPath  string    `json:"indexPath"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+}
+
+// SegmentName returns name of a segment started at given time. Names sort in chronological order.
+func SegmentName(startedAt time.Time) string {
+	return fmt.Sprintf("segment-%020d", startedAt.UnixNano())
+}
+
+// ListSegments returns segments stored in directory sorted from the oldest to the newest.
+func ListSegments(directory string) ([]SegmentInfo, error) {
+	dirEntries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("read directory: %w", err)
+	}
+
+	segments := make([]SegmentInfo, 0)
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), FramesFileExtension) {
+			continue
+		}
+
+		name := strings.TrimSuffix(dirEntry.Name(), FramesFileExtension)
+
+		segment := SegmentInfo{
+			Name:       name,
+			FramesPath: filepath.Join(directory, name+FramesFileExtension),
+			IndexPath:  filepath.Join(directory, name+IndexFileExtension),
+		}
+
+		if stat, err := os.Stat(segment.FramesPath); err == nil {
+			segment.Size += stat.Size()
+			segment.ModifiedAt = stat.ModTime()
+		}
+
+		if stat, err := os.Stat(segment.IndexPath); err == nil {
+			segment.Size += stat.Size()
+		}
+
+		segments = append(segments, segment)
+	}
+
+	slices.SortFunc(segments, func(a, b SegmentInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return segments, nil
+}
+
+// RemoveSegment removes segment files.
+func RemoveSegment(segment SegmentInfo) error {
+	if err := os.Remove(segment.FramesPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove frames file: %w", err)
+	}
+
+	if err := os.Remove(segment.IndexPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove index file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package recording
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type PlayerOptions struct {
+	speed  float64
+	loop   bool
+	paused bool
+	clock  func() time.Time
+}
+
+type PlayerOpt func(*PlayerOptions)
+
+func defaultPlayerOptions() PlayerOptions {
+	return PlayerOptions{
+		speed: 1,
+		clock: time.Now,
+	}
+}
+
+func WithPlayerSpeed(speed float64) PlayerOpt {
+	return func(options *PlayerOptions) {
+		options.speed = speed
+	}
+}
+
+func WithPlayerLoop(loop bool) PlayerOpt {
+	return func(options *PlayerOptions) {
+		options.loop = loop
+	}
+}
+
+func WithPlayerPaused(paused bool) PlayerOpt {
+	return func(options *PlayerOptions) {
+		options.paused = paused
+	}
+}
+
+// WithPlayerClock replaces time source used to advance playback position.
+func WithPlayerClock(clock func() time.Time) PlayerOpt {
+	return func(options *PlayerOptions) {
+		options.clock = clock
+	}
+}
+
+// Player tracks playback position over a timeline. Position advances with the clock multiplied by speed, and is
+// re-anchored every time speed, pause or position changes.
+type Player struct {
+	timeline *Timeline
+	clock    func() time.Time
+
+	speed  float64
+	loop   bool
+	paused bool
+
+	anchorPosition time.Duration
+	anchorTime     time.Time
+
+	lock sync.Mutex
+}
+
+func NewPlayer(timeline *Timeline, opts ...PlayerOpt) (*Player, error) {
+	options := defaultPlayerOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.speed <= 0 {
+		return nil, fmt.Errorf("%w: %v", peripheralSDK.ErrDisplayPlaybackSpeedInvalid, options.speed)
+	}
+
+	return &Player{
+		timeline: timeline,
+		clock:    options.clock,
+
+		speed:  options.speed,
+		loop:   options.loop,
+		paused: options.paused,
+
+		anchorTime: options.clock(),
+	}, nil
+}
+
+// GetFrameIndex returns index of the timeline frame which should be shown now.
+func (player *Player) GetFrameIndex() int {
+	player.lock.Lock()
+	defer player.lock.Unlock()
+
+	return player.timeline.FrameIndexAt(player.position(player.clock()))
+}
+
+func (player *Player) GetState() peripheralSDK.DisplayPlaybackState {
+	player.lock.Lock()
+	defer player.lock.Unlock()
+
+	position := player.position(player.clock())
+
+	return peripheralSDK.DisplayPlaybackState{
+		Position:   position,
+		Duration:   player.timeline.GetDuration(),
+		FrameIndex: player.timeline.FrameIndexAt(position),
+		FrameCount: player.timeline.GetFrameCount(),
+		Speed:      player.speed,
+		Loop:       player.loop,
+		Paused:     player.paused,
+	}
+}
+
+func (player *Player) Seek(position time.Duration) error {
+	if position < 0 || position > player.timeline.GetDuration() {
+		return fmt.Errorf("%w: %s", peripheralSDK.ErrDisplayPlaybackPositionOutOfRange, position)
+	}
+
+	player.lock.Lock()
+	defer player.lock.Unlock()
+
+	player.anchorPosition = position
+	player.anchorTime = player.clock()
+
+	return nil
+}
+
+func (player *Player) SetSpeed(speed float64) error {
+	if speed <= 0 {
+		return fmt.Errorf("%w: %v", peripheralSDK.ErrDisplayPlaybackSpeedInvalid, speed)
+	}
+
+	player.lock.Lock()
+	defer player.lock.Unlock()
+
+	player.reanchor()
+	player.speed = speed
+
+	return nil
+}
+
+func (player *Player) SetLoop(loop bool) {
+	player.lock.Lock()
+	defer player.lock.Unlock()
+
+	player.reanchor()
+	player.loop = loop
+}
+
+func (player *Player) SetPaused(paused bool) {
+	player.lock.Lock()
+	defer player.lock.Unlock()
+
+	player.reanchor()
+	player.paused = paused
+}
+
+func (player *Player) reanchor() {
+	now := player.clock()
+
+	player.anchorPosition = player.position(now)
+	player.anchorTime = now
+}
+
+func (player *Player) position(now time.Time) time.Duration {
+	position := player.anchorPosition
+	if !player.paused {
+		position += time.Duration(float64(now.Sub(player.anchorTime)) * player.speed)
+	}
+
+	duration := player.timeline.GetDuration()
+	if position < duration {
+		return position
+	}
+
+	if player.loop {
+		return position % duration
+	}
+
+	return duration
+}
@@ -0,0 +1,125 @@
+package recording
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (clock *testClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *testClock) Advance(duration time.Duration) {
+	clock.now = clock.now.Add(duration)
+}
+
+// newTestTimeline returns timeline with frames every 100ms and total duration of one second.
+func newTestTimeline(t *testing.T) *Timeline {
+	t.Helper()
+
+	basePath := filepath.Join(t.TempDir(), "segment")
+	startedAt := time.Unix(1700000000, 0)
+
+	writer, err := CreateSegment(basePath, SegmentHeader{
+		Codec:       CodecRaw,
+		DisplayMode: peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 10},
+		StartedAt:   startedAt,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, writer.WriteFrame(uint64(i+1), startedAt.Add(time.Duration(i)*100*time.Millisecond), testFramePixels(byte(i))))
+	}
+	assert.NoError(t, writer.Close())
+
+	timeline, err := OpenSegmentTimeline(filepath.Dir(basePath))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		_ = timeline.Close()
+	})
+
+	return timeline
+}
+
+func TestPlayerAdvancesWithSpeed(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(0, 0)}
+
+	player, err := NewPlayer(newTestTimeline(t), WithPlayerClock(clock.Now))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 0, player.GetFrameIndex())
+
+	clock.Advance(250 * time.Millisecond)
+	assert.Equal(t, 2, player.GetFrameIndex())
+
+	assert.NoError(t, player.SetSpeed(2))
+	clock.Advance(250 * time.Millisecond)
+
+	state := player.GetState()
+	assert.Equal(t, 750*time.Millisecond, state.Position)
+	assert.Equal(t, 7, state.FrameIndex)
+	assert.Equal(t, 10, state.FrameCount)
+	assert.Equal(t, time.Second, state.Duration)
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, time.Second, player.GetState().Position)
+	assert.Equal(t, 9, player.GetFrameIndex())
+
+	assert.ErrorIs(t, player.SetSpeed(0), peripheralSDK.ErrDisplayPlaybackSpeedInvalid)
+}
+
+func TestPlayerLoops(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(0, 0)}
+
+	player, err := NewPlayer(newTestTimeline(t), WithPlayerClock(clock.Now), WithPlayerLoop(true))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	clock.Advance(1350 * time.Millisecond)
+	assert.Equal(t, 350*time.Millisecond, player.GetState().Position)
+	assert.Equal(t, 3, player.GetFrameIndex())
+}
+
+func TestPlayerSeekAndPause(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(0, 0)}
+
+	player, err := NewPlayer(newTestTimeline(t), WithPlayerClock(clock.Now))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, player.Seek(600*time.Millisecond))
+	player.SetPaused(true)
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, 6, player.GetFrameIndex())
+	assert.True(t, player.GetState().Paused)
+
+	player.SetPaused(false)
+	clock.Advance(100 * time.Millisecond)
+	assert.Equal(t, 7, player.GetFrameIndex())
+
+	assert.ErrorIs(t, player.Seek(-time.Millisecond), peripheralSDK.ErrDisplayPlaybackPositionOutOfRange)
+	assert.ErrorIs(t, player.Seek(2*time.Second), peripheralSDK.ErrDisplayPlaybackPositionOutOfRange)
+}
@@ -0,0 +1,248 @@
+package recording
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type RecorderOptions struct {
+	segmentMaxDuration time.Duration
+	segmentMaxSize     int64
+	maxDuration        time.Duration
+	maxSize            int64
+	clock              func() time.Time
+	logger             *slog.Logger
+}
+
+type RecorderOpt func(*RecorderOptions)
+
+func defaultRecorderOptions() RecorderOptions {
+	return RecorderOptions{
+		segmentMaxDuration: time.Minute,
+		segmentMaxSize:     512 * 1024 * 1024,
+		clock:              time.Now,
+		logger:             slog.New(slog.DiscardHandler),
+	}
+}
+
+// WithRecorderSegmentMaxDuration sets duration after which segment is rotated.
+func WithRecorderSegmentMaxDuration(duration time.Duration) RecorderOpt {
+	return func(options *RecorderOptions) {
+		options.segmentMaxDuration = duration
+	}
+}
+
+// WithRecorderSegmentMaxSize sets size in bytes after which segment is rotated.
+func WithRecorderSegmentMaxSize(size int64) RecorderOpt {
+	return func(options *RecorderOptions) {
+		options.segmentMaxSize = size
+	}
+}
+
+// WithRecorderMaxDuration sets retention period. Segments last modified earlier are removed. Zero disables it.
+func WithRecorderMaxDuration(duration time.Duration) RecorderOpt {
+	return func(options *RecorderOptions) {
+		options.maxDuration = duration
+	}
+}
+
+// WithRecorderMaxSize sets total size of the recording in bytes. Oldest segments are removed to stay within the
+// limit. Zero disables it.
+func WithRecorderMaxSize(size int64) RecorderOpt {
+	return func(options *RecorderOptions) {
+		options.maxSize = size
+	}
+}
+
+// WithRecorderClock replaces time source used to apply retention.
+func WithRecorderClock(clock func() time.Time) RecorderOpt {
+	return func(options *RecorderOptions) {
+		options.clock = clock
+	}
+}
+
+func WithRecorderLogger(logger *slog.Logger) RecorderOpt {
+	return func(options *RecorderOptions) {
+		options.logger = logger
+	}
+}
+
+// Recorder writes frames into rotating segments stored in a directory and enforces retention limits.
+type Recorder struct {
+	directory string
+	codec     Codec
+	options   RecorderOptions
+
+	segment *SegmentWriter
+	closed  bool
+}
+
+func NewRecorder(directory string, codec Codec, opts ...RecorderOpt) (*Recorder, error) {
+	if err := codec.Valid(); err != nil {
+		return nil, err
+	}
+
+	options := defaultRecorderOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.segmentMaxDuration <= 0 {
+		return nil, fmt.Errorf("%w: segment max duration must be greater than zero", ErrInvalidRecorderConfiguration)
+	}
+
+	if options.segmentMaxSize <= 0 {
+		return nil, fmt.Errorf("%w: segment max size must be greater than zero", ErrInvalidRecorderConfiguration)
+	}
+
+	if options.maxDuration < 0 || options.maxSize < 0 {
+		return nil, fmt.Errorf("%w: retention limits must not be negative", ErrInvalidRecorderConfiguration)
+	}
+
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, fmt.Errorf("create recording directory: %w", err)
+	}
+
+	return &Recorder{
+		directory: directory,
+		codec:     codec,
+		options:   options,
+	}, nil
+}
+
+// WriteFrame stores frame in the active segment. New segment is started when display mode changes or active
+// segment exceeds configured limits.
+func (recorder *Recorder) WriteFrame(displayMode peripheralSDK.DisplayMode, sequence uint64, timestamp time.Time, pixels []byte) error {
+	if recorder.closed {
+		return ErrRecorderClosed
+	}
+
+	if recorder.shouldRotate(displayMode, timestamp) {
+		if err := recorder.rotate(displayMode, timestamp); err != nil {
+			return fmt.Errorf("rotate segment: %w", err)
+		}
+	}
+
+	return recorder.segment.WriteFrame(sequence, timestamp, pixels)
+}
+
+func (recorder *Recorder) shouldRotate(displayMode peripheralSDK.DisplayMode, timestamp time.Time) bool {
+	if recorder.segment == nil {
+		return true
+	}
+
+	if recorder.segment.GetHeader().DisplayMode != displayMode {
+		return true
+	}
+
+	if recorder.segment.GetSize() >= recorder.options.segmentMaxSize {
+		return true
+	}
+
+	return timestamp.Sub(recorder.segment.GetHeader().StartedAt) >= recorder.options.segmentMaxDuration
+}
+
+func (recorder *Recorder) rotate(displayMode peripheralSDK.DisplayMode, timestamp time.Time) error {
+	if err := recorder.closeSegment(); err != nil {
+		return err
+	}
+
+	basePath := filepath.Join(recorder.directory, SegmentName(timestamp))
+
+	segment, err := CreateSegment(basePath, SegmentHeader{
+		Codec:       recorder.codec,
+		DisplayMode: displayMode,
+		StartedAt:   timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("create segment: %w", err)
+	}
+
+	recorder.segment = segment
+
+	recorder.options.logger.Debug("Recording segment started.",
+		slog.String("segmentPath", basePath),
+		slog.String("displayMode", displayMode.String()),
+	)
+
+	return recorder.applyRetention(filepath.Base(basePath))
+}
+
+func (recorder *Recorder) applyRetention(activeSegmentName string) error {
+	if recorder.options.maxDuration == 0 && recorder.options.maxSize == 0 {
+		return nil
+	}
+
+	segments, err := ListSegments(recorder.directory)
+	if err != nil {
+		return fmt.Errorf("list segments: %w", err)
+	}
+
+	var totalSize int64
+	for _, segment := range segments {
+		totalSize += segment.Size
+	}
+
+	now := recorder.options.clock()
+
+	for _, segment := range segments {
+		if segment.Name == activeSegmentName {
+			break
+		}
+
+		expired := recorder.options.maxDuration > 0 && now.Sub(segment.ModifiedAt) > recorder.options.maxDuration
+		oversized := recorder.options.maxSize > 0 && totalSize > recorder.options.maxSize
+
+		if !expired && !oversized {
+			break
+		}
+
+		if err := RemoveSegment(segment); err != nil {
+			return fmt.Errorf("remove segment %s: %w", segment.Name, err)
+		}
+
+		totalSize -= segment.Size
+
+		recorder.options.logger.Debug("Recording segment removed by retention.", slog.String("segmentName", segment.Name))
+	}
+
+	return nil
+}
+
+func (recorder *Recorder) closeSegment() error {
+	if recorder.segment == nil {
+		return nil
+	}
+
+	err := recorder.segment.Close()
+	recorder.segment = nil
+
+	if err != nil {
+		return fmt.Errorf("close segment: %w", err)
+	}
+
+	return nil
+}
+
+// FinishSegment finishes the active segment, the next frame starts a new one.
+func (recorder *Recorder) FinishSegment() error {
+	return recorder.closeSegment()
+}
+
+// Close finishes the active segment, frames written afterwards are rejected with ErrRecorderClosed.
+func (recorder *Recorder) Close() error {
+	recorder.closed = true
+
+	return recorder.closeSegment()
+}
+
+var (
+	ErrInvalidRecorderConfiguration = errors.New("invalid recorder configuration")
+	ErrRecorderClosed               = errors.New("recorder closed")
+)
@@ -0,0 +1,182 @@
+package recording
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func TestNewRecorderRejectsInvalidConfiguration(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	_, err := NewRecorder(directory, "h264")
+	assert.ErrorIs(t, err, ErrUnsupportedCodec)
+
+	_, err = NewRecorder(directory, CodecRaw, WithRecorderSegmentMaxDuration(0))
+	assert.ErrorIs(t, err, ErrInvalidRecorderConfiguration)
+
+	_, err = NewRecorder(directory, CodecRaw, WithRecorderSegmentMaxSize(0))
+	assert.ErrorIs(t, err, ErrInvalidRecorderConfiguration)
+
+	_, err = NewRecorder(directory, CodecRaw, WithRecorderMaxSize(-1))
+	assert.ErrorIs(t, err, ErrInvalidRecorderConfiguration)
+}
+
+func TestRecorderRotatesOnDurationAndDisplayModeChange(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	recorder, err := NewRecorder(directory, CodecQOI, WithRecorderSegmentMaxDuration(time.Second))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	startedAt := time.Unix(1700000000, 0)
+
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 1, startedAt, testFramePixels(1)))
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 2, startedAt.Add(500*time.Millisecond), testFramePixels(2)))
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 3, startedAt.Add(time.Second), testFramePixels(3)))
+
+	otherDisplayMode := peripheralSDK.DisplayMode{Width: 2, Height: 2, RefreshRate: 30}
+	assert.NoError(t, recorder.WriteFrame(otherDisplayMode, 4, startedAt.Add(1100*time.Millisecond), make([]byte, 12)))
+
+	assert.NoError(t, recorder.Close())
+
+	segments, err := ListSegments(directory)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, segments, 3) {
+		return
+	}
+
+	expectedFrameCounts := []int{2, 1, 1}
+	expectedDisplayModes := []peripheralSDK.DisplayMode{testDisplayMode, testDisplayMode, otherDisplayMode}
+
+	for i, segment := range segments {
+		reader, err := OpenSegment(segment.FramesPath)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, expectedFrameCounts[i], reader.GetFrameCount())
+		assert.Equal(t, expectedDisplayModes[i], reader.GetHeader().DisplayMode)
+		assert.NoError(t, reader.Close())
+	}
+}
+
+func TestRecorderRotatesOnSegmentSize(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	recorder, err := NewRecorder(directory, CodecRaw, WithRecorderSegmentMaxSize(200))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	startedAt := time.Unix(1700000000, 0)
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, recorder.WriteFrame(testDisplayMode, uint64(i+1), startedAt.Add(time.Duration(i)*time.Millisecond), testFramePixels(byte(i))))
+	}
+	assert.NoError(t, recorder.Close())
+
+	segments, err := ListSegments(directory)
+	assert.NoError(t, err)
+	assert.Len(t, segments, 2)
+}
+
+func TestRecorderRejectsFramesAfterClose(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	recorder, err := NewRecorder(directory, CodecRaw)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	startedAt := time.Unix(1700000000, 0)
+
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 1, startedAt, testFramePixels(1)))
+	assert.NoError(t, recorder.FinishSegment())
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 2, startedAt.Add(time.Millisecond), testFramePixels(2)))
+	assert.NoError(t, recorder.Close())
+
+	err = recorder.WriteFrame(testDisplayMode, 3, startedAt.Add(2*time.Millisecond), testFramePixels(3))
+	assert.ErrorIs(t, err, ErrRecorderClosed)
+
+	segments, err := ListSegments(directory)
+	assert.NoError(t, err)
+	assert.Len(t, segments, 2)
+}
+
+func TestRecorderAppliesSizeRetention(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	recorder, err := NewRecorder(directory, CodecRaw,
+		WithRecorderSegmentMaxDuration(time.Millisecond),
+		WithRecorderMaxSize(250),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	startedAt := time.Unix(1700000000, 0)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, recorder.WriteFrame(testDisplayMode, uint64(i+1), startedAt.Add(time.Duration(i)*time.Second), testFramePixels(byte(i))))
+	}
+	assert.NoError(t, recorder.Close())
+
+	segments, err := ListSegments(directory)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, segments, 2)
+	assert.Equal(t, SegmentName(startedAt.Add(4*time.Second)), segments[len(segments)-1].Name)
+}
+
+func TestRecorderAppliesDurationRetention(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	now := time.Now()
+
+	recorder, err := NewRecorder(directory, CodecRaw,
+		WithRecorderSegmentMaxDuration(time.Millisecond),
+		WithRecorderMaxDuration(time.Hour),
+		WithRecorderClock(func() time.Time { return now }),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	startedAt := time.Unix(1700000000, 0)
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 1, startedAt, testFramePixels(1)))
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 2, startedAt.Add(time.Second), testFramePixels(2)))
+
+	segments, err := ListSegments(directory)
+	if !assert.NoError(t, err) || !assert.Len(t, segments, 2) {
+		return
+	}
+
+	oldModificationTime := now.Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(segments[0].FramesPath, oldModificationTime, oldModificationTime))
+
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 3, startedAt.Add(2*time.Second), testFramePixels(3)))
+	assert.NoError(t, recorder.Close())
+
+	segments, err = ListSegments(directory)
+	assert.NoError(t, err)
+	assert.Len(t, segments, 2)
+	assert.Equal(t, SegmentName(startedAt.Add(time.Second)), segments[0].Name)
+}
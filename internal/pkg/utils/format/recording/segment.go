@@ -0,0 +1,242 @@
+package recording
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/qoi"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// Recording is stored as a directory of segments. Every segment consists of two files:
+//
+//   - "<name>.frames" - segment header followed by frame records (record header + encoded RGB24 payload),
+//   - "<name>.index" - index header followed by fixed size entries pointing to frame records.
+//
+// Frame records are self-describing, so the index can be rebuilt from the frames file when it is missing or
+// truncated (e.g. after a crash). All integers are stored in big endian order.
+
+const (
+	FramesFileExtension = ".frames"
+	IndexFileExtension  = ".index"
+
+	formatVersion = 1
+
+	segmentHeaderLength = 28
+	indexHeaderLength   = 8
+	indexEntryLength    = 28
+	frameHeaderLength   = 20
+)
+
+var (
+	segmentMagic = [4]byte{'O', 'Q', 'R', 'F'}
+	indexMagic   = [4]byte{'O', 'Q', 'R', 'I'}
+)
+
+// Codec defines how frame payload is stored.
+type Codec string
+
+const (
+	// CodecUnknown represents an uninitialized or invalid codec.
+	CodecUnknown Codec = ""
+	// CodecRaw stores RGB24 pixels as is.
+	CodecRaw Codec = "raw"
+	// CodecQOI stores RGB24 pixels compressed with QOI.
+	CodecQOI Codec = "qoi"
+)
+
+func (codec Codec) String() string {
+	return string(codec)
+}
+
+func (codec Codec) Valid() error {
+	switch codec {
+	case CodecRaw, CodecQOI:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedCodec, string(codec))
+	}
+}
+
+func (codec Codec) id() byte {
+	switch codec {
+	case CodecRaw:
+		return 1
+	case CodecQOI:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func codecFromId(id byte) (Codec, error) {
+	switch id {
+	case 1:
+		return CodecRaw, nil
+	case 2:
+		return CodecQOI, nil
+	default:
+		return CodecUnknown, fmt.Errorf("%w: id %d", ErrUnsupportedCodec, id)
+	}
+}
+
+func (codec Codec) encode(dst []byte, pixels []byte, displayMode peripheralSDK.DisplayMode) ([]byte, error) {
+	switch codec {
+	case CodecRaw:
+		return append(dst, pixels...), nil
+	case CodecQOI:
+		return qoi.Encode(dst, pixels, displayMode.Width, displayMode.Height)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCodec, string(codec))
+	}
+}
+
+func (codec Codec) decode(dst []byte, payload []byte, displayMode peripheralSDK.DisplayMode) ([]byte, error) {
+	switch codec {
+	case CodecRaw:
+		return append(dst, payload...), nil
+	case CodecQOI:
+		decoded, header, err := qoi.Decode(dst, payload)
+		if err != nil {
+			return nil, err
+		}
+		if header.Width != displayMode.Width || header.Height != displayMode.Height {
+			return nil, fmt.Errorf("%w: frame %dx%d does not match segment %s", ErrCorruptedSegment, header.Width, header.Height, displayMode.String())
+		}
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCodec, string(codec))
+	}
+}
+
+// SegmentHeader describes frames stored in a single segment.
+type SegmentHeader struct {
+	Codec       Codec                     `json:"codec"`
+	DisplayMode peripheralSDK.DisplayMode `json:"displayMode"`
+	StartedAt   time.Time                 `json:"startedAt"`
+}
+
+func (header SegmentHeader) marshal() []byte {
+	data := make([]byte, 0, segmentHeaderLength)
+	data = append(data, segmentMagic[:]...)
+	data = append(data, formatVersion, header.Codec.id(), 0, 0)
+	data = binary.BigEndian.AppendUint32(data, header.DisplayMode.Width)
+	data = binary.BigEndian.AppendUint32(data, header.DisplayMode.Height)
+	data = binary.BigEndian.AppendUint32(data, header.DisplayMode.RefreshRate)
+	data = binary.BigEndian.AppendUint64(data, uint64(header.StartedAt.UnixNano()))
+
+	return data
+}
+
+func unmarshalSegmentHeader(data []byte) (*SegmentHeader, error) {
+	if len(data) < segmentHeaderLength {
+		return nil, fmt.Errorf("%w: segment header too short", ErrCorruptedSegment)
+	}
+
+	if [4]byte(data[0:4]) != segmentMagic {
+		return nil, fmt.Errorf("%w: invalid segment magic", ErrCorruptedSegment)
+	}
+
+	if data[4] != formatVersion {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedVersion, data[4])
+	}
+
+	codec, err := codecFromId(data[5])
+	if err != nil {
+		return nil, err
+	}
+
+	header := &SegmentHeader{
+		Codec: codec,
+		DisplayMode: peripheralSDK.DisplayMode{
+			Width:       binary.BigEndian.Uint32(data[8:12]),
+			Height:      binary.BigEndian.Uint32(data[12:16]),
+			RefreshRate: binary.BigEndian.Uint32(data[16:20]),
+		},
+		StartedAt: time.Unix(0, int64(binary.BigEndian.Uint64(data[20:28]))),
+	}
+
+	if err := header.DisplayMode.Valid(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorruptedSegment, err)
+	}
+
+	return header, nil
+}
+
+// IndexEntry points to a single frame record in the frames file.
+type IndexEntry struct {
+	Sequence  uint64    `json:"sequence"`
+	Timestamp time.Time `json:"timestamp"`
+	Offset    int64     `json:"offset"`
+	Size      uint32    `json:"size"`
+}
+
+func (entry IndexEntry) marshal() []byte {
+	data := make([]byte, 0, indexEntryLength)
+	data = binary.BigEndian.AppendUint64(data, entry.Sequence)
+	data = binary.BigEndian.AppendUint64(data, uint64(entry.Timestamp.UnixNano()))
+	data = binary.BigEndian.AppendUint64(data, uint64(entry.Offset))
+	data = binary.BigEndian.AppendUint32(data, entry.Size)
+
+	return data
+}
+
+func unmarshalIndexEntry(data []byte) IndexEntry {
+	return IndexEntry{
+		Sequence:  binary.BigEndian.Uint64(data[0:8]),
+		Timestamp: time.Unix(0, int64(binary.BigEndian.Uint64(data[8:16]))),
+		Offset:    int64(binary.BigEndian.Uint64(data[16:24])),
+		Size:      binary.BigEndian.Uint32(data[24:28]),
+	}
+}
+
+func marshalIndexHeader() []byte {
+	data := make([]byte, 0, indexHeaderLength)
+	data = append(data, indexMagic[:]...)
+	data = append(data, formatVersion, 0, 0, 0)
+
+	return data
+}
+
+func validateIndexHeader(data []byte) error {
+	if len(data) < indexHeaderLength {
+		return fmt.Errorf("%w: index header too short", ErrCorruptedIndex)
+	}
+
+	if [4]byte(data[0:4]) != indexMagic {
+		return fmt.Errorf("%w: invalid index magic", ErrCorruptedIndex)
+	}
+
+	if data[4] != formatVersion {
+		return fmt.Errorf("%w: version %d", ErrUnsupportedVersion, data[4])
+	}
+
+	return nil
+}
+
+func marshalFrameHeader(sequence uint64, timestamp time.Time, size uint32) []byte {
+	data := make([]byte, 0, frameHeaderLength)
+	data = binary.BigEndian.AppendUint64(data, sequence)
+	data = binary.BigEndian.AppendUint64(data, uint64(timestamp.UnixNano()))
+	data = binary.BigEndian.AppendUint32(data, size)
+
+	return data
+}
+
+func unmarshalFrameHeader(data []byte, offset int64) IndexEntry {
+	return IndexEntry{
+		Sequence:  binary.BigEndian.Uint64(data[0:8]),
+		Timestamp: time.Unix(0, int64(binary.BigEndian.Uint64(data[8:16]))),
+		Offset:    offset,
+		Size:      binary.BigEndian.Uint32(data[16:20]),
+	}
+}
+
+var (
+	ErrUnsupportedCodec   = errors.New("unsupported recording codec")
+	ErrUnsupportedVersion = errors.New("unsupported recording format version")
+	ErrCorruptedSegment   = errors.New("corrupted recording segment")
+	ErrCorruptedIndex     = errors.New("corrupted recording index")
+)
@@ -0,0 +1,170 @@
+package recording
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SegmentReader provides random access to frames stored in a segment.
+type SegmentReader struct {
+	header  SegmentHeader
+	entries []IndexEntry
+
+	framesFile   *os.File
+	payloadBlock []byte
+}
+
+// OpenSegment opens segment stored at path. Path may point to frames file, index file or be given without extension.
+// When the index file is missing or does not cover all frames, the index is rebuilt from the frames file.
+func OpenSegment(path string) (*SegmentReader, error) {
+	basePath := strings.TrimSuffix(strings.TrimSuffix(path, FramesFileExtension), IndexFileExtension)
+
+	framesFile, err := os.Open(basePath + FramesFileExtension)
+	if err != nil {
+		return nil, fmt.Errorf("open frames file: %w", err)
+	}
+
+	headerData := make([]byte, segmentHeaderLength)
+	if _, err := io.ReadFull(framesFile, headerData); err != nil {
+		_ = framesFile.Close()
+		return nil, fmt.Errorf("%w: read segment header: %w", ErrCorruptedSegment, err)
+	}
+
+	header, err := unmarshalSegmentHeader(headerData)
+	if err != nil {
+		_ = framesFile.Close()
+		return nil, err
+	}
+
+	reader := &SegmentReader{
+		header:     *header,
+		framesFile: framesFile,
+	}
+
+	entries, indexErr := readIndex(basePath + IndexFileExtension)
+	if indexErr != nil || !reader.indexCoversFramesFile(entries) {
+		entries, err = reader.scanFrames()
+		if err != nil {
+			_ = framesFile.Close()
+			return nil, fmt.Errorf("rebuild index: %w", err)
+		}
+	}
+
+	reader.entries = entries
+
+	return reader, nil
+}
+
+func readIndex(path string) ([]IndexEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read index file: %w", err)
+	}
+
+	if err := validateIndexHeader(data); err != nil {
+		return nil, err
+	}
+
+	data = data[indexHeaderLength:]
+	entryCount := len(data) / indexEntryLength
+
+	entries := make([]IndexEntry, 0, entryCount)
+	for i := 0; i < entryCount; i++ {
+		entries = append(entries, unmarshalIndexEntry(data[i*indexEntryLength:(i+1)*indexEntryLength]))
+	}
+
+	return entries, nil
+}
+
+func (reader *SegmentReader) indexCoversFramesFile(entries []IndexEntry) bool {
+	stat, err := reader.framesFile.Stat()
+	if err != nil {
+		return false
+	}
+
+	if len(entries) == 0 {
+		return stat.Size() == segmentHeaderLength
+	}
+
+	last := entries[len(entries)-1]
+
+	return last.Offset+frameHeaderLength+int64(last.Size) == stat.Size()
+}
+
+func (reader *SegmentReader) scanFrames() ([]IndexEntry, error) {
+	stat, err := reader.framesFile.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat frames file: %w", err)
+	}
+
+	entries := make([]IndexEntry, 0)
+	offset := int64(segmentHeaderLength)
+	frameHeader := make([]byte, frameHeaderLength)
+
+	for offset+frameHeaderLength <= stat.Size() {
+		if _, err := reader.framesFile.ReadAt(frameHeader, offset); err != nil {
+			return nil, fmt.Errorf("read frame header: %w", err)
+		}
+
+		entry := unmarshalFrameHeader(frameHeader, offset)
+
+		// Partially written frame at the end of the file is ignored.
+		if offset+frameHeaderLength+int64(entry.Size) > stat.Size() {
+			break
+		}
+
+		entries = append(entries, entry)
+		offset += frameHeaderLength + int64(entry.Size)
+	}
+
+	return entries, nil
+}
+
+// GetHeader returns header of the segment.
+func (reader *SegmentReader) GetHeader() SegmentHeader {
+	return reader.header
+}
+
+// GetEntries returns index entries of all frames in the segment.
+func (reader *SegmentReader) GetEntries() []IndexEntry {
+	return reader.entries
+}
+
+// GetFrameCount returns number of frames stored in the segment.
+func (reader *SegmentReader) GetFrameCount() int {
+	return len(reader.entries)
+}
+
+// ReadFrame decodes frame with given index into RGB24 pixels appended to dst.
+func (reader *SegmentReader) ReadFrame(dst []byte, index int) ([]byte, error) {
+	if index < 0 || index >= len(reader.entries) {
+		return nil, fmt.Errorf("%w: %d", ErrFrameIndexOutOfRange, index)
+	}
+
+	entry := reader.entries[index]
+
+	if cap(reader.payloadBlock) < int(entry.Size) {
+		reader.payloadBlock = make([]byte, entry.Size)
+	}
+	payload := reader.payloadBlock[:entry.Size]
+
+	if _, err := reader.framesFile.ReadAt(payload, entry.Offset+frameHeaderLength); err != nil {
+		return nil, fmt.Errorf("%w: read frame payload: %w", ErrCorruptedSegment, err)
+	}
+
+	decoded, err := reader.header.Codec.decode(dst, payload, reader.header.DisplayMode)
+	if err != nil {
+		return nil, fmt.Errorf("decode frame: %w", err)
+	}
+
+	return decoded, nil
+}
+
+func (reader *SegmentReader) Close() error {
+	return reader.framesFile.Close()
+}
+
+var ErrFrameIndexOutOfRange = errors.New("frame index out of range")
@@ -0,0 +1,166 @@
+package recording
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+var testDisplayMode = peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 30}
+
+func testFramePixels(seed byte) []byte {
+	pixels := make([]byte, testDisplayMode.Width*testDisplayMode.Height*3)
+	for i := range pixels {
+		pixels[i] = seed + byte(i%7)
+	}
+
+	return pixels
+}
+
+func writeTestSegment(t *testing.T, basePath string, codec Codec, frameCount int) time.Time {
+	startedAt := time.Unix(1700000000, 0)
+
+	writer, err := CreateSegment(basePath, SegmentHeader{
+		Codec:       codec,
+		DisplayMode: testDisplayMode,
+		StartedAt:   startedAt,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for i := 0; i < frameCount; i++ {
+		err := writer.WriteFrame(uint64(i+1), startedAt.Add(time.Duration(i)*33*time.Millisecond), testFramePixels(byte(i)))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, frameCount, writer.GetFrameCount())
+	assert.NoError(t, writer.Close())
+
+	return startedAt
+}
+
+func TestSegmentRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, codec := range []Codec{CodecRaw, CodecQOI} {
+		t.Run(codec.String(), func(t *testing.T) {
+			basePath := filepath.Join(t.TempDir(), "segment")
+			startedAt := writeTestSegment(t, basePath, codec, 3)
+
+			reader, err := OpenSegment(basePath + FramesFileExtension)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer func() {
+				_ = reader.Close()
+			}()
+
+			header := reader.GetHeader()
+			assert.Equal(t, codec, header.Codec)
+			assert.Equal(t, testDisplayMode, header.DisplayMode)
+			assert.True(t, startedAt.Equal(header.StartedAt))
+
+			assert.Equal(t, 3, reader.GetFrameCount())
+
+			for i, entry := range reader.GetEntries() {
+				assert.Equal(t, uint64(i+1), entry.Sequence)
+				assert.True(t, startedAt.Add(time.Duration(i)*33*time.Millisecond).Equal(entry.Timestamp))
+
+				pixels, err := reader.ReadFrame(nil, i)
+				assert.NoError(t, err)
+				assert.Equal(t, testFramePixels(byte(i)), pixels)
+			}
+
+			_, err = reader.ReadFrame(nil, 3)
+			assert.ErrorIs(t, err, ErrFrameIndexOutOfRange)
+		})
+	}
+}
+
+func TestSegmentRebuildsMissingIndex(t *testing.T) {
+	t.Parallel()
+
+	basePath := filepath.Join(t.TempDir(), "segment")
+	writeTestSegment(t, basePath, CodecQOI, 4)
+
+	assert.NoError(t, os.Remove(basePath+IndexFileExtension))
+
+	reader, err := OpenSegment(basePath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	assert.Equal(t, 4, reader.GetFrameCount())
+
+	pixels, err := reader.ReadFrame(nil, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, testFramePixels(3), pixels)
+}
+
+func TestSegmentIgnoresPartiallyWrittenFrame(t *testing.T) {
+	t.Parallel()
+
+	basePath := filepath.Join(t.TempDir(), "segment")
+	writeTestSegment(t, basePath, CodecRaw, 2)
+
+	framesFile, err := os.OpenFile(basePath+FramesFileExtension, os.O_APPEND|os.O_WRONLY, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = framesFile.Write(marshalFrameHeader(3, time.Now(), 1000))
+	assert.NoError(t, err)
+	_, err = framesFile.Write([]byte{1, 2, 3})
+	assert.NoError(t, err)
+	assert.NoError(t, framesFile.Close())
+
+	reader, err := OpenSegment(basePath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	assert.Equal(t, 2, reader.GetFrameCount())
+}
+
+func TestCreateSegmentRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	_, err := CreateSegment(filepath.Join(directory, "a"), SegmentHeader{Codec: "h264", DisplayMode: testDisplayMode})
+	assert.ErrorIs(t, err, ErrUnsupportedCodec)
+
+	_, err = CreateSegment(filepath.Join(directory, "b"), SegmentHeader{Codec: CodecRaw})
+	assert.Error(t, err)
+
+	writer, err := CreateSegment(filepath.Join(directory, "c"), SegmentHeader{Codec: CodecRaw, DisplayMode: testDisplayMode})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		_ = writer.Close()
+	}()
+
+	err = writer.WriteFrame(1, time.Now(), []byte{1, 2, 3})
+	assert.ErrorIs(t, err, ErrFrameSizeMismatch)
+}
+
+func TestOpenSegmentRejectsCorruptedHeader(t *testing.T) {
+	t.Parallel()
+
+	basePath := filepath.Join(t.TempDir(), "segment")
+	assert.NoError(t, os.WriteFile(basePath+FramesFileExtension, make([]byte, segmentHeaderLength), 0o644))
+
+	_, err := OpenSegment(basePath)
+	assert.ErrorIs(t, err, ErrCorruptedSegment)
+}
@@ -0,0 +1,139 @@
+package recording
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// SegmentWriter appends frames to a single segment.
+type SegmentWriter struct {
+	header SegmentHeader
+
+	framesFile *os.File
+	indexFile  *os.File
+
+	framesOffset  int64
+	indexSize     int64
+	frameCount    int
+	lastTimestamp time.Time
+
+	encodeBuffer []byte
+}
+
+// CreateSegment creates new segment files at basePath (without extension).
+func CreateSegment(basePath string, header SegmentHeader) (*SegmentWriter, error) {
+	if err := header.Codec.Valid(); err != nil {
+		return nil, err
+	}
+
+	if err := header.DisplayMode.Valid(); err != nil {
+		return nil, fmt.Errorf("invalid display mode: %w", err)
+	}
+
+	framesFile, err := os.OpenFile(basePath+FramesFileExtension, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("create frames file: %w", err)
+	}
+
+	indexFile, err := os.OpenFile(basePath+IndexFileExtension, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		_ = framesFile.Close()
+		return nil, fmt.Errorf("create index file: %w", err)
+	}
+
+	writer := &SegmentWriter{
+		header:     header,
+		framesFile: framesFile,
+		indexFile:  indexFile,
+	}
+
+	headerData := header.marshal()
+	if _, err := framesFile.Write(headerData); err != nil {
+		_ = writer.Close()
+		return nil, fmt.Errorf("write segment header: %w", err)
+	}
+	writer.framesOffset = int64(len(headerData))
+
+	indexHeaderData := marshalIndexHeader()
+	if _, err := indexFile.Write(indexHeaderData); err != nil {
+		_ = writer.Close()
+		return nil, fmt.Errorf("write index header: %w", err)
+	}
+	writer.indexSize = int64(len(indexHeaderData))
+
+	return writer, nil
+}
+
+// GetHeader returns header of the segment.
+func (writer *SegmentWriter) GetHeader() SegmentHeader {
+	return writer.header
+}
+
+// WriteFrame encodes RGB24 pixels with segment codec and appends them to the segment.
+func (writer *SegmentWriter) WriteFrame(sequence uint64, timestamp time.Time, pixels []byte) error {
+	expectedSize := int(writer.header.DisplayMode.Width*writer.header.DisplayMode.Height) * peripheralSDK.DisplayPixelFormatRGB24.BytesPerPixel()
+	if len(pixels) != expectedSize {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrFrameSizeMismatch, expectedSize, len(pixels))
+	}
+
+	payload, err := writer.header.Codec.encode(writer.encodeBuffer[:0], pixels, writer.header.DisplayMode)
+	if err != nil {
+		return fmt.Errorf("encode frame: %w", err)
+	}
+	writer.encodeBuffer = payload
+
+	entry := IndexEntry{
+		Sequence:  sequence,
+		Timestamp: timestamp,
+		Offset:    writer.framesOffset,
+		Size:      uint32(len(payload)),
+	}
+
+	if _, err := writer.framesFile.Write(marshalFrameHeader(sequence, timestamp, entry.Size)); err != nil {
+		return fmt.Errorf("write frame header: %w", err)
+	}
+
+	if _, err := writer.framesFile.Write(payload); err != nil {
+		return fmt.Errorf("write frame payload: %w", err)
+	}
+
+	if _, err := writer.indexFile.Write(entry.marshal()); err != nil {
+		return fmt.Errorf("write index entry: %w", err)
+	}
+
+	writer.framesOffset += frameHeaderLength + int64(len(payload))
+	writer.indexSize += indexEntryLength
+	writer.frameCount++
+	writer.lastTimestamp = timestamp
+
+	return nil
+}
+
+// GetSize returns size of segment files in bytes.
+func (writer *SegmentWriter) GetSize() int64 {
+	return writer.framesOffset + writer.indexSize
+}
+
+// GetFrameCount returns number of frames written to the segment.
+func (writer *SegmentWriter) GetFrameCount() int {
+	return writer.frameCount
+}
+
+// GetDuration returns time elapsed between segment start and the last written frame.
+func (writer *SegmentWriter) GetDuration() time.Duration {
+	if writer.frameCount == 0 {
+		return 0
+	}
+
+	return writer.lastTimestamp.Sub(writer.header.StartedAt)
+}
+
+func (writer *SegmentWriter) Close() error {
+	return errors.Join(writer.framesFile.Close(), writer.indexFile.Close())
+}
+
+var ErrFrameSizeMismatch = errors.New("frame size does not match display mode")
@@ -0,0 +1,219 @@
+package recording
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/imagefile"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// TimelineFrame describes single frame available for playback.
+type TimelineFrame struct {
+	// Offset is the time elapsed since the first frame of the timeline.
+	Offset      time.Duration
+	Sequence    uint64
+	Timestamp   time.Time
+	DisplayMode peripheralSDK.DisplayMode
+
+	read func(dst []byte) ([]byte, error)
+}
+
+// Timeline is an ordered list of frames with their original timing, built from recording segments or from
+// a directory of image files.
+type Timeline struct {
+	frames   []TimelineFrame
+	duration time.Duration
+	closers  []io.Closer
+}
+
+// OpenSegmentTimeline opens all segments stored in directory by Recorder. Gaps between segments are preserved.
+func OpenSegmentTimeline(directory string) (*Timeline, error) {
+	segments, err := ListSegments(directory)
+	if err != nil {
+		return nil, fmt.Errorf("list segments: %w", err)
+	}
+
+	timeline := &Timeline{}
+
+	for _, segment := range segments {
+		reader, err := OpenSegment(segment.FramesPath)
+		if err != nil {
+			_ = timeline.Close()
+			return nil, fmt.Errorf("open segment %s: %w", segment.Name, err)
+		}
+
+		timeline.closers = append(timeline.closers, reader)
+
+		for i, entry := range reader.GetEntries() {
+			frameIndex := i
+			timeline.frames = append(timeline.frames, TimelineFrame{
+				Sequence:    entry.Sequence,
+				Timestamp:   entry.Timestamp,
+				DisplayMode: reader.GetHeader().DisplayMode,
+				read: func(dst []byte) ([]byte, error) {
+					return reader.ReadFrame(dst, frameIndex)
+				},
+			})
+		}
+	}
+
+	if err := timeline.complete(); err != nil {
+		_ = timeline.Close()
+		return nil, err
+	}
+
+	return timeline, nil
+}
+
+// OpenImageTimeline lists PNG, JPEG and PPM files stored in directory and plays them in lexical order of file names,
+// one image per frame interval.
+func OpenImageTimeline(directory string, frameInterval time.Duration) (*Timeline, error) {
+	if frameInterval <= 0 {
+		return nil, fmt.Errorf("%w: frame interval must be greater than zero", ErrInvalidTimeline)
+	}
+
+	dirEntries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("read directory: %w", err)
+	}
+
+	imagePaths := make([]string, 0)
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+
+		if imagefile.IsSupportedExtension(dirEntry.Name()) {
+			imagePaths = append(imagePaths, filepath.Join(directory, dirEntry.Name()))
+		}
+	}
+
+	slices.Sort(imagePaths)
+
+	refreshRate := uint32(time.Second / frameInterval)
+	if refreshRate == 0 {
+		refreshRate = 1
+	}
+
+	timeline := &Timeline{}
+	startedAt := time.Now()
+
+	for i, imagePath := range imagePaths {
+		_, width, height, err := imagefile.DecodeFileConfig(imagePath)
+		if err != nil {
+			return nil, fmt.Errorf("decode image %s: %w", imagePath, err)
+		}
+
+		path := imagePath
+		timeline.frames = append(timeline.frames, TimelineFrame{
+			Sequence:  uint64(i + 1),
+			Timestamp: startedAt.Add(time.Duration(i) * frameInterval),
+			DisplayMode: peripheralSDK.DisplayMode{
+				Width:       width,
+				Height:      height,
+				RefreshRate: refreshRate,
+			},
+			read: func(dst []byte) ([]byte, error) {
+				decodedImage, err := imagefile.DecodeFile(dst, path)
+				if err != nil {
+					return nil, err
+				}
+
+				return decodedImage.Pixels, nil
+			},
+		})
+	}
+
+	if err := timeline.complete(); err != nil {
+		return nil, err
+	}
+
+	return timeline, nil
+}
+
+func (timeline *Timeline) complete() error {
+	if len(timeline.frames) == 0 {
+		return fmt.Errorf("%w: no frames found", ErrInvalidTimeline)
+	}
+
+	startedAt := timeline.frames[0].Timestamp
+	for i := range timeline.frames {
+		offset := timeline.frames[i].Timestamp.Sub(startedAt)
+		if i > 0 && offset < timeline.frames[i-1].Offset {
+			offset = timeline.frames[i-1].Offset
+		}
+		timeline.frames[i].Offset = offset
+	}
+
+	lastFrame := timeline.frames[len(timeline.frames)-1]
+
+	lastFrameDuration := time.Second
+	if lastFrame.DisplayMode.RefreshRate > 0 {
+		lastFrameDuration = time.Second / time.Duration(lastFrame.DisplayMode.RefreshRate)
+	}
+
+	timeline.duration = lastFrame.Offset + lastFrameDuration
+
+	return nil
+}
+
+// GetDuration returns total length of the timeline including display time of the last frame.
+func (timeline *Timeline) GetDuration() time.Duration {
+	return timeline.duration
+}
+
+func (timeline *Timeline) GetFrameCount() int {
+	return len(timeline.frames)
+}
+
+func (timeline *Timeline) GetFrame(index int) (TimelineFrame, error) {
+	if index < 0 || index >= len(timeline.frames) {
+		return TimelineFrame{}, ErrFrameIndexOutOfRange
+	}
+
+	return timeline.frames[index], nil
+}
+
+// FrameIndexAt returns index of the frame shown at the given position.
+func (timeline *Timeline) FrameIndexAt(position time.Duration) int {
+	index := sort.Search(len(timeline.frames), func(i int) bool {
+		return timeline.frames[i].Offset > position
+	})
+
+	if index == 0 {
+		return 0
+	}
+
+	return index - 1
+}
+
+// ReadFrame reads RGB24 pixels of the frame with given index. Pixels are appended to dst, which may be nil.
+func (timeline *Timeline) ReadFrame(dst []byte, index int) ([]byte, error) {
+	frame, err := timeline.GetFrame(index)
+	if err != nil {
+		return nil, err
+	}
+
+	return frame.read(dst)
+}
+
+// Close releases opened segments.
+func (timeline *Timeline) Close() error {
+	var closeErr error
+	for _, closer := range timeline.closers {
+		closeErr = errors.Join(closeErr, closer.Close())
+	}
+
+	timeline.closers = nil
+
+	return closeErr
+}
+
+var ErrInvalidTimeline = errors.New("invalid timeline")
@@ -0,0 +1,119 @@
+package recording
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func TestOpenSegmentTimeline(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	recorder, err := NewRecorder(directory, CodecQOI, WithRecorderSegmentMaxDuration(time.Second))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	startedAt := time.Unix(1700000000, 0)
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 1, startedAt, testFramePixels(1)))
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 2, startedAt.Add(500*time.Millisecond), testFramePixels(2)))
+	assert.NoError(t, recorder.WriteFrame(testDisplayMode, 3, startedAt.Add(2*time.Second), testFramePixels(3)))
+	assert.NoError(t, recorder.Close())
+
+	timeline, err := OpenSegmentTimeline(directory)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		assert.NoError(t, timeline.Close())
+	}()
+
+	assert.Equal(t, 3, timeline.GetFrameCount())
+	assert.Equal(t, 2*time.Second+time.Second/30, timeline.GetDuration())
+
+	assert.Equal(t, 0, timeline.FrameIndexAt(0))
+	assert.Equal(t, 0, timeline.FrameIndexAt(499*time.Millisecond))
+	assert.Equal(t, 1, timeline.FrameIndexAt(500*time.Millisecond))
+	assert.Equal(t, 1, timeline.FrameIndexAt(1999*time.Millisecond))
+	assert.Equal(t, 2, timeline.FrameIndexAt(time.Hour))
+
+	frame, err := timeline.GetFrame(2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint64(3), frame.Sequence)
+		assert.Equal(t, 2*time.Second, frame.Offset)
+	}
+
+	pixels, err := timeline.ReadFrame(nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, testFramePixels(3), pixels)
+
+	_, err = timeline.ReadFrame(nil, 3)
+	assert.ErrorIs(t, err, ErrFrameIndexOutOfRange)
+}
+
+func TestOpenImageTimeline(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	ppmPayload := []byte{1, 2, 3, 4, 5, 6}
+	ppmData := append([]byte("P6\n2 1\n255\n"), ppmPayload...)
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, "0001.ppm"), ppmData, 0o644))
+
+	pngImage := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	pngImage.Set(0, 0, color.RGBA{R: 7, G: 8, B: 9, A: 255})
+	pngImage.Set(1, 0, color.RGBA{R: 10, G: 11, B: 12, A: 255})
+
+	pngFile, err := os.Create(filepath.Join(directory, "0002.png"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, png.Encode(pngFile, pngImage))
+	assert.NoError(t, pngFile.Close())
+
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, "notes.txt"), []byte("ignored"), 0o644))
+
+	timeline, err := OpenImageTimeline(directory, 100*time.Millisecond)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 2, timeline.GetFrameCount())
+	assert.Equal(t, 200*time.Millisecond, timeline.GetDuration())
+
+	frame, err := timeline.GetFrame(1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, peripheralSDK.DisplayMode{Width: 2, Height: 1, RefreshRate: 10}, frame.DisplayMode)
+		assert.Equal(t, 100*time.Millisecond, frame.Offset)
+	}
+
+	pixels, err := timeline.ReadFrame(nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, ppmPayload, pixels)
+
+	pixels, err = timeline.ReadFrame(nil, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{7, 8, 9, 10, 11, 12}, pixels)
+}
+
+func TestOpenTimelineRejectsEmptyDirectory(t *testing.T) {
+	t.Parallel()
+
+	_, err := OpenSegmentTimeline(t.TempDir())
+	assert.ErrorIs(t, err, ErrInvalidTimeline)
+
+	_, err = OpenImageTimeline(t.TempDir(), time.Second)
+	assert.ErrorIs(t, err, ErrInvalidTimeline)
+
+	_, err = OpenImageTimeline(t.TempDir(), 0)
+	assert.ErrorIs(t, err, ErrInvalidTimeline)
+}
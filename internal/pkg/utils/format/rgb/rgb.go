@@ -0,0 +1,83 @@
+package rgb
+
+import (
+	"image"
+	"image/color"
+)
+
+// FromImage converts image into RGB24 pixels, as used by display frame buffers. Pixels are appended to dst, which
+// may be nil. Alpha channel is dropped.
+func FromImage(dst []byte, img image.Image) []byte {
+	bounds := img.Bounds()
+	pixels := resize(dst, bounds.Dx()*bounds.Dy()*3)
+
+	switch source := img.(type) {
+	case *image.RGBA:
+		fromPackedRGBA(pixels, source.Pix, source.Stride, bounds.Dx(), bounds.Dy(), source.PixOffset(bounds.Min.X, bounds.Min.Y))
+	case *image.NRGBA:
+		fromPackedRGBA(pixels, source.Pix, source.Stride, bounds.Dx(), bounds.Dy(), source.PixOffset(bounds.Min.X, bounds.Min.Y))
+	case *image.YCbCr:
+		fromYCbCr(pixels, source)
+	default:
+		offset := 0
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				value := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+				pixels[offset] = value.R
+				pixels[offset+1] = value.G
+				pixels[offset+2] = value.B
+				offset += 3
+			}
+		}
+	}
+
+	return pixels
+}
+
+func fromPackedRGBA(pixels []byte, source []byte, stride int, width int, height int, start int) {
+	offset := 0
+	for y := 0; y < height; y++ {
+		row := source[start+y*stride : start+y*stride+width*4]
+		for x := 0; x < width; x++ {
+			pixels[offset] = row[x*4]
+			pixels[offset+1] = row[x*4+1]
+			pixels[offset+2] = row[x*4+2]
+			offset += 3
+		}
+	}
+}
+
+// fromYCbCr converts full range YCbCr image, as decoded from JPEG, without conversion of every pixel to interface.
+func fromYCbCr(pixels []byte, source *image.YCbCr) {
+	bounds := source.Bounds()
+
+	offset := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			chromaOffset := source.COffset(x, y)
+			pixels[offset], pixels[offset+1], pixels[offset+2] = color.YCbCrToRGB(source.Y[source.YOffset(x, y)], source.Cb[chromaOffset], source.Cr[chromaOffset])
+			offset += 3
+		}
+	}
+}
+
+// ToRGBA converts RGB24 pixels into opaque RGBA image. Image dst is reused when it has the same size, otherwise new
+// image is allocated.
+func ToRGBA(dst *image.RGBA, pixels []byte, width int, height int) *image.RGBA {
+	if dst == nil || dst.Bounds().Dx() != width || dst.Bounds().Dy() != height {
+		dst = image.NewRGBA(image.Rect(0, 0, width, height))
+	}
+
+	for y := 0; y < height; y++ {
+		row := dst.Pix[y*dst.Stride : y*dst.Stride+width*4]
+		source := pixels[y*width*3 : (y+1)*width*3]
+		for x := 0; x < width; x++ {
+			row[x*4] = source[x*3]
+			row[x*4+1] = source[x*3+1]
+			row[x*4+2] = source[x*3+2]
+			row[x*4+3] = 0xff
+		}
+	}
+
+	return dst
+}
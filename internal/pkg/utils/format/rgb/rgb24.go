@@ -0,0 +1,14 @@
+package rgb
+
+// FromRGB24 copies RGB24 lines of source, padded to stride bytes, into tightly packed RGB24 pixels. Pixels are
+// appended to dst, which may be nil.
+func FromRGB24(dst []byte, source []byte, width int, height int, stride int) []byte {
+	pixels := resize(dst, width*height*3)
+
+	lineLength := width * 3
+	for y := 0; y < height; y++ {
+		copy(pixels[y*lineLength:(y+1)*lineLength], source[y*stride:y*stride+lineLength])
+	}
+
+	return pixels
+}
@@ -0,0 +1,20 @@
+package rgb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromRGB24(t *testing.T) {
+	t.Parallel()
+
+	source := []byte{
+		1, 2, 3, 4, 5, 6,
+		0xff, 0xff, // padding of the line
+		7, 8, 9, 10, 11, 12,
+		0xff, 0xff,
+	}
+
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, FromRGB24(nil, source, 2, 2, 8))
+}
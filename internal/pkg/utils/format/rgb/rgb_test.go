@@ -0,0 +1,76 @@
+package rgb
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromImageRGBA(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{R: 10, G: 20, B: 30, A: 255})
+	img.Set(1, 0, color.RGBA{R: 40, G: 50, B: 60, A: 255})
+
+	assert.Equal(t, []byte{10, 20, 30, 40, 50, 60}, FromImage(nil, img))
+}
+
+func TestFromImageSubImage(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 1, color.NRGBA{R: 1, G: 2, B: 3, A: 255})
+	img.Set(2, 1, color.NRGBA{R: 4, G: 5, B: 6, A: 255})
+
+	subImage := img.SubImage(image.Rect(1, 1, 3, 2))
+
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6}, FromImage(nil, subImage))
+}
+
+func TestFromImageGeneric(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewGray(image.Rect(0, 0, 2, 1))
+	img.SetGray(0, 0, color.Gray{Y: 100})
+	img.SetGray(1, 0, color.Gray{Y: 200})
+
+	dst := make([]byte, 0, 6)
+	pixels := FromImage(dst, img)
+
+	assert.Equal(t, []byte{100, 100, 100, 200, 200, 200}, pixels)
+	assert.Equal(t, &dst[:1][0], &pixels[0])
+}
+
+func TestToRGBA(t *testing.T) {
+	t.Parallel()
+
+	pixels := []byte{10, 20, 30, 40, 50, 60}
+
+	img := ToRGBA(nil, pixels, 2, 1)
+	assert.Equal(t, []byte{10, 20, 30, 255, 40, 50, 60, 255}, img.Pix)
+	assert.Equal(t, pixels, FromImage(nil, img))
+
+	assert.Same(t, img, ToRGBA(img, pixels, 2, 1))
+	assert.NotSame(t, img, ToRGBA(img, append(pixels, pixels...), 2, 2))
+}
+
+func TestFromImageYCbCr(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewYCbCr(image.Rect(0, 0, 2, 2), image.YCbCrSubsampleRatio420)
+	img.Y[0], img.Y[1], img.Y[img.YStride], img.Y[img.YStride+1] = 0, 255, 128, 64
+	img.Cb[0], img.Cr[0] = 128, 128
+
+	assert.Equal(t, []byte{0, 0, 0, 255, 255, 255, 128, 128, 128, 64, 64, 64}, FromImage(nil, img))
+
+	img.Cr[0] = 255
+	expected := []byte{}
+	for _, luma := range []byte{0, 255, 128, 64} {
+		r, g, b := color.YCbCrToRGB(luma, 128, 255)
+		expected = append(expected, r, g, b)
+	}
+	assert.Equal(t, expected, FromImage(nil, img))
+}
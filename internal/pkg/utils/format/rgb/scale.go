@@ -0,0 +1,59 @@
+package rgb
+
+// Scale resizes RGB24 pixels to the destination size using nearest neighbour sampling. Pixels are appended to dst,
+// which may be nil.
+func Scale(dst []byte, pixels []byte, width int, height int, targetWidth int, targetHeight int) []byte {
+	scaled := resize(dst, targetWidth*targetHeight*3)
+
+	scaleInto(scaled, targetWidth, 0, 0, targetWidth, targetHeight, pixels, width, height)
+
+	return scaled
+}
+
+// Fit resizes RGB24 pixels to fit destination size while keeping aspect ratio. Remaining area is filled with black.
+// Pixels are appended to dst, which may be nil.
+func Fit(dst []byte, pixels []byte, width int, height int, targetWidth int, targetHeight int) []byte {
+	fitted := resize(dst, targetWidth*targetHeight*3)
+	clear(fitted)
+
+	fitWidth := targetWidth
+	fitHeight := height * targetWidth / width
+	if fitHeight > targetHeight {
+		fitHeight = targetHeight
+		fitWidth = width * targetHeight / height
+	}
+
+	if fitWidth == 0 || fitHeight == 0 {
+		return fitted
+	}
+
+	offsetX := (targetWidth - fitWidth) / 2
+	offsetY := (targetHeight - fitHeight) / 2
+
+	scaleInto(fitted, targetWidth, offsetX, offsetY, fitWidth, fitHeight, pixels, width, height)
+
+	return fitted
+}
+
+func scaleInto(target []byte, targetStride int, offsetX int, offsetY int, areaWidth int, areaHeight int, pixels []byte, width int, height int) {
+	for y := 0; y < areaHeight; y++ {
+		sourceY := y * height / areaHeight
+		targetRow := ((offsetY+y)*targetStride + offsetX) * 3
+
+		for x := 0; x < areaWidth; x++ {
+			sourceOffset := (sourceY*width + x*width/areaWidth) * 3
+			targetOffset := targetRow + x*3
+
+			copy(target[targetOffset:targetOffset+3], pixels[sourceOffset:sourceOffset+3])
+		}
+	}
+}
+
+func resize(dst []byte, size int) []byte {
+	resized := dst[:0]
+	if cap(resized) < size {
+		resized = make([]byte, size)
+	}
+
+	return resized[:size]
+}
@@ -0,0 +1,55 @@
+package rgb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	red   = []byte{255, 0, 0}
+	green = []byte{0, 255, 0}
+	black = []byte{0, 0, 0}
+)
+
+func joinPixels(pixels ...[]byte) []byte {
+	var joined []byte
+	for _, pixel := range pixels {
+		joined = append(joined, pixel...)
+	}
+
+	return joined
+}
+
+func TestScale(t *testing.T) {
+	t.Parallel()
+
+	pixels := joinPixels(red, green)
+
+	assert.Equal(t, joinPixels(red, red, green, green), Scale(nil, pixels, 2, 1, 4, 1))
+	assert.Equal(t, joinPixels(red, red), Scale(nil, pixels, 2, 1, 1, 2))
+}
+
+func TestFit(t *testing.T) {
+	t.Parallel()
+
+	pixels := joinPixels(red, green)
+
+	assert.Equal(t, joinPixels(
+		black, black,
+		red, green,
+		black, black,
+	), Fit(nil, pixels, 2, 1, 2, 3))
+
+	assert.Equal(t, joinPixels(
+		black, red, green, black,
+	), Fit(nil, pixels, 2, 1, 4, 1))
+}
+
+func TestFitClearsReusedDestination(t *testing.T) {
+	t.Parallel()
+
+	dst := []byte{9, 9, 9, 9, 9, 9, 9, 9, 9}
+
+	assert.Equal(t, joinPixels(black, red, black), Fit(dst, red, 1, 1, 3, 1))
+}
@@ -0,0 +1,43 @@
+package rgb
+
+// FromYUYV converts packed YUYV 4:2:2 pixels with BT.601 limited range, as delivered by UVC cameras, into RGB24
+// pixels. Two horizontally adjacent pixels share chroma samples, stride is the length of a source line in bytes.
+// Pixels are appended to dst, which may be nil.
+func FromYUYV(dst []byte, source []byte, width int, height int, stride int) []byte {
+	pixels := resize(dst, width*height*3)
+
+	offset := 0
+	for y := 0; y < height; y++ {
+		row := source[y*stride : y*stride+width*2]
+		for x := 0; x+1 < width; x += 2 {
+			u := int32(row[x*2+1]) - 128
+			v := int32(row[x*2+3]) - 128
+
+			fromLimitedYCbCr(pixels[offset:offset+3], int32(row[x*2]), u, v)
+			fromLimitedYCbCr(pixels[offset+3:offset+6], int32(row[x*2+2]), u, v)
+			offset += 6
+		}
+
+		// odd width leaves the last pixel without its pair, chroma samples still follow it
+		if width%2 == 1 {
+			x := width - 1
+			fromLimitedYCbCr(pixels[offset:offset+3], int32(row[x*2]), int32(row[x*2+1])-128, 0)
+			offset += 3
+		}
+	}
+
+	return pixels
+}
+
+// fromLimitedYCbCr writes RGB of the BT.601 limited range sample using 8 bit fixed point coefficients.
+func fromLimitedYCbCr(pixel []byte, y int32, u int32, v int32) {
+	c := 298 * (y - 16)
+
+	pixel[0] = clampUint8((c + 409*v + 128) >> 8)
+	pixel[1] = clampUint8((c - 100*u - 208*v + 128) >> 8)
+	pixel[2] = clampUint8((c + 516*u + 128) >> 8)
+}
+
+func clampUint8(value int32) byte {
+	return byte(min(max(value, 0), 255))
+}
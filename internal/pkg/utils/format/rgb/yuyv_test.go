@@ -0,0 +1,23 @@
+package rgb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromYUYV(t *testing.T) {
+	t.Parallel()
+
+	source := []byte{
+		16, 128, 235, 128, 81, 90, 81, 240, // black, white and two red pixels
+		0xff, 0xff, // padding of the line
+		145, 54, 145, 34, 41, 240, 41, 110, // two green and two blue pixels
+		0xff, 0xff,
+	}
+
+	assert.Equal(t, []byte{
+		0, 0, 0, 255, 255, 255, 255, 0, 0, 255, 0, 0,
+		0, 255, 1, 0, 255, 1, 0, 0, 255, 0, 0, 255,
+	}, FromYUYV(nil, source, 4, 2, 10))
+}
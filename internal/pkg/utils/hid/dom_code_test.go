@@ -0,0 +1,50 @@
+package hid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func TestUsageFromDOMCode(t *testing.T) {
+	tests := []struct {
+		code  string
+		usage peripheralSDK.KeyboardHIDUsage
+	}{
+		{"KeyA", 0x04},
+		{"KeyZ", 0x1d},
+		{"Digit0", 0x27},
+		{"Enter", 0x28},
+		{"F12", 0x45},
+		{"ArrowUp", 0x52},
+		{"NumpadEnter", 0x58},
+		{"MetaRight", 0xe7},
+	}
+
+	for _, test := range tests {
+		usage, found := UsageFromDOMCode(test.code)
+		assert.True(t, found, test.code)
+		assert.Equal(t, test.usage, usage, test.code)
+	}
+
+	_, found := UsageFromDOMCode("Unidentified")
+	assert.False(t, found)
+}
+
+func TestDOMCodeUsagesAreUnique(t *testing.T) {
+	seen := make(map[peripheralSDK.KeyboardHIDUsage]string)
+	for code, usage := range domCodeUsages {
+		if previous, duplicated := seen[usage]; duplicated {
+			t.Errorf("usage %#x mapped by %s and %s", usage, previous, code)
+		}
+		seen[usage] = code
+	}
+}
+
+func TestModifierFromUsage(t *testing.T) {
+	assert.Equal(t, peripheralSDK.KeyboardModifierShift, ModifierFromUsage(0xe5))
+	assert.Equal(t, peripheralSDK.KeyboardModifierAltGr, ModifierFromUsage(0xe6))
+	assert.Equal(t, peripheralSDK.KeyboardModifierNone, ModifierFromUsage(0x04))
+}
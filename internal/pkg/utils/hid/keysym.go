@@ -0,0 +1,136 @@
+package hid
+
+import (
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// Keysym is X11 keysym, as used by RFB KeyEvent messages.
+type Keysym uint32
+
+// keysymKey describes key producing keysym on US layout. Shifted keysyms are produced by the same physical key with
+// Shift held, clients send Shift key events separately.
+type keysymKey struct {
+	usage   peripheralSDK.KeyboardHIDUsage
+	shifted bool
+}
+
+var keysymKeys = map[Keysym]keysymKey{
+	' ': {0x2c, false}, '!': {0x1e, true}, '"': {0x34, true}, '#': {0x20, true}, '$': {0x21, true},
+	'%': {0x22, true}, '&': {0x24, true}, '\'': {0x34, false}, '(': {0x26, true}, ')': {0x27, true},
+	'*': {0x25, true}, '+': {0x2e, true}, ',': {0x36, false}, '-': {0x2d, false}, '.': {0x37, false},
+	'/': {0x38, false}, ':': {0x33, true}, ';': {0x33, false}, '<': {0x36, true}, '=': {0x2e, false},
+	'>': {0x37, true}, '?': {0x38, true}, '@': {0x1f, true}, '[': {0x2f, false}, '\\': {0x31, false},
+	']': {0x30, false}, '^': {0x23, true}, '_': {0x2d, true}, '`': {0x35, false}, '{': {0x2f, true},
+	'|': {0x31, true}, '}': {0x30, true}, '~': {0x35, true},
+
+	0xff08: {0x2a, false}, // BackSpace
+	0xff09: {0x2b, false}, // Tab
+	0xfe20: {0x2b, true},  // ISO_Left_Tab
+	0xff0d: {0x28, false}, // Return
+	0xff13: {0x48, false}, // Pause
+	0xff14: {0x47, false}, // Scroll_Lock
+	0xff15: {0x46, false}, // Sys_Req
+	0xff1b: {0x29, false}, // Escape
+	0xffff: {0x4c, false}, // Delete
+	0xff50: {0x4a, false}, // Home
+	0xff51: {0x50, false}, // Left
+	0xff52: {0x52, false}, // Up
+	0xff53: {0x4f, false}, // Right
+	0xff54: {0x51, false}, // Down
+	0xff55: {0x4b, false}, // Prior
+	0xff56: {0x4e, false}, // Next
+	0xff57: {0x4d, false}, // End
+	0xff61: {0x46, false}, // Print
+	0xff63: {0x49, false}, // Insert
+	0xff67: {0x65, false}, // Menu
+	0xff6a: {0x75, false}, // Help
+	0xff7f: {0x53, false}, // Num_Lock
+
+	0xff8d: {0x58, false}, // KP_Enter
+	0xff95: {0x5f, false}, // KP_Home
+	0xff96: {0x5c, false}, // KP_Left
+	0xff97: {0x60, false}, // KP_Up
+	0xff98: {0x5e, false}, // KP_Right
+	0xff99: {0x5a, false}, // KP_Down
+	0xff9a: {0x61, false}, // KP_Prior
+	0xff9b: {0x5b, false}, // KP_Next
+	0xff9c: {0x59, false}, // KP_End
+	0xff9d: {0x5d, false}, // KP_Begin
+	0xff9e: {0x62, false}, // KP_Insert
+	0xff9f: {0x63, false}, // KP_Delete
+	0xffaa: {0x55, false}, // KP_Multiply
+	0xffab: {0x57, false}, // KP_Add
+	0xffac: {0x85, false}, // KP_Separator
+	0xffad: {0x56, false}, // KP_Subtract
+	0xffae: {0x63, false}, // KP_Decimal
+	0xffaf: {0x54, false}, // KP_Divide
+	0xffbd: {0x67, false}, // KP_Equal
+
+	0xffe1: {0xe1, false}, // Shift_L
+	0xffe2: {0xe5, false}, // Shift_R
+	0xffe3: {0xe0, false}, // Control_L
+	0xffe4: {0xe4, false}, // Control_R
+	0xffe5: {0x39, false}, // Caps_Lock
+	0xffe7: {0xe3, false}, // Meta_L
+	0xffe8: {0xe7, false}, // Meta_R
+	0xffe9: {0xe2, false}, // Alt_L
+	0xffea: {0xe6, false}, // Alt_R
+	0xffeb: {0xe3, false}, // Super_L
+	0xffec: {0xe7, false}, // Super_R
+	0xfe03: {0xe6, false}, // ISO_Level3_Shift
+	0xff7e: {0xe6, false}, // Mode_switch
+}
+
+func init() {
+	for letter := Keysym('a'); letter <= 'z'; letter++ {
+		usage := peripheralSDK.KeyboardHIDUsage(0x04 + letter - 'a')
+		keysymKeys[letter] = keysymKey{usage, false}
+		keysymKeys[letter-'a'+'A'] = keysymKey{usage, true}
+	}
+
+	keysymKeys['0'] = keysymKey{0x27, false}
+	for digit := Keysym('1'); digit <= '9'; digit++ {
+		keysymKeys[digit] = keysymKey{peripheralSDK.KeyboardHIDUsage(0x1e + digit - '1'), false}
+	}
+
+	for function := Keysym(0); function < 12; function++ {
+		keysymKeys[0xffbe+function] = keysymKey{peripheralSDK.KeyboardHIDUsage(0x3a + function), false}
+	}
+	for function := Keysym(12); function < 24; function++ {
+		keysymKeys[0xffbe+function] = keysymKey{peripheralSDK.KeyboardHIDUsage(0x68 + function - 12), false}
+	}
+
+	keysymKeys[0xffb0] = keysymKey{0x62, false} // KP_0
+	for digit := Keysym(1); digit <= 9; digit++ {
+		keysymKeys[0xffb0+digit] = keysymKey{peripheralSDK.KeyboardHIDUsage(0x59 + digit - 1), false}
+	}
+
+	// keys producing several keysyms prefer the highest one, for example KP_7 over KP_Home and Super_L over Meta_L
+	for keysym, key := range keysymKeys {
+		if current, found := keyKeysyms[key]; !found || keysym > current {
+			keyKeysyms[key] = keysym
+		}
+	}
+}
+
+var keyKeysyms = make(map[keysymKey]Keysym, 256)
+
+// UsageFromKeysym returns HID usage of the key producing keysym on US layout. Shifted reports that keysym is produced
+// with Shift held.
+func UsageFromKeysym(keysym Keysym) (usage peripheralSDK.KeyboardHIDUsage, shifted bool, found bool) {
+	key, found := keysymKeys[keysym]
+
+	return key.usage, key.shifted, found
+}
+
+// KeysymFromUsage returns keysym produced by the key of HID usage on US layout, with Shift held when shifted is set.
+// Keys without shifted keysym, like function keys, return the same keysym for both states.
+func KeysymFromUsage(usage peripheralSDK.KeyboardHIDUsage, shifted bool) (Keysym, bool) {
+	if keysym, found := keyKeysyms[keysymKey{usage, shifted}]; found {
+		return keysym, true
+	}
+
+	keysym, found := keyKeysyms[keysymKey{usage, false}]
+
+	return keysym, found
+}
@@ -0,0 +1,64 @@
+package hid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func TestUsageFromKeysym(t *testing.T) {
+	tests := []struct {
+		keysym  Keysym
+		usage   peripheralSDK.KeyboardHIDUsage
+		shifted bool
+	}{
+		{'a', 0x04, false},
+		{'Z', 0x1d, true},
+		{'0', 0x27, false},
+		{'9', 0x26, false},
+		{'!', 0x1e, true},
+		{0xff0d, 0x28, false},
+		{0xffbe, 0x3a, false},
+		{0xffc9, 0x45, false},
+		{0xffca, 0x68, false},
+		{0xffb5, 0x5d, false},
+		{0xffe1, 0xe1, false},
+	}
+
+	for _, test := range tests {
+		usage, shifted, found := UsageFromKeysym(test.keysym)
+		assert.True(t, found, "%#x", test.keysym)
+		assert.Equal(t, test.usage, usage, "%#x", test.keysym)
+		assert.Equal(t, test.shifted, shifted, "%#x", test.keysym)
+	}
+
+	_, _, found := UsageFromKeysym(0x20ac)
+	assert.False(t, found)
+}
+
+func TestKeysymFromUsage(t *testing.T) {
+	tests := []struct {
+		usage   peripheralSDK.KeyboardHIDUsage
+		shifted bool
+		keysym  Keysym
+	}{
+		{0x04, false, 'a'},
+		{0x04, true, 'A'},
+		{0x1e, true, '!'},
+		{0x28, true, 0xff0d},
+		{0x5f, false, 0xffb7},
+		{0xe3, false, 0xffeb},
+		{0xe6, false, 0xffea},
+	}
+
+	for _, test := range tests {
+		keysym, found := KeysymFromUsage(test.usage, test.shifted)
+		assert.True(t, found, "%#x", test.usage)
+		assert.Equal(t, test.keysym, keysym, "%#x", test.usage)
+	}
+
+	_, found := KeysymFromUsage(0xff, false)
+	assert.False(t, found)
+}
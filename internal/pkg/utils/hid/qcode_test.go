@@ -0,0 +1,42 @@
+package hid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func TestQCodeFromUsage(t *testing.T) {
+	tests := []struct {
+		usage peripheralSDK.KeyboardHIDUsage
+		qcode string
+	}{
+		{0x04, "a"},
+		{0x27, "0"},
+		{0x28, "ret"},
+		{0x2c, "spc"},
+		{0x45, "f12"},
+		{0x52, "up"},
+		{0x58, "kp_enter"},
+		{0xe1, "shift"},
+		{0xe7, "meta_r"},
+	}
+
+	for _, test := range tests {
+		qcode, found := QCodeFromUsage(test.usage)
+		assert.True(t, found, "%#x", test.usage)
+		assert.Equal(t, test.qcode, qcode, "%#x", test.usage)
+	}
+
+	_, found := QCodeFromUsage(0xff)
+	assert.False(t, found)
+}
+
+func TestDOMCodeUsagesHaveQCodes(t *testing.T) {
+	for code, usage := range domCodeUsages {
+		_, found := QCodeFromUsage(usage)
+		assert.True(t, found, code)
+	}
+}
@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultLatencyHistogramBounds are upper bounds of buckets from 50µs to 1s, suitable for frame fetch latency.
+var DefaultLatencyHistogramBounds = []time.Duration{
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+type LatencyHistogramOpt func(histogram *LatencyHistogram)
+
+// WithLatencyHistogramBounds replaces bucket upper bounds, bounds must be sorted in ascending order.
+func WithLatencyHistogramBounds(bounds []time.Duration) LatencyHistogramOpt {
+	return func(histogram *LatencyHistogram) {
+		histogram.bounds = bounds
+	}
+}
+
+// LatencyHistogram counts observed durations in buckets with fixed upper bounds. Durations above the last bound are
+// counted in an additional unbounded bucket.
+type LatencyHistogram struct {
+	bounds []time.Duration
+	counts []uint64
+
+	count uint64
+	sum   time.Duration
+	min   time.Duration
+	max   time.Duration
+
+	lock sync.Mutex
+}
+
+// LatencyHistogramBucket is a bucket of durations greater than the previous bucket bound and lower or equal to
+// UpperBound. UpperBound of the last bucket is zero, it means the bucket is unbounded.
+type LatencyHistogramBucket struct {
+	UpperBound time.Duration `json:"upperBound"`
+	Count      uint64        `json:"count"`
+}
+
+// LatencyHistogramSnapshot is a copy of the histogram state. Percentiles are estimated by upper bound of the bucket
+// containing them, limited to the maximum observed duration.
+type LatencyHistogramSnapshot struct {
+	Count   uint64                   `json:"count"`
+	Min     time.Duration            `json:"min"`
+	Mean    time.Duration            `json:"mean"`
+	Max     time.Duration            `json:"max"`
+	P50     time.Duration            `json:"p50"`
+	P95     time.Duration            `json:"p95"`
+	P99     time.Duration            `json:"p99"`
+	Buckets []LatencyHistogramBucket `json:"buckets"`
+}
+
+func NewLatencyHistogram(opts ...LatencyHistogramOpt) *LatencyHistogram {
+	histogram := &LatencyHistogram{
+		bounds: DefaultLatencyHistogramBounds,
+	}
+
+	for _, opt := range opts {
+		opt(histogram)
+	}
+
+	histogram.counts = make([]uint64, len(histogram.bounds)+1)
+
+	return histogram
+}
+
+func (histogram *LatencyHistogram) Observe(duration time.Duration) {
+	histogram.lock.Lock()
+	defer histogram.lock.Unlock()
+
+	index := len(histogram.bounds)
+	for boundIndex, bound := range histogram.bounds {
+		if duration <= bound {
+			index = boundIndex
+			break
+		}
+	}
+
+	histogram.counts[index]++
+
+	if histogram.count == 0 || duration < histogram.min {
+		histogram.min = duration
+	}
+	if duration > histogram.max {
+		histogram.max = duration
+	}
+
+	histogram.count++
+	histogram.sum += duration
+}
+
+func (histogram *LatencyHistogram) Snapshot() LatencyHistogramSnapshot {
+	histogram.lock.Lock()
+	defer histogram.lock.Unlock()
+
+	snapshot := LatencyHistogramSnapshot{
+		Count:   histogram.count,
+		Min:     histogram.min,
+		Max:     histogram.max,
+		Buckets: make([]LatencyHistogramBucket, len(histogram.counts)),
+	}
+
+	for index, count := range histogram.counts {
+		snapshot.Buckets[index].Count = count
+		if index < len(histogram.bounds) {
+			snapshot.Buckets[index].UpperBound = histogram.bounds[index]
+		}
+	}
+
+	if histogram.count == 0 {
+		return snapshot
+	}
+
+	snapshot.Mean = histogram.sum / time.Duration(histogram.count)
+	snapshot.P50 = histogram.percentile(0.50)
+	snapshot.P95 = histogram.percentile(0.95)
+	snapshot.P99 = histogram.percentile(0.99)
+
+	return snapshot
+}
+
+func (histogram *LatencyHistogram) Reset() {
+	histogram.lock.Lock()
+	defer histogram.lock.Unlock()
+
+	clear(histogram.counts)
+	histogram.count = 0
+	histogram.sum = 0
+	histogram.min = 0
+	histogram.max = 0
+}
+
+func (histogram *LatencyHistogram) percentile(quantile float64) time.Duration {
+	rank := uint64(quantile*float64(histogram.count) + 0.5)
+	if rank == 0 {
+		rank = 1
+	}
+
+	var cumulative uint64
+	for index, count := range histogram.counts {
+		cumulative += count
+		if cumulative < rank {
+			continue
+		}
+
+		if index < len(histogram.bounds) && histogram.bounds[index] < histogram.max {
+			return histogram.bounds[index]
+		}
+
+		return histogram.max
+	}
+
+	return histogram.max
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	histogram := NewLatencyHistogram(WithLatencyHistogramBounds([]time.Duration{
+		time.Millisecond,
+		10 * time.Millisecond,
+	}))
+
+	assert.Equal(t, LatencyHistogramSnapshot{
+		Buckets: []LatencyHistogramBucket{
+			{UpperBound: time.Millisecond},
+			{UpperBound: 10 * time.Millisecond},
+			{},
+		},
+	}, histogram.Snapshot())
+
+	for range 90 {
+		histogram.Observe(500 * time.Microsecond)
+	}
+	for range 9 {
+		histogram.Observe(5 * time.Millisecond)
+	}
+	histogram.Observe(20 * time.Millisecond)
+
+	snapshot := histogram.Snapshot()
+	assert.Equal(t, uint64(100), snapshot.Count)
+	assert.Equal(t, 500*time.Microsecond, snapshot.Min)
+	assert.Equal(t, 20*time.Millisecond, snapshot.Max)
+	assert.Equal(t, 1100*time.Microsecond, snapshot.Mean)
+	assert.Equal(t, time.Millisecond, snapshot.P50)
+	assert.Equal(t, 10*time.Millisecond, snapshot.P95)
+	assert.Equal(t, 10*time.Millisecond, snapshot.P99)
+	assert.Equal(t, []LatencyHistogramBucket{
+		{UpperBound: time.Millisecond, Count: 90},
+		{UpperBound: 10 * time.Millisecond, Count: 9},
+		{Count: 1},
+	}, snapshot.Buckets)
+
+	histogram.Reset()
+	assert.Equal(t, uint64(0), histogram.Snapshot().Count)
+}
+
+func TestLatencyHistogramPercentileLimitedToMax(t *testing.T) {
+	t.Parallel()
+
+	histogram := NewLatencyHistogram()
+	histogram.Observe(3 * time.Millisecond)
+
+	snapshot := histogram.Snapshot()
+	assert.Equal(t, 3*time.Millisecond, snapshot.P50)
+	assert.Equal(t, 3*time.Millisecond, snapshot.P99)
+}
@@ -0,0 +1,112 @@
+package sysfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DefaultPath is the sysfs directory exposing DRM devices and their connectors.
+const DefaultPath = "/sys/class/drm"
+
+// ConnectorStatus is the content of connector status attribute.
+type ConnectorStatus string
+
+const (
+	ConnectorStatusConnected    ConnectorStatus = "connected"
+	ConnectorStatusDisconnected ConnectorStatus = "disconnected"
+	ConnectorStatusUnknown      ConnectorStatus = "unknown"
+)
+
+// Connector is a display output of DRM card, e.g. card0-HDMI-A-1.
+type Connector struct {
+	Name   string
+	Status ConnectorStatus
+
+	// Edid holds raw EDID blocks read by the kernel from the monitor, empty when no monitor is connected.
+	Edid []byte
+}
+
+// ListConnectors returns connectors of all DRM cards found in sysfs directory, sorted by name. Directories without edid
+// attribute, e.g. cards themselves and render nodes, are not connectors.
+func ListConnectors(path string) ([]Connector, error) {
+	edidPaths, err := filepath.Glob(filepath.Join(path, "*", "edid"))
+	if err != nil {
+		return nil, fmt.Errorf("glob connectors: %w", err)
+	}
+
+	slices.Sort(edidPaths)
+
+	connectors := make([]Connector, 0, len(edidPaths))
+	for _, edidPath := range edidPaths {
+		connector, err := readConnector(filepath.Dir(edidPath))
+		if err != nil {
+			return nil, err
+		}
+
+		connectors = append(connectors, connector)
+	}
+
+	return connectors, nil
+}
+
+// ReadConnectorEdid returns connector with EDID of the monitor. When name is empty, the first connected connector
+// presenting EDID is returned.
+func ReadConnectorEdid(path string, name string) (*Connector, error) {
+	if name != "" {
+		connector, err := readConnector(filepath.Join(path, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrConnectorNotFound, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(connector.Edid) == 0 {
+			return nil, fmt.Errorf("%w: connector %s is %s", ErrEdidNotAvailable, name, connector.Status)
+		}
+
+		return &connector, nil
+	}
+
+	connectors, err := ListConnectors(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, connector := range connectors {
+		if connector.Status == ConnectorStatusConnected && len(connector.Edid) > 0 {
+			return &connector, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no connected connector in %s", ErrEdidNotAvailable, path)
+}
+
+func readConnector(connectorPath string) (Connector, error) {
+	connector := Connector{
+		Name:   filepath.Base(connectorPath),
+		Status: ConnectorStatusUnknown,
+	}
+
+	edid, err := os.ReadFile(filepath.Join(connectorPath, "edid"))
+	if err != nil {
+		return Connector{}, fmt.Errorf("read edid of %s: %w", connector.Name, err)
+	}
+	connector.Edid = edid
+
+	status, err := os.ReadFile(filepath.Join(connectorPath, "status"))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return Connector{}, fmt.Errorf("read status of %s: %w", connector.Name, err)
+	default:
+		connector.Status = ConnectorStatus(strings.TrimSpace(string(status)))
+	}
+
+	return connector, nil
+}
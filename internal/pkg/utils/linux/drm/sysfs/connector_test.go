@@ -0,0 +1,99 @@
+package sysfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createFakeConnector creates connector directory like the kernel does, status is not written when empty.
+func createFakeConnector(t *testing.T, path string, name string, status string, edid []byte) {
+	t.Helper()
+
+	connectorPath := filepath.Join(path, name)
+	require.NoError(t, os.MkdirAll(connectorPath, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(connectorPath, "edid"), edid, 0o644))
+
+	if status != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(connectorPath, "status"), []byte(status+"\n"), 0o644))
+	}
+}
+
+func createFakeSysfs(t *testing.T) string {
+	t.Helper()
+
+	path := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "card0"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "renderD128"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "version"), []byte("drm 1.1.0 20060810\n"), 0o644))
+
+	createFakeConnector(t, path, "card0-HDMI-A-1", "disconnected", nil)
+	createFakeConnector(t, path, "card0-HDMI-A-2", "connected", []byte{1, 2, 3})
+	createFakeConnector(t, path, "card1-DP-1", "connected", []byte{4, 5, 6})
+
+	return path
+}
+
+func TestListConnectors(t *testing.T) {
+	path := createFakeSysfs(t)
+
+	connectors, err := ListConnectors(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Connector{
+		{Name: "card0-HDMI-A-1", Status: ConnectorStatusDisconnected, Edid: []byte{}},
+		{Name: "card0-HDMI-A-2", Status: ConnectorStatusConnected, Edid: []byte{1, 2, 3}},
+		{Name: "card1-DP-1", Status: ConnectorStatusConnected, Edid: []byte{4, 5, 6}},
+	}, connectors)
+}
+
+func TestListConnectorsEmptyDirectory(t *testing.T) {
+	connectors, err := ListConnectors(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, connectors)
+}
+
+func TestReadConnectorEdid(t *testing.T) {
+	path := createFakeSysfs(t)
+
+	connector, err := ReadConnectorEdid(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "card0-HDMI-A-2", connector.Name)
+	assert.Equal(t, []byte{1, 2, 3}, connector.Edid)
+
+	connector, err = ReadConnectorEdid(path, "card1-DP-1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{4, 5, 6}, connector.Edid)
+}
+
+func TestReadConnectorEdidErrors(t *testing.T) {
+	path := createFakeSysfs(t)
+
+	_, err := ReadConnectorEdid(path, "card0-HDMI-A-1")
+	assert.ErrorIs(t, err, ErrEdidNotAvailable)
+
+	_, err = ReadConnectorEdid(path, "card0-DP-9")
+	assert.ErrorIs(t, err, ErrConnectorNotFound)
+
+	emptyPath := t.TempDir()
+	createFakeConnector(t, emptyPath, "card0-HDMI-A-1", "disconnected", nil)
+
+	_, err = ReadConnectorEdid(emptyPath, "")
+	assert.ErrorIs(t, err, ErrEdidNotAvailable)
+}
+
+func TestReadConnectorEdidWithoutStatus(t *testing.T) {
+	path := t.TempDir()
+	createFakeConnector(t, path, "card0-Virtual-1", "", []byte{7})
+
+	_, err := ReadConnectorEdid(path, "")
+	assert.ErrorIs(t, err, ErrEdidNotAvailable)
+
+	connector, err := ReadConnectorEdid(path, "card0-Virtual-1")
+	require.NoError(t, err)
+	assert.Equal(t, ConnectorStatusUnknown, connector.Status)
+}
@@ -0,0 +1,8 @@
+package sysfs
+
+import "errors"
+
+var (
+	ErrConnectorNotFound = errors.New("drm connector not found")
+	ErrEdidNotAvailable  = errors.New("edid not available")
+)
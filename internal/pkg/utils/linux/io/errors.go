@@ -21,6 +21,11 @@ var (
 	ErrResourceTemporarilyUnavailable = errors.New("resource temporarily unavailable")
 )
 
+// ParseErrno returns the error SendCtl reports for the errno, so simulated devices fail like the kernel does.
+func ParseErrno(errno sys.Errno) error {
+	return parseErrorType(errno)
+}
+
 func parseErrorType(errno sys.Errno) error {
 	switch errno {
 	case sys.EBADF, sys.ENOMEM, sys.ENODEV, sys.EIO, sys.ENXIO, sys.EFAULT: // structural, terminal
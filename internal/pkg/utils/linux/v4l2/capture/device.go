@@ -0,0 +1,602 @@
+//go:build linux
+
+package capture
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/rgb"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/io"
+	v4l2io "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/v4l2/io"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/mjpeg"
+	memorySDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const (
+	// bufferCount is number of buffers requested from the driver, UVC drivers usually need at least two to stream
+	// without dropping frames.
+	bufferCount = 4
+	// retryDelay is a delay between capture attempts, so unplugged device is not reopened in a busy loop.
+	retryDelay = time.Second
+)
+
+// FrameHandler receives RGB24 pixels of every captured frame and becomes the owner of the buffer.
+type FrameHandler func(memoryBuffer memorySDK.Buffer) error
+
+func DiscardFrameHandler(memoryBuffer memorySDK.Buffer) error {
+	return memoryBuffer.Release()
+}
+
+type DeviceOptions struct {
+	backend            v4l2io.Backend
+	memoryPoolProvider memorySDK.PoolProvider
+	frameHandler       FrameHandler
+	displayMode        *peripheralSDK.DisplayMode
+	pixelFormats       []v4l2io.PixelFormatCode
+	frameTimeout       time.Duration
+	logger             *slog.Logger
+}
+
+type DeviceOpt func(*DeviceOptions)
+
+// WithBackend sets backend the device node is opened with, SimulatedBackend runs the device without the hardware.
+func WithBackend(backend v4l2io.Backend) DeviceOpt {
+	return func(options *DeviceOptions) {
+		options.backend = backend
+	}
+}
+
+func WithMemoryPoolProvider(provider memorySDK.PoolProvider) DeviceOpt {
+	return func(options *DeviceOptions) {
+		options.memoryPoolProvider = provider
+	}
+}
+
+func WithFrameHandler(handler FrameHandler) DeviceOpt {
+	return func(options *DeviceOptions) {
+		options.frameHandler = handler
+	}
+}
+
+// WithDisplayMode sets display mode the captured format is selected to match best. The largest frame size with the
+// highest frame rate is captured when not set.
+func WithDisplayMode(displayMode peripheralSDK.DisplayMode) DeviceOpt {
+	return func(options *DeviceOptions) {
+		options.displayMode = &displayMode
+	}
+}
+
+// WithPixelFormats limits pixel formats captured from the device, formats listed first are preferred when they match
+// display mode equally.
+func WithPixelFormats(pixelFormats ...v4l2io.PixelFormatCode) DeviceOpt {
+	return func(options *DeviceOptions) {
+		options.pixelFormats = pixelFormats
+	}
+}
+
+// WithFrameTimeout sets how long the device may not deliver a frame before capture is restarted.
+func WithFrameTimeout(timeout time.Duration) DeviceOpt {
+	return func(options *DeviceOptions) {
+		options.frameTimeout = timeout
+	}
+}
+
+func WithLogger(logger *slog.Logger) DeviceOpt {
+	return func(options *DeviceOptions) {
+		options.logger = logger
+	}
+}
+
+func defaultDeviceOptions() DeviceOptions {
+	return DeviceOptions{
+		backend:            v4l2io.DefaultBackend,
+		memoryPoolProvider: memory.DefaultMemoryPoolProvider,
+		frameHandler:       DiscardFrameHandler,
+		pixelFormats:       DefaultPixelFormats,
+		frameTimeout:       time.Second * 5,
+		logger:             slog.New(slog.DiscardHandler),
+	}
+}
+
+// Device captures frames of a generic V4L2 capture device, like UVC webcam or USB HDMI grabber. Frames are converted
+// into RGB24 regardless of the pixel format captured.
+type Device struct {
+	devicePath string
+	backend    v4l2io.Backend
+
+	displayMode  *peripheralSDK.DisplayMode
+	pixelFormats []v4l2io.PixelFormatCode
+	frameTimeout time.Duration
+
+	currentDisplayMode     *peripheralSDK.DisplayMode
+	currentDisplayModeLock *sync.RWMutex
+
+	frameHandler FrameHandler
+	frameDecoder *mjpeg.Decoder
+	framePixels  []byte
+
+	memoryPool memorySDK.Pool
+	logger     *slog.Logger
+}
+
+func Open(ctx context.Context, devicePath string, opts ...DeviceOpt) (*Device, error) {
+	options := defaultDeviceOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	logger := options.logger.With(slog.String("devicePath", devicePath))
+
+	memoryPool, err := options.memoryPoolProvider()
+	if err != nil {
+		return nil, fmt.Errorf("memory pool: %w", err)
+	}
+
+	device := &Device{
+		devicePath: devicePath,
+		backend:    options.backend,
+
+		displayMode:  options.displayMode,
+		pixelFormats: options.pixelFormats,
+		frameTimeout: options.frameTimeout,
+
+		currentDisplayMode:     nil,
+		currentDisplayModeLock: &sync.RWMutex{},
+
+		frameHandler: options.frameHandler,
+		frameDecoder: mjpeg.NewDecoder(),
+
+		memoryPool: memoryPool,
+		logger:     logger,
+	}
+
+	videoDevice, err := device.openDevice()
+	if err != nil {
+		return nil, fmt.Errorf("open device: %w", err)
+	}
+
+	_, err = device.selectFormat(videoDevice)
+	if err != nil {
+		_ = device.closeDevice(videoDevice)
+		return nil, fmt.Errorf("select format: %w", err)
+	}
+
+	err = device.closeDevice(videoDevice)
+	if err != nil {
+		return nil, fmt.Errorf("close device: %w", err)
+	}
+
+	logger.Info("Device capabilities verified and it is ready for use.")
+
+	go device.controlLoop(ctx)
+
+	return device, nil
+}
+
+// GetDisplayMode returns display mode being captured, nil when the device is not capturing.
+func (device *Device) GetDisplayMode() (*peripheralSDK.DisplayMode, error) {
+	device.currentDisplayModeLock.RLock()
+	defer device.currentDisplayModeLock.RUnlock()
+
+	return device.currentDisplayMode, nil
+}
+
+func (device *Device) GetPixelFormat() (*peripheralSDK.DisplayPixelFormat, error) {
+	pixelFormat := peripheralSDK.DisplayPixelFormatRGB24
+	return &pixelFormat, nil
+}
+
+func (device *Device) controlLoop(ctx context.Context) {
+	for {
+		err := device.capture(ctx)
+
+		device.setCurrentDisplayMode(nil)
+
+		if ctx.Err() != nil {
+			device.logger.Debug("Control loop terminated.")
+			return
+		}
+
+		device.logger.Warn("Capture error. Retrying initialization.", slog.String("error", err.Error()))
+
+		select {
+		case <-ctx.Done():
+			device.logger.Debug("Control loop terminated.")
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// capture opens the device and streams frames until the context is done or the device stops delivering frames.
+func (device *Device) capture(ctx context.Context) error {
+	videoDevice, err := device.openDevice()
+	if err != nil {
+		return fmt.Errorf("open device: %w", err)
+	}
+
+	defer func() {
+		_ = device.closeDevice(videoDevice)
+	}()
+
+	candidate, err := device.selectFormat(videoDevice)
+	if err != nil {
+		return fmt.Errorf("select format: %w", err)
+	}
+
+	videoFormat, err := device.setupFormat(ctx, videoDevice, candidate)
+	if err != nil {
+		return fmt.Errorf("setup format: %w", err)
+	}
+
+	device.logger.Info("Video format set.",
+		slog.Int("inputWidth", int(videoFormat.Width)),
+		slog.Int("inputHeight", int(videoFormat.Height)),
+		slog.String("inputPixelFormat", videoFormat.PixelFormat.String()),
+		slog.Float64("inputFrameRate", candidate.Interval.GetFrameRate()),
+	)
+
+	buffers, err := device.initMemory(videoDevice)
+	if err != nil {
+		return fmt.Errorf("init memory: %w", err)
+	}
+
+	defer func() {
+		err := device.releaseMemory(ctx, videoDevice, buffers)
+		if err != nil {
+			device.logger.Warn("Release memory error.", slog.String("error", err.Error()))
+		}
+	}()
+
+	err = videoDevice.StartStream(v4l2io.BufferTypeVideoCapture)
+	if err != nil {
+		return fmt.Errorf("start stream: %w", err)
+	}
+
+	defer func() {
+		err := videoDevice.StopStream(v4l2io.BufferTypeVideoCapture)
+		if err != nil {
+			device.logger.Warn("Stop stream error.", slog.String("error", err.Error()))
+		}
+	}()
+
+	displayMode := candidate.GetDisplayMode()
+	displayMode.Width, displayMode.Height = videoFormat.Width, videoFormat.Height
+	device.setCurrentDisplayMode(&displayMode)
+
+	device.logger.Info("Capturing input from device.")
+
+	return device.streamLoop(ctx, videoDevice, buffers, videoFormat)
+}
+
+// streamLoop handles frames until the context is done. No frame delivered in frame timeout means the device was
+// unplugged or stalled, so the loop returns an error and the capture is restarted.
+func (device *Device) streamLoop(ctx context.Context, videoDevice v4l2io.Device, buffers v4l2io.BoundMmapBuffers, videoFormat v4l2io.VideoFormat) error {
+	streamCtx, streamCancel := context.WithCancel(ctx)
+	defer streamCancel()
+
+	pollEvents := videoDevice.Poll(streamCtx, io.PollEventInput)
+
+	frameTimer := time.NewTimer(device.frameTimeout)
+	defer frameTimer.Stop()
+
+	device.logger.Debug("Watching for frames.")
+
+	for {
+		select {
+		case <-ctx.Done():
+			device.logger.Debug("Stream watch terminated.")
+			return nil
+		case <-frameTimer.C:
+			return ErrFrameTimeout
+		case <-pollEvents:
+			if device.handleFrame(ctx, videoDevice, buffers, videoFormat) {
+				frameTimer.Reset(device.frameTimeout)
+			}
+		}
+	}
+}
+
+// handleFrame dequeues the frame and passes its pixels to the frame handler. It reports whether the device delivered
+// a frame, even when the frame was corrupted.
+func (device *Device) handleFrame(ctx context.Context, videoDevice v4l2io.Device, buffers v4l2io.BoundMmapBuffers, videoFormat v4l2io.VideoFormat) bool {
+	dequeueCtx, dequeueCancel := context.WithTimeout(ctx, time.Millisecond*100)
+	defer dequeueCancel()
+
+	bufferDescriptor, err := io.RetryOnErrorWithValue(dequeueCtx, func() (v4l2io.BufferDescriptor, error) {
+		return videoDevice.DequeueMmapBuffer(v4l2io.BufferTypeVideoCapture)
+	}, io.ErrTemporary, io.ErrTimeout)
+	if err != nil {
+		device.logger.Warn("Dequeue buffer error.", slog.String("error", err.Error()))
+		return false
+	}
+
+	defer func() {
+		err = videoDevice.QueueBuffer(bufferDescriptor)
+		if err != nil {
+			device.logger.Warn("Queue buffer error.", slog.String("error", err.Error()))
+		}
+	}()
+
+	if bufferDescriptor.Flags&v4l2io.BufferFlagError != 0 {
+		device.logger.Debug("Corrupted frame dropped.", slog.Int("sequence", int(bufferDescriptor.Sequence)))
+		return true
+	}
+
+	videoBuffer := buffers[bufferDescriptor.Index]
+
+	pixels, err := device.decodeFrame(videoBuffer.Data[:bufferDescriptor.BytesUsed], videoFormat)
+	if err != nil {
+		device.logger.Warn("Decode frame error.", slog.String("error", err.Error()))
+		return true
+	}
+
+	memoryBuffer, err := device.memoryPool.Borrow(len(pixels))
+	if err != nil {
+		device.logger.Warn("Borrow memory error.", slog.String("error", err.Error()))
+		return true
+	}
+
+	bytesWritten, err := memoryBuffer.Write(pixels)
+	if err != nil {
+		memoryBuffer.Release()
+		device.logger.Warn("Write memory error.", slog.String("error", err.Error()))
+		return true
+	}
+
+	if bytesWritten != len(pixels) {
+		memoryBuffer.Release()
+		device.logger.Warn("Write memory error.", slog.String("error", "bytes written not equal to frame size"))
+		return true
+	}
+
+	err = device.frameHandler(memoryBuffer)
+	if err != nil {
+		device.logger.Warn("Frame handler error.", slog.String("error", err.Error()))
+	}
+
+	return true
+}
+
+// decodeFrame returns RGB24 pixels of the frame, valid until the next call.
+func (device *Device) decodeFrame(frame []byte, videoFormat v4l2io.VideoFormat) ([]byte, error) {
+	width, height := int(videoFormat.Width), int(videoFormat.Height)
+
+	switch videoFormat.PixelFormat {
+	case v4l2io.PixelFormatCodeRGB24:
+		stride := max(int(videoFormat.BytesPerLine), width*3)
+		if len(frame) < stride*(height-1)+width*3 {
+			return nil, fmt.Errorf("%w: %d bytes", ErrFrameSizeMismatch, len(frame))
+		}
+
+		// lines padded by the driver are packed, unpadded frame is handed over as is
+		if stride == width*3 {
+			return frame[:width*height*3], nil
+		}
+
+		device.framePixels = rgb.FromRGB24(device.framePixels, frame, width, height, stride)
+
+		return device.framePixels, nil
+	case v4l2io.PixelFormatCodeYUYV:
+		stride := max(int(videoFormat.BytesPerLine), width*2)
+		if len(frame) < stride*(height-1)+width*2 {
+			return nil, fmt.Errorf("%w: %d bytes", ErrFrameSizeMismatch, len(frame))
+		}
+
+		device.framePixels = rgb.FromYUYV(device.framePixels, frame, width, height, stride)
+
+		return device.framePixels, nil
+	case v4l2io.PixelFormatCodeMJPEG:
+		pixels, decodedWidth, decodedHeight, err := device.frameDecoder.Decode(frame)
+		if err != nil {
+			return nil, err
+		}
+
+		if decodedWidth != width || decodedHeight != height {
+			return nil, fmt.Errorf("%w: %dx%d", ErrFrameSizeMismatch, decodedWidth, decodedHeight)
+		}
+
+		return pixels, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", peripheralSDK.ErrUnsupportedPixelFormat, videoFormat.PixelFormat.String())
+	}
+}
+
+// selectFormat enumerates formats of the device and returns the one best matching configured display mode.
+func (device *Device) selectFormat(videoDevice v4l2io.Device) (FormatCandidate, error) {
+	devicePixelFormats, err := videoDevice.ListPixelFormats(v4l2io.BufferTypeVideoCapture)
+	if err != nil {
+		return FormatCandidate{}, fmt.Errorf("list pixel formats: %w", err)
+	}
+
+	listFrameIntervals := func(pixelFormat v4l2io.PixelFormatCode, width uint32, height uint32) ([]v4l2io.FrameInterval, error) {
+		return videoDevice.ListFrameIntervals(pixelFormat, width, height)
+	}
+
+	candidates := []FormatCandidate{}
+	for _, devicePixelFormat := range devicePixelFormats {
+		if !slices.Contains(device.pixelFormats, devicePixelFormat.Code) {
+			continue
+		}
+
+		frameSizes, err := videoDevice.ListFrameSizes(devicePixelFormat.Code)
+		if err != nil {
+			return FormatCandidate{}, fmt.Errorf("list frame sizes of %s: %w", devicePixelFormat.Code.String(), err)
+		}
+
+		pixelFormatCandidates, err := CreateFormatCandidates(devicePixelFormat.Code, frameSizes, listFrameIntervals, device.displayMode)
+		if err != nil {
+			return FormatCandidate{}, err
+		}
+
+		candidates = append(candidates, pixelFormatCandidates...)
+	}
+
+	candidate, err := SelectFormatCandidate(candidates, device.displayMode, device.pixelFormats)
+	if err != nil {
+		return FormatCandidate{}, err
+	}
+
+	device.logger.Debug("Format selected.",
+		slog.String("format", candidate.String()),
+		slog.Int("candidateCount", len(candidates)),
+	)
+
+	return candidate, nil
+}
+
+func (device *Device) setupFormat(ctx context.Context, videoDevice v4l2io.Device, candidate FormatCandidate) (v4l2io.VideoFormat, error) {
+	formatSetupCtx, formatSetupCancel := context.WithTimeout(ctx, time.Second*10)
+	defer formatSetupCancel()
+
+	videoFormat := v4l2io.VideoFormat{
+		Width:       candidate.Width,
+		Height:      candidate.Height,
+		PixelFormat: candidate.PixelFormat,
+	}
+
+	err := io.RetryOnError(formatSetupCtx, func() error {
+		return videoDevice.SetVideoFormat(v4l2io.BufferTypeVideoCapture, videoFormat)
+	}, io.ErrDeviceOrResourceBusy)
+	if err != nil {
+		return v4l2io.EmptyVideoFormat, fmt.Errorf("set video format: %w", err)
+	}
+
+	videoFormat, err = videoDevice.GetVideoFormat(v4l2io.BufferTypeVideoCapture)
+	if err != nil {
+		return v4l2io.EmptyVideoFormat, fmt.Errorf("get video format: %w", err)
+	}
+
+	if videoFormat.PixelFormat != candidate.PixelFormat {
+		return v4l2io.EmptyVideoFormat, fmt.Errorf("%w: %s", peripheralSDK.ErrUnsupportedPixelFormat, videoFormat.PixelFormat.String())
+	}
+
+	// frame interval is optional for drivers, capture continues with the interval the driver keeps
+	interval, err := videoDevice.SetFrameInterval(v4l2io.BufferTypeVideoCapture, candidate.Interval)
+	if err != nil {
+		device.logger.Debug("Set frame interval error.", slog.String("error", err.Error()))
+	} else if math.Abs(interval.GetFrameRate()-candidate.Interval.GetFrameRate()) >= 1 {
+		device.logger.Debug("Frame interval adjusted by driver.", slog.Float64("frameRate", interval.GetFrameRate()))
+	}
+
+	return videoFormat, nil
+}
+
+func (device *Device) initMemory(videoDevice v4l2io.Device) (v4l2io.BoundMmapBuffers, error) {
+	count, err := videoDevice.RequestBuffers(v4l2io.BufferTypeVideoCapture, v4l2io.MemoryTypeMmap, bufferCount)
+	if err != nil {
+		return v4l2io.EmptyBoundMmapBuffers, fmt.Errorf("request buffers: %w", err)
+	}
+
+	buffers := make(v4l2io.BoundMmapBuffers, count)
+
+	unbindBuffers := func() {
+		for _, buffer := range buffers {
+			_ = videoDevice.UnbindMmapBuffer(buffer)
+		}
+	}
+
+	for bufferIndex := v4l2io.BufferIndex(0); bufferIndex < count; bufferIndex++ {
+		buffer, err := videoDevice.QueryMmapBuffer(v4l2io.BufferTypeVideoCapture, bufferIndex)
+		if err != nil {
+			unbindBuffers()
+			return v4l2io.EmptyBoundMmapBuffers, fmt.Errorf("query buffer: %d: %w", bufferIndex, err)
+		}
+
+		boundBuffer, err := videoDevice.BindMmapBuffer(buffer)
+		if err != nil {
+			unbindBuffers()
+			return v4l2io.EmptyBoundMmapBuffers, fmt.Errorf("bind buffer: %d: %w", bufferIndex, err)
+		}
+
+		buffers[bufferIndex] = boundBuffer
+
+		err = videoDevice.QueueBuffer(buffer.BufferDescriptor)
+		if err != nil {
+			unbindBuffers()
+			return v4l2io.EmptyBoundMmapBuffers, fmt.Errorf("queue buffer: %d: %w", bufferIndex, err)
+		}
+	}
+
+	device.logger.Debug("Memory initialized.", slog.Int("bufferCount", int(count)))
+
+	return buffers, nil
+}
+
+func (device *Device) releaseMemory(ctx context.Context, videoDevice v4l2io.Device, buffers v4l2io.BoundMmapBuffers) error {
+	releaseMemoryCtx, releaseMemoryCancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second*10)
+	defer releaseMemoryCancel()
+
+	for _, buffer := range buffers {
+		err := videoDevice.UnbindMmapBuffer(buffer)
+		if err != nil {
+			return fmt.Errorf("unbind buffer: %w", err)
+		}
+	}
+
+	err := io.RetryOnError(releaseMemoryCtx, func() error {
+		return videoDevice.ReleaseBuffers(v4l2io.BufferTypeVideoCapture, v4l2io.MemoryTypeMmap)
+	}, io.ErrDeviceOrResourceBusy)
+	if err != nil {
+		return fmt.Errorf("release buffers: %w", err)
+	}
+
+	device.logger.Debug("Memory released.")
+
+	return nil
+}
+
+func (device *Device) openDevice() (v4l2io.Device, error) {
+	videoDevice, err := device.backend.Open(device.devicePath)
+	if err != nil {
+		return nil, fmt.Errorf("open device: %w", err)
+	}
+
+	capabilities, err := videoDevice.QueryCapabilities()
+	if err != nil {
+		_ = videoDevice.Close()
+		return nil, err
+	}
+
+	if !capabilities.Features.VideoCapture {
+		_ = videoDevice.Close()
+		return nil, ErrVideoCaptureNotSupported
+	}
+
+	if !capabilities.Features.Streaming {
+		_ = videoDevice.Close()
+		return nil, ErrStreamingNotSupported
+	}
+
+	device.logger.Debug("Device open.",
+		slog.String("deviceDriver", capabilities.Driver),
+		slog.String("deviceCard", capabilities.Card),
+		slog.String("deviceBus", capabilities.BusInfo),
+	)
+
+	return videoDevice, nil
+}
+
+func (device *Device) closeDevice(videoDevice v4l2io.Device) error {
+	err := videoDevice.Close()
+
+	device.logger.Debug("Device closed.")
+
+	return err
+}
+
+func (device *Device) setCurrentDisplayMode(displayMode *peripheralSDK.DisplayMode) {
+	device.currentDisplayModeLock.Lock()
+	defer device.currentDisplayModeLock.Unlock()
+
+	device.currentDisplayMode = displayMode
+}
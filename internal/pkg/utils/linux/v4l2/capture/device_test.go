@@ -0,0 +1,194 @@
+//go:build linux
+
+package capture
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/io"
+	v4l2io "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/v4l2/io"
+	memorySDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func createMemoryPoolProvider(t *testing.T) memorySDK.PoolProvider {
+	t.Helper()
+
+	memoryPool, err := memory.NewHeapPool(64*48*3, 16)
+	require.NoError(t, err)
+
+	return func() (memorySDK.Pool, error) { return memoryPool, nil }
+}
+
+// openSimulatedDevice opens the capture device on the backend, pixels of captured frames are sent to the returned
+// channel and dropped when it is full.
+func openSimulatedDevice(t *testing.T, backend *v4l2io.SimulatedBackend, opts ...DeviceOpt) (*Device, <-chan []byte) {
+	t.Helper()
+
+	frames := make(chan []byte, 4)
+
+	frameHandler := func(memoryBuffer memorySDK.Buffer) error {
+		defer memoryBuffer.Release()
+
+		pixels := bytes.Buffer{}
+		if _, err := memoryBuffer.WriteTo(&pixels); err != nil {
+			return err
+		}
+
+		select {
+		case frames <- pixels.Bytes():
+		default:
+		}
+
+		return nil
+	}
+
+	opts = append([]DeviceOpt{
+		WithBackend(backend),
+		WithMemoryPoolProvider(createMemoryPoolProvider(t)),
+		WithFrameHandler(frameHandler),
+	}, opts...)
+
+	device, err := Open(t.Context(), "/dev/video0", opts...)
+	require.NoError(t, err)
+
+	return device, frames
+}
+
+func receiveFrame(t *testing.T, frames <-chan []byte, timeout time.Duration) []byte {
+	t.Helper()
+
+	select {
+	case pixels := <-frames:
+		return pixels
+	case <-time.After(timeout):
+		require.FailNow(t, "no frame captured in time")
+		return nil
+	}
+}
+
+func drainFrames(frames <-chan []byte) {
+	for {
+		select {
+		case <-frames:
+		default:
+			return
+		}
+	}
+}
+
+func TestDeviceCapturesRGB24FramesWithPaddedLines(t *testing.T) {
+	backend := v4l2io.NewSimulatedBackend(
+		v4l2io.WithSimulatedTimings(v4l2io.NewSimulatedTimings(5, 4, 100)),
+		v4l2io.WithSimulatedBytesPerLineAlignment(16),
+	)
+
+	device, frames := openSimulatedDevice(t, backend, WithPixelFormats(v4l2io.PixelFormatCodeRGB24))
+
+	pixels := receiveFrame(t, frames, time.Second)
+	require.Len(t, pixels, 5*4*3)
+
+	// every line of the gradient has a single gray level, one above the previous line
+	for y := range 4 {
+		line := pixels[y*15 : (y+1)*15]
+		assert.Equal(t, bytes.Repeat([]byte{pixels[0] + byte(y)}, 15), line, "line %d", y)
+	}
+
+	displayMode, err := device.GetDisplayMode()
+	require.NoError(t, err)
+	assert.Equal(t, &peripheralSDK.DisplayMode{Width: 5, Height: 4, RefreshRate: 100}, displayMode)
+}
+
+func TestDeviceCapturesYUYVFrames(t *testing.T) {
+	backend := v4l2io.NewSimulatedBackend(
+		v4l2io.WithSimulatedTimings(v4l2io.NewSimulatedTimings(4, 2, 100)),
+		v4l2io.WithSimulatedPixelFormats(v4l2io.PixelFormatCodeYUYV),
+	)
+
+	_, frames := openSimulatedDevice(t, backend)
+
+	pixels := receiveFrame(t, frames, time.Second)
+	assert.Len(t, pixels, 4*2*3)
+}
+
+func TestDeviceRestartsCaptureWhenFramesStop(t *testing.T) {
+	timings := v4l2io.NewSimulatedTimings(8, 4, 100)
+	backend := v4l2io.NewSimulatedBackend(v4l2io.WithSimulatedTimings(timings))
+
+	device, frames := openSimulatedDevice(t, backend, WithFrameTimeout(time.Millisecond*200))
+
+	receiveFrame(t, frames, time.Second)
+
+	backend.SetTimings(nil)
+
+	assert.Eventually(t, func() bool {
+		displayMode, err := device.GetDisplayMode()
+		return err == nil && displayMode == nil
+	}, time.Second, time.Millisecond*10)
+
+	drainFrames(frames)
+	backend.SetTimings(&timings)
+
+	receiveFrame(t, frames, retryDelay*3)
+}
+
+func TestDeviceRetriesFailedCapture(t *testing.T) {
+	backend := v4l2io.NewSimulatedBackend(v4l2io.WithSimulatedTimings(v4l2io.NewSimulatedTimings(8, 4, 100)))
+	backend.InjectError(v4l2io.SimulatedOperationStartStream, unix.EBUSY, 1)
+
+	_, frames := openSimulatedDevice(t, backend)
+
+	receiveFrame(t, frames, retryDelay*3)
+}
+
+func TestOpenFailsWithoutCaptureDevice(t *testing.T) {
+	backend := v4l2io.NewSimulatedBackend()
+	backend.InjectError(v4l2io.SimulatedOperationOpen, unix.ENOENT, 1)
+
+	_, err := Open(t.Context(), "/dev/video0", WithBackend(backend), WithMemoryPoolProvider(createMemoryPoolProvider(t)))
+	assert.ErrorIs(t, err, io.ErrNoEntity)
+
+	backend = v4l2io.NewSimulatedBackend(v4l2io.WithSimulatedCapability(v4l2io.Capability{
+		Features: v4l2io.CapabilityFeatures{Streaming: true},
+	}))
+
+	_, err = Open(t.Context(), "/dev/video0", WithBackend(backend), WithMemoryPoolProvider(createMemoryPoolProvider(t)))
+	assert.ErrorIs(t, err, ErrVideoCaptureNotSupported)
+
+	backend = v4l2io.NewSimulatedBackend(v4l2io.WithSimulatedPixelFormats(v4l2io.PixelFormatCodeMJPEG))
+
+	_, err = Open(t.Context(), "/dev/video0", WithBackend(backend), WithMemoryPoolProvider(createMemoryPoolProvider(t)), WithPixelFormats(v4l2io.PixelFormatCodeRGB24))
+	assert.ErrorIs(t, err, ErrNoFormatCandidate)
+}
+
+func TestDeviceDecodeFrameRGB24(t *testing.T) {
+	device := &Device{}
+
+	videoFormat := v4l2io.VideoFormat{PixelFormat: v4l2io.PixelFormatCodeRGB24, Width: 2, Height: 2, BytesPerLine: 6}
+	pixels, err := device.decodeFrame([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, videoFormat)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, pixels)
+
+	// padding of every line is dropped, the last line may come without it
+	videoFormat.BytesPerLine = 8
+	pixels, err = device.decodeFrame([]byte{1, 2, 3, 4, 5, 6, 0xff, 0xff, 7, 8, 9, 10, 11, 12}, videoFormat)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, pixels)
+
+	_, err = device.decodeFrame([]byte{1, 2, 3, 4, 5, 6, 0xff, 0xff, 7, 8, 9}, videoFormat)
+	assert.ErrorIs(t, err, ErrFrameSizeMismatch)
+}
+
+func TestDeviceDecodeFrameUnsupportedPixelFormat(t *testing.T) {
+	device := &Device{}
+
+	_, err := device.decodeFrame([]byte{}, v4l2io.VideoFormat{PixelFormat: v4l2io.PixelFormatCode(0), Width: 2, Height: 2})
+	assert.ErrorIs(t, err, peripheralSDK.ErrUnsupportedPixelFormat)
+}
@@ -0,0 +1,11 @@
+package capture
+
+import "errors"
+
+var (
+	ErrVideoCaptureNotSupported = errors.New("video capture not supported")
+	ErrStreamingNotSupported    = errors.New("streaming not supported")
+	ErrNoFormatCandidate        = errors.New("no supported format")
+	ErrFrameTimeout             = errors.New("no frame received in time")
+	ErrFrameSizeMismatch        = errors.New("frame size does not match video format")
+)
@@ -0,0 +1,163 @@
+//go:build linux
+
+package capture
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	v4l2io "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/v4l2/io"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// DefaultPixelFormats are pixel formats decoded by the device, ordered by cost of decoding.
+var DefaultPixelFormats = []v4l2io.PixelFormatCode{
+	v4l2io.PixelFormatCodeRGB24,
+	v4l2io.PixelFormatCodeYUYV,
+	v4l2io.PixelFormatCodeMJPEG,
+}
+
+// FormatCandidate is a combination of pixel format, frame size and frame interval the device can capture.
+type FormatCandidate struct {
+	PixelFormat v4l2io.PixelFormatCode `json:"pixelFormat"`
+	Width       uint32                 `json:"width"`
+	Height      uint32                 `json:"height"`
+	Interval    v4l2io.Fraction        `json:"interval"`
+}
+
+// GetDisplayMode returns display mode of the candidate with frame rate rounded to whole frames per second.
+func (candidate FormatCandidate) GetDisplayMode() peripheralSDK.DisplayMode {
+	return peripheralSDK.DisplayMode{
+		Width:       candidate.Width,
+		Height:      candidate.Height,
+		RefreshRate: uint32(math.Round(candidate.Interval.GetFrameRate())),
+	}
+}
+
+func (candidate FormatCandidate) String() string {
+	return fmt.Sprintf("%s %dx%d@%.2f", candidate.PixelFormat.String(), candidate.Width, candidate.Height, candidate.Interval.GetFrameRate())
+}
+
+// FrameIntervalLister returns frame intervals of the pixel format and frame size.
+type FrameIntervalLister func(pixelFormat v4l2io.PixelFormatCode, width uint32, height uint32) ([]v4l2io.FrameInterval, error)
+
+// CreateFormatCandidates returns candidates of the pixel format. Continuous and stepwise frame sizes and intervals are
+// narrowed to the values nearest to the display mode, or to the largest size and the shortest interval when display
+// mode is nil.
+func CreateFormatCandidates(pixelFormat v4l2io.PixelFormatCode, frameSizes []v4l2io.FrameSize, listFrameIntervals FrameIntervalLister, displayMode *peripheralSDK.DisplayMode) ([]FormatCandidate, error) {
+	candidates := []FormatCandidate{}
+
+	for _, frameSize := range frameSizes {
+		width, height := frameSize.MaxWidth, frameSize.MaxHeight
+		if displayMode != nil {
+			width = clampToStep(displayMode.Width, frameSize.MinWidth, frameSize.MaxWidth, frameSize.StepWidth)
+			height = clampToStep(displayMode.Height, frameSize.MinHeight, frameSize.MaxHeight, frameSize.StepHeight)
+		}
+
+		frameIntervals, err := listFrameIntervals(pixelFormat, width, height)
+		if err != nil {
+			return nil, fmt.Errorf("list frame intervals of %s %dx%d: %w", pixelFormat.String(), width, height, err)
+		}
+
+		for _, frameInterval := range frameIntervals {
+			interval := frameInterval.Min
+			if displayMode != nil && frameInterval.Type != v4l2io.FrameIntervalTypeDiscrete {
+				interval = nearestInterval(frameInterval, displayMode.RefreshRate)
+			}
+
+			if interval.GetFrameRate() == 0 {
+				continue
+			}
+
+			candidates = append(candidates, FormatCandidate{
+				PixelFormat: pixelFormat,
+				Width:       width,
+				Height:      height,
+				Interval:    interval,
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// SelectFormatCandidate returns the candidate best matching the display mode. Frame size closest to the display mode
+// wins, then frame rate closest to its refresh rate and then the pixel format listed first. Without display mode the
+// largest frame size with the highest frame rate is selected. Candidates of pixel formats not listed are ignored.
+func SelectFormatCandidate(candidates []FormatCandidate, displayMode *peripheralSDK.DisplayMode, pixelFormats []v4l2io.PixelFormatCode) (FormatCandidate, error) {
+	candidates = slices.DeleteFunc(slices.Clone(candidates), func(candidate FormatCandidate) bool {
+		return !slices.Contains(pixelFormats, candidate.PixelFormat)
+	})
+
+	if len(candidates) == 0 {
+		return FormatCandidate{}, ErrNoFormatCandidate
+	}
+
+	compareArea := func(a FormatCandidate, b FormatCandidate) int {
+		return -cmp.Compare(uint64(a.Width)*uint64(a.Height), uint64(b.Width)*uint64(b.Height))
+	}
+
+	compareFrameRate := func(a FormatCandidate, b FormatCandidate) int {
+		return -cmp.Compare(a.Interval.GetFrameRate(), b.Interval.GetFrameRate())
+	}
+
+	if displayMode != nil {
+		compareArea = func(a FormatCandidate, b FormatCandidate) int {
+			return cmp.Or(
+				cmp.Compare(sizeDistance(a, *displayMode), sizeDistance(b, *displayMode)),
+				-cmp.Compare(uint64(a.Width)*uint64(a.Height), uint64(b.Width)*uint64(b.Height)),
+			)
+		}
+
+		compareFrameRate = func(a FormatCandidate, b FormatCandidate) int {
+			return cmp.Or(
+				cmp.Compare(frameRateDistance(a, *displayMode), frameRateDistance(b, *displayMode)),
+				-cmp.Compare(a.Interval.GetFrameRate(), b.Interval.GetFrameRate()),
+			)
+		}
+	}
+
+	return slices.MinFunc(candidates, func(a FormatCandidate, b FormatCandidate) int {
+		return cmp.Or(
+			compareArea(a, b),
+			compareFrameRate(a, b),
+			cmp.Compare(slices.Index(pixelFormats, a.PixelFormat), slices.Index(pixelFormats, b.PixelFormat)),
+		)
+	}), nil
+}
+
+// sizeDistance is zero for the frame size of the display mode and grows with differences of both dimensions.
+func sizeDistance(candidate FormatCandidate, displayMode peripheralSDK.DisplayMode) uint64 {
+	widthDistance := max(candidate.Width, displayMode.Width) - min(candidate.Width, displayMode.Width)
+	heightDistance := max(candidate.Height, displayMode.Height) - min(candidate.Height, displayMode.Height)
+
+	return uint64(widthDistance) + uint64(heightDistance)
+}
+
+func frameRateDistance(candidate FormatCandidate, displayMode peripheralSDK.DisplayMode) float64 {
+	return math.Abs(candidate.Interval.GetFrameRate() - float64(displayMode.RefreshRate))
+}
+
+// clampToStep returns value limited to the range and aligned down to the step from the range minimum.
+func clampToStep(value uint32, minValue uint32, maxValue uint32, step uint32) uint32 {
+	value = min(max(value, minValue), maxValue)
+
+	return minValue + (value-minValue)/max(step, 1)*max(step, 1)
+}
+
+// nearestInterval returns interval of the refresh rate limited to the interval range. Intervals in between the steps
+// are adjusted by the driver.
+func nearestInterval(frameInterval v4l2io.FrameInterval, refreshRate uint32) v4l2io.Fraction {
+	frameRate := float64(refreshRate)
+
+	switch {
+	case frameRate >= frameInterval.Min.GetFrameRate():
+		return frameInterval.Min
+	case frameRate <= frameInterval.Max.GetFrameRate():
+		return frameInterval.Max
+	default:
+		return v4l2io.Fraction{Numerator: 1, Denominator: refreshRate}
+	}
+}
@@ -0,0 +1,123 @@
+//go:build linux
+
+package capture
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v4l2io "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/v4l2/io"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+func createCandidate(pixelFormat v4l2io.PixelFormatCode, width uint32, height uint32, frameRate uint32) FormatCandidate {
+	return FormatCandidate{
+		PixelFormat: pixelFormat,
+		Width:       width,
+		Height:      height,
+		Interval:    v4l2io.Fraction{Numerator: 1, Denominator: frameRate},
+	}
+}
+
+// webcamCandidates resemble a typical UVC webcam, which streams large frames at full rate only with MJPEG.
+var webcamCandidates = []FormatCandidate{
+	createCandidate(v4l2io.PixelFormatCodeYUYV, 640, 480, 30),
+	createCandidate(v4l2io.PixelFormatCodeYUYV, 1280, 720, 10),
+	createCandidate(v4l2io.PixelFormatCodeYUYV, 1920, 1080, 5),
+	createCandidate(v4l2io.PixelFormatCodeMJPEG, 640, 480, 30),
+	createCandidate(v4l2io.PixelFormatCodeMJPEG, 1280, 720, 30),
+	createCandidate(v4l2io.PixelFormatCodeMJPEG, 1920, 1080, 30),
+	createCandidate(v4l2io.PixelFormatCodeMJPEG, 1920, 1080, 60),
+}
+
+func TestSelectFormatCandidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		displayMode  *peripheralSDK.DisplayMode
+		pixelFormats []v4l2io.PixelFormatCode
+		expected     FormatCandidate
+	}{
+		{
+			name:         "largest and fastest without display mode",
+			pixelFormats: DefaultPixelFormats,
+			expected:     createCandidate(v4l2io.PixelFormatCodeMJPEG, 1920, 1080, 60),
+		},
+		{
+			name:         "exact display mode",
+			displayMode:  &peripheralSDK.DisplayMode{Width: 1920, Height: 1080, RefreshRate: 30},
+			pixelFormats: DefaultPixelFormats,
+			expected:     createCandidate(v4l2io.PixelFormatCodeMJPEG, 1920, 1080, 30),
+		},
+		{
+			name:         "preferred pixel format on equal match",
+			displayMode:  &peripheralSDK.DisplayMode{Width: 640, Height: 480, RefreshRate: 30},
+			pixelFormats: DefaultPixelFormats,
+			expected:     createCandidate(v4l2io.PixelFormatCodeYUYV, 640, 480, 30),
+		},
+		{
+			name:         "frame size wins over frame rate",
+			displayMode:  &peripheralSDK.DisplayMode{Width: 1280, Height: 720, RefreshRate: 30},
+			pixelFormats: []v4l2io.PixelFormatCode{v4l2io.PixelFormatCodeYUYV},
+			expected:     createCandidate(v4l2io.PixelFormatCodeYUYV, 1280, 720, 10),
+		},
+		{
+			name:         "nearest frame size",
+			displayMode:  &peripheralSDK.DisplayMode{Width: 1600, Height: 900, RefreshRate: 60},
+			pixelFormats: DefaultPixelFormats,
+			expected:     createCandidate(v4l2io.PixelFormatCodeMJPEG, 1920, 1080, 60),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidate, err := SelectFormatCandidate(webcamCandidates, test.displayMode, test.pixelFormats)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, candidate)
+		})
+	}
+}
+
+func TestSelectFormatCandidateNoCandidate(t *testing.T) {
+	_, err := SelectFormatCandidate(webcamCandidates, nil, []v4l2io.PixelFormatCode{v4l2io.PixelFormatCodeRGB24})
+
+	assert.ErrorIs(t, err, ErrNoFormatCandidate)
+}
+
+func TestCreateFormatCandidates(t *testing.T) {
+	frameSizes := []v4l2io.FrameSize{
+		{
+			Type:     v4l2io.FrameSizeTypeStepwise,
+			MinWidth: 320, MaxWidth: 1920, StepWidth: 16,
+			MinHeight: 240, MaxHeight: 1080, StepHeight: 8,
+		},
+	}
+
+	listFrameIntervals := func(pixelFormat v4l2io.PixelFormatCode, width uint32, height uint32) ([]v4l2io.FrameInterval, error) {
+		return []v4l2io.FrameInterval{
+			{
+				Type: v4l2io.FrameIntervalTypeContinuous,
+				Min:  v4l2io.Fraction{Numerator: 1, Denominator: 60},
+				Max:  v4l2io.Fraction{Numerator: 1, Denominator: 1},
+				Step: v4l2io.Fraction{Numerator: 1, Denominator: 1},
+			},
+		}, nil
+	}
+
+	t.Run("display mode", func(t *testing.T) {
+		candidates, err := CreateFormatCandidates(v4l2io.PixelFormatCodeYUYV, frameSizes, listFrameIntervals,
+			&peripheralSDK.DisplayMode{Width: 1366, Height: 770, RefreshRate: 25})
+
+		require.NoError(t, err)
+		assert.Equal(t, []FormatCandidate{createCandidate(v4l2io.PixelFormatCodeYUYV, 1360, 768, 25)}, candidates)
+	})
+
+	t.Run("no display mode", func(t *testing.T) {
+		candidates, err := CreateFormatCandidates(v4l2io.PixelFormatCodeYUYV, frameSizes, listFrameIntervals, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []FormatCandidate{createCandidate(v4l2io.PixelFormatCodeYUYV, 1920, 1080, 60)}, candidates)
+	})
+}
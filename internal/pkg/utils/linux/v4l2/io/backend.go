@@ -0,0 +1,174 @@
+//go:build linux
+
+package io
+
+import (
+	"context"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/io"
+	"golang.org/x/sys/unix"
+)
+
+// Backend opens V4L2 device nodes. KernelBackend sends ioctls to the kernel, SimulatedBackend emulates a capture
+// device in process, so drivers can be exercised without hardware.
+type Backend interface {
+	Open(devicePath string) (Device, error)
+}
+
+// Device is an open V4L2 device node. Methods behave like functions of this package called with its descriptor and
+// fail with the same errors.
+type Device interface {
+	Close() error
+
+	QueryCapabilities() (Capability, error)
+
+	GetEdid(pad EdidPad) ([]byte, error)
+	SetEdid(pad EdidPad, edid []byte) error
+
+	SubscribeEvent(eventType EventType) error
+	DequeueEvent() (Event, error)
+
+	QueryDigitalVideoBTTimings() (DigitalVideoBTTimings, error)
+	SetDigitalVideoBTTimings(timings DigitalVideoBTTimings) error
+
+	ListPixelFormats(bufferType BufferType) (PixelFormatList, error)
+	TryVideoFormat(bufferType BufferType, format VideoFormat) (VideoFormat, error)
+	SetVideoFormat(bufferType BufferType, format VideoFormat) error
+	GetVideoFormat(bufferType BufferType) (VideoFormat, error)
+
+	ListFrameSizes(pixelFormat PixelFormatCode) ([]FrameSize, error)
+	ListFrameIntervals(pixelFormat PixelFormatCode, width uint32, height uint32) ([]FrameInterval, error)
+	SetFrameInterval(bufferType BufferType, interval Fraction) (Fraction, error)
+
+	RequestBuffers(bufferType BufferType, memoryType MemoryType, count uint32) (uint32, error)
+	ReleaseBuffers(bufferType BufferType, memoryType MemoryType) error
+	QueryMmapBuffer(bufferType BufferType, index BufferIndex) (MmapBuffer, error)
+	BindMmapBuffer(buffer MmapBuffer) (BoundMmapBuffer, error)
+	UnbindMmapBuffer(buffer BoundMmapBuffer) error
+	QueueBuffer(bufferDescriptor BufferDescriptor) error
+	DequeueMmapBuffer(bufferType BufferType) (BufferDescriptor, error)
+
+	StartStream(bufferType BufferType) error
+	StopStream(bufferType BufferType) error
+
+	// Poll reports readiness of the device until the context is done, like io.Poll does for descriptors.
+	Poll(ctx context.Context, events ...io.PollEvent) <-chan io.PollEvent
+}
+
+// KernelBackend opens device nodes in non-blocking mode and sends ioctls to their drivers.
+type KernelBackend struct{}
+
+var DefaultBackend Backend = KernelBackend{}
+
+func (backend KernelBackend) Open(devicePath string) (Device, error) {
+	descriptor, err := io.OpenDevice(devicePath, unix.O_RDWR|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kernelDevice{descriptor: descriptor}, nil
+}
+
+type kernelDevice struct {
+	descriptor io.DeviceDescriptor
+}
+
+func (device *kernelDevice) Close() error {
+	return io.Close(device.descriptor)
+}
+
+func (device *kernelDevice) QueryCapabilities() (Capability, error) {
+	return QueryCapabilities(device.descriptor)
+}
+
+func (device *kernelDevice) GetEdid(pad EdidPad) ([]byte, error) {
+	return GetEdid(device.descriptor, pad)
+}
+
+func (device *kernelDevice) SetEdid(pad EdidPad, edid []byte) error {
+	return SetEdid(device.descriptor, pad, edid)
+}
+
+func (device *kernelDevice) SubscribeEvent(eventType EventType) error {
+	return SubscribeEvent(device.descriptor, eventType)
+}
+
+func (device *kernelDevice) DequeueEvent() (Event, error) {
+	return DequeueEvent(device.descriptor)
+}
+
+func (device *kernelDevice) QueryDigitalVideoBTTimings() (DigitalVideoBTTimings, error) {
+	return QueryDigitalVideoBTTimings(device.descriptor)
+}
+
+func (device *kernelDevice) SetDigitalVideoBTTimings(timings DigitalVideoBTTimings) error {
+	return SetDigitalVideoBTTimings(device.descriptor, timings)
+}
+
+func (device *kernelDevice) ListPixelFormats(bufferType BufferType) (PixelFormatList, error) {
+	return ListPixelFormats(device.descriptor, bufferType)
+}
+
+func (device *kernelDevice) TryVideoFormat(bufferType BufferType, format VideoFormat) (VideoFormat, error) {
+	return TryVideoFormat(device.descriptor, bufferType, format)
+}
+
+func (device *kernelDevice) SetVideoFormat(bufferType BufferType, format VideoFormat) error {
+	return SetVideoFormat(device.descriptor, bufferType, format)
+}
+
+func (device *kernelDevice) GetVideoFormat(bufferType BufferType) (VideoFormat, error) {
+	return GetVideoFormat(device.descriptor, bufferType)
+}
+
+func (device *kernelDevice) ListFrameSizes(pixelFormat PixelFormatCode) ([]FrameSize, error) {
+	return ListFrameSizes(device.descriptor, pixelFormat)
+}
+
+func (device *kernelDevice) ListFrameIntervals(pixelFormat PixelFormatCode, width uint32, height uint32) ([]FrameInterval, error) {
+	return ListFrameIntervals(device.descriptor, pixelFormat, width, height)
+}
+
+func (device *kernelDevice) SetFrameInterval(bufferType BufferType, interval Fraction) (Fraction, error) {
+	return SetFrameInterval(device.descriptor, bufferType, interval)
+}
+
+func (device *kernelDevice) RequestBuffers(bufferType BufferType, memoryType MemoryType, count uint32) (uint32, error) {
+	return RequestBuffers(device.descriptor, bufferType, memoryType, count)
+}
+
+func (device *kernelDevice) ReleaseBuffers(bufferType BufferType, memoryType MemoryType) error {
+	return ReleaseBuffers(device.descriptor, bufferType, memoryType)
+}
+
+func (device *kernelDevice) QueryMmapBuffer(bufferType BufferType, index BufferIndex) (MmapBuffer, error) {
+	return QueryMmapBuffer(device.descriptor, bufferType, index)
+}
+
+func (device *kernelDevice) BindMmapBuffer(buffer MmapBuffer) (BoundMmapBuffer, error) {
+	return BindMmapBuffer(device.descriptor, buffer)
+}
+
+func (device *kernelDevice) UnbindMmapBuffer(buffer BoundMmapBuffer) error {
+	return UnbindMmapBuffer(buffer)
+}
+
+func (device *kernelDevice) QueueBuffer(bufferDescriptor BufferDescriptor) error {
+	return QueueBuffer(device.descriptor, bufferDescriptor)
+}
+
+func (device *kernelDevice) DequeueMmapBuffer(bufferType BufferType) (BufferDescriptor, error) {
+	return DequeueMmapBuffer(device.descriptor, bufferType)
+}
+
+func (device *kernelDevice) StartStream(bufferType BufferType) error {
+	return StartStream(device.descriptor, bufferType)
+}
+
+func (device *kernelDevice) StopStream(bufferType BufferType) error {
+	return StopStream(device.descriptor, bufferType)
+}
+
+func (device *kernelDevice) Poll(ctx context.Context, events ...io.PollEvent) <-chan io.PollEvent {
+	return io.Poll(ctx, device.descriptor, events...)
+}
@@ -106,9 +106,14 @@ func RequestBuffers(descriptor io.DeviceDescriptor, bufferType BufferType, memor
 	return allocatedCount, nil
 }
 
+// ReleaseBuffers frees buffers allocated by RequestBuffers, they have to be unbound first.
+// Uses VIDIOC_REQBUFS ioctl with zero count.
 func ReleaseBuffers(descriptor io.DeviceDescriptor, bufferType BufferType, memoryType MemoryType) error {
-	_, err := RequestBuffers(descriptor, bufferType, memoryType, 0)
-	return err
+	var rawRequestBuffers C.struct_v4l2_requestbuffers
+	rawRequestBuffers._type = C.__u32(bufferType)
+	rawRequestBuffers.memory = C.__u32(memoryType)
+
+	return io.SendCtl(descriptor, C.VIDIOC_REQBUFS, uintptr(unsafe.Pointer(&rawRequestBuffers)))
 }
 
 // QueryMmapBuffer retrieves information about an MMAP buffer.
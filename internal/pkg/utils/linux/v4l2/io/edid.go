@@ -2,12 +2,96 @@
 
 package io
 
-import "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/io"
+/*
+#cgo linux CFLAGS: -I ${SRCDIR}/../include/
+#include <stdlib.h>
+#include <linux/videodev2.h>
+*/
+import "C"
 
-func GetEdid(descriptor io.DeviceDescriptor) error {
-	panic("not implemented")
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/io"
+)
+
+const EdidBlockLength = 128
+
+// EdidMaxBlocks is the maximum number of blocks accepted by VIDIOC_S_EDID.
+const EdidMaxBlocks = 256
+
+type EdidPad uint32
+
+// GetEdid reads all EDID blocks of the pad. Empty slice is returned when no EDID is programmed.
+// Uses VIDIOC_G_EDID ioctl, first to query the block count and then to read the blocks.
+func GetEdid(descriptor io.DeviceDescriptor, pad EdidPad) ([]byte, error) {
+	var rawEdid C.struct_v4l2_edid
+	rawEdid.pad = C.__u32(pad)
+
+	err := io.SendCtl(descriptor, C.VIDIOC_G_EDID, uintptr(unsafe.Pointer(&rawEdid)))
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := int(rawEdid.blocks)
+	if blocks == 0 {
+		return []byte{}, nil
+	}
+
+	edidMemory := C.malloc(C.size_t(blocks * EdidBlockLength))
+	defer C.free(edidMemory)
+
+	rawEdid.start_block = 0
+	rawEdid.blocks = C.__u32(blocks)
+	rawEdid.edid = (*C.__u8)(edidMemory)
+
+	err = io.SendCtl(descriptor, C.VIDIOC_G_EDID, uintptr(unsafe.Pointer(&rawEdid)))
+	if err != nil {
+		return nil, err
+	}
+
+	return C.GoBytes(edidMemory, C.int(int(rawEdid.blocks)*EdidBlockLength)), nil
 }
 
-func SetEdid(descriptor io.DeviceDescriptor) error {
-	panic("not implemented")
+// SetEdid programs EDID blocks of the pad, empty edid clears it.
+// Uses VIDIOC_S_EDID ioctl.
+func SetEdid(descriptor io.DeviceDescriptor, pad EdidPad, edid []byte) error {
+	if len(edid)%EdidBlockLength != 0 {
+		return ErrEdidLengthInvalid
+	}
+
+	blocks := len(edid) / EdidBlockLength
+	if blocks > EdidMaxBlocks {
+		return ErrEdidTooManyBlocks
+	}
+
+	var rawEdid C.struct_v4l2_edid
+	rawEdid.pad = C.__u32(pad)
+	rawEdid.blocks = C.__u32(blocks)
+
+	if blocks > 0 {
+		edidMemory := C.CBytes(edid)
+		defer C.free(edidMemory)
+
+		rawEdid.edid = (*C.__u8)(edidMemory)
+	}
+
+	err := io.SendCtl(descriptor, C.VIDIOC_S_EDID, uintptr(unsafe.Pointer(&rawEdid)))
+	if err != nil {
+		// driver reports the number of blocks it accepts when edid is too long for it
+		if int(rawEdid.blocks) < blocks {
+			return fmt.Errorf("%w: %d blocks, device accepts %d", ErrEdidTooManyBlocks, blocks, int(rawEdid.blocks))
+		}
+
+		return err
+	}
+
+	return nil
 }
+
+var (
+	ErrEdidLengthInvalid = errors.New("edid length is not a multiple of block length")
+	ErrEdidTooManyBlocks = errors.New("edid has too many blocks")
+)
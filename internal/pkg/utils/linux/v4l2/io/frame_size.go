@@ -0,0 +1,180 @@
+//go:build linux
+
+package io
+
+/*
+#cgo linux CFLAGS: -I ${SRCDIR}/../include/
+#include <linux/videodev2.h>
+
+static inline struct v4l2_frmsize_discrete* frame_size_discrete(struct v4l2_frmsizeenum* f) { return &f->discrete; }
+static inline struct v4l2_frmsize_stepwise* frame_size_stepwise(struct v4l2_frmsizeenum* f) { return &f->stepwise; }
+static inline struct v4l2_fract* frame_interval_discrete(struct v4l2_frmivalenum* f) { return &f->discrete; }
+static inline struct v4l2_frmival_stepwise* frame_interval_stepwise(struct v4l2_frmivalenum* f) { return &f->stepwise; }
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/io"
+)
+
+type FrameSizeType uint32
+
+const (
+	FrameSizeTypeDiscrete   FrameSizeType = C.V4L2_FRMSIZE_TYPE_DISCRETE
+	FrameSizeTypeContinuous FrameSizeType = C.V4L2_FRMSIZE_TYPE_CONTINUOUS
+	FrameSizeTypeStepwise   FrameSizeType = C.V4L2_FRMSIZE_TYPE_STEPWISE
+)
+
+// FrameSize is a frame size supported by the device for a pixel format. Discrete size has equal minimal and maximal
+// dimensions, continuous size has steps equal to one.
+type FrameSize struct {
+	Type FrameSizeType `json:"type"`
+
+	MinWidth   uint32 `json:"minWidth"`
+	MaxWidth   uint32 `json:"maxWidth"`
+	StepWidth  uint32 `json:"stepWidth"`
+	MinHeight  uint32 `json:"minHeight"`
+	MaxHeight  uint32 `json:"maxHeight"`
+	StepHeight uint32 `json:"stepHeight"`
+}
+
+type FrameIntervalType uint32
+
+const (
+	FrameIntervalTypeDiscrete   FrameIntervalType = C.V4L2_FRMIVAL_TYPE_DISCRETE
+	FrameIntervalTypeContinuous FrameIntervalType = C.V4L2_FRMIVAL_TYPE_CONTINUOUS
+	FrameIntervalTypeStepwise   FrameIntervalType = C.V4L2_FRMIVAL_TYPE_STEPWISE
+)
+
+// Fraction is a time period in seconds, frame interval of 1/30 means 30 frames per second.
+type Fraction struct {
+	Numerator   uint32 `json:"numerator"`
+	Denominator uint32 `json:"denominator"`
+}
+
+// GetFrameRate returns number of frames per second of the frame interval, zero when the fraction is not valid.
+func (fraction Fraction) GetFrameRate() float64 {
+	if fraction.Numerator == 0 || fraction.Denominator == 0 {
+		return 0
+	}
+
+	return float64(fraction.Denominator) / float64(fraction.Numerator)
+}
+
+// FrameInterval is a frame interval supported by the device for a pixel format and frame size. Discrete interval
+// has equal minimal and maximal values.
+type FrameInterval struct {
+	Type FrameIntervalType `json:"type"`
+
+	Min  Fraction `json:"min"`
+	Max  Fraction `json:"max"`
+	Step Fraction `json:"step"`
+}
+
+// ListFrameSizes queries frame sizes supported by the device for the pixel format.
+// Uses VIDIOC_ENUM_FRAMESIZES ioctl.
+func ListFrameSizes(descriptor io.DeviceDescriptor, pixelFormat PixelFormatCode) ([]FrameSize, error) {
+	result := []FrameSize{}
+
+	for index := uint32(0); ; index++ {
+		var rawFrameSize C.struct_v4l2_frmsizeenum
+		rawFrameSize.index = C.__u32(index)
+		rawFrameSize.pixel_format = C.__u32(pixelFormat)
+
+		if err := io.SendCtl(descriptor, C.VIDIOC_ENUM_FRAMESIZES, uintptr(unsafe.Pointer(&rawFrameSize))); err != nil {
+			if errors.Is(err, io.ErrBadArgument) && len(result) > 0 {
+				break
+			}
+			return result, err
+		}
+
+		frameSizeType := FrameSizeType(rawFrameSize._type)
+
+		if frameSizeType == FrameSizeTypeDiscrete {
+			discrete := C.frame_size_discrete(&rawFrameSize)
+
+			result = append(result, FrameSize{
+				Type:       frameSizeType,
+				MinWidth:   uint32(discrete.width),
+				MaxWidth:   uint32(discrete.width),
+				StepWidth:  1,
+				MinHeight:  uint32(discrete.height),
+				MaxHeight:  uint32(discrete.height),
+				StepHeight: 1,
+			})
+
+			continue
+		}
+
+		// continuous and stepwise sizes are reported by the only entry
+		stepwise := C.frame_size_stepwise(&rawFrameSize)
+
+		result = append(result, FrameSize{
+			Type:       frameSizeType,
+			MinWidth:   uint32(stepwise.min_width),
+			MaxWidth:   uint32(stepwise.max_width),
+			StepWidth:  max(uint32(stepwise.step_width), 1),
+			MinHeight:  uint32(stepwise.min_height),
+			MaxHeight:  uint32(stepwise.max_height),
+			StepHeight: max(uint32(stepwise.step_height), 1),
+		})
+
+		break
+	}
+
+	return result, nil
+}
+
+// ListFrameIntervals queries frame intervals supported by the device for the pixel format and frame size.
+// Uses VIDIOC_ENUM_FRAMEINTERVALS ioctl.
+func ListFrameIntervals(descriptor io.DeviceDescriptor, pixelFormat PixelFormatCode, width uint32, height uint32) ([]FrameInterval, error) {
+	result := []FrameInterval{}
+
+	for index := uint32(0); ; index++ {
+		var rawFrameInterval C.struct_v4l2_frmivalenum
+		rawFrameInterval.index = C.__u32(index)
+		rawFrameInterval.pixel_format = C.__u32(pixelFormat)
+		rawFrameInterval.width = C.__u32(width)
+		rawFrameInterval.height = C.__u32(height)
+
+		if err := io.SendCtl(descriptor, C.VIDIOC_ENUM_FRAMEINTERVALS, uintptr(unsafe.Pointer(&rawFrameInterval))); err != nil {
+			if errors.Is(err, io.ErrBadArgument) && len(result) > 0 {
+				break
+			}
+			return result, err
+		}
+
+		frameIntervalType := FrameIntervalType(rawFrameInterval._type)
+
+		if frameIntervalType == FrameIntervalTypeDiscrete {
+			discrete := C.frame_interval_discrete(&rawFrameInterval)
+			interval := Fraction{Numerator: uint32(discrete.numerator), Denominator: uint32(discrete.denominator)}
+
+			result = append(result, FrameInterval{
+				Type: frameIntervalType,
+				Min:  interval,
+				Max:  interval,
+				Step: interval,
+			})
+
+			continue
+		}
+
+		// continuous and stepwise intervals are reported by the only entry
+		stepwise := C.frame_interval_stepwise(&rawFrameInterval)
+
+		result = append(result, FrameInterval{
+			Type: frameIntervalType,
+			Min:  Fraction{Numerator: uint32(stepwise.min.numerator), Denominator: uint32(stepwise.min.denominator)},
+			Max:  Fraction{Numerator: uint32(stepwise.max.numerator), Denominator: uint32(stepwise.max.denominator)},
+			Step: Fraction{Numerator: uint32(stepwise.step.numerator), Denominator: uint32(stepwise.step.denominator)},
+		})
+
+		break
+	}
+
+	return result, nil
+}
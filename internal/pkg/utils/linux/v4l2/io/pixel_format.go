@@ -20,6 +20,12 @@ type PixelFormatIndex uint32
 
 type PixelFormatCode uint32
 
+const (
+	PixelFormatCodeRGB24 PixelFormatCode = C.V4L2_PIX_FMT_RGB24
+	PixelFormatCodeYUYV  PixelFormatCode = C.V4L2_PIX_FMT_YUYV
+	PixelFormatCodeMJPEG PixelFormatCode = C.V4L2_PIX_FMT_MJPEG
+)
+
 func (code PixelFormatCode) String() string {
 	return string(binary.LittleEndian.AppendUint32(nil, uint32(code)))
 }
@@ -0,0 +1,948 @@
+//go:build linux
+
+package io
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/io"
+	"golang.org/x/sys/unix"
+)
+
+// simulatedMaxBuffers is the most buffers the simulated driver allocates, like videobuf2 does.
+const simulatedMaxBuffers = 32
+
+// simulatedPollInterval is how often readiness is reported again while the device stays ready.
+const simulatedPollInterval = time.Millisecond * 100
+
+// SimulatedOperation names a device operation errors can be injected into.
+type SimulatedOperation string
+
+const (
+	SimulatedOperationOpen                       SimulatedOperation = "open"
+	SimulatedOperationQueryCapabilities          SimulatedOperation = "queryCapabilities"
+	SimulatedOperationGetEdid                    SimulatedOperation = "getEdid"
+	SimulatedOperationSetEdid                    SimulatedOperation = "setEdid"
+	SimulatedOperationSubscribeEvent             SimulatedOperation = "subscribeEvent"
+	SimulatedOperationDequeueEvent               SimulatedOperation = "dequeueEvent"
+	SimulatedOperationQueryDigitalVideoBTTimings SimulatedOperation = "queryDigitalVideoBTTimings"
+	SimulatedOperationSetDigitalVideoBTTimings   SimulatedOperation = "setDigitalVideoBTTimings"
+	SimulatedOperationListPixelFormats           SimulatedOperation = "listPixelFormats"
+	SimulatedOperationTryVideoFormat             SimulatedOperation = "tryVideoFormat"
+	SimulatedOperationSetVideoFormat             SimulatedOperation = "setVideoFormat"
+	SimulatedOperationGetVideoFormat             SimulatedOperation = "getVideoFormat"
+	SimulatedOperationListFrameSizes             SimulatedOperation = "listFrameSizes"
+	SimulatedOperationListFrameIntervals         SimulatedOperation = "listFrameIntervals"
+	SimulatedOperationSetFrameInterval           SimulatedOperation = "setFrameInterval"
+	SimulatedOperationRequestBuffers             SimulatedOperation = "requestBuffers"
+	SimulatedOperationReleaseBuffers             SimulatedOperation = "releaseBuffers"
+	SimulatedOperationQueryBuffer                SimulatedOperation = "queryBuffer"
+	SimulatedOperationBindBuffer                 SimulatedOperation = "bindBuffer"
+	SimulatedOperationQueueBuffer                SimulatedOperation = "queueBuffer"
+	SimulatedOperationDequeueBuffer              SimulatedOperation = "dequeueBuffer"
+	SimulatedOperationStartStream                SimulatedOperation = "startStream"
+	SimulatedOperationStopStream                 SimulatedOperation = "stopStream"
+)
+
+// SimulatedFrameGenerator writes pixels of the frame into data and returns number of bytes used.
+type SimulatedFrameGenerator func(data []byte, format VideoFormat, sequence uint32) uint32
+
+// GradientFrameGenerator fills every line with a gray level increasing by line and moving with every frame.
+func GradientFrameGenerator(data []byte, format VideoFormat, sequence uint32) uint32 {
+	for y := uint32(0); y < format.Height; y++ {
+		line := data[y*format.BytesPerLine : (y+1)*format.BytesPerLine]
+		for x := range line {
+			line[x] = byte(sequence + y)
+		}
+	}
+
+	return format.SizeImage
+}
+
+// NewSimulatedTimings returns timings of the display mode without blanking intervals.
+func NewSimulatedTimings(width uint32, height uint32, refreshRate uint32) DigitalVideoBTTimings {
+	return DigitalVideoBTTimings{
+		Width:        width,
+		Height:       height,
+		PixelClockHz: uint64(width) * uint64(height) * uint64(refreshRate),
+	}
+}
+
+// SimulatedTimingsStep presents the timings for the duration, nil timings mean no signal.
+type SimulatedTimingsStep struct {
+	Timings  *DigitalVideoBTTimings
+	Duration time.Duration
+}
+
+type SimulatedBackendOptions struct {
+	capability            Capability
+	timings               *DigitalVideoBTTimings
+	pixelFormats          []PixelFormatCode
+	bytesPerLineAlignment uint32
+	edid                  []byte
+	frameGenerator        SimulatedFrameGenerator
+}
+
+type SimulatedBackendOpt func(*SimulatedBackendOptions)
+
+// WithSimulatedTimings sets timings of the signal present when the backend is created.
+func WithSimulatedTimings(timings DigitalVideoBTTimings) SimulatedBackendOpt {
+	return func(options *SimulatedBackendOptions) {
+		options.timings = &timings
+	}
+}
+
+// WithSimulatedPixelFormats sets pixel formats the device captures, RGB24 and YUYV are sized correctly.
+func WithSimulatedPixelFormats(pixelFormats ...PixelFormatCode) SimulatedBackendOpt {
+	return func(options *SimulatedBackendOptions) {
+		options.pixelFormats = pixelFormats
+	}
+}
+
+// WithSimulatedBytesPerLineAlignment pads lines of captured frames to multiple of the alignment, like drivers
+// requiring aligned DMA transfers do.
+func WithSimulatedBytesPerLineAlignment(alignment uint32) SimulatedBackendOpt {
+	return func(options *SimulatedBackendOptions) {
+		options.bytesPerLineAlignment = alignment
+	}
+}
+
+func WithSimulatedCapability(capability Capability) SimulatedBackendOpt {
+	return func(options *SimulatedBackendOptions) {
+		options.capability = capability
+	}
+}
+
+func WithSimulatedEdid(edid []byte) SimulatedBackendOpt {
+	return func(options *SimulatedBackendOptions) {
+		options.edid = edid
+	}
+}
+
+func WithSimulatedFrameGenerator(generator SimulatedFrameGenerator) SimulatedBackendOpt {
+	return func(options *SimulatedBackendOptions) {
+		options.frameGenerator = generator
+	}
+}
+
+func defaultSimulatedBackendOptions() SimulatedBackendOptions {
+	return SimulatedBackendOptions{
+		capability: Capability{
+			Driver:  "simulated",
+			Card:    "Simulated HDMI bridge",
+			BusInfo: "platform:simulated",
+			Features: CapabilityFeatures{
+				VideoCapture: true,
+				Streaming:    true,
+			},
+		},
+		pixelFormats:          []PixelFormatCode{PixelFormatCodeRGB24},
+		bytesPerLineAlignment: 1,
+		edid:                  []byte{},
+		frameGenerator:        GradientFrameGenerator,
+	}
+}
+
+type simulatedBuffer struct {
+	data      []byte
+	queued    bool
+	bytesUsed uint32
+	sequence  uint32
+}
+
+// SimulatedBackend emulates a single HDMI bridge capture node in process. Every path opens the same node. Frames are
+// generated at the refresh rate of the signal while streaming and only when the signal matches timings set on the
+// device, signal changes raise source change events like the bridge does. Injected errors are returned by the next
+// calls of an operation, so drivers can be tested for recovery.
+type SimulatedBackend struct {
+	lock *sync.Mutex
+
+	capability            Capability
+	pixelFormats          []PixelFormatCode
+	bytesPerLineAlignment uint32
+	frameGenerator        SimulatedFrameGenerator
+
+	timings       *DigitalVideoBTTimings
+	activeTimings DigitalVideoBTTimings
+	format        VideoFormat
+	edid          []byte
+
+	devices map[*simulatedDevice]struct{}
+	errors  map[SimulatedOperation][]error
+
+	bufferOwner   *simulatedDevice
+	buffers       []*simulatedBuffer
+	queuedBuffers []BufferIndex
+	filledBuffers []BufferIndex
+	sequence      uint32
+	streamCancel  context.CancelFunc
+}
+
+var _ Backend = (*SimulatedBackend)(nil)
+
+func NewSimulatedBackend(opts ...SimulatedBackendOpt) *SimulatedBackend {
+	options := defaultSimulatedBackendOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	backend := &SimulatedBackend{
+		lock: &sync.Mutex{},
+
+		capability:            options.capability,
+		pixelFormats:          options.pixelFormats,
+		bytesPerLineAlignment: max(options.bytesPerLineAlignment, 1),
+		frameGenerator:        options.frameGenerator,
+
+		timings: options.timings,
+		edid:    bytes.Clone(options.edid),
+
+		devices: map[*simulatedDevice]struct{}{},
+		errors:  map[SimulatedOperation][]error{},
+	}
+
+	if backend.timings != nil {
+		backend.activeTimings = *backend.timings
+	}
+
+	backend.format = backend.adjustFormat(VideoFormat{Width: 640, Height: 480})
+
+	return backend
+}
+
+func (backend *SimulatedBackend) Open(devicePath string) (Device, error) {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	if err := backend.takeError(SimulatedOperationOpen); err != nil {
+		return nil, err
+	}
+
+	device := &simulatedDevice{
+		backend:       backend,
+		subscriptions: map[EventType]struct{}{},
+		notify:        make(chan struct{}, 1),
+	}
+
+	backend.devices[device] = struct{}{}
+
+	return device, nil
+}
+
+// SetTimings changes the signal presented to the device, nil timings remove the signal. Subscribed devices receive
+// source change event.
+func (backend *SimulatedBackend) SetTimings(timings *DigitalVideoBTTimings) {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	if timings != nil {
+		presentedTimings := *timings
+		timings = &presentedTimings
+	}
+
+	backend.timings = timings
+
+	for device := range backend.devices {
+		if _, ok := device.subscriptions[EventTypeSourceChange]; ok {
+			device.events = append(device.events, Event{Type: EventTypeSourceChange})
+			device.wake()
+		}
+	}
+}
+
+// PlayTimingsScript presents timings of the steps one after another in background, the last step stays presented.
+func (backend *SimulatedBackend) PlayTimingsScript(ctx context.Context, steps ...SimulatedTimingsStep) {
+	go func() {
+		for _, step := range steps {
+			backend.SetTimings(step.Timings)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(step.Duration):
+			}
+		}
+	}()
+}
+
+// InjectError makes the next count calls of the operation fail with the error SendCtl reports for errno, for example
+// ErrTimeout for EAGAIN or ErrNoEntity for ENOENT.
+func (backend *SimulatedBackend) InjectError(operation SimulatedOperation, errno unix.Errno, count int) {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	for range count {
+		backend.errors[operation] = append(backend.errors[operation], io.ParseErrno(errno))
+	}
+}
+
+// GetEdid returns EDID programmed on the device.
+func (backend *SimulatedBackend) GetEdid() []byte {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	return bytes.Clone(backend.edid)
+}
+
+func (backend *SimulatedBackend) takeError(operation SimulatedOperation) error {
+	errs := backend.errors[operation]
+	if len(errs) == 0 {
+		return nil
+	}
+
+	backend.errors[operation] = errs[1:]
+
+	return errs[0]
+}
+
+// adjustFormat returns the format the bridge captures, its size follows timings set on the device.
+func (backend *SimulatedBackend) adjustFormat(format VideoFormat) VideoFormat {
+	if backend.activeTimings.Width > 0 && backend.activeTimings.Height > 0 {
+		format.Width = backend.activeTimings.Width
+		format.Height = backend.activeTimings.Height
+	}
+
+	if !slices.Contains(backend.pixelFormats, format.PixelFormat) {
+		format.PixelFormat = backend.pixelFormats[0]
+	}
+
+	bytesPerPixel := uint32(2)
+	if format.PixelFormat == PixelFormatCodeRGB24 {
+		bytesPerPixel = 3
+	}
+
+	format.Field = VideoFormatFieldNone
+	format.BytesPerLine = (format.Width*bytesPerPixel + backend.bytesPerLineAlignment - 1) / backend.bytesPerLineAlignment * backend.bytesPerLineAlignment
+	format.SizeImage = format.BytesPerLine * format.Height
+
+	return format
+}
+
+// frameInterval returns the only frame interval the bridge captures, it follows refresh rate of timings set on the
+// device.
+func (backend *SimulatedBackend) frameInterval() Fraction {
+	frameRate := math.Round(backend.activeTimings.GetFrameRate())
+	if frameRate <= 0 {
+		frameRate = 60
+	}
+
+	return Fraction{Numerator: 1, Denominator: uint32(frameRate)}
+}
+
+func (backend *SimulatedBackend) releaseBuffers() {
+	backend.bufferOwner = nil
+	backend.buffers = nil
+	backend.queuedBuffers = nil
+	backend.filledBuffers = nil
+}
+
+func (backend *SimulatedBackend) stopStream() {
+	if backend.streamCancel == nil {
+		return
+	}
+
+	backend.streamCancel()
+	backend.streamCancel = nil
+
+	for _, buffer := range backend.buffers {
+		buffer.queued = false
+	}
+
+	backend.queuedBuffers = nil
+	backend.filledBuffers = nil
+}
+
+func (backend *SimulatedBackend) streamLoop(ctx context.Context, frameRate float64) {
+	if frameRate <= 0 {
+		frameRate = 60
+	}
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / frameRate))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			backend.captureFrame(ctx)
+		}
+	}
+}
+
+// captureFrame fills the first queued buffer, frames are dropped when no buffer is queued and not captured when the
+// signal does not match timings set on the device.
+func (backend *SimulatedBackend) captureFrame(ctx context.Context) {
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	if ctx.Err() != nil || backend.timings == nil || *backend.timings != backend.activeTimings {
+		return
+	}
+
+	backend.sequence++
+
+	if len(backend.queuedBuffers) == 0 {
+		return
+	}
+
+	index := backend.queuedBuffers[0]
+	backend.queuedBuffers = backend.queuedBuffers[1:]
+
+	buffer := backend.buffers[index]
+	buffer.queued = false
+	buffer.sequence = backend.sequence
+	buffer.bytesUsed = backend.frameGenerator(buffer.data, backend.format, backend.sequence)
+
+	backend.filledBuffers = append(backend.filledBuffers, index)
+	backend.bufferOwner.wake()
+}
+
+type simulatedDevice struct {
+	backend *SimulatedBackend
+
+	subscriptions map[EventType]struct{}
+	events        []Event
+	notify        chan struct{}
+	closed        bool
+}
+
+var _ Device = (*simulatedDevice)(nil)
+
+func (device *simulatedDevice) wake() {
+	select {
+	case device.notify <- struct{}{}:
+	default:
+	}
+}
+
+// call locks the backend and returns injected error of the operation, or ErrSystem like the kernel does for closed
+// descriptors.
+func (device *simulatedDevice) call(operation SimulatedOperation) (func(), error) {
+	device.backend.lock.Lock()
+
+	if device.closed {
+		return device.backend.lock.Unlock, io.ParseErrno(unix.EBADF)
+	}
+
+	return device.backend.lock.Unlock, device.backend.takeError(operation)
+}
+
+func (device *simulatedDevice) Close() error {
+	backend := device.backend
+
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	if device.closed {
+		return unix.EBADF
+	}
+
+	device.closed = true
+	delete(backend.devices, device)
+
+	if backend.bufferOwner == device {
+		backend.stopStream()
+		backend.releaseBuffers()
+	}
+
+	return nil
+}
+
+func (device *simulatedDevice) QueryCapabilities() (Capability, error) {
+	unlock, err := device.call(SimulatedOperationQueryCapabilities)
+	defer unlock()
+	if err != nil {
+		return EmptyCapability, err
+	}
+
+	return device.backend.capability, nil
+}
+
+func (device *simulatedDevice) GetEdid(pad EdidPad) ([]byte, error) {
+	unlock, err := device.call(SimulatedOperationGetEdid)
+	defer unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if pad != 0 {
+		return nil, io.ErrBadArgument
+	}
+
+	return bytes.Clone(device.backend.edid), nil
+}
+
+func (device *simulatedDevice) SetEdid(pad EdidPad, edid []byte) error {
+	if len(edid)%EdidBlockLength != 0 {
+		return ErrEdidLengthInvalid
+	}
+
+	if len(edid)/EdidBlockLength > EdidMaxBlocks {
+		return ErrEdidTooManyBlocks
+	}
+
+	unlock, err := device.call(SimulatedOperationSetEdid)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	if pad != 0 {
+		return io.ErrBadArgument
+	}
+
+	device.backend.edid = bytes.Clone(edid)
+
+	return nil
+}
+
+func (device *simulatedDevice) SubscribeEvent(eventType EventType) error {
+	unlock, err := device.call(SimulatedOperationSubscribeEvent)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	device.subscriptions[eventType] = struct{}{}
+
+	return nil
+}
+
+func (device *simulatedDevice) DequeueEvent() (Event, error) {
+	unlock, err := device.call(SimulatedOperationDequeueEvent)
+	defer unlock()
+	if err != nil {
+		return EmptyEvent, err
+	}
+
+	if len(device.events) == 0 {
+		return EmptyEvent, io.ErrNoEntity
+	}
+
+	event := device.events[0]
+	device.events = device.events[1:]
+
+	return event, nil
+}
+
+func (device *simulatedDevice) QueryDigitalVideoBTTimings() (DigitalVideoBTTimings, error) {
+	unlock, err := device.call(SimulatedOperationQueryDigitalVideoBTTimings)
+	defer unlock()
+	if err != nil {
+		return EmptyDigitalVideoBTTimings, err
+	}
+
+	if device.backend.timings == nil {
+		return EmptyDigitalVideoBTTimings, io.ErrNoLink
+	}
+
+	return *device.backend.timings, nil
+}
+
+func (device *simulatedDevice) SetDigitalVideoBTTimings(timings DigitalVideoBTTimings) error {
+	unlock, err := device.call(SimulatedOperationSetDigitalVideoBTTimings)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	if len(device.backend.buffers) > 0 {
+		return io.ErrDeviceOrResourceBusy
+	}
+
+	device.backend.activeTimings = timings
+	device.backend.format = device.backend.adjustFormat(device.backend.format)
+
+	return nil
+}
+
+func (device *simulatedDevice) ListPixelFormats(bufferType BufferType) (PixelFormatList, error) {
+	unlock, err := device.call(SimulatedOperationListPixelFormats)
+	defer unlock()
+	if err != nil {
+		return EmptyPixelFormatList, err
+	}
+
+	if bufferType != BufferTypeVideoCapture {
+		return EmptyPixelFormatList, io.ErrBadArgument
+	}
+
+	result := PixelFormatList{}
+	for index, code := range device.backend.pixelFormats {
+		result[PixelFormatIndex(index)] = PixelFormat{
+			Code:        code,
+			Description: code.String(),
+		}
+	}
+
+	return result, nil
+}
+
+func (device *simulatedDevice) TryVideoFormat(bufferType BufferType, format VideoFormat) (VideoFormat, error) {
+	unlock, err := device.call(SimulatedOperationTryVideoFormat)
+	defer unlock()
+	if err != nil {
+		return EmptyVideoFormat, err
+	}
+
+	if bufferType != BufferTypeVideoCapture {
+		return EmptyVideoFormat, io.ErrBadArgument
+	}
+
+	return device.backend.adjustFormat(format), nil
+}
+
+func (device *simulatedDevice) SetVideoFormat(bufferType BufferType, format VideoFormat) error {
+	unlock, err := device.call(SimulatedOperationSetVideoFormat)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	if bufferType != BufferTypeVideoCapture {
+		return io.ErrBadArgument
+	}
+
+	if len(device.backend.buffers) > 0 {
+		return io.ErrDeviceOrResourceBusy
+	}
+
+	device.backend.format = device.backend.adjustFormat(format)
+
+	return nil
+}
+
+func (device *simulatedDevice) GetVideoFormat(bufferType BufferType) (VideoFormat, error) {
+	unlock, err := device.call(SimulatedOperationGetVideoFormat)
+	defer unlock()
+	if err != nil {
+		return EmptyVideoFormat, err
+	}
+
+	if bufferType != BufferTypeVideoCapture {
+		return EmptyVideoFormat, io.ErrBadArgument
+	}
+
+	return device.backend.format, nil
+}
+
+func (device *simulatedDevice) ListFrameSizes(pixelFormat PixelFormatCode) ([]FrameSize, error) {
+	unlock, err := device.call(SimulatedOperationListFrameSizes)
+	defer unlock()
+	if err != nil {
+		return []FrameSize{}, err
+	}
+
+	if !slices.Contains(device.backend.pixelFormats, pixelFormat) {
+		return []FrameSize{}, io.ErrBadArgument
+	}
+
+	format := device.backend.adjustFormat(VideoFormat{PixelFormat: pixelFormat})
+
+	return []FrameSize{{
+		Type:       FrameSizeTypeDiscrete,
+		MinWidth:   format.Width,
+		MaxWidth:   format.Width,
+		StepWidth:  1,
+		MinHeight:  format.Height,
+		MaxHeight:  format.Height,
+		StepHeight: 1,
+	}}, nil
+}
+
+func (device *simulatedDevice) ListFrameIntervals(pixelFormat PixelFormatCode, width uint32, height uint32) ([]FrameInterval, error) {
+	unlock, err := device.call(SimulatedOperationListFrameIntervals)
+	defer unlock()
+	if err != nil {
+		return []FrameInterval{}, err
+	}
+
+	if !slices.Contains(device.backend.pixelFormats, pixelFormat) {
+		return []FrameInterval{}, io.ErrBadArgument
+	}
+
+	format := device.backend.adjustFormat(VideoFormat{PixelFormat: pixelFormat})
+	if format.Width != width || format.Height != height {
+		return []FrameInterval{}, io.ErrBadArgument
+	}
+
+	interval := device.backend.frameInterval()
+
+	return []FrameInterval{{
+		Type: FrameIntervalTypeDiscrete,
+		Min:  interval,
+		Max:  interval,
+		Step: interval,
+	}}, nil
+}
+
+func (device *simulatedDevice) SetFrameInterval(bufferType BufferType, interval Fraction) (Fraction, error) {
+	unlock, err := device.call(SimulatedOperationSetFrameInterval)
+	defer unlock()
+	if err != nil {
+		return Fraction{}, err
+	}
+
+	if bufferType != BufferTypeVideoCapture {
+		return Fraction{}, io.ErrBadArgument
+	}
+
+	return device.backend.frameInterval(), nil
+}
+
+func (device *simulatedDevice) RequestBuffers(bufferType BufferType, memoryType MemoryType, count uint32) (uint32, error) {
+	if count == 0 {
+		return 0, io.ErrBadArgument
+	}
+
+	unlock, err := device.call(SimulatedOperationRequestBuffers)
+	defer unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	backend := device.backend
+
+	if bufferType != BufferTypeVideoCapture || memoryType != MemoryTypeMmap {
+		return 0, io.ErrBadArgument
+	}
+
+	if len(backend.buffers) > 0 {
+		return 0, io.ErrDeviceOrResourceBusy
+	}
+
+	count = min(count, simulatedMaxBuffers)
+
+	backend.bufferOwner = device
+	backend.buffers = make([]*simulatedBuffer, count)
+	for index := range backend.buffers {
+		backend.buffers[index] = &simulatedBuffer{
+			data: make([]byte, backend.format.SizeImage),
+		}
+	}
+
+	return count, nil
+}
+
+func (device *simulatedDevice) ReleaseBuffers(bufferType BufferType, memoryType MemoryType) error {
+	unlock, err := device.call(SimulatedOperationReleaseBuffers)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	backend := device.backend
+
+	if bufferType != BufferTypeVideoCapture || memoryType != MemoryTypeMmap {
+		return io.ErrBadArgument
+	}
+
+	if backend.bufferOwner != nil && backend.bufferOwner != device || backend.streamCancel != nil {
+		return io.ErrDeviceOrResourceBusy
+	}
+
+	backend.releaseBuffers()
+
+	return nil
+}
+
+func (device *simulatedDevice) QueryMmapBuffer(bufferType BufferType, index BufferIndex) (MmapBuffer, error) {
+	unlock, err := device.call(SimulatedOperationQueryBuffer)
+	defer unlock()
+	if err != nil {
+		return EmptyMmapBuffer, err
+	}
+
+	backend := device.backend
+
+	if bufferType != BufferTypeVideoCapture || int(index) >= len(backend.buffers) {
+		return EmptyMmapBuffer, io.ErrBadArgument
+	}
+
+	return MmapBuffer{
+		Buffer: Buffer{
+			BufferDescriptor: BufferDescriptor{
+				Type:   bufferType,
+				Memory: MemoryTypeMmap,
+				Index:  index,
+				Field:  VideoFormatFieldNone,
+			},
+			Length: uint32(len(backend.buffers[index].data)),
+		},
+		Offset: index * uint32(len(backend.buffers[index].data)),
+	}, nil
+}
+
+func (device *simulatedDevice) BindMmapBuffer(buffer MmapBuffer) (BoundMmapBuffer, error) {
+	unlock, err := device.call(SimulatedOperationBindBuffer)
+	defer unlock()
+	if err != nil {
+		return EmptyBoundMmapBuffer, err
+	}
+
+	if int(buffer.Index) >= len(device.backend.buffers) {
+		return EmptyBoundMmapBuffer, io.ErrBadArgument
+	}
+
+	return BoundMmapBuffer{
+		MmapBuffer: buffer,
+		Data:       device.backend.buffers[buffer.Index].data,
+	}, nil
+}
+
+func (device *simulatedDevice) UnbindMmapBuffer(buffer BoundMmapBuffer) error {
+	return nil
+}
+
+func (device *simulatedDevice) QueueBuffer(bufferDescriptor BufferDescriptor) error {
+	unlock, err := device.call(SimulatedOperationQueueBuffer)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	backend := device.backend
+
+	if backend.bufferOwner != device || int(bufferDescriptor.Index) >= len(backend.buffers) {
+		return io.ErrBadArgument
+	}
+
+	buffer := backend.buffers[bufferDescriptor.Index]
+	if buffer.queued || slices.Contains(backend.filledBuffers, bufferDescriptor.Index) {
+		return io.ErrBadArgument
+	}
+
+	buffer.queued = true
+	backend.queuedBuffers = append(backend.queuedBuffers, bufferDescriptor.Index)
+
+	return nil
+}
+
+func (device *simulatedDevice) DequeueMmapBuffer(bufferType BufferType) (BufferDescriptor, error) {
+	unlock, err := device.call(SimulatedOperationDequeueBuffer)
+	defer unlock()
+	if err != nil {
+		return EmptyBufferDescriptor, err
+	}
+
+	backend := device.backend
+
+	if bufferType != BufferTypeVideoCapture || backend.bufferOwner != device || backend.streamCancel == nil {
+		return EmptyBufferDescriptor, io.ErrBadArgument
+	}
+
+	if len(backend.filledBuffers) == 0 {
+		return EmptyBufferDescriptor, io.ParseErrno(unix.EAGAIN)
+	}
+
+	index := backend.filledBuffers[0]
+	backend.filledBuffers = backend.filledBuffers[1:]
+
+	buffer := backend.buffers[index]
+
+	return BufferDescriptor{
+		Type:      bufferType,
+		Memory:    MemoryTypeMmap,
+		Index:     index,
+		BytesUsed: buffer.bytesUsed,
+		Flags:     BufferFlagMapped | BufferFlagDone,
+		Sequence:  buffer.sequence,
+		Field:     VideoFormatFieldNone,
+	}, nil
+}
+
+func (device *simulatedDevice) StartStream(bufferType BufferType) error {
+	unlock, err := device.call(SimulatedOperationStartStream)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	backend := device.backend
+
+	if bufferType != BufferTypeVideoCapture || backend.bufferOwner != device {
+		return io.ErrBadArgument
+	}
+
+	if backend.streamCancel != nil {
+		return nil
+	}
+
+	streamCtx, streamCancel := context.WithCancel(context.Background())
+	backend.streamCancel = streamCancel
+
+	go backend.streamLoop(streamCtx, backend.activeTimings.GetFrameRate())
+
+	return nil
+}
+
+func (device *simulatedDevice) StopStream(bufferType BufferType) error {
+	unlock, err := device.call(SimulatedOperationStopStream)
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	if bufferType != BufferTypeVideoCapture || device.backend.bufferOwner != device {
+		return io.ErrBadArgument
+	}
+
+	device.backend.stopStream()
+
+	return nil
+}
+
+// Poll reports input while filled buffers wait for dequeue and priority while events are pending. Readiness is
+// reported when it changes and again every poll interval while it lasts, like level triggered poll does.
+func (device *simulatedDevice) Poll(ctx context.Context, events ...io.PollEvent) <-chan io.PollEvent {
+	output := make(chan io.PollEvent, 64)
+
+	go func() {
+		defer close(output)
+
+		for {
+			for _, event := range device.readyEvents(events) {
+				select {
+				case <-ctx.Done():
+					return
+				case output <- event:
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-device.notify:
+			case <-time.After(simulatedPollInterval):
+			}
+		}
+	}()
+
+	return output
+}
+
+func (device *simulatedDevice) readyEvents(events []io.PollEvent) []io.PollEvent {
+	backend := device.backend
+
+	backend.lock.Lock()
+	defer backend.lock.Unlock()
+
+	ready := []io.PollEvent{}
+
+	if slices.Contains(events, io.PollEventInput) && backend.bufferOwner == device && len(backend.filledBuffers) > 0 {
+		ready = append(ready, io.PollEventInput)
+	}
+
+	if slices.Contains(events, io.PollEventPriority) && len(device.events) > 0 {
+		ready = append(ready, io.PollEventPriority)
+	}
+
+	return ready
+}
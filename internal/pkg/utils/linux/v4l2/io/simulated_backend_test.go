@@ -0,0 +1,167 @@
+//go:build linux
+
+package io
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/io"
+)
+
+func openSimulatedDevice(t *testing.T, backend *SimulatedBackend) Device {
+	t.Helper()
+
+	device, err := backend.Open("/dev/video0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = device.Close()
+	})
+
+	return device
+}
+
+func TestSimulatedBackendReportsMissingSignal(t *testing.T) {
+	backend := NewSimulatedBackend()
+	device := openSimulatedDevice(t, backend)
+
+	_, err := device.QueryDigitalVideoBTTimings()
+	assert.ErrorIs(t, err, io.ErrNoLink)
+
+	require.NoError(t, device.SubscribeEvent(EventTypeSourceChange))
+
+	_, err = device.DequeueEvent()
+	assert.ErrorIs(t, err, io.ErrNoEntity)
+
+	timings := NewSimulatedTimings(16, 8, 60)
+	backend.SetTimings(&timings)
+
+	event, err := device.DequeueEvent()
+	require.NoError(t, err)
+	assert.Equal(t, Event{Type: EventTypeSourceChange}, event)
+
+	queriedTimings, err := device.QueryDigitalVideoBTTimings()
+	require.NoError(t, err)
+	assert.Equal(t, timings, queriedTimings)
+	assert.Equal(t, float64(60), queriedTimings.GetFrameRate())
+}
+
+func TestSimulatedBackendStreamsFrames(t *testing.T) {
+	timings := NewSimulatedTimings(16, 8, 100)
+
+	backend := NewSimulatedBackend(WithSimulatedTimings(timings))
+	device := openSimulatedDevice(t, backend)
+
+	require.NoError(t, device.SetDigitalVideoBTTimings(timings))
+
+	format, err := device.TryVideoFormat(BufferTypeVideoCapture, VideoFormat{Width: 1, Height: 1, PixelFormat: PixelFormatCodeRGB24})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(16), format.Width)
+	assert.Equal(t, uint32(16*8*3), format.SizeImage)
+
+	require.NoError(t, device.SetVideoFormat(BufferTypeVideoCapture, format))
+
+	count, err := device.RequestBuffers(BufferTypeVideoCapture, MemoryTypeMmap, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), count)
+
+	assert.ErrorIs(t, device.SetVideoFormat(BufferTypeVideoCapture, format), io.ErrDeviceOrResourceBusy)
+
+	buffers := BoundMmapBuffers{}
+	for index := range BufferIndex(count) {
+		buffer, err := device.QueryMmapBuffer(BufferTypeVideoCapture, index)
+		require.NoError(t, err)
+
+		buffers[index], err = device.BindMmapBuffer(buffer)
+		require.NoError(t, err)
+
+		require.NoError(t, device.QueueBuffer(buffer.BufferDescriptor))
+	}
+
+	require.NoError(t, device.StartStream(BufferTypeVideoCapture))
+
+	_, err = device.DequeueMmapBuffer(BufferTypeVideoCapture)
+	assert.ErrorIs(t, err, io.ErrTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	assert.Equal(t, io.PollEventInput, <-device.Poll(ctx, io.PollEventInput))
+
+	bufferDescriptor, err := device.DequeueMmapBuffer(BufferTypeVideoCapture)
+	require.NoError(t, err)
+	assert.Equal(t, format.SizeImage, bufferDescriptor.BytesUsed)
+	assert.Equal(t, byte(bufferDescriptor.Sequence+1), buffers[bufferDescriptor.Index].Data[format.BytesPerLine])
+
+	require.NoError(t, device.StopStream(BufferTypeVideoCapture))
+	require.NoError(t, device.ReleaseBuffers(BufferTypeVideoCapture, MemoryTypeMmap))
+}
+
+func TestSimulatedBackendListsFrameSizes(t *testing.T) {
+	backend := NewSimulatedBackend(
+		WithSimulatedTimings(NewSimulatedTimings(5, 4, 30)),
+		WithSimulatedBytesPerLineAlignment(16),
+	)
+	device := openSimulatedDevice(t, backend)
+
+	frameSizes, err := device.ListFrameSizes(PixelFormatCodeRGB24)
+	require.NoError(t, err)
+	assert.Equal(t, []FrameSize{{
+		Type:       FrameSizeTypeDiscrete,
+		MinWidth:   5,
+		MaxWidth:   5,
+		StepWidth:  1,
+		MinHeight:  4,
+		MaxHeight:  4,
+		StepHeight: 1,
+	}}, frameSizes)
+
+	_, err = device.ListFrameSizes(PixelFormatCodeMJPEG)
+	assert.ErrorIs(t, err, io.ErrBadArgument)
+
+	interval := Fraction{Numerator: 1, Denominator: 30}
+
+	frameIntervals, err := device.ListFrameIntervals(PixelFormatCodeRGB24, 5, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []FrameInterval{{Type: FrameIntervalTypeDiscrete, Min: interval, Max: interval, Step: interval}}, frameIntervals)
+
+	_, err = device.ListFrameIntervals(PixelFormatCodeRGB24, 640, 480)
+	assert.ErrorIs(t, err, io.ErrBadArgument)
+
+	selectedInterval, err := device.SetFrameInterval(BufferTypeVideoCapture, Fraction{Numerator: 1, Denominator: 60})
+	require.NoError(t, err)
+	assert.Equal(t, interval, selectedInterval)
+
+	// lines are padded to the alignment
+	format, err := device.GetVideoFormat(BufferTypeVideoCapture)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(16), format.BytesPerLine)
+	assert.Equal(t, uint32(16*4), format.SizeImage)
+}
+
+func TestSimulatedBackendInjectsErrors(t *testing.T) {
+	backend := NewSimulatedBackend()
+
+	backend.InjectError(SimulatedOperationOpen, unix.ENODEV, 1)
+
+	_, err := backend.Open("/dev/video0")
+	assert.ErrorIs(t, err, io.ErrSystem)
+
+	device := openSimulatedDevice(t, backend)
+
+	backend.InjectError(SimulatedOperationDequeueEvent, unix.EAGAIN, 2)
+
+	for range 2 {
+		_, err = device.DequeueEvent()
+		assert.ErrorIs(t, err, io.ErrTimeout)
+	}
+
+	_, err = device.DequeueEvent()
+	assert.ErrorIs(t, err, io.ErrNoEntity)
+}
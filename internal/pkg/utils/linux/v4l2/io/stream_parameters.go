@@ -0,0 +1,38 @@
+//go:build linux
+
+package io
+
+/*
+#cgo linux CFLAGS: -I ${SRCDIR}/../include/
+#include <linux/videodev2.h>
+
+static inline struct v4l2_captureparm* stream_parameters_capture(struct v4l2_streamparm* p) { return &p->parm.capture; }
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/io"
+)
+
+// SetFrameInterval requests the frame interval of capture and returns the one selected by the driver, which may
+// differ from the requested one.
+// Uses VIDIOC_S_PARM ioctl.
+func SetFrameInterval(descriptor io.DeviceDescriptor, bufferType BufferType, interval Fraction) (Fraction, error) {
+	var rawStreamParameters C.struct_v4l2_streamparm
+	rawStreamParameters._type = C.__u32(bufferType)
+
+	rawCaptureParameters := C.stream_parameters_capture(&rawStreamParameters)
+	rawCaptureParameters.timeperframe.numerator = C.__u32(interval.Numerator)
+	rawCaptureParameters.timeperframe.denominator = C.__u32(interval.Denominator)
+
+	if err := io.SendCtl(descriptor, C.VIDIOC_S_PARM, uintptr(unsafe.Pointer(&rawStreamParameters))); err != nil {
+		return Fraction{}, err
+	}
+
+	return Fraction{
+		Numerator:   uint32(rawCaptureParameters.timeperframe.numerator),
+		Denominator: uint32(rawCaptureParameters.timeperframe.denominator),
+	}, nil
+}
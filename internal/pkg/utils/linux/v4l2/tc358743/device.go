@@ -3,6 +3,7 @@
 package tc358743
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log/slog"
@@ -14,9 +15,11 @@ import (
 	v4l2io "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/v4l2/io"
 	memorySDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/memory"
 	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
-	"golang.org/x/sys/unix"
 )
 
+// edidPad is the only input pad of the bridge.
+const edidPad v4l2io.EdidPad = 0
+
 type FrameHandler func(memoryBuffer memorySDK.Buffer) error
 
 func DiscardFrameHandler(memoryBuffer memorySDK.Buffer) error {
@@ -27,6 +30,8 @@ type DeviceOptions struct {
 	memoryPoolProvider memorySDK.PoolProvider
 	frameHandler       FrameHandler
 	pixelFormat        peripheralSDK.DisplayPixelFormat
+	edid               []byte
+	backend            v4l2io.Backend
 	logger             *slog.Logger
 }
 
@@ -44,6 +49,21 @@ func WithFrameHandler(handler FrameHandler) DeviceOpt {
 	}
 }
 
+// WithEdid sets raw EDID blocks programmed on the device when it is opened. The connected machine offers display
+// modes declared by the EDID, so it decides which timings the device receives.
+func WithEdid(edid []byte) DeviceOpt {
+	return func(options *DeviceOptions) {
+		options.edid = edid
+	}
+}
+
+// WithBackend sets backend the device node is opened with, SimulatedBackend runs the device without the bridge.
+func WithBackend(backend v4l2io.Backend) DeviceOpt {
+	return func(options *DeviceOptions) {
+		options.backend = backend
+	}
+}
+
 func WithLogger(logger *slog.Logger) DeviceOpt {
 	return func(options *DeviceOptions) {
 		options.logger = logger
@@ -55,15 +75,21 @@ func defaultDeviceOptions() DeviceOptions {
 		memoryPoolProvider: memory.DefaultMemoryPoolProvider,
 		frameHandler:       DiscardFrameHandler,
 		pixelFormat:        peripheralSDK.DisplayPixelFormatRGB24,
+		backend:            v4l2io.DefaultBackend,
 		logger:             slog.New(slog.DiscardHandler),
 	}
 }
 
 type Device struct {
 	devicePath string
+	backend    v4l2io.Backend
 
 	pixelFormat peripheralSDK.DisplayPixelFormat
 
+	edid       []byte
+	activeEdid []byte
+	edidLock   *sync.RWMutex
+
 	currentDisplayMode     *peripheralSDK.DisplayMode
 	currentDisplayModeLock *sync.RWMutex
 
@@ -98,8 +124,12 @@ func Open(ctx context.Context, devicePath string, opts ...DeviceOpt) (*Device, e
 
 	device := &Device{
 		devicePath:  devicePath,
+		backend:     options.backend,
 		pixelFormat: options.pixelFormat,
 
+		edid:     options.edid,
+		edidLock: &sync.RWMutex{},
+
 		currentDisplayMode:     nil,
 		currentDisplayModeLock: &sync.RWMutex{},
 
@@ -114,13 +144,13 @@ func Open(ctx context.Context, devicePath string, opts ...DeviceOpt) (*Device, e
 		logger:     logger,
 	}
 
-	descriptor, err := device.openDevice(ctx)
+	videoDevice, err := device.openDevice(ctx)
 	if err != nil {
 		lifecycleCancel()
 		return nil, fmt.Errorf("open device: %w", err)
 	}
 
-	err = device.closeDevice(ctx, descriptor)
+	err = device.closeDevice(ctx, videoDevice)
 	if err != nil {
 		lifecycleCancel()
 		return nil, fmt.Errorf("close device: %w", err)
@@ -144,23 +174,68 @@ func (device *Device) GetPixelFormat() (*peripheralSDK.DisplayPixelFormat, error
 	return &device.pixelFormat, nil
 }
 
+// GetEdid returns raw EDID blocks read from the device when it was opened last time.
+func (device *Device) GetEdid() ([]byte, error) {
+	device.edidLock.RLock()
+	defer device.edidLock.RUnlock()
+
+	if len(device.activeEdid) == 0 {
+		return nil, ErrEdidNotAvailable
+	}
+
+	return bytes.Clone(device.activeEdid), nil
+}
+
+// SetEdid replaces EDID programmed on the device. It is programmed immediately when the device presents a different
+// one, the connected machine then sees hot plug and picks display mode again. The EDID is kept when device is reopened.
+func (device *Device) SetEdid(edid []byte) error {
+	videoDevice, err := device.backend.Open(device.devicePath)
+	if err != nil {
+		return fmt.Errorf("open device: %w", err)
+	}
+
+	defer func() {
+		_ = videoDevice.Close()
+	}()
+
+	device.edidLock.Lock()
+	previousEdid := device.edid
+	device.edid = bytes.Clone(edid)
+	device.edidLock.Unlock()
+
+	err = device.setupEdid(videoDevice)
+	if err != nil {
+		device.edidLock.Lock()
+		device.edid = previousEdid
+		device.edidLock.Unlock()
+
+		return fmt.Errorf("setup edid: %w", err)
+	}
+
+	return nil
+}
+
 func (device *Device) controlLoop(ctx context.Context) {
 	wg := &sync.WaitGroup{}
 
 	for {
-		descriptor, err := device.openDevice(ctx)
+		if ctx.Err() != nil {
+			device.logger.Debug("Control loop terminated.")
+			return
+		}
+
+		videoDevice, err := device.openDevice(ctx)
 		if err != nil {
 			device.logger.Warn("Open device error. Retrying initialization.", slog.String("error", err.Error()))
-			device.closeDevice(ctx, descriptor)
 			continue
 		}
 
 		device.logger.Info("Waiting for signal.")
 
-		timings, err := device.setupTimings(ctx, descriptor)
+		timings, err := device.setupTimings(ctx, videoDevice)
 		if err != nil {
 			device.logger.Warn("Wait for signal error. Retrying initialization.", slog.String("error", err.Error()))
-			device.closeDevice(ctx, descriptor)
+			device.closeDevice(ctx, videoDevice)
 			continue
 		}
 
@@ -170,10 +245,10 @@ func (device *Device) controlLoop(ctx context.Context) {
 			slog.Float64("inputFrameRate", timings.GetFrameRate()),
 		)
 
-		videoFormat, err := device.setupFormat(ctx, descriptor, int(timings.Width), int(timings.Height), timings.GetFrameRate())
+		videoFormat, err := device.setupFormat(ctx, videoDevice, int(timings.Width), int(timings.Height), timings.GetFrameRate())
 		if err != nil {
 			device.logger.Warn("Setup video format error. Retrying initialization.", slog.String("error", err.Error()))
-			device.closeDevice(ctx, descriptor)
+			device.closeDevice(ctx, videoDevice)
 			continue
 		}
 
@@ -184,19 +259,19 @@ func (device *Device) controlLoop(ctx context.Context) {
 			slog.Int("inputImageSize", int(videoFormat.SizeImage)),
 		)
 
-		buffers, err := device.initMemory(ctx, descriptor)
+		buffers, err := device.initMemory(ctx, videoDevice)
 		if err != nil {
 			device.logger.Warn("Init memory error. Retrying initialization.", slog.String("error", err.Error()))
-			device.closeDevice(ctx, descriptor)
+			device.closeDevice(ctx, videoDevice)
 			continue
 		}
 
 		wg.Add(1)
 
-		err = device.stream(wg, descriptor, buffers)
+		err = device.stream(wg, videoDevice, buffers)
 		if err != nil {
 			device.logger.Warn("Stream error. Retrying initialization.", slog.String("error", err.Error()))
-			device.closeDevice(ctx, descriptor)
+			device.closeDevice(ctx, videoDevice)
 			continue
 		}
 
@@ -204,13 +279,14 @@ func (device *Device) controlLoop(ctx context.Context) {
 
 		wg.Wait()
 
-		err = device.releaseMemory(ctx, descriptor, buffers)
+		err = device.releaseMemory(ctx, videoDevice, buffers)
 		if err != nil {
 			device.logger.Warn("Release memory error. Retrying initialization.", slog.String("error", err.Error()))
+			device.closeDevice(ctx, videoDevice)
 			continue
 		}
 
-		err = device.closeDevice(ctx, descriptor)
+		err = device.closeDevice(ctx, videoDevice)
 		if err != nil {
 			device.logger.Warn("Close device error. Retrying initialization.", slog.String("error", err.Error()))
 			continue
@@ -223,14 +299,14 @@ func (device *Device) controlLoop(ctx context.Context) {
 	}
 }
 
-func (device *Device) handleEvent(ctx context.Context, descriptor io.DeviceDescriptor) {
+func (device *Device) handleEvent(ctx context.Context, videoDevice v4l2io.Device) {
 	device.logger.Debug("Handling event.")
 
 	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*500)
 	defer cancel()
 
 	event, err := io.RetryOnErrorWithValue(ctx, func() (v4l2io.Event, error) {
-		return v4l2io.DequeueEvent(descriptor)
+		return videoDevice.DequeueEvent()
 	}, io.ErrTemporary, io.ErrNoEntity)
 	if err != nil {
 		device.logger.Warn("Dequeue event error.", slog.String("error", err.Error()))
@@ -248,7 +324,7 @@ func (device *Device) handleEvent(ctx context.Context, descriptor io.DeviceDescr
 	}
 }
 
-func (device *Device) stream(wg *sync.WaitGroup, descriptor io.DeviceDescriptor, buffers v4l2io.BoundMmapBuffers) error {
+func (device *Device) stream(wg *sync.WaitGroup, videoDevice v4l2io.Device, buffers v4l2io.BoundMmapBuffers) error {
 	device.streamLock.Lock()
 	defer device.streamLock.Unlock()
 
@@ -258,7 +334,7 @@ func (device *Device) stream(wg *sync.WaitGroup, descriptor io.DeviceDescriptor,
 
 	streamCtx, streamCancel := context.WithCancel(device.lifecycleCtx)
 
-	go device.streamLoop(streamCtx, wg, descriptor, buffers)
+	go device.streamLoop(streamCtx, wg, videoDevice, buffers)
 
 	device.streamCtx = streamCtx
 	device.streamCancel = streamCancel
@@ -279,17 +355,17 @@ func (device *Device) stopStream() error {
 	return nil
 }
 
-func (device *Device) streamLoop(ctx context.Context, wg *sync.WaitGroup, descriptor io.DeviceDescriptor, buffers v4l2io.BoundMmapBuffers) {
+func (device *Device) streamLoop(ctx context.Context, wg *sync.WaitGroup, videoDevice v4l2io.Device, buffers v4l2io.BoundMmapBuffers) {
 	defer wg.Done()
 	done := ctx.Done()
 
-	err := v4l2io.StartStream(descriptor, v4l2io.BufferTypeVideoCapture)
+	err := videoDevice.StartStream(v4l2io.BufferTypeVideoCapture)
 	if err != nil {
 		device.logger.Warn("Start stream error.", slog.String("error", err.Error()))
 		return
 	}
 
-	pollEvents := io.Poll(ctx, descriptor, io.PollEventInput, io.PollEventPriority)
+	pollEvents := videoDevice.Poll(ctx, io.PollEventInput, io.PollEventPriority)
 
 	device.logger.Debug("Watching for frames.")
 
@@ -301,9 +377,9 @@ LOOP:
 		case pollEvent := <-pollEvents:
 			switch pollEvent {
 			case io.PollEventInput:
-				device.handleFrame(ctx, descriptor, buffers)
+				device.handleFrame(ctx, videoDevice, buffers)
 			case io.PollEventPriority:
-				device.handleEvent(ctx, descriptor)
+				device.handleEvent(ctx, videoDevice)
 			default:
 				device.logger.Warn("Unknown poll event.")
 			}
@@ -318,19 +394,19 @@ LOOP:
 	device.streamCtx = nil
 	device.streamCancel = nil
 
-	err = v4l2io.StopStream(descriptor, v4l2io.BufferTypeVideoCapture)
+	err = videoDevice.StopStream(v4l2io.BufferTypeVideoCapture)
 	if err != nil {
 		device.logger.Warn("Stop stream error.", slog.String("error", err.Error()))
 		return
 	}
 }
 
-func (device *Device) handleFrame(ctx context.Context, descriptor io.DeviceDescriptor, buffers v4l2io.BoundMmapBuffers) {
+func (device *Device) handleFrame(ctx context.Context, videoDevice v4l2io.Device, buffers v4l2io.BoundMmapBuffers) {
 	dequeueCtx, dequeueCancel := context.WithTimeout(ctx, time.Millisecond*100)
 	defer dequeueCancel()
 
 	bufferDescriptor, err := io.RetryOnErrorWithValue(dequeueCtx, func() (v4l2io.BufferDescriptor, error) {
-		return v4l2io.DequeueMmapBuffer(descriptor, v4l2io.BufferTypeVideoCapture)
+		return videoDevice.DequeueMmapBuffer(v4l2io.BufferTypeVideoCapture)
 	}, io.ErrTemporary, io.ErrTimeout)
 	if err != nil {
 		device.logger.Warn("Dequeue buffer error.", slog.String("error", err.Error()))
@@ -338,7 +414,7 @@ func (device *Device) handleFrame(ctx context.Context, descriptor io.DeviceDescr
 	}
 
 	defer func() {
-		err = v4l2io.QueueBuffer(descriptor, bufferDescriptor)
+		err = videoDevice.QueueBuffer(bufferDescriptor)
 		if err != nil {
 			device.logger.Warn("Queue buffer error.", slog.String("error", err.Error()))
 		}
@@ -374,19 +450,19 @@ func (device *Device) handleFrame(ctx context.Context, descriptor io.DeviceDescr
 	}
 }
 
-func (device *Device) setupTimings(ctx context.Context, descriptor io.DeviceDescriptor) (v4l2io.DigitalVideoBTTimings, error) {
+func (device *Device) setupTimings(ctx context.Context, videoDevice v4l2io.Device) (v4l2io.DigitalVideoBTTimings, error) {
 	timingsSetupCtx, timingsSetupCancel := context.WithTimeout(ctx, time.Second*10)
 	defer timingsSetupCancel()
 
 	timings, err := io.RetryOnErrorWithValue(timingsSetupCtx, func() (v4l2io.DigitalVideoBTTimings, error) {
-		return v4l2io.QueryDigitalVideoBTTimings(descriptor)
+		return videoDevice.QueryDigitalVideoBTTimings()
 	}, io.ErrDeviceOrResourceBusy, io.ErrNoLink)
 	if err != nil {
 		return v4l2io.EmptyDigitalVideoBTTimings, fmt.Errorf("query digital video timings: %w", err)
 	}
 
 	err = io.RetryOnError(timingsSetupCtx, func() error {
-		return v4l2io.SetDigitalVideoBTTimings(descriptor, timings)
+		return videoDevice.SetDigitalVideoBTTimings(timings)
 	}, io.ErrDeviceOrResourceBusy, io.ErrNoLink)
 	if err != nil {
 		return v4l2io.EmptyDigitalVideoBTTimings, fmt.Errorf("set digital video timings: %w", err)
@@ -395,11 +471,11 @@ func (device *Device) setupTimings(ctx context.Context, descriptor io.DeviceDesc
 	return timings, nil
 }
 
-func (device *Device) setupFormat(ctx context.Context, descriptor io.DeviceDescriptor, width int, height int, refreshRate float64) (v4l2io.VideoFormat, error) {
+func (device *Device) setupFormat(ctx context.Context, videoDevice v4l2io.Device, width int, height int, refreshRate float64) (v4l2io.VideoFormat, error) {
 	formatSetupCtx, formatSetupCancel := context.WithTimeout(ctx, time.Second*10)
 	defer formatSetupCancel()
 
-	pixelFormat, err := device.negotiatePixelFormat(descriptor, device.pixelFormat)
+	pixelFormat, err := device.negotiatePixelFormat(videoDevice, device.pixelFormat)
 	if err != nil {
 		return v4l2io.EmptyVideoFormat, fmt.Errorf("negotiate pixel format: %s: %w", device.pixelFormat.String(), err)
 	}
@@ -413,19 +489,19 @@ func (device *Device) setupFormat(ctx context.Context, descriptor io.DeviceDescr
 		Quantization: v4l2io.VideoFormatQuantizationFullRange,
 	}
 
-	videoFormat, err = v4l2io.TryVideoFormat(descriptor, v4l2io.BufferTypeVideoCapture, videoFormat)
+	videoFormat, err = videoDevice.TryVideoFormat(v4l2io.BufferTypeVideoCapture, videoFormat)
 	if err != nil {
 		return v4l2io.EmptyVideoFormat, fmt.Errorf("try video format: %w", err)
 	}
 
 	err = io.RetryOnError(formatSetupCtx, func() error {
-		return v4l2io.SetVideoFormat(descriptor, v4l2io.BufferTypeVideoCapture, videoFormat)
+		return videoDevice.SetVideoFormat(v4l2io.BufferTypeVideoCapture, videoFormat)
 	}, io.ErrDeviceOrResourceBusy)
 	if err != nil {
 		return v4l2io.EmptyVideoFormat, fmt.Errorf("set video format: %w", err)
 	}
 
-	videoFormat, err = v4l2io.GetVideoFormat(descriptor, v4l2io.BufferTypeVideoCapture)
+	videoFormat, err = videoDevice.GetVideoFormat(v4l2io.BufferTypeVideoCapture)
 	if err != nil {
 		return v4l2io.EmptyVideoFormat, fmt.Errorf("get video format: %w", err)
 	}
@@ -442,8 +518,8 @@ func (device *Device) setupFormat(ctx context.Context, descriptor io.DeviceDescr
 	return videoFormat, nil
 }
 
-func (device *Device) negotiatePixelFormat(descriptor io.DeviceDescriptor, pixelFormat peripheralSDK.DisplayPixelFormat) (v4l2io.PixelFormat, error) {
-	devicePixelFormats, err := v4l2io.ListPixelFormats(descriptor, v4l2io.BufferTypeVideoCapture)
+func (device *Device) negotiatePixelFormat(videoDevice v4l2io.Device, pixelFormat peripheralSDK.DisplayPixelFormat) (v4l2io.PixelFormat, error) {
+	devicePixelFormats, err := videoDevice.ListPixelFormats(v4l2io.BufferTypeVideoCapture)
 	if err != nil {
 		return v4l2io.EmptyPixelFormat, fmt.Errorf("list pixel formats: %w", err)
 	}
@@ -457,8 +533,8 @@ func (device *Device) negotiatePixelFormat(descriptor io.DeviceDescriptor, pixel
 	return v4l2io.EmptyPixelFormat, peripheralSDK.ErrUnsupportedPixelFormat
 }
 
-func (device *Device) initMemory(ctx context.Context, descriptor io.DeviceDescriptor) (v4l2io.BoundMmapBuffers, error) {
-	bufferCount, err := v4l2io.RequestBuffers(descriptor, v4l2io.BufferTypeVideoCapture, v4l2io.MemoryTypeMmap, 4)
+func (device *Device) initMemory(ctx context.Context, videoDevice v4l2io.Device) (v4l2io.BoundMmapBuffers, error) {
+	bufferCount, err := videoDevice.RequestBuffers(v4l2io.BufferTypeVideoCapture, v4l2io.MemoryTypeMmap, 4)
 	if err != nil {
 		return v4l2io.EmptyBoundMmapBuffers, fmt.Errorf("request buffers: %w", err)
 	}
@@ -466,23 +542,23 @@ func (device *Device) initMemory(ctx context.Context, descriptor io.DeviceDescri
 	buffers := make(v4l2io.BoundMmapBuffers, bufferCount)
 
 	for bufferIndex := v4l2io.BufferIndex(0); bufferIndex < bufferCount; bufferIndex++ {
-		buffer, err := v4l2io.QueryMmapBuffer(descriptor, v4l2io.BufferTypeVideoCapture, bufferIndex)
+		buffer, err := videoDevice.QueryMmapBuffer(v4l2io.BufferTypeVideoCapture, bufferIndex)
 		if err != nil {
 			return v4l2io.EmptyBoundMmapBuffers, fmt.Errorf("query buffer: %d: %w", bufferIndex, err)
 		}
 
-		boundBuffer, err := v4l2io.BindMmapBuffer(descriptor, buffer)
+		boundBuffer, err := videoDevice.BindMmapBuffer(buffer)
 		if err != nil {
 			for i := v4l2io.BufferIndex(0); i < bufferIndex; i++ {
-				_ = v4l2io.UnbindMmapBuffer(buffers[bufferIndex])
+				_ = videoDevice.UnbindMmapBuffer(buffers[bufferIndex])
 			}
 			return v4l2io.EmptyBoundMmapBuffers, fmt.Errorf("bind buffer: %d: %w", bufferIndex, err)
 		}
 
-		err = v4l2io.QueueBuffer(descriptor, buffer.BufferDescriptor)
+		err = videoDevice.QueueBuffer(buffer.BufferDescriptor)
 		if err != nil {
 			for i := v4l2io.BufferIndex(0); i < bufferIndex; i++ {
-				_ = v4l2io.UnbindMmapBuffer(buffers[bufferIndex])
+				_ = videoDevice.UnbindMmapBuffer(buffers[bufferIndex])
 			}
 			return v4l2io.EmptyBoundMmapBuffers, fmt.Errorf("queue buffer: %d: %w", bufferIndex, err)
 		}
@@ -495,19 +571,19 @@ func (device *Device) initMemory(ctx context.Context, descriptor io.DeviceDescri
 	return buffers, nil
 }
 
-func (device *Device) releaseMemory(ctx context.Context, descriptor io.DeviceDescriptor, buffers v4l2io.BoundMmapBuffers) error {
+func (device *Device) releaseMemory(ctx context.Context, videoDevice v4l2io.Device, buffers v4l2io.BoundMmapBuffers) error {
 	releaseMemoryCtx, releaseMemoryCancel := context.WithTimeout(ctx, time.Second*10)
 	defer releaseMemoryCancel()
 
 	for bufferIndex := v4l2io.BufferIndex(0); bufferIndex < v4l2io.BufferIndex(len(buffers)); bufferIndex++ {
-		err := v4l2io.UnbindMmapBuffer(buffers[bufferIndex])
+		err := videoDevice.UnbindMmapBuffer(buffers[bufferIndex])
 		if err != nil {
 			return fmt.Errorf("unbind buffer: %w", err)
 		}
 	}
 
 	err := io.RetryOnError(releaseMemoryCtx, func() error {
-		return v4l2io.ReleaseBuffers(descriptor, v4l2io.BufferTypeVideoCapture, v4l2io.MemoryTypeMmap)
+		return videoDevice.ReleaseBuffers(v4l2io.BufferTypeVideoCapture, v4l2io.MemoryTypeMmap)
 	}, io.ErrDeviceOrResourceBusy)
 	if err != nil {
 		return fmt.Errorf("release buffers: %w", err)
@@ -518,32 +594,38 @@ func (device *Device) releaseMemory(ctx context.Context, descriptor io.DeviceDes
 	return nil
 }
 
-func (device *Device) openDevice(ctx context.Context) (io.DeviceDescriptor, error) {
-	descriptor, err := io.OpenDevice(device.devicePath, unix.O_RDWR|unix.O_NONBLOCK, 0)
+func (device *Device) openDevice(ctx context.Context) (v4l2io.Device, error) {
+	videoDevice, err := device.backend.Open(device.devicePath)
 	if err != nil {
-		return io.EmptyDeviceDescriptor, fmt.Errorf("open device: %w", err)
+		return nil, fmt.Errorf("open device: %w", err)
 	}
 
-	capabilities, err := v4l2io.QueryCapabilities(descriptor)
+	capabilities, err := videoDevice.QueryCapabilities()
 	if err != nil {
-		_ = io.Close(descriptor)
-		return io.EmptyDeviceDescriptor, err
+		_ = videoDevice.Close()
+		return nil, err
 	}
 
 	if !capabilities.Features.VideoCapture {
-		_ = io.Close(descriptor)
-		return io.EmptyDeviceDescriptor, ErrVideoCaptureNotSupported
+		_ = videoDevice.Close()
+		return nil, ErrVideoCaptureNotSupported
 	}
 
 	if !capabilities.Features.Streaming {
-		_ = io.Close(descriptor)
-		return io.EmptyDeviceDescriptor, ErrStreamingNotSupported
+		_ = videoDevice.Close()
+		return nil, ErrStreamingNotSupported
+	}
+
+	err = device.setupEdid(videoDevice)
+	if err != nil {
+		_ = videoDevice.Close()
+		return nil, fmt.Errorf("setup edid: %w", err)
 	}
 
-	err = v4l2io.SubscribeEvent(descriptor, v4l2io.EventTypeSourceChange)
+	err = videoDevice.SubscribeEvent(v4l2io.EventTypeSourceChange)
 	if err != nil {
-		_ = io.Close(descriptor)
-		return io.EmptyDeviceDescriptor, fmt.Errorf("subscribe event: %w", err)
+		_ = videoDevice.Close()
+		return nil, fmt.Errorf("subscribe event: %w", err)
 	}
 
 	device.logger.Debug("Device open.",
@@ -551,11 +633,44 @@ func (device *Device) openDevice(ctx context.Context) (io.DeviceDescriptor, erro
 		slog.String("deviceBus", capabilities.BusInfo),
 	)
 
-	return descriptor, nil
+	return videoDevice, nil
+}
+
+// setupEdid programs configured EDID when the device presents a different one. Programming EDID toggles hot plug
+// signal of the connected machine, so it is skipped when the device already presents it, as the device is reopened on
+// every signal change.
+func (device *Device) setupEdid(videoDevice v4l2io.Device) error {
+	device.edidLock.Lock()
+	defer device.edidLock.Unlock()
+
+	activeEdid, err := videoDevice.GetEdid(edidPad)
+	if err != nil {
+		if device.edid != nil {
+			return fmt.Errorf("get edid: %w", err)
+		}
+
+		device.logger.Debug("Failed to read EDID.", slog.String("error", err.Error()))
+		return nil
+	}
+
+	if device.edid != nil && !bytes.Equal(activeEdid, device.edid) {
+		err = videoDevice.SetEdid(edidPad, device.edid)
+		if err != nil {
+			return fmt.Errorf("set edid: %w", err)
+		}
+
+		activeEdid = device.edid
+
+		device.logger.Info("EDID programmed.", slog.Int("edidBlocks", len(device.edid)/v4l2io.EdidBlockLength))
+	}
+
+	device.activeEdid = activeEdid
+
+	return nil
 }
 
-func (device *Device) closeDevice(ctx context.Context, descriptor io.DeviceDescriptor) error {
-	err := io.Close(descriptor)
+func (device *Device) closeDevice(ctx context.Context, videoDevice v4l2io.Device) error {
+	err := videoDevice.Close()
 
 	device.logger.Debug("Device closed.")
 
@@ -0,0 +1,172 @@
+//go:build linux
+
+package tc358743
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/memory"
+	v4l2io "github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/linux/v4l2/io"
+	memorySDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/memory"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const frameWaitTimeout = time.Second * 5
+
+// openSimulatedDevice opens the device on the simulated backend and returns channel receiving sizes of captured
+// frames, the device is terminated when the test ends.
+func openSimulatedDevice(t *testing.T, backend *v4l2io.SimulatedBackend, opts ...DeviceOpt) (*Device, <-chan int) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	frames := make(chan int, 16)
+
+	frameHandler := func(memoryBuffer memorySDK.Buffer) error {
+		select {
+		case frames <- memoryBuffer.GetSize():
+		default:
+		}
+
+		return memoryBuffer.Release()
+	}
+
+	memoryPool, err := memory.NewHeapPool(64*48*3, 16)
+	require.NoError(t, err)
+
+	memoryPoolProvider := func() (memorySDK.Pool, error) {
+		return memoryPool, nil
+	}
+
+	opts = append([]DeviceOpt{
+		WithBackend(backend),
+		WithFrameHandler(frameHandler),
+		WithMemoryPoolProvider(memoryPoolProvider),
+	}, opts...)
+
+	device, err := Open(ctx, "/dev/video0", opts...)
+	require.NoError(t, err)
+
+	return device, frames
+}
+
+// waitForFrame waits for a frame of the size, frames of other sizes captured before the signal change are skipped.
+func waitForFrame(t *testing.T, frames <-chan int, size int) {
+	t.Helper()
+
+	timeout := time.After(frameWaitTimeout)
+
+	for {
+		select {
+		case frameSize := <-frames:
+			if frameSize == size {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no frame of %d bytes captured", size)
+		}
+	}
+}
+
+func createEdid(value byte) []byte {
+	return bytes.Repeat([]byte{value}, v4l2io.EdidBlockLength)
+}
+
+func TestDeviceCapturesFrames(t *testing.T) {
+	backend := v4l2io.NewSimulatedBackend(v4l2io.WithSimulatedTimings(v4l2io.NewSimulatedTimings(64, 48, 30)))
+
+	device, frames := openSimulatedDevice(t, backend)
+
+	waitForFrame(t, frames, 64*48*3)
+
+	displayMode, err := device.GetDisplayMode()
+	require.NoError(t, err)
+	assert.Equal(t, &peripheralSDK.DisplayMode{Width: 64, Height: 48, RefreshRate: 30}, displayMode)
+}
+
+func TestDeviceWaitsForSignal(t *testing.T) {
+	backend := v4l2io.NewSimulatedBackend()
+
+	device, frames := openSimulatedDevice(t, backend)
+
+	select {
+	case <-frames:
+		t.Fatal("frame captured without signal")
+	case <-time.After(time.Millisecond * 200):
+	}
+
+	displayMode, err := device.GetDisplayMode()
+	require.NoError(t, err)
+	assert.Nil(t, displayMode)
+
+	timings := v4l2io.NewSimulatedTimings(32, 24, 60)
+	backend.SetTimings(&timings)
+
+	waitForFrame(t, frames, 32*24*3)
+}
+
+func TestDeviceReacquiresSignalOnSourceChange(t *testing.T) {
+	backend := v4l2io.NewSimulatedBackend(v4l2io.WithSimulatedTimings(v4l2io.NewSimulatedTimings(64, 48, 30)))
+
+	device, frames := openSimulatedDevice(t, backend)
+
+	waitForFrame(t, frames, 64*48*3)
+
+	timings := v4l2io.NewSimulatedTimings(32, 24, 50)
+
+	backend.PlayTimingsScript(t.Context(),
+		v4l2io.SimulatedTimingsStep{Timings: nil, Duration: time.Millisecond * 100},
+		v4l2io.SimulatedTimingsStep{Timings: &timings},
+	)
+
+	waitForFrame(t, frames, 32*24*3)
+
+	displayMode, err := device.GetDisplayMode()
+	require.NoError(t, err)
+	assert.Equal(t, &peripheralSDK.DisplayMode{Width: 32, Height: 24, RefreshRate: 50}, displayMode)
+}
+
+func TestDeviceRecoversFromErrors(t *testing.T) {
+	backend := v4l2io.NewSimulatedBackend(v4l2io.WithSimulatedTimings(v4l2io.NewSimulatedTimings(64, 48, 30)))
+
+	backend.InjectError(v4l2io.SimulatedOperationDequeueBuffer, unix.EAGAIN, 3)
+
+	_, frames := openSimulatedDevice(t, backend)
+
+	waitForFrame(t, frames, 64*48*3)
+
+	backend.InjectError(v4l2io.SimulatedOperationDequeueEvent, unix.ENOENT, 2)
+	backend.InjectError(v4l2io.SimulatedOperationOpen, unix.ENODEV, 2)
+	backend.InjectError(v4l2io.SimulatedOperationQueryDigitalVideoBTTimings, unix.EBUSY, 2)
+
+	timings := v4l2io.NewSimulatedTimings(32, 24, 30)
+	backend.SetTimings(&timings)
+
+	waitForFrame(t, frames, 32*24*3)
+}
+
+func TestDeviceProgramsEdid(t *testing.T) {
+	backend := v4l2io.NewSimulatedBackend(v4l2io.WithSimulatedEdid(createEdid(1)))
+
+	device, _ := openSimulatedDevice(t, backend, WithEdid(createEdid(2)))
+
+	assert.Equal(t, createEdid(2), backend.GetEdid())
+
+	edid, err := device.GetEdid()
+	require.NoError(t, err)
+	assert.Equal(t, createEdid(2), edid)
+
+	require.NoError(t, device.SetEdid(createEdid(3)))
+	assert.Equal(t, createEdid(3), backend.GetEdid())
+
+	assert.Error(t, device.SetEdid([]byte{1, 2, 3}))
+	assert.Equal(t, createEdid(3), backend.GetEdid())
+}
@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrVideoCaptureNotSupported = errors.New("video capture not supported")
 	ErrStreamingNotSupported    = errors.New("streaming not supported")
+	ErrEdidNotAvailable         = errors.New("edid not available")
 )
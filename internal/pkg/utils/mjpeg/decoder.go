@@ -0,0 +1,161 @@
+package mjpeg
+
+import (
+	"bytes"
+	"fmt"
+	"image/jpeg"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/rgb"
+)
+
+const (
+	markerPrefix           = 0xFF
+	markerStartOfImage     = 0xD8
+	markerStartOfScan      = 0xDA
+	markerHuffmanTable     = 0xC4
+	markerStandaloneFirst  = 0xD0
+	markerStandaloneLast   = 0xD9
+	markerTemporaryPrivate = 0x01
+)
+
+// Decoder converts JPEG images of MJPEG stream into RGB24 frames. Output buffer is reused between calls, so Decoder is
+// not safe for concurrent use.
+type Decoder struct {
+	image  bytes.Buffer
+	pixels []byte
+}
+
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// Decode returns RGB24 pixels of the JPEG image with its size. Images without Huffman tables, which many UVC cameras
+// send to save bandwidth, are decoded with standard tables. Returned slice is valid until the next call.
+func (decoder *Decoder) Decode(image []byte) ([]byte, int, int, error) {
+	if !hasHuffmanTables(image) {
+		decoder.image.Reset()
+		decoder.image.Write(image[:2])
+		decoder.image.Write(standardHuffmanTablesSegment)
+		decoder.image.Write(image[2:])
+
+		image = decoder.image.Bytes()
+	}
+
+	decoded, err := jpeg.Decode(bytes.NewReader(image))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("decode jpeg: %w", err)
+	}
+
+	decoder.pixels = rgb.FromImage(decoder.pixels, decoded)
+
+	return decoder.pixels, decoded.Bounds().Dx(), decoded.Bounds().Dy(), nil
+}
+
+// hasHuffmanTables reports whether the image defines Huffman tables before its first scan. Malformed images are
+// reported as having tables, so the decoder reports them as they are.
+func hasHuffmanTables(image []byte) bool {
+	if len(image) < 4 || image[0] != markerPrefix || image[1] != markerStartOfImage {
+		return true
+	}
+
+	for offset := 2; offset+4 <= len(image); {
+		if image[offset] != markerPrefix {
+			return true
+		}
+
+		marker := image[offset+1]
+		switch {
+		case marker == markerPrefix:
+			// fill byte preceding the marker
+			offset++
+			continue
+		case marker == markerHuffmanTable:
+			return true
+		case marker == markerStartOfScan:
+			return false
+		case marker == markerTemporaryPrivate, marker >= markerStandaloneFirst && marker <= markerStandaloneLast:
+			offset += 2
+			continue
+		}
+
+		offset += 2 + (int(image[offset+2])<<8 | int(image[offset+3]))
+	}
+
+	return true
+}
+
+type huffmanTable struct {
+	class       byte
+	destination byte
+	counts      [16]byte
+	values      []byte
+}
+
+// standardHuffmanTables are the typical tables of ITU-T T.81 Annex K.3, which MJPEG streams without tables are
+// encoded with, as specified by AVI1 and UVC payload formats.
+var standardHuffmanTables = []huffmanTable{
+	{
+		class:       0,
+		destination: 0,
+		counts:      [16]byte{0, 1, 5, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0},
+		values:      []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B},
+	},
+	{
+		class:       1,
+		destination: 0,
+		counts:      [16]byte{0, 2, 1, 3, 3, 2, 4, 3, 5, 5, 4, 4, 0, 0, 1, 0x7D},
+		values: []byte{
+			0x01, 0x02, 0x03, 0x00, 0x04, 0x11, 0x05, 0x12, 0x21, 0x31, 0x41, 0x06, 0x13, 0x51, 0x61, 0x07,
+			0x22, 0x71, 0x14, 0x32, 0x81, 0x91, 0xA1, 0x08, 0x23, 0x42, 0xB1, 0xC1, 0x15, 0x52, 0xD1, 0xF0,
+			0x24, 0x33, 0x62, 0x72, 0x82, 0x09, 0x0A, 0x16, 0x17, 0x18, 0x19, 0x1A, 0x25, 0x26, 0x27, 0x28,
+			0x29, 0x2A, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3A, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49,
+			0x4A, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5A, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69,
+			0x6A, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7A, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89,
+			0x8A, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9A, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7,
+			0xA8, 0xA9, 0xAA, 0xB2, 0xB3, 0xB4, 0xB5, 0xB6, 0xB7, 0xB8, 0xB9, 0xBA, 0xC2, 0xC3, 0xC4, 0xC5,
+			0xC6, 0xC7, 0xC8, 0xC9, 0xCA, 0xD2, 0xD3, 0xD4, 0xD5, 0xD6, 0xD7, 0xD8, 0xD9, 0xDA, 0xE1, 0xE2,
+			0xE3, 0xE4, 0xE5, 0xE6, 0xE7, 0xE8, 0xE9, 0xEA, 0xF1, 0xF2, 0xF3, 0xF4, 0xF5, 0xF6, 0xF7, 0xF8,
+			0xF9, 0xFA,
+		},
+	},
+	{
+		class:       0,
+		destination: 1,
+		counts:      [16]byte{0, 3, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0},
+		values:      []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B},
+	},
+	{
+		class:       1,
+		destination: 1,
+		counts:      [16]byte{0, 2, 1, 2, 4, 4, 3, 4, 7, 5, 4, 4, 0, 1, 2, 0x77},
+		values: []byte{
+			0x00, 0x01, 0x02, 0x03, 0x11, 0x04, 0x05, 0x21, 0x31, 0x06, 0x12, 0x41, 0x51, 0x07, 0x61, 0x71,
+			0x13, 0x22, 0x32, 0x81, 0x08, 0x14, 0x42, 0x91, 0xA1, 0xB1, 0xC1, 0x09, 0x23, 0x33, 0x52, 0xF0,
+			0x15, 0x62, 0x72, 0xD1, 0x0A, 0x16, 0x24, 0x34, 0xE1, 0x25, 0xF1, 0x17, 0x18, 0x19, 0x1A, 0x26,
+			0x27, 0x28, 0x29, 0x2A, 0x35, 0x36, 0x37, 0x38, 0x39, 0x3A, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48,
+			0x49, 0x4A, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5A, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68,
+			0x69, 0x6A, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7A, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87,
+			0x88, 0x89, 0x8A, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9A, 0xA2, 0xA3, 0xA4, 0xA5,
+			0xA6, 0xA7, 0xA8, 0xA9, 0xAA, 0xB2, 0xB3, 0xB4, 0xB5, 0xB6, 0xB7, 0xB8, 0xB9, 0xBA, 0xC2, 0xC3,
+			0xC4, 0xC5, 0xC6, 0xC7, 0xC8, 0xC9, 0xCA, 0xD2, 0xD3, 0xD4, 0xD5, 0xD6, 0xD7, 0xD8, 0xD9, 0xDA,
+			0xE2, 0xE3, 0xE4, 0xE5, 0xE6, 0xE7, 0xE8, 0xE9, 0xEA, 0xF2, 0xF3, 0xF4, 0xF5, 0xF6, 0xF7, 0xF8,
+			0xF9, 0xFA,
+		},
+	},
+}
+
+var standardHuffmanTablesSegment = createHuffmanTablesSegment(standardHuffmanTables)
+
+// createHuffmanTablesSegment returns DHT marker segment defining the tables.
+func createHuffmanTablesSegment(tables []huffmanTable) []byte {
+	payload := []byte{}
+	for _, table := range tables {
+		payload = append(payload, table.class<<4|table.destination)
+		payload = append(payload, table.counts[:]...)
+		payload = append(payload, table.values...)
+	}
+
+	length := len(payload) + 2
+
+	return append([]byte{markerPrefix, markerHuffmanTable, byte(length >> 8), byte(length)}, payload...)
+}
@@ -0,0 +1,103 @@
+package mjpeg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/rgb"
+)
+
+func encodeTestImage(t *testing.T) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 32; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 8), G: uint8(y * 16), B: 100, A: 255})
+		}
+	}
+
+	output := bytes.Buffer{}
+	require.NoError(t, jpeg.Encode(&output, img, &jpeg.Options{Quality: 90}))
+
+	return output.Bytes()
+}
+
+// stripHuffmanTables removes DHT segments, like UVC cameras do.
+func stripHuffmanTables(t *testing.T, image []byte) []byte {
+	t.Helper()
+
+	stripped := []byte{image[0], image[1]}
+	for offset := 2; ; {
+		require.Equal(t, byte(markerPrefix), image[offset])
+
+		if image[offset+1] == markerStartOfScan {
+			return append(stripped, image[offset:]...)
+		}
+
+		length := int(image[offset+2])<<8 | int(image[offset+3])
+		if image[offset+1] != markerHuffmanTable {
+			stripped = append(stripped, image[offset:offset+2+length]...)
+		}
+
+		offset += 2 + length
+	}
+}
+
+func TestDecoderDecode(t *testing.T) {
+	t.Parallel()
+
+	image := encodeTestImage(t)
+
+	expected, err := jpeg.Decode(bytes.NewReader(image))
+	require.NoError(t, err)
+
+	decoder := NewDecoder()
+
+	pixels, width, height, err := decoder.Decode(image)
+	require.NoError(t, err)
+	assert.Equal(t, 32, width)
+	assert.Equal(t, 16, height)
+	assert.Equal(t, rgb.FromImage(nil, expected), pixels)
+}
+
+func TestDecoderDecodeWithoutHuffmanTables(t *testing.T) {
+	t.Parallel()
+
+	image := encodeTestImage(t)
+	stripped := stripHuffmanTables(t, image)
+
+	assert.True(t, hasHuffmanTables(image))
+	assert.False(t, hasHuffmanTables(stripped))
+
+	_, err := jpeg.Decode(bytes.NewReader(stripped))
+	require.Error(t, err)
+
+	decoder := NewDecoder()
+
+	expected, _, _, err := decoder.Decode(image)
+	require.NoError(t, err)
+	expected = bytes.Clone(expected)
+
+	pixels, _, _, err := decoder.Decode(stripped)
+	require.NoError(t, err)
+	assert.Equal(t, expected, pixels)
+}
+
+func TestDecoderDecodeInvalidImage(t *testing.T) {
+	t.Parallel()
+
+	decoder := NewDecoder()
+
+	_, _, _, err := decoder.Decode([]byte{0xFF, 0xD8, 0xFF})
+	assert.Error(t, err)
+
+	_, _, _, err = decoder.Decode(nil)
+	assert.Error(t, err)
+}
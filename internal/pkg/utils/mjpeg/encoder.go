@@ -0,0 +1,51 @@
+package mjpeg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/format/rgb"
+)
+
+// Encoder converts RGB24 frames into JPEG images. Intermediate image and output buffer are reused between calls, so
+// Encoder is not safe for concurrent use.
+type Encoder struct {
+	quality int
+
+	image  *image.RGBA
+	output bytes.Buffer
+}
+
+func NewEncoder(quality int) (*Encoder, error) {
+	if quality < 1 || quality > 100 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidQuality, quality)
+	}
+
+	return &Encoder{
+		quality: quality,
+	}, nil
+}
+
+// Encode returns JPEG image of the frame. Returned slice is valid until the next call.
+func (encoder *Encoder) Encode(pixels []byte, width int, height int) ([]byte, error) {
+	if width <= 0 || height <= 0 || len(pixels) != width*height*3 {
+		return nil, fmt.Errorf("%w: %d bytes for %dx%d", ErrInvalidFrameSize, len(pixels), width, height)
+	}
+
+	encoder.image = rgb.ToRGBA(encoder.image, pixels, width, height)
+
+	encoder.output.Reset()
+	if err := jpeg.Encode(&encoder.output, encoder.image, &jpeg.Options{Quality: encoder.quality}); err != nil {
+		return nil, fmt.Errorf("encode jpeg: %w", err)
+	}
+
+	return encoder.output.Bytes(), nil
+}
+
+var (
+	ErrInvalidQuality   = errors.New("invalid jpeg quality")
+	ErrInvalidFrameSize = errors.New("invalid frame size")
+)
@@ -0,0 +1,53 @@
+package mjpeg
+
+import (
+	"bytes"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoderEncode(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := NewEncoder(90)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pixels := bytes.Repeat([]byte{200, 100, 50}, 32*16)
+
+	image, err := encoder.Encode(pixels, 32, 16)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	decoded, err := jpeg.Decode(bytes.NewReader(image))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 32, decoded.Bounds().Dx())
+	assert.Equal(t, 16, decoded.Bounds().Dy())
+
+	red, green, blue, _ := decoded.At(8, 8).RGBA()
+	assert.InDelta(t, 200, red>>8, 4)
+	assert.InDelta(t, 100, green>>8, 4)
+	assert.InDelta(t, 50, blue>>8, 4)
+}
+
+func TestEncoderRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewEncoder(0)
+	assert.ErrorIs(t, err, ErrInvalidQuality)
+
+	encoder, err := NewEncoder(80)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = encoder.Encode(make([]byte, 10), 2, 2)
+	assert.ErrorIs(t, err, ErrInvalidFrameSize)
+}
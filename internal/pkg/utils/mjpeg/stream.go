@@ -0,0 +1,129 @@
+package mjpeg
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+const streamBoundary = "frame"
+
+// Stream broadcasts the latest published JPEG image to HTTP clients as multipart/x-mixed-replace response. Slow
+// clients skip intermediate images instead of delaying others.
+type Stream struct {
+	image    []byte
+	notify   chan struct{}
+	closed   bool
+	watchers int
+
+	lock sync.Mutex
+}
+
+func NewStream() *Stream {
+	return &Stream{
+		notify: make(chan struct{}),
+	}
+}
+
+// Publish replaces the latest image and wakes up all clients. Image must not be modified afterwards.
+func (stream *Stream) Publish(image []byte) {
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+
+	if stream.closed {
+		return
+	}
+
+	stream.image = image
+
+	close(stream.notify)
+	stream.notify = make(chan struct{})
+}
+
+// GetWatchers returns number of connected clients.
+func (stream *Stream) GetWatchers() int {
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+
+	return stream.watchers
+}
+
+// Close disconnects all clients. Published images are ignored afterwards.
+func (stream *Stream) Close() {
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+
+	if stream.closed {
+		return
+	}
+
+	stream.closed = true
+	close(stream.notify)
+}
+
+func (stream *Stream) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	flusher, isFlusher := writer.(http.Flusher)
+	if !isFlusher {
+		http.Error(writer, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	stream.lock.Lock()
+	stream.watchers++
+	image, notify, closed := stream.image, stream.notify, stream.closed
+	stream.lock.Unlock()
+
+	defer func() {
+		stream.lock.Lock()
+		stream.watchers--
+		stream.lock.Unlock()
+	}()
+
+	writer.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+streamBoundary)
+	writer.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	writer.Header().Set("Connection", "close")
+	writer.WriteHeader(http.StatusOK)
+
+	if _, err := io.WriteString(writer, "--"+streamBoundary+"\r\n"); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	for !closed {
+		if image != nil {
+			if err := writePart(writer, image); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+
+		select {
+		case <-request.Context().Done():
+			return
+		case <-notify:
+		}
+
+		stream.lock.Lock()
+		image, notify, closed = stream.image, stream.notify, stream.closed
+		stream.lock.Unlock()
+	}
+}
+
+// writePart writes image followed by the boundary, so clients show the image without waiting for the next one.
+func writePart(writer io.Writer, image []byte) error {
+	header := fmt.Sprintf("Content-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", len(image))
+	if _, err := io.WriteString(writer, header); err != nil {
+		return fmt.Errorf("write part header: %w", err)
+	}
+
+	if _, err := writer.Write(image); err != nil {
+		return fmt.Errorf("write part: %w", err)
+	}
+
+	if _, err := io.WriteString(writer, "\r\n--"+streamBoundary+"\r\n"); err != nil {
+		return fmt.Errorf("write boundary: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package mjpeg
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamServesPublishedImages(t *testing.T) {
+	t.Parallel()
+
+	stream := NewStream()
+	server := httptest.NewServer(stream)
+	defer server.Close()
+
+	response, err := http.Get(server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	mediaType, params, err := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "multipart/x-mixed-replace", mediaType)
+
+	assert.Eventually(t, func() bool {
+		return stream.GetWatchers() == 1
+	}, time.Second, time.Millisecond)
+
+	reader := multipart.NewReader(response.Body, params["boundary"])
+
+	for _, image := range [][]byte{[]byte("first"), []byte("second")} {
+		stream.Publish(image)
+
+		part, err := reader.NextPart()
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, "image/jpeg", part.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(part)
+		assert.NoError(t, err)
+		assert.Equal(t, image, body)
+	}
+
+	stream.Close()
+
+	_, err = reader.NextPart()
+	assert.Error(t, err)
+
+	assert.Eventually(t, func() bool {
+		return stream.GetWatchers() == 0
+	}, time.Second, time.Millisecond)
+}
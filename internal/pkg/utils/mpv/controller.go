@@ -0,0 +1,433 @@
+package mpv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/process"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const (
+	mpvStableDuration     = 2 * time.Second
+	ipcConnectTimeout     = 5 * time.Second
+	ipcConnectRetryPeriod = 50 * time.Millisecond
+	videoWriteTimeout     = 5 * time.Second
+)
+
+// SupervisorProvider is a function that creates a new process.Supervisor instance.
+// It accepts the same parameters as process.SuperviseLocal.
+type SupervisorProvider func(specification process.Specification, restartPolicy process.RestartPolicy) process.Supervisor
+
+type controllerOptions struct {
+	executablePath     string
+	ipcSocketPath      string
+	arguments          []string
+	logger             *slog.Logger
+	supervisorProvider SupervisorProvider
+}
+
+func defaultControllerOptions() controllerOptions {
+	return controllerOptions{
+		executablePath: "/usr/local/bin/mpv",
+		logger:         slog.New(slog.DiscardHandler),
+	}
+}
+
+type ControllerOpt func(*controllerOptions) error
+
+func WithControllerLogger(logger *slog.Logger) ControllerOpt {
+	return func(options *controllerOptions) error {
+		options.logger = logger
+		return nil
+	}
+}
+
+func WithControllerExecutablePath(executablePath string) ControllerOpt {
+	return func(options *controllerOptions) error {
+		options.executablePath = executablePath
+		return nil
+	}
+}
+
+// WithControllerArguments adds arguments passed to mpv, e.g. --fs or --geometry.
+func WithControllerArguments(arguments ...string) ControllerOpt {
+	return func(options *controllerOptions) error {
+		options.arguments = append(options.arguments, arguments...)
+		return nil
+	}
+}
+
+func WithControllerSupervisorProvider(provider SupervisorProvider) ControllerOpt {
+	return func(options *controllerOptions) error {
+		options.supervisorProvider = provider
+		return nil
+	}
+}
+
+// Controller runs mpv under process supervisor and controls it through JSON IPC. Raw video is played from a named
+// pipe next to the IPC socket. Display mode is changed by loading a new pipe with new demuxer options, so the window
+// is kept open and no bytes of previous display mode are read as frames of the new one.
+type Controller struct {
+	options *controllerOptions
+	process process.Supervisor
+
+	ipc     *IPCClient
+	ipcLock sync.Mutex
+
+	// video is the pipe mpv plays, videoStale is set when mpv was restarted and video must be loaded again.
+	// videoLock is always taken before ipcLock.
+	video         *videoPipe
+	videoSequence uint64
+	videoStale    atomic.Bool
+	videoLock     sync.Mutex
+
+	// state is replayed when mpv was restarted by supervisor
+	title       string
+	message     string
+	displayMode *peripheralSDK.DisplayMode
+	stateLock   sync.Mutex
+
+	logger *slog.Logger
+}
+
+// NewController prepares mpv listening on IPC socket at ipcSocketPath.
+func NewController(ipcSocketPath string, opts ...ControllerOpt) (*Controller, error) {
+	if ipcSocketPath == "" {
+		return nil, ErrMissingIPCSocketPath
+	}
+
+	options := defaultControllerOptions()
+	options.ipcSocketPath = ipcSocketPath
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return nil, err
+		}
+	}
+
+	arguments := slices.Concat(
+		[]string{
+			"--idle=yes",
+			"--force-window=yes",
+			"--keep-open=always",
+			"--no-terminal",
+			"--profile=low-latency",
+			"--untimed",
+			"--cache=no",
+			"--demuxer=rawvideo",
+			"--demuxer-rawvideo-mp-format=rgb24",
+			"--input-ipc-server=" + ipcSocketPath,
+		},
+		options.arguments,
+	)
+
+	supervisorProvider := options.supervisorProvider
+	if supervisorProvider == nil {
+		supervisorProvider = func(specification process.Specification, restartPolicy process.RestartPolicy) process.Supervisor {
+			return process.SuperviseLocal(specification, restartPolicy, process.WithLogger(options.logger))
+		}
+	}
+
+	controller := &Controller{
+		options: &options,
+		process: supervisorProvider(process.Specification{
+			ExecutablePath: options.executablePath,
+			Arguments:      arguments,
+		}, process.RestartPolicy{
+			Enabled:      true,
+			MaxAttempts:  10,
+			Strategy:     process.StrategyExponential,
+			InitialDelay: time.Second,
+			MaxDelay:     time.Second * 5,
+			ResetWindow:  time.Second * 2,
+		}),
+		ipcLock:   sync.Mutex{},
+		videoLock: sync.Mutex{},
+		stateLock: sync.Mutex{},
+		logger:    options.logger,
+	}
+
+	controller.logger.Debug("Mpv prepared for start.",
+		slog.String("mpvArguments", strings.Join(arguments, " ")),
+	)
+
+	return controller, nil
+}
+
+func (controller *Controller) Start(ctx context.Context) error {
+	if err := controller.process.Start(ctx, mpvStableDuration); err != nil {
+		return fmt.Errorf("error starting mpv: %w", err)
+	}
+
+	if _, err := controller.getIPC(ctx); err != nil {
+		_ = controller.process.Stop(ctx)
+		return err
+	}
+
+	controller.logger.Debug("Mpv started.")
+
+	return nil
+}
+
+func (controller *Controller) Stop(ctx context.Context) error {
+	controller.videoLock.Lock()
+	controller.closeVideo()
+	controller.videoLock.Unlock()
+
+	controller.ipcLock.Lock()
+	if controller.ipc != nil {
+		_ = controller.ipc.Close()
+		controller.ipc = nil
+	}
+	controller.ipcLock.Unlock()
+
+	err := controller.process.Stop(ctx)
+
+	controller.logger.Debug("Mpv stopped.")
+
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, process.KilledError{}) {
+		return nil
+	}
+
+	return fmt.Errorf("error stopping mpv: %w", err)
+}
+
+// LoadRawVideo starts playing RGB24 frames of display mode written by WriteFrame. Frames written before are dropped.
+func (controller *Controller) LoadRawVideo(ctx context.Context, displayMode peripheralSDK.DisplayMode) error {
+	controller.stateLock.Lock()
+	controller.displayMode = &displayMode
+	controller.stateLock.Unlock()
+
+	controller.videoLock.Lock()
+	defer controller.videoLock.Unlock()
+
+	return controller.loadRawVideo(ctx, displayMode)
+}
+
+// WriteFrame writes the whole frame of the loaded display mode. Frames are never interleaved with display mode
+// changes. Video is loaded again when mpv was restarted or the previous write failed.
+func (controller *Controller) WriteFrame(ctx context.Context, frame io.WriterTo) error {
+	controller.videoLock.Lock()
+	defer controller.videoLock.Unlock()
+
+	if controller.videoStale.Swap(false) {
+		controller.closeVideo()
+	}
+
+	if controller.video == nil {
+		controller.stateLock.Lock()
+		displayMode := controller.displayMode
+		controller.stateLock.Unlock()
+
+		if displayMode == nil {
+			return ErrRawVideoNotLoaded
+		}
+
+		if err := controller.loadRawVideo(ctx, *displayMode); err != nil {
+			return err
+		}
+	}
+
+	if err := controller.video.write(frame, videoWriteTimeout); err != nil {
+		// part of the frame may be in the pipe already, the next frame is written to a new one
+		controller.closeVideo()
+
+		return fmt.Errorf("write frame: %w", err)
+	}
+
+	return nil
+}
+
+func (controller *Controller) SetTitle(ctx context.Context, title string) error {
+	controller.stateLock.Lock()
+	controller.title = title
+	controller.stateLock.Unlock()
+
+	client, err := controller.getIPC(ctx)
+	if err != nil {
+		return err
+	}
+
+	return client.SetProperty(ctx, "title", title)
+}
+
+// SetMessage shows message on OSD until it is replaced, empty message hides it.
+func (controller *Controller) SetMessage(ctx context.Context, message string) error {
+	controller.stateLock.Lock()
+	controller.message = message
+	controller.stateLock.Unlock()
+
+	client, err := controller.getIPC(ctx)
+	if err != nil {
+		return err
+	}
+
+	return client.SetProperty(ctx, "options/osd-msg1", message)
+}
+
+func (controller *Controller) SetFullscreen(ctx context.Context, fullscreen bool) error {
+	client, err := controller.getIPC(ctx)
+	if err != nil {
+		return err
+	}
+
+	return client.SetProperty(ctx, "fullscreen", fullscreen)
+}
+
+// Command runs any mpv input command, see IPCClient.Command.
+func (controller *Controller) Command(ctx context.Context, result any, command ...any) error {
+	client, err := controller.getIPC(ctx)
+	if err != nil {
+		return err
+	}
+
+	return client.Command(ctx, result, command...)
+}
+
+// getIPC returns connected IPC client. Connection is established again when mpv was restarted, the last title and
+// message are applied to the new instance and video is loaded again with the next frame.
+func (controller *Controller) getIPC(ctx context.Context) (*IPCClient, error) {
+	controller.ipcLock.Lock()
+	defer controller.ipcLock.Unlock()
+
+	if controller.ipc != nil && controller.ipc.Err() == nil {
+		return controller.ipc, nil
+	}
+
+	reconnect := controller.ipc != nil
+	if reconnect {
+		_ = controller.ipc.Close()
+		controller.ipc = nil
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, ipcConnectTimeout)
+	defer cancel()
+
+	var client *IPCClient
+	var err error
+
+	for {
+		client, err = DialIPC(connectCtx, controller.options.ipcSocketPath, WithIPCClientLogger(controller.logger))
+		if err == nil {
+			break
+		}
+
+		select {
+		case <-connectCtx.Done():
+			return nil, fmt.Errorf("connect mpv ipc: %w", err)
+		case <-time.After(ipcConnectRetryPeriod):
+		}
+	}
+
+	controller.ipc = client
+
+	if reconnect {
+		controller.logger.Info("Mpv IPC reconnected, restoring state.")
+
+		if err := controller.restoreState(ctx, client); err != nil {
+			return nil, fmt.Errorf("restore mpv state: %w", err)
+		}
+	}
+
+	return client, nil
+}
+
+func (controller *Controller) restoreState(ctx context.Context, client *IPCClient) error {
+	controller.stateLock.Lock()
+	title := controller.title
+	message := controller.message
+	controller.stateLock.Unlock()
+
+	if title != "" {
+		if err := client.SetProperty(ctx, "title", title); err != nil {
+			return err
+		}
+	}
+
+	if message != "" {
+		if err := client.SetProperty(ctx, "options/osd-msg1", message); err != nil {
+			return err
+		}
+	}
+
+	// video is not loaded here, as videoLock can not be taken while holding ipcLock
+	controller.videoStale.Store(true)
+
+	return nil
+}
+
+// loadRawVideo plays video of display mode from a new pipe. Caller must hold videoLock.
+func (controller *Controller) loadRawVideo(ctx context.Context, displayMode peripheralSDK.DisplayMode) error {
+	client, err := controller.getIPC(ctx)
+	if err != nil {
+		return err
+	}
+
+	options := []struct {
+		name  string
+		value any
+	}{
+		{"options/demuxer-rawvideo-w", displayMode.Width},
+		{"options/demuxer-rawvideo-h", displayMode.Height},
+		{"options/demuxer-rawvideo-fps", displayMode.RefreshRate},
+	}
+
+	for _, option := range options {
+		if err := client.SetProperty(ctx, option.name, option.value); err != nil {
+			return fmt.Errorf("set %s: %w", option.name, err)
+		}
+	}
+
+	controller.videoSequence++
+
+	video, err := createVideoPipe(fmt.Sprintf("%s.video-%d", controller.options.ipcSocketPath, controller.videoSequence))
+	if err != nil {
+		return err
+	}
+
+	if err := client.Command(ctx, nil, "loadfile", video.path, "replace"); err != nil {
+		_ = video.close()
+		return fmt.Errorf("load video pipe: %w", err)
+	}
+
+	if err := video.open(ctx); err != nil {
+		_ = video.close()
+		return err
+	}
+
+	controller.closeVideo()
+	controller.video = video
+	controller.videoStale.Store(false)
+
+	return nil
+}
+
+// closeVideo closes the pipe being played, mpv keeps showing the last frame. Caller must hold videoLock.
+func (controller *Controller) closeVideo() {
+	if controller.video == nil {
+		return
+	}
+
+	if err := controller.video.close(); err != nil {
+		controller.logger.Warn("Failed to close mpv video pipe.", slog.String("error", err.Error()))
+	}
+
+	controller.video = nil
+}
+
+var (
+	ErrMissingIPCSocketPath = errors.New("missing mpv ipc socket path")
+	ErrRawVideoNotLoaded    = errors.New("mpv raw video not loaded")
+)
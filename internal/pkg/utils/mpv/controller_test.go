@@ -0,0 +1,262 @@
+package mpv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/process"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// testFrame is a frame read by testSupervisor from the video pipe loaded as pipe-th.
+type testFrame struct {
+	pipe int
+	data []byte
+}
+
+// testSupervisor stands for mpv process, Start begins accepting IPC connections on the socket. Loaded video pipes
+// are read in frames of the display mode set before, at most readLimits[pipe] frames are read from a pipe when set.
+// The pipe is then closed when closeAtLimit is set, like mpv does when it exits, or left unread.
+type testSupervisor struct {
+	process.Supervisor
+
+	socketPath    string
+	specification process.Specification
+	requests      chan testRequest
+	listener      net.Listener
+
+	readLimits   []int
+	closeAtLimit bool
+	frames       chan testFrame
+	width        int
+	height       int
+	pipes        int
+}
+
+func (supervisor *testSupervisor) Start(ctx context.Context, stableFor time.Duration) error {
+	listener, err := net.Listen("unix", supervisor.socketPath)
+	if err != nil {
+		return err
+	}
+	supervisor.listener = listener
+
+	go func() {
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			serveTestConnection(connection, supervisor.requests, supervisor.handleRequest)
+		}
+	}()
+
+	return nil
+}
+
+func (supervisor *testSupervisor) Stop(ctx context.Context) error {
+	return supervisor.listener.Close()
+}
+
+func (supervisor *testSupervisor) handleRequest(request testRequest) (any, string) {
+	switch {
+	case request.Command[0] == "set_property" && request.Command[1] == "options/demuxer-rawvideo-w":
+		supervisor.width = int(request.Command[2].(float64))
+	case request.Command[0] == "set_property" && request.Command[1] == "options/demuxer-rawvideo-h":
+		supervisor.height = int(request.Command[2].(float64))
+	case request.Command[0] == "loadfile":
+		readLimit := -1
+		if supervisor.pipes < len(supervisor.readLimits) {
+			readLimit = supervisor.readLimits[supervisor.pipes]
+		}
+
+		go supervisor.readVideo(request.Command[1].(string), supervisor.pipes, supervisor.width*supervisor.height*3, readLimit)
+		supervisor.pipes++
+	}
+
+	return nil, "success"
+}
+
+func (supervisor *testSupervisor) readVideo(path string, pipe int, frameSize int, readLimit int) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	for frames := 0; frames != readLimit; frames++ {
+		data := make([]byte, frameSize)
+		if _, err := io.ReadFull(file, data); err != nil {
+			return
+		}
+
+		supervisor.frames <- testFrame{pipe: pipe, data: data}
+	}
+
+	if !supervisor.closeAtLimit {
+		<-time.After(time.Second * 5)
+	}
+}
+
+func startTestController(t *testing.T, opts ...func(supervisor *testSupervisor)) (*Controller, *testSupervisor) {
+	// unix socket paths are limited in length, t.TempDir may be too long
+	socketPath := filepath.Join(os.TempDir(), fmt.Sprintf("mpv-test-%d.sock", time.Now().UnixNano()))
+
+	supervisor := &testSupervisor{
+		socketPath: socketPath,
+		requests:   make(chan testRequest, 64),
+		frames:     make(chan testFrame, 16),
+	}
+
+	for _, opt := range opts {
+		opt(supervisor)
+	}
+
+	controller, err := NewController(socketPath,
+		WithControllerArguments("--fs"),
+		WithControllerSupervisorProvider(func(specification process.Specification, restartPolicy process.RestartPolicy) process.Supervisor {
+			supervisor.specification = specification
+			return supervisor
+		}),
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	if !assert.NoError(t, controller.Start(t.Context())) {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		assert.NoError(t, controller.Stop(context.Background()))
+	})
+
+	return controller, supervisor
+}
+
+func TestControllerSpecification(t *testing.T) {
+	_, supervisor := startTestController(t)
+
+	assert.Equal(t, "/usr/local/bin/mpv", supervisor.specification.ExecutablePath)
+	assert.Contains(t, supervisor.specification.Arguments, "--input-ipc-server="+supervisor.socketPath)
+	assert.Contains(t, supervisor.specification.Arguments, "--demuxer=rawvideo")
+	assert.Equal(t, "--fs", supervisor.specification.Arguments[len(supervisor.specification.Arguments)-1])
+}
+
+func TestControllerLoadRawVideo(t *testing.T) {
+	controller, supervisor := startTestController(t)
+
+	err := controller.LoadRawVideo(t.Context(), peripheralSDK.DisplayMode{Width: 1280, Height: 720, RefreshRate: 30})
+	assert.NoError(t, err)
+
+	expected := [][]any{
+		{"set_property", "options/demuxer-rawvideo-w", float64(1280)},
+		{"set_property", "options/demuxer-rawvideo-h", float64(720)},
+		{"set_property", "options/demuxer-rawvideo-fps", float64(30)},
+		{"loadfile", supervisor.socketPath + ".video-1", "replace"},
+	}
+
+	for _, command := range expected {
+		assert.Equal(t, command, (<-supervisor.requests).Command)
+	}
+}
+
+// testFrameData returns frame of display mode with every byte set to value.
+func testFrameData(displayMode peripheralSDK.DisplayMode, value byte) *bytes.Reader {
+	return bytes.NewReader(bytes.Repeat([]byte{value}, int(displayMode.Width*displayMode.Height)*3))
+}
+
+func receiveTestFrame(t *testing.T, supervisor *testSupervisor) testFrame {
+	select {
+	case frame := <-supervisor.frames:
+		return frame
+	case <-time.After(time.Second * 5):
+		t.Fatal("no frame read by mpv")
+		return testFrame{}
+	}
+}
+
+func TestControllerKeepsFramesAlignedOnDisplayModeChange(t *testing.T) {
+	// the first pipe is read only partially, so frames of the previous display mode are left in it
+	controller, supervisor := startTestController(t, func(supervisor *testSupervisor) {
+		supervisor.readLimits = []int{1}
+	})
+
+	small := peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 30}
+	large := peripheralSDK.DisplayMode{Width: 5, Height: 3, RefreshRate: 60}
+
+	assert.ErrorIs(t, controller.WriteFrame(t.Context(), testFrameData(small, 1)), ErrRawVideoNotLoaded)
+
+	require.NoError(t, controller.LoadRawVideo(t.Context(), small))
+
+	for range 3 {
+		require.NoError(t, controller.WriteFrame(t.Context(), testFrameData(small, 1)))
+	}
+
+	assert.Equal(t, testFrame{pipe: 0, data: bytes.Repeat([]byte{1}, 4*2*3)}, receiveTestFrame(t, supervisor))
+
+	require.NoError(t, controller.LoadRawVideo(t.Context(), large))
+
+	for value := range byte(3) {
+		require.NoError(t, controller.WriteFrame(t.Context(), testFrameData(large, 2+value)))
+	}
+
+	for value := range byte(3) {
+		assert.Equal(t, testFrame{pipe: 1, data: bytes.Repeat([]byte{2 + value}, 5*3*3)}, receiveTestFrame(t, supervisor))
+	}
+}
+
+func TestControllerReloadsVideoWhenPipeIsClosed(t *testing.T) {
+	controller, supervisor := startTestController(t, func(supervisor *testSupervisor) {
+		supervisor.readLimits = []int{1}
+		supervisor.closeAtLimit = true
+	})
+
+	displayMode := peripheralSDK.DisplayMode{Width: 4, Height: 2, RefreshRate: 30}
+
+	require.NoError(t, controller.LoadRawVideo(t.Context(), displayMode))
+	require.NoError(t, controller.WriteFrame(t.Context(), testFrameData(displayMode, 1)))
+	assert.Equal(t, 0, receiveTestFrame(t, supervisor).pipe)
+
+	// mpv closed the pipe, e.g. it was restarted, writes fail until video is loaded again
+	assert.Eventually(t, func() bool {
+		return controller.WriteFrame(t.Context(), testFrameData(displayMode, 2)) != nil
+	}, time.Second*5, time.Millisecond*10)
+
+	require.NoError(t, controller.WriteFrame(t.Context(), testFrameData(displayMode, 3)))
+	assert.Equal(t, testFrame{pipe: 1, data: bytes.Repeat([]byte{3}, 4*2*3)}, receiveTestFrame(t, supervisor))
+}
+
+func TestControllerRestoresStateAfterReconnect(t *testing.T) {
+	controller, supervisor := startTestController(t)
+
+	assert.NoError(t, controller.SetTitle(t.Context(), "console"))
+	assert.Equal(t, []any{"set_property", "title", "console"}, (<-supervisor.requests).Command)
+
+	// mpv restarted by supervisor closes previous IPC connection
+	controller.ipcLock.Lock()
+	_ = controller.ipc.Close()
+	<-controller.ipc.Done()
+	controller.ipcLock.Unlock()
+
+	assert.NoError(t, controller.SetMessage(t.Context(), "NO SIGNAL"))
+	assert.Equal(t, []any{"set_property", "title", "console"}, (<-supervisor.requests).Command)
+	assert.Equal(t, []any{"set_property", "options/osd-msg1", "NO SIGNAL"}, (<-supervisor.requests).Command)
+	assert.Equal(t, []any{"set_property", "options/osd-msg1", "NO SIGNAL"}, (<-supervisor.requests).Command)
+}
+
+func TestNewControllerMissingSocketPath(t *testing.T) {
+	_, err := NewController("")
+	assert.ErrorIs(t, err, ErrMissingIPCSocketPath)
+}
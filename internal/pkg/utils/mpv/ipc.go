@@ -0,0 +1,254 @@
+package mpv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// Event is asynchronous notification sent by mpv, e.g. file-loaded or end-file. Data holds the whole message.
+type Event struct {
+	Name string
+	Data json.RawMessage
+}
+
+type EventHandler func(event Event)
+
+type IPCClientOptions struct {
+	eventHandler EventHandler
+	logger       *slog.Logger
+}
+
+type IPCClientOpt func(*IPCClientOptions)
+
+func defaultIPCClientOptions() IPCClientOptions {
+	return IPCClientOptions{
+		eventHandler: func(event Event) {},
+		logger:       slog.New(slog.DiscardHandler),
+	}
+}
+
+// WithIPCClientEventHandler sets handler called for every event, it is called from the read loop, so it must not
+// block.
+func WithIPCClientEventHandler(handler EventHandler) IPCClientOpt {
+	return func(options *IPCClientOptions) {
+		options.eventHandler = handler
+	}
+}
+
+func WithIPCClientLogger(logger *slog.Logger) IPCClientOpt {
+	return func(options *IPCClientOptions) {
+		options.logger = logger
+	}
+}
+
+// IPCClient is client of mpv JSON IPC. Commands may be executed concurrently, responses are matched by request id.
+type IPCClient struct {
+	connection net.Conn
+	encoder    *json.Encoder
+	writeLock  sync.Mutex
+
+	nextId      int64
+	pending     map[int64]chan message
+	pendingLock sync.Mutex
+
+	done    chan struct{}
+	doneErr error
+
+	eventHandler EventHandler
+	logger       *slog.Logger
+}
+
+type message struct {
+	RequestId *int64          `json:"request_id"`
+	Error     string          `json:"error"`
+	Data      json.RawMessage `json:"data"`
+
+	Event string `json:"event"`
+}
+
+type request struct {
+	Command   []any `json:"command"`
+	RequestId int64 `json:"request_id"`
+}
+
+// DialIPC connects to mpv IPC unix socket at path, i.e. --input-ipc-server of mpv.
+func DialIPC(ctx context.Context, path string, opts ...IPCClientOpt) (*IPCClient, error) {
+	var dialer net.Dialer
+
+	connection, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+
+	return NewIPCClient(connection, opts...), nil
+}
+
+// NewIPCClient starts reading responses and events from connection in background.
+func NewIPCClient(connection net.Conn, opts ...IPCClientOpt) *IPCClient {
+	options := defaultIPCClientOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	client := &IPCClient{
+		connection: connection,
+		encoder:    json.NewEncoder(connection),
+		pending:    make(map[int64]chan message),
+		done:       make(chan struct{}),
+
+		eventHandler: options.eventHandler,
+		logger:       options.logger,
+	}
+
+	go client.readLoop(json.NewDecoder(connection))
+
+	return client
+}
+
+// Command runs mpv input command, e.g. Command(ctx, nil, "loadfile", "-", "replace"), and decodes its data into
+// result. Result may be nil. Command errors reported by mpv are returned as *Error.
+func (client *IPCClient) Command(ctx context.Context, result any, command ...any) error {
+	if len(command) == 0 {
+		return ErrEmptyCommand
+	}
+
+	if err := client.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrClosed, err)
+	}
+
+	responseChannel := make(chan message, 1)
+
+	client.pendingLock.Lock()
+	client.nextId++
+	id := client.nextId
+	client.pending[id] = responseChannel
+	client.pendingLock.Unlock()
+
+	defer func() {
+		client.pendingLock.Lock()
+		delete(client.pending, id)
+		client.pendingLock.Unlock()
+	}()
+
+	client.writeLock.Lock()
+	err := client.encoder.Encode(request{Command: command, RequestId: id})
+	client.writeLock.Unlock()
+	if err != nil {
+		return fmt.Errorf("write command: %w", err)
+	}
+
+	var response message
+	select {
+	case response = <-responseChannel:
+	case <-client.done:
+		return fmt.Errorf("%w: %w", ErrClosed, client.doneErr)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if response.Error != "success" {
+		return &Error{Command: fmt.Sprint(command[0]), Message: response.Error}
+	}
+
+	if result == nil || len(response.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(response.Data, result); err != nil {
+		return fmt.Errorf("decode %v result: %w", command[0], err)
+	}
+
+	return nil
+}
+
+func (client *IPCClient) SetProperty(ctx context.Context, name string, value any) error {
+	return client.Command(ctx, nil, "set_property", name, value)
+}
+
+func (client *IPCClient) GetProperty(ctx context.Context, name string, result any) error {
+	return client.Command(ctx, result, "get_property", name)
+}
+
+// ShowText shows text on OSD for duration.
+func (client *IPCClient) ShowText(ctx context.Context, text string, duration time.Duration) error {
+	return client.Command(ctx, nil, "show-text", text, duration.Milliseconds())
+}
+
+// Done returns channel closed when connection is lost.
+func (client *IPCClient) Done() <-chan struct{} {
+	return client.done
+}
+
+// Err returns reason of connection loss, nil until Done is closed.
+func (client *IPCClient) Err() error {
+	select {
+	case <-client.done:
+		return client.doneErr
+	default:
+		return nil
+	}
+}
+
+func (client *IPCClient) Close() error {
+	return client.connection.Close()
+}
+
+func (client *IPCClient) readLoop(decoder *json.Decoder) {
+	defer close(client.done)
+
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			client.doneErr = err
+			return
+		}
+
+		var received message
+		if err := json.Unmarshal(raw, &received); err != nil {
+			client.logger.Warn("Failed to decode mpv message.", slog.String("error", err.Error()))
+			continue
+		}
+
+		if received.Event != "" {
+			client.eventHandler(Event{
+				Name: received.Event,
+				Data: raw,
+			})
+			continue
+		}
+
+		if received.RequestId == nil {
+			client.logger.Warn("Received mpv response without request id.")
+			continue
+		}
+
+		client.pendingLock.Lock()
+		responseChannel, found := client.pending[*received.RequestId]
+		client.pendingLock.Unlock()
+
+		if found {
+			responseChannel <- received
+		}
+	}
+}
+
+// Error is error reported by mpv in response to command, e.g. "property not found".
+type Error struct {
+	Command string
+	Message string
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("mpv %s: %s", err.Command, err.Message)
+}
+
+var (
+	ErrClosed       = errors.New("mpv ipc connection closed")
+	ErrEmptyCommand = errors.New("mpv command is empty")
+)
@@ -0,0 +1,113 @@
+package mpv
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequest struct {
+	Command   []any `json:"command"`
+	RequestId int64 `json:"request_id"`
+}
+
+// serveTestConnection serves mpv IPC on connection, handler returns data and error string of every command.
+// Requests are sent to the requests channel.
+func serveTestConnection(connection net.Conn, requests chan<- testRequest, handler func(request testRequest) (any, string)) *json.Encoder {
+	encoder := json.NewEncoder(connection)
+
+	go func() {
+		decoder := json.NewDecoder(connection)
+		for {
+			var request testRequest
+			if err := decoder.Decode(&request); err != nil {
+				return
+			}
+
+			requests <- request
+
+			data, errorMessage := handler(request)
+			_ = encoder.Encode(map[string]any{"error": errorMessage, "data": data, "request_id": request.RequestId})
+		}
+	}()
+
+	return encoder
+}
+
+func TestIPCClientCommand(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	requests := make(chan testRequest, 16)
+	serveTestConnection(serverConn, requests, func(request testRequest) (any, string) {
+		if request.Command[0] == "get_property" && request.Command[1] == "fullscreen" {
+			return true, "success"
+		}
+		if request.Command[0] == "set_property" {
+			return nil, "success"
+		}
+		return nil, "property not found"
+	})
+	defer func() {
+		_ = serverConn.Close()
+	}()
+
+	client := NewIPCClient(clientConn)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	var fullscreen bool
+	assert.NoError(t, client.GetProperty(t.Context(), "fullscreen", &fullscreen))
+	assert.True(t, fullscreen)
+	assert.Equal(t, []any{"get_property", "fullscreen"}, (<-requests).Command)
+
+	assert.NoError(t, client.SetProperty(t.Context(), "title", "console"))
+	assert.Equal(t, []any{"set_property", "title", "console"}, (<-requests).Command)
+
+	err := client.GetProperty(t.Context(), "missing", nil)
+	var mpvErr *Error
+	if assert.ErrorAs(t, err, &mpvErr) {
+		assert.Equal(t, "get_property", mpvErr.Command)
+		assert.Equal(t, "property not found", mpvErr.Message)
+	}
+
+	assert.ErrorIs(t, client.Command(t.Context(), nil), ErrEmptyCommand)
+}
+
+func TestIPCClientEvents(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer func() {
+		_ = serverConn.Close()
+	}()
+
+	events := make(chan Event, 1)
+	client := NewIPCClient(clientConn, WithIPCClientEventHandler(func(event Event) {
+		events <- event
+	}))
+	defer func() {
+		_ = client.Close()
+	}()
+
+	_ = json.NewEncoder(serverConn).Encode(map[string]any{"event": "end-file", "reason": "eof"})
+
+	select {
+	case event := <-events:
+		assert.Equal(t, "end-file", event.Name)
+		assert.JSONEq(t, `{"event":"end-file","reason":"eof"}`, string(event.Data))
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not received")
+	}
+}
+
+func TestIPCClientClosed(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+
+	client := NewIPCClient(clientConn)
+	_ = serverConn.Close()
+
+	<-client.Done()
+
+	assert.ErrorIs(t, client.SetProperty(t.Context(), "pause", true), ErrClosed)
+}
@@ -0,0 +1,73 @@
+package mpv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const videoPipeOpenRetryPeriod = 10 * time.Millisecond
+
+// videoPipe is a named pipe mpv plays raw video from. Every display mode is played from a new pipe, so bytes of frames
+// written before the display mode changed are never read as frames of the new display mode.
+type videoPipe struct {
+	path string
+	file *os.File
+}
+
+func createVideoPipe(path string) (*videoPipe, error) {
+	if err := unix.Mkfifo(path, 0o600); err != nil {
+		return nil, fmt.Errorf("create fifo %s: %w", path, err)
+	}
+
+	return &videoPipe{path: path}, nil
+}
+
+// open waits until mpv opens the pipe for reading and opens it for writing. Writes fail once mpv closes the pipe.
+func (pipe *videoPipe) open(ctx context.Context) error {
+	for {
+		// non-blocking open of write end fails until there is a reader, the file is then written through poller
+		file, err := os.OpenFile(pipe.path, os.O_WRONLY|unix.O_NONBLOCK, 0)
+		if err == nil {
+			pipe.file = file
+			return nil
+		}
+
+		if !errors.Is(err, unix.ENXIO) {
+			return fmt.Errorf("open fifo %s: %w", pipe.path, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for mpv to open fifo: %w", ctx.Err())
+		case <-time.After(videoPipeOpenRetryPeriod):
+		}
+	}
+}
+
+// write writes the whole frame, the pipe is not usable anymore when it fails.
+func (pipe *videoPipe) write(frame io.WriterTo, timeout time.Duration) error {
+	if err := pipe.file.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return fmt.Errorf("set write deadline: %w", err)
+	}
+
+	if _, err := frame.WriteTo(pipe.file); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pipe *videoPipe) close() error {
+	var err error
+	if pipe.file != nil {
+		err = pipe.file.Close()
+	}
+
+	return errors.Join(err, os.Remove(pipe.path))
+}
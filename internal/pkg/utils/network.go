@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+)
+
+// IsLoopbackAddress reports whether host of HOST:PORT address is loopback, see IsLoopbackHost.
+func IsLoopbackAddress(address string) (bool, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false, fmt.Errorf("parse address %q: %w", address, err)
+	}
+
+	return IsLoopbackHost(host), nil
+}
+
+// IsLoopbackHost reports whether host is localhost or loopback IP address. Empty host, which listens on all
+// interfaces, is not loopback.
+func IsLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// IsLocalNetworkHost reports whether host is loopback, private, link-local or multicast address, so traffic sent to
+// it stays in the local network.
+func IsLocalNetworkHost(host string) bool {
+	if IsLoopbackHost(host) {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && (ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsMulticast())
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsLoopbackAddress(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:8080", "[::1]:8080", "localhost:8080"} {
+		loopback, err := IsLoopbackAddress(address)
+		require.NoError(t, err, address)
+		assert.True(t, loopback, address)
+	}
+
+	for _, address := range []string{"0.0.0.0:8080", ":8080", "192.168.1.10:8080", "example.com:8080"} {
+		loopback, err := IsLoopbackAddress(address)
+		require.NoError(t, err, address)
+		assert.False(t, loopback, address)
+	}
+
+	_, err := IsLoopbackAddress("127.0.0.1")
+	assert.Error(t, err)
+}
+
+func TestIsLocalNetworkHost(t *testing.T) {
+	for _, host := range []string{"localhost", "127.0.0.1", "::1", "10.0.0.1", "192.168.1.10", "169.254.1.1", "239.0.0.1"} {
+		assert.True(t, IsLocalNetworkHost(host), host)
+	}
+
+	for _, host := range []string{"", "8.8.8.8", "example.com"} {
+		assert.False(t, IsLocalNetworkHost(host), host)
+	}
+}
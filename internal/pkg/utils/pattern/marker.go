@@ -0,0 +1,177 @@
+package pattern
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// Marker is a machine-readable block drawn in the top-left corner of every generated frame. It is a grid of
+// MarkerColumns x MarkerRows square cells, where white cell encodes bit 1 and black cell encodes bit 0:
+//
+//	row 0     sync pattern, alternating white and black cells starting with white
+//	rows 1-4  frame number, 64 bits, most significant bit first
+//	rows 5-6  CRC-32 (IEEE) of all frame pixels outside of the marker block, row by row
+//
+// Cells are sampled in their centre, so the marker survives moderate lossy compression.
+const (
+	MarkerColumns = 16
+	MarkerRows    = 7
+
+	markerFrameNumberRow = 1
+	markerChecksumRow    = 5
+)
+
+// Marker holds values decoded from marker block.
+type Marker struct {
+	FrameNumber uint64
+	Checksum    uint32
+}
+
+// MarkerSize returns marker block width and height in pixels for the given cell size.
+func MarkerSize(cellSize int) (int, int) {
+	return MarkerColumns * cellSize, MarkerRows * cellSize
+}
+
+// DrawMarker draws marker block with frame number and checksum of the remaining frame pixels. It must be called
+// after the rest of the frame is rendered.
+func DrawMarker(pixels []byte, width int, height int, cellSize int, frameNumber uint64) error {
+	if err := validateMarkerGeometry(pixels, width, height, cellSize); err != nil {
+		return err
+	}
+
+	checksum := Checksum(pixels, width, height, cellSize)
+
+	bits := make([]bool, 0, MarkerColumns*MarkerRows)
+	for column := 0; column < MarkerColumns; column++ {
+		bits = append(bits, column%2 == 0)
+	}
+	bits = appendBits(bits, frameNumber, 64)
+	bits = appendBits(bits, uint64(checksum), 32)
+
+	for i, bit := range bits {
+		var value byte
+		if bit {
+			value = 0xff
+		}
+
+		cellX := (i % MarkerColumns) * cellSize
+		cellY := (i / MarkerColumns) * cellSize
+
+		for y := cellY; y < cellY+cellSize; y++ {
+			row := pixels[(y*width+cellX)*3 : (y*width+cellX+cellSize)*3]
+			for j := range row {
+				row[j] = value
+			}
+		}
+	}
+
+	return nil
+}
+
+// DecodeMarker reads marker block from frame pixels.
+func DecodeMarker(pixels []byte, width int, height int, cellSize int) (*Marker, error) {
+	if err := validateMarkerGeometry(pixels, width, height, cellSize); err != nil {
+		return nil, err
+	}
+
+	readBit := func(index int) bool {
+		x := (index%MarkerColumns)*cellSize + cellSize/2
+		y := (index/MarkerColumns)*cellSize + cellSize/2
+		offset := (y*width + x) * 3
+
+		luminance := (int(pixels[offset]) + int(pixels[offset+1]) + int(pixels[offset+2])) / 3
+
+		return luminance >= 128
+	}
+
+	for column := 0; column < MarkerColumns; column++ {
+		if readBit(column) != (column%2 == 0) {
+			return nil, ErrMarkerNotFound
+		}
+	}
+
+	readValue := func(startIndex int, bitCount int) uint64 {
+		var value uint64
+		for i := 0; i < bitCount; i++ {
+			value <<= 1
+			if readBit(startIndex + i) {
+				value |= 1
+			}
+		}
+		return value
+	}
+
+	return &Marker{
+		FrameNumber: readValue(markerFrameNumberRow*MarkerColumns, 64),
+		Checksum:    uint32(readValue(markerChecksumRow*MarkerColumns, 32)),
+	}, nil
+}
+
+// Checksum computes CRC-32 of frame pixels outside of the marker block.
+func Checksum(pixels []byte, width int, height int, cellSize int) uint32 {
+	markerWidth, markerHeight := MarkerSize(cellSize)
+
+	hash := crc32.NewIEEE()
+	for y := 0; y < height; y++ {
+		row := pixels[y*width*3 : (y+1)*width*3]
+		if y < markerHeight {
+			row = row[markerWidth*3:]
+		}
+		_, _ = hash.Write(row)
+	}
+
+	return hash.Sum32()
+}
+
+// VerifyMarker decodes marker block and checks that checksum matches frame pixels. Lossless pipelines are expected
+// to pass, lossy pipelines should only rely on DecodeMarker.
+func VerifyMarker(pixels []byte, width int, height int, cellSize int) (*Marker, error) {
+	marker, err := DecodeMarker(pixels, width, height, cellSize)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := Checksum(pixels, width, height, cellSize)
+	if checksum != marker.Checksum {
+		return marker, fmt.Errorf("%w: marker %08x, frame %08x", ErrChecksumMismatch, marker.Checksum, checksum)
+	}
+
+	return marker, nil
+}
+
+func validateMarkerGeometry(pixels []byte, width int, height int, cellSize int) error {
+	if cellSize <= 0 {
+		return fmt.Errorf("%w: cell size must be greater than zero", ErrMarkerDoesNotFit)
+	}
+
+	markerWidth, markerHeight := MarkerSize(cellSize)
+	if width < markerWidth || height < markerHeight {
+		return fmt.Errorf("%w: %dx%d marker in %dx%d frame", ErrMarkerDoesNotFit, markerWidth, markerHeight, width, height)
+	}
+
+	if len(pixels) != width*height*3 {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidFrameSize, width*height*3, len(pixels))
+	}
+
+	return nil
+}
+
+func appendBits(bits []bool, value uint64, bitCount int) []bool {
+	var encoded [8]byte
+	binary.BigEndian.PutUint64(encoded[:], value<<(64-bitCount))
+
+	for i := 0; i < bitCount; i++ {
+		bits = append(bits, encoded[i/8]&(0x80>>(i%8)) != 0)
+	}
+
+	return bits
+}
+
+var (
+	ErrMarkerNotFound   = errors.New("marker not found")
+	ErrMarkerDoesNotFit = errors.New("marker does not fit in frame")
+	ErrChecksumMismatch = errors.New("frame checksum mismatch")
+	ErrInvalidFrameSize = errors.New("invalid frame size")
+)
@@ -0,0 +1,65 @@
+package pattern
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFrame(width int, height int) []byte {
+	pixels := make([]byte, width*height*3)
+	for i := range pixels {
+		pixels[i] = byte(i % 251)
+	}
+
+	return pixels
+}
+
+func TestMarkerRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	width, height := 160, 90
+	pixels := newTestFrame(width, height)
+
+	for _, frameNumber := range []uint64{0, 1, 0xdeadbeef, 1<<64 - 1} {
+		assert.NoError(t, DrawMarker(pixels, width, height, 4, frameNumber))
+
+		marker, err := VerifyMarker(pixels, width, height, 4)
+		if assert.NoError(t, err) {
+			assert.Equal(t, frameNumber, marker.FrameNumber)
+			assert.Equal(t, Checksum(pixels, width, height, 4), marker.Checksum)
+		}
+	}
+}
+
+func TestVerifyMarkerDetectsPixelMismatch(t *testing.T) {
+	t.Parallel()
+
+	width, height := 80, 40
+	pixels := newTestFrame(width, height)
+
+	assert.NoError(t, DrawMarker(pixels, width, height, 2, 42))
+
+	pixels[len(pixels)-1] ^= 0xff
+
+	marker, err := VerifyMarker(pixels, width, height, 2)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	if assert.NotNil(t, marker) {
+		assert.Equal(t, uint64(42), marker.FrameNumber)
+	}
+}
+
+func TestDecodeMarkerRejectsFrameWithoutMarker(t *testing.T) {
+	t.Parallel()
+
+	width, height := 64, 28
+
+	_, err := DecodeMarker(make([]byte, width*height*3), width, height, 4)
+	assert.ErrorIs(t, err, ErrMarkerNotFound)
+
+	_, err = DecodeMarker(make([]byte, width*height*3), width, height, 8)
+	assert.ErrorIs(t, err, ErrMarkerDoesNotFit)
+
+	_, err = DecodeMarker(make([]byte, 3), width, height, 4)
+	assert.ErrorIs(t, err, ErrInvalidFrameSize)
+}
@@ -0,0 +1,304 @@
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+// Kind selects generated test pattern.
+type Kind string
+
+const (
+	KindUnknown     Kind = ""
+	KindSMPTEBars   Kind = "smpte-bars"
+	KindGradient    Kind = "gradient"
+	KindMovingBoxes Kind = "moving-boxes"
+)
+
+func (kind Kind) String() string {
+	return string(kind)
+}
+
+func (kind Kind) Valid() error {
+	switch kind {
+	case KindSMPTEBars, KindGradient, KindMovingBoxes:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedKind, string(kind))
+	}
+}
+
+type GeneratorOptions struct {
+	seed           uint64
+	boxCount       int
+	markerCellSize int
+}
+
+type GeneratorOpt func(*GeneratorOptions)
+
+func defaultGeneratorOptions() GeneratorOptions {
+	return GeneratorOptions{
+		boxCount:       4,
+		markerCellSize: 8,
+	}
+}
+
+// WithGeneratorSeed sets seed used to derive colors, positions and velocities. Frames are identical for the same
+// seed, kind, display mode and frame number.
+func WithGeneratorSeed(seed uint64) GeneratorOpt {
+	return func(options *GeneratorOptions) {
+		options.seed = seed
+	}
+}
+
+func WithGeneratorBoxCount(boxCount int) GeneratorOpt {
+	return func(options *GeneratorOptions) {
+		options.boxCount = boxCount
+	}
+}
+
+// WithGeneratorMarkerCellSize sets size of a single marker cell in pixels. Zero disables the marker.
+func WithGeneratorMarkerCellSize(cellSize int) GeneratorOpt {
+	return func(options *GeneratorOptions) {
+		options.markerCellSize = cellSize
+	}
+}
+
+type box struct {
+	x, y          int
+	width, height int
+	velocityX     int
+	velocityY     int
+	color         [3]byte
+}
+
+// Generator renders deterministic RGB24 test pattern frames.
+type Generator struct {
+	kind        Kind
+	displayMode peripheralSDK.DisplayMode
+	options     GeneratorOptions
+
+	background [3]byte
+	hueOffset  int
+	boxes      []box
+}
+
+func NewGenerator(kind Kind, displayMode peripheralSDK.DisplayMode, opts ...GeneratorOpt) (*Generator, error) {
+	if err := kind.Valid(); err != nil {
+		return nil, err
+	}
+
+	if err := displayMode.Valid(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGeneratorConfiguration, err)
+	}
+
+	options := defaultGeneratorOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.boxCount < 0 || options.markerCellSize < 0 {
+		return nil, fmt.Errorf("%w: box count and marker cell size must not be negative", ErrInvalidGeneratorConfiguration)
+	}
+
+	if options.markerCellSize > 0 {
+		markerWidth, markerHeight := MarkerSize(options.markerCellSize)
+		if int(displayMode.Width) < markerWidth || int(displayMode.Height) < markerHeight {
+			return nil, fmt.Errorf("%w: %dx%d marker in %s", ErrMarkerDoesNotFit, markerWidth, markerHeight, displayMode.String())
+		}
+	}
+
+	random := rand.New(rand.NewPCG(options.seed, options.seed^0x9e3779b97f4a7c15))
+
+	generator := &Generator{
+		kind:        kind,
+		displayMode: displayMode,
+		options:     options,
+		background:  [3]byte{byte(random.IntN(48)), byte(random.IntN(48)), byte(random.IntN(48))},
+		hueOffset:   random.IntN(256),
+	}
+
+	width := int(displayMode.Width)
+	height := int(displayMode.Height)
+
+	for i := 0; i < options.boxCount; i++ {
+		boxWidth := max(1, width/8+random.IntN(max(1, width/8)))
+		boxHeight := max(1, height/8+random.IntN(max(1, height/8)))
+
+		generator.boxes = append(generator.boxes, box{
+			x:         random.IntN(max(1, width-boxWidth)),
+			y:         random.IntN(max(1, height-boxHeight)),
+			width:     min(boxWidth, width),
+			height:    min(boxHeight, height),
+			velocityX: randomVelocity(random, width),
+			velocityY: randomVelocity(random, height),
+			color:     [3]byte{byte(64 + random.IntN(192)), byte(64 + random.IntN(192)), byte(64 + random.IntN(192))},
+		})
+	}
+
+	return generator, nil
+}
+
+func (generator *Generator) GetDisplayMode() peripheralSDK.DisplayMode {
+	return generator.displayMode
+}
+
+// GetMarkerCellSize returns marker cell size, zero when marker is disabled.
+func (generator *Generator) GetMarkerCellSize() int {
+	return generator.options.markerCellSize
+}
+
+// Render draws frame with the given number. Pixels are appended to dst, which may be nil.
+func (generator *Generator) Render(dst []byte, frameNumber uint64) ([]byte, error) {
+	width := int(generator.displayMode.Width)
+	height := int(generator.displayMode.Height)
+	size := width * height * 3
+
+	pixels := dst[:0]
+	if cap(pixels) < size {
+		pixels = make([]byte, size)
+	}
+	pixels = pixels[:size]
+
+	switch generator.kind {
+	case KindSMPTEBars:
+		generator.renderSMPTEBars(pixels, width, height)
+	case KindGradient:
+		generator.renderGradient(pixels, width, height, frameNumber)
+	case KindMovingBoxes:
+		generator.renderMovingBoxes(pixels, width, height, frameNumber)
+	}
+
+	if generator.options.markerCellSize > 0 {
+		if err := DrawMarker(pixels, width, height, generator.options.markerCellSize, frameNumber); err != nil {
+			return nil, fmt.Errorf("draw marker: %w", err)
+		}
+	}
+
+	return pixels, nil
+}
+
+var (
+	smpteTopBars = [][3]byte{
+		{191, 191, 191}, {191, 191, 0}, {0, 191, 191}, {0, 191, 0}, {191, 0, 191}, {191, 0, 0}, {0, 0, 191},
+	}
+	smpteMiddleBars = [][3]byte{
+		{0, 0, 191}, {19, 19, 19}, {191, 0, 191}, {19, 19, 19}, {0, 191, 191}, {19, 19, 19}, {191, 191, 191},
+	}
+	smpteBlack = [3]byte{19, 19, 19}
+)
+
+// renderSMPTEBars draws SMPTE ECR 1-1978 color bars: seven 75% bars, reversed castellations and the bottom row
+// with -I, white, +Q and PLUGE.
+func (generator *Generator) renderSMPTEBars(pixels []byte, width int, height int) {
+	topHeight := height * 2 / 3
+	middleHeight := height / 12
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var color [3]byte
+
+			switch {
+			case y < topHeight:
+				color = smpteTopBars[x*7/width]
+			case y < topHeight+middleHeight:
+				color = smpteMiddleBars[x*7/width]
+			default:
+				color = smpteBottomColor(x * 28 / width)
+			}
+
+			setPixel(pixels, width, x, y, color)
+		}
+	}
+}
+
+// smpteBottomColor returns color of the bottom row for position expressed in quarters of a bar width.
+func smpteBottomColor(quarter int) [3]byte {
+	switch {
+	case quarter < 5:
+		return [3]byte{0, 33, 76}
+	case quarter < 10:
+		return [3]byte{255, 255, 255}
+	case quarter < 15:
+		return [3]byte{50, 0, 106}
+	case quarter < 20:
+		return smpteBlack
+	case quarter < 21:
+		return [3]byte{9, 9, 9}
+	case quarter < 23:
+		return smpteBlack
+	case quarter < 24:
+		return [3]byte{29, 29, 29}
+	default:
+		return smpteBlack
+	}
+}
+
+// renderGradient draws horizontal red and vertical green ramps with blue channel cycling over frames.
+func (generator *Generator) renderGradient(pixels []byte, width int, height int, frameNumber uint64) {
+	blue := byte((uint64(generator.hueOffset) + frameNumber) % 256)
+
+	for y := 0; y < height; y++ {
+		green := byte(y * 255 / max(1, height-1))
+		for x := 0; x < width; x++ {
+			red := byte(x * 255 / max(1, width-1))
+			setPixel(pixels, width, x, y, [3]byte{red, green, blue})
+		}
+	}
+}
+
+// renderMovingBoxes draws boxes bouncing off frame edges. Position is computed from the frame number, so any frame
+// can be rendered without rendering previous ones.
+func (generator *Generator) renderMovingBoxes(pixels []byte, width int, height int, frameNumber uint64) {
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			setPixel(pixels, width, x, y, generator.background)
+		}
+	}
+
+	for _, movingBox := range generator.boxes {
+		boxX := bounce(movingBox.x, movingBox.velocityX, frameNumber, width-movingBox.width)
+		boxY := bounce(movingBox.y, movingBox.velocityY, frameNumber, height-movingBox.height)
+
+		for y := boxY; y < boxY+movingBox.height; y++ {
+			for x := boxX; x < boxX+movingBox.width; x++ {
+				setPixel(pixels, width, x, y, movingBox.color)
+			}
+		}
+	}
+}
+
+// bounce returns position after frameNumber steps of given velocity, reflected within [0, limit].
+func bounce(start int, velocity int, frameNumber uint64, limit int) int {
+	if limit <= 0 {
+		return 0
+	}
+
+	period := uint64(2 * limit)
+	distance := (uint64(start) + uint64(velocity)*(frameNumber%period)) % period
+
+	if distance > uint64(limit) {
+		return int(period - distance)
+	}
+
+	return int(distance)
+}
+
+func randomVelocity(random *rand.Rand, size int) int {
+	return 1 + random.IntN(max(1, size/64))
+}
+
+func setPixel(pixels []byte, width int, x int, y int, color [3]byte) {
+	offset := (y*width + x) * 3
+	pixels[offset] = color[0]
+	pixels[offset+1] = color[1]
+	pixels[offset+2] = color[2]
+}
+
+var (
+	ErrUnsupportedKind               = errors.New("unsupported pattern kind")
+	ErrInvalidGeneratorConfiguration = errors.New("invalid generator configuration")
+)
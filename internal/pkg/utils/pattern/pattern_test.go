@@ -0,0 +1,112 @@
+package pattern
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+var testDisplayMode = peripheralSDK.DisplayMode{Width: 320, Height: 180, RefreshRate: 30}
+
+func TestGeneratorIsDeterministic(t *testing.T) {
+	t.Parallel()
+
+	for _, kind := range []Kind{KindSMPTEBars, KindGradient, KindMovingBoxes} {
+		t.Run(kind.String(), func(t *testing.T) {
+			first, err := NewGenerator(kind, testDisplayMode, WithGeneratorSeed(7))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			second, err := NewGenerator(kind, testDisplayMode, WithGeneratorSeed(7))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			firstFrame, err := first.Render(nil, 123)
+			assert.NoError(t, err)
+
+			secondFrame, err := second.Render(nil, 123)
+			assert.NoError(t, err)
+
+			assert.Equal(t, firstFrame, secondFrame)
+
+			marker, err := VerifyMarker(firstFrame, int(testDisplayMode.Width), int(testDisplayMode.Height), first.GetMarkerCellSize())
+			if assert.NoError(t, err) {
+				assert.Equal(t, uint64(123), marker.FrameNumber)
+			}
+		})
+	}
+}
+
+func TestGeneratorMovingBoxesDependOnSeedAndFrame(t *testing.T) {
+	t.Parallel()
+
+	generator, err := NewGenerator(KindMovingBoxes, testDisplayMode, WithGeneratorSeed(1), WithGeneratorMarkerCellSize(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	otherGenerator, err := NewGenerator(KindMovingBoxes, testDisplayMode, WithGeneratorSeed(2), WithGeneratorMarkerCellSize(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	firstFrame, _ := generator.Render(nil, 0)
+	nextFrame, _ := generator.Render(nil, 1)
+	otherFrame, _ := otherGenerator.Render(nil, 0)
+
+	assert.NotEqual(t, firstFrame, nextFrame)
+	assert.NotEqual(t, firstFrame, otherFrame)
+}
+
+func TestGeneratorSMPTEBars(t *testing.T) {
+	t.Parallel()
+
+	generator, err := NewGenerator(KindSMPTEBars, peripheralSDK.DisplayMode{Width: 700, Height: 120, RefreshRate: 30}, WithGeneratorMarkerCellSize(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pixels, err := generator.Render(nil, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pixelAt := func(x int, y int) []byte {
+		offset := (y*700 + x) * 3
+		return pixels[offset : offset+3]
+	}
+
+	assert.Equal(t, []byte{191, 191, 191}, pixelAt(50, 10))
+	assert.Equal(t, []byte{191, 191, 0}, pixelAt(150, 10))
+	assert.Equal(t, []byte{0, 0, 191}, pixelAt(650, 10))
+	assert.Equal(t, []byte{0, 0, 191}, pixelAt(50, 85))
+	assert.Equal(t, []byte{255, 255, 255}, pixelAt(150, 110))
+}
+
+func TestNewGeneratorRejectsInvalidConfiguration(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewGenerator("noise", testDisplayMode)
+	assert.ErrorIs(t, err, ErrUnsupportedKind)
+
+	_, err = NewGenerator(KindGradient, peripheralSDK.DisplayMode{})
+	assert.ErrorIs(t, err, ErrInvalidGeneratorConfiguration)
+
+	_, err = NewGenerator(KindGradient, peripheralSDK.DisplayMode{Width: 64, Height: 32, RefreshRate: 30})
+	assert.ErrorIs(t, err, ErrMarkerDoesNotFit)
+
+	_, err = NewGenerator(KindGradient, peripheralSDK.DisplayMode{Width: 64, Height: 32, RefreshRate: 30}, WithGeneratorMarkerCellSize(0))
+	assert.NoError(t, err)
+}
+
+func TestBounce(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, bounce(0, 3, 0, 10))
+	assert.Equal(t, 9, bounce(0, 3, 3, 10))
+	assert.Equal(t, 8, bounce(0, 3, 4, 10))
+	assert.Equal(t, 0, bounce(5, 3, 100, 0))
+}
@@ -0,0 +1,233 @@
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type VerifierOptions struct {
+	markerCellSize      int
+	expectedKind        Kind
+	expectedDisplayMode *peripheralSDK.DisplayMode
+	generatorOpts       []GeneratorOpt
+	tolerance           uint8
+	clock               func() time.Time
+}
+
+type VerifierOpt func(*VerifierOptions)
+
+func defaultVerifierOptions() VerifierOptions {
+	return VerifierOptions{
+		markerCellSize: 8,
+		clock:          time.Now,
+	}
+}
+
+func WithVerifierMarkerCellSize(cellSize int) VerifierOpt {
+	return func(options *VerifierOptions) {
+		options.markerCellSize = cellSize
+	}
+}
+
+// WithVerifierExpectedPattern enables pixel comparison against frames rendered by Generator with the given kind and
+// options. Generator options must match those of the source, marker cell size is taken from the verifier.
+func WithVerifierExpectedPattern(kind Kind, generatorOpts ...GeneratorOpt) VerifierOpt {
+	return func(options *VerifierOptions) {
+		options.expectedKind = kind
+		options.generatorOpts = generatorOpts
+	}
+}
+
+// WithVerifierExpectedDisplayMode makes frames of different size counted as display mode mismatch.
+func WithVerifierExpectedDisplayMode(displayMode peripheralSDK.DisplayMode) VerifierOpt {
+	return func(options *VerifierOptions) {
+		options.expectedDisplayMode = &displayMode
+	}
+}
+
+// WithVerifierTolerance sets maximum difference of a single color channel which is not counted as pixel mismatch.
+func WithVerifierTolerance(tolerance uint8) VerifierOpt {
+	return func(options *VerifierOptions) {
+		options.tolerance = tolerance
+	}
+}
+
+func WithVerifierClock(clock func() time.Time) VerifierOpt {
+	return func(options *VerifierOptions) {
+		options.clock = clock
+	}
+}
+
+// Verifier checks frames produced by Generator after they passed through a display pipeline. Frame numbers decoded
+// from markers reveal dropped, duplicated and reordered frames, while checksum and optional comparison with the
+// expected pattern reveal corrupted pixels.
+type Verifier struct {
+	options VerifierOptions
+
+	report        peripheralSDK.DisplayVerificationReport
+	lastFrameSeen bool
+
+	generator      *Generator
+	expectedPixels []byte
+
+	lock sync.Mutex
+}
+
+func NewVerifier(opts ...VerifierOpt) (*Verifier, error) {
+	options := defaultVerifierOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.markerCellSize <= 0 {
+		return nil, fmt.Errorf("%w: marker cell size must be greater than zero", ErrInvalidVerifierConfiguration)
+	}
+
+	if options.expectedKind != KindUnknown {
+		if err := options.expectedKind.Valid(); err != nil {
+			return nil, err
+		}
+	}
+
+	verifier := &Verifier{
+		options: options,
+	}
+	verifier.report.StartedAt = options.clock()
+
+	return verifier, nil
+}
+
+// Observe verifies single RGB24 frame and updates the report.
+func (verifier *Verifier) Observe(pixels []byte, displayMode peripheralSDK.DisplayMode) {
+	verifier.lock.Lock()
+	defer verifier.lock.Unlock()
+
+	report := &verifier.report
+	report.FramesVerified++
+
+	if verifier.options.expectedDisplayMode != nil &&
+		(displayMode.Width != verifier.options.expectedDisplayMode.Width || displayMode.Height != verifier.options.expectedDisplayMode.Height) {
+		report.DisplayModeMismatchFrames++
+		return
+	}
+
+	width := int(displayMode.Width)
+	height := int(displayMode.Height)
+
+	marker, err := VerifyMarker(pixels, width, height, verifier.options.markerCellSize)
+	if errors.Is(err, ErrChecksumMismatch) {
+		report.ChecksumMismatchFrames++
+	} else if err != nil {
+		report.MissingMarkerFrames++
+		return
+	}
+
+	verifier.observeFrameNumber(marker.FrameNumber)
+
+	if verifier.options.expectedKind != KindUnknown {
+		verifier.comparePixels(pixels, displayMode, marker.FrameNumber)
+	}
+}
+
+func (verifier *Verifier) observeFrameNumber(frameNumber uint64) {
+	report := &verifier.report
+
+	if !verifier.lastFrameSeen {
+		report.FirstFrameNumber = frameNumber
+		report.LastFrameNumber = frameNumber
+		verifier.lastFrameSeen = true
+		return
+	}
+
+	switch {
+	case frameNumber == report.LastFrameNumber:
+		report.DuplicateFrames++
+	case frameNumber < report.LastFrameNumber:
+		report.OutOfOrderFrames++
+		if frameNumber < report.FirstFrameNumber {
+			report.FirstFrameNumber = frameNumber
+		}
+	default:
+		report.DroppedFrames += frameNumber - report.LastFrameNumber - 1
+		report.LastFrameNumber = frameNumber
+	}
+}
+
+func (verifier *Verifier) comparePixels(pixels []byte, displayMode peripheralSDK.DisplayMode, frameNumber uint64) {
+	report := &verifier.report
+
+	if verifier.generator == nil || verifier.generator.GetDisplayMode() != displayMode {
+		generatorOpts := append(slices.Clone(verifier.options.generatorOpts), WithGeneratorMarkerCellSize(verifier.options.markerCellSize))
+
+		generator, err := NewGenerator(verifier.options.expectedKind, displayMode, generatorOpts...)
+		if err != nil {
+			report.DisplayModeMismatchFrames++
+			return
+		}
+
+		verifier.generator = generator
+	}
+
+	expectedPixels, err := verifier.generator.Render(verifier.expectedPixels, frameNumber)
+	if err != nil {
+		report.DisplayModeMismatchFrames++
+		return
+	}
+	verifier.expectedPixels = expectedPixels
+
+	var mismatches uint64
+	for i := 0; i < len(expectedPixels); i += 3 {
+		pixelMismatch := false
+		for channel := 0; channel < 3; channel++ {
+			difference := absDifference(pixels[i+channel], expectedPixels[i+channel])
+			if difference > report.MaxPixelDifference {
+				report.MaxPixelDifference = difference
+			}
+			if difference > verifier.options.tolerance {
+				pixelMismatch = true
+			}
+		}
+
+		if pixelMismatch {
+			mismatches++
+		}
+	}
+
+	if mismatches > 0 {
+		report.PixelMismatchFrames++
+		report.PixelMismatches += mismatches
+	}
+}
+
+func (verifier *Verifier) GetReport() peripheralSDK.DisplayVerificationReport {
+	verifier.lock.Lock()
+	defer verifier.lock.Unlock()
+
+	return verifier.report
+}
+
+// Reset clears the report. Next observed frame starts new sequence of frame numbers.
+func (verifier *Verifier) Reset() {
+	verifier.lock.Lock()
+	defer verifier.lock.Unlock()
+
+	verifier.report = peripheralSDK.DisplayVerificationReport{
+		StartedAt: verifier.options.clock(),
+	}
+	verifier.lastFrameSeen = false
+}
+
+func absDifference(a byte, b byte) uint8 {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
+
+var ErrInvalidVerifierConfiguration = errors.New("invalid verifier configuration")
@@ -0,0 +1,92 @@
+package pattern
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifierCountsFrameNumberAnomalies(t *testing.T) {
+	t.Parallel()
+
+	generator, err := NewGenerator(KindGradient, testDisplayMode)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	verifier, err := NewVerifier()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, frameNumber := range []uint64{10, 11, 11, 14, 12, 15} {
+		pixels, err := generator.Render(nil, frameNumber)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		verifier.Observe(pixels, testDisplayMode)
+	}
+
+	report := verifier.GetReport()
+	assert.Equal(t, uint64(6), report.FramesVerified)
+	assert.Equal(t, uint64(10), report.FirstFrameNumber)
+	assert.Equal(t, uint64(15), report.LastFrameNumber)
+	assert.Equal(t, uint64(2), report.DroppedFrames)
+	assert.Equal(t, uint64(1), report.DuplicateFrames)
+	assert.Equal(t, uint64(1), report.OutOfOrderFrames)
+	assert.Zero(t, report.ChecksumMismatchFrames)
+	assert.False(t, report.Passed())
+
+	verifier.Reset()
+	assert.Zero(t, verifier.GetReport().FramesVerified)
+}
+
+func TestVerifierDetectsCorruptedPixels(t *testing.T) {
+	t.Parallel()
+
+	generator, err := NewGenerator(KindMovingBoxes, testDisplayMode, WithGeneratorSeed(3))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	verifier, err := NewVerifier(
+		WithVerifierExpectedPattern(KindMovingBoxes, WithGeneratorSeed(3)),
+		WithVerifierTolerance(4),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pixels, _ := generator.Render(nil, 1)
+	verifier.Observe(pixels, testDisplayMode)
+
+	report := verifier.GetReport()
+	assert.True(t, report.Passed())
+	assert.Zero(t, report.MaxPixelDifference)
+
+	pixels, _ = generator.Render(nil, 2)
+	lastPixel := len(pixels) - 3
+	pixels[lastPixel] ^= 0x80
+	verifier.Observe(pixels, testDisplayMode)
+
+	report = verifier.GetReport()
+	assert.Equal(t, uint64(1), report.ChecksumMismatchFrames)
+	assert.Equal(t, uint64(1), report.PixelMismatchFrames)
+	assert.Equal(t, uint64(1), report.PixelMismatches)
+	assert.Equal(t, uint8(0x80), report.MaxPixelDifference)
+}
+
+func TestVerifierCountsMissingMarker(t *testing.T) {
+	t.Parallel()
+
+	verifier, err := NewVerifier()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pixels := make([]byte, testDisplayMode.Width*testDisplayMode.Height*3)
+	verifier.Observe(pixels, testDisplayMode)
+
+	assert.Equal(t, uint64(1), verifier.GetReport().MissingMarkerFrames)
+}
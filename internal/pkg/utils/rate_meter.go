@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+type RateMeterOpt func(meter *RateMeter)
+
+// WithRateMeterClock replaces time source used to split observations into intervals.
+func WithRateMeterClock(clock func() time.Time) RateMeterOpt {
+	return func(meter *RateMeter) {
+		meter.clock = clock
+	}
+}
+
+// RateMeter counts values added within one second intervals. Rate reports total of the last complete interval, so
+// it is stable between reads and does not depend on read frequency.
+type RateMeter struct {
+	clock func() time.Time
+
+	intervalStart time.Time
+	current       uint64
+	previous      uint64
+
+	lock sync.Mutex
+}
+
+func NewRateMeter(opts ...RateMeterOpt) *RateMeter {
+	meter := &RateMeter{
+		clock: time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(meter)
+	}
+
+	meter.intervalStart = meter.clock().Truncate(time.Second)
+
+	return meter
+}
+
+func (meter *RateMeter) Add(value uint64) {
+	meter.lock.Lock()
+	defer meter.lock.Unlock()
+
+	meter.advance()
+	meter.current += value
+}
+
+// Rate returns total of values added during the last complete second.
+func (meter *RateMeter) Rate() uint64 {
+	meter.lock.Lock()
+	defer meter.lock.Unlock()
+
+	meter.advance()
+
+	return meter.previous
+}
+
+func (meter *RateMeter) advance() {
+	intervalStart := meter.clock().Truncate(time.Second)
+
+	switch elapsed := intervalStart.Sub(meter.intervalStart); {
+	case elapsed <= 0:
+		return
+	case elapsed == time.Second:
+		meter.previous = meter.current
+	default:
+		meter.previous = 0
+	}
+
+	meter.current = 0
+	meter.intervalStart = intervalStart
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateMeter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	meter := NewRateMeter(WithRateMeterClock(func() time.Time { return now }))
+
+	meter.Add(10)
+	meter.Add(5)
+	assert.Equal(t, uint64(0), meter.Rate())
+
+	now = now.Add(time.Second)
+	assert.Equal(t, uint64(15), meter.Rate())
+
+	meter.Add(3)
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, uint64(15), meter.Rate())
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, uint64(3), meter.Rate())
+
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, uint64(0), meter.Rate())
+}
@@ -0,0 +1,193 @@
+package tile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/utils/mjpeg"
+)
+
+// Tile is a JPEG encoded rectangle of the frame.
+type Tile struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+	Image  []byte
+}
+
+type EncoderOptions struct {
+	tileSize           int
+	quality            int
+	fullFrameThreshold float64
+}
+
+type EncoderOpt func(*EncoderOptions)
+
+func defaultEncoderOptions() EncoderOptions {
+	return EncoderOptions{
+		tileSize:           64,
+		quality:            80,
+		fullFrameThreshold: 0.5,
+	}
+}
+
+// WithEncoderTileSize sets width and height of tiles in pixels.
+func WithEncoderTileSize(tileSize int) EncoderOpt {
+	return func(options *EncoderOptions) {
+		options.tileSize = tileSize
+	}
+}
+
+// WithEncoderQuality sets JPEG quality of tile images.
+func WithEncoderQuality(quality int) EncoderOpt {
+	return func(options *EncoderOptions) {
+		options.quality = quality
+	}
+}
+
+// WithEncoderFullFrameThreshold sets fraction of changed tiles above which whole frame is sent as a single tile.
+func WithEncoderFullFrameThreshold(threshold float64) EncoderOpt {
+	return func(options *EncoderOptions) {
+		options.fullFrameThreshold = threshold
+	}
+}
+
+// Encoder splits RGB24 frames into tiles and encodes only tiles which changed since the previous frame. The first
+// frame, frames after size change and frames where most tiles changed are sent as a single tile covering the whole
+// frame. Encoder is not safe for concurrent use.
+type Encoder struct {
+	options EncoderOptions
+
+	jpegEncoder *mjpeg.Encoder
+
+	previous      []byte
+	width, height int
+	tilePixels    []byte
+}
+
+func NewEncoder(opts ...EncoderOpt) (*Encoder, error) {
+	options := defaultEncoderOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.tileSize < 8 {
+		return nil, fmt.Errorf("%w: tile size must be at least 8", ErrInvalidEncoderConfiguration)
+	}
+
+	if options.fullFrameThreshold < 0 || options.fullFrameThreshold > 1 {
+		return nil, fmt.Errorf("%w: full frame threshold must be between 0 and 1", ErrInvalidEncoderConfiguration)
+	}
+
+	jpegEncoder, err := mjpeg.NewEncoder(options.quality)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncoderConfiguration, err)
+	}
+
+	return &Encoder{
+		options:     options,
+		jpegEncoder: jpegEncoder,
+	}, nil
+}
+
+// Reset drops reference frame, so the next frame is sent whole.
+func (encoder *Encoder) Reset() {
+	encoder.previous = encoder.previous[:0]
+}
+
+// Encode returns tiles changed since the previous frame. Empty result means that frame did not change.
+func (encoder *Encoder) Encode(pixels []byte, width int, height int) ([]Tile, error) {
+	if width <= 0 || height <= 0 || len(pixels) != width*height*3 {
+		return nil, fmt.Errorf("%w: %d bytes for %dx%d", ErrInvalidFrameSize, len(pixels), width, height)
+	}
+
+	if len(encoder.previous) != len(pixels) || encoder.width != width || encoder.height != height {
+		return encoder.encodeFullFrame(pixels, width, height)
+	}
+
+	tileSize := encoder.options.tileSize
+	columns := (width + tileSize - 1) / tileSize
+	rows := (height + tileSize - 1) / tileSize
+
+	var changed [][2]int
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			if encoder.tileChanged(pixels, column*tileSize, row*tileSize) {
+				changed = append(changed, [2]int{column, row})
+			}
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	if float64(len(changed)) > float64(columns*rows)*encoder.options.fullFrameThreshold {
+		return encoder.encodeFullFrame(pixels, width, height)
+	}
+
+	tiles := make([]Tile, 0, len(changed))
+	for _, position := range changed {
+		x := position[0] * tileSize
+		y := position[1] * tileSize
+		tileWidth := min(tileSize, width-x)
+		tileHeight := min(tileSize, height-y)
+
+		encoder.tilePixels = copyRect(encoder.tilePixels, pixels, width, x, y, tileWidth, tileHeight)
+
+		image, err := encoder.jpegEncoder.Encode(encoder.tilePixels, tileWidth, tileHeight)
+		if err != nil {
+			return nil, fmt.Errorf("encode tile %d,%d: %w", x, y, err)
+		}
+
+		tiles = append(tiles, Tile{X: x, Y: y, Width: tileWidth, Height: tileHeight, Image: bytes.Clone(image)})
+	}
+
+	copy(encoder.previous, pixels)
+
+	return tiles, nil
+}
+
+func (encoder *Encoder) encodeFullFrame(pixels []byte, width int, height int) ([]Tile, error) {
+	image, err := encoder.jpegEncoder.Encode(pixels, width, height)
+	if err != nil {
+		return nil, fmt.Errorf("encode frame: %w", err)
+	}
+
+	encoder.previous = append(encoder.previous[:0], pixels...)
+	encoder.width = width
+	encoder.height = height
+
+	return []Tile{{Width: width, Height: height, Image: bytes.Clone(image)}}, nil
+}
+
+func (encoder *Encoder) tileChanged(pixels []byte, x int, y int) bool {
+	tileSize := encoder.options.tileSize
+	rowLength := min(tileSize, encoder.width-x) * 3
+
+	for row := y; row < min(y+tileSize, encoder.height); row++ {
+		offset := (row*encoder.width + x) * 3
+		if !bytes.Equal(pixels[offset:offset+rowLength], encoder.previous[offset:offset+rowLength]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func copyRect(dst []byte, pixels []byte, width int, x int, y int, rectWidth int, rectHeight int) []byte {
+	dst = dst[:0]
+	for row := y; row < y+rectHeight; row++ {
+		offset := (row*width + x) * 3
+		dst = append(dst, pixels[offset:offset+rectWidth*3]...)
+	}
+
+	return dst
+}
+
+var (
+	ErrInvalidEncoderConfiguration = errors.New("invalid tile encoder configuration")
+	ErrInvalidFrameSize            = errors.New("invalid frame size")
+)
@@ -0,0 +1,106 @@
+package tile
+
+import (
+	"bytes"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFrame(width int, height int) []byte {
+	return make([]byte, width*height*3)
+}
+
+func TestNewEncoderRejectsInvalidConfiguration(t *testing.T) {
+	_, err := NewEncoder(WithEncoderTileSize(4))
+	assert.ErrorIs(t, err, ErrInvalidEncoderConfiguration)
+
+	_, err = NewEncoder(WithEncoderQuality(0))
+	assert.ErrorIs(t, err, ErrInvalidEncoderConfiguration)
+
+	_, err = NewEncoder(WithEncoderFullFrameThreshold(2))
+	assert.ErrorIs(t, err, ErrInvalidEncoderConfiguration)
+}
+
+func TestEncoderSendsFullFrameFirst(t *testing.T) {
+	encoder, err := NewEncoder(WithEncoderTileSize(16))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	tiles, err := encoder.Encode(newTestFrame(40, 24), 40, 24)
+	if assert.NoError(t, err) && assert.Len(t, tiles, 1) {
+		assert.Equal(t, Tile{X: 0, Y: 0, Width: 40, Height: 24, Image: tiles[0].Image}, tiles[0])
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(tiles[0].Image))
+		assert.NoError(t, err)
+		assert.Equal(t, 40, config.Width)
+	}
+
+	tiles, err = encoder.Encode(newTestFrame(40, 24), 40, 24)
+	assert.NoError(t, err)
+	assert.Empty(t, tiles)
+}
+
+func TestEncoderSendsChangedTiles(t *testing.T) {
+	encoder, err := NewEncoder(WithEncoderTileSize(16))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	frame := newTestFrame(40, 24)
+	_, err = encoder.Encode(frame, 40, 24)
+	assert.NoError(t, err)
+
+	// pixel in the bottom-right edge tile, which is clipped to 8x8
+	frame[(20*40+35)*3] = 0xff
+
+	tiles, err := encoder.Encode(frame, 40, 24)
+	if assert.NoError(t, err) && assert.Len(t, tiles, 1) {
+		assert.Equal(t, 32, tiles[0].X)
+		assert.Equal(t, 16, tiles[0].Y)
+		assert.Equal(t, 8, tiles[0].Width)
+		assert.Equal(t, 8, tiles[0].Height)
+	}
+
+	tiles, err = encoder.Encode(frame, 40, 24)
+	assert.NoError(t, err)
+	assert.Empty(t, tiles)
+}
+
+func TestEncoderSendsFullFrameWhenMostTilesChanged(t *testing.T) {
+	encoder, err := NewEncoder(WithEncoderTileSize(16))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	frame := newTestFrame(32, 32)
+	_, err = encoder.Encode(frame, 32, 32)
+	assert.NoError(t, err)
+
+	for i := range frame {
+		frame[i] = 0x80
+	}
+
+	tiles, err := encoder.Encode(frame, 32, 32)
+	if assert.NoError(t, err) && assert.Len(t, tiles, 1) {
+		assert.Equal(t, 32, tiles[0].Width)
+	}
+
+	encoder.Reset()
+
+	tiles, err = encoder.Encode(frame, 32, 32)
+	assert.NoError(t, err)
+	assert.Len(t, tiles, 1)
+}
+
+func TestEncoderRejectsInvalidFrameSize(t *testing.T) {
+	encoder, err := NewEncoder()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, err = encoder.Encode(make([]byte, 10), 2, 2)
+	assert.ErrorIs(t, err, ErrInvalidFrameSize)
+}
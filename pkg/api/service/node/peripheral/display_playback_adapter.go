@@ -0,0 +1,135 @@
+package peripheral
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type DisplayPlaybackAdapterOpt func(*DisplayPlaybackAdapter)
+
+type DisplayPlaybackAdapter struct {
+	playbackController peripheralSDK.DisplayPlaybackController
+	serviceId          nodeSDK.ServiceId
+	logger             *slog.Logger
+}
+
+func WithDisplayPlaybackAdapterLogger(logger *slog.Logger) DisplayPlaybackAdapterOpt {
+	return func(adapter *DisplayPlaybackAdapter) {
+		adapter.logger = logger
+	}
+}
+
+func NewDisplayPlaybackAdapter(playbackController peripheralSDK.DisplayPlaybackController, opts ...DisplayPlaybackAdapterOpt) *DisplayPlaybackAdapter {
+	adapter := &DisplayPlaybackAdapter{
+		playbackController: playbackController,
+		serviceId:          DisplayPlaybackServiceId.WithArgument(string(playbackController.GetId())),
+		logger:             slog.New(slog.DiscardHandler),
+	}
+
+	for _, opt := range opts {
+		opt(adapter)
+	}
+
+	adapter.logger = adapter.logger.With(
+		slog.String("serviceId", string(adapter.serviceId)),
+		slog.String("peripheralId", playbackController.GetId().String()),
+	)
+
+	return adapter
+}
+
+func (adapter *DisplayPlaybackAdapter) GetServiceId() nodeSDK.ServiceId {
+	return adapter.serviceId
+}
+
+func (adapter *DisplayPlaybackAdapter) Handle(ctx context.Context, stream io.ReadWriteCloser) {
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	var requestHeader api.RequestHeader
+	if err := jsonCodec.Decode(&requestHeader); err != nil {
+		adapter.logger.Warn("Failed to decode request header.", slog.String("error", err.Error()))
+		return
+	}
+
+	logger := adapter.logger.With(slog.String("serviceMethodName", string(requestHeader.MethodName)))
+
+	var handleErr error
+
+	switch requestHeader.MethodName {
+	case DisplayPlaybackGetStateMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleGetState)
+	case DisplayPlaybackSeekMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleSeek)
+	case DisplayPlaybackSetSpeedMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleSetSpeed)
+	case DisplayPlaybackSetLoopMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleSetLoop)
+	case DisplayPlaybackSetPausedMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleSetPaused)
+	default:
+		_ = jsonCodec.Encode(&api.ResponseHeader{Error: api.ErrUnsupportedMethod.Error()})
+		logger.Warn("Unsupported request method.")
+		return
+	}
+
+	if handleErr != nil {
+		logger.Error("Failed to handle request.", slog.String("error", handleErr.Error()))
+		return
+	}
+
+	logger.Debug("Request handled successfully.")
+}
+
+func (adapter *DisplayPlaybackAdapter) handleGetState(ctx context.Context, request DisplayPlaybackGetStateRequest) (*DisplayPlaybackGetStateResponse, error) {
+	state, err := adapter.playbackController.GetDisplayPlaybackState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DisplayPlaybackGetStateResponse{
+		State: state,
+	}, nil
+}
+
+func (adapter *DisplayPlaybackAdapter) handleSeek(ctx context.Context, request DisplayPlaybackSeekRequest) (*DisplayPlaybackSeekResponse, error) {
+	if err := adapter.playbackController.SeekDisplayPlayback(ctx, request.Position); err != nil {
+		return nil, err
+	}
+
+	return &DisplayPlaybackSeekResponse{}, nil
+}
+
+func (adapter *DisplayPlaybackAdapter) handleSetSpeed(ctx context.Context, request DisplayPlaybackSetSpeedRequest) (*DisplayPlaybackSetSpeedResponse, error) {
+	if err := adapter.playbackController.SetDisplayPlaybackSpeed(ctx, request.Speed); err != nil {
+		return nil, err
+	}
+
+	return &DisplayPlaybackSetSpeedResponse{}, nil
+}
+
+func (adapter *DisplayPlaybackAdapter) handleSetLoop(ctx context.Context, request DisplayPlaybackSetLoopRequest) (*DisplayPlaybackSetLoopResponse, error) {
+	if err := adapter.playbackController.SetDisplayPlaybackLoop(ctx, request.Loop); err != nil {
+		return nil, err
+	}
+
+	return &DisplayPlaybackSetLoopResponse{}, nil
+}
+
+func (adapter *DisplayPlaybackAdapter) handleSetPaused(ctx context.Context, request DisplayPlaybackSetPausedRequest) (*DisplayPlaybackSetPausedResponse, error) {
+	if err := adapter.playbackController.SetDisplayPlaybackPaused(ctx, request.Paused); err != nil {
+		return nil, err
+	}
+
+	return &DisplayPlaybackSetPausedResponse{}, nil
+}
@@ -0,0 +1,167 @@
+package peripheral
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type DisplayPlaybackClient struct {
+	nodeId           nodeSDK.NodeId
+	serviceId        nodeSDK.ServiceId
+	transport        apiSDK.Transport
+	peripheralClient *PeripheralClient
+}
+
+var _ peripheralSDK.DisplayPlaybackController = (*DisplayPlaybackClient)(nil)
+
+func AsDisplayPlayback(peripheralClient *PeripheralClient) *DisplayPlaybackClient {
+	return &DisplayPlaybackClient{
+		nodeId:           peripheralClient.nodeId,
+		serviceId:        DisplayPlaybackServiceId.WithArgument(string(peripheralClient.peripheralDescriptor.Id)),
+		transport:        peripheralClient.transport,
+		peripheralClient: peripheralClient,
+	}
+}
+
+func (client *DisplayPlaybackClient) GetId() peripheralSDK.Id {
+	return client.peripheralClient.GetId()
+}
+
+func (client *DisplayPlaybackClient) GetName() peripheralSDK.Name {
+	return client.peripheralClient.GetName()
+}
+
+func (client *DisplayPlaybackClient) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return client.peripheralClient.GetCapabilities()
+}
+
+func (client *DisplayPlaybackClient) Terminate(ctx context.Context) error {
+	return client.peripheralClient.Terminate(ctx)
+}
+
+func (client *DisplayPlaybackClient) GetDisplayPlaybackState(ctx context.Context) (*peripheralSDK.DisplayPlaybackState, error) {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return nil, fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	response, err := utils.HandleClientRequest[DisplayPlaybackGetStateRequest, DisplayPlaybackGetStateResponse](
+		ctx,
+		jsonCodec,
+		DisplayPlaybackGetStateMethod,
+		DisplayPlaybackGetStateRequest{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", DisplayPlaybackGetStateMethod, err)
+	}
+
+	return response.State, nil
+}
+
+func (client *DisplayPlaybackClient) SeekDisplayPlayback(ctx context.Context, position time.Duration) error {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	_, err = utils.HandleClientRequest[DisplayPlaybackSeekRequest, DisplayPlaybackSeekResponse](
+		ctx,
+		jsonCodec,
+		DisplayPlaybackSeekMethod,
+		DisplayPlaybackSeekRequest{Position: position},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", DisplayPlaybackSeekMethod, err)
+	}
+
+	return nil
+}
+
+func (client *DisplayPlaybackClient) SetDisplayPlaybackSpeed(ctx context.Context, speed float64) error {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	_, err = utils.HandleClientRequest[DisplayPlaybackSetSpeedRequest, DisplayPlaybackSetSpeedResponse](
+		ctx,
+		jsonCodec,
+		DisplayPlaybackSetSpeedMethod,
+		DisplayPlaybackSetSpeedRequest{Speed: speed},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", DisplayPlaybackSetSpeedMethod, err)
+	}
+
+	return nil
+}
+
+func (client *DisplayPlaybackClient) SetDisplayPlaybackLoop(ctx context.Context, loop bool) error {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	_, err = utils.HandleClientRequest[DisplayPlaybackSetLoopRequest, DisplayPlaybackSetLoopResponse](
+		ctx,
+		jsonCodec,
+		DisplayPlaybackSetLoopMethod,
+		DisplayPlaybackSetLoopRequest{Loop: loop},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", DisplayPlaybackSetLoopMethod, err)
+	}
+
+	return nil
+}
+
+func (client *DisplayPlaybackClient) SetDisplayPlaybackPaused(ctx context.Context, paused bool) error {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	_, err = utils.HandleClientRequest[DisplayPlaybackSetPausedRequest, DisplayPlaybackSetPausedResponse](
+		ctx,
+		jsonCodec,
+		DisplayPlaybackSetPausedMethod,
+		DisplayPlaybackSetPausedRequest{Paused: paused},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", DisplayPlaybackSetPausedMethod, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package peripheral
+
+import (
+	"time"
+
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const DisplayPlaybackServiceId = nodeSDK.ServiceId("node/peripheral/display-playback")
+
+const (
+	DisplayPlaybackGetStateMethod  nodeSDK.MethodName = "get-state"
+	DisplayPlaybackSeekMethod      nodeSDK.MethodName = "seek"
+	DisplayPlaybackSetSpeedMethod  nodeSDK.MethodName = "set-speed"
+	DisplayPlaybackSetLoopMethod   nodeSDK.MethodName = "set-loop"
+	DisplayPlaybackSetPausedMethod nodeSDK.MethodName = "set-paused"
+)
+
+type DisplayPlaybackGetStateRequest struct{}
+
+type DisplayPlaybackGetStateResponse struct {
+	State *peripheralSDK.DisplayPlaybackState `json:"state"`
+}
+
+type DisplayPlaybackSeekRequest struct {
+	Position time.Duration `json:"position"`
+}
+
+type DisplayPlaybackSeekResponse struct{}
+
+type DisplayPlaybackSetSpeedRequest struct {
+	Speed float64 `json:"speed"`
+}
+
+type DisplayPlaybackSetSpeedResponse struct{}
+
+type DisplayPlaybackSetLoopRequest struct {
+	Loop bool `json:"loop"`
+}
+
+type DisplayPlaybackSetLoopResponse struct{}
+
+type DisplayPlaybackSetPausedRequest struct {
+	Paused bool `json:"paused"`
+}
+
+type DisplayPlaybackSetPausedResponse struct{}
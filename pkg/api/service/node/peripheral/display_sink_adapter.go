@@ -2,10 +2,13 @@ package peripheral
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"sync"
 
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/peripheral"
 	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
 	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
 	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
@@ -19,6 +22,11 @@ type DisplaySinkAdapter struct {
 	displaySink peripheralSDK.DisplaySink
 	serviceId   nodeSDK.ServiceId
 	logger      *slog.Logger
+
+	// passthroughSource is the display source which adapted its EDID to this sink, it presents its configured EDID
+	// again when the route is removed.
+	passthroughSource *DisplaySourceClient
+	passthroughLock   sync.Mutex
 }
 
 func WithDisplaySinkAdapterLogger(logger *slog.Logger) DisplaySinkAdapterOpt {
@@ -32,6 +40,8 @@ func NewDisplaySinkAdapter(displaySink peripheralSDK.DisplaySink, opts ...Displa
 		displaySink: displaySink,
 		serviceId:   DisplaySinkServiceId.WithArgument(string(displaySink.GetId())),
 		logger:      slog.New(slog.DiscardHandler),
+
+		passthroughLock: sync.Mutex{},
 	}
 
 	for _, opt := range opts {
@@ -72,6 +82,14 @@ func (adapter *DisplaySinkAdapter) Handle(ctx context.Context, stream io.ReadWri
 		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleSetDisplayFrameBufferProvider)
 	case DisplaySinkClearFrameBufferProviderMethod:
 		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleClearDisplayFrameBufferProvider)
+	case DisplaySinkSetFailoverProviderMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleSetFailoverProvider)
+	case DisplaySinkGetMetricsMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleGetMetrics)
+	case DisplaySinkGetInfoMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleGetInfo)
+	case DisplaySinkCheckFrameBufferProviderMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleCheckFrameBufferProvider)
 	default:
 		_ = jsonCodec.Encode(&api.ResponseHeader{Error: api.ErrUnsupportedMethod.Error()})
 		logger.Warn("Unsupported request method.")
@@ -97,13 +115,192 @@ func (adapter *DisplaySinkAdapter) handleSetDisplayFrameBufferProvider(ctx conte
 		return nil, err
 	}
 
+	adapter.passthroughDisplaySinkInfo(ctx, displaySource)
+
 	return &DisplaySinkSetFrameBufferProviderResponse{}, nil
 }
 
+// passthroughDisplaySinkInfo lets the display source adapt its EDID to this sink, the source routed to the sink before
+// presents its configured EDID again. Failure is only logged, the route is already set and frames flow with the EDID
+// source presents. Source adapts its EDID to the sink routed to it last, when its frames are shown by several sinks.
+func (adapter *DisplaySinkAdapter) passthroughDisplaySinkInfo(ctx context.Context, displaySource *DisplaySourceClient) {
+	adapter.passthroughLock.Lock()
+	defer adapter.passthroughLock.Unlock()
+
+	if adapter.passthroughSource != nil && !isSameDisplaySource(adapter.passthroughSource, displaySource) {
+		adapter.restoreDisplaySourceEdid(ctx)
+	}
+
+	infoProvider, isInfoProvider := adapter.displaySink.(peripheralSDK.DisplaySinkInfoProvider)
+	if !isInfoProvider {
+		adapter.logger.Debug("Display sink does not provide info, edid passthrough skipped.")
+		return
+	}
+
+	logger := adapter.logger.With(slog.String("sourcePeripheralId", displaySource.GetId().String()))
+
+	sinkInfo, err := infoProvider.GetDisplaySinkInfo(ctx)
+	if err != nil {
+		logger.Warn("Failed to get display sink info for edid passthrough.", slog.String("error", err.Error()))
+		return
+	}
+
+	err = displaySource.PassthroughDisplaySinkInfo(ctx, sinkInfo)
+	switch {
+	case err == nil:
+		adapter.passthroughSource = displaySource
+		logger.Info("Display sink info passed through to display source.")
+	case errors.Is(err, ErrDisplaySourceEdidPassthroughUnsupported):
+		logger.Info("Display source does not support edid passthrough, it presents its own edid.")
+	default:
+		// source may have adapted its edid before failing
+		adapter.passthroughSource = displaySource
+		logger.Warn("Failed to pass display sink info through to display source.", slog.String("error", err.Error()))
+	}
+}
+
+// clearPassthroughDisplaySource lets the display source which adapted its EDID to this sink present its configured
+// EDID again, the route from it is removed.
+func (adapter *DisplaySinkAdapter) clearPassthroughDisplaySource(ctx context.Context) {
+	adapter.passthroughLock.Lock()
+	defer adapter.passthroughLock.Unlock()
+
+	if adapter.passthroughSource != nil {
+		adapter.restoreDisplaySourceEdid(ctx)
+	}
+}
+
+func (adapter *DisplaySinkAdapter) restoreDisplaySourceEdid(ctx context.Context) {
+	displaySource := adapter.passthroughSource
+	adapter.passthroughSource = nil
+
+	logger := adapter.logger.With(slog.String("sourcePeripheralId", displaySource.GetId().String()))
+
+	err := displaySource.PassthroughDisplaySinkInfo(ctx, nil)
+	if err != nil {
+		logger.Warn("Failed to restore edid of display source.", slog.String("error", err.Error()))
+		return
+	}
+
+	logger.Info("Display source presents its configured edid again.")
+}
+
+func isSameDisplaySource(a *DisplaySourceClient, b *DisplaySourceClient) bool {
+	return a.nodeId == b.nodeId && a.GetId() == b.GetId()
+}
+
+func (adapter *DisplaySinkAdapter) handleSetFailoverProvider(ctx context.Context, request DisplaySinkSetFailoverProviderRequest) (*DisplaySinkSetFailoverProviderResponse, error) {
+	transport, hasTransport := ctx.Value("transport").(api.Transport)
+	if !hasTransport {
+		return nil, fmt.Errorf("transport not found in context")
+	}
+
+	providers := make([]peripheralSDK.DisplayFrameBufferProvider, 0, len(request.Sources))
+	for _, source := range request.Sources {
+		providers = append(providers, newDisplaySourceClient(transport, source.NodeId, source.Peripheral))
+	}
+
+	opts := []peripheral.DisplayFailoverProviderOpt{
+		peripheral.WithDisplayFailoverProviderLogger(adapter.logger),
+	}
+
+	hysteresis := request.Hysteresis
+	if hysteresis.FailoverAfter > 0 {
+		opts = append(opts, peripheral.WithDisplayFailoverProviderFailoverAfter(hysteresis.FailoverAfter))
+	}
+	if hysteresis.FailbackAfter > 0 {
+		opts = append(opts, peripheral.WithDisplayFailoverProviderFailbackAfter(hysteresis.FailbackAfter))
+	}
+	if hysteresis.ProbeInterval > 0 {
+		opts = append(opts, peripheral.WithDisplayFailoverProviderProbeInterval(hysteresis.ProbeInterval))
+	}
+	if hysteresis.ErrorThreshold > 0 {
+		opts = append(opts, peripheral.WithDisplayFailoverProviderErrorThreshold(hysteresis.ErrorThreshold))
+	}
+
+	failoverProvider, err := peripheral.NewDisplayFailoverProvider(providers, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create failover provider: %w", err)
+	}
+
+	if err := adapter.displaySink.SetDisplayFrameBufferProvider(failoverProvider); err != nil {
+		return nil, err
+	}
+
+	// edid is not passed through to failover sources, each of them may be shown
+	adapter.clearPassthroughDisplaySource(ctx)
+
+	return &DisplaySinkSetFailoverProviderResponse{}, nil
+}
+
 func (adapter *DisplaySinkAdapter) handleClearDisplayFrameBufferProvider(ctx context.Context, request DisplaySinkClearFrameBufferProviderRequest) (*DisplaySinkClearFrameBufferProviderResponse, error) {
 	if err := adapter.displaySink.ClearDisplayFrameBufferProvider(); err != nil {
 		return nil, err
 	}
 
+	adapter.clearPassthroughDisplaySource(ctx)
+
 	return &DisplaySinkClearFrameBufferProviderResponse{}, nil
 }
+
+func (adapter *DisplaySinkAdapter) handleGetMetrics(ctx context.Context, request DisplaySinkGetMetricsRequest) (*DisplaySinkGetMetricsResponse, error) {
+	metricsProvider, isMetricsProvider := adapter.displaySink.(peripheralSDK.DisplaySinkMetricsProvider)
+	if !isMetricsProvider {
+		return nil, ErrDisplaySinkMetricsUnsupported
+	}
+
+	metrics, err := metricsProvider.GetDisplaySinkMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DisplaySinkGetMetricsResponse{
+		Metrics: metrics,
+	}, nil
+}
+
+func (adapter *DisplaySinkAdapter) handleGetInfo(ctx context.Context, request DisplaySinkGetInfoRequest) (*DisplaySinkGetInfoResponse, error) {
+	infoProvider, isInfoProvider := adapter.displaySink.(peripheralSDK.DisplaySinkInfoProvider)
+	if !isInfoProvider {
+		return nil, ErrDisplaySinkInfoUnsupported
+	}
+
+	info, err := infoProvider.GetDisplaySinkInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DisplaySinkGetInfoResponse{
+		Info: info,
+	}, nil
+}
+
+// handleCheckFrameBufferProvider checks the provider against sink info without attaching it. Sinks without info
+// get report of the provider only.
+func (adapter *DisplaySinkAdapter) handleCheckFrameBufferProvider(ctx context.Context, request DisplaySinkCheckFrameBufferProviderRequest) (*DisplaySinkCheckFrameBufferProviderResponse, error) {
+	transport, hasTransport := ctx.Value("transport").(api.Transport)
+	if !hasTransport {
+		return nil, fmt.Errorf("transport not found in context")
+	}
+
+	var info *peripheralSDK.DisplaySinkInfo
+
+	if infoProvider, isInfoProvider := adapter.displaySink.(peripheralSDK.DisplaySinkInfoProvider); isInfoProvider {
+		var err error
+		info, err = infoProvider.GetDisplaySinkInfo(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get display sink info: %w", err)
+		}
+	}
+
+	displaySource := newDisplaySourceClient(transport, request.NodeId, request.Peripheral)
+
+	return &DisplaySinkCheckFrameBufferProviderResponse{
+		Report: peripheralSDK.CheckDisplaySinkCompatibility(ctx, info, displaySource),
+	}, nil
+}
+
+var (
+	ErrDisplaySinkMetricsUnsupported = errors.New("display sink does not provide metrics")
+	ErrDisplaySinkInfoUnsupported    = errors.New("display sink does not provide info")
+)
@@ -0,0 +1,129 @@
+package peripheral
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/internal/pkg/api/transport/loopback"
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const testNodeId = nodeSDK.NodeId("node")
+
+// testDisplaySink is a display sink describing itself by the info.
+type testDisplaySink struct {
+	*peripheralSDK.DisplaySinkMock
+	info *peripheralSDK.DisplaySinkInfo
+}
+
+func (sink *testDisplaySink) GetDisplaySinkInfo(ctx context.Context) (*peripheralSDK.DisplaySinkInfo, error) {
+	return sink.info, nil
+}
+
+// testPassthroughDisplaySource is a display source recording sink info passed through to it.
+type testPassthroughDisplaySource struct {
+	*peripheralSDK.DisplaySourceMock
+	sinkInfos chan *peripheralSDK.DisplaySinkInfo
+}
+
+func (source *testPassthroughDisplaySource) PassthroughDisplaySinkInfo(ctx context.Context, sinkInfo *peripheralSDK.DisplaySinkInfo) error {
+	source.sinkInfos <- sinkInfo
+	return nil
+}
+
+func newTestDisplaySourceMock(t *testing.T, id peripheralSDK.Id) *peripheralSDK.DisplaySourceMock {
+	source := peripheralSDK.NewDisplaySourceMock(t)
+	source.EXPECT().GetId().Return(id).Maybe()
+	source.EXPECT().GetName().Return(peripheralSDK.Name(id)).Maybe()
+	source.EXPECT().GetCapabilities().Return([]peripheralSDK.PeripheralCapability{peripheralSDK.DisplaySourceCapability}).Maybe()
+
+	return source
+}
+
+func newTestPassthroughDisplaySource(t *testing.T, id peripheralSDK.Id) *testPassthroughDisplaySource {
+	return &testPassthroughDisplaySource{
+		DisplaySourceMock: newTestDisplaySourceMock(t, id),
+		sinkInfos:         make(chan *peripheralSDK.DisplaySinkInfo, 4),
+	}
+}
+
+// newTestTransport returns transport serving the adapters on the local node.
+func newTestTransport(t *testing.T, services ...nodeSDK.Service) apiSDK.Transport {
+	transport := apiSDK.NewTransportMock(t)
+	transport.EXPECT().GetLocalNodeId().Return(testNodeId).Maybe()
+
+	return loopback.NewTransport(transport, loopback.WithTransportServices(services...))
+}
+
+func receiveSinkInfo(t *testing.T, source *testPassthroughDisplaySource) *peripheralSDK.DisplaySinkInfo {
+	t.Helper()
+
+	select {
+	case sinkInfo := <-source.sinkInfos:
+		return sinkInfo
+	default:
+		require.FailNow(t, "sink info not passed through to display source")
+		return nil
+	}
+}
+
+func TestDisplaySinkAdapterPassesSinkInfoThroughToSource(t *testing.T) {
+	displaySink := &testDisplaySink{
+		DisplaySinkMock: peripheralSDK.NewDisplaySinkMock(t),
+		info: &peripheralSDK.DisplaySinkInfo{
+			Model:          "Monitor",
+			SupportedModes: peripheralSDK.DisplayModeList{{Width: 1920, Height: 1080, RefreshRate: 60}},
+		},
+	}
+	displaySink.EXPECT().GetId().Return("sink").Maybe()
+	displaySink.EXPECT().SetDisplayFrameBufferProvider(mock.Anything).Return(nil)
+	displaySink.EXPECT().ClearDisplayFrameBufferProvider().Return(nil)
+
+	sourceA := newTestPassthroughDisplaySource(t, "source-a")
+	sourceB := newTestPassthroughDisplaySource(t, "source-b")
+	sourceWithoutPassthrough := newTestDisplaySourceMock(t, "source-c")
+
+	transport := newTestTransport(t,
+		NewDisplaySinkAdapter(displaySink),
+		NewDisplaySourceAdapter(sourceA),
+		NewDisplaySourceAdapter(sourceB),
+		NewDisplaySourceAdapter(sourceWithoutPassthrough),
+	)
+
+	sinkClient := newDisplaySinkClient(transport, testNodeId, peripheralDescriptor{Id: "sink"})
+
+	err := sinkClient.SetDisplayFrameBufferProvider(newDisplaySourceClient(transport, testNodeId, createPeripheralDescriptor(sourceA)))
+	require.NoError(t, err)
+	assert.Equal(t, displaySink.info, receiveSinkInfo(t, sourceA))
+
+	// the source routed before presents its configured edid again
+	err = sinkClient.SetDisplayFrameBufferProvider(newDisplaySourceClient(transport, testNodeId, createPeripheralDescriptor(sourceB)))
+	require.NoError(t, err)
+	assert.Nil(t, receiveSinkInfo(t, sourceA))
+	assert.Equal(t, displaySink.info, receiveSinkInfo(t, sourceB))
+
+	err = sinkClient.ClearDisplayFrameBufferProvider()
+	require.NoError(t, err)
+	assert.Nil(t, receiveSinkInfo(t, sourceB))
+
+	// route is set even when the source does not support passthrough
+	sourceWithoutPassthroughClient := newDisplaySourceClient(transport, testNodeId, createPeripheralDescriptor(sourceWithoutPassthrough))
+
+	err = sourceWithoutPassthroughClient.PassthroughDisplaySinkInfo(t.Context(), displaySink.info)
+	assert.ErrorIs(t, err, ErrDisplaySourceEdidPassthroughUnsupported)
+
+	err = sinkClient.SetDisplayFrameBufferProvider(sourceWithoutPassthroughClient)
+	require.NoError(t, err)
+
+	err = sinkClient.ClearDisplayFrameBufferProvider()
+	require.NoError(t, err)
+
+	assert.Empty(t, sourceA.sinkInfos)
+	assert.Empty(t, sourceB.sinkInfos)
+}
@@ -18,7 +18,11 @@ type DisplaySinkClient struct {
 	peripheralClient *PeripheralClient
 }
 
-var _ peripheralSDK.DisplaySink = (*DisplaySinkClient)(nil)
+var (
+	_ peripheralSDK.DisplaySink                = (*DisplaySinkClient)(nil)
+	_ peripheralSDK.DisplaySinkMetricsProvider = (*DisplaySinkClient)(nil)
+	_ peripheralSDK.DisplaySinkInfoProvider    = (*DisplaySinkClient)(nil)
+)
 
 func newDisplaySinkClient(transport apiSDK.Transport, nodeId nodeSDK.NodeId, descriptor peripheralDescriptor) *DisplaySinkClient {
 	return &DisplaySinkClient{
@@ -88,6 +92,43 @@ func (client *DisplaySinkClient) SetDisplayFrameBufferProvider(provider peripher
 	return nil
 }
 
+// SetFailoverDisplayFrameBufferProvider routes ordered list of display sources to the sink. The sink switches to
+// the next source when the active one loses signal and back when a higher priority source recovers.
+func (client *DisplaySinkClient) SetFailoverDisplayFrameBufferProvider(ctx context.Context, sources []*DisplaySourceClient, hysteresis DisplaySinkFailoverHysteresis) error {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	failoverSources := make([]DisplaySinkFailoverSource, 0, len(sources))
+	for _, source := range sources {
+		failoverSources = append(failoverSources, DisplaySinkFailoverSource{
+			NodeId:     source.nodeId,
+			Peripheral: source.peripheralClient.peripheralDescriptor,
+		})
+	}
+
+	_, err = utils.HandleClientRequest[DisplaySinkSetFailoverProviderRequest, DisplaySinkSetFailoverProviderResponse](
+		ctx,
+		jsonCodec,
+		DisplaySinkSetFailoverProviderMethod,
+		DisplaySinkSetFailoverProviderRequest{
+			Sources:    failoverSources,
+			Hysteresis: hysteresis,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", DisplaySinkSetFailoverProviderMethod, err)
+	}
+
+	return nil
+}
+
 func (client *DisplaySinkClient) ClearDisplayFrameBufferProvider() error {
 	ctx := context.Background()
 
@@ -112,3 +153,80 @@ func (client *DisplaySinkClient) ClearDisplayFrameBufferProvider() error {
 
 	return nil
 }
+
+func (client *DisplaySinkClient) GetDisplaySinkMetrics(ctx context.Context) (*peripheralSDK.DisplaySinkMetrics, error) {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return nil, fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	response, err := utils.HandleClientRequest[DisplaySinkGetMetricsRequest, DisplaySinkGetMetricsResponse](
+		ctx,
+		jsonCodec,
+		DisplaySinkGetMetricsMethod,
+		DisplaySinkGetMetricsRequest{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", DisplaySinkGetMetricsMethod, err)
+	}
+
+	return response.Metrics, nil
+}
+
+func (client *DisplaySinkClient) GetDisplaySinkInfo(ctx context.Context) (*peripheralSDK.DisplaySinkInfo, error) {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return nil, fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	response, err := utils.HandleClientRequest[DisplaySinkGetInfoRequest, DisplaySinkGetInfoResponse](
+		ctx,
+		jsonCodec,
+		DisplaySinkGetInfoMethod,
+		DisplaySinkGetInfoRequest{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", DisplaySinkGetInfoMethod, err)
+	}
+
+	return response.Info, nil
+}
+
+// CheckDisplayFrameBufferProvider asks the sink whether it can accept display source as its provider. The source is
+// not attached.
+func (client *DisplaySinkClient) CheckDisplayFrameBufferProvider(ctx context.Context, source *DisplaySourceClient) (*peripheralSDK.DisplaySinkCompatibilityReport, error) {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return nil, fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	response, err := utils.HandleClientRequest[DisplaySinkCheckFrameBufferProviderRequest, DisplaySinkCheckFrameBufferProviderResponse](
+		ctx,
+		jsonCodec,
+		DisplaySinkCheckFrameBufferProviderMethod,
+		DisplaySinkCheckFrameBufferProviderRequest{
+			NodeId:     source.nodeId,
+			Peripheral: source.peripheralClient.peripheralDescriptor,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", DisplaySinkCheckFrameBufferProviderMethod, err)
+	}
+
+	return response.Report, nil
+}
@@ -1,7 +1,10 @@
 package peripheral
 
 import (
+	"time"
+
 	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
 )
 
 const DisplaySinkServiceId = nodeSDK.ServiceId("node/peripheral/display-sink")
@@ -9,6 +12,10 @@ const DisplaySinkServiceId = nodeSDK.ServiceId("node/peripheral/display-sink")
 const (
 	DisplaySinkSetFrameBufferProviderMethod   nodeSDK.MethodName = "set-frame-buffer-provider"
 	DisplaySinkClearFrameBufferProviderMethod nodeSDK.MethodName = "clear-frame-buffer-provider"
+	DisplaySinkSetFailoverProviderMethod      nodeSDK.MethodName = "set-failover-provider"
+	DisplaySinkGetMetricsMethod               nodeSDK.MethodName = "get-metrics"
+	DisplaySinkGetInfoMethod                  nodeSDK.MethodName = "get-info"
+	DisplaySinkCheckFrameBufferProviderMethod nodeSDK.MethodName = "check-frame-buffer-provider"
 )
 
 type DisplaySinkSetFrameBufferProviderRequest struct {
@@ -21,3 +28,45 @@ type DisplaySinkSetFrameBufferProviderResponse struct{}
 type DisplaySinkClearFrameBufferProviderRequest struct{}
 
 type DisplaySinkClearFrameBufferProviderResponse struct{}
+
+type DisplaySinkFailoverSource struct {
+	NodeId     nodeSDK.NodeId       `json:"nodeId"`
+	Peripheral peripheralDescriptor `json:"peripheral"`
+}
+
+// DisplaySinkFailoverHysteresis configures when failover route switches between sources. Zero values fall back to
+// defaults.
+type DisplaySinkFailoverHysteresis struct {
+	FailoverAfter  time.Duration `json:"failoverAfter"`
+	FailbackAfter  time.Duration `json:"failbackAfter"`
+	ProbeInterval  time.Duration `json:"probeInterval"`
+	ErrorThreshold int           `json:"errorThreshold"`
+}
+
+type DisplaySinkSetFailoverProviderRequest struct {
+	Sources    []DisplaySinkFailoverSource   `json:"sources"`
+	Hysteresis DisplaySinkFailoverHysteresis `json:"hysteresis"`
+}
+
+type DisplaySinkSetFailoverProviderResponse struct{}
+
+type DisplaySinkGetMetricsRequest struct{}
+
+type DisplaySinkGetMetricsResponse struct {
+	Metrics *peripheralSDK.DisplaySinkMetrics `json:"metrics"`
+}
+
+type DisplaySinkGetInfoRequest struct{}
+
+type DisplaySinkGetInfoResponse struct {
+	Info *peripheralSDK.DisplaySinkInfo `json:"info"`
+}
+
+type DisplaySinkCheckFrameBufferProviderRequest struct {
+	NodeId     nodeSDK.NodeId       `json:"nodeId"`
+	Peripheral peripheralDescriptor `json:"peripheral"`
+}
+
+type DisplaySinkCheckFrameBufferProviderResponse struct {
+	Report *peripheralSDK.DisplaySinkCompatibilityReport `json:"report"`
+}
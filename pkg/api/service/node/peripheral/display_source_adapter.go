@@ -2,6 +2,7 @@ package peripheral
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -76,6 +77,12 @@ func (adapter *DisplaySourceAdapter) Handle(ctx context.Context, stream io.ReadW
 		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleGetPixelFormat)
 	case DisplaySourceGetMetricsMethod:
 		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleGetMetrics)
+	case DisplaySourceGetEdidMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleGetEdid)
+	case DisplaySourceSetEdidMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleSetEdid)
+	case DisplaySourcePassthroughSinkInfoMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handlePassthroughSinkInfo)
 	default:
 		_ = jsonCodec.Encode(&api.ResponseHeader{Error: api.ErrUnsupportedMethod.Error()})
 		logger.Warn("Unsupported request method.")
@@ -110,7 +117,9 @@ func (adapter *DisplaySourceAdapter) handleGetFrameBuffer(ctx context.Context, j
 	}()
 
 	response := &DisplaySourceGetFrameBufferResponse{
-		Size: frameBuffer.GetSize(),
+		Size:      frameBuffer.GetSize(),
+		Sequence:  frameBuffer.GetSequence(),
+		Timestamp: frameBuffer.GetTimestamp(),
 	}
 
 	if err := jsonCodec.Encode(&api.ResponseHeader{}); err != nil {
@@ -157,3 +166,55 @@ func (adapter *DisplaySourceAdapter) handleGetMetrics(ctx context.Context, reque
 		Metrics: metrics,
 	}, nil
 }
+
+func (adapter *DisplaySourceAdapter) handleGetEdid(ctx context.Context, request DisplaySourceGetEdidRequest) (*DisplaySourceGetEdidResponse, error) {
+	edidProvider, isEdidProvider := adapter.displaySource.(peripheralSDK.DisplaySourceEdidProvider)
+	if !isEdidProvider {
+		return nil, ErrDisplaySourceEdidUnsupported
+	}
+
+	edid, err := edidProvider.GetDisplayEdid(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DisplaySourceGetEdidResponse{
+		Edid: edid,
+	}, nil
+}
+
+func (adapter *DisplaySourceAdapter) handleSetEdid(ctx context.Context, request DisplaySourceSetEdidRequest) (*DisplaySourceSetEdidResponse, error) {
+	edidProgrammer, isEdidProgrammer := adapter.displaySource.(peripheralSDK.DisplaySourceEdidProgrammer)
+	if !isEdidProgrammer {
+		return nil, ErrDisplaySourceEdidProgrammingUnsupported
+	}
+
+	if len(request.Edid) == 0 {
+		return nil, api.ErrMalformedRequest
+	}
+
+	if err := edidProgrammer.SetDisplayEdid(ctx, request.Edid); err != nil {
+		return nil, err
+	}
+
+	return &DisplaySourceSetEdidResponse{}, nil
+}
+
+func (adapter *DisplaySourceAdapter) handlePassthroughSinkInfo(ctx context.Context, request DisplaySourcePassthroughSinkInfoRequest) (*DisplaySourcePassthroughSinkInfoResponse, error) {
+	edidPassthrough, isEdidPassthrough := adapter.displaySource.(peripheralSDK.DisplaySourceEdidPassthrough)
+	if !isEdidPassthrough {
+		return &DisplaySourcePassthroughSinkInfoResponse{Unsupported: true}, nil
+	}
+
+	if err := edidPassthrough.PassthroughDisplaySinkInfo(ctx, request.SinkInfo); err != nil {
+		return nil, err
+	}
+
+	return &DisplaySourcePassthroughSinkInfoResponse{}, nil
+}
+
+var (
+	ErrDisplaySourceEdidUnsupported            = errors.New("display source does not provide edid")
+	ErrDisplaySourceEdidPassthroughUnsupported = errors.New("display source does not support edid passthrough")
+	ErrDisplaySourceEdidProgrammingUnsupported = errors.New("display source does not support edid programming")
+)
@@ -23,7 +23,12 @@ type DisplaySourceClient struct {
 	peripheralClient *PeripheralClient
 }
 
-var _ peripheralSDK.DisplaySource = (*DisplaySourceClient)(nil)
+var (
+	_ peripheralSDK.DisplaySource                = (*DisplaySourceClient)(nil)
+	_ peripheralSDK.DisplaySourceEdidProvider    = (*DisplaySourceClient)(nil)
+	_ peripheralSDK.DisplaySourceEdidPassthrough = (*DisplaySourceClient)(nil)
+	_ peripheralSDK.DisplaySourceEdidProgrammer  = (*DisplaySourceClient)(nil)
+)
 
 func newDisplaySourceClient(transport apiSDK.Transport, nodeId nodeSDK.NodeId, descriptor peripheralDescriptor) *DisplaySourceClient {
 	return &DisplaySourceClient{
@@ -132,7 +137,10 @@ func (client *DisplaySourceClient) GetDisplayFrameBuffer(ctx context.Context) (*
 		return nil, fmt.Errorf("read frame buffer payload: %w", err)
 	}
 
-	frameBuffer := peripheralSDK.NewDisplayFrameBuffer(memoryBuffer)
+	frameBuffer := peripheralSDK.NewDisplayFrameBuffer(memoryBuffer,
+		peripheralSDK.WithDisplayFrameBufferSequence(response.Sequence),
+		peripheralSDK.WithDisplayFrameBufferTimestamp(response.Timestamp),
+	)
 
 	return frameBuffer, nil
 }
@@ -210,3 +218,79 @@ func (client *DisplaySourceClient) GetDisplaySourceMetrics() peripheralSDK.Displ
 
 	return response.Metrics
 }
+
+func (client *DisplaySourceClient) GetDisplayEdid(ctx context.Context) ([]byte, error) {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return nil, fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	response, err := utils.HandleClientRequest[DisplaySourceGetEdidRequest, DisplaySourceGetEdidResponse](
+		ctx,
+		jsonCodec,
+		DisplaySourceGetEdidMethod,
+		DisplaySourceGetEdidRequest{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", DisplaySourceGetEdidMethod, err)
+	}
+
+	return response.Edid, nil
+}
+
+func (client *DisplaySourceClient) SetDisplayEdid(ctx context.Context, edid []byte) error {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	_, err = utils.HandleClientRequest[DisplaySourceSetEdidRequest, DisplaySourceSetEdidResponse](
+		ctx,
+		jsonCodec,
+		DisplaySourceSetEdidMethod,
+		DisplaySourceSetEdidRequest{Edid: edid},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", DisplaySourceSetEdidMethod, err)
+	}
+
+	return nil
+}
+
+func (client *DisplaySourceClient) PassthroughDisplaySinkInfo(ctx context.Context, sinkInfo *peripheralSDK.DisplaySinkInfo) error {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	response, err := utils.HandleClientRequest[DisplaySourcePassthroughSinkInfoRequest, DisplaySourcePassthroughSinkInfoResponse](
+		ctx,
+		jsonCodec,
+		DisplaySourcePassthroughSinkInfoMethod,
+		DisplaySourcePassthroughSinkInfoRequest{SinkInfo: sinkInfo},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", DisplaySourcePassthroughSinkInfoMethod, err)
+	}
+
+	if response.Unsupported {
+		return ErrDisplaySourceEdidPassthroughUnsupported
+	}
+
+	return nil
+}
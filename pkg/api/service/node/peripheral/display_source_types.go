@@ -1,6 +1,8 @@
 package peripheral
 
 import (
+	"time"
+
 	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
 	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
 )
@@ -12,12 +14,18 @@ const (
 	DisplaySourceGetDisplayModeMethod nodeSDK.MethodName = "get-display-mode"
 	DisplaySourceGetPixelFormatMethod nodeSDK.MethodName = "get-pixel-format"
 	DisplaySourceGetMetricsMethod     nodeSDK.MethodName = "get-metrics"
+	DisplaySourceGetEdidMethod        nodeSDK.MethodName = "get-edid"
+	DisplaySourceSetEdidMethod        nodeSDK.MethodName = "set-edid"
+
+	DisplaySourcePassthroughSinkInfoMethod nodeSDK.MethodName = "passthrough-sink-info"
 )
 
 type DisplaySourceGetFrameBufferRequest struct{}
 
 type DisplaySourceGetFrameBufferResponse struct {
-	Size int `json:"size"`
+	Size      int       `json:"size"`
+	Sequence  uint64    `json:"sequence"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 type DisplaySourceGetDisplayModeRequest struct{}
@@ -37,3 +45,26 @@ type DisplaySourceGetMetricsRequest struct{}
 type DisplaySourceGetMetricsResponse struct {
 	Metrics peripheralSDK.DisplaySourceMetrics `json:"metrics"`
 }
+
+type DisplaySourceGetEdidRequest struct{}
+
+type DisplaySourceGetEdidResponse struct {
+	Edid []byte `json:"edid"`
+}
+
+type DisplaySourceSetEdidRequest struct {
+	Edid []byte `json:"edid"`
+}
+
+type DisplaySourceSetEdidResponse struct{}
+
+type DisplaySourcePassthroughSinkInfoRequest struct {
+	// SinkInfo is nil when the route to the sink is removed.
+	SinkInfo *peripheralSDK.DisplaySinkInfo `json:"sinkInfo"`
+}
+
+type DisplaySourcePassthroughSinkInfoResponse struct {
+	// Unsupported is set when the display source does not adapt its EDID to the sink, client returns
+	// ErrDisplaySourceEdidPassthroughUnsupported then.
+	Unsupported bool `json:"unsupported,omitempty"`
+}
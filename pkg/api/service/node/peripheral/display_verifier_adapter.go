@@ -0,0 +1,105 @@
+package peripheral
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type DisplayVerifierAdapterOpt func(*DisplayVerifierAdapter)
+
+type DisplayVerifierAdapter struct {
+	displayVerifier peripheralSDK.DisplayVerifier
+	serviceId       nodeSDK.ServiceId
+	logger          *slog.Logger
+}
+
+func WithDisplayVerifierAdapterLogger(logger *slog.Logger) DisplayVerifierAdapterOpt {
+	return func(adapter *DisplayVerifierAdapter) {
+		adapter.logger = logger
+	}
+}
+
+func NewDisplayVerifierAdapter(displayVerifier peripheralSDK.DisplayVerifier, opts ...DisplayVerifierAdapterOpt) *DisplayVerifierAdapter {
+	adapter := &DisplayVerifierAdapter{
+		displayVerifier: displayVerifier,
+		serviceId:       DisplayVerifierServiceId.WithArgument(string(displayVerifier.GetId())),
+		logger:          slog.New(slog.DiscardHandler),
+	}
+
+	for _, opt := range opts {
+		opt(adapter)
+	}
+
+	adapter.logger = adapter.logger.With(
+		slog.String("serviceId", string(adapter.serviceId)),
+		slog.String("peripheralId", displayVerifier.GetId().String()),
+	)
+
+	return adapter
+}
+
+func (adapter *DisplayVerifierAdapter) GetServiceId() nodeSDK.ServiceId {
+	return adapter.serviceId
+}
+
+func (adapter *DisplayVerifierAdapter) Handle(ctx context.Context, stream io.ReadWriteCloser) {
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	var requestHeader api.RequestHeader
+	if err := jsonCodec.Decode(&requestHeader); err != nil {
+		adapter.logger.Warn("Failed to decode request header.", slog.String("error", err.Error()))
+		return
+	}
+
+	logger := adapter.logger.With(slog.String("serviceMethodName", string(requestHeader.MethodName)))
+
+	var handleErr error
+
+	switch requestHeader.MethodName {
+	case DisplayVerifierGetReportMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleGetReport)
+	case DisplayVerifierResetMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleReset)
+	default:
+		_ = jsonCodec.Encode(&api.ResponseHeader{Error: api.ErrUnsupportedMethod.Error()})
+		logger.Warn("Unsupported request method.")
+		return
+	}
+
+	if handleErr != nil {
+		logger.Error("Failed to handle request.", slog.String("error", handleErr.Error()))
+		return
+	}
+
+	logger.Debug("Request handled successfully.")
+}
+
+func (adapter *DisplayVerifierAdapter) handleGetReport(ctx context.Context, request DisplayVerifierGetReportRequest) (*DisplayVerifierGetReportResponse, error) {
+	report, err := adapter.displayVerifier.GetDisplayVerificationReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DisplayVerifierGetReportResponse{
+		Report: report,
+	}, nil
+}
+
+func (adapter *DisplayVerifierAdapter) handleReset(ctx context.Context, request DisplayVerifierResetRequest) (*DisplayVerifierResetResponse, error) {
+	if err := adapter.displayVerifier.ResetDisplayVerification(ctx); err != nil {
+		return nil, err
+	}
+
+	return &DisplayVerifierResetResponse{}, nil
+}
@@ -0,0 +1,94 @@
+package peripheral
+
+import (
+	"context"
+	"fmt"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type DisplayVerifierClient struct {
+	nodeId           nodeSDK.NodeId
+	serviceId        nodeSDK.ServiceId
+	transport        apiSDK.Transport
+	peripheralClient *PeripheralClient
+}
+
+var _ peripheralSDK.DisplayVerifier = (*DisplayVerifierClient)(nil)
+
+func AsDisplayVerifier(peripheralClient *PeripheralClient) *DisplayVerifierClient {
+	return &DisplayVerifierClient{
+		nodeId:           peripheralClient.nodeId,
+		serviceId:        DisplayVerifierServiceId.WithArgument(string(peripheralClient.peripheralDescriptor.Id)),
+		transport:        peripheralClient.transport,
+		peripheralClient: peripheralClient,
+	}
+}
+
+func (client *DisplayVerifierClient) GetId() peripheralSDK.Id {
+	return client.peripheralClient.GetId()
+}
+
+func (client *DisplayVerifierClient) GetName() peripheralSDK.Name {
+	return client.peripheralClient.GetName()
+}
+
+func (client *DisplayVerifierClient) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return client.peripheralClient.GetCapabilities()
+}
+
+func (client *DisplayVerifierClient) Terminate(ctx context.Context) error {
+	return client.peripheralClient.Terminate(ctx)
+}
+
+func (client *DisplayVerifierClient) GetDisplayVerificationReport(ctx context.Context) (*peripheralSDK.DisplayVerificationReport, error) {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return nil, fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	response, err := utils.HandleClientRequest[DisplayVerifierGetReportRequest, DisplayVerifierGetReportResponse](
+		ctx,
+		jsonCodec,
+		DisplayVerifierGetReportMethod,
+		DisplayVerifierGetReportRequest{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", DisplayVerifierGetReportMethod, err)
+	}
+
+	return response.Report, nil
+}
+
+func (client *DisplayVerifierClient) ResetDisplayVerification(ctx context.Context) error {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	_, err = utils.HandleClientRequest[DisplayVerifierResetRequest, DisplayVerifierResetResponse](
+		ctx,
+		jsonCodec,
+		DisplayVerifierResetMethod,
+		DisplayVerifierResetRequest{},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", DisplayVerifierResetMethod, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package peripheral
+
+import (
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const DisplayVerifierServiceId = nodeSDK.ServiceId("node/peripheral/display-verifier")
+
+const (
+	DisplayVerifierGetReportMethod nodeSDK.MethodName = "get-report"
+	DisplayVerifierResetMethod     nodeSDK.MethodName = "reset"
+)
+
+type DisplayVerifierGetReportRequest struct{}
+
+type DisplayVerifierGetReportResponse struct {
+	Report *peripheralSDK.DisplayVerificationReport `json:"report"`
+}
+
+type DisplayVerifierResetRequest struct{}
+
+type DisplayVerifierResetResponse struct{}
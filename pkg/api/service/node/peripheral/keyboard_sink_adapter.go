@@ -0,0 +1,92 @@
+package peripheral
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type KeyboardSinkAdapterOpt func(*KeyboardSinkAdapter)
+
+type KeyboardSinkAdapter struct {
+	keyboardSink peripheralSDK.KeyboardSink
+	serviceId    nodeSDK.ServiceId
+	logger       *slog.Logger
+}
+
+func WithKeyboardSinkAdapterLogger(logger *slog.Logger) KeyboardSinkAdapterOpt {
+	return func(adapter *KeyboardSinkAdapter) {
+		adapter.logger = logger
+	}
+}
+
+func NewKeyboardSinkAdapter(keyboardSink peripheralSDK.KeyboardSink, opts ...KeyboardSinkAdapterOpt) *KeyboardSinkAdapter {
+	adapter := &KeyboardSinkAdapter{
+		keyboardSink: keyboardSink,
+		serviceId:    KeyboardSinkServiceId.WithArgument(string(keyboardSink.GetId())),
+		logger:       slog.New(slog.DiscardHandler),
+	}
+
+	for _, opt := range opts {
+		opt(adapter)
+	}
+
+	adapter.logger = adapter.logger.With(
+		slog.String("serviceId", string(adapter.serviceId)),
+		slog.String("peripheralId", keyboardSink.GetId().String()),
+	)
+
+	return adapter
+}
+
+func (adapter *KeyboardSinkAdapter) GetServiceId() nodeSDK.ServiceId {
+	return adapter.serviceId
+}
+
+func (adapter *KeyboardSinkAdapter) Handle(ctx context.Context, stream io.ReadWriteCloser) {
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	var requestHeader api.RequestHeader
+	if err := jsonCodec.Decode(&requestHeader); err != nil {
+		adapter.logger.Warn("Failed to decode request header.", slog.String("error", err.Error()))
+		return
+	}
+
+	logger := adapter.logger.With(slog.String("serviceMethodName", string(requestHeader.MethodName)))
+
+	var handleErr error
+
+	switch requestHeader.MethodName {
+	case KeyboardSinkHandleDataEventMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleDataEvent)
+	default:
+		_ = jsonCodec.Encode(&api.ResponseHeader{Error: api.ErrUnsupportedMethod.Error()})
+		logger.Warn("Unsupported request method.")
+		return
+	}
+
+	if handleErr != nil {
+		logger.Error("Failed to handle request.", slog.String("error", handleErr.Error()))
+		return
+	}
+
+	logger.Debug("Request handled successfully.")
+}
+
+func (adapter *KeyboardSinkAdapter) handleDataEvent(ctx context.Context, request KeyboardSinkHandleDataEventRequest) (*KeyboardSinkHandleDataEventResponse, error) {
+	if err := adapter.keyboardSink.HandleKeyboardDataEvent(request.Event.ToEvent()); err != nil {
+		return nil, err
+	}
+
+	return &KeyboardSinkHandleDataEventResponse{}, nil
+}
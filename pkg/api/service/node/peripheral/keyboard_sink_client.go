@@ -0,0 +1,93 @@
+package peripheral
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type KeyboardSinkClient struct {
+	nodeId           nodeSDK.NodeId
+	serviceId        nodeSDK.ServiceId
+	transport        apiSDK.Transport
+	peripheralClient *PeripheralClient
+}
+
+var _ peripheralSDK.KeyboardSink = (*KeyboardSinkClient)(nil)
+
+func AsKeyboardSink(peripheralClient *PeripheralClient) *KeyboardSinkClient {
+	return &KeyboardSinkClient{
+		nodeId:           peripheralClient.nodeId,
+		serviceId:        KeyboardSinkServiceId.WithArgument(string(peripheralClient.peripheralDescriptor.Id)),
+		transport:        peripheralClient.transport,
+		peripheralClient: peripheralClient,
+	}
+}
+
+func (client *KeyboardSinkClient) GetId() peripheralSDK.Id {
+	return client.peripheralClient.GetId()
+}
+
+func (client *KeyboardSinkClient) GetName() peripheralSDK.Name {
+	return client.peripheralClient.GetName()
+}
+
+func (client *KeyboardSinkClient) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return client.peripheralClient.GetCapabilities()
+}
+
+func (client *KeyboardSinkClient) Terminate(ctx context.Context) error {
+	return client.peripheralClient.Terminate(ctx)
+}
+
+func (client *KeyboardSinkClient) HandleKeyboardDataEvent(event peripheralSDK.KeyboardEvent) error {
+	payload, err := NewKeyboardKeyEventPayload(event)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	_, err = utils.HandleClientRequest[KeyboardSinkHandleDataEventRequest, KeyboardSinkHandleDataEventResponse](
+		ctx,
+		jsonCodec,
+		KeyboardSinkHandleDataEventMethod,
+		KeyboardSinkHandleDataEventRequest{Event: *payload},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", KeyboardSinkHandleDataEventMethod, err)
+	}
+
+	return nil
+}
+
+// KeyboardControlChannel returns channel closed when ctx is done. Control events of remote sinks are not
+// transferred by the API.
+func (client *KeyboardSinkClient) KeyboardControlChannel(ctx context.Context) <-chan peripheralSDK.KeyboardControlEvent {
+	controlChannel := make(chan peripheralSDK.KeyboardControlEvent)
+
+	go func() {
+		<-ctx.Done()
+		close(controlChannel)
+	}()
+
+	return controlChannel
+}
+
+var ErrKeyboardEventUnsupported = errors.New("keyboard event unsupported")
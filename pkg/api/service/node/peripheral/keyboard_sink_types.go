@@ -0,0 +1,64 @@
+package peripheral
+
+import (
+	"fmt"
+	"time"
+
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const KeyboardSinkServiceId = nodeSDK.ServiceId("node/peripheral/keyboard-sink")
+
+const (
+	KeyboardSinkHandleDataEventMethod nodeSDK.MethodName = "handle-data-event"
+)
+
+// KeyboardKeyEventPayload is wire representation of peripheralSDK.KeyboardKeyEvent.
+type KeyboardKeyEventPayload struct {
+	Timestamp        time.Time                        `json:"timestamp"`
+	HIDUsage         peripheralSDK.KeyboardHIDUsage   `json:"hidUsage"`
+	PhysicalScanCode string                           `json:"physicalScanCode,omitempty"`
+	LogicalKey       peripheralSDK.KeyboardLogicalKey `json:"logicalKey"`
+	Modifiers        peripheralSDK.KeyboardModifiers  `json:"modifiers"`
+	State            peripheralSDK.KeyboardKeyState   `json:"state"`
+	Text             string                           `json:"text,omitempty"`
+	SourceID         string                           `json:"sourceId,omitempty"`
+}
+
+func NewKeyboardKeyEventPayload(event peripheralSDK.KeyboardEvent) (*KeyboardKeyEventPayload, error) {
+	keyEvent, isKeyEvent := event.(peripheralSDK.KeyboardKeyEvent)
+	if !isKeyEvent {
+		return nil, fmt.Errorf("%w: %T", ErrKeyboardEventUnsupported, event)
+	}
+
+	return &KeyboardKeyEventPayload{
+		Timestamp:        keyEvent.Timestamp(),
+		HIDUsage:         keyEvent.HIDUsage,
+		PhysicalScanCode: keyEvent.PhysicalScanCode,
+		LogicalKey:       keyEvent.LogicalKey,
+		Modifiers:        keyEvent.Modifiers,
+		State:            keyEvent.State,
+		Text:             keyEvent.Text,
+		SourceID:         keyEvent.SourceID,
+	}, nil
+}
+
+func (payload KeyboardKeyEventPayload) ToEvent() peripheralSDK.KeyboardKeyEvent {
+	return peripheralSDK.NewKeyboardKeyEvent(
+		payload.HIDUsage,
+		payload.PhysicalScanCode,
+		payload.LogicalKey,
+		payload.Modifiers,
+		payload.State,
+		payload.Text,
+		payload.SourceID,
+		payload.Timestamp,
+	)
+}
+
+type KeyboardSinkHandleDataEventRequest struct {
+	Event KeyboardKeyEventPayload `json:"event"`
+}
+
+type KeyboardSinkHandleDataEventResponse struct{}
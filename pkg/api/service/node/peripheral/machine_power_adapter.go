@@ -0,0 +1,105 @@
+package peripheral
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type MachinePowerAdapterOpt func(*MachinePowerAdapter)
+
+type MachinePowerAdapter struct {
+	powerController peripheralSDK.MachinePowerController
+	serviceId       nodeSDK.ServiceId
+	logger          *slog.Logger
+}
+
+func WithMachinePowerAdapterLogger(logger *slog.Logger) MachinePowerAdapterOpt {
+	return func(adapter *MachinePowerAdapter) {
+		adapter.logger = logger
+	}
+}
+
+func NewMachinePowerAdapter(powerController peripheralSDK.MachinePowerController, opts ...MachinePowerAdapterOpt) *MachinePowerAdapter {
+	adapter := &MachinePowerAdapter{
+		powerController: powerController,
+		serviceId:       MachinePowerServiceId.WithArgument(string(powerController.GetId())),
+		logger:          slog.New(slog.DiscardHandler),
+	}
+
+	for _, opt := range opts {
+		opt(adapter)
+	}
+
+	adapter.logger = adapter.logger.With(
+		slog.String("serviceId", string(adapter.serviceId)),
+		slog.String("peripheralId", powerController.GetId().String()),
+	)
+
+	return adapter
+}
+
+func (adapter *MachinePowerAdapter) GetServiceId() nodeSDK.ServiceId {
+	return adapter.serviceId
+}
+
+func (adapter *MachinePowerAdapter) Handle(ctx context.Context, stream io.ReadWriteCloser) {
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	var requestHeader api.RequestHeader
+	if err := jsonCodec.Decode(&requestHeader); err != nil {
+		adapter.logger.Warn("Failed to decode request header.", slog.String("error", err.Error()))
+		return
+	}
+
+	logger := adapter.logger.With(slog.String("serviceMethodName", string(requestHeader.MethodName)))
+
+	var handleErr error
+
+	switch requestHeader.MethodName {
+	case MachinePowerGetStateMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleGetState)
+	case MachinePowerHandleActionMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleAction)
+	default:
+		_ = jsonCodec.Encode(&api.ResponseHeader{Error: api.ErrUnsupportedMethod.Error()})
+		logger.Warn("Unsupported request method.")
+		return
+	}
+
+	if handleErr != nil {
+		logger.Error("Failed to handle request.", slog.String("error", handleErr.Error()))
+		return
+	}
+
+	logger.Debug("Request handled successfully.")
+}
+
+func (adapter *MachinePowerAdapter) handleGetState(ctx context.Context, request MachinePowerGetStateRequest) (*MachinePowerGetStateResponse, error) {
+	state, err := adapter.powerController.GetMachinePowerState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MachinePowerGetStateResponse{
+		State: state,
+	}, nil
+}
+
+func (adapter *MachinePowerAdapter) handleAction(ctx context.Context, request MachinePowerHandleActionRequest) (*MachinePowerHandleActionResponse, error) {
+	if err := adapter.powerController.HandleMachinePowerAction(ctx, request.Action); err != nil {
+		return nil, err
+	}
+
+	return &MachinePowerHandleActionResponse{}, nil
+}
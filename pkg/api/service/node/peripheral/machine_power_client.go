@@ -0,0 +1,94 @@
+package peripheral
+
+import (
+	"context"
+	"fmt"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type MachinePowerClient struct {
+	nodeId           nodeSDK.NodeId
+	serviceId        nodeSDK.ServiceId
+	transport        apiSDK.Transport
+	peripheralClient *PeripheralClient
+}
+
+var _ peripheralSDK.MachinePowerController = (*MachinePowerClient)(nil)
+
+func AsMachinePower(peripheralClient *PeripheralClient) *MachinePowerClient {
+	return &MachinePowerClient{
+		nodeId:           peripheralClient.nodeId,
+		serviceId:        MachinePowerServiceId.WithArgument(string(peripheralClient.peripheralDescriptor.Id)),
+		transport:        peripheralClient.transport,
+		peripheralClient: peripheralClient,
+	}
+}
+
+func (client *MachinePowerClient) GetId() peripheralSDK.Id {
+	return client.peripheralClient.GetId()
+}
+
+func (client *MachinePowerClient) GetName() peripheralSDK.Name {
+	return client.peripheralClient.GetName()
+}
+
+func (client *MachinePowerClient) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return client.peripheralClient.GetCapabilities()
+}
+
+func (client *MachinePowerClient) Terminate(ctx context.Context) error {
+	return client.peripheralClient.Terminate(ctx)
+}
+
+func (client *MachinePowerClient) GetMachinePowerState(ctx context.Context) (*peripheralSDK.MachinePowerState, error) {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return nil, fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	response, err := utils.HandleClientRequest[MachinePowerGetStateRequest, MachinePowerGetStateResponse](
+		ctx,
+		jsonCodec,
+		MachinePowerGetStateMethod,
+		MachinePowerGetStateRequest{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", MachinePowerGetStateMethod, err)
+	}
+
+	return response.State, nil
+}
+
+func (client *MachinePowerClient) HandleMachinePowerAction(ctx context.Context, action peripheralSDK.MachinePowerAction) error {
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	_, err = utils.HandleClientRequest[MachinePowerHandleActionRequest, MachinePowerHandleActionResponse](
+		ctx,
+		jsonCodec,
+		MachinePowerHandleActionMethod,
+		MachinePowerHandleActionRequest{Action: action},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", MachinePowerHandleActionMethod, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package peripheral
+
+import (
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const MachinePowerServiceId = nodeSDK.ServiceId("node/peripheral/machine-power")
+
+const (
+	MachinePowerGetStateMethod     nodeSDK.MethodName = "get-state"
+	MachinePowerHandleActionMethod nodeSDK.MethodName = "handle-action"
+)
+
+type MachinePowerGetStateRequest struct{}
+
+type MachinePowerGetStateResponse struct {
+	State *peripheralSDK.MachinePowerState `json:"state"`
+}
+
+type MachinePowerHandleActionRequest struct {
+	Action peripheralSDK.MachinePowerAction `json:"action"`
+}
+
+type MachinePowerHandleActionResponse struct{}
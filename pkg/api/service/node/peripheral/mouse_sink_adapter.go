@@ -0,0 +1,105 @@
+package peripheral
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type MouseSinkAdapterOpt func(*MouseSinkAdapter)
+
+type MouseSinkAdapter struct {
+	mouseSink peripheralSDK.MouseSink
+	serviceId nodeSDK.ServiceId
+	logger    *slog.Logger
+}
+
+func WithMouseSinkAdapterLogger(logger *slog.Logger) MouseSinkAdapterOpt {
+	return func(adapter *MouseSinkAdapter) {
+		adapter.logger = logger
+	}
+}
+
+func NewMouseSinkAdapter(mouseSink peripheralSDK.MouseSink, opts ...MouseSinkAdapterOpt) *MouseSinkAdapter {
+	adapter := &MouseSinkAdapter{
+		mouseSink: mouseSink,
+		serviceId: MouseSinkServiceId.WithArgument(string(mouseSink.GetId())),
+		logger:    slog.New(slog.DiscardHandler),
+	}
+
+	for _, opt := range opts {
+		opt(adapter)
+	}
+
+	adapter.logger = adapter.logger.With(
+		slog.String("serviceId", string(adapter.serviceId)),
+		slog.String("peripheralId", mouseSink.GetId().String()),
+	)
+
+	return adapter
+}
+
+func (adapter *MouseSinkAdapter) GetServiceId() nodeSDK.ServiceId {
+	return adapter.serviceId
+}
+
+func (adapter *MouseSinkAdapter) Handle(ctx context.Context, stream io.ReadWriteCloser) {
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	var requestHeader api.RequestHeader
+	if err := jsonCodec.Decode(&requestHeader); err != nil {
+		adapter.logger.Warn("Failed to decode request header.", slog.String("error", err.Error()))
+		return
+	}
+
+	logger := adapter.logger.With(slog.String("serviceMethodName", string(requestHeader.MethodName)))
+
+	var handleErr error
+
+	switch requestHeader.MethodName {
+	case MouseSinkHandleDataEventMethod:
+		handleErr = utils.HandleServiceRequest(ctx, jsonCodec, adapter.handleDataEvent)
+	default:
+		_ = jsonCodec.Encode(&api.ResponseHeader{Error: api.ErrUnsupportedMethod.Error()})
+		logger.Warn("Unsupported request method.")
+		return
+	}
+
+	if handleErr != nil {
+		logger.Error("Failed to handle request.", slog.String("error", handleErr.Error()))
+		return
+	}
+
+	logger.Debug("Request handled successfully.")
+}
+
+func (adapter *MouseSinkAdapter) handleDataEvent(ctx context.Context, request MouseSinkHandleDataEventRequest) (*MouseSinkHandleDataEventResponse, error) {
+	event, err := request.Event.ToEvent()
+	if err != nil {
+		return nil, err
+	}
+
+	eventHandler, isEventHandler := adapter.mouseSink.(peripheralSDK.MouseEventHandler)
+	if !isEventHandler {
+		return nil, ErrMouseSinkEventsUnsupported
+	}
+
+	if err := eventHandler.HandleMouseDataEvent(event); err != nil {
+		return nil, err
+	}
+
+	return &MouseSinkHandleDataEventResponse{}, nil
+}
+
+var ErrMouseSinkEventsUnsupported = errors.New("mouse sink does not handle mouse events")
@@ -0,0 +1,83 @@
+package peripheral
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/api"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/codec"
+	"github.com/szymonpodeszwa/go-kvm-agent/pkg/api/utils"
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+type MouseSinkClient struct {
+	nodeId           nodeSDK.NodeId
+	serviceId        nodeSDK.ServiceId
+	transport        apiSDK.Transport
+	peripheralClient *PeripheralClient
+}
+
+var (
+	_ peripheralSDK.MouseSink         = (*MouseSinkClient)(nil)
+	_ peripheralSDK.MouseEventHandler = (*MouseSinkClient)(nil)
+)
+
+func AsMouseSink(peripheralClient *PeripheralClient) *MouseSinkClient {
+	return &MouseSinkClient{
+		nodeId:           peripheralClient.nodeId,
+		serviceId:        MouseSinkServiceId.WithArgument(string(peripheralClient.peripheralDescriptor.Id)),
+		transport:        peripheralClient.transport,
+		peripheralClient: peripheralClient,
+	}
+}
+
+func (client *MouseSinkClient) GetId() peripheralSDK.Id {
+	return client.peripheralClient.GetId()
+}
+
+func (client *MouseSinkClient) GetName() peripheralSDK.Name {
+	return client.peripheralClient.GetName()
+}
+
+func (client *MouseSinkClient) GetCapabilities() []peripheralSDK.PeripheralCapability {
+	return client.peripheralClient.GetCapabilities()
+}
+
+func (client *MouseSinkClient) Terminate(ctx context.Context) error {
+	return client.peripheralClient.Terminate(ctx)
+}
+
+func (client *MouseSinkClient) HandleMouseDataEvent(event peripheralSDK.MouseEvent) error {
+	payload, err := NewMouseEventPayload(event)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	stream, err := client.transport.OpenServiceStream(ctx, client.serviceId, client.nodeId)
+	if err != nil {
+		return fmt.Errorf("open stream: %w", err)
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	jsonCodec := codec.NewJsonCodec(stream)
+
+	_, err = utils.HandleClientRequest[MouseSinkHandleDataEventRequest, MouseSinkHandleDataEventResponse](
+		ctx,
+		jsonCodec,
+		MouseSinkHandleDataEventMethod,
+		MouseSinkHandleDataEventRequest{Event: *payload},
+	)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", MouseSinkHandleDataEventMethod, err)
+	}
+
+	return nil
+}
+
+var ErrMouseEventUnsupported = errors.New("mouse event unsupported")
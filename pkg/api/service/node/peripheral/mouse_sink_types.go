@@ -0,0 +1,80 @@
+package peripheral
+
+import (
+	"fmt"
+	"time"
+
+	nodeSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/node"
+	peripheralSDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/peripheral"
+)
+
+const MouseSinkServiceId = nodeSDK.ServiceId("node/peripheral/mouse-sink")
+
+const (
+	MouseSinkHandleDataEventMethod nodeSDK.MethodName = "handle-data-event"
+)
+
+// MouseEventPayload is wire representation of mouse events. Fields used depend on Type.
+type MouseEventPayload struct {
+	Type      peripheralSDK.MouseEventType   `json:"type"`
+	Timestamp time.Time                      `json:"timestamp"`
+	Absolute  bool                           `json:"absolute,omitempty"`
+	X         float64                        `json:"x,omitempty"`
+	Y         float64                        `json:"y,omitempty"`
+	DeltaX    int32                          `json:"deltaX,omitempty"`
+	DeltaY    int32                          `json:"deltaY,omitempty"`
+	Button    peripheralSDK.MouseButton      `json:"button,omitempty"`
+	State     peripheralSDK.MouseButtonState `json:"state,omitempty"`
+	SourceID  string                         `json:"sourceId,omitempty"`
+}
+
+func NewMouseEventPayload(event peripheralSDK.MouseEvent) (*MouseEventPayload, error) {
+	payload := &MouseEventPayload{
+		Type:      event.Type(),
+		Timestamp: event.Timestamp(),
+	}
+
+	switch mouseEvent := event.(type) {
+	case peripheralSDK.MouseMoveEvent:
+		payload.Absolute = mouseEvent.Absolute
+		payload.X = mouseEvent.X
+		payload.Y = mouseEvent.Y
+		payload.DeltaX = mouseEvent.DeltaX
+		payload.DeltaY = mouseEvent.DeltaY
+		payload.SourceID = mouseEvent.SourceID
+	case peripheralSDK.MouseButtonEvent:
+		payload.Button = mouseEvent.Button
+		payload.State = mouseEvent.State
+		payload.SourceID = mouseEvent.SourceID
+	case peripheralSDK.MouseWheelEvent:
+		payload.DeltaX = mouseEvent.DeltaX
+		payload.DeltaY = mouseEvent.DeltaY
+		payload.SourceID = mouseEvent.SourceID
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrMouseEventUnsupported, event)
+	}
+
+	return payload, nil
+}
+
+func (payload MouseEventPayload) ToEvent() (peripheralSDK.MouseEvent, error) {
+	switch payload.Type {
+	case peripheralSDK.MouseEventMove:
+		if payload.Absolute {
+			return peripheralSDK.NewMouseAbsoluteMoveEvent(payload.X, payload.Y, payload.SourceID, payload.Timestamp), nil
+		}
+		return peripheralSDK.NewMouseRelativeMoveEvent(payload.DeltaX, payload.DeltaY, payload.SourceID, payload.Timestamp), nil
+	case peripheralSDK.MouseEventButton:
+		return peripheralSDK.NewMouseButtonEvent(payload.Button, payload.State, payload.SourceID, payload.Timestamp), nil
+	case peripheralSDK.MouseEventWheel:
+		return peripheralSDK.NewMouseWheelEvent(payload.DeltaX, payload.DeltaY, payload.SourceID, payload.Timestamp), nil
+	default:
+		return nil, fmt.Errorf("%w: type %d", ErrMouseEventUnsupported, payload.Type)
+	}
+}
+
+type MouseSinkHandleDataEventRequest struct {
+	Event MouseEventPayload `json:"event"`
+}
+
+type MouseSinkHandleDataEventResponse struct{}
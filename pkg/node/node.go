@@ -14,6 +14,7 @@ func (role NodeRole) String() string {
 const (
 	Peripheral NodeRole = "peripheral"
 	CLI                 = "cli"
+	Gateway             = "gateway"
 )
 
 type NodeId string
@@ -4,19 +4,55 @@ import (
 	"context"
 	"errors"
 	"io"
+	"time"
 
 	memorySDK "github.com/szymonpodeszwa/go-kvm-agent/pkg/memory"
 )
 
 // DisplayFrameBuffer holds buffer with raw display frame data. It wraps memory buffer and holds frame metadata.
 type DisplayFrameBuffer struct {
-	buffer memorySDK.Buffer
+	buffer    memorySDK.Buffer
+	sequence  uint64
+	timestamp time.Time
 }
 
-func NewDisplayFrameBuffer(buffer memorySDK.Buffer) *DisplayFrameBuffer {
-	return &DisplayFrameBuffer{
-		buffer: buffer,
+type DisplayFrameBufferOpt func(*DisplayFrameBuffer)
+
+// WithDisplayFrameBufferSequence sets monotonic sequence number of the frame assigned by the source.
+func WithDisplayFrameBufferSequence(sequence uint64) DisplayFrameBufferOpt {
+	return func(frameBuffer *DisplayFrameBuffer) {
+		frameBuffer.sequence = sequence
+	}
+}
+
+// WithDisplayFrameBufferTimestamp sets time at which the frame was captured. Defaults to creation time.
+func WithDisplayFrameBufferTimestamp(timestamp time.Time) DisplayFrameBufferOpt {
+	return func(frameBuffer *DisplayFrameBuffer) {
+		frameBuffer.timestamp = timestamp
+	}
+}
+
+func NewDisplayFrameBuffer(buffer memorySDK.Buffer, opts ...DisplayFrameBufferOpt) *DisplayFrameBuffer {
+	frameBuffer := &DisplayFrameBuffer{
+		buffer:    buffer,
+		timestamp: time.Now(),
 	}
+
+	for _, opt := range opts {
+		opt(frameBuffer)
+	}
+
+	return frameBuffer
+}
+
+// GetSequence returns sequence number of the frame. Zero means that source does not track sequence.
+func (frameBuffer *DisplayFrameBuffer) GetSequence() uint64 {
+	return frameBuffer.sequence
+}
+
+// GetTimestamp returns time at which the frame was captured.
+func (frameBuffer *DisplayFrameBuffer) GetTimestamp() time.Time {
+	return frameBuffer.timestamp
 }
 
 func (frameBuffer *DisplayFrameBuffer) GetCapacity() int {
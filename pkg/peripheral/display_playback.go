@@ -0,0 +1,55 @@
+package peripheral
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DisplayPlaybackState describes position and settings of a display source replaying recorded frames.
+type DisplayPlaybackState struct {
+	// Position is the current playback position relative to the first frame.
+	Position time.Duration `json:"position"`
+
+	// Duration is the total length of the recording.
+	Duration time.Duration `json:"duration"`
+
+	// FrameIndex is the index of the frame shown at the current position.
+	FrameIndex int `json:"frameIndex"`
+
+	// FrameCount is the number of frames in the recording.
+	FrameCount int `json:"frameCount"`
+
+	// Speed is the playback rate, where 1 replays frames with original timing.
+	Speed float64 `json:"speed"`
+
+	// Loop indicates that playback starts over after the last frame.
+	Loop bool `json:"loop"`
+
+	// Paused indicates that playback position is frozen.
+	Paused bool `json:"paused"`
+}
+
+// DisplayPlaybackController controls display sources which replay recorded frames instead of capturing them live.
+type DisplayPlaybackController interface {
+	Peripheral
+
+	GetDisplayPlaybackState(ctx context.Context) (*DisplayPlaybackState, error)
+
+	// SeekDisplayPlayback moves playback to the given position. It returns ErrDisplayPlaybackPositionOutOfRange
+	// when position is negative or exceeds recording duration.
+	SeekDisplayPlayback(ctx context.Context, position time.Duration) error
+
+	// SetDisplayPlaybackSpeed changes playback rate. It returns ErrDisplayPlaybackSpeedInvalid when speed is not
+	// greater than zero.
+	SetDisplayPlaybackSpeed(ctx context.Context, speed float64) error
+
+	SetDisplayPlaybackLoop(ctx context.Context, loop bool) error
+
+	SetDisplayPlaybackPaused(ctx context.Context, paused bool) error
+}
+
+var (
+	ErrDisplayPlaybackPositionOutOfRange = errors.New("display playback position out of range")
+	ErrDisplayPlaybackSpeedInvalid       = errors.New("display playback speed invalid")
+)
@@ -0,0 +1,125 @@
+package peripheral
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DisplaySinkInfo describes display sink and frames it accepts.
+type DisplaySinkInfo struct {
+	Manufacturer string `json:"manufacturer"`
+	Model        string `json:"model"`
+	SerialNumber string `json:"serialNumber"`
+
+	// SupportedModes lists accepted display modes, empty list means any valid display mode is accepted.
+	SupportedModes DisplayModeList `json:"supportedModes"`
+
+	// CurrentMode is the display mode of the attached provider, nil when sink has no provider or its mode is unknown.
+	CurrentMode *DisplayMode `json:"currentMode,omitempty"`
+
+	// PixelFormats lists accepted pixel formats.
+	PixelFormats []DisplayPixelFormat `json:"pixelFormats"`
+
+	// Edid holds raw EDID blocks of the monitor behind the sink, empty when sink has no physical monitor or its EDID is
+	// unknown.
+	Edid []byte `json:"edid,omitempty"`
+}
+
+// DisplaySinkInfoProvider is implemented by display sinks which describe themselves and frames they accept.
+type DisplaySinkInfoProvider interface {
+	Peripheral
+
+	GetDisplaySinkInfo(ctx context.Context) (*DisplaySinkInfo, error)
+}
+
+// DisplaySinkCompatibilityIssueCode identifies the reason why a sink cannot accept a provider.
+type DisplaySinkCompatibilityIssueCode string
+
+const (
+	// DisplaySinkCompatibilityIssueProviderUnavailable means provider could not report its display mode or pixel
+	// format.
+	DisplaySinkCompatibilityIssueProviderUnavailable DisplaySinkCompatibilityIssueCode = "provider-unavailable"
+	// DisplaySinkCompatibilityIssueInvalidDisplayMode means provider reported invalid display mode.
+	DisplaySinkCompatibilityIssueInvalidDisplayMode DisplaySinkCompatibilityIssueCode = "invalid-display-mode"
+	// DisplaySinkCompatibilityIssueUnsupportedDisplayMode means display mode of provider is not supported by sink.
+	DisplaySinkCompatibilityIssueUnsupportedDisplayMode DisplaySinkCompatibilityIssueCode = "unsupported-display-mode"
+	// DisplaySinkCompatibilityIssueUnsupportedPixelFormat means pixel format of provider is not accepted by sink.
+	DisplaySinkCompatibilityIssueUnsupportedPixelFormat DisplaySinkCompatibilityIssueCode = "unsupported-pixel-format"
+)
+
+type DisplaySinkCompatibilityIssue struct {
+	Code    DisplaySinkCompatibilityIssueCode `json:"code"`
+	Message string                            `json:"message"`
+}
+
+// DisplaySinkCompatibilityReport is the result of checking whether a sink can accept a provider.
+type DisplaySinkCompatibilityReport struct {
+	Compatible bool `json:"compatible"`
+
+	// DisplayMode and PixelFormat are reported by the provider, they are empty when provider is unavailable.
+	DisplayMode *DisplayMode       `json:"displayMode,omitempty"`
+	PixelFormat DisplayPixelFormat `json:"pixelFormat"`
+
+	// SinkInfoAvailable is false when sink does not describe accepted frames, only the provider is checked then.
+	SinkInfoAvailable bool `json:"sinkInfoAvailable"`
+
+	Issues []DisplaySinkCompatibilityIssue `json:"issues"`
+}
+
+// CheckDisplaySinkCompatibility checks provider against display modes and pixel formats accepted by sink described
+// by info. Info may be nil when sink does not provide it.
+func CheckDisplaySinkCompatibility(ctx context.Context, info *DisplaySinkInfo, provider DisplayFrameBufferProvider) *DisplaySinkCompatibilityReport {
+	report := &DisplaySinkCompatibilityReport{
+		SinkInfoAvailable: info != nil,
+		Issues:            []DisplaySinkCompatibilityIssue{},
+	}
+
+	addIssue := func(code DisplaySinkCompatibilityIssueCode, format string, args ...any) {
+		report.Issues = append(report.Issues, DisplaySinkCompatibilityIssue{
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	displayMode, err := provider.GetDisplayMode(ctx)
+	switch {
+	case err != nil:
+		addIssue(DisplaySinkCompatibilityIssueProviderUnavailable, "get display mode: %s", err.Error())
+	case displayMode.Valid() != nil:
+		report.DisplayMode = displayMode
+		addIssue(DisplaySinkCompatibilityIssueInvalidDisplayMode, "display mode %s: %s", displayMode.String(), displayMode.Valid().Error())
+	default:
+		report.DisplayMode = displayMode
+		if info != nil && len(info.SupportedModes) > 0 && !info.SupportedModes.Supports(*displayMode) {
+			addIssue(DisplaySinkCompatibilityIssueUnsupportedDisplayMode, "display mode %s is not supported, supported modes: %s",
+				displayMode.String(), info.SupportedModes.String())
+		}
+	}
+
+	pixelFormat, err := provider.GetDisplayPixelFormat(ctx)
+	switch {
+	case err != nil:
+		addIssue(DisplaySinkCompatibilityIssueProviderUnavailable, "get pixel format: %s", err.Error())
+	default:
+		report.PixelFormat = *pixelFormat
+		if info != nil && !slices.Contains(info.PixelFormats, *pixelFormat) {
+			addIssue(DisplaySinkCompatibilityIssueUnsupportedPixelFormat, "pixel format %q is not supported, supported pixel formats: %s",
+				pixelFormat.String(), joinPixelFormats(info.PixelFormats))
+		}
+	}
+
+	report.Compatible = len(report.Issues) == 0
+
+	return report
+}
+
+func joinPixelFormats(pixelFormats []DisplayPixelFormat) string {
+	formats := make([]string, 0, len(pixelFormats))
+	for _, pixelFormat := range pixelFormats {
+		formats = append(formats, pixelFormat.String())
+	}
+
+	return strings.Join(formats, ", ")
+}
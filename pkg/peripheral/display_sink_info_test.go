@@ -0,0 +1,70 @@
+package peripheral
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestProvider(t *testing.T, displayMode *DisplayMode, displayModeErr error, pixelFormat DisplayPixelFormat) *DisplayFrameBufferProviderMock {
+	provider := NewDisplayFrameBufferProviderMock(t)
+	provider.EXPECT().GetDisplayMode(mock.Anything).Return(displayMode, displayModeErr)
+	provider.EXPECT().GetDisplayPixelFormat(mock.Anything).Return(&pixelFormat, nil)
+
+	return provider
+}
+
+func TestCheckDisplaySinkCompatibility(t *testing.T) {
+	info := &DisplaySinkInfo{
+		SupportedModes: DisplayModeList{
+			{Width: 1280, Height: 720, RefreshRate: 30},
+			{Width: 1920, Height: 1080, RefreshRate: 30},
+		},
+		PixelFormats: []DisplayPixelFormat{DisplayPixelFormatRGB24},
+	}
+
+	t.Run("accepts supported display mode", func(t *testing.T) {
+		provider := newTestProvider(t, &DisplayMode{Width: 1280, Height: 720, RefreshRate: 30}, nil, DisplayPixelFormatRGB24)
+
+		report := CheckDisplaySinkCompatibility(t.Context(), info, provider)
+		assert.True(t, report.Compatible)
+		assert.True(t, report.SinkInfoAvailable)
+		assert.Equal(t, &DisplayMode{Width: 1280, Height: 720, RefreshRate: 30}, report.DisplayMode)
+		assert.Equal(t, DisplayPixelFormatRGB24, report.PixelFormat)
+		assert.Empty(t, report.Issues)
+	})
+
+	t.Run("reports unsupported display mode and pixel format", func(t *testing.T) {
+		provider := newTestProvider(t, &DisplayMode{Width: 800, Height: 600, RefreshRate: 60}, nil, DisplayPixelFormatUnknown)
+
+		report := CheckDisplaySinkCompatibility(t.Context(), info, provider)
+		assert.False(t, report.Compatible)
+		if assert.Len(t, report.Issues, 2) {
+			assert.Equal(t, DisplaySinkCompatibilityIssueUnsupportedDisplayMode, report.Issues[0].Code)
+			assert.Equal(t, "display mode 800x600@60 is not supported, supported modes: 1280x720@30, 1920x1080@30", report.Issues[0].Message)
+			assert.Equal(t, DisplaySinkCompatibilityIssueUnsupportedPixelFormat, report.Issues[1].Code)
+		}
+	})
+
+	t.Run("reports unavailable provider", func(t *testing.T) {
+		provider := newTestProvider(t, nil, errors.New("source offline"), DisplayPixelFormatRGB24)
+
+		report := CheckDisplaySinkCompatibility(t.Context(), info, provider)
+		assert.False(t, report.Compatible)
+		assert.Nil(t, report.DisplayMode)
+		if assert.Len(t, report.Issues, 1) {
+			assert.Equal(t, DisplaySinkCompatibilityIssueProviderUnavailable, report.Issues[0].Code)
+			assert.Equal(t, "get display mode: source offline", report.Issues[0].Message)
+		}
+	})
+
+	t.Run("checks only provider without sink info", func(t *testing.T) {
+		provider := newTestProvider(t, &DisplayMode{Width: 800, Height: 600, RefreshRate: 60}, nil, DisplayPixelFormatRGB24)
+
+		report := CheckDisplaySinkCompatibility(t.Context(), nil, provider)
+		assert.True(t, report.Compatible)
+		assert.False(t, report.SinkInfoAvailable)
+	})
+}
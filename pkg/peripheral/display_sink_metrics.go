@@ -0,0 +1,28 @@
+package peripheral
+
+import "context"
+
+// DisplaySinkMetrics captures throughput counters exposed by display sinks.
+type DisplaySinkMetrics struct {
+	// FramesReceived counts new frames taken from the frame buffer provider.
+	FramesReceived uint64 `json:"framesReceived"`
+
+	// BytesReceived counts bytes of the received frames.
+	BytesReceived uint64 `json:"bytesReceived"`
+
+	// FramesPerSecond indicates how many new frames were received during the last second.
+	FramesPerSecond uint64 `json:"framesPerSecond"`
+
+	// Errors counts failures of reading or processing frames.
+	Errors uint64 `json:"errors"`
+
+	// AdditionalMetrics may contain metrics related to underlying sink.
+	AdditionalMetrics map[string]interface{} `json:"additionalMetrics"`
+}
+
+// DisplaySinkMetricsProvider is implemented by display sinks which expose DisplaySinkMetrics.
+type DisplaySinkMetricsProvider interface {
+	Peripheral
+
+	GetDisplaySinkMetrics(ctx context.Context) (*DisplaySinkMetrics, error)
+}
@@ -0,0 +1,31 @@
+package peripheral
+
+import "context"
+
+// DisplaySourceEdidProvider is implemented by display sources which present EDID to the connected machine. The EDID
+// decides which display modes the machine offers.
+type DisplaySourceEdidProvider interface {
+	Peripheral
+
+	// GetDisplayEdid returns raw EDID blocks currently presented by the source.
+	GetDisplayEdid(ctx context.Context) ([]byte, error)
+}
+
+// DisplaySourceEdidPassthrough is implemented by display sources which adapt presented EDID to the display sink their
+// frames are routed to, so the connected machine picks a display mode the sink shows natively. Source keeps no list of
+// sinks, when frames are routed to several sinks the EDID follows the one passed through last.
+type DisplaySourceEdidPassthrough interface {
+	Peripheral
+
+	// PassthroughDisplaySinkInfo adapts presented EDID to the sink according to the source policy. Nil sink info means
+	// the route to the sink is removed and the source presents its configured EDID again.
+	PassthroughDisplaySinkInfo(ctx context.Context, sinkInfo *DisplaySinkInfo) error
+}
+
+// DisplaySourceEdidProgrammer is implemented by display sources which accept EDID to present at runtime.
+type DisplaySourceEdidProgrammer interface {
+	Peripheral
+
+	// SetDisplayEdid replaces configured EDID of the source with the raw EDID blocks and presents it.
+	SetDisplayEdid(ctx context.Context, edid []byte) error
+}
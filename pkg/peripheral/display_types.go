@@ -3,6 +3,7 @@ package peripheral
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // DisplayPixelFormat defines the pixel format for display frames.
@@ -81,13 +82,13 @@ func (displayModeList DisplayModeList) Supports(testedMode DisplayMode) bool {
 	return false
 }
 
-// DisplayInfo contains information about a display device.
-type DisplayInfo struct {
-	Manufacturer   string
-	Model          string
-	SerialNumber   string
-	SupportedModes []DisplayMode
-	CurrentMode    DisplayMode
+func (displayModeList DisplayModeList) String() string {
+	displayModes := make([]string, 0, len(displayModeList))
+	for _, displayMode := range displayModeList {
+		displayModes = append(displayModes, displayMode.String())
+	}
+
+	return strings.Join(displayModes, ", ")
 }
 
 var ErrUnsupportedDisplayMode = errors.New("unsupported display mode")
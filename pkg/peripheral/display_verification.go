@@ -0,0 +1,68 @@
+package peripheral
+
+import (
+	"context"
+	"time"
+)
+
+// DisplayVerificationReport summarizes frames checked against frame counter and checksum markers drawn by a test
+// pattern source.
+type DisplayVerificationReport struct {
+	// StartedAt is the time when verification started or was last reset.
+	StartedAt time.Time `json:"startedAt"`
+
+	// FramesVerified counts frames inspected by the verifier.
+	FramesVerified uint64 `json:"framesVerified"`
+
+	// FirstFrameNumber and LastFrameNumber are the lowest and highest frame numbers decoded from markers.
+	FirstFrameNumber uint64 `json:"firstFrameNumber"`
+	LastFrameNumber  uint64 `json:"lastFrameNumber"`
+
+	// DroppedFrames counts frame numbers skipped between consecutive frames.
+	DroppedFrames uint64 `json:"droppedFrames"`
+
+	// DuplicateFrames counts frames repeating the previous frame number.
+	DuplicateFrames uint64 `json:"duplicateFrames"`
+
+	// OutOfOrderFrames counts frames with frame number lower than already seen.
+	OutOfOrderFrames uint64 `json:"outOfOrderFrames"`
+
+	// MissingMarkerFrames counts frames in which marker could not be decoded.
+	MissingMarkerFrames uint64 `json:"missingMarkerFrames"`
+
+	// ChecksumMismatchFrames counts frames whose pixels do not match the checksum stored in the marker.
+	ChecksumMismatchFrames uint64 `json:"checksumMismatchFrames"`
+
+	// DisplayModeMismatchFrames counts frames with size different from the expected pattern.
+	DisplayModeMismatchFrames uint64 `json:"displayModeMismatchFrames"`
+
+	// PixelMismatchFrames counts frames which differ from the expected pattern by more than the tolerance, and
+	// PixelMismatches counts all such pixels.
+	PixelMismatchFrames uint64 `json:"pixelMismatchFrames"`
+	PixelMismatches     uint64 `json:"pixelMismatches"`
+
+	// MaxPixelDifference is the largest difference of a single color channel against the expected pattern.
+	MaxPixelDifference uint8 `json:"maxPixelDifference"`
+}
+
+// Passed reports whether every verified frame was delivered in order and rendered pixel-perfect.
+func (report DisplayVerificationReport) Passed() bool {
+	return report.FramesVerified > 0 &&
+		report.DroppedFrames == 0 &&
+		report.DuplicateFrames == 0 &&
+		report.OutOfOrderFrames == 0 &&
+		report.MissingMarkerFrames == 0 &&
+		report.ChecksumMismatchFrames == 0 &&
+		report.DisplayModeMismatchFrames == 0 &&
+		report.PixelMismatchFrames == 0
+}
+
+// DisplayVerifier is implemented by display sinks which verify received frames.
+type DisplayVerifier interface {
+	Peripheral
+
+	GetDisplayVerificationReport(ctx context.Context) (*DisplayVerificationReport, error)
+
+	// ResetDisplayVerification clears the report and starts verification over.
+	ResetDisplayVerification(ctx context.Context) error
+}
@@ -0,0 +1,66 @@
+package peripheral
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MachinePowerStatus describes power state of a managed machine.
+type MachinePowerStatus string
+
+const (
+	// MachinePowerStatusUnknown represents a state which cannot be determined.
+	MachinePowerStatusUnknown MachinePowerStatus = ""
+	// MachinePowerStatusRunning indicates that the machine is powered on and executing.
+	MachinePowerStatusRunning MachinePowerStatus = "running"
+	// MachinePowerStatusPaused indicates that the machine is powered on but its execution is stopped.
+	MachinePowerStatusPaused MachinePowerStatus = "paused"
+	// MachinePowerStatusSuspended indicates that the machine is in sleep state.
+	MachinePowerStatusSuspended MachinePowerStatus = "suspended"
+	// MachinePowerStatusOff indicates that the machine is powered off.
+	MachinePowerStatusOff MachinePowerStatus = "off"
+)
+
+// MachinePowerAction is a power operation requested from a managed machine.
+type MachinePowerAction string
+
+const (
+	// MachinePowerActionPowerOn starts a powered off, paused or suspended machine.
+	MachinePowerActionPowerOn MachinePowerAction = "power-on"
+	// MachinePowerActionShutdown asks the operating system to shut down, like a short press of the power button.
+	MachinePowerActionShutdown MachinePowerAction = "shutdown"
+	// MachinePowerActionReset restarts the machine immediately, like the reset button.
+	MachinePowerActionReset MachinePowerAction = "reset"
+	// MachinePowerActionPause stops execution of the machine without powering it off.
+	MachinePowerActionPause MachinePowerAction = "pause"
+	// MachinePowerActionResume continues execution of a paused machine.
+	MachinePowerActionResume MachinePowerAction = "resume"
+)
+
+// MachinePowerState describes power state of a managed machine along with the last power event it reported.
+type MachinePowerState struct {
+	Status MachinePowerStatus `json:"status"`
+
+	// Detail is the driver specific state, e.g. QEMU run state.
+	Detail string `json:"detail"`
+
+	// LastEvent is the most recent power event reported by the machine, empty when none was observed.
+	LastEvent string `json:"lastEvent"`
+
+	// LastEventAt is the time of LastEvent.
+	LastEventAt time.Time `json:"lastEventAt"`
+}
+
+// MachinePowerController controls power of machines managed by a peripheral, e.g. virtual machines.
+type MachinePowerController interface {
+	Peripheral
+
+	GetMachinePowerState(ctx context.Context) (*MachinePowerState, error)
+
+	// HandleMachinePowerAction applies power action to the machine. It returns ErrMachinePowerActionUnsupported
+	// when the machine does not support the action.
+	HandleMachinePowerAction(ctx context.Context, action MachinePowerAction) error
+}
+
+var ErrMachinePowerActionUnsupported = errors.New("machine power action unsupported")
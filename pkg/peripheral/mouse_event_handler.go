@@ -0,0 +1,10 @@
+package peripheral
+
+// MouseEventHandler is implemented by mouse sinks which apply mouse events received from mouse sources, e.g. by
+// injecting them into a machine.
+type MouseEventHandler interface {
+	Peripheral
+
+	// HandleMouseDataEvent applies a mouse event to the sink.
+	HandleMouseDataEvent(event MouseEvent) error
+}
@@ -0,0 +1,152 @@
+package peripheral
+
+import "time"
+
+// MouseEventType defines the type of mouse data event.
+type MouseEventType int
+
+const (
+	// MouseEventUnknown represents an uninitialized or invalid mouse event type.
+	MouseEventUnknown MouseEventType = iota
+	// MouseEventMove represents a pointer movement event.
+	MouseEventMove
+	// MouseEventButton represents a mouse button event.
+	MouseEventButton
+	// MouseEventWheel represents a scroll wheel event.
+	MouseEventWheel
+)
+
+// MouseEvent represents a mouse data event.
+type MouseEvent interface {
+	Type() MouseEventType
+	Timestamp() time.Time
+}
+
+// MouseButton identifies a mouse button.
+type MouseButton int
+
+const (
+	// MouseButtonUnknown represents an uninitialized or invalid button.
+	MouseButtonUnknown MouseButton = iota
+	// MouseButtonLeft represents the primary button.
+	MouseButtonLeft
+	// MouseButtonMiddle represents the middle button or wheel click.
+	MouseButtonMiddle
+	// MouseButtonRight represents the secondary button.
+	MouseButtonRight
+	// MouseButtonBack represents the back side button.
+	MouseButtonBack
+	// MouseButtonForward represents the forward side button.
+	MouseButtonForward
+)
+
+// MouseButtonState captures the transition of a mouse button.
+type MouseButtonState int
+
+const (
+	// MouseButtonStateUnknown represents an uninitialized or invalid button state.
+	MouseButtonStateUnknown MouseButtonState = iota
+	// MouseButtonStatePress represents a button press action.
+	MouseButtonStatePress
+	// MouseButtonStateRelease represents a button release action.
+	MouseButtonStateRelease
+)
+
+// MouseMoveEvent describes pointer movement. Absolute events carry position normalized to the [0, 1] range of the
+// display, with origin in the top-left corner. Relative events carry movement delta in device units.
+type MouseMoveEvent struct {
+	timestamp time.Time
+	Absolute  bool
+	X         float64
+	Y         float64
+	DeltaX    int32
+	DeltaY    int32
+	SourceID  string
+}
+
+// NewMouseAbsoluteMoveEvent constructs an absolute MouseMoveEvent with an explicit timestamp.
+func NewMouseAbsoluteMoveEvent(x, y float64, sourceID string, timestamp time.Time) MouseMoveEvent {
+	return MouseMoveEvent{
+		timestamp: timestamp,
+		Absolute:  true,
+		X:         x,
+		Y:         y,
+		SourceID:  sourceID,
+	}
+}
+
+// NewMouseRelativeMoveEvent constructs a relative MouseMoveEvent with an explicit timestamp.
+func NewMouseRelativeMoveEvent(deltaX, deltaY int32, sourceID string, timestamp time.Time) MouseMoveEvent {
+	return MouseMoveEvent{
+		timestamp: timestamp,
+		DeltaX:    deltaX,
+		DeltaY:    deltaY,
+		SourceID:  sourceID,
+	}
+}
+
+// Type returns the event type.
+func (e MouseMoveEvent) Type() MouseEventType {
+	return MouseEventMove
+}
+
+// Timestamp returns the event timestamp.
+func (e MouseMoveEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// MouseButtonEvent describes a button transition.
+type MouseButtonEvent struct {
+	timestamp time.Time
+	Button    MouseButton
+	State     MouseButtonState
+	SourceID  string
+}
+
+// NewMouseButtonEvent constructs a MouseButtonEvent with an explicit timestamp.
+func NewMouseButtonEvent(button MouseButton, state MouseButtonState, sourceID string, timestamp time.Time) MouseButtonEvent {
+	return MouseButtonEvent{
+		timestamp: timestamp,
+		Button:    button,
+		State:     state,
+		SourceID:  sourceID,
+	}
+}
+
+// Type returns the event type.
+func (e MouseButtonEvent) Type() MouseEventType {
+	return MouseEventButton
+}
+
+// Timestamp returns the event timestamp.
+func (e MouseButtonEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// MouseWheelEvent describes scrolling in wheel detents. Positive DeltaY scrolls down, positive DeltaX scrolls right.
+type MouseWheelEvent struct {
+	timestamp time.Time
+	DeltaX    int32
+	DeltaY    int32
+	SourceID  string
+}
+
+// NewMouseWheelEvent constructs a MouseWheelEvent with an explicit timestamp.
+func NewMouseWheelEvent(deltaX, deltaY int32, sourceID string, timestamp time.Time) MouseWheelEvent {
+	return MouseWheelEvent{
+		timestamp: timestamp,
+		DeltaX:    deltaX,
+		DeltaY:    deltaY,
+		SourceID:  sourceID,
+	}
+}
+
+// Type returns the event type.
+func (e MouseWheelEvent) Type() MouseEventType {
+	return MouseEventWheel
+}
+
+// Timestamp returns the event timestamp.
+func (e MouseWheelEvent) Timestamp() time.Time {
+	return e.timestamp
+}